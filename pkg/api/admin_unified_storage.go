@@ -0,0 +1,256 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/storage/unified/federated"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// AdminUnifiedStorageRestore restores the collections of the current org to a previous point in time
+func (hs *HTTPServer) AdminUnifiedStorageRestore(c *contextmodel.ReqContext) response.Response {
+	form := dtos.UnifiedStorageRestoreForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	req := &resourcepb.RestoreRequest{
+		Namespace:       hs.namespacer(c.GetOrgID()),
+		Folder:          form.Folder,
+		ResourceVersion: form.ResourceVersion,
+		Timestamp:       form.Timestamp,
+		DryRun:          form.DryRun,
+	}
+	for _, r := range form.Resources {
+		req.Resources = append(req.Resources, &resourcepb.ResourceKey{Group: r.Group, Resource: r.Resource})
+	}
+
+	rsp, err := hs.unifiedStorage.Restore(c.Req.Context(), req)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to restore", err)
+	}
+	if rsp.Error != nil {
+		return unifiedStorageError(rsp.Error)
+	}
+
+	result := dtos.UnifiedStorageRestoreResult{
+		ResourceVersion: rsp.ResourceVersion,
+		Changes:         make([]dtos.UnifiedStorageRestoreChange, 0, len(rsp.Changes)),
+	}
+	for _, change := range rsp.Changes {
+		result.Changes = append(result.Changes, dtos.UnifiedStorageRestoreChange{
+			Group:                   change.Key.Group,
+			Resource:                change.Key.Resource,
+			Name:                    change.Key.Name,
+			Action:                  change.Action.String(),
+			Folder:                  change.Folder,
+			CurrentResourceVersion:  change.CurrentResourceVersion,
+			RestoredResourceVersion: change.RestoredResourceVersion,
+		})
+	}
+	if rsp.Bulk != nil {
+		if rsp.Bulk.Error != nil {
+			return unifiedStorageError(rsp.Bulk.Error)
+		}
+		result.Processed = rsp.Bulk.Processed
+		for _, r := range rsp.Bulk.Rejected {
+			result.Rejected = append(result.Rejected, fmt.Sprintf("%s/%s: %s", r.Key.GetResource(), r.Key.GetName(), r.Error))
+		}
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func unifiedStorageError(err *resourcepb.ErrorResult) response.Response {
+	status := int(err.Code)
+	if status < http.StatusBadRequest {
+		status = http.StatusInternalServerError
+	}
+	return response.Error(status, err.Message, nil)
+}
+
+// AdminUnifiedStorageListTrash lists the deleted resources of the current org.
+// Every collection is passed as a resource=group/resource query parameter.
+func (hs *HTTPServer) AdminUnifiedStorageListTrash(c *contextmodel.ReqContext) response.Response {
+	req := &resourcepb.ListTrashRequest{
+		Namespace: hs.namespacer(c.GetOrgID()),
+	}
+	for _, v := range c.QueryStrings("resource") {
+		group, resource, ok := strings.Cut(v, "/")
+		if !ok {
+			return response.Error(http.StatusBadRequest, fmt.Sprintf("invalid resource %q, expected group/resource", v), nil)
+		}
+		req.Resources = append(req.Resources, &resourcepb.ResourceKey{Group: group, Resource: resource})
+	}
+
+	rsp, err := hs.unifiedStorage.ListTrash(c.Req.Context(), req)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to list the trash", err)
+	}
+	if rsp.Error != nil {
+		return unifiedStorageError(rsp.Error)
+	}
+
+	items := make([]dtos.UnifiedStorageTrashItem, 0, len(rsp.Items))
+	for _, item := range rsp.Items {
+		items = append(items, dtos.UnifiedStorageTrashItem{
+			Group:           item.Key.Group,
+			Resource:        item.Key.Resource,
+			Name:            item.Key.Name,
+			Folder:          item.Folder,
+			ResourceVersion: item.ResourceVersion,
+			DeletedBy:       item.DeletedBy,
+			DeletedAt:       item.DeletedAt,
+		})
+	}
+	return response.JSON(http.StatusOK, items)
+}
+
+// AdminUnifiedStorageRestoreFromTrash recreates a deleted resource of the current org, and any missing parent folders
+func (hs *HTTPServer) AdminUnifiedStorageRestoreFromTrash(c *contextmodel.ReqContext) response.Response {
+	form := dtos.UnifiedStorageTrashRestoreForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	rsp, err := hs.unifiedStorage.RestoreFromTrash(c.Req.Context(), &resourcepb.RestoreFromTrashRequest{
+		Key: &resourcepb.ResourceKey{
+			Namespace: hs.namespacer(c.GetOrgID()),
+			Group:     form.Group,
+			Resource:  form.Resource,
+			Name:      form.Name,
+		},
+		ResourceVersion: form.ResourceVersion,
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to restore from the trash", err)
+	}
+	if rsp.Error != nil {
+		return unifiedStorageError(rsp.Error)
+	}
+
+	result := dtos.UnifiedStorageTrashRestoreResult{
+		Restored: make([]dtos.UnifiedStorageTrashItem, 0, len(rsp.Restored)),
+	}
+	for _, r := range rsp.Restored {
+		result.Restored = append(result.Restored, dtos.UnifiedStorageTrashItem{
+			Group:           r.Key.Group,
+			Resource:        r.Key.Resource,
+			Name:            r.Key.Name,
+			ResourceVersion: r.ResourceVersion,
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// AdminUnifiedStoragePurgeTrash permanently removes the deleted resources of the current org
+func (hs *HTTPServer) AdminUnifiedStoragePurgeTrash(c *contextmodel.ReqContext) response.Response {
+	form := dtos.UnifiedStorageTrashPurgeForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	req := &resourcepb.PurgeTrashRequest{
+		Namespace:     hs.namespacer(c.GetOrgID()),
+		DeletedBefore: form.DeletedBefore,
+	}
+	for _, r := range form.Resources {
+		req.Resources = append(req.Resources, &resourcepb.ResourceKey{Group: r.Group, Resource: r.Resource})
+	}
+
+	rsp, err := hs.unifiedStorage.PurgeTrash(c.Req.Context(), req)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to purge the trash", err)
+	}
+	if rsp.Error != nil {
+		return unifiedStorageError(rsp.Error)
+	}
+	return response.JSON(http.StatusOK, dtos.UnifiedStorageTrashPurgeResult{Purged: rsp.Purged})
+}
+
+// AdminUnifiedStorageListMigrations lists the progress of the online migrations of the storage backend
+func (hs *HTTPServer) AdminUnifiedStorageListMigrations(c *contextmodel.ReqContext) response.Response {
+	rsp, err := hs.unifiedStorage.ListOnlineMigrations(c.Req.Context(), &resourcepb.ListOnlineMigrationsRequest{})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get the online migrations", err)
+	}
+	if rsp.Error != nil {
+		return unifiedStorageError(rsp.Error)
+	}
+
+	migrations := make([]dtos.UnifiedStorageOnlineMigration, 0, len(rsp.Migrations))
+	for _, m := range rsp.Migrations {
+		migrations = append(migrations, dtos.UnifiedStorageOnlineMigration{
+			ID:        m.Id,
+			Status:    m.Status,
+			Shard:     m.Shard,
+			Processed: m.Processed,
+			Total:     m.Total,
+			Error:     m.Error,
+			Started:   m.Started,
+			Updated:   m.Updated,
+		})
+	}
+	return response.JSON(http.StatusOK, migrations)
+}
+
+// AdminUnifiedStorageMoveNamespace starts moving a namespace to another storage shard.
+// The move runs in the background, its progress is listed by AdminUnifiedStorageListNamespaceMoves.
+func (hs *HTTPServer) AdminUnifiedStorageMoveNamespace(c *contextmodel.ReqContext) response.Response {
+	form := dtos.UnifiedStorageNamespaceMoveForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	mover, ok := federated.AsNamespaceMover(hs.unifiedStorage)
+	if !ok {
+		return response.Error(http.StatusBadRequest, "Unified storage is not sharded", nil)
+	}
+
+	move, err := mover.StartNamespaceMove(c.Req.Context(), form.Namespace, form.Shard)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Failed to move the namespace", err)
+	}
+	return response.JSON(http.StatusAccepted, toNamespaceMoveDTO(move))
+}
+
+// AdminUnifiedStorageListNamespaceMoves lists the last move of each namespace moved between storage shards
+func (hs *HTTPServer) AdminUnifiedStorageListNamespaceMoves(c *contextmodel.ReqContext) response.Response {
+	mover, ok := federated.AsNamespaceMover(hs.unifiedStorage)
+	if !ok {
+		return response.JSON(http.StatusOK, []dtos.UnifiedStorageNamespaceMove{})
+	}
+
+	moves, err := mover.ListNamespaceMoves(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to list the namespace moves", err)
+	}
+	result := make([]dtos.UnifiedStorageNamespaceMove, 0, len(moves))
+	for _, move := range moves {
+		result = append(result, toNamespaceMoveDTO(move))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func toNamespaceMoveDTO(move *federated.NamespaceMove) dtos.UnifiedStorageNamespaceMove {
+	dto := dtos.UnifiedStorageNamespaceMove{
+		Namespace:   move.Namespace,
+		From:        move.From,
+		To:          move.To,
+		State:       string(move.State),
+		Error:       move.Error,
+		Collections: make([]dtos.UnifiedStorageCollection, 0, len(move.Collections)),
+		Copied:      move.Copied,
+		Recopied:    move.Recopied,
+		Started:     move.Started.UnixMilli(),
+		Updated:     move.Updated.UnixMilli(),
+	}
+	for _, key := range move.Collections {
+		dto.Collections = append(dto.Collections, dtos.UnifiedStorageCollection{Group: key.Group, Resource: key.Resource})
+	}
+	return dto
+}
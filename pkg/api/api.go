@@ -551,6 +551,27 @@ func (hs *HTTPServer) registerRoutes() {
 
 			// Some channels may have info
 			liveRoute.Get("/info/*", routing.Wrap(hs.Live.HandleInfoHTTP))
+
+			// Users viewing and editing a dashboard
+			liveRoute.Get("/dashboards/:uid/presence", routing.Wrap(hs.Live.HandleDashboardPresenceHTTP))
+
+			if hs.Cfg.LivePipelineEnabled {
+				// POST Live data to be processed according to channel rules.
+				liveRoute.Post("/pipeline/push/*", reqOrgAdmin, hs.LivePushGateway.HandlePipelinePush)
+				liveRoute.Post("/pipeline-convert-test", reqOrgAdmin, routing.Wrap(hs.Live.HandlePipelineConvertTestHTTP))
+				liveRoute.Get("/pipeline-entities", reqOrgAdmin, routing.Wrap(hs.Live.HandlePipelineEntitiesListHTTP))
+				liveRoute.Get("/channel-rules", reqOrgAdmin, routing.Wrap(hs.Live.HandleChannelRulesListHTTP))
+				liveRoute.Post("/channel-rules", reqOrgAdmin, routing.Wrap(hs.Live.HandleChannelRulesPostHTTP))
+				liveRoute.Put("/channel-rules", reqOrgAdmin, routing.Wrap(hs.Live.HandleChannelRulesPutHTTP))
+				liveRoute.Delete("/channel-rules", reqOrgAdmin, routing.Wrap(hs.Live.HandleChannelRulesDeleteHTTP))
+				liveRoute.Post("/channel-rules/test", reqOrgAdmin, routing.Wrap(hs.Live.HandleChannelRulesTestHTTP))
+				liveRoute.Get("/channel-rules/versions", reqOrgAdmin, routing.Wrap(hs.Live.HandleChannelRuleVersionsListHTTP))
+				liveRoute.Post("/channel-rules/rollback", reqOrgAdmin, routing.Wrap(hs.Live.HandleChannelRulesRollbackHTTP))
+				liveRoute.Get("/write-configs", reqOrgAdmin, routing.Wrap(hs.Live.HandleWriteConfigsListHTTP))
+				liveRoute.Post("/write-configs", reqOrgAdmin, routing.Wrap(hs.Live.HandleWriteConfigsPostHTTP))
+				liveRoute.Put("/write-configs", reqOrgAdmin, routing.Wrap(hs.Live.HandleWriteConfigsPutHTTP))
+				liveRoute.Delete("/write-configs", reqOrgAdmin, routing.Wrap(hs.Live.HandleWriteConfigsDeleteHTTP))
+			}
 		}, requestmeta.SetSLOGroup(requestmeta.SLOGroupNone))
 
 		// short urls
@@ -573,6 +594,14 @@ func (hs *HTTPServer) registerRoutes() {
 		adminRoute.Post("/provisioning/plugins/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersPlugins)), routing.Wrap(hs.AdminProvisioningReloadPlugins))
 		adminRoute.Post("/provisioning/datasources/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersDatasources)), routing.Wrap(hs.AdminProvisioningReloadDatasources))
 		adminRoute.Post("/provisioning/alerting/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersAlertRules)), routing.Wrap(hs.AdminProvisioningReloadAlerting))
+
+		adminRoute.Post("/unified-storage/restore", reqGrafanaAdmin, routing.Wrap(hs.AdminUnifiedStorageRestore))
+		adminRoute.Get("/unified-storage/trash", reqGrafanaAdmin, routing.Wrap(hs.AdminUnifiedStorageListTrash))
+		adminRoute.Post("/unified-storage/trash/restore", reqGrafanaAdmin, routing.Wrap(hs.AdminUnifiedStorageRestoreFromTrash))
+		adminRoute.Post("/unified-storage/trash/purge", reqGrafanaAdmin, routing.Wrap(hs.AdminUnifiedStoragePurgeTrash))
+		adminRoute.Get("/unified-storage/migrations", reqGrafanaAdmin, routing.Wrap(hs.AdminUnifiedStorageListMigrations))
+		adminRoute.Get("/unified-storage/namespaces/moves", reqGrafanaAdmin, routing.Wrap(hs.AdminUnifiedStorageListNamespaceMoves))
+		adminRoute.Post("/unified-storage/namespaces/moves", reqGrafanaAdmin, routing.Wrap(hs.AdminUnifiedStorageMoveNamespace))
 	}, reqSignedIn)
 
 	// Administering users
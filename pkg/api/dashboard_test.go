@@ -140,7 +140,7 @@ func newTestLive(t *testing.T, store db.DB) *live.GrafanaLive {
 		features, acimpl.ProvideAccessControl(features),
 		&dashboards.FakeDashboardService{},
 		annotationstest.NewFakeAnnotationsRepo(),
-		nil, nil)
+		nil, nil, nil)
 	require.NoError(t, err)
 	return gLive
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/dependencies"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
@@ -146,10 +147,11 @@ func (hs *HTTPServer) GetDataSourceById(c *contextmodel.ReqContext) response.Res
 // Deprecated: true
 //
 // Responses:
-// 200: okResponse
+// 200: deleteDataSourceResponse
 // 401: unauthorisedError
 // 404: notFoundError
 // 403: forbiddenError
+// 409: resourceInUseError
 // 500: internalServerError
 func (hs *HTTPServer) DeleteDataSourceById(c *contextmodel.ReqContext) response.Response {
 	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
@@ -173,6 +175,10 @@ func (hs *HTTPServer) DeleteDataSourceById(c *contextmodel.ReqContext) response.
 		return response.Error(http.StatusForbidden, "Cannot delete read-only data source", nil)
 	}
 
+	dependents, rsp := hs.dataSourceInUse(c, ds.UID)
+	if rsp != nil {
+		return rsp
+	}
 	cmd := &datasources.DeleteDataSourceCommand{ID: id, OrgID: c.GetOrgID(), Name: ds.Name}
 
 	err = hs.DataSourcesService.DeleteDataSource(c.Req.Context(), cmd)
@@ -182,7 +188,11 @@ func (hs *HTTPServer) DeleteDataSourceById(c *contextmodel.ReqContext) response.
 
 	hs.Live.HandleDatasourceDelete(c.GetOrgID(), ds.UID)
 
-	return response.Success("Data source deleted")
+	return response.JSON(http.StatusOK, DeleteDataSourceResult{
+		Message:    "Data source deleted",
+		ID:         ds.ID,
+		Dependents: dependents,
+	})
 }
 
 // swagger:route GET /datasources/uid/{uid} datasources getDataSourceByUID
@@ -225,10 +235,11 @@ func (hs *HTTPServer) GetDataSourceByUID(c *contextmodel.ReqContext) response.Re
 // you need to have a permission with action: `datasources:delete` and scopes: `datasources:*`, `datasources:uid:*` and `datasources:uid:kLtEtcRGk` (single data source).
 //
 // Responses:
-// 200: okResponse
+// 200: deleteDataSourceResponse
 // 401: unauthorisedError
 // 403: forbiddenError
 // 404: notFoundError
+// 409: resourceInUseError
 // 500: internalServerError
 func (hs *HTTPServer) DeleteDataSourceByUID(c *contextmodel.ReqContext) response.Response {
 	uid := web.Params(c.Req)[":uid"]
@@ -249,6 +260,10 @@ func (hs *HTTPServer) DeleteDataSourceByUID(c *contextmodel.ReqContext) response
 		return response.Error(http.StatusForbidden, "Cannot delete read-only data source", nil)
 	}
 
+	dependents, rsp := hs.dataSourceInUse(c, ds.UID)
+	if rsp != nil {
+		return rsp
+	}
 	cmd := &datasources.DeleteDataSourceCommand{UID: uid, OrgID: c.GetOrgID(), Name: ds.Name}
 
 	err = hs.DataSourcesService.DeleteDataSource(c.Req.Context(), cmd)
@@ -258,9 +273,10 @@ func (hs *HTTPServer) DeleteDataSourceByUID(c *contextmodel.ReqContext) response
 
 	hs.Live.HandleDatasourceDelete(c.GetOrgID(), ds.UID)
 
-	return response.JSON(http.StatusOK, util.DynMap{
-		"message": "Data source deleted",
-		"id":      ds.ID,
+	return response.JSON(http.StatusOK, DeleteDataSourceResult{
+		Message:    "Data source deleted",
+		ID:         ds.ID,
+		Dependents: dependents,
 	})
 }
 
@@ -276,6 +292,7 @@ func (hs *HTTPServer) DeleteDataSourceByUID(c *contextmodel.ReqContext) response
 // 401: unauthorisedError
 // 403: forbiddenError
 // 404: notFoundError
+// 409: resourceInUseError
 // 500: internalServerError
 func (hs *HTTPServer) DeleteDataSourceByName(c *contextmodel.ReqContext) response.Response {
 	name := web.Params(c.Req)[":name"]
@@ -297,6 +314,10 @@ func (hs *HTTPServer) DeleteDataSourceByName(c *contextmodel.ReqContext) respons
 		return response.Error(http.StatusForbidden, "Cannot delete read-only data source", nil)
 	}
 
+	dependents, rsp := hs.dataSourceInUse(c, dataSource.UID)
+	if rsp != nil {
+		return rsp
+	}
 	cmd := &datasources.DeleteDataSourceCommand{Name: name, OrgID: c.GetOrgID()}
 	err = hs.DataSourcesService.DeleteDataSource(c.Req.Context(), cmd)
 	if err != nil {
@@ -305,12 +326,42 @@ func (hs *HTTPServer) DeleteDataSourceByName(c *contextmodel.ReqContext) respons
 
 	hs.Live.HandleDatasourceDelete(c.GetOrgID(), dataSource.UID)
 
-	return response.JSON(http.StatusOK, util.DynMap{
-		"message": "Data source deleted",
-		"id":      dataSource.ID,
+	return response.JSON(http.StatusOK, DeleteDataSourceResult{
+		Message:    "Data source deleted",
+		ID:         dataSource.ID,
+		Dependents: dependents,
 	})
 }
 
+// dataSourceInUse returns the resources that still use the data source, they are returned with the deleted data source.
+// The deletion is only refused when the request sets checkDependents=true.
+// The dependents are found before the deletion, since the correlations of the data source are deleted with it.
+func (hs *HTTPServer) dataSourceInUse(c *contextmodel.ReqContext, uid string) ([]dependencies.Node, response.Response) {
+	dependents := []dependencies.Node{}
+	if hs.DependencyService == nil {
+		return dependents, nil
+	}
+	found, hidden, err := dependencies.FindDependents(c.Req.Context(), hs.DependencyService, c.SignedInUser, c.GetOrgID(), dependencies.KindDataSource, uid)
+	if err != nil {
+		datasourcesLogger.Warn("Failed to get the dependents of the data source", "uid", uid, "error", err)
+		return dependents, nil
+	}
+	dependents = append(dependents, found...)
+	if len(dependents) == 0 && hidden == 0 {
+		return dependents, nil
+	}
+
+	if c.QueryBool("checkDependents") {
+		return nil, response.JSON(http.StatusConflict, dependencies.InUseResult{
+			Message:    "Data source is in use",
+			Dependents: dependents,
+			Hidden:     hidden,
+		})
+	}
+	datasourcesLogger.Warn("Deleting a data source that is in use", "uid", uid, "dependents", len(dependents)+hidden)
+	return dependents, nil
+}
+
 func validateURL(cmdType string, url string) response.Response {
 	if _, err := datasource.ValidateURL(cmdType, url); err != nil {
 		datasourcesLogger.Error("Failed to validate URL", "url", url)
@@ -872,6 +923,12 @@ type DeleteDataSourceByIDParams struct {
 	// in:path
 	// required:true
 	DatasourceID string `json:"id"`
+	// If `true` the data source is not deleted when other resources still use it, the request fails with these resources.
+	// Otherwise the data source is deleted and the resources that used it are returned.
+	// in:query
+	// required:false
+	// default:false
+	CheckDependents bool `json:"checkDependents"`
 }
 
 // swagger:parameters getDataSourceByID
@@ -900,6 +957,12 @@ type DeleteDataSourceByUIDParams struct {
 	// in:path
 	// required:true
 	DatasourceUID string `json:"uid"`
+	// If `true` the data source is not deleted when other resources still use it, the request fails with these resources.
+	// Otherwise the data source is deleted and the resources that used it are returned.
+	// in:query
+	// required:false
+	// default:false
+	CheckDependents bool `json:"checkDependents"`
 }
 
 // swagger:parameters getDataSourceByUID
@@ -921,6 +984,12 @@ type DeleteDataSourceByNameParams struct {
 	// in:path
 	// required:true
 	DatasourceName string `json:"name"`
+	// If `true` the data source is not deleted when other resources still use it, the request fails with these resources.
+	// Otherwise the data source is deleted and the resources that used it are returned.
+	// in:query
+	// required:false
+	// default:false
+	CheckDependents bool `json:"checkDependents"`
 }
 
 // swagger:parameters getDataSourceIdByName
@@ -1013,15 +1082,27 @@ type GetDataSourceIDresponse struct {
 type DeleteDataSourceByNameResponse struct {
 	// The response message
 	// in: body
-	Body struct {
-		// ID Identifier of the deleted data source.
-		// required: true
-		// example: 65
-		ID int64 `json:"id"`
+	Body DeleteDataSourceResult `json:"body"`
+}
 
-		// Message Message of the deleted dashboard.
-		// required: true
-		// example: Dashboard My Dashboard deleted
-		Message string `json:"message"`
-	} `json:"body"`
+// swagger:response deleteDataSourceResponse
+type DeleteDataSourceResponse struct {
+	// in: body
+	Body DeleteDataSourceResult `json:"body"`
+}
+
+type DeleteDataSourceResult struct {
+	// ID Identifier of the deleted data source.
+	// required: true
+	// example: 65
+	ID int64 `json:"id"`
+
+	// Message Message of the deleted data source.
+	// required: true
+	// example: Data source deleted
+	Message string `json:"message"`
+
+	// Dependents The resources that used the deleted data source.
+	// required: true
+	Dependents []dependencies.Node `json:"dependents"`
 }
@@ -0,0 +1,117 @@
+package dtos
+
+// UnifiedStorageCollection is a group+resource stored in unified storage
+type UnifiedStorageCollection struct {
+	Group    string `json:"group"`
+	Resource string `json:"resource"`
+}
+
+type UnifiedStorageRestoreForm struct {
+	Resources []UnifiedStorageCollection `json:"resources" binding:"Required"`
+
+	// Only restore the folder and its descendants
+	Folder string `json:"folder"`
+
+	// The point in time to restore, the timestamp (unix milliseconds) is used when the resource version is not set
+	ResourceVersion int64 `json:"resourceVersion"`
+	Timestamp       int64 `json:"timestamp"`
+
+	DryRun bool `json:"dryRun"`
+}
+
+type UnifiedStorageRestoreChange struct {
+	Group                   string `json:"group"`
+	Resource                string `json:"resource"`
+	Name                    string `json:"name"`
+	Action                  string `json:"action"`
+	Folder                  string `json:"folder,omitempty"`
+	CurrentResourceVersion  int64  `json:"currentResourceVersion,omitempty"`
+	RestoredResourceVersion int64  `json:"restoredResourceVersion,omitempty"`
+}
+
+type UnifiedStorageRestoreResult struct {
+	ResourceVersion int64                         `json:"resourceVersion"`
+	Changes         []UnifiedStorageRestoreChange `json:"changes"`
+
+	// Set once the changes are applied
+	Processed int64    `json:"processed,omitempty"`
+	Rejected  []string `json:"rejected,omitempty"`
+}
+
+type UnifiedStorageTrashItem struct {
+	Group           string `json:"group"`
+	Resource        string `json:"resource"`
+	Name            string `json:"name"`
+	Folder          string `json:"folder,omitempty"`
+	ResourceVersion int64  `json:"resourceVersion"`
+	DeletedBy       string `json:"deletedBy,omitempty"`
+	// Unix milliseconds
+	DeletedAt int64 `json:"deletedAt,omitempty"`
+}
+
+type UnifiedStorageTrashRestoreForm struct {
+	Group    string `json:"group" binding:"Required"`
+	Resource string `json:"resource" binding:"Required"`
+	Name     string `json:"name" binding:"Required"`
+
+	// The resource version of the deletion, the latest deletion is restored when not set
+	ResourceVersion int64 `json:"resourceVersion"`
+}
+
+type UnifiedStorageTrashRestoreResult struct {
+	// The restored resources, parent folders first
+	Restored []UnifiedStorageTrashItem `json:"restored"`
+}
+
+type UnifiedStorageTrashPurgeForm struct {
+	Resources []UnifiedStorageCollection `json:"resources" binding:"Required"`
+
+	// Only purge resources deleted before this time (unix milliseconds), defaults to now
+	DeletedBefore int64 `json:"deletedBefore"`
+}
+
+type UnifiedStorageTrashPurgeResult struct {
+	// The number of history entries removed
+	Purged int64 `json:"purged"`
+}
+
+// UnifiedStorageOnlineMigration is the progress of an online migration of the storage backend
+type UnifiedStorageOnlineMigration struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+
+	// The storage shard, only set with a federated storage
+	Shard string `json:"shard,omitempty"`
+
+	Processed int64  `json:"processed"`
+	Total     int64  `json:"total"`
+	Error     string `json:"error,omitempty"`
+
+	// unix milliseconds
+	Started int64 `json:"started,omitempty"`
+	Updated int64 `json:"updated,omitempty"`
+}
+
+type UnifiedStorageNamespaceMoveForm struct {
+	Namespace string `json:"namespace" binding:"Required"`
+	// The name of the target storage shard
+	Shard string `json:"shard" binding:"Required"`
+}
+
+// UnifiedStorageNamespaceMove is the progress of a namespace move between storage shards
+type UnifiedStorageNamespaceMove struct {
+	Namespace   string                     `json:"namespace"`
+	From        string                     `json:"from"`
+	To          string                     `json:"to"`
+	State       string                     `json:"state"`
+	Error       string                     `json:"error,omitempty"`
+	Collections []UnifiedStorageCollection `json:"collections"`
+
+	// The objects copied while the namespace was still written, then while the writes were blocked
+	Copied   int64 `json:"copied"`
+	Recopied int64 `json:"recopied"`
+
+	// unix milliseconds
+	Started int64 `json:"started"`
+	Updated int64 `json:"updated"`
+}
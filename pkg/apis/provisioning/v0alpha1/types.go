@@ -57,6 +57,44 @@ type GitHubRepositoryConfig struct {
 	//
 	// When specifying something like `grafana-`, we will not look for `grafana-*`; we will only look for files under the directory `/grafana-/`. That means `/grafana-example.json` would not be found.
 	Path string `json:"path,omitempty"`
+
+	// Signing configures how commits written by Grafana are signed, and whether signatures are verified before sync.
+	Signing *CommitSigningConfig `json:"signing,omitempty"`
+}
+
+// SigningFormat defines the signature format used for commits
+// +enum
+type SigningFormat string
+
+// SigningFormat values
+const (
+	// GPGSigningFormat signs commits with an OpenPGP key
+	GPGSigningFormat SigningFormat = "gpg"
+	// SSHSigningFormat signs commits with an SSH key
+	SSHSigningFormat SigningFormat = "ssh"
+)
+
+type CommitSigningConfig struct {
+	// The format of the signing key. Defaults to gpg.
+	Format SigningFormat `json:"format,omitempty"`
+
+	// Private key used to sign commits (armored OpenPGP key or OpenSSH private key).
+	// If set, it will be encrypted into encryptedKey, then set to an empty string again.
+	// The key must not be protected by a passphrase.
+	Key string `json:"key,omitempty"`
+	// Private key used to sign commits, but encrypted. This is not possible to read back to a user decrypted.
+	// +listType=atomic
+	EncryptedKey []byte `json:"encryptedKey,omitempty"`
+
+	// When true, sync refuses to apply changes unless every commit since the last synced commit verifies against one of the trusted keys.
+	// Commits Grafana writes through the GitHub API, such as saving a dashboard from the UI, are not signed with the signing key:
+	// they only verify when GitHub signs them and GitHub's web-flow key is trusted, otherwise the next sync rejects them.
+	RequireVerified bool `json:"requireVerified,omitempty"`
+
+	// Public keys trusted when verifying commit signatures.
+	// Each entry is either an armored OpenPGP public key (block) or an SSH public key in authorized_keys format.
+	// +listType=atomic
+	TrustedKeys []string `json:"trustedKeys,omitempty"`
 }
 
 // RepositoryType defines the types of Repository
@@ -27,6 +27,32 @@ func (in *Author) DeepCopy() *Author {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommitSigningConfig) DeepCopyInto(out *CommitSigningConfig) {
+	*out = *in
+	if in.EncryptedKey != nil {
+		in, out := &in.EncryptedKey, &out.EncryptedKey
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.TrustedKeys != nil {
+		in, out := &in.TrustedKeys, &out.TrustedKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommitSigningConfig.
+func (in *CommitSigningConfig) DeepCopy() *CommitSigningConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CommitSigningConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ErrorDetails) DeepCopyInto(out *ErrorDetails) {
 	*out = *in
@@ -114,6 +140,11 @@ func (in *GitHubRepositoryConfig) DeepCopyInto(out *GitHubRepositoryConfig) {
 		*out = make([]byte, len(*in))
 		copy(*out, *in)
 	}
+	if in.Signing != nil {
+		in, out := &in.Signing, &out.Signing
+		*out = new(CommitSigningConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
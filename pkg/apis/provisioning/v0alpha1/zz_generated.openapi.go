@@ -15,6 +15,7 @@ import (
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
 		"github.com/grafana/grafana/pkg/apis/provisioning/v0alpha1.Author":                 schema_pkg_apis_provisioning_v0alpha1_Author(ref),
+		"github.com/grafana/grafana/pkg/apis/provisioning/v0alpha1.CommitSigningConfig":    schema_pkg_apis_provisioning_v0alpha1_CommitSigningConfig(ref),
 		"github.com/grafana/grafana/pkg/apis/provisioning/v0alpha1.ErrorDetails":           schema_pkg_apis_provisioning_v0alpha1_ErrorDetails(ref),
 		"github.com/grafana/grafana/pkg/apis/provisioning/v0alpha1.ExportJobOptions":       schema_pkg_apis_provisioning_v0alpha1_ExportJobOptions(ref),
 		"github.com/grafana/grafana/pkg/apis/provisioning/v0alpha1.FileItem":               schema_pkg_apis_provisioning_v0alpha1_FileItem(ref),
@@ -89,6 +90,72 @@ func schema_pkg_apis_provisioning_v0alpha1_Author(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_provisioning_v0alpha1_CommitSigningConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"format": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The format of the signing key. Defaults to gpg.\n\nPossible enum values:\n - `\"gpg\"` GPGSigningFormat signs commits with an OpenPGP key\n - `\"ssh\"` SSHSigningFormat signs commits with an SSH key",
+							Type:        []string{"string"},
+							Format:      "",
+							Enum:        []interface{}{"gpg", "ssh"},
+						},
+					},
+					"key": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Private key used to sign commits (armored OpenPGP key or OpenSSH private key). If set, it will be encrypted into encryptedKey, then set to an empty string again. The key must not be protected by a passphrase.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"encryptedKey": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Private key used to sign commits, but encrypted. This is not possible to read back to a user decrypted.",
+							Type:        []string{"string"},
+							Format:      "byte",
+						},
+					},
+					"requireVerified": {
+						SchemaProps: spec.SchemaProps{
+							Description: "When true, sync refuses to apply changes unless every commit since the last synced commit verifies against one of the trusted keys. Commits Grafana writes through the GitHub API, such as saving a dashboard from the UI, are not signed with the signing key: they only verify when GitHub signs them and GitHub's web-flow key is trusted, otherwise the next sync rejects them.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"trustedKeys": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Public keys trusted when verifying commit signatures. Each entry is either an armored OpenPGP public key (block) or an SSH public key in authorized_keys format.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_provisioning_v0alpha1_ErrorDetails(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -306,10 +373,18 @@ func schema_pkg_apis_provisioning_v0alpha1_GitHubRepositoryConfig(ref common.Ref
 							Format:      "",
 						},
 					},
+					"signing": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Signing configures how commits written by Grafana are signed, and whether signatures are verified before sync.",
+							Ref:         ref("github.com/grafana/grafana/pkg/apis/provisioning/v0alpha1.CommitSigningConfig"),
+						},
+					},
 				},
 				Required: []string{"branch"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/grafana/grafana/pkg/apis/provisioning/v0alpha1.CommitSigningConfig"},
 	}
 }
 
@@ -0,0 +1,72 @@
+package v0alpha1
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	GROUP      = "search.grafana.app"
+	VERSION    = "v0alpha1"
+	APIVERSION = GROUP + "/" + VERSION
+)
+
+var SavedSearchResourceInfo = utils.NewResourceInfo(GROUP, VERSION,
+	"savedsearches", "savedsearch", "SavedSearch",
+	func() runtime.Object { return &SavedSearch{} },
+	func() runtime.Object { return &SavedSearchList{} },
+	utils.TableColumns{
+		Definition: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name"},
+			{Name: "Title", Type: "string", Format: "string", Description: "The saved search title"},
+			{Name: "Matches", Type: "number", Description: "The resources matching the query on the last evaluation"},
+			{Name: "Created At", Type: "date"},
+		},
+		Reader: func(obj any) ([]interface{}, error) {
+			m, ok := obj.(*SavedSearch)
+			if !ok {
+				return nil, fmt.Errorf("expected saved search")
+			}
+			return []interface{}{
+				m.Name,
+				m.Spec.Title,
+				len(m.Status.Matches),
+				m.CreationTimestamp.UTC().Format(time.RFC3339),
+			}, nil
+		},
+	}, // default table converter
+)
+
+var (
+	// SchemeGroupVersion is group version used to register these objects
+	SchemeGroupVersion = schema.GroupVersion{Group: GROUP, Version: VERSION}
+
+	// SchemaBuilder is used by standard codegen
+	SchemeBuilder      runtime.SchemeBuilder
+	localSchemeBuilder = &SchemeBuilder
+	AddToScheme        = localSchemeBuilder.AddToScheme
+)
+
+func init() {
+	localSchemeBuilder.Register(addKnownTypes)
+}
+
+// Adds the list of known types to the given scheme.
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&SavedSearch{},
+		&SavedSearchList{},
+	)
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
+
+// Resource takes an unqualified resource and returns a Group qualified GroupResource
+func Resource(resource string) schema.GroupResource {
+	return SchemeGroupVersion.WithResource(resource).GroupResource()
+}
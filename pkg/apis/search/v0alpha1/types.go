@@ -0,0 +1,52 @@
+package v0alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	common "github.com/grafana/grafana/pkg/apimachinery/apis/common/v0alpha1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SavedSearch struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SavedSearchSpec `json:"spec,omitempty"`
+
+	// Written by the storage server when the saved search has an alert
+	Status SavedSearchStatus `json:"status,omitempty"`
+}
+
+type SavedSearchSpec struct {
+	Title string `json:"title"`
+
+	// The unified search request in the protobuf JSON format.
+	// The query always runs in the namespace of the saved search, with the permissions of the user who last changed it.
+	Query common.Unstructured `json:"query"`
+
+	// Send a notification when the set of matching resources changes
+	Alert *SavedSearchAlert `json:"alert,omitempty"`
+}
+
+type SavedSearchAlert struct {
+	Emails []string `json:"emails,omitempty"`
+
+	// Only the hosts allowed in [unified_storage] saved_search_webhook_allowed_hosts can be used
+	Webhooks []string `json:"webhooks,omitempty"`
+}
+
+type SavedSearchStatus struct {
+	// The generation of the saved search the matches were recorded for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// The resources matching the query on the last evaluation ({group}/{resource}/{name})
+	Matches []string `json:"matches,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SavedSearchList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []SavedSearch `json:"items"`
+}
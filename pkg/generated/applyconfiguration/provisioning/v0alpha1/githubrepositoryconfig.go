@@ -7,12 +7,13 @@ package v0alpha1
 // GitHubRepositoryConfigApplyConfiguration represents a declarative configuration of the GitHubRepositoryConfig type for use
 // with apply.
 type GitHubRepositoryConfigApplyConfiguration struct {
-	URL                       *string `json:"url,omitempty"`
-	Branch                    *string `json:"branch,omitempty"`
-	Token                     *string `json:"token,omitempty"`
-	EncryptedToken            []byte  `json:"encryptedToken,omitempty"`
-	GenerateDashboardPreviews *bool   `json:"generateDashboardPreviews,omitempty"`
-	Path                      *string `json:"path,omitempty"`
+	URL                       *string                                `json:"url,omitempty"`
+	Branch                    *string                                `json:"branch,omitempty"`
+	Token                     *string                                `json:"token,omitempty"`
+	EncryptedToken            []byte                                 `json:"encryptedToken,omitempty"`
+	GenerateDashboardPreviews *bool                                  `json:"generateDashboardPreviews,omitempty"`
+	Path                      *string                                `json:"path,omitempty"`
+	Signing                   *CommitSigningConfigApplyConfiguration `json:"signing,omitempty"`
 }
 
 // GitHubRepositoryConfigApplyConfiguration constructs a declarative configuration of the GitHubRepositoryConfig type for use with
@@ -70,3 +71,11 @@ func (b *GitHubRepositoryConfigApplyConfiguration) WithPath(value string) *GitHu
 	b.Path = &value
 	return b
 }
+
+// WithSigning sets the Signing field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Signing field is set to the value of the last call.
+func (b *GitHubRepositoryConfigApplyConfiguration) WithSigning(value *CommitSigningConfigApplyConfiguration) *GitHubRepositoryConfigApplyConfiguration {
+	b.Signing = value
+	return b
+}
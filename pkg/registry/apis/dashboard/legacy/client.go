@@ -48,6 +48,11 @@ func (d *directResourceClient) IsHealthy(ctx context.Context, in *resourcepb.Hea
 	return d.server.IsHealthy(ctx, in)
 }
 
+// ListOnlineMigrations implements ResourceClient.
+func (d *directResourceClient) ListOnlineMigrations(ctx context.Context, in *resourcepb.ListOnlineMigrationsRequest, opts ...grpc.CallOption) (*resourcepb.ListOnlineMigrationsResponse, error) {
+	return d.server.ListOnlineMigrations(ctx, in)
+}
+
 // List implements ResourceClient.
 func (d *directResourceClient) List(ctx context.Context, in *resourcepb.ListRequest, opts ...grpc.CallOption) (*resourcepb.ListResponse, error) {
 	return d.server.List(ctx, in)
@@ -90,3 +95,23 @@ func (d *directResourceClient) Watch(ctx context.Context, in *resourcepb.WatchRe
 func (d *directResourceClient) BulkProcess(ctx context.Context, opts ...grpc.CallOption) (resourcepb.BulkStore_BulkProcessClient, error) {
 	return nil, fmt.Errorf("BulkProcess not supported with direct resource client")
 }
+
+// Restore implements resource.ResourceClient.
+func (d *directResourceClient) Restore(ctx context.Context, in *resourcepb.RestoreRequest, opts ...grpc.CallOption) (*resourcepb.RestoreResponse, error) {
+	return d.server.Restore(ctx, in)
+}
+
+// ListTrash implements resource.ResourceClient.
+func (d *directResourceClient) ListTrash(ctx context.Context, in *resourcepb.ListTrashRequest, opts ...grpc.CallOption) (*resourcepb.ListTrashResponse, error) {
+	return d.server.ListTrash(ctx, in)
+}
+
+// RestoreFromTrash implements resource.ResourceClient.
+func (d *directResourceClient) RestoreFromTrash(ctx context.Context, in *resourcepb.RestoreFromTrashRequest, opts ...grpc.CallOption) (*resourcepb.RestoreFromTrashResponse, error) {
+	return d.server.RestoreFromTrash(ctx, in)
+}
+
+// PurgeTrash implements resource.ResourceClient.
+func (d *directResourceClient) PurgeTrash(ctx context.Context, in *resourcepb.PurgeTrashRequest, opts ...grpc.CallOption) (*resourcepb.PurgeTrashResponse, error) {
+	return d.server.PurgeTrash(ctx, in)
+}
@@ -708,6 +708,21 @@ func (m *MockClient) ListManagedObjects(ctx context.Context, in *resourcepb.List
 func (m *MockClient) IsHealthy(ctx context.Context, in *resourcepb.HealthCheckRequest, opts ...grpc.CallOption) (*resourcepb.HealthCheckResponse, error) {
 	return nil, nil
 }
+func (m *MockClient) ListOnlineMigrations(ctx context.Context, in *resourcepb.ListOnlineMigrationsRequest, opts ...grpc.CallOption) (*resourcepb.ListOnlineMigrationsResponse, error) {
+	return nil, nil
+}
 func (m *MockClient) BulkProcess(ctx context.Context, opts ...grpc.CallOption) (resourcepb.BulkStore_BulkProcessClient, error) {
 	return nil, nil
 }
+func (m *MockClient) Restore(ctx context.Context, in *resourcepb.RestoreRequest, opts ...grpc.CallOption) (*resourcepb.RestoreResponse, error) {
+	return nil, nil
+}
+func (m *MockClient) ListTrash(ctx context.Context, in *resourcepb.ListTrashRequest, opts ...grpc.CallOption) (*resourcepb.ListTrashResponse, error) {
+	return nil, nil
+}
+func (m *MockClient) RestoreFromTrash(ctx context.Context, in *resourcepb.RestoreFromTrashRequest, opts ...grpc.CallOption) (*resourcepb.RestoreFromTrashResponse, error) {
+	return nil, nil
+}
+func (m *MockClient) PurgeTrash(ctx context.Context, in *resourcepb.PurgeTrashRequest, opts ...grpc.CallOption) (*resourcepb.PurgeTrashResponse, error) {
+	return nil, nil
+}
@@ -98,6 +98,80 @@ func (_c *MockBulkStoreClient_BulkProcess_Call) RunAndReturn(run func(context.Co
 	return _c
 }
 
+// Restore provides a mock function with given fields: ctx, in, opts
+func (_m *MockBulkStoreClient) Restore(ctx context.Context, in *resourcepb.RestoreRequest, opts ...grpc.CallOption) (*resourcepb.RestoreResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 *resourcepb.RestoreResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *resourcepb.RestoreRequest, ...grpc.CallOption) (*resourcepb.RestoreResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *resourcepb.RestoreRequest, ...grpc.CallOption) *resourcepb.RestoreResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*resourcepb.RestoreResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *resourcepb.RestoreRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockBulkStoreClient_Restore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Restore'
+type MockBulkStoreClient_Restore_Call struct {
+	*mock.Call
+}
+
+// Restore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *resourcepb.RestoreRequest
+//   - opts ...grpc.CallOption
+func (_e *MockBulkStoreClient_Expecter) Restore(ctx interface{}, in interface{}, opts ...interface{}) *MockBulkStoreClient_Restore_Call {
+	return &MockBulkStoreClient_Restore_Call{Call: _e.mock.On("Restore",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *MockBulkStoreClient_Restore_Call) Run(run func(ctx context.Context, in *resourcepb.RestoreRequest, opts ...grpc.CallOption)) *MockBulkStoreClient_Restore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]grpc.CallOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(grpc.CallOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*resourcepb.RestoreRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockBulkStoreClient_Restore_Call) Return(_a0 *resourcepb.RestoreResponse, _a1 error) *MockBulkStoreClient_Restore_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockBulkStoreClient_Restore_Call) RunAndReturn(run func(context.Context, *resourcepb.RestoreRequest, ...grpc.CallOption) (*resourcepb.RestoreResponse, error)) *MockBulkStoreClient_Restore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockBulkStoreClient creates a new instance of MockBulkStoreClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockBulkStoreClient(t interface {
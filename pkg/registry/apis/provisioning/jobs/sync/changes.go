@@ -64,6 +64,7 @@ func Changes(source []repository.FileTreeEntry, target *provisioning.ResourceLis
 		lookup[item.Path] = &item
 	}
 
+	librariesDigest := resources.LibrariesDigest(source)
 	keep := safepath.NewTrie()
 	changes := make([]ResourceFileChange, 0, len(source))
 	for _, file := range source {
@@ -72,9 +73,18 @@ func Changes(source []repository.FileTreeEntry, target *provisioning.ResourceLis
 			file.Path = file.Path + "/"
 		}
 
+		// Libraries installed by jsonnet-bundler are not resources, nor are their folders
+		if resources.IsVendorPath(file.Path) && (!file.Blob || resources.IsLibraryPath(file.Path)) {
+			continue
+		}
+
 		check, ok := lookup[file.Path]
 		if ok {
-			if check.Hash != file.Hash && check.Resource != resources.FolderResource.Resource {
+			hash := file.Hash
+			if resources.SupportsImports(file.Path) {
+				hash = resources.RenderedChecksum(file.Hash, librariesDigest)
+			}
+			if check.Hash != hash && check.Resource != resources.FolderResource.Resource {
 				changes = append(changes, ResourceFileChange{
 					Action:   repository.FileActionUpdated,
 					Path:     check.Path,
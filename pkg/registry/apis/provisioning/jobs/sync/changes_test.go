@@ -29,6 +29,41 @@ func TestChanges(t *testing.T) {
 		require.Empty(t, changes)
 	})
 
+	t.Run("rendered files with imports are updated when a library changes", func(t *testing.T) {
+		source := []repository.FileTreeEntry{
+			{Path: "dashboards/generated.jsonnet", Hash: "xyz", Blob: true},
+			{Path: "dashboards/lib/panels.libsonnet", Hash: "abc", Blob: true},
+			{Path: "dashboards/config.cue", Hash: "xyz", Blob: true},
+			{Path: "vendor/", Hash: "", Blob: false},
+			{Path: "vendor/grafonnet/main.jsonnet", Hash: "def", Blob: true},
+		}
+		librariesDigest := resources.LibrariesDigest(source)
+		require.NotEmpty(t, librariesDigest)
+
+		// Rendered with the same libraries
+		target := &provisioning.ResourceList{
+			Items: []provisioning.ResourceListItem{
+				{Path: "dashboards/generated.jsonnet", Hash: resources.RenderedChecksum("xyz", librariesDigest)},
+				{Path: "dashboards/config.cue", Hash: "xyz"},
+			},
+		}
+		changes, err := Changes(source, target)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, repository.FileActionCreated, changes[0].Action)
+		require.Equal(t, "dashboards/lib/", changes[0].Path)
+
+		// Rendered with other libraries
+		target.Items[0].Hash = resources.RenderedChecksum("xyz", "previous")
+		changes, err = Changes(source, target)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		require.Equal(t, repository.FileActionUpdated, changes[0].Action)
+		require.Equal(t, "dashboards/generated.jsonnet", changes[0].Path)
+		require.Equal(t, repository.FileActionCreated, changes[1].Action)
+		require.Equal(t, "dashboards/lib/", changes[1].Path)
+	})
+
 	t.Run("create a source file", func(t *testing.T) {
 		source := []repository.FileTreeEntry{
 			{Path: "muta.json", Hash: "xyz", Blob: true},
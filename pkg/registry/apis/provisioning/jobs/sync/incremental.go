@@ -27,6 +27,12 @@ func IncrementalSync(ctx context.Context, repo repository.Versioned, previousRef
 		return nil
 	}
 
+	dependents, err := renderedDependents(ctx, diff, currentRef, repositoryResources)
+	if err != nil {
+		return fmt.Errorf("find rendered files to update: %w", err)
+	}
+	diff = append(diff, dependents...)
+
 	progress.SetTotal(ctx, len(diff))
 	progress.SetMessage(ctx, "replicating versioned changes")
 
@@ -45,7 +51,8 @@ func IncrementalSync(ctx context.Context, repo repository.Versioned, previousRef
 				safeSegment = safepath.Dir(safeSegment)
 			}
 
-			if safeSegment != "" && resources.IsPathSupported(safeSegment) == nil {
+			// Libraries installed by jsonnet-bundler do not need a folder
+			if safeSegment != "" && !resources.IsVendorPath(safeSegment) && resources.IsPathSupported(safeSegment) == nil {
 				folder, err := repositoryResources.EnsureFolderPathExist(ctx, safeSegment)
 				if err != nil {
 					return fmt.Errorf("unable to create empty file folder: %w", err)
@@ -109,3 +116,39 @@ func IncrementalSync(ctx context.Context, repo repository.Versioned, previousRef
 
 	return nil
 }
+
+// renderedDependents returns an update for every rendered file that was not changed itself
+// when a library it may import has changed.
+// Imports are not tracked, so all the rendered files that support imports are updated.
+func renderedDependents(ctx context.Context, diff []repository.VersionedFileChange, ref string, repositoryResources resources.RepositoryResources) ([]repository.VersionedFileChange, error) {
+	changed := make(map[string]bool, len(diff))
+	libraryChanged := false
+	for _, change := range diff {
+		changed[change.Path] = true
+		if resources.IsLibraryPath(change.Path) || resources.IsLibraryPath(change.PreviousPath) {
+			libraryChanged = true
+		}
+	}
+	if !libraryChanged {
+		return nil, nil
+	}
+
+	list, err := repositoryResources.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list resources: %w", err)
+	}
+
+	var dependents []repository.VersionedFileChange
+	for _, item := range list.Items {
+		if !resources.SupportsImports(item.Path) || changed[item.Path] {
+			continue
+		}
+		dependents = append(dependents, repository.VersionedFileChange{
+			Action: repository.FileActionUpdated,
+			Path:   item.Path,
+			Ref:    ref,
+		})
+	}
+
+	return dependents, nil
+}
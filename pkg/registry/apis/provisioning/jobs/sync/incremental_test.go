@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	provisioning "github.com/grafana/grafana/pkg/apis/provisioning/v0alpha1"
 	"github.com/grafana/grafana/pkg/registry/apis/provisioning/jobs"
 	"github.com/grafana/grafana/pkg/registry/apis/provisioning/repository"
 	"github.com/grafana/grafana/pkg/registry/apis/provisioning/resources"
@@ -168,6 +169,56 @@ func TestIncrementalSync(t *testing.T) {
 			currentRef:    "new-ref",
 			expectedCalls: 1,
 		},
+		{
+			name: "library change renders the files that may import it",
+			setupMocks: func(repo *repository.MockVersioned, repoResources *resources.MockRepositoryResources, progress *jobs.MockJobProgressRecorder) {
+				changes := []repository.VersionedFileChange{
+					{
+						Action: repository.FileActionUpdated,
+						Path:   "dashboards/lib/panels.libsonnet",
+						Ref:    "new-ref",
+					},
+					{
+						Action: repository.FileActionUpdated,
+						Path:   "dashboards/changed.jsonnet",
+						Ref:    "new-ref",
+					},
+				}
+				repo.On("CompareFiles", mock.Anything, "old-ref", "new-ref").Return(changes, nil)
+				repoResources.On("List", mock.Anything).Return(&provisioning.ResourceList{
+					Items: []provisioning.ResourceListItem{
+						{Path: "dashboards/changed.jsonnet"},
+						{Path: "dashboards/other.jsonnet"},
+						{Path: "dashboards/plain.json"},
+						{Path: "dashboards/config.cue"},
+					},
+				}, nil)
+				progress.On("SetTotal", mock.Anything, 3).Return()
+				progress.On("SetMessage", mock.Anything, "replicating versioned changes").Return()
+				progress.On("SetMessage", mock.Anything, "versioned changes replicated").Return()
+
+				// The library itself is not a resource
+				repoResources.On("EnsureFolderPathExist", mock.Anything, "dashboards/lib/").
+					Return("lib-folder", nil)
+				progress.On("Record", mock.Anything, mock.MatchedBy(func(result jobs.JobResourceResult) bool {
+					return result.Path == "dashboards/lib/"
+				})).Return()
+
+				repoResources.On("WriteResourceFromFile", mock.Anything, "dashboards/changed.jsonnet", "new-ref").
+					Return("changed", schema.GroupVersionKind{Kind: "Dashboard", Group: "dashboards"}, nil).Once()
+				repoResources.On("WriteResourceFromFile", mock.Anything, "dashboards/other.jsonnet", "new-ref").
+					Return("other", schema.GroupVersionKind{Kind: "Dashboard", Group: "dashboards"}, nil).Once()
+				progress.On("Record", mock.Anything, mock.MatchedBy(func(result jobs.JobResourceResult) bool {
+					return result.Action == repository.FileActionUpdated &&
+						(result.Path == "dashboards/changed.jsonnet" || result.Path == "dashboards/other.jsonnet")
+				})).Return().Twice()
+
+				progress.On("TooManyErrors").Return(nil)
+			},
+			previousRef:   "old-ref",
+			currentRef:    "new-ref",
+			expectedCalls: 3,
+		},
 		{
 			name: "file deletion",
 			setupMocks: func(repo *repository.MockVersioned, repoResources *resources.MockRepositoryResources, progress *jobs.MockJobProgressRecorder) {
@@ -50,6 +50,10 @@ func (r *syncer) Sync(ctx context.Context, repo repository.ReaderWriter, options
 			return "", fmt.Errorf("get latest ref: %w", err)
 		}
 
+		if err := verifyCommits(ctx, repo, cfg.Status.Sync.LastRef, currentRef); err != nil {
+			return "", err
+		}
+
 		if cfg.Status.Sync.LastRef != "" && options.Incremental {
 			progress.SetMessage(ctx, "incremental sync")
 			return currentRef, r.incrementalSync(ctx, versionedRepo, cfg.Status.Sync.LastRef, currentRef, repositoryResources, progress)
@@ -60,3 +64,28 @@ func (r *syncer) Sync(ctx context.Context, repo repository.ReaderWriter, options
 
 	return currentRef, r.fullSync(ctx, repo, r.compare, clients, currentRef, repositoryResources, progress)
 }
+
+// verifyCommits refuses to sync a ref if the repository requires signed commits and any commit since the last synced ref
+// does not verify. Signing the head commit does not vouch for the commits before it, so each commit is verified.
+// On the first sync there is no previous ref, and only the head commit is verified.
+func verifyCommits(ctx context.Context, repo repository.Repository, lastRef, ref string) error {
+	cfg := repo.Config()
+	if cfg.Spec.GitHub == nil || cfg.Spec.GitHub.Signing == nil || !cfg.Spec.GitHub.Signing.RequireVerified {
+		return nil
+	}
+
+	verifier, ok := repo.(repository.SignatureVerifier)
+	if !ok {
+		return fmt.Errorf("repository does not support signature verification")
+	}
+
+	if lastRef == ref {
+		return nil
+	}
+
+	if err := verifier.VerifyCommits(ctx, lastRef, ref); err != nil {
+		return fmt.Errorf("verify signatures up to commit %s: %w", ref, err)
+	}
+
+	return nil
+}
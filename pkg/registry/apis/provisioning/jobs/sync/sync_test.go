@@ -178,3 +178,97 @@ func TestSyncer_Sync(t *testing.T) {
 		})
 	}
 }
+
+type mockVerifiedReaderWriter struct {
+	*mockReaderWriter
+	*repository.MockSignatureVerifier
+}
+
+func TestSyncer_SyncVerifiesSignatures(t *testing.T) {
+	signedRepo := &provisioning.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-repo",
+		},
+		Spec: provisioning.RepositorySpec{
+			Type: provisioning.GitHubRepositoryType,
+			GitHub: &provisioning.GitHubRepositoryConfig{
+				Signing: &provisioning.CommitSigningConfig{
+					RequireVerified: true,
+					TrustedKeys:     []string{"ssh-ed25519 AAAA"},
+				},
+			},
+		},
+	}
+
+	t.Run("verified commit is synced", func(t *testing.T) {
+		repo := &mockVerifiedReaderWriter{
+			mockReaderWriter: &mockReaderWriter{
+				MockRepository: repository.NewMockRepository(t),
+				MockVersioned:  repository.NewMockVersioned(t),
+			},
+			MockSignatureVerifier: repository.NewMockSignatureVerifier(t),
+		}
+		repo.MockRepository.On("Config").Return(signedRepo)
+		repo.MockVersioned.On("LatestRef", mock.Anything).Return("new-ref", nil)
+		repo.MockSignatureVerifier.EXPECT().VerifyCommits(mock.Anything, "", "new-ref").Return(nil)
+
+		progress := jobs.NewMockJobProgressRecorder(t)
+		progress.On("SetMessage", mock.Anything, "full sync").Return()
+		fullSyncFn := NewMockFullSyncFn(t)
+		fullSyncFn.EXPECT().Execute(mock.Anything, mock.Anything, mock.Anything, mock.Anything, "new-ref", mock.Anything, mock.Anything).Return(nil)
+
+		syncer := NewSyncer(NewMockCompareFn(t).Execute, fullSyncFn.Execute, NewMockIncrementalSyncFn(t).Execute)
+		ref, err := syncer.Sync(context.Background(), repo, provisioning.SyncJobOptions{}, resources.NewMockRepositoryResources(t), resources.NewMockResourceClients(t), progress)
+		require.NoError(t, err)
+		require.Equal(t, "new-ref", ref)
+	})
+
+	t.Run("unverified commit is refused", func(t *testing.T) {
+		repo := &mockVerifiedReaderWriter{
+			mockReaderWriter: &mockReaderWriter{
+				MockRepository: repository.NewMockRepository(t),
+				MockVersioned:  repository.NewMockVersioned(t),
+			},
+			MockSignatureVerifier: repository.NewMockSignatureVerifier(t),
+		}
+		repo.MockRepository.On("Config").Return(signedRepo)
+		repo.MockVersioned.On("LatestRef", mock.Anything).Return("new-ref", nil)
+		repo.MockSignatureVerifier.EXPECT().VerifyCommits(mock.Anything, "", "new-ref").Return(fmt.Errorf("commit is not signed"))
+
+		syncer := NewSyncer(NewMockCompareFn(t).Execute, NewMockFullSyncFn(t).Execute, NewMockIncrementalSyncFn(t).Execute)
+		_, err := syncer.Sync(context.Background(), repo, provisioning.SyncJobOptions{}, resources.NewMockRepositoryResources(t), resources.NewMockResourceClients(t), jobs.NewMockJobProgressRecorder(t))
+		require.EqualError(t, err, "verify signatures up to commit new-ref: commit is not signed")
+	})
+
+	t.Run("every commit since the last sync is verified", func(t *testing.T) {
+		syncedRepo := signedRepo.DeepCopy()
+		syncedRepo.Status.Sync.LastRef = "old-ref"
+		repo := &mockVerifiedReaderWriter{
+			mockReaderWriter: &mockReaderWriter{
+				MockRepository: repository.NewMockRepository(t),
+				MockVersioned:  repository.NewMockVersioned(t),
+			},
+			MockSignatureVerifier: repository.NewMockSignatureVerifier(t),
+		}
+		repo.MockRepository.On("Config").Return(syncedRepo)
+		repo.MockVersioned.On("LatestRef", mock.Anything).Return("new-ref", nil)
+		repo.MockSignatureVerifier.EXPECT().VerifyCommits(mock.Anything, "old-ref", "new-ref").Return(fmt.Errorf("commit middle-ref: commit is not signed"))
+
+		syncer := NewSyncer(NewMockCompareFn(t).Execute, NewMockFullSyncFn(t).Execute, NewMockIncrementalSyncFn(t).Execute)
+		_, err := syncer.Sync(context.Background(), repo, provisioning.SyncJobOptions{Incremental: true}, resources.NewMockRepositoryResources(t), resources.NewMockResourceClients(t), jobs.NewMockJobProgressRecorder(t))
+		require.EqualError(t, err, "verify signatures up to commit new-ref: commit middle-ref: commit is not signed")
+	})
+
+	t.Run("repository without verification support is refused", func(t *testing.T) {
+		repo := &mockReaderWriter{
+			MockRepository: repository.NewMockRepository(t),
+			MockVersioned:  repository.NewMockVersioned(t),
+		}
+		repo.MockRepository.On("Config").Return(signedRepo)
+		repo.MockVersioned.On("LatestRef", mock.Anything).Return("new-ref", nil)
+
+		syncer := NewSyncer(NewMockCompareFn(t).Execute, NewMockFullSyncFn(t).Execute, NewMockIncrementalSyncFn(t).Execute)
+		_, err := syncer.Sync(context.Background(), repo, provisioning.SyncJobOptions{}, resources.NewMockRepositoryResources(t), resources.NewMockResourceClients(t), jobs.NewMockJobProgressRecorder(t))
+		require.EqualError(t, err, "repository does not support signature verification")
+	})
+}
@@ -47,6 +47,7 @@ import (
 	"github.com/grafana/grafana/pkg/registry/apis/provisioning/repository"
 	"github.com/grafana/grafana/pkg/registry/apis/provisioning/repository/github"
 	gogit "github.com/grafana/grafana/pkg/registry/apis/provisioning/repository/go-git"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/repository/signing"
 	"github.com/grafana/grafana/pkg/registry/apis/provisioning/resources"
 	"github.com/grafana/grafana/pkg/registry/apis/provisioning/resources/signature"
 	"github.com/grafana/grafana/pkg/registry/apis/provisioning/safepath"
@@ -415,6 +416,10 @@ func (b *APIBuilder) Mutate(ctx context.Context, a admission.Attributes, o admis
 		return fmt.Errorf("failed to encrypt secrets: %w", err)
 	}
 
+	if err := b.encryptSigningKey(ctx, r); err != nil {
+		return fmt.Errorf("failed to encrypt signing key: %w", err)
+	}
+
 	// Mutate the repository with any extra mutators
 	for _, extra := range b.extras {
 		if err := extra.Mutate(ctx, r); err != nil {
@@ -440,6 +445,28 @@ func (b *APIBuilder) encryptGithubToken(ctx context.Context, repo *provisioning.
 	return nil
 }
 
+// encryptSigningKey validates the commit signing key and stores it encrypted
+func (b *APIBuilder) encryptSigningKey(ctx context.Context, repo *provisioning.Repository) error {
+	if repo.Spec.GitHub == nil || repo.Spec.GitHub.Signing == nil || repo.Spec.GitHub.Signing.Key == "" {
+		return nil
+	}
+
+	cfg := repo.Spec.GitHub.Signing
+	// The key can not be validated once encrypted, so make sure it is usable first
+	if _, err := signing.NewSigner(cfg.Format, []byte(cfg.Key)); err != nil {
+		return err
+	}
+
+	var err error
+	cfg.EncryptedKey, err = b.secrets.Encrypt(ctx, []byte(cfg.Key))
+	if err != nil {
+		return err
+	}
+	cfg.Key = ""
+
+	return nil
+}
+
 // TODO: move logic to a more appropriate place. Probably controller/validation.go
 func (b *APIBuilder) Validate(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) (err error) {
 	obj := a.GetObject()
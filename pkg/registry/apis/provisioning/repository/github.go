@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana-app-sdk/logging"
 	provisioning "github.com/grafana/grafana/pkg/apis/provisioning/v0alpha1"
 	pgh "github.com/grafana/grafana/pkg/registry/apis/provisioning/repository/github"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/repository/signing"
 	"github.com/grafana/grafana/pkg/registry/apis/provisioning/safepath"
 	"github.com/grafana/grafana/pkg/registry/apis/provisioning/secrets"
 )
@@ -129,6 +130,29 @@ func (r *githubRepository) Validate() (list field.ErrorList) {
 		list = append(list, field.Invalid(field.NewPath("spec", "github", "prefix"), gh.Path, "path must be relative"))
 	}
 
+	if gh.Signing != nil {
+		list = append(list, validateSigning(gh.Signing)...)
+	}
+
+	return list
+}
+
+func validateSigning(cfg *provisioning.CommitSigningConfig) (list field.ErrorList) {
+	switch cfg.Format {
+	case "", provisioning.GPGSigningFormat, provisioning.SSHSigningFormat:
+	default:
+		list = append(list, field.NotSupported(field.NewPath("spec", "github", "signing", "format"), cfg.Format,
+			[]provisioning.SigningFormat{provisioning.GPGSigningFormat, provisioning.SSHSigningFormat}))
+	}
+
+	if cfg.RequireVerified && len(cfg.TrustedKeys) == 0 {
+		list = append(list, field.Required(field.NewPath("spec", "github", "signing", "trustedKeys"), "trusted keys are required to verify signatures"))
+	} else if len(cfg.TrustedKeys) > 0 {
+		if _, err := signing.NewVerifier(cfg.TrustedKeys); err != nil {
+			list = append(list, field.Invalid(field.NewPath("spec", "github", "signing", "trustedKeys"), "", err.Error()))
+		}
+	}
+
 	return list
 }
 
@@ -530,6 +554,42 @@ func (r *githubRepository) LatestRef(ctx context.Context) (string, error) {
 	return branch.Sha, nil
 }
 
+// VerifyCommits implements SignatureVerifier.
+func (r *githubRepository) VerifyCommits(ctx context.Context, base, ref string) error {
+	ctx, _ = r.logger(ctx, ref)
+
+	var trustedKeys []string
+	if cfg := r.config.Spec.GitHub.Signing; cfg != nil {
+		trustedKeys = cfg.TrustedKeys
+	}
+	verifier, err := signing.NewVerifier(trustedKeys)
+	if err != nil {
+		return fmt.Errorf("create verifier: %w", err)
+	}
+
+	var signatures []pgh.CommitSignature
+	if base == "" {
+		sig, err := r.gh.GetCommitSignature(ctx, r.owner, r.repo, ref)
+		if err != nil {
+			return fmt.Errorf("get commit signature: %w", err)
+		}
+		signatures = append(signatures, sig)
+	} else {
+		signatures, err = r.gh.CompareCommitSignatures(ctx, r.owner, r.repo, base, ref)
+		if err != nil {
+			return fmt.Errorf("get commit signatures: %w", err)
+		}
+	}
+
+	for _, sig := range signatures {
+		if err := verifier.Verify([]byte(sig.Payload), []byte(sig.Signature)); err != nil {
+			return fmt.Errorf("commit %s: %w", sig.SHA, err)
+		}
+	}
+
+	return nil
+}
+
 func (r *githubRepository) CompareFiles(ctx context.Context, base, ref string) ([]VersionedFileChange, error) {
 	if ref == "" {
 		var err error
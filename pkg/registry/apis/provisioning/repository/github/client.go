@@ -82,6 +82,13 @@ type Client interface {
 	// CompareCommits returns the changes between two commits.
 	CompareCommits(ctx context.Context, owner, repository, base, head string) ([]CommitFile, error)
 
+	// GetCommitSignature returns the signed payload and the signature of a commit.
+	// Both values are empty if the commit is not signed.
+	GetCommitSignature(ctx context.Context, owner, repository, sha string) (CommitSignature, error)
+
+	// CompareCommitSignatures returns the signatures of the commits reachable from head but not from base.
+	CompareCommitSignatures(ctx context.Context, owner, repository, base, head string) ([]CommitSignature, error)
+
 	// RepoExists checks if a repository exists.
 	RepoExists(ctx context.Context, owner, repository string) (bool, error)
 
@@ -142,6 +149,15 @@ type Commit struct {
 	CreatedAt time.Time
 }
 
+type CommitSignature struct {
+	// The SHA of the signed commit.
+	SHA string
+	// The raw commit object, without the signature header, which was signed.
+	Payload string
+	// The armored signature. Empty if the commit is not signed.
+	Signature string
+}
+
 //go:generate mockery --name CommitFile --structname MockCommitFile --inpackage --filename mock_commit_file.go --with-expecter
 type CommitFile interface {
 	GetSHA() string
@@ -66,6 +66,7 @@ const (
 	maxTreeItems                = 10000            // Maximum number of items allowed in a tree
 	maxCommits                  = 1000             // Maximum number of commits to fetch
 	maxCompareFiles             = 1000             // Maximum number of files to compare between commits
+	maxCompareCommits           = 1000             // Maximum number of commits to verify between commits
 	maxWebhooks                 = 100              // Maximum number of webhooks allowed per repository
 	maxPRFiles                  = 1000             // Maximum number of files allowed in a pull request
 	maxPullRequestsFileComments = 1000             // Maximum number of comments allowed in a pull request
@@ -373,6 +374,60 @@ func (r *githubClient) CompareCommits(ctx context.Context, owner, repository, ba
 	return ret, nil
 }
 
+func (r *githubClient) GetCommitSignature(ctx context.Context, owner, repository, sha string) (CommitSignature, error) {
+	commit, resp, err := r.gh.Git.GetCommit(ctx, owner, repository, sha)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return CommitSignature{}, ErrResourceNotFound
+		}
+		if resp != nil && resp.StatusCode == http.StatusServiceUnavailable {
+			return CommitSignature{}, ErrServiceUnavailable
+		}
+		return CommitSignature{}, err
+	}
+
+	verification := commit.GetVerification()
+	return CommitSignature{
+		SHA:       commit.GetSHA(),
+		Payload:   verification.GetPayload(),
+		Signature: verification.GetSignature(),
+	}, nil
+}
+
+func (r *githubClient) CompareCommitSignatures(ctx context.Context, owner, repository, base, head string) ([]CommitSignature, error) {
+	listFn := func(ctx context.Context, opts *github.ListOptions) ([]*github.RepositoryCommit, *github.Response, error) {
+		compare, resp, err := r.gh.Repositories.CompareCommits(ctx, owner, repository, base, head, opts)
+		if err != nil {
+			return nil, resp, err
+		}
+		return compare.Commits, resp, nil
+	}
+
+	commits, err := paginatedList(
+		ctx,
+		listFn,
+		defaultListOptions(maxCompareCommits),
+	)
+	if errors.Is(err, ErrTooManyItems) {
+		return nil, fmt.Errorf("too many commits between %s and %s (more than %d)", base, head, maxCompareCommits)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]CommitSignature, 0, len(commits))
+	for _, c := range commits {
+		verification := c.GetCommit().GetVerification()
+		ret = append(ret, CommitSignature{
+			SHA:       c.GetSHA(),
+			Payload:   verification.GetPayload(),
+			Signature: verification.GetSignature(),
+		})
+	}
+
+	return ret, nil
+}
+
 func (r *githubClient) GetBranch(ctx context.Context, owner, repository, branchName string) (Branch, error) {
 	branch, resp, err := r.gh.Repositories.GetBranch(ctx, owner, repository, branchName, 0)
 	if err != nil {
@@ -1955,6 +1955,177 @@ func TestCompareCommits(t *testing.T) {
 	}
 }
 
+func TestGithubClient_GetCommitSignature(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockHandler   *http.Client
+		wantSignature CommitSignature
+		wantErr       error
+	}{
+		{
+			name: "signed commit",
+			mockHandler: mockhub.NewMockedHTTPClient(
+				mockhub.WithRequestMatchHandler(
+					mockhub.GetReposGitCommitsByOwnerByRepoByCommitSha,
+					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+						commit := &github.Commit{
+							SHA: github.Ptr("abc123"),
+							Verification: &github.SignatureVerification{
+								Verified:  github.Ptr(true),
+								Payload:   github.Ptr("tree abc\n\nmessage"),
+								Signature: github.Ptr("-----BEGIN PGP SIGNATURE-----"),
+							},
+						}
+						w.WriteHeader(http.StatusOK)
+						require.NoError(t, json.NewEncoder(w).Encode(commit))
+					}),
+				),
+			),
+			wantSignature: CommitSignature{
+				SHA:       "abc123",
+				Payload:   "tree abc\n\nmessage",
+				Signature: "-----BEGIN PGP SIGNATURE-----",
+			},
+		},
+		{
+			name: "unsigned commit",
+			mockHandler: mockhub.NewMockedHTTPClient(
+				mockhub.WithRequestMatchHandler(
+					mockhub.GetReposGitCommitsByOwnerByRepoByCommitSha,
+					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+						commit := &github.Commit{
+							SHA: github.Ptr("abc123"),
+							Verification: &github.SignatureVerification{
+								Verified: github.Ptr(false),
+								Reason:   github.Ptr("unsigned"),
+							},
+						}
+						w.WriteHeader(http.StatusOK)
+						require.NoError(t, json.NewEncoder(w).Encode(commit))
+					}),
+				),
+			),
+			wantSignature: CommitSignature{SHA: "abc123"},
+		},
+		{
+			name: "commit not found",
+			mockHandler: mockhub.NewMockedHTTPClient(
+				mockhub.WithRequestMatchHandler(
+					mockhub.GetReposGitCommitsByOwnerByRepoByCommitSha,
+					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+						w.WriteHeader(http.StatusNotFound)
+						require.NoError(t, json.NewEncoder(w).Encode(github.ErrorResponse{
+							Response: &http.Response{
+								StatusCode: http.StatusNotFound,
+							},
+							Message: "Not Found",
+						}))
+					}),
+				),
+			),
+			wantErr: ErrResourceNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := ProvideFactory()
+			factory.Client = tt.mockHandler
+			client := factory.New(context.Background(), "")
+
+			sig, err := client.GetCommitSignature(context.Background(), "test-owner", "test-repo", "abc123")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSignature, sig)
+		})
+	}
+}
+
+func TestGithubClient_CompareCommitSignatures(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockHandler    *http.Client
+		wantSignatures []CommitSignature
+		wantErr        error
+	}{
+		{
+			name: "signed and unsigned commits",
+			mockHandler: mockhub.NewMockedHTTPClient(
+				mockhub.WithRequestMatchHandler(
+					mockhub.GetReposCompareByOwnerByRepoByBasehead,
+					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+						comparison := &github.CommitsComparison{
+							Commits: []*github.RepositoryCommit{
+								{
+									SHA: github.Ptr("abc123"),
+									Commit: &github.Commit{
+										Verification: &github.SignatureVerification{
+											Payload:   github.Ptr("tree abc\n\nfirst"),
+											Signature: github.Ptr("-----BEGIN PGP SIGNATURE-----"),
+										},
+									},
+								},
+								{
+									SHA: github.Ptr("def456"),
+									Commit: &github.Commit{
+										Verification: &github.SignatureVerification{
+											Reason: github.Ptr("unsigned"),
+										},
+									},
+								},
+							},
+						}
+						w.WriteHeader(http.StatusOK)
+						require.NoError(t, json.NewEncoder(w).Encode(comparison))
+					}),
+				),
+			),
+			wantSignatures: []CommitSignature{
+				{SHA: "abc123", Payload: "tree abc\n\nfirst", Signature: "-----BEGIN PGP SIGNATURE-----"},
+				{SHA: "def456"},
+			},
+		},
+		{
+			name: "too many commits",
+			mockHandler: mockhub.NewMockedHTTPClient(
+				mockhub.WithRequestMatchHandler(
+					mockhub.GetReposCompareByOwnerByRepoByBasehead,
+					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+						commits := make([]*github.RepositoryCommit, maxCompareCommits+1)
+						for i := range commits {
+							commits[i] = &github.RepositoryCommit{SHA: github.Ptr(fmt.Sprintf("sha%d", i))}
+						}
+						w.WriteHeader(http.StatusOK)
+						require.NoError(t, json.NewEncoder(w).Encode(&github.CommitsComparison{Commits: commits}))
+					}),
+				),
+			),
+			wantErr: fmt.Errorf("too many commits between base and head (more than %d)", maxCompareCommits),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := ProvideFactory()
+			factory.Client = tt.mockHandler
+			client := factory.New(context.Background(), "")
+
+			signatures, err := client.CompareCommitSignatures(context.Background(), "test-owner", "test-repo", "base", "head")
+			if tt.wantErr != nil {
+				require.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSignatures, signatures)
+		})
+	}
+}
+
 func TestGetBranch(t *testing.T) {
 	tests := []struct {
 		name        string
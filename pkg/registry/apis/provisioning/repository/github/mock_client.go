@@ -204,6 +204,68 @@ func (_c *MockClient_CompareCommits_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// CompareCommitSignatures provides a mock function with given fields: ctx, owner, repository, base, head
+func (_m *MockClient) CompareCommitSignatures(ctx context.Context, owner string, repository string, base string, head string) ([]CommitSignature, error) {
+	ret := _m.Called(ctx, owner, repository, base, head)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompareCommitSignatures")
+	}
+
+	var r0 []CommitSignature
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) ([]CommitSignature, error)); ok {
+		return rf(ctx, owner, repository, base, head)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) []CommitSignature); ok {
+		r0 = rf(ctx, owner, repository, base, head)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]CommitSignature)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, owner, repository, base, head)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockClient_CompareCommitSignatures_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompareCommitSignatures'
+type MockClient_CompareCommitSignatures_Call struct {
+	*mock.Call
+}
+
+// CompareCommitSignatures is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repository string
+//   - base string
+//   - head string
+func (_e *MockClient_Expecter) CompareCommitSignatures(ctx interface{}, owner interface{}, repository interface{}, base interface{}, head interface{}) *MockClient_CompareCommitSignatures_Call {
+	return &MockClient_CompareCommitSignatures_Call{Call: _e.mock.On("CompareCommitSignatures", ctx, owner, repository, base, head)}
+}
+
+func (_c *MockClient_CompareCommitSignatures_Call) Run(run func(ctx context.Context, owner string, repository string, base string, head string)) *MockClient_CompareCommitSignatures_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockClient_CompareCommitSignatures_Call) Return(_a0 []CommitSignature, _a1 error) *MockClient_CompareCommitSignatures_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockClient_CompareCommitSignatures_Call) RunAndReturn(run func(context.Context, string, string, string, string) ([]CommitSignature, error)) *MockClient_CompareCommitSignatures_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateBranch provides a mock function with given fields: ctx, owner, repository, sourceBranch, branchName
 func (_m *MockClient) CreateBranch(ctx context.Context, owner string, repository string, sourceBranch string, branchName string) error {
 	ret := _m.Called(ctx, owner, repository, sourceBranch, branchName)
@@ -624,6 +686,65 @@ func (_c *MockClient_GetBranch_Call) RunAndReturn(run func(context.Context, stri
 	return _c
 }
 
+// GetCommitSignature provides a mock function with given fields: ctx, owner, repository, sha
+func (_m *MockClient) GetCommitSignature(ctx context.Context, owner string, repository string, sha string) (CommitSignature, error) {
+	ret := _m.Called(ctx, owner, repository, sha)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommitSignature")
+	}
+
+	var r0 CommitSignature
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (CommitSignature, error)); ok {
+		return rf(ctx, owner, repository, sha)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) CommitSignature); ok {
+		r0 = rf(ctx, owner, repository, sha)
+	} else {
+		r0 = ret.Get(0).(CommitSignature)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, owner, repository, sha)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockClient_GetCommitSignature_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCommitSignature'
+type MockClient_GetCommitSignature_Call struct {
+	*mock.Call
+}
+
+// GetCommitSignature is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - repository string
+//   - sha string
+func (_e *MockClient_Expecter) GetCommitSignature(ctx interface{}, owner interface{}, repository interface{}, sha interface{}) *MockClient_GetCommitSignature_Call {
+	return &MockClient_GetCommitSignature_Call{Call: _e.mock.On("GetCommitSignature", ctx, owner, repository, sha)}
+}
+
+func (_c *MockClient_GetCommitSignature_Call) Run(run func(ctx context.Context, owner string, repository string, sha string)) *MockClient_GetCommitSignature_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockClient_GetCommitSignature_Call) Return(_a0 CommitSignature, _a1 error) *MockClient_GetCommitSignature_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockClient_GetCommitSignature_Call) RunAndReturn(run func(context.Context, string, string, string) (CommitSignature, error)) *MockClient_GetCommitSignature_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetContents provides a mock function with given fields: ctx, owner, repository, path, ref
 func (_m *MockClient) GetContents(ctx context.Context, owner string, repository string, path string, ref string) (RepositoryContent, []RepositoryContent, error) {
 	ret := _m.Called(ctx, owner, repository, path, ref)
@@ -2,22 +2,28 @@ package repository
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	field "k8s.io/apimachinery/pkg/util/validation/field"
 
 	provisioning "github.com/grafana/grafana/pkg/apis/provisioning/v0alpha1"
 	pgh "github.com/grafana/grafana/pkg/registry/apis/provisioning/repository/github"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/repository/signing"
 	"github.com/grafana/grafana/pkg/registry/apis/provisioning/secrets"
 )
 
@@ -426,6 +432,58 @@ func TestGitHubRepositoryValidate(t *testing.T) {
 			expectedErrors: 4,
 			errorFields:    []string{"spec.github.url", "spec.github.branch", "spec.github.token", "spec.github.prefix"},
 		},
+		{
+			name: "Invalid signing format",
+			config: &provisioning.Repository{
+				Spec: provisioning.RepositorySpec{
+					GitHub: &provisioning.GitHubRepositoryConfig{
+						URL:    "https://github.com/grafana/grafana",
+						Branch: "main",
+						Token:  "valid-token",
+						Signing: &provisioning.CommitSigningConfig{
+							Format: "x509",
+						},
+					},
+				},
+			},
+			expectedErrors: 1,
+			errorFields:    []string{"spec.github.signing.format"},
+		},
+		{
+			name: "Signature verification without trusted keys",
+			config: &provisioning.Repository{
+				Spec: provisioning.RepositorySpec{
+					GitHub: &provisioning.GitHubRepositoryConfig{
+						URL:    "https://github.com/grafana/grafana",
+						Branch: "main",
+						Token:  "valid-token",
+						Signing: &provisioning.CommitSigningConfig{
+							RequireVerified: true,
+						},
+					},
+				},
+			},
+			expectedErrors: 1,
+			errorFields:    []string{"spec.github.signing.trustedKeys"},
+		},
+		{
+			name: "Invalid trusted key",
+			config: &provisioning.Repository{
+				Spec: provisioning.RepositorySpec{
+					GitHub: &provisioning.GitHubRepositoryConfig{
+						URL:    "https://github.com/grafana/grafana",
+						Branch: "main",
+						Token:  "valid-token",
+						Signing: &provisioning.CommitSigningConfig{
+							RequireVerified: true,
+							TrustedKeys:     []string{"not a key"},
+						},
+					},
+				},
+			},
+			expectedErrors: 1,
+			errorFields:    []string{"spec.github.signing.trustedKeys"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -2517,6 +2575,84 @@ func TestGitHubRepository_LatestRef(t *testing.T) {
 	}
 }
 
+func TestGitHubRepository_VerifyCommits(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(priv, "grafana")
+	require.NoError(t, err)
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+
+	signer, err := signing.NewSigner(provisioning.SSHSigningFormat, pem.EncodeToMemory(block))
+	require.NoError(t, err)
+	payload := "tree abc\n\nsigned commit"
+	signature, err := signer.Sign(strings.NewReader(payload))
+	require.NoError(t, err)
+	signed := pgh.CommitSignature{SHA: "abc123", Payload: payload, Signature: string(signature)}
+
+	tests := []struct {
+		name          string
+		base          string
+		setupMock     func(m *pgh.MockClient)
+		expectedError string
+	}{
+		{
+			name: "only the head commit is verified without base",
+			setupMock: func(m *pgh.MockClient) {
+				m.EXPECT().GetCommitSignature(mock.Anything, "grafana", "grafana", "head").Return(signed, nil)
+			},
+		},
+		{
+			name: "every commit since base is verified",
+			base: "base",
+			setupMock: func(m *pgh.MockClient) {
+				m.EXPECT().CompareCommitSignatures(mock.Anything, "grafana", "grafana", "base", "head").
+					Return([]pgh.CommitSignature{{SHA: "def456"}, signed}, nil)
+			},
+			expectedError: "commit def456: commit is not signed",
+		},
+		{
+			name: "signed commits since base",
+			base: "base",
+			setupMock: func(m *pgh.MockClient) {
+				m.EXPECT().CompareCommitSignatures(mock.Anything, "grafana", "grafana", "base", "head").
+					Return([]pgh.CommitSignature{signed, signed}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGH := pgh.NewMockClient(t)
+			tt.setupMock(mockGH)
+
+			repo := &githubRepository{
+				gh: mockGH,
+				config: &provisioning.Repository{
+					Spec: provisioning.RepositorySpec{
+						GitHub: &provisioning.GitHubRepositoryConfig{
+							Branch: "main",
+							Signing: &provisioning.CommitSigningConfig{
+								RequireVerified: true,
+								TrustedKeys:     []string{string(ssh.MarshalAuthorizedKey(sshPub))},
+							},
+						},
+					},
+				},
+				owner: "grafana",
+				repo:  "grafana",
+			}
+
+			err := repo.VerifyCommits(context.Background(), tt.base, "head")
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestGitHubRepository_CompareFiles(t *testing.T) {
 	tests := []struct {
 		name            string
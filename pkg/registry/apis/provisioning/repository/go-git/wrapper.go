@@ -24,6 +24,7 @@ import (
 
 	provisioning "github.com/grafana/grafana/pkg/apis/provisioning/v0alpha1"
 	"github.com/grafana/grafana/pkg/registry/apis/provisioning/repository"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/repository/signing"
 	"github.com/grafana/grafana/pkg/registry/apis/provisioning/safepath"
 	"github.com/grafana/grafana/pkg/registry/apis/provisioning/secrets"
 	"github.com/grafana/grafana/pkg/util/httpclient"
@@ -72,6 +73,7 @@ type GoGitRepo struct {
 	config            *provisioning.Repository
 	decryptedPassword string
 	opts              repository.CloneOptions
+	signer            git.Signer // nil when commits should not be signed
 
 	repo Repository
 	tree Worktree
@@ -118,6 +120,11 @@ func Clone(
 		return nil, fmt.Errorf("error decrypting token: %w", err)
 	}
 
+	signer, err := newSigner(ctx, config, secrets)
+	if err != nil {
+		return nil, fmt.Errorf("create commit signer: %w", err)
+	}
+
 	if err := os.MkdirAll(root, 0700); err != nil {
 		return nil, fmt.Errorf("create root dir: %w", err)
 	}
@@ -146,11 +153,32 @@ func Clone(
 		tree:              &worktree{Worktree: tree},
 		opts:              opts,
 		decryptedPassword: string(decrypted),
+		signer:            signer,
 		repo:              repo,
 		dir:               dir,
 	}, nil
 }
 
+// newSigner returns the signer for commits, or nil if the repository has no signing key.
+func newSigner(ctx context.Context, config *provisioning.Repository, secrets secrets.Service) (git.Signer, error) {
+	cfg := config.Spec.GitHub.Signing
+	if cfg == nil || len(cfg.EncryptedKey) == 0 {
+		return nil, nil
+	}
+
+	key, err := secrets.Decrypt(ctx, cfg.EncryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting signing key: %w", err)
+	}
+
+	signer, err := signing.NewSigner(cfg.Format, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return signer, nil
+}
+
 func clone(ctx context.Context, config *provisioning.Repository, opts repository.CloneOptions, decrypted []byte, dir string, progress io.Writer) (*git.Repository, *git.Worktree, error) {
 	gitcfg := config.Spec.GitHub
 	url := gitcfg.URL
@@ -236,7 +264,8 @@ func (g *GoGitRepo) Push(ctx context.Context, opts repository.PushOptions) error
 
 	if !g.opts.PushOnWrites {
 		_, err := g.tree.Commit("exported from grafana", &git.CommitOptions{
-			All: true, // Add everything that changed
+			All:    true, // Add everything that changed
+			Signer: g.signer,
 		})
 		if err != nil {
 			// empty commit is fine -- no change
@@ -376,6 +405,7 @@ func (g *GoGitRepo) maybeCommit(ctx context.Context, message string) error {
 		Author: &object.Signature{
 			Name: "grafana",
 		},
+		Signer: g.signer,
 	}
 	sig := repository.GetAuthorSignature(ctx)
 	if sig != nil && sig.Name != "" {
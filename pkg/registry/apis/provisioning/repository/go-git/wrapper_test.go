@@ -633,6 +633,82 @@ func TestGoGitRepo_Write(t *testing.T) {
 	}
 }
 
+type fakeSigner struct{}
+
+func (f *fakeSigner) Sign(message io.Reader) ([]byte, error) {
+	return []byte("signature"), nil
+}
+
+func TestGoGitRepo_WriteSigned(t *testing.T) {
+	signer := &fakeSigner{}
+	mockTree := NewMockWorktree(t)
+	mockTree.On("Filesystem").Return(memfs.New())
+	mockTree.On("Add", "test.txt").Return(plumbing.NewHash("abc123"), nil)
+	mockTree.On("Commit", "test write", mock.MatchedBy(func(opts *git.CommitOptions) bool {
+		return opts.Signer == signer
+	})).Return(plumbing.NewHash("def456"), nil)
+
+	repo := &GoGitRepo{
+		config: &v0alpha1.Repository{
+			Spec: v0alpha1.RepositorySpec{
+				GitHub: &v0alpha1.GitHubRepositoryConfig{},
+			},
+		},
+		tree:   mockTree,
+		signer: signer,
+		opts: repository.CloneOptions{
+			PushOnWrites: true,
+		},
+	}
+
+	require.NoError(t, repo.Write(context.Background(), "test.txt", "", []byte("test content"), "test write"))
+}
+
+func TestNewSigner(t *testing.T) {
+	t.Run("no signing config", func(t *testing.T) {
+		signer, err := newSigner(context.Background(), &v0alpha1.Repository{
+			Spec: v0alpha1.RepositorySpec{
+				GitHub: &v0alpha1.GitHubRepositoryConfig{},
+			},
+		}, secrets.NewMockService(t))
+		require.NoError(t, err)
+		require.Nil(t, signer)
+	})
+
+	t.Run("decrypt error", func(t *testing.T) {
+		mockSecrets := secrets.NewMockService(t)
+		mockSecrets.On("Decrypt", mock.Anything, []byte("encrypted")).Return(nil, errors.New("decrypt failed"))
+
+		_, err := newSigner(context.Background(), &v0alpha1.Repository{
+			Spec: v0alpha1.RepositorySpec{
+				GitHub: &v0alpha1.GitHubRepositoryConfig{
+					Signing: &v0alpha1.CommitSigningConfig{
+						EncryptedKey: []byte("encrypted"),
+					},
+				},
+			},
+		}, mockSecrets)
+		require.EqualError(t, err, "error decrypting signing key: decrypt failed")
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		mockSecrets := secrets.NewMockService(t)
+		mockSecrets.On("Decrypt", mock.Anything, []byte("encrypted")).Return([]byte("not a key"), nil)
+
+		_, err := newSigner(context.Background(), &v0alpha1.Repository{
+			Spec: v0alpha1.RepositorySpec{
+				GitHub: &v0alpha1.GitHubRepositoryConfig{
+					Signing: &v0alpha1.CommitSigningConfig{
+						Format:       v0alpha1.SSHSigningFormat,
+						EncryptedKey: []byte("encrypted"),
+					},
+				},
+			},
+		}, mockSecrets)
+		require.ErrorContains(t, err, "read ssh key")
+	})
+}
+
 func TestGoGitRepo_Test(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -198,3 +198,12 @@ type Versioned interface {
 	LatestRef(ctx context.Context) (string, error)
 	CompareFiles(ctx context.Context, base, ref string) ([]VersionedFileChange, error)
 }
+
+// SignatureVerifier is a repository that can verify commit signatures against the configured trusted keys.
+//
+//go:generate mockery --name SignatureVerifier --structname MockSignatureVerifier --inpackage --filename signature_verifier_mock.go --with-expecter
+type SignatureVerifier interface {
+	// VerifyCommits returns an error if any commit reachable from ref but not from base is unsigned,
+	// or if its signature was not made by a trusted key. When base is empty, only ref is verified.
+	VerifyCommits(ctx context.Context, base, ref string) error
+}
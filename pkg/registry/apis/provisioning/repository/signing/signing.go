@@ -0,0 +1,172 @@
+// The signing package signs commits written by Grafana and verifies the signatures of commits read from a repository.
+// Both OpenPGP (gpg) and SSH signatures are supported, using the same armored formats that git itself produces.
+package signing
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+	"golang.org/x/crypto/ssh"
+
+	provisioning "github.com/grafana/grafana/pkg/apis/provisioning/v0alpha1"
+)
+
+var (
+	ErrNoSignature      = errors.New("commit is not signed")
+	ErrUntrustedSigner  = errors.New("signature was not made by a trusted key")
+	ErrInvalidSignature = errors.New("signature does not match the signed payload")
+)
+
+// Signer signs a commit payload and returns the armored signature.
+// The interface matches the go-git Signer, so it can be set on git.CommitOptions directly.
+type Signer interface {
+	Sign(message io.Reader) ([]byte, error)
+}
+
+// NewSigner parses a private key in the given format.
+// An empty format is treated as gpg.
+func NewSigner(format provisioning.SigningFormat, key []byte) (Signer, error) {
+	switch format {
+	case provisioning.GPGSigningFormat, "":
+		return newGPGSigner(key)
+	case provisioning.SSHSigningFormat:
+		return newSSHSigner(key)
+	default:
+		return nil, fmt.Errorf("unsupported signing format: %s", format)
+	}
+}
+
+type gpgSigner struct {
+	entity *openpgp.Entity
+}
+
+func newGPGSigner(key []byte) (*gpgSigner, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(key))
+	if err != nil {
+		return nil, fmt.Errorf("read gpg key: %w", err)
+	}
+
+	for _, entity := range entities {
+		if entity.PrivateKey == nil {
+			continue
+		}
+		if entity.PrivateKey.Encrypted {
+			return nil, errors.New("gpg key is protected by a passphrase")
+		}
+		return &gpgSigner{entity: entity}, nil
+	}
+
+	return nil, errors.New("gpg key does not contain a private key")
+}
+
+func (s *gpgSigner) Sign(message io.Reader) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&buf, s.entity, message, nil); err != nil {
+		return nil, fmt.Errorf("sign with gpg key: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+type sshSigner struct {
+	signer ssh.Signer
+}
+
+func newSSHSigner(key []byte) (*sshSigner, error) {
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		var missing *ssh.PassphraseMissingError
+		if errors.As(err, &missing) {
+			return nil, errors.New("ssh key is protected by a passphrase")
+		}
+		return nil, fmt.Errorf("read ssh key: %w", err)
+	}
+	return &sshSigner{signer: signer}, nil
+}
+
+func (s *sshSigner) Sign(message io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(message)
+	if err != nil {
+		return nil, fmt.Errorf("read message: %w", err)
+	}
+	return sshSign(s.signer, data)
+}
+
+// Verifier checks a commit signature against a set of trusted public keys.
+type Verifier interface {
+	// Verify returns nil when the signature is valid for the payload and was made by a trusted key.
+	Verify(payload, signature []byte) error
+}
+
+type verifier struct {
+	gpgKeys openpgp.EntityList
+	sshKeys []ssh.PublicKey
+}
+
+// NewVerifier parses the trusted keys.
+// Each key is either an armored OpenPGP public key block or an SSH public key in authorized_keys format.
+func NewVerifier(trustedKeys []string) (Verifier, error) {
+	v := &verifier{}
+	for i, key := range trustedKeys {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+
+		if strings.HasPrefix(key, "-----BEGIN PGP") {
+			entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key))
+			if err != nil {
+				return nil, fmt.Errorf("read trusted key %d: %w", i, err)
+			}
+			v.gpgKeys = append(v.gpgKeys, entities...)
+			continue
+		}
+
+		pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key))
+		if err != nil {
+			return nil, fmt.Errorf("read trusted key %d: %w", i, err)
+		}
+		v.sshKeys = append(v.sshKeys, pub)
+	}
+
+	if len(v.gpgKeys) == 0 && len(v.sshKeys) == 0 {
+		return nil, errors.New("no trusted keys configured")
+	}
+
+	return v, nil
+}
+
+func (v *verifier) Verify(payload, signature []byte) error {
+	sig := bytes.TrimSpace(signature)
+	switch {
+	case len(sig) == 0:
+		return ErrNoSignature
+	case bytes.HasPrefix(sig, []byte(sshSignatureHeader)):
+		return sshVerify(v.sshKeys, payload, sig)
+	case bytes.HasPrefix(sig, []byte("-----BEGIN PGP SIGNATURE-----")):
+		return v.verifyGPG(payload, sig)
+	default:
+		return fmt.Errorf("unsupported signature format")
+	}
+}
+
+func (v *verifier) verifyGPG(payload, signature []byte) error {
+	if len(v.gpgKeys) == 0 {
+		return ErrUntrustedSigner
+	}
+
+	_, err := openpgp.CheckArmoredDetachedSignature(v.gpgKeys, bytes.NewReader(payload), bytes.NewReader(signature), nil)
+	if err == nil {
+		return nil
+	}
+
+	// The signature was made by a key that is not in the keyring
+	if errors.Is(err, pgperrors.ErrUnknownIssuer) {
+		return ErrUntrustedSigner
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidSignature, err.Error())
+}
@@ -0,0 +1,160 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	provisioning "github.com/grafana/grafana/pkg/apis/provisioning/v0alpha1"
+)
+
+const testPayload = "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\nauthor grafana <> 1700000000 +0000\ncommitter grafana <> 1700000000 +0000\n\nexported from grafana\n"
+
+func newGPGKeyPair(t *testing.T) (private string, public string) {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity("grafana", "", "grafana@example.com", nil)
+	require.NoError(t, err)
+
+	var priv bytes.Buffer
+	w, err := armor.Encode(&priv, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.SerializePrivate(w, nil))
+	require.NoError(t, w.Close())
+
+	var pub bytes.Buffer
+	w, err = armor.Encode(&pub, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+
+	return priv.String(), pub.String()
+}
+
+func newSSHKeyPair(t *testing.T) (private string, public string) {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	block, err := ssh.MarshalPrivateKey(priv, "grafana")
+	require.NoError(t, err)
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(block)), string(ssh.MarshalAuthorizedKey(sshPub))
+}
+
+func TestSignAndVerify(t *testing.T) {
+	gpgPrivate, gpgPublic := newGPGKeyPair(t)
+	sshPrivate, sshPublic := newSSHKeyPair(t)
+
+	tests := []struct {
+		name    string
+		format  provisioning.SigningFormat
+		private string
+		public  string
+		header  string
+	}{
+		{
+			name:    "gpg",
+			format:  provisioning.GPGSigningFormat,
+			private: gpgPrivate,
+			public:  gpgPublic,
+			header:  "-----BEGIN PGP SIGNATURE-----",
+		},
+		{
+			name:    "default format is gpg",
+			format:  "",
+			private: gpgPrivate,
+			public:  gpgPublic,
+			header:  "-----BEGIN PGP SIGNATURE-----",
+		},
+		{
+			name:    "ssh",
+			format:  provisioning.SSHSigningFormat,
+			private: sshPrivate,
+			public:  sshPublic,
+			header:  sshSignatureHeader,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, err := NewSigner(tt.format, []byte(tt.private))
+			require.NoError(t, err)
+
+			sig, err := signer.Sign(strings.NewReader(testPayload))
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(string(sig), tt.header))
+
+			verifier, err := NewVerifier([]string{tt.public})
+			require.NoError(t, err)
+			require.NoError(t, verifier.Verify([]byte(testPayload), sig))
+
+			err = verifier.Verify([]byte(testPayload+"tampered"), sig)
+			require.ErrorIs(t, err, ErrInvalidSignature)
+		})
+	}
+}
+
+func TestVerifyUntrustedSigner(t *testing.T) {
+	gpgPrivate, _ := newGPGKeyPair(t)
+	_, otherGPGPublic := newGPGKeyPair(t)
+	sshPrivate, _ := newSSHKeyPair(t)
+	_, otherSSHPublic := newSSHKeyPair(t)
+
+	verifier, err := NewVerifier([]string{otherGPGPublic, otherSSHPublic})
+	require.NoError(t, err)
+
+	gpgSigner, err := NewSigner(provisioning.GPGSigningFormat, []byte(gpgPrivate))
+	require.NoError(t, err)
+	sig, err := gpgSigner.Sign(strings.NewReader(testPayload))
+	require.NoError(t, err)
+	require.ErrorIs(t, verifier.Verify([]byte(testPayload), sig), ErrUntrustedSigner)
+
+	sshSigner, err := NewSigner(provisioning.SSHSigningFormat, []byte(sshPrivate))
+	require.NoError(t, err)
+	sig, err = sshSigner.Sign(strings.NewReader(testPayload))
+	require.NoError(t, err)
+	require.ErrorIs(t, verifier.Verify([]byte(testPayload), sig), ErrUntrustedSigner)
+}
+
+func TestVerifyUnsigned(t *testing.T) {
+	_, sshPublic := newSSHKeyPair(t)
+	verifier, err := NewVerifier([]string{sshPublic})
+	require.NoError(t, err)
+
+	require.ErrorIs(t, verifier.Verify([]byte(testPayload), nil), ErrNoSignature)
+	require.Error(t, verifier.Verify([]byte(testPayload), []byte("not a signature")))
+}
+
+func TestNewVerifier(t *testing.T) {
+	_, err := NewVerifier(nil)
+	require.EqualError(t, err, "no trusted keys configured")
+
+	_, err = NewVerifier([]string{"ssh-ed25519 not-base64"})
+	require.ErrorContains(t, err, "read trusted key 0")
+}
+
+func TestNewSigner(t *testing.T) {
+	_, gpgPublic := newGPGKeyPair(t)
+
+	_, err := NewSigner(provisioning.GPGSigningFormat, []byte(gpgPublic))
+	require.EqualError(t, err, "gpg key does not contain a private key")
+
+	_, err = NewSigner(provisioning.SSHSigningFormat, []byte("invalid"))
+	require.ErrorContains(t, err, "read ssh key")
+
+	_, err = NewSigner("x509", nil)
+	require.EqualError(t, err, "unsupported signing format: x509")
+}
@@ -33,7 +33,13 @@ func IsPathSupported(filePath string) error {
 
 	// Only check file extension if it's not a folder path
 	if !safepath.IsDir(filePath) {
-		if ext := path.Ext(filePath); ext != ".yml" && ext != ".yaml" && ext != ".json" {
+		switch path.Ext(filePath) {
+		case ".yml", ".yaml", ".json":
+		case jsonnetExt, cueExt:
+			if IsLibraryPath(filePath) {
+				return ErrLibraryFile
+			}
+		default:
 			return ErrUnsupportedFileExtension
 		}
 	}
@@ -25,6 +25,24 @@ func TestIsPathSupported(t *testing.T) {
 			name: "valid json file",
 			path: "dashboards/my-dashboard.json",
 		},
+		{
+			name: "valid jsonnet file",
+			path: "dashboards/my-dashboard.jsonnet",
+		},
+		{
+			name: "valid cue file",
+			path: "dashboards/my-dashboard.cue",
+		},
+		{
+			name:        "jsonnet library is not a resource",
+			path:        "dashboards/lib/panels.libsonnet",
+			expectedErr: ErrUnsupportedFileExtension,
+		},
+		{
+			name:        "vendored jsonnet is a library",
+			path:        "vendor/grafonnet/main.jsonnet",
+			expectedErr: ErrLibraryFile,
+		},
 		{
 			name: "valid nested path",
 			path: "dashboards/folder1/folder2/my-dashboard.yaml",
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path"
+	"sync"
 
 	"gopkg.in/yaml.v3"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -63,6 +64,7 @@ func (f *parserFactory) GetParser(ctx context.Context, repo repository.Reader) (
 			Namespace: config.Namespace,
 			Name:      config.Name,
 		},
+		reader:  repo,
 		urls:    urls,
 		clients: clients,
 	}, nil
@@ -72,6 +74,13 @@ type parser struct {
 	// The target repository
 	repo provisioning.ResourceRepositoryInfo
 
+	// used to resolve imports when rendering files
+	reader repository.Reader
+
+	// digests of the library files by ref, for the checksum of rendered files
+	librariesMu      sync.Mutex
+	librariesDigests map[string]string
+
 	// for repositories that have URL support
 	urls repository.RepositoryWithURLs
 
@@ -132,11 +141,31 @@ func (r *parser) Parse(ctx context.Context, info *repository.FileInfo) (parsed *
 		return nil, err
 	}
 
+	// Rendered files are decoded from the evaluated output, the original file info is kept as is
+	source := info
+	checksum := info.Hash
+	if IsRenderedPath(info.Path) {
+		data, err := RenderFile(ctx, r.reader, info)
+		if err != nil {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("unable to render %s: %s", info.Path, err.Error()))
+		}
+		rendered := *info
+		rendered.Data = data
+		source = &rendered
+	}
+	if SupportsImports(info.Path) {
+		digest, err := r.librariesDigest(ctx, info.Ref)
+		if err != nil {
+			return nil, fmt.Errorf("read libraries: %w", err)
+		}
+		checksum = RenderedChecksum(info.Hash, digest)
+	}
+
 	var gvk *schema.GroupVersionKind
-	parsed.Obj, gvk, err = DecodeYAMLObject(bytes.NewBuffer(info.Data))
+	parsed.Obj, gvk, err = DecodeYAMLObject(bytes.NewBuffer(source.Data))
 	if err != nil || gvk == nil {
 		logger.Debug("failed to find GVK of the input data, trying fallback loader", "error", err)
-		parsed.Obj, gvk, parsed.Classic, err = ReadClassicResource(ctx, info)
+		parsed.Obj, gvk, parsed.Classic, err = ReadClassicResource(ctx, source)
 		if err != nil || gvk == nil {
 			return nil, apierrors.NewBadRequest("unable to read file as a resource")
 		}
@@ -176,7 +205,7 @@ func (r *parser) Parse(ctx context.Context, info *repository.FileInfo) (parsed *
 	})
 	parsed.Meta.SetSourceProperties(utils.SourceProperties{
 		Path:     info.Path, // joinPathWithRef(info.Path, info.Ref),
-		Checksum: info.Hash,
+		Checksum: checksum,
 	})
 
 	if obj.GetName() == "" {
@@ -211,6 +240,28 @@ func (r *parser) Parse(ctx context.Context, info *repository.FileInfo) (parsed *
 	return parsed, nil
 }
 
+// librariesDigest returns the digest of the library files at the ref, the tree is read once per ref.
+func (r *parser) librariesDigest(ctx context.Context, ref string) (string, error) {
+	r.librariesMu.Lock()
+	defer r.librariesMu.Unlock()
+
+	if digest, ok := r.librariesDigests[ref]; ok {
+		return digest, nil
+	}
+
+	tree, err := r.reader.ReadTree(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+
+	if r.librariesDigests == nil {
+		r.librariesDigests = make(map[string]string)
+	}
+	digest := LibrariesDigest(tree)
+	r.librariesDigests[ref] = digest
+	return digest, nil
+}
+
 func (f *ParsedResource) DryRun(ctx context.Context) error {
 	if f.DryRunResponse != nil {
 		return nil // this already ran (and helpful for testing)
@@ -312,6 +363,10 @@ func (f *ParsedResource) ToSaveBytes() ([]byte, error) {
 	case ".yaml", ".yml":
 		return yaml.Marshal(obj)
 
+	// The source can not be recreated from the rendered value
+	case jsonnetExt, cueExt:
+		return nil, ErrRenderedFileReadOnly
+
 	default:
 		return nil, fmt.Errorf("unexpected format")
 	}
@@ -0,0 +1,282 @@
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/google/go-jsonnet"
+
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/repository"
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/safepath"
+)
+
+// Rendered files are evaluated into JSON before they are parsed as a resource.
+// Library files can only be imported by a rendered file, they are never a resource on their own.
+const (
+	jsonnetExt   = ".jsonnet"
+	libsonnetExt = ".libsonnet"
+	cueExt       = ".cue"
+)
+
+const (
+	// Jsonnet libraries that can not be found relative to the importing file
+	// are looked up in this folder, which is where jsonnet-bundler installs them.
+	jsonnetVendorDir = "vendor/"
+
+	maxRenderImports    = 256
+	maxRenderStack      = 500
+	maxRenderFileSize   = 5 * 1024 * 1024
+	maxRenderOutputSize = 10 * 1024 * 1024
+
+	// The evaluation is stopped at the deadline of the context, or after this duration.
+	maxRenderDuration = 30 * time.Second
+	// Neither go-jsonnet nor CUE can be interrupted: an evaluation that runs past its
+	// deadline keeps its slot until it ends, so runaway files can not pile up.
+	maxConcurrentRenders = 4
+)
+
+var (
+	ErrRenderedFileReadOnly = errors.New("rendered files can not be written")
+	ErrLibraryFile          = errors.New("library files can only be imported")
+	ErrTooManyImports       = errors.New("too many imports")
+	ErrImportTooLarge       = errors.New("imported file is too large")
+	ErrRenderTooLarge       = errors.New("rendered file is too large")
+	ErrRenderTimeout        = errors.New("rendering took too long")
+)
+
+var renderSlots = make(chan struct{}, maxConcurrentRenders)
+
+// IsRenderedPath returns true when the file must be evaluated before it can be parsed as a resource.
+func IsRenderedPath(filePath string) bool {
+	switch path.Ext(filePath) {
+	case jsonnetExt, cueExt:
+		return !IsLibraryPath(filePath)
+	default:
+		return false
+	}
+}
+
+// IsLibraryPath returns true for files that can be imported by rendered files.
+// Jsonnet files installed in the vendor folder are libraries too.
+func IsLibraryPath(filePath string) bool {
+	switch path.Ext(filePath) {
+	case libsonnetExt:
+		return true
+	case jsonnetExt:
+		return IsVendorPath(filePath)
+	default:
+		return false
+	}
+}
+
+// IsVendorPath returns true for paths in the folder where jsonnet-bundler installs libraries.
+func IsVendorPath(filePath string) bool {
+	return strings.HasPrefix(filePath, jsonnetVendorDir)
+}
+
+// SupportsImports returns true for rendered files which can import other files from the repository.
+// Changes to an imported file are not visible in the hash of the file itself, see RenderedChecksum.
+func SupportsImports(filePath string) bool {
+	return path.Ext(filePath) == jsonnetExt && !IsLibraryPath(filePath)
+}
+
+// LibrariesDigest returns a digest of the library files in the tree, or an empty string if there are none.
+func LibrariesDigest(tree []repository.FileTreeEntry) string {
+	var libraries []string
+	for _, entry := range tree {
+		if entry.Blob && IsLibraryPath(entry.Path) {
+			libraries = append(libraries, entry.Path+":"+entry.Hash)
+		}
+	}
+	if len(libraries) == 0 {
+		return ""
+	}
+
+	sort.Strings(libraries)
+	sum := sha256.Sum256([]byte(strings.Join(libraries, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// RenderedChecksum returns the checksum saved for a file which supports imports.
+// Imports are not tracked, so the checksum changes when the file or any library changes.
+func RenderedChecksum(fileHash, librariesDigest string) string {
+	if librariesDigest == "" {
+		return fileHash
+	}
+	return fileHash + "+" + librariesDigest
+}
+
+// RenderFile evaluates a Jsonnet or CUE file and returns the resulting JSON.
+// The evaluation is sandboxed: there are no native functions, external variables or filesystem access,
+// it must end before the deadline of the context, and the file and its output are limited in size.
+// Jsonnet imports are resolved relative to the importing file, within the same repository and ref.
+func RenderFile(ctx context.Context, reader repository.Reader, info *repository.FileInfo) ([]byte, error) {
+	if !IsRenderedPath(info.Path) {
+		return nil, fmt.Errorf("file can not be rendered: %s", info.Path)
+	}
+	if len(info.Data) > maxRenderFileSize {
+		return nil, fmt.Errorf("%w: %s", ErrRenderTooLarge, info.Path)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, maxRenderDuration)
+	defer cancel()
+
+	return evaluate(ctx, func() ([]byte, error) {
+		if path.Ext(info.Path) == cueExt {
+			return renderCUE(ctx, info)
+		}
+		return renderJsonnet(ctx, reader, info)
+	})
+}
+
+// evaluate runs the evaluation until the context is done, and checks the size of its output.
+func evaluate(ctx context.Context, fn func() ([]byte, error)) ([]byte, error) {
+	select {
+	case renderSlots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: %w", ErrRenderTimeout, ctx.Err())
+	}
+
+	type result struct {
+		out []byte
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() { <-renderSlots }()
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{err: fmt.Errorf("evaluation failed: %v", r)}
+			}
+		}()
+
+		out, err := fn()
+		done <- result{out: out, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return nil, r.err
+		}
+		if len(r.out) > maxRenderOutputSize {
+			return nil, ErrRenderTooLarge
+		}
+		return r.out, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: %w", ErrRenderTimeout, ctx.Err())
+	}
+}
+
+func renderJsonnet(ctx context.Context, reader repository.Reader, info *repository.FileInfo) ([]byte, error) {
+	vm := jsonnet.MakeVM()
+	vm.MaxStack = maxRenderStack
+	vm.SetTraceOut(io.Discard)
+	vm.Importer(&repositoryImporter{
+		ctx:    ctx,
+		reader: reader,
+		ref:    info.Ref,
+		cache: map[string]jsonnet.Contents{
+			// The file is evaluated through the importer, so relative imports resolve from its folder
+			info.Path: jsonnet.MakeContents(string(info.Data)),
+		},
+	})
+
+	out, err := vm.EvaluateFile(info.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(out), nil
+}
+
+func renderCUE(ctx context.Context, info *repository.FileInfo) ([]byte, error) {
+	cuectx := cuecontext.New()
+	value := cuectx.CompileBytes(info.Data, cue.Filename(info.Path))
+	if err := value.Err(); err != nil {
+		return nil, err
+	}
+
+	// CUE can not be interrupted, stop between the steps when the deadline has passed
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := value.Validate(cue.Concrete(true)); err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return value.MarshalJSON()
+}
+
+// repositoryImporter resolves Jsonnet imports from the repository being rendered
+type repositoryImporter struct {
+	ctx    context.Context
+	reader repository.Reader
+	ref    string
+
+	// go-jsonnet requires the same contents for every import of a path
+	cache map[string]jsonnet.Contents
+}
+
+func (i *repositoryImporter) Import(importedFrom, importedPath string) (jsonnet.Contents, string, error) {
+	// Imports are the only point where the evaluation can be stopped
+	if err := i.ctx.Err(); err != nil {
+		return jsonnet.Contents{}, "", err
+	}
+	if importedPath == "" || safepath.IsAbs(importedPath) {
+		return jsonnet.Contents{}, "", fmt.Errorf("import path must be relative to the repository: %q", importedPath)
+	}
+
+	candidates := []string{
+		path.Join(path.Dir(importedFrom), importedPath),
+		path.Join(jsonnetVendorDir, importedPath),
+	}
+
+	for _, foundAt := range candidates {
+		// The path can not leave the repository
+		if foundAt == ".." || strings.HasPrefix(foundAt, "../") {
+			return jsonnet.Contents{}, "", fmt.Errorf("import path is outside the repository: %q", importedPath)
+		}
+		if err := safepath.IsSafe(foundAt); err != nil {
+			return jsonnet.Contents{}, "", fmt.Errorf("invalid import path %q: %w", importedPath, err)
+		}
+
+		if contents, ok := i.cache[foundAt]; ok {
+			return contents, foundAt, nil
+		}
+
+		if len(i.cache) >= maxRenderImports {
+			return jsonnet.Contents{}, "", ErrTooManyImports
+		}
+
+		info, err := i.reader.Read(i.ctx, foundAt, i.ref)
+		if errors.Is(err, repository.ErrFileNotFound) {
+			continue
+		}
+		if err != nil {
+			return jsonnet.Contents{}, "", fmt.Errorf("read import %q: %w", foundAt, err)
+		}
+		if len(info.Data) > maxRenderFileSize {
+			return jsonnet.Contents{}, "", fmt.Errorf("%w: %s", ErrImportTooLarge, foundAt)
+		}
+
+		contents := jsonnet.MakeContents(string(info.Data))
+		i.cache[foundAt] = contents
+		return contents, foundAt, nil
+	}
+
+	return jsonnet.Contents{}, "", fmt.Errorf("import not found: %q", importedPath)
+}
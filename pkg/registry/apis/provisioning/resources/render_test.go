@@ -0,0 +1,209 @@
+package resources
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/registry/apis/provisioning/repository"
+)
+
+func TestRenderFile(t *testing.T) {
+	t.Run("jsonnet with relative and vendored imports", func(t *testing.T) {
+		reader := repository.NewMockReader(t)
+		reader.On("Read", mock.Anything, "dashboards/lib/panels.libsonnet", "main").
+			Return(&repository.FileInfo{Data: []byte(`{ panel(title):: { title: title, type: "timeseries" } }`)}, nil).Once()
+		reader.On("Read", mock.Anything, "dashboards/grafonnet/main.libsonnet", "main").
+			Return(nil, repository.ErrFileNotFound).Once()
+		reader.On("Read", mock.Anything, "vendor/grafonnet/main.libsonnet", "main").
+			Return(&repository.FileInfo{Data: []byte(`{ dashboard(title):: { title: title } }`)}, nil).Once()
+
+		out, err := RenderFile(context.Background(), reader, &repository.FileInfo{
+			Path: "dashboards/test.jsonnet",
+			Ref:  "main",
+			Data: []byte(`
+local panels = import 'lib/panels.libsonnet';
+local g = import 'grafonnet/main.libsonnet';
+local again = import 'lib/panels.libsonnet';
+{
+  apiVersion: 'dashboard.grafana.app/v0alpha1',
+  kind: 'Dashboard',
+  metadata: { name: 'test' },
+  spec: g.dashboard('Test') + { panels: [panels.panel('A'), again.panel('B')] },
+}`),
+		})
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"apiVersion": "dashboard.grafana.app/v0alpha1",
+			"kind": "Dashboard",
+			"metadata": {"name": "test"},
+			"spec": {
+				"title": "Test",
+				"panels": [{"title": "A", "type": "timeseries"}, {"title": "B", "type": "timeseries"}]
+			}
+		}`, string(out))
+	})
+
+	t.Run("jsonnet imports can not leave the repository", func(t *testing.T) {
+		reader := repository.NewMockReader(t)
+		_, err := RenderFile(context.Background(), reader, &repository.FileInfo{
+			Path: "dashboards/test.jsonnet",
+			Data: []byte(`import '../../secrets.libsonnet'`),
+		})
+		require.ErrorContains(t, err, "import path is outside the repository")
+
+		_, err = RenderFile(context.Background(), reader, &repository.FileInfo{
+			Path: "dashboards/test.jsonnet",
+			Data: []byte(`import '/etc/passwd'`),
+		})
+		require.ErrorContains(t, err, "import path must be relative to the repository")
+	})
+
+	t.Run("jsonnet has no access to the environment", func(t *testing.T) {
+		reader := repository.NewMockReader(t)
+		_, err := RenderFile(context.Background(), reader, &repository.FileInfo{
+			Path: "test.jsonnet",
+			Data: []byte(`std.extVar('HOME')`),
+		})
+		require.ErrorContains(t, err, "Undefined external variable: HOME")
+
+		_, err = RenderFile(context.Background(), reader, &repository.FileInfo{
+			Path: "test.jsonnet",
+			Data: []byte(`std.native('exec')('id')`),
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("jsonnet evaluation error", func(t *testing.T) {
+		_, err := RenderFile(context.Background(), repository.NewMockReader(t), &repository.FileInfo{
+			Path: "test.jsonnet",
+			Data: []byte(`{ a: error 'broken dashboard' }`),
+		})
+		require.ErrorContains(t, err, "broken dashboard")
+	})
+
+	t.Run("cue", func(t *testing.T) {
+		out, err := RenderFile(context.Background(), repository.NewMockReader(t), &repository.FileInfo{
+			Path: "test.cue",
+			Data: []byte(`
+#Panel: { title: string, type: *"timeseries" | string }
+apiVersion: "dashboard.grafana.app/v0alpha1"
+kind:       "Dashboard"
+metadata: name: "test"
+spec: {
+	title: "Test"
+	panels: [#Panel & { title: "A" }]
+}`),
+		})
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"apiVersion": "dashboard.grafana.app/v0alpha1",
+			"kind": "Dashboard",
+			"metadata": {"name": "test"},
+			"spec": {"title": "Test", "panels": [{"title": "A", "type": "timeseries"}]}
+		}`, string(out))
+	})
+
+	t.Run("evaluation stops at the deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := RenderFile(ctx, repository.NewMockReader(t), &repository.FileInfo{
+			Path: "test.jsonnet",
+			Data: []byte(`local f(n) = if n == 0 then 1 else f(n - 1) + f(n - 1); f(18)`),
+		})
+		require.ErrorIs(t, err, ErrRenderTimeout)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		ctx, cancel = context.WithCancel(context.Background())
+		cancel()
+		_, err = RenderFile(ctx, repository.NewMockReader(t), &repository.FileInfo{
+			Path: "test.cue",
+			Data: []byte(`spec: title: "A"`),
+		})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("file and output sizes are limited", func(t *testing.T) {
+		_, err := RenderFile(context.Background(), repository.NewMockReader(t), &repository.FileInfo{
+			Path: "test.jsonnet",
+			Data: []byte(strings.Repeat(" ", maxRenderFileSize+1)),
+		})
+		require.ErrorIs(t, err, ErrRenderTooLarge)
+
+		_, err = evaluate(context.Background(), func() ([]byte, error) {
+			return make([]byte, maxRenderOutputSize+1), nil
+		})
+		require.ErrorIs(t, err, ErrRenderTooLarge)
+	})
+
+	t.Run("vendored jsonnet is a library", func(t *testing.T) {
+		require.True(t, IsLibraryPath("vendor/grafonnet/main.jsonnet"))
+		require.False(t, IsRenderedPath("vendor/grafonnet/main.jsonnet"))
+		require.False(t, SupportsImports("vendor/grafonnet/main.jsonnet"))
+		require.True(t, SupportsImports("dashboards/vendor.jsonnet"))
+
+		_, err := RenderFile(context.Background(), repository.NewMockReader(t), &repository.FileInfo{
+			Path: "vendor/grafonnet/main.jsonnet",
+			Data: []byte(`{}`),
+		})
+		require.EqualError(t, err, "file can not be rendered: vendor/grafonnet/main.jsonnet")
+	})
+
+	t.Run("cue values must be concrete", func(t *testing.T) {
+		_, err := RenderFile(context.Background(), repository.NewMockReader(t), &repository.FileInfo{
+			Path: "test.cue",
+			Data: []byte(`spec: title: string`),
+		})
+		require.ErrorContains(t, err, "incomplete value string")
+	})
+}
+
+func TestParserRendersFiles(t *testing.T) {
+	reader := repository.NewMockReader(t)
+	parser := &parser{reader: reader}
+
+	t.Run("rendered output is the resource", func(t *testing.T) {
+		tree := []repository.FileTreeEntry{
+			{Path: "test.jsonnet", Hash: "xyz", Blob: true},
+			{Path: "lib/panels.libsonnet", Hash: "abc", Blob: true},
+		}
+		reader.On("ReadTree", mock.Anything, "main").Return(tree, nil).Once()
+
+		info := &repository.FileInfo{
+			Path: "test.jsonnet",
+			Ref:  "main",
+			Hash: "xyz",
+			Data: []byte(`{ apiVersion: 'dashboard.grafana.app/v0alpha1', kind: 'Dashboard', metadata: { name: 'test' }, spec: { title: 'A' + 'B' } }`),
+		}
+		parsed, err := parser.Parse(context.Background(), info)
+		require.EqualError(t, err, "no clients configured") // parsing worked
+		require.Equal(t, "test", parsed.Obj.GetName())
+		require.Equal(t, "AB", parsed.Obj.Object["spec"].(map[string]any)["title"])
+		require.Same(t, info, parsed.Info)
+
+		// The checksum changes with the libraries the file may import
+		source, ok := parsed.Meta.GetSourceProperties()
+		require.True(t, ok)
+		require.Equal(t, RenderedChecksum("xyz", LibrariesDigest(tree)), source.Checksum)
+
+		// The libraries are read once per ref
+		_, err = parser.Parse(context.Background(), info)
+		require.EqualError(t, err, "no clients configured")
+
+		_, err = parsed.ToSaveBytes()
+		require.ErrorIs(t, err, ErrRenderedFileReadOnly)
+	})
+
+	t.Run("evaluation errors name the file", func(t *testing.T) {
+		_, err := parser.Parse(context.Background(), &repository.FileInfo{
+			Path: "dashboards/broken.jsonnet",
+			Data: []byte(`{ a: `),
+		})
+		require.ErrorContains(t, err, "unable to render dashboards/broken.jsonnet")
+	})
+}
@@ -0,0 +1,83 @@
+package savedsearch
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/registry/rest"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/kube-openapi/pkg/common"
+
+	search "github.com/grafana/grafana/pkg/apis/search/v0alpha1"
+	grafanaregistry "github.com/grafana/grafana/pkg/apiserver/registry/generic"
+	"github.com/grafana/grafana/pkg/services/apiserver/builder"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+)
+
+var _ builder.APIGroupBuilder = (*SavedSearchAPIBuilder)(nil)
+
+// SavedSearchAPIBuilder stores the saved searches in unified storage.
+// The storage server runs the saved searches with an alert and writes their status.
+type SavedSearchAPIBuilder struct{}
+
+func RegisterAPIService(features featuremgmt.FeatureToggles, apiregistration builder.APIRegistrar) *SavedSearchAPIBuilder {
+	if !features.IsEnabledGlobally(featuremgmt.FlagUnifiedStorageSearch) {
+		return nil // saved searches need the unified search index
+	}
+
+	builder := &SavedSearchAPIBuilder{}
+	apiregistration.RegisterAPI(builder)
+	return builder
+}
+
+func (b *SavedSearchAPIBuilder) GetAuthorizer() authorizer.Authorizer {
+	return nil // default authorizer is fine
+}
+
+func (b *SavedSearchAPIBuilder) GetGroupVersion() schema.GroupVersion {
+	return search.SchemeGroupVersion
+}
+
+func addKnownTypes(scheme *runtime.Scheme, gv schema.GroupVersion) {
+	scheme.AddKnownTypes(gv,
+		&search.SavedSearch{},
+		&search.SavedSearchList{},
+	)
+}
+
+func (b *SavedSearchAPIBuilder) InstallSchema(scheme *runtime.Scheme) error {
+	gv := search.SchemeGroupVersion
+	err := search.AddToScheme(scheme)
+	if err != nil {
+		return err
+	}
+
+	// Link this version to the internal representation.
+	// This is used for server-side-apply (PATCH), and avoids the error:
+	//   "no kind is registered for the type"
+	addKnownTypes(scheme, schema.GroupVersion{
+		Group:   search.GROUP,
+		Version: runtime.APIVersionInternal,
+	})
+	metav1.AddToGroupVersion(scheme, gv)
+	return scheme.SetVersionPriority(gv)
+}
+
+func (b *SavedSearchAPIBuilder) UpdateAPIGroupInfo(apiGroupInfo *genericapiserver.APIGroupInfo, opts builder.APIGroupOptions) error {
+	resourceInfo := search.SavedSearchResourceInfo
+	storage := map[string]rest.Storage{}
+
+	savedSearchStorage, err := grafanaregistry.NewRegistryStore(opts.Scheme, resourceInfo, opts.OptsGetter)
+	if err != nil {
+		return err
+	}
+
+	storage[resourceInfo.StoragePath()] = savedSearchStorage
+	apiGroupInfo.VersionedResourcesStorageMap[search.VERSION] = storage
+	return nil
+}
+
+func (b *SavedSearchAPIBuilder) GetOpenAPIDefinitions() common.GetOpenAPIDefinitions {
+	return search.GetOpenAPIDefinitions
+}
@@ -48,6 +48,11 @@ type StorageOptions struct {
 	GrpcClientAuthenticationTokenNamespace   string
 	GrpcClientAuthenticationAllowInsecure    bool
 
+	// For unified-grpc with several storage servers, the address of each shard by name.
+	// The namespaces missing from ShardNamespaces are assigned to a shard by hash
+	ShardAddresses  map[string]string
+	ShardNamespaces map[string]string
+
 	// For file storage, this is the requested path
 	DataPath string
 
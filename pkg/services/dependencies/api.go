@@ -0,0 +1,87 @@
+package dependencies
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *DependencyService) registerAPIEndpoints() {
+	if s.routeRegister == nil {
+		return
+	}
+	s.routeRegister.Get("/api/dependencies/:kind/:uid", middleware.ReqSignedIn, routing.Wrap(s.getGraphHandler))
+}
+
+// swagger:route GET /dependencies/{kind}/{uid} dependencies getDependencyGraph
+//
+// Get the resources connected to a resource.
+//
+// The dependencies are the resources the resource uses, the dependents are the resources that use it.
+// Resources the user can not read are only counted.
+//
+// Responses:
+// 200: getDependencyGraphResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *DependencyService) getGraphHandler(c *contextmodel.ReqContext) response.Response {
+	query := GetGraphQuery{
+		OrgID:     c.GetOrgID(),
+		Kind:      web.Params(c.Req)[":kind"],
+		UID:       web.Params(c.Req)[":uid"],
+		Direction: Direction(c.Query("direction")),
+	}
+	if v := c.Query("depth"); v != "" {
+		depth, err := strconv.Atoi(v)
+		if err != nil {
+			return response.Error(http.StatusBadRequest, "invalid depth", err)
+		}
+		query.Depth = depth
+	}
+
+	graph, err := s.GetGraph(c.Req.Context(), c.SignedInUser, query)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get the dependency graph", err)
+	}
+	return response.JSON(http.StatusOK, graph)
+}
+
+// swagger:parameters getDependencyGraph
+type GetDependencyGraphParams struct {
+	// in:path
+	// required:true
+	// enum: DataSource,Dashboard,LibraryPanel,AlertRule,Correlation,PublicDashboard
+	Kind string `json:"kind"`
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:query
+	// required:false
+	// default:both
+	// enum: dependencies,dependents,both
+	Direction string `json:"direction"`
+	// The number of edges followed from the resource (at most 5)
+	// in:query
+	// required:false
+	// default:1
+	Depth int64 `json:"depth"`
+}
+
+// swagger:response getDependencyGraphResponse
+type GetDependencyGraphResponse struct {
+	// in: body
+	Body Graph `json:"body"`
+}
+
+// swagger:response resourceInUseError
+type ResourceInUseError struct {
+	// in: body
+	Body InUseResult `json:"body"`
+}
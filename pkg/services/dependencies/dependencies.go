@@ -0,0 +1,142 @@
+package dependencies
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+)
+
+// The kinds of resources in the dependency graph
+const (
+	KindDataSource      = "DataSource"
+	KindDashboard       = "Dashboard"
+	KindLibraryPanel    = "LibraryPanel"
+	KindAlertRule       = "AlertRule"
+	KindCorrelation     = "Correlation"
+	KindPublicDashboard = "PublicDashboard"
+)
+
+type Direction string
+
+const (
+	// Follow the edges to the resources a resource depends on
+	DirectionDependencies Direction = "dependencies"
+	// Follow the edges to the resources that depend on a resource
+	DirectionDependents Direction = "dependents"
+	// Follow the edges in both directions
+	DirectionBoth Direction = "both"
+)
+
+const (
+	DefaultDepth = 1
+	MaxDepth     = 5
+
+	// The traversal stops once the graph has this many nodes
+	MaxNodes = 1000
+)
+
+type Service interface {
+	// GetGraph returns the resources connected to a resource, up to the query depth.
+	// Resources the user can not read are counted, but not included.
+	GetGraph(ctx context.Context, user identity.Requester, query GetGraphQuery) (*Graph, error)
+}
+
+type GetGraphQuery struct {
+	OrgID     int64
+	Kind      string
+	UID       string
+	Direction Direction
+	Depth     int
+}
+
+type Node struct {
+	Kind  string `json:"kind"`
+	UID   string `json:"uid"`
+	Title string `json:"title,omitempty"`
+
+	// The folder of the resource, used for access control
+	folderUID string
+	// The data source or dashboard that controls the access to the resource
+	parentUID string
+}
+
+// ID identifies the node in the graph: {kind}/{uid}
+func (n Node) ID() string {
+	return n.Kind + "/" + n.UID
+}
+
+// Edge from a resource to a resource it depends on
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type Graph struct {
+	// The queried resource is the first node
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+
+	// The number of connected resources the user can not read
+	Hidden int `json:"hidden,omitempty"`
+
+	// The sources that could not be queried, or limits that were reached
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Dependents returns the resources that directly depend on the queried resource
+func (g *Graph) Dependents() []Node {
+	if len(g.Nodes) == 0 {
+		return nil
+	}
+	root := g.Nodes[0].ID()
+	from := make(map[string]bool)
+	for _, e := range g.Edges {
+		if e.To == root {
+			from[e.From] = true
+		}
+	}
+	var nodes []Node
+	for _, n := range g.Nodes[1:] {
+		if from[n.ID()] {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// FindDependents returns the resources that directly depend on a resource,
+// and the number of dependents the user can not read
+func FindDependents(ctx context.Context, s Service, user identity.Requester, orgID int64, kind, uid string) ([]Node, int, error) {
+	graph, err := s.GetGraph(ctx, user, GetGraphQuery{
+		OrgID:     orgID,
+		Kind:      kind,
+		UID:       uid,
+		Direction: DirectionDependents,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return graph.Dependents(), graph.Hidden, nil
+}
+
+// InUseResult is returned when the deletion of a resource that is still used is refused
+type InUseResult struct {
+	Message string `json:"message"`
+
+	// The resources that directly depend on the resource
+	Dependents []Node `json:"dependents"`
+
+	// The number of dependents the user can not read
+	Hidden int `json:"hidden,omitempty"`
+}
+
+// source provides the edges from one store
+type source interface {
+	name() string
+
+	// The resources the node depends on
+	dependencies(ctx context.Context, orgID int64, node Node) ([]Node, error)
+
+	// The resources that depend on the node
+	dependents(ctx context.Context, orgID int64, node Node) ([]Node, error)
+}
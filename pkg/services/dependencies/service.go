@@ -0,0 +1,287 @@
+package dependencies
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/apiserver/endpoints/request"
+	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	ngstore "github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+)
+
+var (
+	ErrInvalidQuery = errutil.BadRequest("dependencies.invalidQuery")
+	ErrAccessDenied = errutil.Forbidden("dependencies.accessDenied", errutil.WithPublicMessage("Access to the resource denied"))
+)
+
+var logger = log.New("dependencies")
+
+var _ Service = (*DependencyService)(nil)
+
+type DependencyService struct {
+	log           log.Logger
+	accessControl ac.AccessControl
+	routeRegister routing.RouteRegister
+	sources       []source
+}
+
+func ProvideService(cfg *setting.Cfg, routeRegister routing.RouteRegister, accessControl ac.AccessControl,
+	unified resource.ResourceClient, ruleStore *ngstore.DBstore, correlationsService *correlations.CorrelationsService,
+	publicDashboards publicdashboards.ServiceWrapper,
+) *DependencyService {
+	s := &DependencyService{
+		log:           logger,
+		accessControl: accessControl,
+		routeRegister: routeRegister,
+	}
+	if unified != nil {
+		s.sources = append(s.sources, &searchSource{index: unified, namespacer: request.GetNamespaceMapper(cfg)})
+	}
+	if ruleStore != nil {
+		s.sources = append(s.sources, &alertRuleSource{store: ruleStore})
+	}
+	if correlationsService != nil {
+		s.sources = append(s.sources, &correlationSource{store: correlationsService})
+	}
+	if publicDashboards != nil {
+		s.sources = append(s.sources, &publicDashboardSource{store: publicDashboards})
+	}
+	s.registerAPIEndpoints()
+	return s
+}
+
+// parseKind returns the kind with the canonical casing
+func parseKind(kind string) (string, bool) {
+	for _, k := range []string{KindDataSource, KindDashboard, KindLibraryPanel, KindAlertRule, KindCorrelation, KindPublicDashboard} {
+		if strings.EqualFold(k, kind) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// step is a node waiting to be expanded
+type step struct {
+	node  Node
+	depth int
+	// Nodes reached from a dependency edge only follow the dependencies further,
+	// so the dependents of a data source do not include every other user of the same library panel
+	forward bool
+	reverse bool
+}
+
+func (s *DependencyService) GetGraph(ctx context.Context, user identity.Requester, query GetGraphQuery) (*Graph, error) {
+	kind, ok := parseKind(query.Kind)
+	if !ok {
+		return nil, ErrInvalidQuery.Errorf("invalid resource kind: %q", query.Kind)
+	}
+	if query.UID == "" {
+		return nil, ErrInvalidQuery.Errorf("missing resource uid")
+	}
+	switch query.Direction {
+	case "":
+		query.Direction = DirectionBoth
+	case DirectionDependencies, DirectionDependents, DirectionBoth:
+	default:
+		return nil, ErrInvalidQuery.Errorf("invalid direction: %q", query.Direction)
+	}
+	if query.Depth <= 0 {
+		query.Depth = DefaultDepth
+	}
+	if query.Depth > MaxDepth {
+		query.Depth = MaxDepth
+	}
+
+	root := Node{Kind: kind, UID: query.UID}
+	ok, err := s.canRead(ctx, user, root)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrAccessDenied.Errorf("can not read %s", root.ID())
+	}
+
+	graph, err := s.traverse(ctx, query.OrgID, root, query.Direction, query.Depth)
+	if err != nil {
+		return nil, err
+	}
+	return s.filter(ctx, user, graph)
+}
+
+// traverse walks the graph breadth first from the root node
+func (s *DependencyService) traverse(ctx context.Context, orgID int64, root Node, direction Direction, depth int) (*Graph, error) {
+	graph := &Graph{Nodes: []Node{root}, Edges: []Edge{}}
+	index := map[string]int{root.ID(): 0}
+	edges := make(map[Edge]bool)
+	failed := make(map[string]bool)
+	truncated := false
+
+	// visit adds the node when it is new, and returns if the node is in the graph
+	visit := func(n Node) (bool, bool) {
+		if i, ok := index[n.ID()]; ok {
+			existing := &graph.Nodes[i]
+			if existing.Title == "" {
+				existing.Title = n.Title
+			}
+			if existing.folderUID == "" {
+				existing.folderUID = n.folderUID
+			}
+			if existing.parentUID == "" {
+				existing.parentUID = n.parentUID
+			}
+			return true, false
+		}
+		if len(graph.Nodes) >= MaxNodes {
+			truncated = true
+			return false, false
+		}
+		index[n.ID()] = len(graph.Nodes)
+		graph.Nodes = append(graph.Nodes, n)
+		return true, true
+	}
+	addEdge := func(e Edge) {
+		if e.From == e.To || edges[e] {
+			return
+		}
+		edges[e] = true
+		graph.Edges = append(graph.Edges, e)
+	}
+
+	queue := []step{{
+		node:    root,
+		forward: direction != DirectionDependents,
+		reverse: direction != DirectionDependencies,
+	}}
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		current := queue[0]
+		queue = queue[1:]
+		if current.depth >= depth {
+			continue
+		}
+
+		for _, src := range s.sources {
+			if current.forward {
+				found, err := src.dependencies(ctx, orgID, current.node)
+				if err != nil {
+					s.log.Warn("Failed to get dependencies", "source", src.name(), "node", current.node.ID(), "error", err)
+					failed[src.name()] = true
+				}
+				for _, n := range found {
+					ok, added := visit(n)
+					if !ok {
+						continue
+					}
+					addEdge(Edge{From: current.node.ID(), To: n.ID()})
+					if added {
+						queue = append(queue, step{node: n, depth: current.depth + 1, forward: true})
+					}
+				}
+			}
+			if current.reverse {
+				found, err := src.dependents(ctx, orgID, current.node)
+				if err != nil {
+					s.log.Warn("Failed to get dependents", "source", src.name(), "node", current.node.ID(), "error", err)
+					failed[src.name()] = true
+				}
+				for _, n := range found {
+					ok, added := visit(n)
+					if !ok {
+						continue
+					}
+					addEdge(Edge{From: n.ID(), To: current.node.ID()})
+					if added {
+						queue = append(queue, step{node: n, depth: current.depth + 1, reverse: true})
+					}
+				}
+			}
+		}
+	}
+
+	for _, src := range s.sources {
+		if failed[src.name()] {
+			graph.Warnings = append(graph.Warnings, fmt.Sprintf("%s: failed to load the connected resources", src.name()))
+		}
+	}
+	if truncated {
+		graph.Warnings = append(graph.Warnings, fmt.Sprintf("the graph was limited to %d resources", MaxNodes))
+	}
+	return graph, nil
+}
+
+// filter removes the nodes the user can not read, and their edges
+func (s *DependencyService) filter(ctx context.Context, user identity.Requester, graph *Graph) (*Graph, error) {
+	visible := make(map[string]bool, len(graph.Nodes))
+	nodes := graph.Nodes[:1]
+	visible[graph.Nodes[0].ID()] = true
+	for _, n := range graph.Nodes[1:] {
+		ok, err := s.canRead(ctx, user, n)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			graph.Hidden++
+			continue
+		}
+		visible[n.ID()] = true
+		nodes = append(nodes, n)
+	}
+	graph.Nodes = nodes
+
+	edges := graph.Edges[:0]
+	for _, e := range graph.Edges {
+		if visible[e.From] && visible[e.To] {
+			edges = append(edges, e)
+		}
+	}
+	graph.Edges = edges
+	return graph, nil
+}
+
+func (s *DependencyService) canRead(ctx context.Context, user identity.Requester, n Node) (bool, error) {
+	return s.accessControl.Evaluate(ctx, user, readEvaluator(n))
+}
+
+// readEvaluator returns the permission needed to see the node.
+// When the folder or parent is not known (the queried resource), the action on any scope is enough.
+func readEvaluator(n Node) ac.Evaluator {
+	switch n.Kind {
+	case KindDataSource:
+		return ac.EvalPermission(datasources.ActionRead, datasources.ScopeProvider.GetResourceScopeUID(n.UID))
+	case KindDashboard:
+		return ac.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(n.UID))
+	case KindLibraryPanel:
+		return ac.EvalPermission(ac.ActionLibraryPanelsRead, ac.Scope("library.panels", "uid", n.UID))
+	case KindAlertRule:
+		if n.folderUID == "" {
+			return ac.EvalPermission(ac.ActionAlertingRuleRead)
+		}
+		return ac.EvalPermission(ac.ActionAlertingRuleRead, dashboards.ScopeFoldersProvider.GetResourceScopeUID(n.folderUID))
+	case KindCorrelation:
+		// Correlations are readable with the source data source
+		if n.parentUID == "" {
+			return ac.EvalPermission(datasources.ActionRead)
+		}
+		return ac.EvalPermission(datasources.ActionRead, datasources.ScopeProvider.GetResourceScopeUID(n.parentUID))
+	case KindPublicDashboard:
+		// Public dashboards are readable with the dashboard
+		if n.parentUID == "" {
+			return ac.EvalPermission(dashboards.ActionDashboardsRead)
+		}
+		return ac.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(n.parentUID))
+	}
+	return ac.EvalPermission("")
+}
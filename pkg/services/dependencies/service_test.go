@@ -0,0 +1,186 @@
+package dependencies
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// fakeSource returns the edges from a list of (dependent, dependency) pairs
+type fakeSource struct {
+	id    string
+	edges [][2]Node
+	err   error
+}
+
+func (f *fakeSource) name() string {
+	return f.id
+}
+
+func (f *fakeSource) dependencies(_ context.Context, _ int64, node Node) ([]Node, error) {
+	var nodes []Node
+	for _, e := range f.edges {
+		if e[0].ID() == node.ID() {
+			nodes = append(nodes, e[1])
+		}
+	}
+	return nodes, f.err
+}
+
+func (f *fakeSource) dependents(_ context.Context, _ int64, node Node) ([]Node, error) {
+	var nodes []Node
+	for _, e := range f.edges {
+		if e[1].ID() == node.ID() {
+			nodes = append(nodes, e[0])
+		}
+	}
+	return nodes, f.err
+}
+
+var (
+	prometheus = Node{Kind: KindDataSource, UID: "prom"}
+	loki       = Node{Kind: KindDataSource, UID: "loki"}
+	overview   = Node{Kind: KindDashboard, UID: "overview", Title: "Overview"}
+	details    = Node{Kind: KindDashboard, UID: "details", Title: "Details"}
+	cpuPanel   = Node{Kind: KindLibraryPanel, UID: "cpu"}
+	highCPU    = Node{Kind: KindAlertRule, UID: "high-cpu", Title: "High CPU", folderUID: "alerts"}
+	logsLink   = Node{Kind: KindCorrelation, UID: "logs", parentUID: "prom"}
+	shared     = Node{Kind: KindPublicDashboard, UID: "shared", parentUID: "overview"}
+)
+
+func testSource() *fakeSource {
+	return &fakeSource{id: "fake", edges: [][2]Node{
+		{overview, prometheus},
+		{overview, cpuPanel},
+		{details, cpuPanel},
+		{details, loki},
+		{highCPU, prometheus},
+		{highCPU, overview},
+		{logsLink, prometheus},
+		{logsLink, loki},
+		{shared, overview},
+	}}
+}
+
+func newTestService(sources ...source) *DependencyService {
+	return &DependencyService{
+		log:           log.NewNopLogger(),
+		accessControl: acimpl.ProvideAccessControl(featuremgmt.WithFeatures()),
+		sources:       sources,
+	}
+}
+
+func newTestUser(permissions map[string][]string) *user.SignedInUser {
+	return &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: permissions}}
+}
+
+var readAll = newTestUser(map[string][]string{
+	datasources.ActionRead:                {datasources.ScopeAll},
+	dashboards.ActionDashboardsRead:       {dashboards.ScopeDashboardsAll},
+	accesscontrol.ActionLibraryPanelsRead: {"library.panels:*"},
+	accesscontrol.ActionAlertingRuleRead:  {dashboards.ScopeFoldersAll},
+})
+
+func nodeIDs(nodes []Node) []string {
+	ids := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		ids = append(ids, n.ID())
+	}
+	return ids
+}
+
+func TestGetGraph(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns the direct dependents", func(t *testing.T) {
+		s := newTestService(testSource())
+		graph, err := s.GetGraph(ctx, readAll, GetGraphQuery{OrgID: 1, Kind: "datasource", UID: "prom", Direction: DirectionDependents})
+		require.NoError(t, err)
+		require.Equal(t, []string{"DataSource/prom", "Dashboard/overview", "AlertRule/high-cpu", "Correlation/logs"}, nodeIDs(graph.Nodes))
+		require.ElementsMatch(t, []Edge{
+			{From: "Dashboard/overview", To: "DataSource/prom"},
+			{From: "AlertRule/high-cpu", To: "DataSource/prom"},
+			{From: "Correlation/logs", To: "DataSource/prom"},
+		}, graph.Edges)
+		require.Equal(t, "Overview", graph.Nodes[1].Title)
+		require.Len(t, graph.Dependents(), 3)
+	})
+
+	t.Run("returns the dependencies", func(t *testing.T) {
+		s := newTestService(testSource())
+		graph, err := s.GetGraph(ctx, readAll, GetGraphQuery{OrgID: 1, Kind: KindDashboard, UID: "overview", Direction: DirectionDependencies})
+		require.NoError(t, err)
+		require.Equal(t, []string{"Dashboard/overview", "DataSource/prom", "LibraryPanel/cpu"}, nodeIDs(graph.Nodes))
+		require.Empty(t, graph.Dependents())
+	})
+
+	t.Run("follows each direction from the resource", func(t *testing.T) {
+		s := newTestService(testSource())
+		graph, err := s.GetGraph(ctx, readAll, GetGraphQuery{OrgID: 1, Kind: KindDataSource, UID: "prom", Direction: DirectionDependents, Depth: 2})
+		require.NoError(t, err)
+		// The other users of the library panel and the data sources of the correlation are not dependents
+		require.Equal(t, []string{
+			"DataSource/prom", "Dashboard/overview", "AlertRule/high-cpu", "Correlation/logs", "PublicDashboard/shared",
+		}, nodeIDs(graph.Nodes))
+		require.Contains(t, graph.Edges, Edge{From: "AlertRule/high-cpu", To: "Dashboard/overview"})
+
+		graph, err = s.GetGraph(ctx, readAll, GetGraphQuery{OrgID: 1, Kind: KindDashboard, UID: "overview", Depth: 2})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{
+			"Dashboard/overview", "DataSource/prom", "LibraryPanel/cpu", "AlertRule/high-cpu", "PublicDashboard/shared",
+		}, nodeIDs(graph.Nodes))
+	})
+
+	t.Run("limits the depth", func(t *testing.T) {
+		s := newTestService(testSource())
+		graph, err := s.GetGraph(ctx, readAll, GetGraphQuery{OrgID: 1, Kind: KindPublicDashboard, UID: "shared", Direction: DirectionDependencies, Depth: 100})
+		require.NoError(t, err)
+		require.Equal(t, []string{"PublicDashboard/shared", "Dashboard/overview", "DataSource/prom", "LibraryPanel/cpu"}, nodeIDs(graph.Nodes))
+	})
+
+	t.Run("hides the resources the user can not read", func(t *testing.T) {
+		s := newTestService(testSource())
+		viewer := newTestUser(map[string][]string{
+			datasources.ActionRead:               {datasources.ScopeAll},
+			accesscontrol.ActionAlertingRuleRead: {dashboards.ScopeFoldersProvider.GetResourceScopeUID("other")},
+		})
+		graph, err := s.GetGraph(ctx, viewer, GetGraphQuery{OrgID: 1, Kind: KindDataSource, UID: "prom", Direction: DirectionDependents})
+		require.NoError(t, err)
+		require.Equal(t, []string{"DataSource/prom", "Correlation/logs"}, nodeIDs(graph.Nodes))
+		require.Equal(t, []Edge{{From: "Correlation/logs", To: "DataSource/prom"}}, graph.Edges)
+		require.Equal(t, 2, graph.Hidden)
+
+		_, err = s.GetGraph(ctx, viewer, GetGraphQuery{OrgID: 1, Kind: KindDashboard, UID: "overview"})
+		require.ErrorIs(t, err, ErrAccessDenied)
+	})
+
+	t.Run("reports the failing sources", func(t *testing.T) {
+		s := newTestService(testSource(), &fakeSource{id: "broken", err: errors.New("unavailable")})
+		graph, err := s.GetGraph(ctx, readAll, GetGraphQuery{OrgID: 1, Kind: KindDataSource, UID: "prom"})
+		require.NoError(t, err)
+		require.Len(t, graph.Nodes, 4)
+		require.Equal(t, []string{"broken: failed to load the connected resources"}, graph.Warnings)
+	})
+
+	t.Run("validates the query", func(t *testing.T) {
+		s := newTestService(testSource())
+		for _, q := range []GetGraphQuery{
+			{OrgID: 1, Kind: "folder", UID: "a"},
+			{OrgID: 1, Kind: KindDashboard},
+			{OrgID: 1, Kind: KindDashboard, UID: "a", Direction: "up"},
+		} {
+			_, err := s.GetGraph(ctx, readAll, q)
+			require.ErrorIs(t, err, ErrInvalidQuery)
+		}
+	})
+}
@@ -0,0 +1,298 @@
+package dependencies
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/selection"
+
+	dashboardv0 "github.com/grafana/grafana/apps/dashboard/pkg/apis/dashboard/v0alpha1"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/apiserver/endpoints/request"
+	"github.com/grafana/grafana/pkg/services/correlations"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	pdmodels "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// The most dashboards returned for a single data source or library panel
+const searchLimit = 500
+
+// searchSource reads the dashboard references from the unified search index
+type searchSource struct {
+	index      resourcepb.ResourceIndexClient
+	namespacer request.NamespaceMapper
+}
+
+func (s *searchSource) name() string {
+	return "search"
+}
+
+func (s *searchSource) search(ctx context.Context, orgID int64, field string, value string) (*resourcepb.ResourceSearchResponse, error) {
+	// The graph is filtered with the user permissions after the traversal
+	ctx = identity.WithServiceIdentityContext(ctx, orgID)
+	rsp, err := s.index.Search(ctx, &resourcepb.ResourceSearchRequest{
+		Options: &resourcepb.ListOptions{
+			Key: &resourcepb.ResourceKey{
+				Namespace: s.namespacer(orgID),
+				Group:     dashboardv0.GROUP,
+				Resource:  dashboardv0.DASHBOARD_RESOURCE,
+			},
+			Fields: []*resourcepb.Requirement{{
+				Key:      field,
+				Operator: string(selection.Equals),
+				Values:   []string{value},
+			}},
+		},
+		Fields: []string{resource.SEARCH_FIELD_TITLE, resource.SEARCH_FIELD_FOLDER, resource.SEARCH_FIELD_REFERENCES},
+		Limit:  searchLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rsp.Error != nil {
+		return nil, resource.GetError(rsp.Error)
+	}
+	return rsp, nil
+}
+
+func (s *searchSource) dependencies(ctx context.Context, orgID int64, node Node) ([]Node, error) {
+	if node.Kind != KindDashboard {
+		return nil, nil
+	}
+	rsp, err := s.search(ctx, orgID, resource.SEARCH_FIELD_NAME, node.UID)
+	if err != nil || rsp.Results == nil {
+		return nil, err
+	}
+
+	var nodes []Node
+	for _, row := range rsp.Results.Rows {
+		for i, col := range rsp.Results.Columns {
+			if col.Name != resource.SEARCH_FIELD_REFERENCES || i >= len(row.Cells) {
+				continue
+			}
+			v, err := resource.DecodeCell(col, i, row.Cells[i])
+			if err != nil {
+				return nil, err
+			}
+			refs, _ := v.([]any)
+			for _, ref := range refs {
+				target, _ := ref.(string)
+				kind, uid, ok := strings.Cut(target, "/")
+				if !ok || uid == "" || (kind != KindDataSource && kind != KindLibraryPanel) {
+					continue
+				}
+				nodes = append(nodes, Node{Kind: kind, UID: uid})
+			}
+		}
+	}
+	return nodes, nil
+}
+
+func (s *searchSource) dependents(ctx context.Context, orgID int64, node Node) ([]Node, error) {
+	if node.Kind != KindDataSource && node.Kind != KindLibraryPanel {
+		return nil, nil
+	}
+	rsp, err := s.search(ctx, orgID, resource.SEARCH_FIELD_REFERENCES, node.ID())
+	if err != nil || rsp.Results == nil {
+		return nil, err
+	}
+
+	nodes := make([]Node, 0, len(rsp.Results.Rows))
+	for _, row := range rsp.Results.Rows {
+		n := Node{Kind: KindDashboard, UID: row.Key.Name}
+		for i, col := range rsp.Results.Columns {
+			if col.Name == resource.SEARCH_FIELD_TITLE && i < len(row.Cells) {
+				n.Title = string(row.Cells[i])
+			}
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
+
+type ruleStore interface {
+	ListAlertRules(ctx context.Context, query *ngmodels.ListAlertRulesQuery) (ngmodels.RulesGroup, error)
+}
+
+// alertRuleSource reads the data sources queried by the alert rules, and the dashboards they are linked to
+type alertRuleSource struct {
+	store ruleStore
+}
+
+func (s *alertRuleSource) name() string {
+	return "alerting"
+}
+
+func ruleNode(rule *ngmodels.AlertRule) Node {
+	return Node{Kind: KindAlertRule, UID: rule.UID, Title: rule.Title, folderUID: rule.NamespaceUID}
+}
+
+// ruleDataSources returns the data sources queried by the rule, without the expressions
+func ruleDataSources(rule *ngmodels.AlertRule) []string {
+	var uids []string
+	for _, q := range rule.Data {
+		if expr, err := q.IsExpression(); err != nil || expr {
+			continue
+		}
+		uids = append(uids, q.DatasourceUID)
+	}
+	return uids
+}
+
+func (s *alertRuleSource) dependencies(ctx context.Context, orgID int64, node Node) ([]Node, error) {
+	if node.Kind != KindAlertRule {
+		return nil, nil
+	}
+	rules, err := s.store.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{OrgID: orgID, RuleUIDs: []string{node.UID}})
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []Node
+	for _, rule := range rules {
+		for _, uid := range ruleDataSources(rule) {
+			nodes = append(nodes, Node{Kind: KindDataSource, UID: uid})
+		}
+		if rule.DashboardUID != nil && *rule.DashboardUID != "" {
+			nodes = append(nodes, Node{Kind: KindDashboard, UID: *rule.DashboardUID})
+		}
+	}
+	return nodes, nil
+}
+
+func (s *alertRuleSource) dependents(ctx context.Context, orgID int64, node Node) ([]Node, error) {
+	switch node.Kind {
+	case KindDashboard:
+		rules, err := s.store.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{OrgID: orgID, DashboardUID: node.UID})
+		if err != nil {
+			return nil, err
+		}
+		nodes := make([]Node, 0, len(rules))
+		for _, rule := range rules {
+			nodes = append(nodes, ruleNode(rule))
+		}
+		return nodes, nil
+
+	case KindDataSource:
+		// The rule queries are not indexed by data source
+		rules, err := s.store.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{OrgID: orgID})
+		if err != nil {
+			return nil, err
+		}
+		var nodes []Node
+		for _, rule := range rules {
+			for _, uid := range ruleDataSources(rule) {
+				if uid == node.UID {
+					nodes = append(nodes, ruleNode(rule))
+					break
+				}
+			}
+		}
+		return nodes, nil
+	}
+	return nil, nil
+}
+
+type correlationStore interface {
+	GetCorrelations(ctx context.Context, cmd correlations.GetCorrelationsQuery) (correlations.GetCorrelationsResponseBody, error)
+}
+
+// correlationSource reads the data sources linked by the correlations
+type correlationSource struct {
+	store correlationStore
+}
+
+func (s *correlationSource) name() string {
+	return "correlations"
+}
+
+const correlationsPageSize = 100
+
+// each calls the function for all the correlations in the organization
+func (s *correlationSource) each(ctx context.Context, orgID int64, fn func(c correlations.Correlation)) error {
+	for page := int64(1); ; page++ {
+		rsp, err := s.store.GetCorrelations(ctx, correlations.GetCorrelationsQuery{
+			OrgId: orgID,
+			Limit: correlationsPageSize,
+			Page:  page,
+		})
+		if err != nil {
+			return err
+		}
+		for _, c := range rsp.Correlations {
+			fn(c)
+		}
+		if len(rsp.Correlations) < correlationsPageSize {
+			return nil
+		}
+	}
+}
+
+func correlationNode(c correlations.Correlation) Node {
+	return Node{Kind: KindCorrelation, UID: c.UID, Title: c.Label, parentUID: c.SourceUID}
+}
+
+func (s *correlationSource) dependencies(ctx context.Context, orgID int64, node Node) ([]Node, error) {
+	if node.Kind != KindCorrelation {
+		return nil, nil
+	}
+	var nodes []Node
+	err := s.each(ctx, orgID, func(c correlations.Correlation) {
+		if c.UID != node.UID {
+			return
+		}
+		nodes = append(nodes, Node{Kind: KindDataSource, UID: c.SourceUID})
+		if c.TargetUID != nil && *c.TargetUID != "" {
+			nodes = append(nodes, Node{Kind: KindDataSource, UID: *c.TargetUID})
+		}
+	})
+	return nodes, err
+}
+
+func (s *correlationSource) dependents(ctx context.Context, orgID int64, node Node) ([]Node, error) {
+	if node.Kind != KindDataSource {
+		return nil, nil
+	}
+	var nodes []Node
+	err := s.each(ctx, orgID, func(c correlations.Correlation) {
+		if c.SourceUID == node.UID || (c.TargetUID != nil && *c.TargetUID == node.UID) {
+			nodes = append(nodes, correlationNode(c))
+		}
+	})
+	return nodes, err
+}
+
+// publicDashboardSource reads the public dashboards of a dashboard.
+// The public dashboards can not be looked up by uid, so only the dependents are supported.
+type publicDashboardSource struct {
+	store publicdashboards.ServiceWrapper
+}
+
+func (s *publicDashboardSource) name() string {
+	return "publicdashboards"
+}
+
+func (s *publicDashboardSource) dependencies(ctx context.Context, orgID int64, node Node) ([]Node, error) {
+	return nil, nil
+}
+
+func (s *publicDashboardSource) dependents(ctx context.Context, orgID int64, node Node) ([]Node, error) {
+	if node.Kind != KindDashboard {
+		return nil, nil
+	}
+	pd, err := s.store.FindByDashboardUid(ctx, orgID, node.UID)
+	if err != nil {
+		if errors.Is(err, pdmodels.ErrPublicDashboardNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if pd == nil {
+		return nil, nil
+	}
+	return []Node{{Kind: KindPublicDashboard, UID: pd.Uid, parentUID: pd.DashboardUid}}, nil
+}
@@ -10,6 +10,7 @@ import (
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dependencies"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/libraryelements/model"
@@ -107,26 +108,64 @@ func (l *LibraryElementService) createHandler(c *contextmodel.ReqContext) respon
 //
 // Deletes an existing library element as specified by the UID. This operation cannot be reverted.
 // You cannot delete a library element that is connected. This operation cannot be reverted.
+// A library element that is used by dashboards is only deleted with force=true.
 //
 // Responses:
-// 200: okResponse
+// 200: deleteLibraryElementResponse
 // 400: badRequestError
 // 401: unauthorisedError
 // 403: forbiddenError
 // 404: notFoundError
+// 409: resourceInUseError
 // 500: internalServerError
 func (l *LibraryElementService) deleteHandler(c *contextmodel.ReqContext) response.Response {
-	id, err := l.deleteLibraryElement(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
+	uid := web.Params(c.Req)[":uid"]
+	// The permissions on the element can only be resolved before it is deleted
+	dependents, rsp := l.libraryPanelInUse(c, uid)
+	if rsp != nil {
+		return rsp
+	}
+
+	id, err := l.deleteLibraryElement(c.Req.Context(), c.SignedInUser, uid)
 	if err != nil {
 		return l.toLibraryElementError(err, "Failed to delete library element")
 	}
 
-	return response.JSON(http.StatusOK, model.DeleteLibraryElementResponse{
-		Message: "Library element deleted",
-		ID:      id,
+	return response.JSON(http.StatusOK, DeleteLibraryElementResult{
+		Message:    "Library element deleted",
+		ID:         id,
+		Dependents: dependents,
 	})
 }
 
+// libraryPanelInUse refuses to delete a library panel that dashboards found in the search index still use,
+// unless the request sets force=true. The connections saved with the legacy API are checked by the deletion itself.
+func (l *LibraryElementService) libraryPanelInUse(c *contextmodel.ReqContext, uid string) ([]dependencies.Node, response.Response) {
+	dependents := []dependencies.Node{}
+	if l.dependencyService == nil {
+		return dependents, nil
+	}
+	found, hidden, err := dependencies.FindDependents(c.Req.Context(), l.dependencyService, c.SignedInUser, c.GetOrgID(), dependencies.KindLibraryPanel, uid)
+	if err != nil {
+		l.log.Debug("Failed to get the dependents of the library element", "uid", uid, "error", err)
+		return dependents, nil
+	}
+	dependents = append(dependents, found...)
+	if len(dependents) == 0 && hidden == 0 {
+		return dependents, nil
+	}
+
+	if !c.QueryBool("force") {
+		return nil, response.JSON(http.StatusConflict, dependencies.InUseResult{
+			Message:    "Library element is in use",
+			Dependents: dependents,
+			Hidden:     hidden,
+		})
+	}
+	l.log.Warn("Deleting a library element that is in use", "uid", uid, "dashboards", len(dependents)+hidden)
+	return dependents, nil
+}
+
 // swagger:route GET /library-elements/{library_element_uid} library_elements getLibraryElementByUID
 //
 // Get library element by UID.
@@ -385,6 +424,11 @@ type DeleteLibraryElementByUIDParams struct {
 	// in:path
 	// required:true
 	UID string `json:"library_element_uid"`
+	// If `true` the library element is deleted even when dashboards still use it.
+	// in:query
+	// required:false
+	// default:false
+	Force bool `json:"force"`
 }
 
 // swagger:parameters getLibraryElementByName
@@ -479,3 +523,17 @@ type GetLibraryElementConnectionsResponse struct {
 	// in: body
 	Body model.LibraryElementConnectionsResponse `json:"body"`
 }
+
+// swagger:response deleteLibraryElementResponse
+type DeleteLibraryElementResponse struct {
+	// in: body
+	Body DeleteLibraryElementResult `json:"body"`
+}
+
+// DeleteLibraryElementResult is the response struct for deleting a library element.
+type DeleteLibraryElementResult struct {
+	ID      int64  `json:"id"`
+	Message string `json:"message"`
+	// Dependents are the dashboards that used the deleted library element, when the deletion was forced.
+	Dependents []dependencies.Node `json:"dependents"`
+}
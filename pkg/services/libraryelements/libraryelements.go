@@ -10,19 +10,21 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dependencies"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/libraryelements/model"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
-func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister, folderService folder.Service, features featuremgmt.FeatureToggles, ac accesscontrol.AccessControl, dashboardsService dashboards.DashboardService) *LibraryElementService {
+func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister, folderService folder.Service, features featuremgmt.FeatureToggles, ac accesscontrol.AccessControl, dashboardsService dashboards.DashboardService, dependencyService dependencies.Service) *LibraryElementService {
 	l := &LibraryElementService{
 		Cfg:               cfg,
 		SQLStore:          sqlStore,
 		RouteRegister:     routeRegister,
 		folderService:     folderService,
 		dashboardsService: dashboardsService,
+		dependencyService: dependencyService,
 		log:               log.New("library-elements"),
 		features:          features,
 		AccessControl:     ac,
@@ -52,6 +54,7 @@ type LibraryElementService struct {
 	RouteRegister     routing.RouteRegister
 	folderService     folder.Service
 	dashboardsService dashboards.DashboardService
+	dependencyService dependencies.Service
 	log               log.Logger
 	features          featuremgmt.FeatureToggles
 	AccessControl     accesscontrol.AccessControl
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 
@@ -61,6 +62,7 @@ type dashboardEvent struct {
 type DashboardHandler struct {
 	Publisher        model.ChannelPublisher
 	ClientCount      model.ChannelClientCount
+	Presence         model.ChannelPresence
 	Store            db.DB
 	DashboardService dashboards.DashboardService
 	AccessControl    accesscontrol.AccessControl
@@ -71,23 +73,16 @@ func (h *DashboardHandler) GetHandlerForPath(_ string) (model.ChannelHandler, er
 	return h, nil // all dashboards share the same handler
 }
 
-// OnSubscribe for now allows anyone to subscribe to any dashboard
+// OnSubscribe allows users to subscribe to the dashboards they can view, and
+// to edit the dashboards they can edit.
 func (h *DashboardHandler) OnSubscribe(ctx context.Context, user identity.Requester, e model.SubscribeEvent) (model.SubscribeReply, backend.SubscribeStreamStatus, error) {
 	parts := strings.Split(e.Path, "/")
 
 	// make sure can view this dashboard
 	if len(parts) == 2 && parts[0] == "uid" {
-		query := dashboards.GetDashboardQuery{UID: parts[1], OrgID: user.GetOrgID()}
-		_, err := h.DashboardService.GetDashboard(ctx, &query)
-		if err != nil {
-			logger.Error("Error getting dashboard", "query", query, "error", err)
-			return model.SubscribeReply{}, backend.SubscribeStreamStatusNotFound, nil
-		}
-
-		evaluator := accesscontrol.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(parts[1]))
-		canView, err := h.AccessControl.Evaluate(ctx, user, evaluator)
-		if err != nil || !canView {
-			return model.SubscribeReply{}, backend.SubscribeStreamStatusPermissionDenied, err
+		status, err := h.checkDashboardAccess(ctx, user, parts[1], dashboards.ActionDashboardsRead)
+		if status != backend.SubscribeStreamStatusOK {
+			return model.SubscribeReply{}, status, err
 		}
 
 		return model.SubscribeReply{
@@ -96,6 +91,33 @@ func (h *DashboardHandler) OnSubscribe(ctx context.Context, user identity.Reques
 		}, backend.SubscribeStreamStatusOK, nil
 	}
 
+	// Editors are told who else is editing, the first editor holds the edit lock
+	if len(parts) == 2 && parts[0] == "edit" {
+		status, err := h.checkDashboardAccess(ctx, user, parts[1], dashboards.ActionDashboardsWrite)
+		if status != backend.SubscribeStreamStatusOK {
+			return model.SubscribeReply{}, status, err
+		}
+
+		presence, err := h.dashboardPresence(user.GetOrgID(), parts[1])
+		if err != nil {
+			return model.SubscribeReply{}, 0, err
+		}
+		data, err := json.Marshal(presence)
+		if err != nil {
+			return model.SubscribeReply{}, 0, err
+		}
+		info, err := json.Marshal(editInfo{Since: time.Now().UnixMilli()})
+		if err != nil {
+			return model.SubscribeReply{}, 0, err
+		}
+		return model.SubscribeReply{
+			Presence:    true,
+			JoinLeave:   true,
+			Data:        data,
+			ChannelInfo: info,
+		}, backend.SubscribeStreamStatusOK, nil
+	}
+
 	// Unknown path
 	logger.Error("Unknown dashboard channel", "path", e.Path)
 	return model.SubscribeReply{}, backend.SubscribeStreamStatusNotFound, nil
@@ -156,7 +178,7 @@ func (h *DashboardHandler) publish(orgID int64, event dashboardEvent) error {
 
 	// Only broadcast non-error events
 	if event.Error == "" {
-		err = h.Publisher(orgID, "grafana/dashboard/uid/"+event.UID, msg)
+		err = h.Publisher(orgID, dashboardChannelPrefix+event.UID, msg)
 		if err != nil {
 			return err
 		}
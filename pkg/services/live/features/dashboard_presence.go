@@ -0,0 +1,146 @@
+package features
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/live/model"
+)
+
+const (
+	dashboardChannelPrefix = "grafana/dashboard/uid/"
+	// Users editing a dashboard subscribe to its edit channel.
+	dashboardEditChannelPrefix = "grafana/dashboard/edit/"
+)
+
+var ErrDashboardAccessDenied = errors.New("dashboard access denied")
+
+// DashboardUser is a user viewing or editing a dashboard.
+type DashboardUser struct {
+	model.ConnInfo
+	// Sessions is the number of connections of the user to the channel.
+	Sessions int `json:"sessions"`
+	// Since is the time the user started editing in milliseconds.
+	Since int64 `json:"since,omitempty"`
+}
+
+// DashboardPresence lists the users viewing and editing a dashboard.
+type DashboardPresence struct {
+	UID     string          `json:"uid"`
+	Viewers []DashboardUser `json:"viewers"`
+	Editors []DashboardUser `json:"editors"`
+	// LockedBy is the user editing the dashboard first, the other editors are
+	// told someone else is editing.
+	LockedBy *DashboardUser `json:"lockedBy,omitempty"`
+}
+
+// editInfo is the channel info of the clients of the edit channels.
+type editInfo struct {
+	Since int64 `json:"since"`
+}
+
+// DashboardPresence returns the users viewing and editing the dashboard, on all
+// Grafana instances when using the HA engine.
+func (h *DashboardHandler) DashboardPresence(ctx context.Context, user identity.Requester, uid string) (*DashboardPresence, error) {
+	status, err := h.checkDashboardAccess(ctx, user, uid, dashboards.ActionDashboardsRead)
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case backend.SubscribeStreamStatusNotFound:
+		return nil, dashboards.ErrDashboardNotFound
+	case backend.SubscribeStreamStatusPermissionDenied:
+		return nil, ErrDashboardAccessDenied
+	}
+	return h.dashboardPresence(user.GetOrgID(), uid)
+}
+
+func (h *DashboardHandler) dashboardPresence(orgID int64, uid string) (*DashboardPresence, error) {
+	presence := &DashboardPresence{UID: uid, Viewers: []DashboardUser{}, Editors: []DashboardUser{}}
+	if h.Presence == nil {
+		return presence, nil
+	}
+	viewers, err := h.Presence(orgID, dashboardChannelPrefix+uid)
+	if err != nil {
+		return nil, err
+	}
+	presence.Viewers = groupDashboardUsers(viewers)
+	sort.Slice(presence.Viewers, func(i, j int) bool {
+		return presence.Viewers[i].Login < presence.Viewers[j].Login
+	})
+
+	editors, err := h.Presence(orgID, dashboardEditChannelPrefix+uid)
+	if err != nil {
+		return nil, err
+	}
+	presence.Editors = groupDashboardUsers(editors)
+	sort.Slice(presence.Editors, func(i, j int) bool {
+		if presence.Editors[i].Since != presence.Editors[j].Since {
+			return presence.Editors[i].Since < presence.Editors[j].Since
+		}
+		return presence.Editors[i].Login < presence.Editors[j].Login
+	})
+	if len(presence.Editors) > 0 {
+		presence.LockedBy = &presence.Editors[0]
+	}
+	return presence, nil
+}
+
+// groupDashboardUsers returns a user for each user of the clients, with the
+// earliest time of the clients editing.
+func groupDashboardUsers(clients []model.PresenceClient) []DashboardUser {
+	users := make([]DashboardUser, 0, len(clients))
+	index := make(map[string]int, len(clients))
+	for _, client := range clients {
+		var info editInfo
+		if len(client.ChannelInfo) > 0 {
+			if err := json.Unmarshal(client.ChannelInfo, &info); err != nil {
+				logger.Warn("Error decoding dashboard channel info", "client", client.ClientID, "error", err)
+			}
+		}
+		if i, ok := index[client.UserID]; ok {
+			users[i].Sessions++
+			if info.Since > 0 && (users[i].Since == 0 || info.Since < users[i].Since) {
+				users[i].Since = info.Since
+			}
+			continue
+		}
+		user := DashboardUser{Sessions: 1, Since: info.Since}
+		if len(client.ConnInfo) > 0 {
+			if err := json.Unmarshal(client.ConnInfo, &user.ConnInfo); err != nil {
+				logger.Warn("Error decoding connection info", "client", client.ClientID, "error", err)
+			}
+		}
+		index[client.UserID] = len(users)
+		users = append(users, user)
+	}
+	return users
+}
+
+// checkDashboardAccess returns the subscribe status of the user for the
+// dashboard and the action.
+func (h *DashboardHandler) checkDashboardAccess(ctx context.Context, user identity.Requester, uid string, action string) (backend.SubscribeStreamStatus, error) {
+	query := dashboards.GetDashboardQuery{UID: uid, OrgID: user.GetOrgID()}
+	_, err := h.DashboardService.GetDashboard(ctx, &query)
+	if err != nil {
+		logger.Error("Error getting dashboard", "query", query, "error", err)
+		return backend.SubscribeStreamStatusNotFound, nil
+	}
+
+	evaluator := accesscontrol.EvalPermission(action, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(uid))
+	ok, err := h.AccessControl.Evaluate(ctx, user, evaluator)
+	if err != nil {
+		return backend.SubscribeStreamStatusPermissionDenied, err
+	}
+	if !ok {
+		return backend.SubscribeStreamStatusPermissionDenied, nil
+	}
+	return backend.SubscribeStreamStatusOK, nil
+}
@@ -0,0 +1,66 @@
+package features
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live/model"
+)
+
+func testPresenceClient(t *testing.T, clientID string, login string, since int64) model.PresenceClient {
+	t.Helper()
+	connInfo, err := json.Marshal(model.ConnInfo{UID: "u-" + login, Login: login})
+	require.NoError(t, err)
+	client := model.PresenceClient{ClientID: clientID, UserID: login, ConnInfo: connInfo}
+	if since > 0 {
+		client.ChannelInfo, err = json.Marshal(editInfo{Since: since})
+		require.NoError(t, err)
+	}
+	return client
+}
+
+func TestDashboardPresence(t *testing.T) {
+	clients := map[string][]model.PresenceClient{
+		"grafana/dashboard/uid/abc": {
+			testPresenceClient(t, "1", "bob", 0),
+			testPresenceClient(t, "2", "alice", 0),
+			testPresenceClient(t, "3", "bob", 0),
+		},
+		"grafana/dashboard/edit/abc": {
+			testPresenceClient(t, "4", "bob", 300),
+			testPresenceClient(t, "5", "alice", 200),
+			testPresenceClient(t, "6", "bob", 100),
+		},
+	}
+	h := &DashboardHandler{
+		Presence: func(orgID int64, channel string) ([]model.PresenceClient, error) {
+			require.Equal(t, int64(1), orgID)
+			return clients[channel], nil
+		},
+	}
+
+	presence, err := h.dashboardPresence(1, "abc")
+	require.NoError(t, err)
+	require.Equal(t, "abc", presence.UID)
+	require.Len(t, presence.Viewers, 2)
+	require.Equal(t, "alice", presence.Viewers[0].Login)
+	require.Equal(t, 1, presence.Viewers[0].Sessions)
+	require.Equal(t, "bob", presence.Viewers[1].Login)
+	require.Equal(t, "u-bob", presence.Viewers[1].UID)
+	require.Equal(t, 2, presence.Viewers[1].Sessions)
+
+	// The user editing first holds the lock.
+	require.Len(t, presence.Editors, 2)
+	require.Equal(t, "bob", presence.Editors[0].Login)
+	require.Equal(t, int64(100), presence.Editors[0].Since)
+	require.Equal(t, "alice", presence.Editors[1].Login)
+	require.Equal(t, "bob", presence.LockedBy.Login)
+
+	presence, err = h.dashboardPresence(1, "other")
+	require.NoError(t, err)
+	require.Empty(t, presence.Viewers)
+	require.Empty(t, presence.Editors)
+	require.Nil(t, presence.LockedBy)
+}
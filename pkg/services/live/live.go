@@ -50,6 +50,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/live/orgchannel"
 	"github.com/grafana/grafana/pkg/services/live/pipeline"
 	"github.com/grafana/grafana/pkg/services/live/pushws"
+	"github.com/grafana/grafana/pkg/services/live/querystream"
 	"github.com/grafana/grafana/pkg/services/live/runstream"
 	"github.com/grafana/grafana/pkg/services/live/survey"
 	"github.com/grafana/grafana/pkg/services/org"
@@ -80,7 +81,7 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 	dataSourceCache datasources.CacheService, sqlStore db.DB, secretsService secrets.Service,
 	usageStatsService usagestats.Service, queryDataService query.Service, toggles featuremgmt.FeatureToggles,
 	accessControl accesscontrol.AccessControl, dashboardService dashboards.DashboardService, annotationsRepo annotations.Repository,
-	orgService org.Service, configProvider apiserver.RestConfigProvider) (*GrafanaLive, error) {
+	orgService org.Service, configProvider apiserver.RestConfigProvider, frameHistory managedstream.FrameHistory) (*GrafanaLive, error) {
 	g := &GrafanaLive{
 		Cfg:                   cfg,
 		Features:              toggles,
@@ -163,26 +164,50 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 			g.Publish,
 			channelLocalPublisher,
 			managedstream.NewRedisFrameCache(redisClient, g.keyPrefix),
+			frameHistory,
 		)
 	} else {
 		managedStreamRunner = managedstream.NewRunner(
 			g.Publish,
 			channelLocalPublisher,
 			managedstream.NewMemoryFrameCache(),
+			frameHistory,
 		)
 	}
 
 	g.ManagedStreamRunner = managedStreamRunner
 
+	if cfg.LivePipelineEnabled {
+		storage := &pipeline.FileStorage{
+			DataPath:       cfg.DataPath,
+			SecretsService: g.SecretsService,
+		}
+		g.pipelineStorage = storage
+		builder := &pipeline.StorageRuleBuilder{
+			Node:                 node,
+			ManagedStream:        g.ManagedStreamRunner,
+			FrameStorage:         pipeline.NewFrameStorage(),
+			Storage:              storage,
+			ChannelHandlerGetter: g,
+			SecretsService:       g.SecretsService,
+		}
+		g.Pipeline, err = pipeline.New(pipeline.NewCacheSegmentedTree(builder))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	g.contextGetter = liveplugin.NewContextGetter(g.PluginContextProvider, g.DataSourceCache)
 	pipelinedChannelLocalPublisher := liveplugin.NewChannelLocalPublisher(node, g.Pipeline)
 	numLocalSubscribersGetter := liveplugin.NewNumLocalSubscribersGetter(node)
 	g.runStreamManager = runstream.NewManager(pipelinedChannelLocalPublisher, numLocalSubscribersGetter, g.contextGetter)
+	g.queryStreamManager = querystream.NewManager(pipelinedChannelLocalPublisher, numLocalSubscribersGetter, g.queryDataService)
 
 	// Initialize the main features
 	dash := &features.DashboardHandler{
 		Publisher:        g.Publish,
 		ClientCount:      g.ClientCount,
+		Presence:         g.Presence,
 		Store:            sqlStore,
 		DashboardService: dashboardService,
 		AccessControl:    accessControl,
@@ -191,6 +216,7 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 	g.GrafanaScope.Dashboards = dash
 	g.GrafanaScope.Features["dashboard"] = dash
 	g.GrafanaScope.Features["broadcast"] = features.NewBroadcastRunner(g.storage)
+	g.GrafanaScope.Features[querystream.Namespace] = g.queryStreamManager
 
 	// Testing watch with just the provisioning support -- this will be removed when it is well validated
 	if toggles.IsEnabledGlobally(featuremgmt.FlagProvisioning) {
@@ -256,6 +282,11 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 			}
 		})
 
+		// Called when client unsubscribes from the channel, or disconnects.
+		client.OnUnsubscribe(func(e centrifuge.UnsubscribeEvent) {
+			g.handleOnUnsubscribe(client, e)
+		})
+
 		client.OnDisconnect(func(e centrifuge.DisconnectEvent) {
 			reason := e.Reason
 			if e.Code == 3001 { // Shutdown
@@ -303,9 +334,19 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 	g.websocketHandler = func(ctx *contextmodel.ReqContext) {
 		user := ctx.SignedInUser
 		id, _ := user.GetInternalID()
+		// Connection info is reported in the presence of the channels.
+		info, err := json.Marshal(model.ConnInfo{
+			UID:   user.GetRawIdentifier(),
+			Login: user.GetLogin(),
+			Name:  user.GetName(),
+		})
+		if err != nil {
+			logger.Error("Error encoding connection info", "error", err)
+		}
 		// Centrifuge expects Credentials in context with a current user ID.
 		cred := &centrifuge.Credentials{
 			UserID: strconv.FormatInt(id, 10),
+			Info:   info,
 		}
 		newCtx := centrifuge.SetCredentials(ctx.Req.Context(), cred)
 		newCtx = livecontext.SetContextSignedUser(newCtx, user)
@@ -423,9 +464,10 @@ type GrafanaLive struct {
 	Pipeline            *pipeline.Pipeline
 	pipelineStorage     pipeline.Storage
 
-	contextGetter    *liveplugin.ContextGetter
-	runStreamManager *runstream.Manager
-	storage          *database.Storage
+	contextGetter      *liveplugin.ContextGetter
+	runStreamManager   *runstream.Manager
+	queryStreamManager *querystream.Manager
+	storage            *database.Storage
 
 	usageStatsService usagestats.Service
 	usageStats        usageStats
@@ -445,6 +487,9 @@ type DashboardActivityChannel interface {
 	// Experimental! Indicate is GitOps is active.  This really means
 	// someone is subscribed to the `grafana/dashboards/gitops` channel
 	HasGitOpsObserver(orgID int64) bool
+
+	// Returns the users viewing and editing the dashboard
+	DashboardPresence(ctx context.Context, user identity.Requester, uid string) (*features.DashboardPresence, error)
 }
 
 func (g *GrafanaLive) getStreamPlugin(ctx context.Context, pluginID string) (backend.StreamHandler, error) {
@@ -482,6 +527,18 @@ func (g *GrafanaLive) Run(ctx context.Context) error {
 		})
 	}
 
+	if g.queryStreamManager != nil {
+		eGroup.Go(func() error {
+			return g.queryStreamManager.Run(eCtx)
+		})
+	}
+
+	if g.ManagedStreamRunner != nil {
+		eGroup.Go(func() error {
+			return g.ManagedStreamRunner.Run(eCtx)
+		})
+	}
+
 	return eGroup.Wait()
 }
 
@@ -587,7 +644,7 @@ var jsonStd = jsoniter.ConfigCompatibleWithStandardLibrary
 
 func (g *GrafanaLive) handleOnRPC(client *centrifuge.Client, e centrifuge.RPCEvent) (centrifuge.RPCReply, error) {
 	logger.Debug("Client calls RPC", "user", client.UserID(), "client", client.ID(), "method", e.Method)
-	if e.Method != "grafana.query" {
+	if e.Method != "grafana.query" && e.Method != "grafana.query.subscribe" {
 		return centrifuge.RPCReply{}, centrifuge.ErrorMethodNotFound
 	}
 	user, ok := livecontext.GetContextSignedUser(client.Context())
@@ -595,24 +652,37 @@ func (g *GrafanaLive) handleOnRPC(client *centrifuge.Client, e centrifuge.RPCEve
 		logger.Error("No user found in context", "user", client.UserID(), "client", client.ID(), "method", e.Method)
 		return centrifuge.RPCReply{}, centrifuge.ErrorInternal
 	}
-	var req dtos.MetricRequest
-	err := json.Unmarshal(e.Data, &req)
-	if err != nil {
-		return centrifuge.RPCReply{}, centrifuge.ErrorBadRequest
-	}
-	resp, err := g.queryDataService.QueryData(client.Context(), user, false, req)
-	if err != nil {
-		logger.Error("Error query data", "user", client.UserID(), "client", client.ID(), "method", e.Method, "error", err)
-		if errors.Is(err, datasources.ErrDataSourceAccessDenied) {
-			return centrifuge.RPCReply{}, &centrifuge.Error{Code: uint32(http.StatusForbidden), Message: http.StatusText(http.StatusForbidden)}
+	var result any
+	if e.Method == "grafana.query.subscribe" {
+		// Register a query run on an interval for the subscribers of the returned channel.
+		var req querystream.Request
+		err := json.Unmarshal(e.Data, &req)
+		if err != nil {
+			return centrifuge.RPCReply{}, centrifuge.ErrorBadRequest
 		}
-		var gfErr errutil.Error
-		if errors.As(err, &gfErr) && gfErr.Reason.Status() == errutil.StatusBadRequest {
-			return centrifuge.RPCReply{}, &centrifuge.Error{Code: uint32(http.StatusBadRequest), Message: http.StatusText(http.StatusBadRequest)}
+		reply, err := g.queryStreamManager.Register(client.Context(), user, req)
+		if errors.Is(err, querystream.ErrNoQueries) || errors.Is(err, querystream.ErrRefreshInterval) {
+			return centrifuge.RPCReply{}, &centrifuge.Error{Code: uint32(http.StatusBadRequest), Message: err.Error()}
 		}
-		return centrifuge.RPCReply{}, centrifuge.ErrorInternal
+		if err != nil {
+			logger.Error("Error query data", "user", client.UserID(), "client", client.ID(), "method", e.Method, "error", err)
+			return centrifuge.RPCReply{}, queryErrorToRPCError(err)
+		}
+		result = reply
+	} else {
+		var req dtos.MetricRequest
+		err := json.Unmarshal(e.Data, &req)
+		if err != nil {
+			return centrifuge.RPCReply{}, centrifuge.ErrorBadRequest
+		}
+		resp, err := g.queryDataService.QueryData(client.Context(), user, false, req)
+		if err != nil {
+			logger.Error("Error query data", "user", client.UserID(), "client", client.ID(), "method", e.Method, "error", err)
+			return centrifuge.RPCReply{}, queryErrorToRPCError(err)
+		}
+		result = resp
 	}
-	data, err := jsonStd.Marshal(resp)
+	data, err := jsonStd.Marshal(result)
 	if err != nil {
 		logger.Error("Error marshaling query response", "user", client.UserID(), "client", client.ID(), "method", e.Method, "error", err)
 		return centrifuge.RPCReply{}, centrifuge.ErrorInternal
@@ -622,6 +692,17 @@ func (g *GrafanaLive) handleOnRPC(client *centrifuge.Client, e centrifuge.RPCEve
 	}, nil
 }
 
+func queryErrorToRPCError(err error) *centrifuge.Error {
+	if errors.Is(err, datasources.ErrDataSourceAccessDenied) {
+		return &centrifuge.Error{Code: uint32(http.StatusForbidden), Message: http.StatusText(http.StatusForbidden)}
+	}
+	var gfErr errutil.Error
+	if errors.As(err, &gfErr) && gfErr.Reason.Status() == errutil.StatusBadRequest {
+		return &centrifuge.Error{Code: uint32(http.StatusBadRequest), Message: http.StatusText(http.StatusBadRequest)}
+	}
+	return centrifuge.ErrorInternal
+}
+
 func (g *GrafanaLive) handleOnSubscribe(ctx context.Context, client *centrifuge.Client, e centrifuge.SubscribeEvent) (centrifuge.SubscribeReply, error) {
 	logger.Debug("Client wants to subscribe", "user", client.UserID(), "client", client.ID(), "channel", e.Channel)
 
@@ -719,10 +800,33 @@ func (g *GrafanaLive) handleOnSubscribe(ctx context.Context, client *centrifuge.
 			PushJoinLeave:  reply.JoinLeave,
 			EnableRecovery: reply.Recover,
 			Data:           reply.Data,
+			ChannelInfo:    reply.ChannelInfo,
 		},
 	}, nil
 }
 
+// handleOnUnsubscribe notifies the channel handler tracking its subscribers.
+// The handler was used for the subscription, so it is cached.
+func (g *GrafanaLive) handleOnUnsubscribe(client *centrifuge.Client, e centrifuge.UnsubscribeEvent) {
+	user, ok := livecontext.GetContextSignedUser(client.Context())
+	if !ok {
+		return
+	}
+	_, channel, err := orgchannel.StripOrgID(e.Channel)
+	if err != nil {
+		return
+	}
+	g.channelsMu.RLock()
+	handler, ok := g.channels[channel]
+	g.channelsMu.RUnlock()
+	if !ok {
+		return
+	}
+	if h, ok := handler.(model.ChannelUnsubscribeHandler); ok {
+		h.OnUnsubscribe(client.Context(), user, model.UnsubscribeEvent{Channel: channel})
+	}
+}
+
 func (g *GrafanaLive) handleOnPublish(ctx context.Context, client *centrifuge.Client, e centrifuge.PublishEvent) (centrifuge.PublishReply, error) {
 	logger.Debug("Client wants to publish", "user", client.UserID(), "client", client.ID(), "channel", e.Channel)
 
@@ -973,6 +1077,24 @@ func (g *GrafanaLive) ClientCount(orgID int64, channel string) (int, error) {
 	return len(p.Presence), nil
 }
 
+// Presence returns the clients subscribed to a channel.
+func (g *GrafanaLive) Presence(orgID int64, channel string) ([]model.PresenceClient, error) {
+	p, err := g.node.Presence(orgchannel.PrependOrgID(orgID, channel))
+	if err != nil {
+		return nil, err
+	}
+	clients := make([]model.PresenceClient, 0, len(p.Presence))
+	for _, info := range p.Presence {
+		clients = append(clients, model.PresenceClient{
+			ClientID:    info.ClientID,
+			UserID:      info.UserID,
+			ConnInfo:    info.ConnInfo,
+			ChannelInfo: info.ChanInfo,
+		})
+	}
+	return clients, nil
+}
+
 func (g *GrafanaLive) HandleHTTPPublish(ctx *contextmodel.ReqContext) response.Response {
 	cmd := dtos.LivePublishCmd{}
 	if err := web.Bind(ctx.Req, &cmd); err != nil {
@@ -1079,6 +1201,22 @@ func (g *GrafanaLive) HandleInfoHTTP(ctx *contextmodel.ReqContext) response.Resp
 	})
 }
 
+// HandleDashboardPresenceHTTP returns the users viewing and editing a dashboard.
+func (g *GrafanaLive) HandleDashboardPresenceHTTP(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	presence, err := g.GrafanaScope.Dashboards.DashboardPresence(c.Req.Context(), c.SignedInUser, uid)
+	if errors.Is(err, dashboards.ErrDashboardNotFound) {
+		return response.Error(http.StatusNotFound, "Dashboard not found", err)
+	}
+	if errors.Is(err, features.ErrDashboardAccessDenied) {
+		return response.Error(http.StatusForbidden, "Access denied to dashboard", err)
+	}
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get dashboard presence", err)
+	}
+	return response.JSON(http.StatusOK, presence)
+}
+
 // HandleChannelRulesListHTTP ...
 func (g *GrafanaLive) HandleChannelRulesListHTTP(c *contextmodel.ReqContext) response.Response {
 	result, err := g.pipelineStorage.ListChannelRules(c.Req.Context(), c.GetOrgID())
@@ -1140,6 +1278,36 @@ func (s *DryRunRuleStorage) ListChannelRules(_ context.Context, _ int64) ([]pipe
 	return s.ChannelRules, nil
 }
 
+func (s *DryRunRuleStorage) ListChannelRuleVersions(_ context.Context, _ int64, _ pipeline.ChannelRuleVersionsCmd) ([]pipeline.ChannelRuleVersion, error) {
+	return nil, errors.New("not implemented by dry run rule storage")
+}
+
+func (s *DryRunRuleStorage) RollbackChannelRule(_ context.Context, _ int64, _ pipeline.ChannelRuleRollbackCmd) (pipeline.ChannelRule, error) {
+	return pipeline.ChannelRule{}, errors.New("not implemented by dry run rule storage")
+}
+
+// dryRunRuleBuilder builds rules to check or test them. It has its own frame
+// storage, so tests do not change the state of the running rules.
+func (g *GrafanaLive) dryRunRuleBuilder(storage pipeline.Storage) *pipeline.StorageRuleBuilder {
+	return &pipeline.StorageRuleBuilder{
+		Node:                 g.node,
+		ManagedStream:        g.ManagedStreamRunner,
+		FrameStorage:         pipeline.NewFrameStorage(),
+		Storage:              storage,
+		ChannelHandlerGetter: g,
+		SecretsService:       g.SecretsService,
+	}
+}
+
+// checkChannelRule builds the rule to check its settings, the outputs built
+// for the check are closed.
+func (g *GrafanaLive) checkChannelRule(ctx context.Context, orgID int64, rule pipeline.ChannelRule) error {
+	builder := g.dryRunRuleBuilder(g.pipelineStorage)
+	defer builder.Close()
+	_, err := builder.BuildRule(ctx, orgID, rule)
+	return err
+}
+
 // HandlePipelineConvertTestHTTP ...
 func (g *GrafanaLive) HandlePipelineConvertTestHTTP(c *contextmodel.ReqContext) response.Response {
 	body, err := io.ReadAll(c.Req.Body)
@@ -1154,14 +1322,14 @@ func (g *GrafanaLive) HandlePipelineConvertTestHTTP(c *contextmodel.ReqContext)
 	storage := &DryRunRuleStorage{
 		ChannelRules: req.ChannelRules,
 	}
-	builder := &pipeline.StorageRuleBuilder{
-		Node:                 g.node,
-		ManagedStream:        g.ManagedStreamRunner,
-		FrameStorage:         pipeline.NewFrameStorage(),
-		Storage:              storage,
-		ChannelHandlerGetter: g,
+	// The rules are built once for the request, a cached tree would keep
+	// updating them in the background.
+	builder := g.dryRunRuleBuilder(storage)
+	defer builder.Close()
+	channelRuleGetter, err := pipeline.NewRuleTree(c.Req.Context(), builder, c.GetOrgID())
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Error building channel rules", err)
 	}
-	channelRuleGetter := pipeline.NewCacheSegmentedTree(builder)
 	pipe, err := pipeline.New(channelRuleGetter)
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Error creating pipeline", err)
@@ -1196,6 +1364,10 @@ func (g *GrafanaLive) HandleChannelRulesPostHTTP(c *contextmodel.ReqContext) res
 	if err != nil {
 		return response.Error(http.StatusBadRequest, "Error decoding channel rule", err)
 	}
+	err = g.checkChannelRule(c.Req.Context(), c.GetOrgID(), pipeline.ChannelRule{Pattern: cmd.Pattern, Settings: cmd.Settings})
+	if err != nil {
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
 	rule, err := g.pipelineStorage.CreateChannelRule(c.Req.Context(), c.GetOrgID(), cmd)
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Failed to create channel rule", err)
@@ -1219,7 +1391,14 @@ func (g *GrafanaLive) HandleChannelRulesPutHTTP(c *contextmodel.ReqContext) resp
 	if cmd.Pattern == "" {
 		return response.Error(http.StatusBadRequest, "Rule pattern required", nil)
 	}
+	err = g.checkChannelRule(c.Req.Context(), c.GetOrgID(), pipeline.ChannelRule{Pattern: cmd.Pattern, Settings: cmd.Settings})
+	if err != nil {
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
 	rule, err := g.pipelineStorage.UpdateChannelRule(c.Req.Context(), c.GetOrgID(), cmd)
+	if errors.Is(err, pipeline.ErrChannelRuleVersionConflict) {
+		return response.Error(http.StatusConflict, err.Error(), err)
+	}
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Failed to update channel rule", err)
 	}
@@ -1249,6 +1428,99 @@ func (g *GrafanaLive) HandleChannelRulesDeleteHTTP(c *contextmodel.ReqContext) r
 	return response.JSON(http.StatusOK, util.DynMap{})
 }
 
+type ChannelRuleTestRequest struct {
+	Rule    pipeline.ChannelRule `json:"rule"`
+	Channel string               `json:"channel"`
+	Data    string               `json:"data"`
+}
+
+// HandleChannelRulesTestHTTP runs sample data through a channel rule, without
+// saving it, and returns the frames of each step.
+func (g *GrafanaLive) HandleChannelRulesTestHTTP(c *contextmodel.ReqContext) response.Response {
+	body, err := io.ReadAll(c.Req.Body)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Error reading body", err)
+	}
+	var req ChannelRuleTestRequest
+	err = json.Unmarshal(body, &req)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Error decoding request", err)
+	}
+	if req.Channel == "" {
+		return response.Error(http.StatusBadRequest, "Channel required", nil)
+	}
+	builder := g.dryRunRuleBuilder(g.pipelineStorage)
+	defer builder.Close()
+	rule, err := builder.BuildRule(c.Req.Context(), c.GetOrgID(), req.Rule)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
+	result, err := pipeline.DryRun(c.Req.Context(), rule, c.GetOrgID(), req.Channel, []byte(req.Data))
+	if err != nil {
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// HandleChannelRuleVersionsListHTTP returns the versions of the rule, latest first.
+func (g *GrafanaLive) HandleChannelRuleVersionsListHTTP(c *contextmodel.ReqContext) response.Response {
+	pattern := c.Query("pattern")
+	if pattern == "" {
+		return response.Error(http.StatusBadRequest, "Rule pattern required", nil)
+	}
+	versions, err := g.pipelineStorage.ListChannelRuleVersions(c.Req.Context(), c.GetOrgID(), pipeline.ChannelRuleVersionsCmd{
+		Pattern: pattern,
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get channel rule versions", err)
+	}
+	return response.JSON(http.StatusOK, util.DynMap{
+		"versions": versions,
+	})
+}
+
+// HandleChannelRulesRollbackHTTP restores a previous version of a rule.
+func (g *GrafanaLive) HandleChannelRulesRollbackHTTP(c *contextmodel.ReqContext) response.Response {
+	body, err := io.ReadAll(c.Req.Body)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Error reading body", err)
+	}
+	var cmd pipeline.ChannelRuleRollbackCmd
+	err = json.Unmarshal(body, &cmd)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Error decoding rollback command", err)
+	}
+	if cmd.Pattern == "" || cmd.Version <= 0 {
+		return response.Error(http.StatusBadRequest, "Rule pattern and version required", nil)
+	}
+	versions, err := g.pipelineStorage.ListChannelRuleVersions(c.Req.Context(), c.GetOrgID(), pipeline.ChannelRuleVersionsCmd{
+		Pattern: cmd.Pattern,
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get channel rule versions", err)
+	}
+	for _, v := range versions {
+		if v.Version != cmd.Version || v.Deleted {
+			continue
+		}
+		// The write configs used by the version may have changed since.
+		err = g.checkChannelRule(c.Req.Context(), c.GetOrgID(), pipeline.ChannelRule{Pattern: v.Pattern, Settings: v.Settings})
+		if err != nil {
+			return response.Error(http.StatusBadRequest, err.Error(), err)
+		}
+	}
+	rule, err := g.pipelineStorage.RollbackChannelRule(c.Req.Context(), c.GetOrgID(), cmd)
+	if errors.Is(err, pipeline.ErrChannelRuleVersionNotFound) {
+		return response.Error(http.StatusNotFound, err.Error(), err)
+	}
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to roll back channel rule", err)
+	}
+	return response.JSON(http.StatusOK, util.DynMap{
+		"rule": rule,
+	})
+}
+
 // HandlePipelineEntitiesListHTTP ...
 func (g *GrafanaLive) HandlePipelineEntitiesListHTTP(_ *contextmodel.ReqContext) response.Response {
 	return response.JSON(http.StatusOK, util.DynMap{
@@ -0,0 +1,128 @@
+package managedstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// FrameHistory keeps the recent frames of each channel, so they can be replayed
+// to new subscribers and queried over a time range.
+type FrameHistory interface {
+	// Add appends the full JSON frame pushed at the time to the channel history.
+	Add(ctx context.Context, orgID int64, channel string, t time.Time, frameJSON json.RawMessage) error
+	// Get returns the JSON frames pushed to the channel between from and to, oldest first.
+	Get(ctx context.Context, orgID int64, channel string, from, to time.Time) ([]json.RawMessage, error)
+}
+
+// FrameHistoryRunner is implemented by the histories removing the expired frames
+// in the background.
+type FrameHistoryRunner interface {
+	Run(ctx context.Context) error
+}
+
+// HistoryOptions bounds the history of each channel.
+type HistoryOptions struct {
+	// MaxFrames kept for each channel, no limit when 0.
+	MaxFrames int
+	// MaxAge of the frames kept for each channel, no limit when 0.
+	MaxAge time.Duration
+}
+
+// Enabled returns true when the history is bounded by at least one limit.
+func (o HistoryOptions) Enabled() bool {
+	return o.MaxFrames > 0 || o.MaxAge > 0
+}
+
+// ProvideFrameHistory returns the history configured in the [live] section, in
+// Redis with the Redis HA engine, otherwise in memory. It returns nil when the
+// history is disabled.
+func ProvideFrameHistory(cfg *setting.Cfg) FrameHistory {
+	opts := HistoryOptions{
+		MaxFrames: cfg.LiveHistoryMaxFrames,
+		MaxAge:    cfg.LiveHistoryMaxAge,
+	}
+	if !opts.Enabled() {
+		return nil
+	}
+	if cfg.LiveHAEngine == "redis" {
+		redisClient := redis.NewClient(&redis.Options{
+			Addr:     cfg.LiveHAEngineAddress,
+			Password: cfg.LiveHAEnginePassword,
+		})
+		if _, err := redisClient.Ping(context.Background()).Result(); err != nil {
+			logger.Error("Live history failed to ping redis, keeping history in memory", "error", err)
+			_ = redisClient.Close()
+		} else {
+			keyPrefix := "gf_live"
+			if cfg.LiveHAPrefix != "" {
+				keyPrefix = cfg.LiveHAPrefix + ".gf_live"
+			}
+			return NewRedisFrameHistory(redisClient, keyPrefix, opts)
+		}
+	}
+	return NewMemoryFrameHistory(opts)
+}
+
+// MergeHistoryFrames joins the history frames into a single frame with the rows
+// of every frame. Only the latest frames with the same schema as the last one
+// are kept, and at most maxRows of their rows when maxRows is positive.
+func MergeHistoryFrames(frames []json.RawMessage, maxRows int) (*data.Frame, error) {
+	if len(frames) == 0 {
+		return nil, nil
+	}
+	decoded := make([]*data.Frame, 0, len(frames))
+	for i := len(frames) - 1; i >= 0; i-- {
+		var frame data.Frame
+		if err := json.Unmarshal(frames[i], &frame); err != nil {
+			return nil, fmt.Errorf("error decoding history frame: %w", err)
+		}
+		if len(decoded) > 0 && !sameFrameSchema(decoded[0], &frame) {
+			break
+		}
+		decoded = append(decoded, &frame)
+	}
+
+	last := decoded[0]
+	merged := last.EmptyCopy()
+	rows := 0
+	// decoded holds the frames from the latest to the oldest.
+	for i := len(decoded) - 1; i >= 0; i-- {
+		rows += decoded[i].Rows()
+	}
+	skip := 0
+	if maxRows > 0 && rows > maxRows {
+		skip = rows - maxRows
+	}
+	for i := len(decoded) - 1; i >= 0; i-- {
+		frame := decoded[i]
+		for row := 0; row < frame.Rows(); row++ {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			for f, field := range frame.Fields {
+				merged.Fields[f].Append(field.At(row))
+			}
+		}
+	}
+	return merged, nil
+}
+
+func sameFrameSchema(a, b *data.Frame) bool {
+	if a.Name != b.Name || len(a.Fields) != len(b.Fields) {
+		return false
+	}
+	for i := range a.Fields {
+		if a.Fields[i].Name != b.Fields[i].Name || a.Fields[i].Type() != b.Fields[i].Type() || !a.Fields[i].Labels.Equals(b.Fields[i].Labels) {
+			return false
+		}
+	}
+	return true
+}
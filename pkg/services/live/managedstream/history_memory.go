@@ -0,0 +1,145 @@
+package managedstream
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/live/orgchannel"
+)
+
+const (
+	// historyMaxChannelBytes bounds the memory used by the history of each
+	// channel whatever the configured limits, the oldest frames are removed above it.
+	historyMaxChannelBytes = 4 << 20
+	// historySweepInterval is how often the frames older than the max age are
+	// removed from the channels without new frames.
+	historySweepInterval = time.Minute
+)
+
+// MemoryFrameHistory keeps the channel history in memory. Not usable in HA setup.
+type MemoryFrameHistory struct {
+	opts     HistoryOptions
+	maxBytes int
+	mu       sync.RWMutex
+	channels map[string]*channelHistory
+}
+
+type channelHistory struct {
+	frames []historyFrame
+	bytes  int
+}
+
+type historyFrame struct {
+	time  time.Time
+	frame json.RawMessage
+}
+
+// NewMemoryFrameHistory creates a MemoryFrameHistory.
+func NewMemoryFrameHistory(opts HistoryOptions) *MemoryFrameHistory {
+	return &MemoryFrameHistory{
+		opts:     opts,
+		maxBytes: historyMaxChannelBytes,
+		channels: map[string]*channelHistory{},
+	}
+}
+
+func (h *MemoryFrameHistory) Add(_ context.Context, orgID int64, channel string, t time.Time, frameJSON json.RawMessage) error {
+	key := orgchannel.PrependOrgID(orgID, channel)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ch, ok := h.channels[key]
+	if !ok {
+		ch = &channelHistory{}
+		h.channels[key] = ch
+	}
+	ch.frames = append(ch.frames, historyFrame{time: t, frame: frameJSON})
+	ch.bytes += len(frameJSON)
+	h.trim(key, ch, t)
+	return nil
+}
+
+// trim removes the frames of the channel above the limits at the time.
+func (h *MemoryFrameHistory) trim(key string, ch *channelHistory, t time.Time) {
+	start := 0
+	bytes := ch.bytes
+	remove := func() {
+		bytes -= len(ch.frames[start].frame)
+		start++
+	}
+	if h.opts.MaxFrames > 0 {
+		for len(ch.frames)-start > h.opts.MaxFrames {
+			remove()
+		}
+	}
+	if h.opts.MaxAge > 0 {
+		minTime := t.Add(-h.opts.MaxAge)
+		for start < len(ch.frames) && ch.frames[start].time.Before(minTime) {
+			remove()
+		}
+	}
+	// The latest frame is kept, it is also in the frame cache.
+	for start < len(ch.frames)-1 && bytes > h.maxBytes {
+		remove()
+	}
+	if start == len(ch.frames) {
+		delete(h.channels, key)
+		return
+	}
+	if start > 0 {
+		// Copy to release the memory of the removed frames.
+		ch.frames = append([]historyFrame(nil), ch.frames[start:]...)
+		ch.bytes = bytes
+	}
+}
+
+// Run removes the expired frames of every channel until the context is done,
+// so the channels without new frames do not keep their history.
+func (h *MemoryFrameHistory) Run(ctx context.Context) error {
+	if h.opts.MaxAge <= 0 {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	ticker := time.NewTicker(historySweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			h.sweep(time.Now())
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (h *MemoryFrameHistory) sweep(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for key, ch := range h.channels {
+		h.trim(key, ch, now)
+	}
+}
+
+func (h *MemoryFrameHistory) Get(_ context.Context, orgID int64, channel string, from, to time.Time) ([]json.RawMessage, error) {
+	key := orgchannel.PrependOrgID(orgID, channel)
+	if h.opts.MaxAge > 0 {
+		if minTime := time.Now().Add(-h.opts.MaxAge); from.Before(minTime) {
+			from = minTime
+		}
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	var frames []json.RawMessage
+	ch, ok := h.channels[key]
+	if !ok {
+		return nil, nil
+	}
+	for _, f := range ch.frames {
+		if f.time.Before(from) || f.time.After(to) {
+			continue
+		}
+		frames = append(frames, f.frame)
+	}
+	return frames, nil
+}
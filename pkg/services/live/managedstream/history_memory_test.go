@@ -0,0 +1,127 @@
+package managedstream
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func historyTestFrame(t *testing.T, name string, values ...float64) json.RawMessage {
+	t.Helper()
+	frame := data.NewFrame(name, data.NewField("value", nil, values))
+	frameJSON, err := data.FrameToJSON(frame, data.IncludeAll)
+	require.NoError(t, err)
+	return frameJSON
+}
+
+func TestMemoryFrameHistory_MaxFrames(t *testing.T) {
+	h := NewMemoryFrameHistory(HistoryOptions{MaxFrames: 2})
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		err := h.Add(context.Background(), 1, "stream/test/cpu", now.Add(time.Duration(i)*time.Second), historyTestFrame(t, "cpu", float64(i)))
+		require.NoError(t, err)
+	}
+
+	frames, err := h.Get(context.Background(), 1, "stream/test/cpu", time.Time{}, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, frames, 2)
+
+	frame, err := MergeHistoryFrames(frames, 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, frame.Rows())
+	require.Equal(t, 1.0, frame.Fields[0].At(0))
+	require.Equal(t, 2.0, frame.Fields[0].At(1))
+
+	// Other orgs have their own history.
+	frames, err = h.Get(context.Background(), 2, "stream/test/cpu", time.Time{}, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Empty(t, frames)
+}
+
+func TestMemoryFrameHistory_MaxAge(t *testing.T) {
+	h := NewMemoryFrameHistory(HistoryOptions{MaxAge: time.Minute})
+	now := time.Now()
+	err := h.Add(context.Background(), 1, "stream/test/cpu", now.Add(-2*time.Minute), historyTestFrame(t, "cpu", 1))
+	require.NoError(t, err)
+	err = h.Add(context.Background(), 1, "stream/test/cpu", now.Add(-30*time.Second), historyTestFrame(t, "cpu", 2))
+	require.NoError(t, err)
+	err = h.Add(context.Background(), 1, "stream/test/cpu", now, historyTestFrame(t, "cpu", 3))
+	require.NoError(t, err)
+
+	frames, err := h.Get(context.Background(), 1, "stream/test/cpu", time.Time{}, now)
+	require.NoError(t, err)
+	require.Len(t, frames, 2)
+
+	// Only the frames in the time range are returned.
+	frames, err = h.Get(context.Background(), 1, "stream/test/cpu", now.Add(-time.Minute), now.Add(-time.Second))
+	require.NoError(t, err)
+	require.Len(t, frames, 1)
+	require.JSONEq(t, string(historyTestFrame(t, "cpu", 2)), string(frames[0]))
+}
+
+func TestMergeHistoryFrames(t *testing.T) {
+	frame, err := MergeHistoryFrames(nil, 0)
+	require.NoError(t, err)
+	require.Nil(t, frame)
+
+	frames := []json.RawMessage{
+		historyTestFrame(t, "mem", 1, 2),
+		historyTestFrame(t, "cpu", 3, 4),
+		historyTestFrame(t, "cpu", 5, 6),
+	}
+
+	// Frames before a schema change are dropped.
+	frame, err = MergeHistoryFrames(frames, 0)
+	require.NoError(t, err)
+	require.Equal(t, "cpu", frame.Name)
+	require.Equal(t, 4, frame.Rows())
+
+	// The latest rows are kept.
+	frame, err = MergeHistoryFrames(frames, 3)
+	require.NoError(t, err)
+	require.Equal(t, 3, frame.Rows())
+	require.Equal(t, 4.0, frame.Fields[0].At(0))
+	require.Equal(t, 6.0, frame.Fields[0].At(2))
+}
+
+func TestMemoryFrameHistory_Sweep(t *testing.T) {
+	h := NewMemoryFrameHistory(HistoryOptions{MaxAge: time.Minute})
+	now := time.Now()
+	err := h.Add(context.Background(), 1, "stream/test/cpu", now.Add(-30*time.Second), historyTestFrame(t, "cpu", 1))
+	require.NoError(t, err)
+	err = h.Add(context.Background(), 1, "stream/test/mem", now, historyTestFrame(t, "mem", 1))
+	require.NoError(t, err)
+
+	// The channels without new frames are swept too.
+	h.sweep(now.Add(45 * time.Second))
+	require.Len(t, h.channels, 1)
+	require.Contains(t, h.channels, "1/stream/test/mem")
+	h.sweep(now.Add(2 * time.Minute))
+	require.Empty(t, h.channels)
+}
+
+func TestMemoryFrameHistory_MaxBytes(t *testing.T) {
+	h := NewMemoryFrameHistory(HistoryOptions{MaxFrames: 100})
+	frameJSON := historyTestFrame(t, "cpu", 1)
+	h.maxBytes = 3 * len(frameJSON)
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		err := h.Add(context.Background(), 1, "stream/test/cpu", now.Add(time.Duration(i)*time.Second), frameJSON)
+		require.NoError(t, err)
+	}
+	frames, err := h.Get(context.Background(), 1, "stream/test/cpu", time.Time{}, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, frames, 3)
+
+	// The latest frame is kept even above the limit.
+	h.maxBytes = 1
+	err = h.Add(context.Background(), 1, "stream/test/cpu", now.Add(time.Minute), frameJSON)
+	require.NoError(t, err)
+	frames, err = h.Get(context.Background(), 1, "stream/test/cpu", time.Time{}, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, frames, 1)
+}
@@ -0,0 +1,87 @@
+package managedstream
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/grafana/grafana/pkg/services/live/orgchannel"
+)
+
+// RedisFrameHistory keeps the channel history in a Redis sorted set per channel,
+// scored by push time in milliseconds.
+type RedisFrameHistory struct {
+	redisClient *redis.Client
+	keyPrefix   string
+	opts        HistoryOptions
+}
+
+// NewRedisFrameHistory creates a RedisFrameHistory.
+func NewRedisFrameHistory(redisClient *redis.Client, keyPrefix string, opts HistoryOptions) *RedisFrameHistory {
+	return &RedisFrameHistory{
+		redisClient: redisClient,
+		keyPrefix:   keyPrefix,
+		opts:        opts,
+	}
+}
+
+func (h *RedisFrameHistory) Add(ctx context.Context, orgID int64, channel string, t time.Time, frameJSON json.RawMessage) error {
+	key := h.getHistoryKey(orgchannel.PrependOrgID(orgID, channel))
+
+	pipe := h.redisClient.TxPipeline()
+	defer func() { _ = pipe.Close() }()
+
+	// Members of a sorted set are unique, the push time keeps equal frames apart.
+	pipe.ZAdd(ctx, key, &redis.Z{
+		Score:  float64(t.UnixMilli()),
+		Member: strconv.FormatInt(t.UnixNano(), 10) + ":" + string(frameJSON),
+	})
+	if h.opts.MaxFrames > 0 {
+		pipe.ZRemRangeByRank(ctx, key, 0, -int64(h.opts.MaxFrames)-1)
+	}
+	ttl := frameCacheTTL
+	if h.opts.MaxAge > 0 {
+		pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(t.Add(-h.opts.MaxAge).UnixMilli(), 10))
+		ttl = h.opts.MaxAge
+	}
+	pipe.Expire(ctx, key, ttl)
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (h *RedisFrameHistory) Get(ctx context.Context, orgID int64, channel string, from, to time.Time) ([]json.RawMessage, error) {
+	key := h.getHistoryKey(orgchannel.PrependOrgID(orgID, channel))
+	if h.opts.MaxAge > 0 {
+		if minTime := time.Now().Add(-h.opts.MaxAge); from.Before(minTime) {
+			from = minTime
+		}
+	}
+	minScore := "-inf"
+	if !from.IsZero() {
+		minScore = strconv.FormatInt(from.UnixMilli(), 10)
+	}
+	members, err := h.redisClient.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: minScore,
+		Max: strconv.FormatInt(to.UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	frames := make([]json.RawMessage, 0, len(members))
+	for _, m := range members {
+		_, frame, ok := strings.Cut(m, ":")
+		if ok {
+			frames = append(frames, json.RawMessage(frame))
+		}
+	}
+	return frames, nil
+}
+
+func (h *RedisFrameHistory) getHistoryKey(channelID string) string {
+	return h.keyPrefix + ".managed_stream_history." + channelID
+}
@@ -42,22 +42,35 @@ type Runner struct {
 	publisher      model.ChannelPublisher
 	localPublisher LocalPublisher
 	frameCache     FrameCache
+	frameHistory   FrameHistory
 }
 
 type LocalPublisher interface {
 	PublishLocal(channel string, data []byte) error
 }
 
-// NewRunner creates new Runner.
-func NewRunner(publisher model.ChannelPublisher, localPublisher LocalPublisher, frameCache FrameCache) *Runner {
+// NewRunner creates new Runner. The frame history is optional, when set the
+// recent frames are replayed to new subscribers.
+func NewRunner(publisher model.ChannelPublisher, localPublisher LocalPublisher, frameCache FrameCache, frameHistory FrameHistory) *Runner {
 	return &Runner{
 		publisher:      publisher,
 		localPublisher: localPublisher,
 		streams:        map[int64]map[string]*NamespaceStream{},
 		frameCache:     frameCache,
+		frameHistory:   frameHistory,
 	}
 }
 
+// Run removes the expired frames of the history in the background until the
+// context is done.
+func (r *Runner) Run(ctx context.Context) error {
+	if historyRunner, ok := r.frameHistory.(FrameHistoryRunner); ok {
+		return historyRunner.Run(ctx)
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
 func (r *Runner) GetManagedChannels(orgID int64) ([]*ManagedChannel, error) {
 	activeChannels, err := r.frameCache.GetActiveChannels(orgID)
 	if err != nil {
@@ -126,6 +139,7 @@ func (r *Runner) GetOrCreateStream(orgID int64, scope string, namespace string)
 	s, ok := r.streams[orgID][prefix]
 	if !ok {
 		s = NewNamespaceStream(orgID, scope, namespace, r.publisher, r.localPublisher, r.frameCache)
+		s.frameHistory = r.frameHistory
 		r.streams[orgID][prefix] = s
 	}
 	return s, nil
@@ -139,6 +153,7 @@ type NamespaceStream struct {
 	publisher      model.ChannelPublisher
 	localPublisher LocalPublisher
 	frameCache     FrameCache
+	frameHistory   FrameHistory
 	rateMu         sync.RWMutex
 	rates          map[string][60]rateEntry
 }
@@ -169,7 +184,7 @@ func NewNamespaceStream(orgID int64, scope string, namespace string, publisher m
 }
 
 // Push sends frame to the stream and saves it for later retrieval by subscribers.
-// * Saves the entire frame to cache.
+// * Saves the entire frame to cache, and to the history if enabled.
 // * If schema has been changed sends entire frame to channel, otherwise only data.
 func (s *NamespaceStream) Push(ctx context.Context, path string, frame *data.Frame) error {
 	jsonFrameCache, err := data.FrameToJSONCache(frame)
@@ -186,6 +201,14 @@ func (s *NamespaceStream) Push(ctx context.Context, path string, frame *data.Fra
 		return err
 	}
 
+	if s.frameHistory != nil {
+		err := s.frameHistory.Add(ctx, s.orgID, channel, time.Now(), jsonFrameCache.Bytes(data.IncludeAll))
+		if err != nil {
+			// The frame is still published, only the replay misses it.
+			logger.Error("Error adding frame to managed stream history", "error", err, "channel", channel)
+		}
+	}
+
 	// When the schema has not changed, just send the data.
 	include := data.IncludeDataOnly
 	if isUpdated {
@@ -241,6 +264,16 @@ func (s *NamespaceStream) GetHandlerForPath(_ string) (model.ChannelHandler, err
 
 func (s *NamespaceStream) OnSubscribe(ctx context.Context, u identity.Requester, e model.SubscribeEvent) (model.SubscribeReply, backend.SubscribeStreamStatus, error) {
 	reply := model.SubscribeReply{}
+	if s.frameHistory != nil {
+		// Replay the history so the subscriber does not wait for the graph to fill.
+		frameJSON, err := s.historyFrame(ctx, u.GetOrgID(), e.Channel)
+		if err != nil {
+			logger.Error("Error getting managed stream history", "error", err, "channel", e.Channel)
+		} else if frameJSON != nil {
+			reply.Data = frameJSON
+			return reply, backend.SubscribeStreamStatusOK, nil
+		}
+	}
 	frameJSON, ok, err := s.frameCache.GetFrame(ctx, u.GetOrgID(), e.Channel)
 	if err != nil {
 		return reply, 0, err
@@ -251,6 +284,19 @@ func (s *NamespaceStream) OnSubscribe(ctx context.Context, u identity.Requester,
 	return reply, backend.SubscribeStreamStatusOK, nil
 }
 
+// historyFrame returns the frames of the channel history merged in a single frame.
+func (s *NamespaceStream) historyFrame(ctx context.Context, orgID int64, channel string) (json.RawMessage, error) {
+	frames, err := s.frameHistory.Get(ctx, orgID, channel, time.Time{}, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	frame, err := MergeHistoryFrames(frames, 0)
+	if err != nil || frame == nil {
+		return nil, err
+	}
+	return data.FrameToJSON(frame, data.IncludeAll)
+}
+
 func (s *NamespaceStream) OnPublish(_ context.Context, _ identity.Requester, _ model.PublishEvent) (model.PublishReply, backend.PublishStreamStatus, error) {
 	return model.PublishReply{}, backend.PublishStreamStatusPermissionDenied, nil
 }
@@ -48,7 +48,7 @@ func TestManagedStreamMinuteRate(t *testing.T) {
 func TestGetManagedStreams(t *testing.T) {
 	publisher := &testPublisher{t: t}
 	frameCache := NewMemoryFrameCache()
-	runner := NewRunner(publisher.publish, nil, frameCache)
+	runner := NewRunner(publisher.publish, nil, frameCache, nil)
 	s1, err := runner.GetOrCreateStream(1, "stream", "test1")
 	require.NoError(t, err)
 	s2, err := runner.GetOrCreateStream(1, "stream", "test2")
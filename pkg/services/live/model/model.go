@@ -16,6 +16,26 @@ type ChannelPublisher func(orgID int64, channel string, data []byte) error
 // ChannelClientCount will return the number of clients for a channel
 type ChannelClientCount func(orgID int64, channel string) (int, error)
 
+// ChannelPresence will return the clients subscribed to a channel.
+type ChannelPresence func(orgID int64, channel string) ([]PresenceClient, error)
+
+// PresenceClient is a client subscribed to a channel.
+type PresenceClient struct {
+	ClientID string
+	UserID   string
+	// ConnInfo is set for the connection, see ConnInfo.
+	ConnInfo json.RawMessage
+	// ChannelInfo is set by the channel handler when the client subscribed.
+	ChannelInfo json.RawMessage
+}
+
+// ConnInfo is attached to connections and reported in channel presence.
+type ConnInfo struct {
+	UID   string `json:"uid,omitempty"`
+	Login string `json:"login,omitempty"`
+	Name  string `json:"name,omitempty"`
+}
+
 // SubscribeEvent contains subscription data.
 type SubscribeEvent struct {
 	Channel string
@@ -23,12 +43,19 @@ type SubscribeEvent struct {
 	Data    json.RawMessage
 }
 
+// UnsubscribeEvent contains the channel a client unsubscribed from.
+type UnsubscribeEvent struct {
+	Channel string
+}
+
 // SubscribeReply is a reaction to SubscribeEvent.
 type SubscribeReply struct {
 	Presence  bool
 	JoinLeave bool
 	Recover   bool
 	Data      json.RawMessage
+	// ChannelInfo is reported in the presence and join events of the client.
+	ChannelInfo json.RawMessage
 }
 
 // PublishEvent contains publication data.
@@ -60,6 +87,14 @@ type ChannelHandler interface {
 	OnPublish(ctx context.Context, user identity.Requester, e PublishEvent) (PublishReply, backend.PublishStreamStatus, error)
 }
 
+// ChannelUnsubscribeHandler is implemented by the channel handlers that track
+// their subscribers.
+type ChannelUnsubscribeHandler interface {
+	// OnUnsubscribe is called when a client subscribed to the channel unsubscribes
+	// or disconnects.
+	OnUnsubscribe(ctx context.Context, user identity.Requester, e UnsubscribeEvent)
+}
+
 // ChannelHandlerFactory should be implemented by all core features.
 type ChannelHandlerFactory interface {
 	// GetHandlerForPath gets a ChannelHandler for a path.
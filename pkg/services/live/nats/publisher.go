@@ -0,0 +1,160 @@
+// Package nats publishes messages to a NATS server with the nats.go client.
+// Only publishing is supported, there are no subscriptions.
+package nats
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+var logger = log.New("live.nats")
+
+const defaultPort = "4222"
+
+// Options of the connection to the server.
+type Options struct {
+	// User and Password when the server requires authentication. They are only
+	// sent over TLS, setting them requires TLS even for a nats:// address.
+	User     string
+	Password string
+	// TLSConfig of the TLS connection, the system roots are used when not set.
+	TLSConfig *tls.Config
+	// Timeout of the connection and of each publish, 5 seconds when not set.
+	Timeout time.Duration
+}
+
+// Publisher publishes messages to a NATS server. The connection is opened on the
+// first publish, the client reconnects on its own after an error and the
+// connection is opened again on the next publish once the client gave up. It is
+// safe for concurrent use.
+type Publisher struct {
+	url  string
+	opts Options
+
+	mu     sync.Mutex
+	conn   *nats.Conn
+	closed bool
+}
+
+// ErrPublisherClosed is returned by the publishes after Close.
+var ErrPublisherClosed = errors.New("NATS publisher closed")
+
+// NewPublisher creates a Publisher for the server address, as nats://host:port,
+// tls://host:port or host:port.
+func NewPublisher(address string, opts Options) (*Publisher, error) {
+	u, err := parseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	return &Publisher{url: u, opts: opts}, nil
+}
+
+func parseAddress(address string) (string, error) {
+	if address == "" {
+		return "", errors.New("missing NATS server address")
+	}
+	if !strings.Contains(address, "://") {
+		address = "nats://" + address
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid NATS server address: %w", err)
+	}
+	if u.Scheme != "nats" && u.Scheme != "tls" {
+		return "", fmt.Errorf("unsupported NATS server address scheme: %s", u.Scheme)
+	}
+	if u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), defaultPort)
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
+
+// Publish sends the payload to the subject and waits for the server to process
+// it, so the errors of the server, like a permission violation, are returned.
+func (p *Publisher) Publish(ctx context.Context, subject string, payload []byte) error {
+	if subject == "" || strings.ContainsAny(subject, " \t\r\n") {
+		return fmt.Errorf("invalid NATS subject: %q", subject)
+	}
+
+	// Publishes are serialized so an error of the server is returned to the
+	// publish it belongs to.
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return ErrPublisherClosed
+	}
+	if p.conn == nil || p.conn.IsClosed() {
+		conn, err := p.connect()
+		if err != nil {
+			return err
+		}
+		p.conn = conn
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.opts.Timeout)
+	defer cancel()
+	lastErr := p.conn.LastError()
+	if err := p.conn.Publish(subject, payload); err != nil {
+		return fmt.Errorf("error publishing to NATS: %w", err)
+	}
+	if err := p.conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("error publishing to NATS: %w", err)
+	}
+	// The server errors are processed before the reply to the flush, they are
+	// reported by the last error of the connection.
+	if err := p.conn.LastError(); err != nil && err != lastErr { //nolint:errorlint
+		return fmt.Errorf("error publishing to NATS: %w", err)
+	}
+	return nil
+}
+
+// Close closes the connection to the server, the publisher is not usable after it.
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+	}
+	return nil
+}
+
+func (p *Publisher) connect() (*nats.Conn, error) {
+	options := []nats.Option{
+		nats.Name("grafana-live"),
+		nats.Timeout(p.opts.Timeout),
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+			logger.Error("NATS server error", "address", p.url, "error", err)
+		}),
+	}
+	if p.opts.TLSConfig != nil || p.opts.User != "" || strings.HasPrefix(p.url, "tls://") {
+		tlsConfig := p.opts.TLSConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		options = append(options, nats.Secure(tlsConfig))
+	}
+	if p.opts.User != "" {
+		options = append(options, nats.UserInfo(p.opts.User, p.opts.Password))
+	}
+	conn, err := nats.Connect(p.url, options...)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to NATS: %w", err)
+	}
+	return conn, nil
+}
@@ -0,0 +1,217 @@
+package nats
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+)
+
+func runServer(t *testing.T, configure func(opts *server.Options)) *server.Server {
+	t.Helper()
+	opts := natsserver.DefaultTestOptions
+	opts.Port = server.RANDOM_PORT
+	opts.MaxPayload = 1024
+	if configure != nil {
+		configure(&opts)
+	}
+	s := natsserver.RunServer(&opts)
+	t.Cleanup(s.Shutdown)
+	return s
+}
+
+func serverAddress(scheme string, s *server.Server) string {
+	return scheme + "://127.0.0.1:" + strconv.Itoa(s.Addr().(*net.TCPAddr).Port)
+}
+
+// subscribe returns the messages published to the subject.
+func subscribe(t *testing.T, s *server.Server, subject string, options ...nats.Option) chan *nats.Msg {
+	t.Helper()
+	conn, err := nats.Connect(serverAddress("nats", s), options...)
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+	messages := make(chan *nats.Msg, 10)
+	_, err = conn.ChanSubscribe(subject, messages)
+	require.NoError(t, err)
+	require.NoError(t, conn.Flush())
+	return messages
+}
+
+func nextMessage(t *testing.T, messages chan *nats.Msg) *nats.Msg {
+	t.Helper()
+	select {
+	case m := <-messages:
+		return m
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+		return nil
+	}
+}
+
+// testTLSConfigs returns the TLS configs of a server with a self-signed
+// certificate, and of a client trusting it.
+func testTLSConfigs(t *testing.T) (*tls.Config, *tls.Config) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	serverConfig := &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+		MinVersion:   tls.VersionTLS12,
+	}
+	return serverConfig, &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+}
+
+func TestPublisher_Publish(t *testing.T) {
+	s := runServer(t, nil)
+	messages := subscribe(t, s, "grafana.>")
+
+	p, err := NewPublisher(serverAddress("nats", s), Options{})
+	require.NoError(t, err)
+	defer func() { _ = p.Close() }()
+
+	err = p.Publish(context.Background(), "grafana.live.1.stream.test.cpu", []byte(`{"a":1}`))
+	require.NoError(t, err)
+	m := nextMessage(t, messages)
+	require.Equal(t, "grafana.live.1.stream.test.cpu", m.Subject)
+	require.Equal(t, `{"a":1}`, string(m.Data))
+
+	err = p.Publish(context.Background(), "invalid subject", []byte(`{}`))
+	require.Error(t, err)
+
+	// The max payload of the server is enforced.
+	err = p.Publish(context.Background(), "grafana.big", make([]byte, 2048))
+	require.ErrorIs(t, err, nats.ErrMaxPayload)
+}
+
+func TestPublisher_TLS(t *testing.T) {
+	serverTLS, clientTLS := testTLSConfigs(t)
+	s := runServer(t, func(opts *server.Options) {
+		opts.TLSConfig = serverTLS
+		opts.Username = "grafana"
+		opts.Password = "secret"
+	})
+	messages := subscribe(t, s, "test", nats.Secure(clientTLS), nats.UserInfo("grafana", "secret"))
+
+	t.Run("publishes with the credentials over TLS", func(t *testing.T) {
+		p, err := NewPublisher(serverAddress("nats", s), Options{User: "grafana", Password: "secret", TLSConfig: clientTLS})
+		require.NoError(t, err)
+		defer func() { _ = p.Close() }()
+
+		require.NoError(t, p.Publish(context.Background(), "test", []byte("1")))
+		require.Equal(t, "1", string(nextMessage(t, messages).Data))
+	})
+
+	t.Run("verifies the server certificate", func(t *testing.T) {
+		p, err := NewPublisher(serverAddress("tls", s), Options{User: "grafana", Password: "secret"})
+		require.NoError(t, err)
+		defer func() { _ = p.Close() }()
+
+		err = p.Publish(context.Background(), "test", []byte("1"))
+		require.ErrorContains(t, err, "error connecting to NATS")
+	})
+
+	t.Run("fails with wrong credentials", func(t *testing.T) {
+		p, err := NewPublisher(serverAddress("tls", s), Options{User: "grafana", Password: "wrong", TLSConfig: clientTLS})
+		require.NoError(t, err)
+		defer func() { _ = p.Close() }()
+
+		err = p.Publish(context.Background(), "test", []byte("1"))
+		require.ErrorIs(t, err, nats.ErrAuthorization)
+	})
+}
+
+func TestPublisher_RequiresTLSForCredentials(t *testing.T) {
+	s := runServer(t, nil)
+	p, err := NewPublisher(serverAddress("nats", s), Options{User: "grafana", Password: "secret"})
+	require.NoError(t, err)
+	defer func() { _ = p.Close() }()
+
+	err = p.Publish(context.Background(), "test", []byte("1"))
+	require.ErrorIs(t, err, nats.ErrSecureConnWanted)
+}
+
+func TestPublisher_PermissionViolation(t *testing.T) {
+	s := runServer(t, func(opts *server.Options) {
+		opts.Users = []*server.User{{
+			Username: "grafana",
+			Password: "secret",
+			Permissions: &server.Permissions{
+				Publish: &server.SubjectPermission{Allow: []string{"grafana.>"}},
+			},
+		}}
+	})
+	p, err := NewPublisher(serverAddress("nats", s), Options{})
+	require.NoError(t, err)
+	defer func() { _ = p.Close() }()
+	// The user is set on the connection without the TLS requirement of the options.
+	p.conn, err = nats.Connect(serverAddress("nats", s), nats.UserInfo("grafana", "secret"))
+	require.NoError(t, err)
+
+	require.NoError(t, p.Publish(context.Background(), "grafana.test", []byte("1")))
+	err = p.Publish(context.Background(), "other.test", []byte("1"))
+	require.ErrorIs(t, err, nats.ErrPermissionViolation)
+	require.NoError(t, p.Publish(context.Background(), "grafana.test", []byte("2")))
+}
+
+func TestPublisher_Reconnect(t *testing.T) {
+	s := runServer(t, nil)
+	p, err := NewPublisher(serverAddress("nats", s), Options{})
+	require.NoError(t, err)
+	defer func() { _ = p.Close() }()
+	require.NoError(t, p.Publish(context.Background(), "test", []byte("1")))
+
+	// The connection is closed, the next publish opens it again.
+	p.conn.Close()
+	messages := subscribe(t, s, "test")
+	require.NoError(t, p.Publish(context.Background(), "test", []byte("2")))
+	require.Equal(t, "2", string(nextMessage(t, messages).Data))
+}
+
+func TestParseAddress(t *testing.T) {
+	addr, err := parseAddress("nats://localhost:4223")
+	require.NoError(t, err)
+	require.Equal(t, "nats://localhost:4223", addr)
+
+	addr, err = parseAddress("localhost")
+	require.NoError(t, err)
+	require.Equal(t, "nats://localhost:4222", addr)
+
+	addr, err = parseAddress("tls://localhost")
+	require.NoError(t, err)
+	require.Equal(t, "tls://localhost:4222", addr)
+
+	_, err = parseAddress("http://localhost:4222")
+	require.Error(t, err)
+}
@@ -32,6 +32,8 @@ type ChannelRule struct {
 	OrgId    int64               `json:"-"`
 	Pattern  string              `json:"pattern"`
 	Settings ChannelRuleSettings `json:"settings"`
+	// Version is incremented on each change of the rule.
+	Version int64 `json:"version,omitempty"`
 }
 
 type ConverterConfig struct {
@@ -40,6 +42,9 @@ type ConverterConfig struct {
 	ExactJsonConverterConfig  *ExactJsonConverterConfig  `json:"jsonExact,omitempty"`
 	AutoInfluxConverterConfig *AutoInfluxConverterConfig `json:"influxAuto,omitempty"`
 	JsonFrameConverterConfig  *JsonFrameConverterConfig  `json:"jsonFrame,omitempty"`
+	PrometheusConverterConfig *PrometheusConverterConfig `json:"prometheus,omitempty"`
+	OTLPConverterConfig       *OTLPConverterConfig       `json:"otlp,omitempty"`
+	CSVConverterConfig        *CSVConverterConfig        `json:"csv,omitempty"`
 }
 
 type DropFieldsFrameProcessorConfig struct {
@@ -50,11 +55,44 @@ type KeepFieldsFrameProcessorConfig struct {
 	FieldNames []string `json:"fieldNames"`
 }
 
+type RenameFieldsFrameProcessorConfig struct {
+	// Names maps the current field names to the new ones.
+	Names map[string]string `json:"names"`
+}
+
+type LabelsFrameProcessorConfig struct {
+	// Labels to add, values are templates like {{ .Path }}.
+	Labels map[string]string `json:"labels"`
+	// FieldNames to label, all the value fields when empty.
+	FieldNames []string `json:"fieldNames,omitempty"`
+}
+
+type DerivedFieldFrameProcessorConfig struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+}
+
+type DownsampleFrameProcessorConfig struct {
+	IntervalMilliseconds int64 `json:"intervalMilliseconds"`
+	// Aggregation is avg (default) or max.
+	Aggregation string `json:"aggregation,omitempty"`
+}
+
+type RateLimitFrameProcessorConfig struct {
+	FramesPerSecond float64 `json:"framesPerSecond"`
+	Burst           int     `json:"burst,omitempty"`
+}
+
 type FrameProcessorConfig struct {
-	Type                      string                          `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
-	DropFieldsProcessorConfig *DropFieldsFrameProcessorConfig `json:"dropFields,omitempty"`
-	KeepFieldsProcessorConfig *KeepFieldsFrameProcessorConfig `json:"keepFields,omitempty"`
-	MultipleProcessorConfig   *MultipleFrameProcessorConfig   `json:"multiple,omitempty"`
+	Type                        string                            `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
+	DropFieldsProcessorConfig   *DropFieldsFrameProcessorConfig   `json:"dropFields,omitempty"`
+	KeepFieldsProcessorConfig   *KeepFieldsFrameProcessorConfig   `json:"keepFields,omitempty"`
+	MultipleProcessorConfig     *MultipleFrameProcessorConfig     `json:"multiple,omitempty"`
+	RenameFieldsProcessorConfig *RenameFieldsFrameProcessorConfig `json:"renameFields,omitempty"`
+	LabelsProcessorConfig       *LabelsFrameProcessorConfig       `json:"labels,omitempty"`
+	DerivedFieldProcessorConfig *DerivedFieldFrameProcessorConfig `json:"derivedField,omitempty"`
+	DownsampleProcessorConfig   *DownsampleFrameProcessorConfig   `json:"downsample,omitempty"`
+	RateLimitProcessorConfig    *RateLimitFrameProcessorConfig    `json:"rateLimit,omitempty"`
 }
 
 type MultipleFrameProcessorConfig struct {
@@ -79,6 +117,26 @@ type LokiOutputConfig struct {
 	UID string `json:"uid"`
 }
 
+type WebhookOutputConfig struct {
+	// UID of the write config with the webhook endpoint.
+	UID string `json:"uid"`
+	// BatchSize is the max number of frames sent in one request, 100 when not set.
+	BatchSize int `json:"batchSize,omitempty"`
+	// FlushMilliseconds is the max time a frame waits for its batch, 1000 when not set.
+	FlushMilliseconds int64 `json:"flushMilliseconds,omitempty"`
+	// MaxRetries of a failed request before the batch is dropped, 3 when not set.
+	MaxRetries int `json:"maxRetries,omitempty"`
+}
+
+type NATSOutputConfig struct {
+	// UID of the write config with the NATS server address, as nats://host:port
+	// or tls://host:port. The basic auth credentials are only sent over TLS.
+	UID string `json:"uid"`
+	// Subject to publish frames to. When not set frames are published to
+	// grafana.live.<orgId>.<channel> with the channel slashes replaced by dots.
+	Subject string `json:"subject,omitempty"`
+}
+
 type MultipleSubscriberConfig struct {
 	Subscribers []SubscriberConfig `json:"subscribers"`
 }
@@ -109,6 +167,8 @@ type FrameOutputterConfig struct {
 	RemoteWriteOutputConfig *RemoteWriteOutputConfig   `json:"remoteWrite,omitempty"`
 	LokiOutputConfig        *LokiOutputConfig          `json:"loki,omitempty"`
 	ChangeLogOutputConfig   *ChangeLogOutputConfig     `json:"changeLog,omitempty"`
+	WebhookOutputConfig     *WebhookOutputConfig       `json:"webhook,omitempty"`
+	NATSOutputConfig        *NATSOutputConfig          `json:"nats,omitempty"`
 }
 
 type MultipleFrameConditionCheckerConfig struct {
@@ -152,4 +212,25 @@ type AutoInfluxConverterConfig struct {
 
 type JsonFrameConverterConfig struct{}
 
+type PrometheusConverterConfig struct{}
+
+type OTLPConverterConfig struct {
+	// ResourceAttributes to add as labels, all of them when empty.
+	ResourceAttributes []string `json:"resourceAttributes,omitempty"`
+}
+
+type CSVConverterConfig struct {
+	// Columns names, when empty the first line is the header.
+	Columns []string `json:"columns,omitempty"`
+	// Delimiter between the values, a comma by default.
+	Delimiter string `json:"delimiter,omitempty"`
+	// TimeColumn holds the time of each line, "time" by default.
+	// The time of conversion is used when the column is missing.
+	TimeColumn string `json:"timeColumn,omitempty"`
+	// TimeFormat is one of rfc3339, unix or unix_ms. Detected when empty.
+	TimeFormat string `json:"timeFormat,omitempty"`
+	// LabelColumns are set as labels of the values in the same line.
+	LabelColumns []string `json:"labelColumns,omitempty"`
+}
+
 type ManagedStreamOutputConfig struct{}
@@ -0,0 +1,157 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// CSVConverter decodes CSV lines into a single frame with a time field and a
+// field for each value column and label set. The columns listed as labels set
+// the labels of the values in the same line, every other column must hold numbers.
+type CSVConverter struct {
+	config      CSVConverterConfig
+	nowTimeFunc func() time.Time
+}
+
+func NewCSVConverter(c CSVConverterConfig) *CSVConverter {
+	return &CSVConverter{config: c}
+}
+
+const ConverterTypeCSV = "csv"
+
+func (c *CSVConverter) Type() string {
+	return ConverterTypeCSV
+}
+
+func (c *CSVConverter) Convert(_ context.Context, vars Vars, body []byte) ([]*ChannelFrame, error) {
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	if c.config.Delimiter != "" {
+		r := []rune(c.config.Delimiter)
+		if len(r) != 1 {
+			return nil, fmt.Errorf("invalid delimiter: %q", c.config.Delimiter)
+		}
+		reader.Comma = r[0]
+	}
+
+	columns := c.config.Columns
+	if len(columns) == 0 {
+		header, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("error reading header: %w", err)
+		}
+		columns = header
+	}
+	timeColumn := c.config.TimeColumn
+	if timeColumn == "" {
+		timeColumn = "time"
+	}
+
+	nowTimeFunc := c.nowTimeFunc
+	if nowTimeFunc == nil {
+		nowTimeFunc = time.Now
+	}
+	now := nowTimeFunc()
+
+	frames := newMetricFrames()
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading line: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) != len(columns) {
+			return nil, fmt.Errorf("line %d: expected %d columns, got %d", line, len(columns), len(record))
+		}
+
+		ts := now
+		labels := data.Labels{}
+		for i, name := range columns {
+			switch {
+			case name == timeColumn:
+				ts, err = parseCSVTime(record[i], c.config.TimeFormat)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", line, err)
+				}
+			case slices.Contains(c.config.LabelColumns, name):
+				labels[name] = record[i]
+			}
+		}
+		for i, name := range columns {
+			if name == timeColumn || slices.Contains(c.config.LabelColumns, name) {
+				continue
+			}
+			var value *float64
+			if s := strings.TrimSpace(record[i]); s != "" {
+				v, err := strconv.ParseFloat(s, 64)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid number in column %s: %q", line, name, record[i])
+				}
+				value = &v
+			}
+			frames.add("", vars.Path, metricSample{Name: name, Labels: labels, Time: ts, Value: value})
+		}
+	}
+	if len(frames.keys()) == 0 {
+		return nil, nil
+	}
+	return []*ChannelFrame{
+		{Channel: "", Frame: frames.frame("")},
+	}, nil
+}
+
+// parseCSVTime parses the time in the format: rfc3339, unix (seconds), unix_ms,
+// or by default RFC3339 or a unix time in seconds or milliseconds depending on its size.
+func parseCSVTime(s string, format string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	switch format {
+	case "rfc3339":
+		return parseCSVTimeRFC3339(s)
+	case "unix":
+		return parseCSVTimeUnix(s, time.Second)
+	case "unix_ms":
+		return parseCSVTimeUnix(s, time.Millisecond)
+	case "":
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return parseCSVTimeRFC3339(s)
+		}
+		// 1e11 seconds is year 5138, bigger values are milliseconds.
+		if v >= 1e11 {
+			return parseCSVTimeUnix(s, time.Millisecond)
+		}
+		return parseCSVTimeUnix(s, time.Second)
+	default:
+		return time.Time{}, fmt.Errorf("unknown time format: %s", format)
+	}
+}
+
+func parseCSVTimeRFC3339(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time: %q", s)
+	}
+	return t.UTC(), nil
+}
+
+func parseCSVTimeUnix(s string, unit time.Duration) (time.Time, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time: %q", s)
+	}
+	return time.Unix(0, int64(v*float64(unit))).UTC(), nil
+}
@@ -0,0 +1,81 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVConverter_Convert(t *testing.T) {
+	body := []byte(`time,host,cpu,mem
+2021-01-01T00:00:00Z,a,0.5,100
+2021-01-01T00:00:00Z,b,0.7,
+2021-01-01T00:00:10Z,a,0.6,110
+`)
+	converter := NewCSVConverter(CSVConverterConfig{LabelColumns: []string{"host"}})
+	channelFrames, err := converter.Convert(context.Background(), Vars{Path: "hosts"}, body)
+	require.NoError(t, err)
+	require.Len(t, channelFrames, 1)
+	require.Empty(t, channelFrames[0].Channel)
+
+	frame := channelFrames[0].Frame
+	require.Equal(t, "hosts", frame.Name)
+	require.Len(t, frame.Fields, 5)
+	require.Equal(t, 2, frame.Rows())
+	require.Equal(t, time.Date(2021, 1, 1, 0, 0, 10, 0, time.UTC), frame.Fields[0].At(1))
+
+	values := map[string][]*float64{}
+	for _, f := range frame.Fields[1:] {
+		key := f.Name + f.Labels.String()
+		for i := 0; i < f.Len(); i++ {
+			values[key] = append(values[key], f.At(i).(*float64))
+		}
+	}
+	require.Equal(t, map[string][]*float64{
+		"cpu" + data.Labels{"host": "a"}.String(): {ptr(0.5), ptr(0.6)},
+		"mem" + data.Labels{"host": "a"}.String(): {ptr(100.0), ptr(110.0)},
+		"cpu" + data.Labels{"host": "b"}.String(): {ptr(0.7), nil},
+		"mem" + data.Labels{"host": "b"}.String(): {nil, nil},
+	}, values)
+}
+
+func TestCSVConverter_Convert_columns(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	converter := NewCSVConverter(CSVConverterConfig{
+		Columns:    []string{"ts", "value"},
+		Delimiter:  ";",
+		TimeColumn: "ts",
+		TimeFormat: "unix_ms",
+	})
+	converter.nowTimeFunc = func() time.Time { return now }
+
+	channelFrames, err := converter.Convert(context.Background(), Vars{}, []byte("1609459200000;1\n1609459201000;2\n"))
+	require.NoError(t, err)
+	frame := channelFrames[0].Frame
+	require.Equal(t, 2, frame.Rows())
+	require.Equal(t, time.Date(2021, 1, 1, 0, 0, 1, 0, time.UTC), frame.Fields[0].At(1))
+
+	_, err = converter.Convert(context.Background(), Vars{}, []byte("1609459200000;high\n"))
+	require.ErrorContains(t, err, "invalid number in column value")
+
+	_, err = converter.Convert(context.Background(), Vars{}, []byte("1609459200000\n"))
+	require.ErrorContains(t, err, "expected 2 columns")
+}
+
+func TestParseCSVTime(t *testing.T) {
+	expected := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, s := range []string{"2021-01-01T00:00:00Z", "1609459200", "1609459200000"} {
+		ts, err := parseCSVTime(s, "")
+		require.NoError(t, err)
+		require.Equal(t, expected, ts, s)
+	}
+	_, err := parseCSVTime("yesterday", "")
+	require.Error(t, err)
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
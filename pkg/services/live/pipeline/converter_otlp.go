@@ -0,0 +1,188 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+)
+
+// OTLPConverter decodes OpenTelemetry metrics sent with OTLP/HTTP, in protobuf
+// or JSON encoding, and transforms them to a ChannelFrame for each metric name,
+// where Channel is constructed from original channel + / + <metric_name>.
+// Data point attributes become labels, histograms and summaries are split into
+// the _bucket, _sum and _count series like Prometheus does.
+type OTLPConverter struct {
+	config OTLPConverterConfig
+}
+
+func NewOTLPConverter(c OTLPConverterConfig) *OTLPConverter {
+	return &OTLPConverter{config: c}
+}
+
+const ConverterTypeOTLP = "otlp"
+
+func (c *OTLPConverter) Type() string {
+	return ConverterTypeOTLP
+}
+
+func (c *OTLPConverter) Convert(_ context.Context, vars Vars, body []byte) ([]*ChannelFrame, error) {
+	req := pmetricotlp.NewExportRequest()
+	var err error
+	// JSON starts with an object, a protobuf message never starts with '{'
+	// as it is not a valid field tag of ExportMetricsServiceRequest.
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		err = req.UnmarshalJSON(body)
+	} else {
+		err = req.UnmarshalProto(body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing metrics: %w", err)
+	}
+
+	frames := newMetricFrames()
+	resourceMetrics := req.Metrics().ResourceMetrics()
+	for i := 0; i < resourceMetrics.Len(); i++ {
+		rm := resourceMetrics.At(i)
+		resourceLabels := c.resourceLabels(rm.Resource().Attributes())
+		scopeMetrics := rm.ScopeMetrics()
+		for j := 0; j < scopeMetrics.Len(); j++ {
+			metrics := scopeMetrics.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				m := metrics.At(k)
+				for _, s := range otlpSamples(m, resourceLabels) {
+					frames.add(m.Name(), m.Name(), s)
+				}
+			}
+		}
+	}
+	return channelFramesByMetric(vars.Channel, frames), nil
+}
+
+// resourceLabels returns the resource attributes added to the labels of every data point.
+func (c *OTLPConverter) resourceLabels(attrs pcommon.Map) data.Labels {
+	labels := data.Labels{}
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		if len(c.config.ResourceAttributes) == 0 || slices.Contains(c.config.ResourceAttributes, k) {
+			labels[k] = v.AsString()
+		}
+		return true
+	})
+	return labels
+}
+
+func otlpLabels(resourceLabels data.Labels, attrs pcommon.Map) data.Labels {
+	labels := resourceLabels.Copy()
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		labels[k] = v.AsString()
+		return true
+	})
+	return labels
+}
+
+func otlpSamples(m pmetric.Metric, resourceLabels data.Labels) []metricSample {
+	name := m.Name()
+	var samples []metricSample
+	switch m.Type() {
+	case pmetric.MetricTypeGauge, pmetric.MetricTypeSum:
+		var points pmetric.NumberDataPointSlice
+		if m.Type() == pmetric.MetricTypeGauge {
+			points = m.Gauge().DataPoints()
+		} else {
+			points = m.Sum().DataPoints()
+		}
+		for i := 0; i < points.Len(); i++ {
+			p := points.At(i)
+			if p.Flags().NoRecordedValue() {
+				continue
+			}
+			v := p.DoubleValue()
+			if p.ValueType() == pmetric.NumberDataPointValueTypeInt {
+				v = float64(p.IntValue())
+			}
+			samples = append(samples, metricSample{
+				Name:   name,
+				Labels: otlpLabels(resourceLabels, p.Attributes()),
+				Time:   p.Timestamp().AsTime().UTC(),
+				Value:  sampleValue(v),
+			})
+		}
+	case pmetric.MetricTypeHistogram:
+		points := m.Histogram().DataPoints()
+		for i := 0; i < points.Len(); i++ {
+			p := points.At(i)
+			if p.Flags().NoRecordedValue() {
+				continue
+			}
+			labels := otlpLabels(resourceLabels, p.Attributes())
+			ts := p.Timestamp().AsTime().UTC()
+			// OTLP buckets hold the count between two bounds, the series hold the
+			// cumulative count of each upper bound, the last one being +Inf.
+			var cumulative uint64
+			bounds := p.ExplicitBounds()
+			counts := p.BucketCounts()
+			for b := 0; b < counts.Len(); b++ {
+				cumulative += counts.At(b)
+				le := math.Inf(1)
+				if b < bounds.Len() {
+					le = bounds.At(b)
+				}
+				samples = append(samples, metricSample{
+					Name:   name + "_bucket",
+					Labels: withLabel(labels, "le", formatFloatLabel(le)),
+					Time:   ts,
+					Value:  sampleValue(float64(cumulative)),
+				})
+			}
+			if p.HasSum() {
+				samples = append(samples, metricSample{Name: name + "_sum", Labels: labels, Time: ts, Value: sampleValue(p.Sum())})
+			}
+			samples = append(samples, metricSample{Name: name + "_count", Labels: labels, Time: ts, Value: sampleValue(float64(p.Count()))})
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		// The buckets depend on the scale of each point, only the totals are kept.
+		points := m.ExponentialHistogram().DataPoints()
+		for i := 0; i < points.Len(); i++ {
+			p := points.At(i)
+			if p.Flags().NoRecordedValue() {
+				continue
+			}
+			labels := otlpLabels(resourceLabels, p.Attributes())
+			ts := p.Timestamp().AsTime().UTC()
+			if p.HasSum() {
+				samples = append(samples, metricSample{Name: name + "_sum", Labels: labels, Time: ts, Value: sampleValue(p.Sum())})
+			}
+			samples = append(samples, metricSample{Name: name + "_count", Labels: labels, Time: ts, Value: sampleValue(float64(p.Count()))})
+		}
+	case pmetric.MetricTypeSummary:
+		points := m.Summary().DataPoints()
+		for i := 0; i < points.Len(); i++ {
+			p := points.At(i)
+			if p.Flags().NoRecordedValue() {
+				continue
+			}
+			labels := otlpLabels(resourceLabels, p.Attributes())
+			ts := p.Timestamp().AsTime().UTC()
+			quantiles := p.QuantileValues()
+			for q := 0; q < quantiles.Len(); q++ {
+				samples = append(samples, metricSample{
+					Name:   name,
+					Labels: withLabel(labels, "quantile", formatFloatLabel(quantiles.At(q).Quantile())),
+					Time:   ts,
+					Value:  sampleValue(quantiles.At(q).Value()),
+				})
+			}
+			samples = append(samples,
+				metricSample{Name: name + "_sum", Labels: labels, Time: ts, Value: sampleValue(p.Sum())},
+				metricSample{Name: name + "_count", Labels: labels, Time: ts, Value: sampleValue(float64(p.Count()))},
+			)
+		}
+	}
+	return samples
+}
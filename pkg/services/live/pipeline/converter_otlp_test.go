@@ -0,0 +1,69 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/experimental"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+)
+
+func TestOTLPConverter_Convert_JSON(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "otlp.json"))
+	require.NoError(t, err)
+
+	converter := NewOTLPConverter(OTLPConverterConfig{ResourceAttributes: []string{"service.name"}})
+	channelFrames, err := converter.Convert(context.Background(), Vars{Channel: "stream/test/otel"}, content)
+	require.NoError(t, err)
+
+	var channels []string
+	dr := &backend.DataResponse{}
+	for _, cf := range channelFrames {
+		channels = append(channels, cf.Channel)
+		dr.Frames = append(dr.Frames, cf.Frame)
+	}
+	require.Equal(t, []string{
+		"stream/test/otel/queue.size",
+		"stream/test/otel/http.server.duration",
+	}, channels)
+
+	experimental.CheckGoldenJSONResponse(t, "testdata", "otlp.golden", dr, *update)
+}
+
+func TestOTLPConverter_Convert_Protobuf(t *testing.T) {
+	metrics := pmetric.NewMetrics()
+	rm := metrics.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "checkout")
+	m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("orders")
+	p := m.SetEmptySum().DataPoints().AppendEmpty()
+	p.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(1700000000, 0)))
+	p.SetIntValue(42)
+	p.Attributes().PutStr("region", "eu")
+
+	body, err := pmetricotlp.NewExportRequestFromMetrics(metrics).MarshalProto()
+	require.NoError(t, err)
+
+	converter := NewOTLPConverter(OTLPConverterConfig{})
+	channelFrames, err := converter.Convert(context.Background(), Vars{Channel: "stream/test/otel"}, body)
+	require.NoError(t, err)
+	require.Len(t, channelFrames, 1)
+	require.Equal(t, "stream/test/otel/orders", channelFrames[0].Channel)
+
+	frame := channelFrames[0].Frame
+	require.Len(t, frame.Fields, 2)
+	require.Equal(t, time.Unix(1700000000, 0).UTC(), frame.Fields[0].At(0))
+	require.Equal(t, "orders", frame.Fields[1].Name)
+	require.Equal(t, data.Labels{"service.name": "checkout", "region": "eu"}, frame.Fields[1].Labels)
+	v, ok := frame.Fields[1].ConcreteAt(0)
+	require.True(t, ok)
+	require.Equal(t, 42.0, v)
+}
@@ -0,0 +1,145 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// PrometheusConverter decodes the Prometheus text exposition format and
+// transforms it to a ChannelFrame for each metric family, where Channel is
+// constructed from original channel + / + <metric_name>. Histograms and
+// summaries are split into the _bucket, _sum and _count series, like they
+// are exposed.
+type PrometheusConverter struct {
+	config      PrometheusConverterConfig
+	nowTimeFunc func() time.Time
+}
+
+func NewPrometheusConverter(c PrometheusConverterConfig) *PrometheusConverter {
+	return &PrometheusConverter{config: c}
+}
+
+const ConverterTypePrometheus = "prometheus"
+
+func (c *PrometheusConverter) Type() string {
+	return ConverterTypePrometheus
+}
+
+func (c *PrometheusConverter) Convert(_ context.Context, vars Vars, body []byte) ([]*ChannelFrame, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing metrics: %w", err)
+	}
+
+	nowTimeFunc := c.nowTimeFunc
+	if nowTimeFunc == nil {
+		nowTimeFunc = time.Now
+	}
+	// Samples without timestamp are all taken at the same time.
+	now := nowTimeFunc()
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	frames := newMetricFrames()
+	for _, name := range names {
+		for _, m := range families[name].Metric {
+			ts := now
+			if m.TimestampMs != nil {
+				ts = time.UnixMilli(m.GetTimestampMs()).UTC()
+			}
+			for _, s := range prometheusSamples(name, m) {
+				s.Time = ts
+				frames.add(name, name, s)
+			}
+		}
+	}
+	return channelFramesByMetric(vars.Channel, frames), nil
+}
+
+func prometheusSamples(name string, m *dto.Metric) []metricSample {
+	labels := data.Labels{}
+	for _, l := range m.Label {
+		labels[l.GetName()] = l.GetValue()
+	}
+	switch {
+	case m.Counter != nil:
+		return []metricSample{{Name: name, Labels: labels, Value: sampleValue(m.Counter.GetValue())}}
+	case m.Gauge != nil:
+		return []metricSample{{Name: name, Labels: labels, Value: sampleValue(m.Gauge.GetValue())}}
+	case m.Untyped != nil:
+		return []metricSample{{Name: name, Labels: labels, Value: sampleValue(m.Untyped.GetValue())}}
+	case m.Summary != nil:
+		samples := make([]metricSample, 0, len(m.Summary.Quantile)+2)
+		for _, q := range m.Summary.Quantile {
+			samples = append(samples, metricSample{
+				Name:   name,
+				Labels: withLabel(labels, "quantile", formatFloatLabel(q.GetQuantile())),
+				Value:  sampleValue(q.GetValue()),
+			})
+		}
+		return append(samples,
+			metricSample{Name: name + "_sum", Labels: labels, Value: sampleValue(m.Summary.GetSampleSum())},
+			metricSample{Name: name + "_count", Labels: labels, Value: sampleValue(float64(m.Summary.GetSampleCount()))},
+		)
+	case m.Histogram != nil:
+		samples := make([]metricSample, 0, len(m.Histogram.Bucket)+3)
+		for _, b := range m.Histogram.Bucket {
+			samples = append(samples, metricSample{
+				Name:   name + "_bucket",
+				Labels: withLabel(labels, "le", formatFloatLabel(b.GetUpperBound())),
+				Value:  sampleValue(float64(b.GetCumulativeCount())),
+			})
+		}
+		// The +Inf bucket is implicit, it holds every observation.
+		if n := len(m.Histogram.Bucket); n == 0 || !math.IsInf(m.Histogram.Bucket[n-1].GetUpperBound(), 1) {
+			samples = append(samples, metricSample{
+				Name:   name + "_bucket",
+				Labels: withLabel(labels, "le", "+Inf"),
+				Value:  sampleValue(float64(m.Histogram.GetSampleCount())),
+			})
+		}
+		return append(samples,
+			metricSample{Name: name + "_sum", Labels: labels, Value: sampleValue(m.Histogram.GetSampleSum())},
+			metricSample{Name: name + "_count", Labels: labels, Value: sampleValue(float64(m.Histogram.GetSampleCount()))},
+		)
+	}
+	return nil
+}
+
+// sampleValue returns the value, NaN is kept as null since it can't be encoded in JSON.
+func sampleValue(v float64) *float64 {
+	if math.IsNaN(v) {
+		return nil
+	}
+	return &v
+}
+
+func withLabel(labels data.Labels, name, value string) data.Labels {
+	l := labels.Copy()
+	l[name] = value
+	return l
+}
+
+func formatFloatLabel(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,46 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/experimental"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusConverter_Convert(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "prometheus.txt"))
+	require.NoError(t, err)
+
+	converter := NewPrometheusConverter(PrometheusConverterConfig{})
+	converter.nowTimeFunc = func() time.Time {
+		return time.Date(2021, 01, 01, 12, 12, 12, 0, time.UTC)
+	}
+	channelFrames, err := converter.Convert(context.Background(), Vars{Channel: "stream/test/metrics"}, content)
+	require.NoError(t, err)
+
+	var channels []string
+	dr := &backend.DataResponse{}
+	for _, cf := range channelFrames {
+		channels = append(channels, cf.Channel)
+		dr.Frames = append(dr.Frames, cf.Frame)
+	}
+	require.Equal(t, []string{
+		"stream/test/metrics/http_requests_total",
+		"stream/test/metrics/request_duration_seconds",
+		"stream/test/metrics/rpc_duration_seconds",
+		"stream/test/metrics/temperature",
+	}, channels)
+
+	experimental.CheckGoldenJSONResponse(t, "testdata", "prometheus.golden", dr, *update)
+}
+
+func TestPrometheusConverter_Convert_invalid(t *testing.T) {
+	converter := NewPrometheusConverter(PrometheusConverterConfig{})
+	_, err := converter.Convert(context.Background(), Vars{}, []byte("metric{label=} 1\n"))
+	require.Error(t, err)
+}
@@ -0,0 +1,185 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/live"
+)
+
+const (
+	DryRunStageConverter = "converter"
+	DryRunStageProcessor = "processor"
+	DryRunStageOutput    = "output"
+)
+
+// dryRunFrameOutputs are the outputs run during a dry run, they only produce
+// frames for other channels. Other outputs send frames out of the pipeline and
+// are skipped.
+var dryRunFrameOutputs = map[string]bool{
+	FrameOutputTypeRedirect:  true,
+	FrameOutputTypeThreshold: true,
+	FrameOutputTypeChangeLog: true,
+}
+
+// DryRunResult traces a sample payload through the converter, processors and
+// outputs of a channel rule.
+type DryRunResult struct {
+	Steps []DryRunStep `json:"steps"`
+}
+
+type DryRunStep struct {
+	// Stage is converter, processor or output.
+	Stage string `json:"stage"`
+	Type  string `json:"type"`
+	// Frames after the step: converted, processed, or produced by the output.
+	Frames []DryRunFrame `json:"frames,omitempty"`
+	// Dropped is set when a processor stopped the frame processing.
+	Dropped bool `json:"dropped,omitempty"`
+	// Matched is the result of the condition of a conditional output.
+	Matched *bool `json:"matched,omitempty"`
+	// Skipped is set for outputs which would send the frame out of the pipeline.
+	Skipped bool   `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// DryRunFrame is a frame encoded when the step ran, later steps may modify
+// the frame itself.
+type DryRunFrame struct {
+	// Channel the frame is sent to, empty for the rule channel.
+	Channel string          `json:"channel,omitempty"`
+	Frame   json.RawMessage `json:"frame"`
+}
+
+// DryRun runs the payload through the rule for the channel and returns each
+// step. Frames converted for other channels are not processed further. Threshold
+// and change log outputs are run, so the rule should be built with its own
+// FrameStorage. Errors of the rule entities end the trace with the failed step.
+func DryRun(ctx context.Context, rule *LiveChannelRule, orgID int64, channelID string, body []byte) (*DryRunResult, error) {
+	if rule.Converter == nil {
+		return nil, errors.New("channel rule has no converter")
+	}
+	channel, err := live.ParseChannel(channelID)
+	if err != nil {
+		return nil, err
+	}
+	vars := Vars{
+		OrgID:     orgID,
+		Channel:   channelID,
+		Scope:     channel.Scope,
+		Namespace: channel.Namespace,
+		Path:      channel.Path,
+	}
+
+	result := &DryRunResult{}
+	channelFrames, err := rule.Converter.Convert(ctx, vars, body)
+	step := DryRunStep{Stage: DryRunStageConverter, Type: rule.Converter.Type()}
+	if err != nil {
+		step.Error = err.Error()
+		result.Steps = append(result.Steps, step)
+		return result, nil
+	}
+	if step.Frames, err = dryRunFrames(channelFrames...); err != nil {
+		return nil, err
+	}
+	result.Steps = append(result.Steps, step)
+
+	for _, channelFrame := range channelFrames {
+		if channelFrame.Channel != "" && channelFrame.Channel != channelID {
+			continue
+		}
+		if err := result.processFrame(ctx, rule, vars, channelFrame.Frame); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// processFrame adds the steps of the processors and outputs for the frame.
+func (r *DryRunResult) processFrame(ctx context.Context, rule *LiveChannelRule, vars Vars, frame *data.Frame) error {
+	var err error
+	for _, proc := range rule.FrameProcessors {
+		step := DryRunStep{Stage: DryRunStageProcessor, Type: proc.Type()}
+		frame, err = proc.ProcessFrame(ctx, vars, frame)
+		switch {
+		case err != nil:
+			step.Error = err.Error()
+		case frame == nil:
+			step.Dropped = true
+		default:
+			if step.Frames, err = dryRunFrames(&ChannelFrame{Frame: frame}); err != nil {
+				return err
+			}
+		}
+		r.Steps = append(r.Steps, step)
+		if step.Error != "" || step.Dropped {
+			return nil
+		}
+	}
+	for _, out := range rule.FrameOutputters {
+		ok, err := r.output(ctx, out, vars, frame)
+		if err != nil || !ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// output adds the steps of the output, it returns false when the output failed.
+func (r *DryRunResult) output(ctx context.Context, out FrameOutputter, vars Vars, frame *data.Frame) (bool, error) {
+	step := DryRunStep{Stage: DryRunStageOutput, Type: out.Type()}
+	switch o := out.(type) {
+	case *MultipleFrameOutput:
+		for _, out := range o.Outputters {
+			ok, err := r.output(ctx, out, vars, frame)
+			if err != nil || !ok {
+				return ok, err
+			}
+		}
+		return true, nil
+	case *ConditionalOutput:
+		matched, err := o.Condition.CheckFrameCondition(ctx, frame)
+		if err != nil {
+			step.Error = err.Error()
+			r.Steps = append(r.Steps, step)
+			return false, nil
+		}
+		step.Matched = &matched
+		r.Steps = append(r.Steps, step)
+		if !matched {
+			return true, nil
+		}
+		return r.output(ctx, o.Outputter, vars, frame)
+	}
+
+	if !dryRunFrameOutputs[out.Type()] {
+		step.Skipped = true
+		r.Steps = append(r.Steps, step)
+		return true, nil
+	}
+	channelFrames, err := out.OutputFrame(ctx, vars, frame)
+	if err != nil {
+		step.Error = err.Error()
+		r.Steps = append(r.Steps, step)
+		return false, nil
+	}
+	if step.Frames, err = dryRunFrames(channelFrames...); err != nil {
+		return false, err
+	}
+	r.Steps = append(r.Steps, step)
+	return true, nil
+}
+
+func dryRunFrames(channelFrames ...*ChannelFrame) ([]DryRunFrame, error) {
+	frames := make([]DryRunFrame, 0, len(channelFrames))
+	for _, cf := range channelFrames {
+		frameJSON, err := data.FrameToJSON(cf.Frame, data.IncludeAll)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, DryRunFrame{Channel: cf.Channel, Frame: frameJSON})
+	}
+	return frames, nil
+}
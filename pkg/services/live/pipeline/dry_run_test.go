@@ -0,0 +1,83 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	rule := &LiveChannelRule{
+		Pattern:   "stream/test/cpu",
+		Converter: NewJsonFrameConverter(JsonFrameConverterConfig{}),
+		FrameProcessors: []FrameProcessor{
+			NewRenameFieldsFrameProcessor(RenameFieldsFrameProcessorConfig{Names: map[string]string{"value": "cpu"}}),
+		},
+		FrameOutputters: []FrameOutputter{
+			NewConditionalOutput(
+				NewFrameNumberCompareCondition("cpu", NumberCompareOpGt, 50),
+				NewRedirectFrameOutput(RedirectOutputConfig{Channel: "stream/test/alerts"}),
+			),
+			NewConditionalOutput(
+				NewFrameNumberCompareCondition("cpu", NumberCompareOpLt, 0),
+				NewRedirectFrameOutput(RedirectOutputConfig{Channel: "stream/test/invalid"}),
+			),
+			NewRemoteWriteFrameOutput("", nil, 0),
+		},
+	}
+	value := 80.0
+	frame := data.NewFrame("cpu",
+		data.NewField("time", nil, []time.Time{time.Unix(1, 0)}),
+		data.NewField("value", nil, []*float64{&value}),
+	)
+	body, err := json.Marshal(frame)
+	require.NoError(t, err)
+
+	result, err := DryRun(context.Background(), rule, 1, "stream/test/cpu", body)
+	require.NoError(t, err)
+
+	stages := make([]string, 0, len(result.Steps))
+	for _, step := range result.Steps {
+		stages = append(stages, step.Stage+"/"+step.Type)
+		require.Empty(t, step.Error)
+	}
+	require.Equal(t, []string{
+		"converter/jsonFrame",
+		"processor/renameFields",
+		"output/conditional",
+		"output/redirect",
+		"output/conditional",
+		"output/remoteWrite",
+	}, stages)
+
+	// Frames are kept as they were after each step.
+	var converted data.Frame
+	require.NoError(t, json.Unmarshal(result.Steps[0].Frames[0].Frame, &converted))
+	require.Equal(t, "value", converted.Fields[1].Name)
+	var processed data.Frame
+	require.NoError(t, json.Unmarshal(result.Steps[1].Frames[0].Frame, &processed))
+	require.Equal(t, "cpu", processed.Fields[1].Name)
+
+	require.True(t, *result.Steps[2].Matched)
+	require.Equal(t, "stream/test/alerts", result.Steps[3].Frames[0].Channel)
+	require.False(t, *result.Steps[4].Matched)
+	require.True(t, result.Steps[5].Skipped)
+}
+
+func TestDryRun_ConverterError(t *testing.T) {
+	rule := &LiveChannelRule{
+		Pattern:   "stream/test/cpu",
+		Converter: NewJsonFrameConverter(JsonFrameConverterConfig{}),
+	}
+	result, err := DryRun(context.Background(), rule, 1, "stream/test/cpu", []byte("{"))
+	require.NoError(t, err)
+	require.Len(t, result.Steps, 1)
+	require.NotEmpty(t, result.Steps[0].Error)
+
+	_, err = DryRun(context.Background(), &LiveChannelRule{Pattern: "stream/test/cpu"}, 1, "stream/test/cpu", nil)
+	require.Error(t, err)
+}
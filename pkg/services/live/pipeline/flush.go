@@ -0,0 +1,169 @@
+package pipeline
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/live"
+
+	"github.com/grafana/grafana/pkg/services/live/orgchannel"
+)
+
+// processorFlushInterval is how often the processors holding frames back are flushed.
+const processorFlushInterval = time.Second
+
+// FrameFlusher is implemented by the processors holding frames back. The
+// pipeline flushes them periodically, so the frames held are passed on even
+// when no later frame is pushed to the channel.
+type FrameFlusher interface {
+	// FlushFrames returns the frames of the channel due at now, and whether
+	// frames of the channel are still held back.
+	FlushFrames(ctx context.Context, vars Vars, now time.Time) ([]*data.Frame, bool, error)
+}
+
+type flushedChannel struct {
+	orgID   int64
+	channel string
+	// seq changes on each frame pushed to the channel.
+	seq uint64
+}
+
+// flushProcessors flushes the processors in order, the frames flushed by a
+// processor are processed by the next ones.
+func flushProcessors(ctx context.Context, processors []FrameProcessor, vars Vars, now time.Time) ([]*data.Frame, bool, error) {
+	var frames []*data.Frame
+	var held bool
+	for _, proc := range processors {
+		var next []*data.Frame
+		for _, frame := range frames {
+			frame, err := proc.ProcessFrame(ctx, vars, frame)
+			if err != nil {
+				return nil, false, err
+			}
+			if frame != nil {
+				next = append(next, frame)
+			}
+		}
+		if flusher, ok := proc.(FrameFlusher); ok {
+			flushed, procHeld, err := flusher.FlushFrames(ctx, vars, now)
+			if err != nil {
+				return nil, false, err
+			}
+			next = append(next, flushed...)
+			held = held || procHeld
+		}
+		frames = next
+	}
+	return frames, held, nil
+}
+
+// watchFlush registers the channel to be flushed when its rule has processors holding frames back.
+func (p *Pipeline) watchFlush(orgID int64, channelID string, rule *LiveChannelRule) {
+	flush := false
+	for _, proc := range rule.FrameProcessors {
+		if _, ok := proc.(FrameFlusher); ok {
+			flush = true
+			break
+		}
+	}
+	if !flush {
+		return
+	}
+	key := orgchannel.PrependOrgID(orgID, channelID)
+	p.flushMu.Lock()
+	defer p.flushMu.Unlock()
+	if p.flushChannels == nil {
+		p.flushChannels = map[string]*flushedChannel{}
+	}
+	ch, ok := p.flushChannels[key]
+	if !ok {
+		ch = &flushedChannel{orgID: orgID, channel: channelID}
+		p.flushChannels[key] = ch
+	}
+	ch.seq++
+	if !p.flushRunning {
+		p.flushRunning = true
+		go p.flushPeriodically()
+	}
+}
+
+// flushPeriodically flushes the registered channels until no frames are held back.
+func (p *Pipeline) flushPeriodically() {
+	ticker := time.NewTicker(processorFlushInterval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		p.flushMu.Lock()
+		channels := make(map[string]flushedChannel, len(p.flushChannels))
+		for key, ch := range p.flushChannels {
+			channels[key] = *ch
+		}
+		p.flushMu.Unlock()
+
+		for key, ch := range channels {
+			held, err := p.flushChannel(context.Background(), ch.orgID, ch.channel, now)
+			if err != nil {
+				logger.Error("Error flushing frames", "error", err, "orgId", ch.orgID, "channel", ch.channel)
+			}
+			if held && err == nil {
+				continue
+			}
+			p.flushMu.Lock()
+			// Frames pushed during the flush are flushed on the next tick.
+			if current, ok := p.flushChannels[key]; ok && current.seq == ch.seq {
+				delete(p.flushChannels, key)
+			}
+			p.flushMu.Unlock()
+		}
+
+		p.flushMu.Lock()
+		if len(p.flushChannels) == 0 {
+			p.flushRunning = false
+			p.flushMu.Unlock()
+			return
+		}
+		p.flushMu.Unlock()
+	}
+}
+
+// flushChannel passes the frames flushed by the processors of the channel rule
+// on to the outputs, it returns whether frames are still held back.
+func (p *Pipeline) flushChannel(ctx context.Context, orgID int64, channelID string, now time.Time) (bool, error) {
+	rule, ok, err := p.ruleGetter.Get(orgID, channelID)
+	if err != nil || !ok {
+		return false, err
+	}
+	ch, err := live.ParseChannel(channelID)
+	if err != nil {
+		return false, err
+	}
+	vars := Vars{
+		OrgID:     orgID,
+		Channel:   channelID,
+		Scope:     ch.Scope,
+		Namespace: ch.Namespace,
+		Path:      ch.Path,
+	}
+
+	frames, held, err := flushProcessors(ctx, rule.FrameProcessors, vars, now)
+	if err != nil {
+		return held, err
+	}
+	for _, frame := range frames {
+		var resultingFrames []*ChannelFrame
+		for _, out := range rule.FrameOutputters {
+			outFrames, err := p.processFrameOutput(ctx, out, vars, frame)
+			if err != nil {
+				return held, err
+			}
+			resultingFrames = append(resultingFrames, outFrames...)
+		}
+		if len(resultingFrames) > 0 {
+			err := p.processChannelFrames(ctx, orgID, channelID, resultingFrames, map[string]struct{}{channelID: {}})
+			if err != nil {
+				return held, err
+			}
+		}
+	}
+	return held, nil
+}
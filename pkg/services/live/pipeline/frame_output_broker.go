@@ -0,0 +1,62 @@
+package pipeline
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/live/nats"
+)
+
+// MessageBroker publishes messages to the subjects (or topics) of a message bus.
+type MessageBroker interface {
+	Publish(ctx context.Context, subject string, payload []byte) error
+}
+
+// BrokerFrameOutput publishes frames encoded to JSON to a message broker.
+type BrokerFrameOutput struct {
+	outputType string
+	broker     MessageBroker
+	subject    string
+}
+
+// NewBrokerFrameOutput creates a BrokerFrameOutput publishing to the subject, or
+// to a subject built from the channel when the subject is empty.
+func NewBrokerFrameOutput(outputType string, broker MessageBroker, subject string) *BrokerFrameOutput {
+	return &BrokerFrameOutput{
+		outputType: outputType,
+		broker:     broker,
+		subject:    subject,
+	}
+}
+
+const FrameOutputTypeNATS = "nats"
+
+// NewNATSFrameOutput creates a BrokerFrameOutput publishing to the NATS server.
+func NewNATSFrameOutput(publisher *nats.Publisher, config NATSOutputConfig) *BrokerFrameOutput {
+	return NewBrokerFrameOutput(FrameOutputTypeNATS, publisher, config.Subject)
+}
+
+func (out *BrokerFrameOutput) Type() string {
+	return out.outputType
+}
+
+func (out *BrokerFrameOutput) OutputFrame(ctx context.Context, vars Vars, frame *data.Frame) ([]*ChannelFrame, error) {
+	frameJSON, err := data.FrameToJSON(frame, data.IncludeAll)
+	if err != nil {
+		return nil, err
+	}
+	subject := out.subject
+	if subject == "" {
+		subject = channelSubject(vars.OrgID, vars.Channel)
+	}
+	return nil, out.broker.Publish(ctx, subject, frameJSON)
+}
+
+// channelSubject returns grafana.live.<orgId>.<channel> with the channel
+// slashes replaced by dots, so subscribers can use subject wildcards.
+func channelSubject(orgID int64, channel string) string {
+	return "grafana.live." + strconv.FormatInt(orgID, 10) + "." + strings.ReplaceAll(channel, "/", ".")
+}
@@ -0,0 +1,42 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+type testBroker struct {
+	subjects []string
+	payloads [][]byte
+}
+
+func (b *testBroker) Publish(_ context.Context, subject string, payload []byte) error {
+	b.subjects = append(b.subjects, subject)
+	b.payloads = append(b.payloads, payload)
+	return nil
+}
+
+func TestBrokerFrameOutput(t *testing.T) {
+	broker := &testBroker{}
+	vars := Vars{OrgID: 2, Channel: "stream/edge/cpu"}
+	frame := data.NewFrame("cpu", data.NewField("value", nil, []float64{1}))
+
+	out := NewBrokerFrameOutput(FrameOutputTypeNATS, broker, "")
+	require.Equal(t, FrameOutputTypeNATS, out.Type())
+	_, err := out.OutputFrame(context.Background(), vars, frame)
+	require.NoError(t, err)
+
+	out = NewBrokerFrameOutput(FrameOutputTypeNATS, broker, "telemetry.edge")
+	_, err = out.OutputFrame(context.Background(), vars, frame)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"grafana.live.2.stream.edge.cpu", "telemetry.edge"}, broker.subjects)
+	var published data.Frame
+	require.NoError(t, json.Unmarshal(broker.payloads[0], &published))
+	require.Equal(t, "cpu", published.Name)
+	require.Equal(t, 1.0, published.Fields[0].At(0))
+}
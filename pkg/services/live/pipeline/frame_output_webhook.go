@@ -0,0 +1,241 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	webhookDefaultBatchSize     = 100
+	webhookDefaultFlushInterval = time.Second
+	webhookDefaultMaxRetries    = 3
+	webhookRetryBackoff         = 500 * time.Millisecond
+	// webhookMaxBufferedBatches bounds the frames waiting for a slow endpoint,
+	// the oldest frames are dropped above it.
+	webhookMaxBufferedBatches = 10
+)
+
+// WebhookFrameOutput sends frames encoded to JSON to an HTTP endpoint. Frames
+// are sent in batches, when the batch is full or on each flush interval. Failed
+// requests are retried with an exponential backoff, then the batch is dropped.
+type WebhookFrameOutput struct {
+	writer *webhookWriter
+}
+
+func NewWebhookFrameOutput(endpoint string, basicAuth *BasicAuth, config WebhookOutputConfig) *WebhookFrameOutput {
+	return &WebhookFrameOutput{
+		writer: newWebhookWriter(endpoint, basicAuth, config),
+	}
+}
+
+const FrameOutputTypeWebhook = "webhook"
+
+// Close sends the buffered frames and stops the flushes, it is called once no
+// rule uses the output.
+func (out *WebhookFrameOutput) Close() error {
+	out.writer.close()
+	return nil
+}
+
+func (out *WebhookFrameOutput) Type() string {
+	return FrameOutputTypeWebhook
+}
+
+// WebhookPayload is the body of the requests sent to the webhook endpoint.
+type WebhookPayload struct {
+	Messages []WebhookMessage `json:"messages"`
+}
+
+type WebhookMessage struct {
+	OrgID   int64  `json:"orgId"`
+	Channel string `json:"channel"`
+	// Time the frame was pushed in milliseconds.
+	Time  int64           `json:"time"`
+	Frame json.RawMessage `json:"frame"`
+}
+
+func (out *WebhookFrameOutput) OutputFrame(_ context.Context, vars Vars, frame *data.Frame) ([]*ChannelFrame, error) {
+	if out.writer.endpoint == "" {
+		logger.Debug("Skip sending to webhook: no url")
+		return nil, nil
+	}
+	frameJSON, err := data.FrameToJSON(frame, data.IncludeAll)
+	if err != nil {
+		return nil, err
+	}
+	out.writer.write(WebhookMessage{
+		OrgID:   vars.OrgID,
+		Channel: vars.Channel,
+		Time:    time.Now().UnixMilli(),
+		Frame:   frameJSON,
+	})
+	return nil, nil
+}
+
+type webhookWriter struct {
+	endpoint      string
+	basicAuth     *BasicAuth
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+	retryBackoff  time.Duration
+	httpClient    *http.Client
+
+	startOnce sync.Once
+	closeOnce sync.Once
+	mu        sync.Mutex
+	buffer    []WebhookMessage
+	dropped   int
+	flushCh   chan struct{}
+	done      chan struct{}
+}
+
+func newWebhookWriter(endpoint string, basicAuth *BasicAuth, config WebhookOutputConfig) *webhookWriter {
+	w := &webhookWriter{
+		endpoint:      endpoint,
+		basicAuth:     basicAuth,
+		batchSize:     config.BatchSize,
+		flushInterval: time.Duration(config.FlushMilliseconds) * time.Millisecond,
+		maxRetries:    config.MaxRetries,
+		retryBackoff:  webhookRetryBackoff,
+		httpClient:    &http.Client{Timeout: 5 * time.Second},
+		flushCh:       make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+	if w.batchSize <= 0 {
+		w.batchSize = webhookDefaultBatchSize
+	}
+	if w.flushInterval <= 0 {
+		w.flushInterval = webhookDefaultFlushInterval
+	}
+	if w.maxRetries <= 0 {
+		w.maxRetries = webhookDefaultMaxRetries
+	}
+	return w
+}
+
+func (w *webhookWriter) write(m WebhookMessage) {
+	// Flushes start with the first frame, outputs built only to check or test
+	// a rule do not keep a goroutine.
+	if w.endpoint != "" {
+		w.startOnce.Do(func() { go w.flushPeriodically() })
+	}
+	w.mu.Lock()
+	w.buffer = append(w.buffer, m)
+	if maxBuffered := webhookMaxBufferedBatches * w.batchSize; len(w.buffer) > maxBuffered {
+		n := len(w.buffer) - maxBuffered
+		w.buffer = append([]WebhookMessage(nil), w.buffer[n:]...)
+		w.dropped += n
+	}
+	full := len(w.buffer) >= w.batchSize
+	w.mu.Unlock()
+	if full {
+		select {
+		case w.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (w *webhookWriter) flushPeriodically() {
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.flush(false)
+		case <-w.flushCh:
+			w.flush(true)
+		case <-w.done:
+			w.flush(false)
+			return
+		}
+	}
+}
+
+// close stops the flushes once the buffered messages are sent, the messages
+// written after it are not sent.
+func (w *webhookWriter) close() {
+	w.startOnce.Do(func() {})
+	w.closeOnce.Do(func() { close(w.done) })
+}
+
+// flush sends the buffered messages, a batch at a time. With fullOnly the last
+// batch stays in the buffer until it is full or the flush interval elapses.
+func (w *webhookWriter) flush(fullOnly bool) {
+	for {
+		w.mu.Lock()
+		if fullOnly && len(w.buffer) < w.batchSize {
+			w.mu.Unlock()
+			return
+		}
+		n := min(len(w.buffer), w.batchSize)
+		batch := w.buffer[:n:n]
+		w.buffer = w.buffer[n:]
+		dropped := w.dropped
+		w.dropped = 0
+		w.mu.Unlock()
+
+		if dropped > 0 {
+			logger.Warn("Webhook buffer full, frames dropped", "url", w.endpoint, "numDropped", dropped)
+		}
+		if len(batch) == 0 {
+			return
+		}
+		if err := w.send(batch); err != nil {
+			logger.Error("Error sending frames to webhook, batch dropped", "url", w.endpoint, "error", err, "numFrames", len(batch))
+		}
+	}
+}
+
+// send posts the batch, retrying on network errors, 429 and 5xx responses.
+func (w *webhookWriter) send(batch []WebhookMessage) error {
+	body, err := json.Marshal(WebhookPayload{Messages: batch})
+	if err != nil {
+		return fmt.Errorf("error encoding webhook payload: %w", err)
+	}
+	for attempt := 0; ; attempt++ {
+		retry, err := w.post(body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= w.maxRetries {
+			return err
+		}
+		logger.Debug("Retrying webhook request", "url", w.endpoint, "error", err, "attempt", attempt+1)
+		time.Sleep(w.retryBackoff << attempt)
+	}
+}
+
+func (w *webhookWriter) post(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, w.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("error constructing webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.basicAuth != nil {
+		req.SetBasicAuth(w.basicAuth.User, w.basicAuth.Password)
+	}
+
+	started := time.Now()
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("error sending webhook request: %w", err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, fmt.Errorf("unexpected response code from webhook endpoint: %d", resp.StatusCode)
+	}
+	logger.Debug("Successfully sent to webhook", "url", w.endpoint, "bodyLength", len(body), "elapsed", time.Since(started))
+	return false, nil
+}
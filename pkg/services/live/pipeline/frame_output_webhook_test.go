@@ -0,0 +1,158 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+type testWebhookEndpoint struct {
+	mu       sync.Mutex
+	statuses []int
+	requests int
+	payloads []WebhookPayload
+	user     string
+}
+
+func (e *testWebhookEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.requests++
+	if len(e.statuses) > 0 {
+		status := e.statuses[0]
+		e.statuses = e.statuses[1:]
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+	}
+	var payload WebhookPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	e.user, _, _ = r.BasicAuth()
+	e.payloads = append(e.payloads, payload)
+}
+
+func (e *testWebhookEndpoint) respond(statuses ...int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.statuses = statuses
+}
+
+func (e *testWebhookEndpoint) numRequests() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.requests
+}
+
+func (e *testWebhookEndpoint) received() []WebhookPayload {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]WebhookPayload(nil), e.payloads...)
+}
+
+func TestWebhookFrameOutput_Batch(t *testing.T) {
+	endpoint := &testWebhookEndpoint{}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	out := NewWebhookFrameOutput(server.URL, &BasicAuth{User: "edge"}, WebhookOutputConfig{
+		BatchSize:         2,
+		FlushMilliseconds: 60000,
+	})
+	vars := Vars{OrgID: 1, Channel: "stream/edge/cpu"}
+	for i := 0; i < 5; i++ {
+		_, err := out.OutputFrame(context.Background(), vars, data.NewFrame("cpu", data.NewField("value", nil, []float64{float64(i)})))
+		require.NoError(t, err)
+	}
+
+	// Full batches are sent without waiting for the flush interval.
+	require.Eventually(t, func() bool {
+		return len(endpoint.received()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	payloads := endpoint.received()
+	require.Len(t, payloads[0].Messages, 2)
+	require.Len(t, payloads[1].Messages, 2)
+	require.Equal(t, int64(1), payloads[0].Messages[0].OrgID)
+	require.Equal(t, "stream/edge/cpu", payloads[0].Messages[0].Channel)
+	require.Equal(t, "edge", endpoint.user)
+
+	var frame data.Frame
+	require.NoError(t, json.Unmarshal(payloads[1].Messages[1].Frame, &frame))
+	require.Equal(t, 3.0, frame.Fields[0].At(0))
+
+	// The last frame waits for the flush.
+	out.writer.flush(false)
+	payloads = endpoint.received()
+	require.Len(t, payloads, 3)
+	require.Len(t, payloads[2].Messages, 1)
+}
+
+func TestWebhookFrameOutput_Retry(t *testing.T) {
+	endpoint := &testWebhookEndpoint{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK}}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+
+	w := newWebhookWriter("", nil, WebhookOutputConfig{})
+	w.endpoint = server.URL
+	w.retryBackoff = time.Millisecond
+
+	err := w.send([]WebhookMessage{{Channel: "stream/edge/cpu", Frame: json.RawMessage(`{}`)}})
+	require.NoError(t, err)
+	require.Equal(t, 3, endpoint.numRequests())
+	require.Len(t, endpoint.received(), 1)
+
+	// Client errors are not retried.
+	endpoint.respond(http.StatusBadRequest)
+	err = w.send([]WebhookMessage{{Channel: "stream/edge/cpu", Frame: json.RawMessage(`{}`)}})
+	require.Error(t, err)
+	require.Equal(t, 4, endpoint.numRequests())
+
+	// The batch is dropped after the max retries.
+	endpoint.respond(500, 500, 500, 500, 500)
+	err = w.send([]WebhookMessage{{Channel: "stream/edge/cpu", Frame: json.RawMessage(`{}`)}})
+	require.Error(t, err)
+	require.Equal(t, 8, endpoint.numRequests())
+}
+
+func TestWebhookFrameOutput_BufferLimit(t *testing.T) {
+	w := newWebhookWriter("", nil, WebhookOutputConfig{BatchSize: 1})
+	for i := 0; i < webhookMaxBufferedBatches+5; i++ {
+		w.write(WebhookMessage{Time: int64(i)})
+	}
+	require.Len(t, w.buffer, webhookMaxBufferedBatches)
+	require.Equal(t, int64(5), w.buffer[0].Time)
+	require.Equal(t, 5, w.dropped)
+}
+
+func TestWebhookFrameOutput_Close(t *testing.T) {
+	received := make(chan WebhookPayload, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload WebhookPayload
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		received <- payload
+	}))
+	defer server.Close()
+
+	out := NewWebhookFrameOutput(server.URL, nil, WebhookOutputConfig{FlushMilliseconds: 60000})
+	_, err := out.OutputFrame(context.Background(), Vars{OrgID: 1, Channel: "stream/test/cpu"}, data.NewFrame("cpu"))
+	require.NoError(t, err)
+
+	// The buffered frames are sent on close.
+	require.NoError(t, out.Close())
+	select {
+	case payload := <-received:
+		require.Len(t, payload.Messages, 1)
+	case <-time.After(5 * time.Second):
+		t.Fatal("frames not sent on close")
+	}
+}
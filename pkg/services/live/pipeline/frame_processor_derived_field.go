@@ -0,0 +1,243 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"strconv"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// DerivedFieldFrameProcessor adds a field computed from the other fields of
+// the same row with an arithmetic expression, for example (temp * 1.8) + 32.
+// Fields are referenced by name, or with field("name") when the name is not
+// an identifier. The result is null when a referenced value is null.
+type DerivedFieldFrameProcessor struct {
+	config DerivedFieldFrameProcessorConfig
+	expr   rowExpr
+}
+
+func NewDerivedFieldFrameProcessor(config DerivedFieldFrameProcessorConfig) (*DerivedFieldFrameProcessor, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("missing field name")
+	}
+	expr, err := compileRowExpr(config.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression for %s: %w", config.Name, err)
+	}
+	return &DerivedFieldFrameProcessor{config: config, expr: expr}, nil
+}
+
+const FrameProcessorTypeDerivedField = "derivedField"
+
+func (p *DerivedFieldFrameProcessor) Type() string {
+	return FrameProcessorTypeDerivedField
+}
+
+func (p *DerivedFieldFrameProcessor) ProcessFrame(_ context.Context, _ Vars, frame *data.Frame) (*data.Frame, error) {
+	rows, err := frame.RowLen()
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]*data.Field, len(frame.Fields))
+	for _, f := range frame.Fields {
+		if _, ok := fields[f.Name]; !ok {
+			fields[f.Name] = f
+		}
+	}
+
+	values := make([]*float64, rows)
+	for i := 0; i < rows; i++ {
+		row := func(name string) (float64, bool, error) {
+			f, ok := fields[name]
+			if !ok {
+				return 0, false, fmt.Errorf("unknown field: %s", name)
+			}
+			v, err := f.NullableFloatAt(i)
+			if err != nil {
+				return 0, false, fmt.Errorf("field %s: %w", name, err)
+			}
+			if v == nil {
+				return 0, false, nil
+			}
+			return *v, true, nil
+		}
+		v, ok, err := p.expr(row)
+		if err != nil {
+			return nil, err
+		}
+		if ok && !math.IsNaN(v) {
+			values[i] = &v
+		}
+	}
+
+	derived := data.NewField(p.config.Name, nil, values)
+	for i, f := range frame.Fields {
+		if f.Name == p.config.Name {
+			frame.Fields[i] = derived
+			return frame, nil
+		}
+	}
+	frame.Fields = append(frame.Fields, derived)
+	return frame, nil
+}
+
+// rowExpr evaluates an expression for a row. The bool result is false when the
+// value is null.
+type rowExpr func(row func(name string) (float64, bool, error)) (float64, bool, error)
+
+type rowExprFunc struct {
+	args int
+	fn   func(args []float64) float64
+}
+
+var rowExprFuncs = map[string]rowExprFunc{
+	"abs":   {args: 1, fn: func(a []float64) float64 { return math.Abs(a[0]) }},
+	"sqrt":  {args: 1, fn: func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"log":   {args: 1, fn: func(a []float64) float64 { return math.Log(a[0]) }},
+	"round": {args: 1, fn: func(a []float64) float64 { return math.Round(a[0]) }},
+	"floor": {args: 1, fn: func(a []float64) float64 { return math.Floor(a[0]) }},
+	"ceil":  {args: 1, fn: func(a []float64) float64 { return math.Ceil(a[0]) }},
+	"pow":   {args: 2, fn: func(a []float64) float64 { return math.Pow(a[0], a[1]) }},
+	"min":   {args: 2, fn: func(a []float64) float64 { return math.Min(a[0], a[1]) }},
+	"max":   {args: 2, fn: func(a []float64) float64 { return math.Max(a[0], a[1]) }},
+}
+
+func compileRowExpr(s string) (rowExpr, error) {
+	node, err := parser.ParseExpr(s)
+	if err != nil {
+		return nil, err
+	}
+	return compileRowExprNode(node)
+}
+
+func compileRowExprNode(node ast.Expr) (rowExpr, error) {
+	switch n := node.(type) {
+	case *ast.ParenExpr:
+		return compileRowExprNode(n.X)
+
+	case *ast.BasicLit:
+		if n.Kind != token.INT && n.Kind != token.FLOAT {
+			return nil, fmt.Errorf("unsupported literal: %s", n.Value)
+		}
+		v, err := strconv.ParseFloat(n.Value, 64)
+		if err != nil {
+			return nil, err
+		}
+		return func(func(string) (float64, bool, error)) (float64, bool, error) {
+			return v, true, nil
+		}, nil
+
+	case *ast.Ident:
+		name := n.Name
+		return func(row func(string) (float64, bool, error)) (float64, bool, error) {
+			return row(name)
+		}, nil
+
+	case *ast.UnaryExpr:
+		x, err := compileRowExprNode(n.X)
+		if err != nil {
+			return nil, err
+		}
+		switch n.Op {
+		case token.SUB:
+			return func(row func(string) (float64, bool, error)) (float64, bool, error) {
+				v, ok, err := x(row)
+				return -v, ok, err
+			}, nil
+		case token.ADD:
+			return x, nil
+		}
+		return nil, fmt.Errorf("unsupported operator: %s", n.Op)
+
+	case *ast.BinaryExpr:
+		x, err := compileRowExprNode(n.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := compileRowExprNode(n.Y)
+		if err != nil {
+			return nil, err
+		}
+		var op func(a, b float64) float64
+		switch n.Op {
+		case token.ADD:
+			op = func(a, b float64) float64 { return a + b }
+		case token.SUB:
+			op = func(a, b float64) float64 { return a - b }
+		case token.MUL:
+			op = func(a, b float64) float64 { return a * b }
+		case token.QUO:
+			op = func(a, b float64) float64 { return a / b }
+		case token.REM:
+			op = math.Mod
+		default:
+			return nil, fmt.Errorf("unsupported operator: %s", n.Op)
+		}
+		return func(row func(string) (float64, bool, error)) (float64, bool, error) {
+			a, ok, err := x(row)
+			if err != nil || !ok {
+				return 0, false, err
+			}
+			b, ok, err := y(row)
+			if err != nil || !ok {
+				return 0, false, err
+			}
+			return op(a, b), true, nil
+		}, nil
+
+	case *ast.CallExpr:
+		fn, ok := n.Fun.(*ast.Ident)
+		if !ok {
+			return nil, fmt.Errorf("unsupported function call")
+		}
+		if fn.Name == "field" {
+			if len(n.Args) != 1 {
+				return nil, fmt.Errorf("field expects the field name")
+			}
+			lit, ok := n.Args[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return nil, fmt.Errorf("field expects the field name")
+			}
+			name, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				return nil, err
+			}
+			return func(row func(string) (float64, bool, error)) (float64, bool, error) {
+				return row(name)
+			}, nil
+		}
+
+		f, ok := rowExprFuncs[fn.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown function: %s", fn.Name)
+		}
+		if len(n.Args) != f.args {
+			return nil, fmt.Errorf("%s expects %d arguments", fn.Name, f.args)
+		}
+		args := make([]rowExpr, 0, len(n.Args))
+		for _, arg := range n.Args {
+			a, err := compileRowExprNode(arg)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, a)
+		}
+		return func(row func(string) (float64, bool, error)) (float64, bool, error) {
+			values := make([]float64, len(args))
+			for i, a := range args {
+				v, ok, err := a(row)
+				if err != nil || !ok {
+					return 0, false, err
+				}
+				values[i] = v
+			}
+			return f.fn(values), true, nil
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported expression: %T", node)
+}
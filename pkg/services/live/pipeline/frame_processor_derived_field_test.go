@@ -0,0 +1,70 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDerivedFieldFrameProcessor(t *testing.T) {
+	frame := data.NewFrame("test",
+		data.NewField("temperature", nil, []float64{0, 100}),
+		data.NewField("humidity %", nil, []*float64{ptr(40), nil}),
+	)
+
+	p, err := NewDerivedFieldFrameProcessor(DerivedFieldFrameProcessorConfig{
+		Name:       "fahrenheit",
+		Expression: "round(temperature * 1.8 + 32)",
+	})
+	require.NoError(t, err)
+	frame, err = p.ProcessFrame(context.Background(), Vars{}, frame)
+	require.NoError(t, err)
+	require.Len(t, frame.Fields, 3)
+	require.Equal(t, "fahrenheit", frame.Fields[2].Name)
+	require.Equal(t, ptr(32), frame.Fields[2].At(0))
+	require.Equal(t, ptr(212), frame.Fields[2].At(1))
+
+	// Null values give a null result
+	p, err = NewDerivedFieldFrameProcessor(DerivedFieldFrameProcessorConfig{
+		Name:       "dry",
+		Expression: `100 - field("humidity %")`,
+	})
+	require.NoError(t, err)
+	frame, err = p.ProcessFrame(context.Background(), Vars{}, frame)
+	require.NoError(t, err)
+	require.Equal(t, ptr(60), frame.Fields[3].At(0))
+	require.Nil(t, frame.Fields[3].At(1))
+
+	p, err = NewDerivedFieldFrameProcessor(DerivedFieldFrameProcessorConfig{Name: "x", Expression: "missing + 1"})
+	require.NoError(t, err)
+	_, err = p.ProcessFrame(context.Background(), Vars{}, frame)
+	require.ErrorContains(t, err, "unknown field: missing")
+}
+
+func TestCompileRowExpr(t *testing.T) {
+	row := func(name string) (float64, bool, error) {
+		return map[string]float64{"a": 3, "b": 4}[name], true, nil
+	}
+	for expr, expected := range map[string]float64{
+		"a + b * 2":       11,
+		"(a + b) * 2":     14,
+		"-a % 2":          -1,
+		"sqrt(a*a + b*b)": 5,
+		"max(a, b) / 2":   2,
+		"pow(2, 10)":      1024,
+	} {
+		e, err := compileRowExpr(expr)
+		require.NoError(t, err, expr)
+		v, ok, err := e(row)
+		require.NoError(t, err, expr)
+		require.True(t, ok, expr)
+		require.Equal(t, expected, v, expr)
+	}
+
+	for _, expr := range []string{"a == b", `"a"`, "unknown(a)", "max(a)", "a.b", "a +"} {
+		_, err := compileRowExpr(expr)
+		require.Error(t, err, expr)
+	}
+}
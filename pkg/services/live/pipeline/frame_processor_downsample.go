@@ -0,0 +1,253 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/live/orgchannel"
+)
+
+const (
+	DownsampleAggregationAvg = "avg"
+	DownsampleAggregationMax = "max"
+)
+
+// DownsampleFrameProcessor aggregates the rows of each channel over time windows.
+// Frames are held back until a row of a later window arrives, then a frame
+// with one row per completed window is passed on: numeric fields hold the
+// average or the max of the window, other fields their last value. The last
+// window is passed on by the pipeline flush once no row arrived for an interval.
+// Windows are aligned on the frame time field, or on the arrival time when the
+// frame has no time field. Not usable in HA setup, each instance downsamples
+// the frames it receives.
+type DownsampleFrameProcessor struct {
+	config   DownsampleFrameProcessorConfig
+	interval time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	windows   map[string]*downsampleWindow
+	lastPrune time.Time
+}
+
+type downsampleWindow struct {
+	start     time.Time
+	frameName string
+	timeName  string
+	order     []string
+	fields    map[string]*downsampleField
+	updated   time.Time
+}
+
+type downsampleField struct {
+	name    string
+	labels  data.Labels
+	typ     data.FieldType
+	numeric bool
+	count   int
+	sum     float64
+	max     float64
+	last    any
+}
+
+func NewDownsampleFrameProcessor(config DownsampleFrameProcessorConfig) (*DownsampleFrameProcessor, error) {
+	if config.IntervalMilliseconds <= 0 {
+		return nil, fmt.Errorf("downsample interval must be positive")
+	}
+	switch config.Aggregation {
+	case "":
+		config.Aggregation = DownsampleAggregationAvg
+	case DownsampleAggregationAvg, DownsampleAggregationMax:
+	default:
+		return nil, fmt.Errorf("unknown downsample aggregation: %s", config.Aggregation)
+	}
+	return &DownsampleFrameProcessor{
+		config:   config,
+		interval: time.Duration(config.IntervalMilliseconds) * time.Millisecond,
+		now:      time.Now,
+		windows:  map[string]*downsampleWindow{},
+	}, nil
+}
+
+const FrameProcessorTypeDownsample = "downsample"
+
+func (p *DownsampleFrameProcessor) Type() string {
+	return FrameProcessorTypeDownsample
+}
+
+func (p *DownsampleFrameProcessor) ProcessFrame(_ context.Context, vars Vars, frame *data.Frame) (*data.Frame, error) {
+	rows, err := frame.RowLen()
+	if err != nil {
+		return nil, err
+	}
+	timeIndex := -1
+	for i, f := range frame.Fields {
+		if f.Type().Time() {
+			timeIndex = i
+			break
+		}
+	}
+
+	key := orgchannel.PrependOrgID(vars.OrgID, vars.Channel)
+	now := p.now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.prune(now)
+
+	var completed []*downsampleWindow
+	window := p.windows[key]
+	for i := 0; i < rows; i++ {
+		t := now
+		if timeIndex >= 0 {
+			if v, ok := frame.Fields[timeIndex].ConcreteAt(i); ok {
+				t = v.(time.Time)
+			}
+		}
+		start := t.Truncate(p.interval)
+		if window == nil || start.After(window.start) {
+			if window != nil {
+				completed = append(completed, window)
+			}
+			window = &downsampleWindow{start: start, fields: map[string]*downsampleField{}}
+		}
+		// Late rows are added to the current window.
+		if err := window.add(frame, timeIndex, i); err != nil {
+			return nil, err
+		}
+		window.updated = now
+	}
+	if window != nil {
+		p.windows[key] = window
+	}
+
+	if len(completed) == 0 {
+		return nil, nil
+	}
+	return p.frame(completed), nil
+}
+
+// FlushFrames passes on the last window of the channel once no row arrived for an interval.
+func (p *DownsampleFrameProcessor) FlushFrames(_ context.Context, vars Vars, now time.Time) ([]*data.Frame, bool, error) {
+	key := orgchannel.PrependOrgID(vars.OrgID, vars.Channel)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	window, ok := p.windows[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if now.Sub(window.updated) < p.interval {
+		return nil, true, nil
+	}
+	delete(p.windows, key)
+	return []*data.Frame{p.frame([]*downsampleWindow{window})}, false, nil
+}
+
+func (w *downsampleWindow) add(frame *data.Frame, timeIndex int, row int) error {
+	w.frameName = frame.Name
+	w.timeName = "time"
+	if timeIndex >= 0 {
+		w.timeName = frame.Fields[timeIndex].Name
+	}
+	for i, f := range frame.Fields {
+		if i == timeIndex {
+			continue
+		}
+		key := f.Name + f.Labels.String()
+		field, ok := w.fields[key]
+		if !ok {
+			field = &downsampleField{name: f.Name, labels: f.Labels, typ: f.Type(), numeric: f.Type().Numeric()}
+			w.fields[key] = field
+			w.order = append(w.order, key)
+		}
+		if !field.numeric {
+			if v, ok := f.ConcreteAt(row); ok {
+				field.last = v
+			}
+			continue
+		}
+		v, err := f.NullableFloatAt(row)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		if v == nil {
+			continue
+		}
+		if field.count == 0 || *v > field.max {
+			field.max = *v
+		}
+		field.sum += *v
+		field.count++
+	}
+	return nil
+}
+
+// frame returns a row for each window, with the fields of every window.
+func (p *DownsampleFrameProcessor) frame(windows []*downsampleWindow) *data.Frame {
+	last := windows[len(windows)-1]
+	times := make([]time.Time, len(windows))
+	var order []string
+	fields := map[string]*data.Field{}
+	for i, w := range windows {
+		times[i] = w.start
+		for _, key := range w.order {
+			if _, ok := fields[key]; ok {
+				continue
+			}
+			wf := w.fields[key]
+			typ := data.FieldTypeNullableFloat64
+			if !wf.numeric {
+				typ = wf.typ.NullableType()
+			}
+			f := data.NewFieldFromFieldType(typ, len(windows))
+			f.Name = wf.name
+			f.Labels = wf.labels
+			fields[key] = f
+			order = append(order, key)
+		}
+	}
+
+	for i, w := range windows {
+		for key, wf := range w.fields {
+			f := fields[key]
+			switch {
+			case !wf.numeric:
+				if wf.last != nil && f.Type() == wf.typ.NullableType() {
+					f.SetConcrete(i, wf.last)
+				}
+			case wf.count > 0:
+				v := wf.sum / float64(wf.count)
+				if p.config.Aggregation == DownsampleAggregationMax {
+					v = wf.max
+				}
+				f.SetConcrete(i, v)
+			}
+		}
+	}
+
+	frameFields := make([]*data.Field, 0, len(order)+1)
+	frameFields = append(frameFields, data.NewField(last.timeName, nil, times))
+	for _, key := range order {
+		frameFields = append(frameFields, fields[key])
+	}
+	return data.NewFrame(last.frameName, frameFields...)
+}
+
+// prune removes the windows of the channels without frames for a while.
+func (p *DownsampleFrameProcessor) prune(now time.Time) {
+	if now.Sub(p.lastPrune) < time.Minute {
+		return
+	}
+	p.lastPrune = now
+	idle := max(10*p.interval, time.Minute)
+	for key, w := range p.windows {
+		if now.Sub(w.updated) > idle {
+			delete(p.windows, key)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownsampleFrameProcessor(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	sample := func(offset time.Duration, value float64, state string) *data.Frame {
+		return data.NewFrame("sensor",
+			data.NewField("time", nil, []time.Time{start.Add(offset)}),
+			data.NewField("value", data.Labels{"sensor": "a"}, []float64{value}),
+			data.NewField("state", nil, []string{state}),
+		)
+	}
+
+	for aggregation, expected := range map[string][]*float64{
+		DownsampleAggregationAvg: {ptr(2), ptr(10)},
+		DownsampleAggregationMax: {ptr(3), ptr(10)},
+	} {
+		t.Run(aggregation, func(t *testing.T) {
+			p, err := NewDownsampleFrameProcessor(DownsampleFrameProcessorConfig{
+				IntervalMilliseconds: 10000,
+				Aggregation:          aggregation,
+			})
+			require.NoError(t, err)
+			vars := Vars{OrgID: 1, Channel: "stream/sensors/a"}
+
+			// The frames of the first window are held back
+			for i, v := range []float64{1, 2, 3} {
+				frame, err := p.ProcessFrame(context.Background(), vars, sample(time.Duration(i)*time.Second, v, "ok"))
+				require.NoError(t, err)
+				require.Nil(t, frame)
+			}
+
+			// A row of a later window outputs the completed one
+			frame, err := p.ProcessFrame(context.Background(), vars, sample(12*time.Second, 10, "warn"))
+			require.NoError(t, err)
+			require.NotNil(t, frame)
+			require.Equal(t, 1, frame.Rows())
+			require.Equal(t, start, frame.Fields[0].At(0))
+			require.Equal(t, expected[0], frame.Fields[1].At(0))
+
+			frame, err = p.ProcessFrame(context.Background(), vars, sample(25*time.Second, 20, "ok"))
+			require.NoError(t, err)
+			require.NotNil(t, frame)
+			require.Equal(t, "sensor", frame.Name)
+			require.Equal(t, 1, frame.Rows())
+			require.Equal(t, start.Add(10*time.Second), frame.Fields[0].At(0))
+			require.Equal(t, expected[1], frame.Fields[1].At(0))
+			require.Equal(t, data.Labels{"sensor": "a"}, frame.Fields[1].Labels)
+			state := "warn"
+			require.Equal(t, &state, frame.Fields[2].At(0))
+
+			// Several windows in one frame
+			frame, err = p.ProcessFrame(context.Background(), vars, data.NewFrame("sensor",
+				data.NewField("time", nil, []time.Time{start.Add(31 * time.Second), start.Add(45 * time.Second)}),
+				data.NewField("value", data.Labels{"sensor": "a"}, []float64{1, 2}),
+			))
+			require.NoError(t, err)
+			require.Equal(t, 2, frame.Rows())
+			require.Equal(t, ptr(20), frame.Fields[1].At(0))
+			require.Equal(t, ptr(1), frame.Fields[1].At(1))
+		})
+	}
+
+	_, err := NewDownsampleFrameProcessor(DownsampleFrameProcessorConfig{IntervalMilliseconds: 1000, Aggregation: "median"})
+	require.Error(t, err)
+}
+
+func TestDownsampleFrameProcessor_channels(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	p, err := NewDownsampleFrameProcessor(DownsampleFrameProcessorConfig{IntervalMilliseconds: 1000})
+	require.NoError(t, err)
+
+	frame := func(offset time.Duration) *data.Frame {
+		return data.NewFrame("", data.NewField("time", nil, []time.Time{start.Add(offset)}), data.NewField("v", nil, []float64{1}))
+	}
+	out, err := p.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: "stream/a"}, frame(0))
+	require.NoError(t, err)
+	require.Nil(t, out)
+	// Each channel has its own windows
+	out, err = p.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: "stream/b"}, frame(2*time.Second))
+	require.NoError(t, err)
+	require.Nil(t, out)
+	out, err = p.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: "stream/a"}, frame(2*time.Second))
+	require.NoError(t, err)
+	require.NotNil(t, out)
+}
+
+func TestDownsampleFrameProcessor_FlushFrames(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	p, err := NewDownsampleFrameProcessor(DownsampleFrameProcessorConfig{IntervalMilliseconds: 1000})
+	require.NoError(t, err)
+	now := start
+	p.now = func() time.Time { return now }
+	vars := Vars{OrgID: 1, Channel: "stream/a"}
+
+	frames, held, err := p.FlushFrames(context.Background(), vars, now)
+	require.NoError(t, err)
+	require.Empty(t, frames)
+	require.False(t, held)
+
+	out, err := p.ProcessFrame(context.Background(), vars, data.NewFrame("", data.NewField("time", nil, []time.Time{start}), data.NewField("v", nil, []float64{4})))
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	// The last window is held while rows may still arrive
+	frames, held, err = p.FlushFrames(context.Background(), vars, now.Add(500*time.Millisecond))
+	require.NoError(t, err)
+	require.Empty(t, frames)
+	require.True(t, held)
+
+	frames, held, err = p.FlushFrames(context.Background(), vars, now.Add(time.Second))
+	require.NoError(t, err)
+	require.False(t, held)
+	require.Len(t, frames, 1)
+	require.Equal(t, start, frames[0].Fields[0].At(0))
+	require.Equal(t, ptr(4), frames[0].Fields[1].At(0))
+
+	frames, held, err = p.FlushFrames(context.Background(), vars, now.Add(2*time.Second))
+	require.NoError(t, err)
+	require.Empty(t, frames)
+	require.False(t, held)
+}
@@ -0,0 +1,82 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/template"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// LabelsFrameProcessor adds labels to the value fields of a data.Frame.
+// Label values are templates, executed with the channel parts and the
+// frame name, for example {{ .Path }} or {{ .Frame }}.
+type LabelsFrameProcessor struct {
+	config    LabelsFrameProcessorConfig
+	templates map[string]*template.Template
+}
+
+// labelTemplateData is the data available to label templates.
+type labelTemplateData struct {
+	Channel   string
+	Scope     string
+	Namespace string
+	Path      string
+	Frame     string
+}
+
+func NewLabelsFrameProcessor(config LabelsFrameProcessorConfig) (*LabelsFrameProcessor, error) {
+	templates := make(map[string]*template.Template, len(config.Labels))
+	for name, value := range config.Labels {
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template for label %s: %w", name, err)
+		}
+		templates[name] = tmpl
+	}
+	return &LabelsFrameProcessor{config: config, templates: templates}, nil
+}
+
+const FrameProcessorTypeLabels = "labels"
+
+func (p *LabelsFrameProcessor) Type() string {
+	return FrameProcessorTypeLabels
+}
+
+func (p *LabelsFrameProcessor) ProcessFrame(_ context.Context, vars Vars, frame *data.Frame) (*data.Frame, error) {
+	templateData := labelTemplateData{
+		Channel:   vars.Channel,
+		Scope:     vars.Scope,
+		Namespace: vars.Namespace,
+		Path:      vars.Path,
+		Frame:     frame.Name,
+	}
+	labels := make(data.Labels, len(p.templates))
+	for name, tmpl := range p.templates {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, templateData); err != nil {
+			return nil, fmt.Errorf("error executing template for label %s: %w", name, err)
+		}
+		labels[name] = buf.String()
+	}
+
+	for _, field := range frame.Fields {
+		if field.Type().Time() {
+			continue
+		}
+		if len(p.config.FieldNames) > 0 && !stringInSlice(field.Name, p.config.FieldNames) {
+			continue
+		}
+		if field.Labels == nil {
+			field.Labels = data.Labels{}
+		} else {
+			// Labels may be shared between fields.
+			field.Labels = field.Labels.Copy()
+		}
+		for name, value := range labels {
+			field.Labels[name] = value
+		}
+	}
+	return frame, nil
+}
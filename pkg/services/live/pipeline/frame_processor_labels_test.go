@@ -0,0 +1,33 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelsFrameProcessor(t *testing.T) {
+	shared := data.Labels{"host": "a"}
+	frame := data.NewFrame("cpu",
+		data.NewField("time", nil, []time.Time{{}}),
+		data.NewField("user", shared, []float64{1}),
+		data.NewField("system", shared, []float64{1}),
+	)
+	p, err := NewLabelsFrameProcessor(LabelsFrameProcessorConfig{
+		Labels:     map[string]string{"env": "prod", "sensor": "{{ .Path }}/{{ .Frame }}"},
+		FieldNames: []string{"user"},
+	})
+	require.NoError(t, err)
+
+	frame, err = p.ProcessFrame(context.Background(), Vars{Path: "room-1"}, frame)
+	require.NoError(t, err)
+	require.Nil(t, frame.Fields[0].Labels)
+	require.Equal(t, data.Labels{"host": "a", "env": "prod", "sensor": "room-1/cpu"}, frame.Fields[1].Labels)
+	require.Equal(t, data.Labels{"host": "a"}, frame.Fields[2].Labels)
+
+	_, err = NewLabelsFrameProcessor(LabelsFrameProcessorConfig{Labels: map[string]string{"bad": "{{ .Path"}})
+	require.Error(t, err)
+}
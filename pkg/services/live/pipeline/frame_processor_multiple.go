@@ -2,6 +2,7 @@ package pipeline
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
@@ -30,6 +31,12 @@ func (p *MultipleFrameProcessor) ProcessFrame(ctx context.Context, vars Vars, fr
 	return frame, nil
 }
 
+// FlushFrames flushes the processors holding frames back, the flushed frames are
+// processed by the next processors.
+func (p *MultipleFrameProcessor) FlushFrames(ctx context.Context, vars Vars, now time.Time) ([]*data.Frame, bool, error) {
+	return flushProcessors(ctx, p.Processors, vars, now)
+}
+
 func NewMultipleFrameProcessor(processors ...FrameProcessor) *MultipleFrameProcessor {
 	return &MultipleFrameProcessor{Processors: processors}
 }
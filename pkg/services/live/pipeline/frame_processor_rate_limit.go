@@ -0,0 +1,85 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"golang.org/x/time/rate"
+
+	"github.com/grafana/grafana/pkg/services/live/orgchannel"
+)
+
+// RateLimitFrameProcessor drops the frames of a channel above the configured
+// rate, so high frequency pushes do not flood the subscribers. Each channel
+// matching the rule has its own limit. Not usable in HA setup, each instance
+// limits the frames it receives.
+type RateLimitFrameProcessor struct {
+	config RateLimitFrameProcessorConfig
+	now    func() time.Time
+
+	mu        sync.Mutex
+	limiters  map[string]*channelLimiter
+	lastPrune time.Time
+}
+
+type channelLimiter struct {
+	limiter *rate.Limiter
+	used    time.Time
+}
+
+func NewRateLimitFrameProcessor(config RateLimitFrameProcessorConfig) (*RateLimitFrameProcessor, error) {
+	if config.FramesPerSecond <= 0 {
+		return nil, fmt.Errorf("frames per second must be positive")
+	}
+	if config.Burst <= 0 {
+		config.Burst = 1
+	}
+	return &RateLimitFrameProcessor{
+		config:   config,
+		now:      time.Now,
+		limiters: map[string]*channelLimiter{},
+	}, nil
+}
+
+const FrameProcessorTypeRateLimit = "rateLimit"
+
+func (p *RateLimitFrameProcessor) Type() string {
+	return FrameProcessorTypeRateLimit
+}
+
+func (p *RateLimitFrameProcessor) ProcessFrame(_ context.Context, vars Vars, frame *data.Frame) (*data.Frame, error) {
+	key := orgchannel.PrependOrgID(vars.OrgID, vars.Channel)
+	now := p.now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.prune(now)
+
+	l, ok := p.limiters[key]
+	if !ok {
+		l = &channelLimiter{limiter: rate.NewLimiter(rate.Limit(p.config.FramesPerSecond), p.config.Burst)}
+		p.limiters[key] = l
+	}
+	l.used = now
+	if !l.limiter.AllowN(now, 1) {
+		return nil, nil
+	}
+	return frame, nil
+}
+
+// prune removes the limiters of the channels idle long enough for their burst to be full again.
+func (p *RateLimitFrameProcessor) prune(now time.Time) {
+	if now.Sub(p.lastPrune) < time.Minute {
+		return
+	}
+	p.lastPrune = now
+	idle := max(time.Minute, time.Duration(float64(p.config.Burst)/p.config.FramesPerSecond*float64(time.Second)))
+	for key, l := range p.limiters {
+		if now.Sub(l.used) > idle {
+			delete(p.limiters, key)
+		}
+	}
+}
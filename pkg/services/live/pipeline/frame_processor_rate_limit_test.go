@@ -0,0 +1,35 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitFrameProcessor(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	p, err := NewRateLimitFrameProcessor(RateLimitFrameProcessorConfig{FramesPerSecond: 2})
+	require.NoError(t, err)
+	p.now = func() time.Time { return now }
+
+	passed := func(channel string) bool {
+		frame, err := p.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: channel}, data.NewFrame("test"))
+		require.NoError(t, err)
+		return frame != nil
+	}
+
+	require.True(t, passed("stream/a"))
+	require.False(t, passed("stream/a"))
+	// The other channels have their own limit
+	require.True(t, passed("stream/b"))
+
+	now = now.Add(500 * time.Millisecond)
+	require.True(t, passed("stream/a"))
+	require.False(t, passed("stream/a"))
+
+	_, err = NewRateLimitFrameProcessor(RateLimitFrameProcessorConfig{})
+	require.Error(t, err)
+}
@@ -0,0 +1,31 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// RenameFieldsFrameProcessor renames the fields of a data.Frame.
+type RenameFieldsFrameProcessor struct {
+	config RenameFieldsFrameProcessorConfig
+}
+
+func NewRenameFieldsFrameProcessor(config RenameFieldsFrameProcessorConfig) *RenameFieldsFrameProcessor {
+	return &RenameFieldsFrameProcessor{config: config}
+}
+
+const FrameProcessorTypeRenameFields = "renameFields"
+
+func (p *RenameFieldsFrameProcessor) Type() string {
+	return FrameProcessorTypeRenameFields
+}
+
+func (p *RenameFieldsFrameProcessor) ProcessFrame(_ context.Context, _ Vars, frame *data.Frame) (*data.Frame, error) {
+	for _, field := range frame.Fields {
+		if name, ok := p.config.Names[field.Name]; ok {
+			field.Name = name
+		}
+	}
+	return frame, nil
+}
@@ -0,0 +1,116 @@
+package pipeline
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// metricSample is a single value of a time series.
+type metricSample struct {
+	Name   string
+	Labels data.Labels
+	Time   time.Time
+	Value  *float64
+}
+
+// metricFrames groups samples into wide frames: a time field followed by one
+// labeled value field for each series.
+type metricFrames struct {
+	order  []string
+	frames map[string]*metricFrameBuilder
+}
+
+type metricFrameBuilder struct {
+	name   string
+	times  map[time.Time]struct{}
+	order  []string
+	series map[string]*metricSeries
+}
+
+type metricSeries struct {
+	name   string
+	labels data.Labels
+	values map[time.Time]*float64
+}
+
+func newMetricFrames() *metricFrames {
+	return &metricFrames{frames: map[string]*metricFrameBuilder{}}
+}
+
+// add the sample to the frame with the key, frames keep the order of their first sample.
+func (m *metricFrames) add(key string, frameName string, s metricSample) {
+	f, ok := m.frames[key]
+	if !ok {
+		f = &metricFrameBuilder{
+			name:   frameName,
+			times:  map[time.Time]struct{}{},
+			series: map[string]*metricSeries{},
+		}
+		m.frames[key] = f
+		m.order = append(m.order, key)
+	}
+	seriesKey := s.Name + s.Labels.String()
+	series, ok := f.series[seriesKey]
+	if !ok {
+		series = &metricSeries{name: s.Name, labels: s.Labels, values: map[time.Time]*float64{}}
+		f.series[seriesKey] = series
+		f.order = append(f.order, seriesKey)
+	}
+	f.times[s.Time] = struct{}{}
+	series.values[s.Time] = s.Value
+}
+
+func (m *metricFrames) keys() []string {
+	return m.order
+}
+
+// frame returns the frame with the key, rows are sorted by time and the
+// series missing a value at some time get a null.
+func (m *metricFrames) frame(key string) *data.Frame {
+	f := m.frames[key]
+	times := make([]time.Time, 0, len(f.times))
+	for t := range f.times {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+
+	fields := make([]*data.Field, 0, len(f.order)+1)
+	fields = append(fields, data.NewField("time", nil, times))
+	for _, key := range f.order {
+		series := f.series[key]
+		values := make([]*float64, len(times))
+		for i, t := range times {
+			values[i] = series.values[t]
+		}
+		fields = append(fields, data.NewField(series.name, series.labels, values))
+	}
+	return data.NewFrame(f.name, fields...)
+}
+
+// channelPathSegment replaces the characters not allowed in a channel path.
+func channelPathSegment(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-', r == '.':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// channelFramesByMetric returns a frame for each key, sent to the channel + / + key.
+func channelFramesByMetric(channel string, frames *metricFrames) []*ChannelFrame {
+	channelFrames := make([]*ChannelFrame, 0, len(frames.keys()))
+	for _, key := range frames.keys() {
+		channelFrames = append(channelFrames, &ChannelFrame{
+			Channel: channel + "/" + channelPathSegment(key),
+			Frame:   frames.frame(key),
+		})
+	}
+	return channelFrames
+}
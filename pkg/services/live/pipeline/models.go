@@ -2,6 +2,7 @@ package pipeline
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/grafana/grafana/pkg/services/live/pipeline/pattern"
 	"github.com/grafana/grafana/pkg/services/live/pipeline/tree"
@@ -154,8 +155,36 @@ type ChannelRuleCreateCmd struct {
 type ChannelRuleUpdateCmd struct {
 	Pattern  string              `json:"pattern"`
 	Settings ChannelRuleSettings `json:"settings"`
+	// Version of the rule the update is based on. When set, the update fails
+	// with ErrChannelRuleVersionConflict if the rule was changed meanwhile.
+	Version int64 `json:"version,omitempty"`
 }
 
 type ChannelRuleDeleteCmd struct {
 	Pattern string `json:"pattern"`
 }
+
+type ChannelRuleVersionsCmd struct {
+	Pattern string `json:"pattern"`
+}
+
+type ChannelRuleRollbackCmd struct {
+	Pattern string `json:"pattern"`
+	// Version to restore, the rule gets a new version with its settings.
+	Version int64 `json:"version"`
+}
+
+// ChannelRuleVersion is a past state of a channel rule.
+type ChannelRuleVersion struct {
+	OrgId   int64     `json:"orgId"`
+	Pattern string    `json:"pattern"`
+	Version int64     `json:"version"`
+	Created time.Time `json:"created"`
+	// Deleted is set on the version recording the rule deletion.
+	Deleted  bool                `json:"deleted,omitempty"`
+	Settings ChannelRuleSettings `json:"settings"`
+}
+
+type ChannelRuleVersions struct {
+	Versions []ChannelRuleVersion `json:"versions"`
+}
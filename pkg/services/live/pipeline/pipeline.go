@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -182,6 +183,10 @@ type ChannelRuleGetter interface {
 type Pipeline struct {
 	ruleGetter ChannelRuleGetter
 	tracer     trace.Tracer
+
+	flushMu       sync.Mutex
+	flushChannels map[string]*flushedChannel
+	flushRunning  bool
 }
 
 // New creates new Pipeline.
@@ -405,6 +410,8 @@ func (p *Pipeline) processFrame(ctx context.Context, orgID int64, channelID stri
 		Path:      ch.Path,
 	}
 
+	p.watchFlush(orgID, channelID, rule)
+
 	if len(rule.FrameProcessors) > 0 {
 		for _, proc := range rule.FrameProcessors {
 			frame, err = p.execProcessor(ctx, proc, vars, frame)
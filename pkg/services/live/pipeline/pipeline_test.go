@@ -5,6 +5,7 @@ import (
 	"errors"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
@@ -142,3 +143,51 @@ func TestPipeline_Recursion(t *testing.T) {
 	_, err = p.ProcessInput(context.Background(), 1, "stream/test/xxx", []byte(`{}`))
 	require.ErrorIs(t, err, errChannelRecursion)
 }
+
+type chanOutputter struct {
+	frames chan *data.Frame
+}
+
+func (t *chanOutputter) Type() string {
+	return "test"
+}
+
+func (t *chanOutputter) OutputFrame(_ context.Context, _ Vars, frame *data.Frame) ([]*ChannelFrame, error) {
+	t.frames <- frame
+	return nil, nil
+}
+
+func TestPipeline_Flush(t *testing.T) {
+	downsample, err := NewDownsampleFrameProcessor(DownsampleFrameProcessorConfig{IntervalMilliseconds: 10})
+	require.NoError(t, err)
+	outputter := &chanOutputter{frames: make(chan *data.Frame, 1)}
+	p, err := New(&testRuleGetter{
+		rules: map[string]*LiveChannelRule{
+			"stream/test/xxx": {
+				Converter: &testConverter{"", data.NewFrame("test",
+					data.NewField("time", nil, []time.Time{time.Now()}),
+					data.NewField("value", nil, []float64{1}),
+				)},
+				FrameProcessors: []FrameProcessor{NewMultipleFrameProcessor(downsample, &testProcessor{})},
+				FrameOutputters: []FrameOutputter{outputter},
+			},
+		},
+	})
+	require.NoError(t, err)
+	ok, err := p.ProcessInput(context.Background(), 1, "stream/test/xxx", []byte(`{}`))
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// The window held back by the downsample is output without other frames.
+	select {
+	case frame := <-outputter.frames:
+		require.Equal(t, 1, frame.Rows())
+	case <-time.After(5 * time.Second):
+		t.Fatal("frame not flushed")
+	}
+	require.Eventually(t, func() bool {
+		p.flushMu.Lock()
+		defer p.flushMu.Unlock()
+		return !p.flushRunning
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -48,6 +48,22 @@ var FrameOutputsRegistry = []EntityInfo{
 		Type:        FrameOutputTypeLoki,
 		Description: "output frame as JSON to Loki",
 	},
+	{
+		Type:        FrameOutputTypeWebhook,
+		Description: "output frames as JSON to an HTTP endpoint in batches",
+		Example: WebhookOutputConfig{
+			BatchSize:         100,
+			FlushMilliseconds: 1000,
+			MaxRetries:        3,
+		},
+	},
+	{
+		Type:        FrameOutputTypeNATS,
+		Description: "publish frames as JSON to a NATS server",
+		Example: NATSOutputConfig{
+			Subject: "telemetry.edge",
+		},
+	},
 }
 
 var ConvertersRegistry = []EntityInfo{
@@ -66,6 +82,25 @@ var ConvertersRegistry = []EntityInfo{
 		Type:        ConverterTypeJsonFrame,
 		Description: "JSON-encoded Grafana data frame",
 	},
+	{
+		Type:        ConverterTypePrometheus,
+		Description: "accept Prometheus text exposition format",
+	},
+	{
+		Type:        ConverterTypeOTLP,
+		Description: "accept OpenTelemetry metrics (OTLP/HTTP protobuf or JSON)",
+		Example: OTLPConverterConfig{
+			ResourceAttributes: []string{"service.name", "host.name"},
+		},
+	},
+	{
+		Type:        ConverterTypeCSV,
+		Description: "accept CSV lines, with a header or configured columns",
+		Example: CSVConverterConfig{
+			TimeColumn:   "time",
+			LabelColumns: []string{"host"},
+		},
+	},
 }
 
 var FrameProcessorsRegistry = []EntityInfo{
@@ -79,6 +114,43 @@ var FrameProcessorsRegistry = []EntityInfo{
 		Description: "list the fields that should be removed",
 		Example:     DropFieldsFrameProcessorConfig{},
 	},
+	{
+		Type:        FrameProcessorTypeRenameFields,
+		Description: "rename fields",
+		Example: RenameFieldsFrameProcessorConfig{
+			Names: map[string]string{"temp": "temperature"},
+		},
+	},
+	{
+		Type:        FrameProcessorTypeLabels,
+		Description: "add constant or templated labels to the fields",
+		Example: LabelsFrameProcessorConfig{
+			Labels: map[string]string{"sensor": "{{ .Path }}"},
+		},
+	},
+	{
+		Type:        FrameProcessorTypeDerivedField,
+		Description: "add a field computed from an expression of the other fields",
+		Example: DerivedFieldFrameProcessorConfig{
+			Name:       "fahrenheit",
+			Expression: "temperature * 1.8 + 32",
+		},
+	},
+	{
+		Type:        FrameProcessorTypeDownsample,
+		Description: "aggregate values over time windows",
+		Example: DownsampleFrameProcessorConfig{
+			IntervalMilliseconds: 5000,
+			Aggregation:          DownsampleAggregationAvg,
+		},
+	},
+	{
+		Type:        FrameProcessorTypeRateLimit,
+		Description: "drop frames above a rate for each channel",
+		Example: RateLimitFrameProcessorConfig{
+			FramesPerSecond: 1,
+		},
+	},
 }
 
 var DataOutputsRegistry = []EntityInfo{
@@ -3,10 +3,13 @@ package pipeline
 import (
 	"context"
 	"fmt"
+	"io"
+	"sync"
 
 	"github.com/centrifugal/centrifuge"
 
 	"github.com/grafana/grafana/pkg/services/live/managedstream"
+	"github.com/grafana/grafana/pkg/services/live/nats"
 	"github.com/grafana/grafana/pkg/services/secrets"
 )
 
@@ -17,6 +20,105 @@ type StorageRuleBuilder struct {
 	Storage              Storage
 	ChannelHandlerGetter ChannelHandlerGetter
 	SecretsService       secrets.Service
+
+	buildMu sync.Mutex
+	shared  sharedEntities
+}
+
+// sharedEntities keeps the outputs holding connections or background flushes,
+// and the processors holding the state of the channels, by configuration so
+// they are reused when the rules are built again. The entities no longer used
+// by the rules of any org are closed after a build.
+type sharedEntities struct {
+	mu       sync.Mutex
+	entities map[string]*sharedEntity
+	// used collects the keys of the entities used by the rules being built.
+	used map[string]struct{}
+}
+
+type sharedEntity struct {
+	entity any
+	// orgs with rules using the entity.
+	orgs map[int64]struct{}
+}
+
+func getOrCreateShared[T any](s *sharedEntities, key string, create func() (T, error)) (T, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.used != nil {
+		s.used[key] = struct{}{}
+	}
+	if shared, ok := s.entities[key]; ok {
+		if entity, ok := shared.entity.(T); ok {
+			return entity, nil
+		}
+	}
+	entity, err := create()
+	if err != nil {
+		return entity, err
+	}
+	if s.entities == nil {
+		s.entities = map[string]*sharedEntity{}
+	}
+	s.entities[key] = &sharedEntity{entity: entity, orgs: map[int64]struct{}{}}
+	return entity, nil
+}
+
+func (s *sharedEntities) startBuild() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.used = map[string]struct{}{}
+}
+
+// endBuild updates the orgs using the entities after a build of the rules of
+// the org, and closes the entities no longer used. When the build failed the
+// org keeps its previous rules and entities.
+func (s *sharedEntities) endBuild(orgID int64, built bool) {
+	s.mu.Lock()
+	var unused []any
+	for key, shared := range s.entities {
+		if built {
+			if _, ok := s.used[key]; ok {
+				shared.orgs[orgID] = struct{}{}
+			} else {
+				delete(shared.orgs, orgID)
+			}
+		}
+		if len(shared.orgs) == 0 {
+			delete(s.entities, key)
+			unused = append(unused, shared.entity)
+		}
+	}
+	s.used = nil
+	s.mu.Unlock()
+	closeEntities(unused)
+}
+
+func (s *sharedEntities) close() {
+	s.mu.Lock()
+	entities := make([]any, 0, len(s.entities))
+	for _, shared := range s.entities {
+		entities = append(entities, shared.entity)
+	}
+	s.entities = nil
+	s.mu.Unlock()
+	closeEntities(entities)
+}
+
+func closeEntities(entities []any) {
+	for _, entity := range entities {
+		if closer, ok := entity.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				logger.Error("Error closing unused pipeline entity", "error", err)
+			}
+		}
+	}
+}
+
+// Close closes the outputs and processors of the built rules, for builders
+// used only to check or test rules.
+func (f *StorageRuleBuilder) Close() {
+	f.shared.close()
 }
 
 func (f *StorageRuleBuilder) extractSubscriber(config *SubscriberConfig) (Subscriber, error) {
@@ -69,12 +171,29 @@ func (f *StorageRuleBuilder) extractConverter(config *ConverterConfig) (Converte
 			return nil, missingConfiguration
 		}
 		return NewAutoInfluxConverter(*config.AutoInfluxConverterConfig), nil
+	case ConverterTypePrometheus:
+		if config.PrometheusConverterConfig == nil {
+			config.PrometheusConverterConfig = &PrometheusConverterConfig{}
+		}
+		return NewPrometheusConverter(*config.PrometheusConverterConfig), nil
+	case ConverterTypeOTLP:
+		if config.OTLPConverterConfig == nil {
+			config.OTLPConverterConfig = &OTLPConverterConfig{}
+		}
+		return NewOTLPConverter(*config.OTLPConverterConfig), nil
+	case ConverterTypeCSV:
+		if config.CSVConverterConfig == nil {
+			config.CSVConverterConfig = &CSVConverterConfig{}
+		}
+		return NewCSVConverter(*config.CSVConverterConfig), nil
 	default:
 		return nil, fmt.Errorf("unknown converter type: %s", config.Type)
 	}
 }
 
-func (f *StorageRuleBuilder) extractFrameProcessor(config *FrameProcessorConfig) (FrameProcessor, error) {
+// extractFrameProcessor builds the processor at the position in the rule, the processors keeping
+// state are shared with the previous builds of the rule while their position and configuration are the same.
+func (f *StorageRuleBuilder) extractFrameProcessor(config *FrameProcessorConfig, position string) (FrameProcessor, error) {
 	if config == nil {
 		return nil, nil
 	}
@@ -95,15 +214,54 @@ func (f *StorageRuleBuilder) extractFrameProcessor(config *FrameProcessorConfig)
 			return nil, missingConfiguration
 		}
 		var processors []FrameProcessor
-		for _, outConf := range config.MultipleProcessorConfig.Processors {
+		for i, outConf := range config.MultipleProcessorConfig.Processors {
 			out := outConf
-			proc, err := f.extractFrameProcessor(&out)
+			proc, err := f.extractFrameProcessor(&out, fmt.Sprintf("%s/%d", position, i))
 			if err != nil {
 				return nil, err
 			}
 			processors = append(processors, proc)
 		}
 		return NewMultipleFrameProcessor(processors...), nil
+	case FrameProcessorTypeRenameFields:
+		if config.RenameFieldsProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewRenameFieldsFrameProcessor(*config.RenameFieldsProcessorConfig), nil
+	case FrameProcessorTypeLabels:
+		if config.LabelsProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewLabelsFrameProcessor(*config.LabelsProcessorConfig)
+	case FrameProcessorTypeDerivedField:
+		if config.DerivedFieldProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewDerivedFieldFrameProcessor(*config.DerivedFieldProcessorConfig)
+	case FrameProcessorTypeDownsample:
+		if config.DownsampleProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		key := fmt.Sprintf("%s/%s/%+v", FrameProcessorTypeDownsample, position, *config.DownsampleProcessorConfig)
+		proc, err := getOrCreateShared(&f.shared, key, func() (*DownsampleFrameProcessor, error) {
+			return NewDownsampleFrameProcessor(*config.DownsampleProcessorConfig)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return proc, nil
+	case FrameProcessorTypeRateLimit:
+		if config.RateLimitProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		key := fmt.Sprintf("%s/%s/%+v", FrameProcessorTypeRateLimit, position, *config.RateLimitProcessorConfig)
+		proc, err := getOrCreateShared(&f.shared, key, func() (*RateLimitFrameProcessor, error) {
+			return NewRateLimitFrameProcessor(*config.RateLimitProcessorConfig)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return proc, nil
 	default:
 		return nil, fmt.Errorf("unknown processor type: %s", config.Type)
 	}
@@ -249,6 +407,52 @@ func (f *StorageRuleBuilder) extractFrameOutputter(config *FrameOutputterConfig,
 			return nil, missingConfiguration
 		}
 		return NewChangeLogFrameOutput(f.FrameStorage, *config.ChangeLogOutputConfig), nil
+	case FrameOutputTypeWebhook:
+		if config.WebhookOutputConfig == nil {
+			return nil, missingConfiguration
+		}
+		writeConfig, ok := f.getWriteConfig(config.WebhookOutputConfig.UID, writeConfigs)
+		if !ok {
+			return nil, fmt.Errorf("unknown write config uid: %s", config.WebhookOutputConfig.UID)
+		}
+		basicAuth, err := f.constructBasicAuth(writeConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error getting password: %w", err)
+		}
+		key := fmt.Sprintf("%s/%s/%v/%+v", FrameOutputTypeWebhook, writeConfig.Settings.Endpoint, basicAuth, *config.WebhookOutputConfig)
+		output, err := getOrCreateShared(&f.shared, key, func() (*WebhookFrameOutput, error) {
+			return NewWebhookFrameOutput(writeConfig.Settings.Endpoint, basicAuth, *config.WebhookOutputConfig), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return output, nil
+	case FrameOutputTypeNATS:
+		if config.NATSOutputConfig == nil {
+			return nil, missingConfiguration
+		}
+		writeConfig, ok := f.getWriteConfig(config.NATSOutputConfig.UID, writeConfigs)
+		if !ok {
+			return nil, fmt.Errorf("unknown write config uid: %s", config.NATSOutputConfig.UID)
+		}
+		basicAuth, err := f.constructBasicAuth(writeConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error getting password: %w", err)
+		}
+		// Outputs publishing to the same server share the connection.
+		key := fmt.Sprintf("%s/%s/%v", FrameOutputTypeNATS, writeConfig.Settings.Endpoint, basicAuth)
+		publisher, err := getOrCreateShared(&f.shared, key, func() (*nats.Publisher, error) {
+			opts := nats.Options{}
+			if basicAuth != nil {
+				opts.User = basicAuth.User
+				opts.Password = basicAuth.Password
+			}
+			return nats.NewPublisher(writeConfig.Settings.Endpoint, opts)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return NewNATSFrameOutput(publisher, *config.NATSOutputConfig), nil
 	default:
 		return nil, fmt.Errorf("unknown output type: %s", config.Type)
 	}
@@ -300,6 +504,15 @@ func (f *StorageRuleBuilder) getWriteConfig(uid string, writeConfigs []WriteConf
 }
 
 func (f *StorageRuleBuilder) BuildRules(ctx context.Context, orgID int64) ([]*LiveChannelRule, error) {
+	f.buildMu.Lock()
+	defer f.buildMu.Unlock()
+	f.shared.startBuild()
+	rules, err := f.buildRules(ctx, orgID)
+	f.shared.endBuild(orgID, err == nil)
+	return rules, err
+}
+
+func (f *StorageRuleBuilder) buildRules(ctx context.Context, orgID int64) ([]*LiveChannelRule, error) {
 	channelRules, err := f.Storage.ListChannelRules(ctx, orgID)
 	if err != nil {
 		return nil, err
@@ -313,68 +526,90 @@ func (f *StorageRuleBuilder) BuildRules(ctx context.Context, orgID int64) ([]*Li
 	rules := make([]*LiveChannelRule, 0, len(channelRules))
 
 	for _, ruleConfig := range channelRules {
-		rule := &LiveChannelRule{
-			OrgId:   orgID,
-			Pattern: ruleConfig.Pattern,
+		rule, err := f.buildRule(orgID, ruleConfig, writeConfigs)
+		if err != nil {
+			return nil, err
 		}
+		rules = append(rules, rule)
+	}
 
-		if ruleConfig.Settings.Auth != nil && ruleConfig.Settings.Auth.Subscribe != nil {
-			rule.SubscribeAuth = NewRoleCheckAuthorizer(ruleConfig.Settings.Auth.Subscribe.RequireRole)
-		}
+	return rules, nil
+}
 
-		if ruleConfig.Settings.Auth != nil && ruleConfig.Settings.Auth.Publish != nil {
-			rule.PublishAuth = NewRoleCheckAuthorizer(ruleConfig.Settings.Auth.Publish.RequireRole)
-		}
+// BuildRule builds a single rule with the write configs of the org, so rule
+// settings can be checked or tested before they are saved.
+func (f *StorageRuleBuilder) BuildRule(ctx context.Context, orgID int64, ruleConfig ChannelRule) (*LiveChannelRule, error) {
+	ok, reason := ruleConfig.Valid()
+	if !ok {
+		return nil, fmt.Errorf("invalid channel rule: %s", reason)
+	}
+	writeConfigs, err := f.Storage.ListWriteConfigs(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return f.buildRule(orgID, ruleConfig, writeConfigs)
+}
 
-		var err error
+func (f *StorageRuleBuilder) buildRule(orgID int64, ruleConfig ChannelRule, writeConfigs []WriteConfig) (*LiveChannelRule, error) {
+	rule := &LiveChannelRule{
+		OrgId:   orgID,
+		Pattern: ruleConfig.Pattern,
+	}
 
-		rule.Converter, err = f.extractConverter(ruleConfig.Settings.Converter)
-		if err != nil {
-			return nil, fmt.Errorf("error building converter for %s: %w", rule.Pattern, err)
-		}
+	if ruleConfig.Settings.Auth != nil && ruleConfig.Settings.Auth.Subscribe != nil {
+		rule.SubscribeAuth = NewRoleCheckAuthorizer(ruleConfig.Settings.Auth.Subscribe.RequireRole)
+	}
 
-		var processors []FrameProcessor
-		for _, procConfig := range ruleConfig.Settings.FrameProcessors {
-			proc, err := f.extractFrameProcessor(procConfig)
-			if err != nil {
-				return nil, fmt.Errorf("error building processor for %s: %w", rule.Pattern, err)
-			}
-			processors = append(processors, proc)
-		}
-		rule.FrameProcessors = processors
+	if ruleConfig.Settings.Auth != nil && ruleConfig.Settings.Auth.Publish != nil {
+		rule.PublishAuth = NewRoleCheckAuthorizer(ruleConfig.Settings.Auth.Publish.RequireRole)
+	}
 
-		var dataOutputters []DataOutputter
-		for _, outConfig := range ruleConfig.Settings.DataOutputters {
-			out, err := f.extractDataOutputter(outConfig, writeConfigs)
-			if err != nil {
-				return nil, fmt.Errorf("error building data outputter for %s: %w", rule.Pattern, err)
-			}
-			dataOutputters = append(dataOutputters, out)
+	var err error
+
+	rule.Converter, err = f.extractConverter(ruleConfig.Settings.Converter)
+	if err != nil {
+		return nil, fmt.Errorf("error building converter for %s: %w", rule.Pattern, err)
+	}
+
+	var processors []FrameProcessor
+	for i, procConfig := range ruleConfig.Settings.FrameProcessors {
+		proc, err := f.extractFrameProcessor(procConfig, fmt.Sprintf("%d/%s/%d", orgID, rule.Pattern, i))
+		if err != nil {
+			return nil, fmt.Errorf("error building processor for %s: %w", rule.Pattern, err)
 		}
-		rule.DataOutputters = dataOutputters
+		processors = append(processors, proc)
+	}
+	rule.FrameProcessors = processors
 
-		var outputters []FrameOutputter
-		for _, outConfig := range ruleConfig.Settings.FrameOutputters {
-			out, err := f.extractFrameOutputter(outConfig, writeConfigs)
-			if err != nil {
-				return nil, fmt.Errorf("error building frame outputter for %s: %w", rule.Pattern, err)
-			}
-			outputters = append(outputters, out)
+	var dataOutputters []DataOutputter
+	for _, outConfig := range ruleConfig.Settings.DataOutputters {
+		out, err := f.extractDataOutputter(outConfig, writeConfigs)
+		if err != nil {
+			return nil, fmt.Errorf("error building data outputter for %s: %w", rule.Pattern, err)
 		}
-		rule.FrameOutputters = outputters
+		dataOutputters = append(dataOutputters, out)
+	}
+	rule.DataOutputters = dataOutputters
 
-		var subscribers []Subscriber
-		for _, subConfig := range ruleConfig.Settings.Subscribers {
-			sub, err := f.extractSubscriber(subConfig)
-			if err != nil {
-				return nil, fmt.Errorf("error building subscriber for %s: %w", rule.Pattern, err)
-			}
-			subscribers = append(subscribers, sub)
+	var outputters []FrameOutputter
+	for _, outConfig := range ruleConfig.Settings.FrameOutputters {
+		out, err := f.extractFrameOutputter(outConfig, writeConfigs)
+		if err != nil {
+			return nil, fmt.Errorf("error building frame outputter for %s: %w", rule.Pattern, err)
 		}
-		rule.Subscribers = subscribers
+		outputters = append(outputters, out)
+	}
+	rule.FrameOutputters = outputters
 
-		rules = append(rules, rule)
+	var subscribers []Subscriber
+	for _, subConfig := range ruleConfig.Settings.Subscribers {
+		sub, err := f.extractSubscriber(subConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error building subscriber for %s: %w", rule.Pattern, err)
+		}
+		subscribers = append(subscribers, sub)
 	}
+	rule.Subscribers = subscribers
 
-	return rules, nil
+	return rule, nil
 }
@@ -0,0 +1,120 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live/nats"
+)
+
+func TestStorageRuleBuilder_SharedProcessors(t *testing.T) {
+	ctx := context.Background()
+	storage := &FileStorage{DataPath: t.TempDir()}
+	builder := &StorageRuleBuilder{Storage: storage}
+
+	downsample := &FrameProcessorConfig{
+		Type:                      FrameProcessorTypeDownsample,
+		DownsampleProcessorConfig: &DownsampleFrameProcessorConfig{IntervalMilliseconds: 1000},
+	}
+	rateLimit := &FrameProcessorConfig{
+		Type:                     FrameProcessorTypeRateLimit,
+		RateLimitProcessorConfig: &RateLimitFrameProcessorConfig{FramesPerSecond: 1},
+	}
+	settings := ChannelRuleSettings{
+		FrameProcessors: []*FrameProcessorConfig{rateLimit, downsample},
+	}
+	_, err := storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/test/cpu", Settings: settings})
+	require.NoError(t, err)
+
+	rules, err := builder.BuildRules(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+
+	// The processors keep the state of the channels when the rules are built again.
+	again, err := builder.BuildRules(ctx, 1)
+	require.NoError(t, err)
+	require.Same(t, rules[0].FrameProcessors[0], again[0].FrameProcessors[0])
+	require.Same(t, rules[0].FrameProcessors[1], again[0].FrameProcessors[1])
+
+	// Processors of another rule or configuration have their own state.
+	rateLimit.RateLimitProcessorConfig.FramesPerSecond = 2
+	_, err = storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/test/cpu", Settings: settings, Version: 1})
+	require.NoError(t, err)
+	_, err = storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/test/mem", Settings: settings})
+	require.NoError(t, err)
+	updated, err := builder.BuildRules(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, updated, 2)
+	require.NotSame(t, rules[0].FrameProcessors[0], updated[0].FrameProcessors[0])
+	require.Same(t, rules[0].FrameProcessors[1], updated[0].FrameProcessors[1])
+	require.NotSame(t, updated[0].FrameProcessors[0], updated[1].FrameProcessors[0])
+}
+
+// testRuleStorage returns the rules of each org, and the write configs without encrypted settings.
+type testRuleStorage struct {
+	Storage
+	rules        map[int64][]ChannelRule
+	writeConfigs []WriteConfig
+}
+
+func (s *testRuleStorage) ListChannelRules(_ context.Context, orgID int64) ([]ChannelRule, error) {
+	return s.rules[orgID], nil
+}
+
+func (s *testRuleStorage) ListWriteConfigs(_ context.Context, _ int64) ([]WriteConfig, error) {
+	return s.writeConfigs, nil
+}
+
+func TestStorageRuleBuilder_CloseUnusedOutputs(t *testing.T) {
+	ctx := context.Background()
+	rule := ChannelRule{
+		Pattern: "stream/test/cpu",
+		Settings: ChannelRuleSettings{
+			FrameOutputters: []*FrameOutputterConfig{
+				{Type: FrameOutputTypeWebhook, WebhookOutputConfig: &WebhookOutputConfig{UID: "hook"}},
+				{Type: FrameOutputTypeNATS, NATSOutputConfig: &NATSOutputConfig{UID: "nats", Subject: "test"}},
+			},
+		},
+	}
+	storage := &testRuleStorage{
+		rules: map[int64][]ChannelRule{1: {rule}, 2: {rule}},
+		writeConfigs: []WriteConfig{
+			{UID: "hook", Settings: WriteSettings{Endpoint: "http://localhost:3001/hook"}},
+			{UID: "nats", Settings: WriteSettings{Endpoint: "nats://localhost:4222"}},
+		},
+	}
+	builder := &StorageRuleBuilder{Storage: storage}
+
+	rules, err := builder.BuildRules(ctx, 1)
+	require.NoError(t, err)
+	webhook := rules[0].FrameOutputters[0].(*WebhookFrameOutput)
+	publisher := rules[0].FrameOutputters[1].(*BrokerFrameOutput).broker.(*nats.Publisher)
+	other, err := builder.BuildRules(ctx, 2)
+	require.NoError(t, err)
+	require.Same(t, webhook, other[0].FrameOutputters[0])
+
+	closed := func() bool {
+		select {
+		case <-webhook.writer.done:
+			return true
+		default:
+			return false
+		}
+	}
+
+	// The outputs are kept while the rules of an org use them.
+	delete(storage.rules, 1)
+	rules, err = builder.BuildRules(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, rules)
+	require.False(t, closed())
+
+	delete(storage.rules, 2)
+	_, err = builder.BuildRules(ctx, 2)
+	require.NoError(t, err)
+	require.True(t, closed())
+	require.ErrorIs(t, publisher.Publish(ctx, "test", []byte(`{}`)), nats.ErrPublisherClosed)
+	require.Empty(t, builder.shared.entities)
+}
@@ -52,10 +52,7 @@ func (s *CacheSegmentedTree) fillOrg(orgID int64) error {
 	}
 	s.radixMu.Lock()
 	defer s.radixMu.Unlock()
-	s.radix[orgID] = tree.New()
-	for _, ch := range channels {
-		s.radix[orgID].AddRoute("/"+ch.Pattern, ch)
-	}
+	s.radix[orgID] = newRuleNode(channels)
 	return nil
 }
 
@@ -75,9 +72,46 @@ func (s *CacheSegmentedTree) Get(orgID int64, channel string) (*LiveChannelRule,
 	if !ok {
 		return nil, false, nil
 	}
-	nodeValue := t.GetValue("/"+channel, true)
-	if nodeValue.Handler == nil {
+	rule, ok := getRule(t, channel)
+	return rule, ok, nil
+}
+
+// RuleTree provides access to the channel rules of an org built once. Unlike
+// CacheSegmentedTree it does not update the rules in the background, so it can
+// be used for a single request.
+type RuleTree struct {
+	orgID int64
+	root  *tree.Node
+}
+
+func NewRuleTree(ctx context.Context, ruleBuilder RuleBuilder, orgID int64) (*RuleTree, error) {
+	channels, err := ruleBuilder.BuildRules(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return &RuleTree{orgID: orgID, root: newRuleNode(channels)}, nil
+}
+
+func (t *RuleTree) Get(orgID int64, channel string) (*LiveChannelRule, bool, error) {
+	if orgID != t.orgID {
 		return nil, false, nil
 	}
-	return nodeValue.Handler.(*LiveChannelRule), true, nil
+	rule, ok := getRule(t.root, channel)
+	return rule, ok, nil
+}
+
+func newRuleNode(channels []*LiveChannelRule) *tree.Node {
+	node := tree.New()
+	for _, ch := range channels {
+		node.AddRoute("/"+ch.Pattern, ch)
+	}
+	return node
+}
+
+func getRule(node *tree.Node, channel string) (*LiveChannelRule, bool) {
+	nodeValue := node.GetValue("/"+channel, true)
+	if nodeValue.Handler == nil {
+		return nil, false
+	}
+	return nodeValue.Handler.(*LiveChannelRule), true
 }
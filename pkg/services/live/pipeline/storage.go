@@ -13,4 +13,6 @@ type Storage interface {
 	CreateChannelRule(_ context.Context, orgID int64, cmd ChannelRuleCreateCmd) (ChannelRule, error)
 	UpdateChannelRule(_ context.Context, orgID int64, cmd ChannelRuleUpdateCmd) (ChannelRule, error)
 	DeleteChannelRule(_ context.Context, orgID int64, cmd ChannelRuleDeleteCmd) error
+	ListChannelRuleVersions(_ context.Context, orgID int64, cmd ChannelRuleVersionsCmd) ([]ChannelRuleVersion, error)
+	RollbackChannelRule(_ context.Context, orgID int64, cmd ChannelRuleRollbackCmd) (ChannelRule, error)
 }
@@ -7,15 +7,29 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/util"
 )
 
-// FileStorage can load channel rules from a file on disk.
+// maxChannelRuleVersions kept in the history of each channel rule.
+const maxChannelRuleVersions = 20
+
+var (
+	ErrChannelRuleVersionConflict = errors.New("channel rule was changed since the version of the update")
+	ErrChannelRuleVersionNotFound = errors.New("channel rule version not found")
+)
+
+// FileStorage can load channel rules from a file on disk. Each change of a
+// channel rule is kept in a history file, so rules can be rolled back.
 type FileStorage struct {
 	DataPath       string
 	SecretsService secrets.Service
+
+	// rulesMu serializes the changes of the rules and of their history.
+	rulesMu sync.Mutex
 }
 
 func (f *FileStorage) ListWriteConfigs(_ context.Context, orgID int64) ([]WriteConfig, error) {
@@ -159,6 +173,12 @@ func (f *FileStorage) ListChannelRules(_ context.Context, orgID int64) ([]Channe
 }
 
 func (f *FileStorage) CreateChannelRule(_ context.Context, orgID int64, cmd ChannelRuleCreateCmd) (ChannelRule, error) {
+	f.rulesMu.Lock()
+	defer f.rulesMu.Unlock()
+	return f.createChannelRule(orgID, cmd)
+}
+
+func (f *FileStorage) createChannelRule(orgID int64, cmd ChannelRuleCreateCmd) (ChannelRule, error) {
 	channelRules, err := f.readRules()
 	if err != nil {
 		return ChannelRule{}, fmt.Errorf("can't read channel rules: %w", err)
@@ -180,8 +200,7 @@ func (f *FileStorage) CreateChannelRule(_ context.Context, orgID int64, cmd Chan
 		}
 	}
 	channelRules.Rules = append(channelRules.Rules, rule)
-	err = f.saveChannelRules(orgID, channelRules)
-	return rule, err
+	return f.saveChannelRuleChange(orgID, channelRules, len(channelRules.Rules)-1, rule, false)
 }
 
 func patternMatch(orgID int64, pattern string, existingRule ChannelRule) bool {
@@ -192,7 +211,13 @@ func uidMatch(orgID int64, uid string, existingBackend WriteConfig) bool {
 	return uid == existingBackend.UID && (existingBackend.OrgId == orgID || (existingBackend.OrgId == 0 && orgID == 1))
 }
 
-func (f *FileStorage) UpdateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleUpdateCmd) (ChannelRule, error) {
+func (f *FileStorage) UpdateChannelRule(_ context.Context, orgID int64, cmd ChannelRuleUpdateCmd) (ChannelRule, error) {
+	f.rulesMu.Lock()
+	defer f.rulesMu.Unlock()
+	return f.updateChannelRule(orgID, cmd)
+}
+
+func (f *FileStorage) updateChannelRule(orgID int64, cmd ChannelRuleUpdateCmd) (ChannelRule, error) {
 	channelRules, err := f.readRules()
 	if err != nil {
 		return ChannelRule{}, fmt.Errorf("can't read channel rules: %w", err)
@@ -218,13 +243,16 @@ func (f *FileStorage) UpdateChannelRule(ctx context.Context, orgID int64, cmd Ch
 		}
 	}
 	if index > -1 {
+		if cmd.Version != 0 && cmd.Version != channelRules.Rules[index].Version {
+			return rule, ErrChannelRuleVersionConflict
+		}
+		rule.Version = channelRules.Rules[index].Version
 		channelRules.Rules[index] = rule
 	} else {
-		return f.CreateChannelRule(ctx, orgID, ChannelRuleCreateCmd(cmd))
+		return f.createChannelRule(orgID, ChannelRuleCreateCmd{Pattern: cmd.Pattern, Settings: cmd.Settings})
 	}
 
-	err = f.saveChannelRules(orgID, channelRules)
-	return rule, err
+	return f.saveChannelRuleChange(orgID, channelRules, index, rule, false)
 }
 
 func removeChannelRuleByIndex(s []ChannelRule, index int) []ChannelRule {
@@ -240,6 +268,9 @@ func (f *FileStorage) readRules() (ChannelRules, error) {
 	// Safe to ignore gosec warning G304.
 	// nolint:gosec
 	ruleBytes, err := os.ReadFile(ruleFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return ChannelRules{}, nil
+	}
 	if err != nil {
 		return ChannelRules{}, fmt.Errorf("can't read pipeline rules: %s: %w", f.ruleFilePath(), err)
 	}
@@ -256,24 +287,44 @@ func (f *FileStorage) saveChannelRules(orgID int64, rules ChannelRules) error {
 	if !ok {
 		return errors.New(reason)
 	}
-	ruleFile := f.ruleFilePath()
-	// Safe to ignore gosec warning G304.
-	// nolint:gosec
-	file, err := os.OpenFile(ruleFile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	return saveJSONFile(f.ruleFilePath(), rules)
+}
+
+// saveChannelRuleChange saves the rules with the changed rule at index, or
+// without the rule when deleted, and records the change in the rule history.
+func (f *FileStorage) saveChannelRuleChange(orgID int64, rules ChannelRules, index int, rule ChannelRule, deleted bool) (ChannelRule, error) {
+	history, err := f.readRuleHistory()
 	if err != nil {
-		return fmt.Errorf("can't open channel rule file: %w", err)
+		return rule, fmt.Errorf("can't read channel rule history: %w", err)
 	}
-	defer func() { _ = file.Close() }()
-	enc := json.NewEncoder(file)
-	enc.SetIndent("", "  ")
-	err = enc.Encode(rules)
-	if err != nil {
-		return fmt.Errorf("can't save rules to file: %w", err)
+	version := ChannelRuleVersion{
+		OrgId:    orgID,
+		Pattern:  rule.Pattern,
+		Version:  max(rule.Version, lastChannelRuleVersion(orgID, rule.Pattern, history)) + 1,
+		Created:  time.Now(),
+		Deleted:  deleted,
+		Settings: rule.Settings,
+	}
+	rule.Version = version.Version
+	if deleted {
+		rules.Rules = removeChannelRuleByIndex(rules.Rules, index)
+	} else {
+		rules.Rules[index] = rule
 	}
-	return nil
+	if err := f.saveChannelRules(orgID, rules); err != nil {
+		return rule, err
+	}
+	history.Versions = appendChannelRuleVersion(history.Versions, version)
+	if err := saveJSONFile(f.ruleHistoryFilePath(), history); err != nil {
+		return rule, fmt.Errorf("can't save channel rule history: %w", err)
+	}
+	return rule, nil
 }
 
 func (f *FileStorage) DeleteChannelRule(_ context.Context, orgID int64, cmd ChannelRuleDeleteCmd) error {
+	f.rulesMu.Lock()
+	defer f.rulesMu.Unlock()
+
 	channelRules, err := f.readRules()
 	if err != nil {
 		return fmt.Errorf("can't read channel rules: %w", err)
@@ -287,13 +338,120 @@ func (f *FileStorage) DeleteChannelRule(_ context.Context, orgID int64, cmd Chan
 		}
 	}
 
-	if index > -1 {
-		channelRules.Rules = removeChannelRuleByIndex(channelRules.Rules, index)
-	} else {
+	if index == -1 {
 		return fmt.Errorf("rule not found")
 	}
 
-	return f.saveChannelRules(orgID, channelRules)
+	_, err = f.saveChannelRuleChange(orgID, channelRules, index, channelRules.Rules[index], true)
+	return err
+}
+
+// ListChannelRuleVersions returns the kept versions of the rule, latest first.
+func (f *FileStorage) ListChannelRuleVersions(_ context.Context, orgID int64, cmd ChannelRuleVersionsCmd) ([]ChannelRuleVersion, error) {
+	history, err := f.readRuleHistory()
+	if err != nil {
+		return nil, fmt.Errorf("can't read channel rule history: %w", err)
+	}
+	var versions []ChannelRuleVersion
+	for i := len(history.Versions) - 1; i >= 0; i-- {
+		v := history.Versions[i]
+		if v.OrgId == orgID && v.Pattern == cmd.Pattern {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+// RollbackChannelRule restores the settings of a previous version of the rule,
+// as a new version. Deleted rules are created again.
+func (f *FileStorage) RollbackChannelRule(_ context.Context, orgID int64, cmd ChannelRuleRollbackCmd) (ChannelRule, error) {
+	f.rulesMu.Lock()
+	defer f.rulesMu.Unlock()
+
+	history, err := f.readRuleHistory()
+	if err != nil {
+		return ChannelRule{}, fmt.Errorf("can't read channel rule history: %w", err)
+	}
+	for _, v := range history.Versions {
+		if v.OrgId != orgID || v.Pattern != cmd.Pattern || v.Version != cmd.Version {
+			continue
+		}
+		if v.Deleted {
+			return ChannelRule{}, fmt.Errorf("version %d is a deletion of the rule", cmd.Version)
+		}
+		return f.updateChannelRule(orgID, ChannelRuleUpdateCmd{Pattern: v.Pattern, Settings: v.Settings})
+	}
+	return ChannelRule{}, ErrChannelRuleVersionNotFound
+}
+
+func (f *FileStorage) ruleHistoryFilePath() string {
+	return filepath.Join(f.DataPath, "pipeline", "live-channel-rules-history.json")
+}
+
+func (f *FileStorage) readRuleHistory() (ChannelRuleVersions, error) {
+	historyFile := f.ruleHistoryFilePath()
+	// Safe to ignore gosec warning G304.
+	// nolint:gosec
+	historyBytes, err := os.ReadFile(historyFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return ChannelRuleVersions{}, nil
+	}
+	if err != nil {
+		return ChannelRuleVersions{}, err
+	}
+	var history ChannelRuleVersions
+	err = json.Unmarshal(historyBytes, &history)
+	if err != nil {
+		return ChannelRuleVersions{}, fmt.Errorf("can't unmarshal live-channel-rules-history.json data: %w", err)
+	}
+	return history, nil
+}
+
+func lastChannelRuleVersion(orgID int64, pattern string, history ChannelRuleVersions) int64 {
+	var last int64
+	for _, v := range history.Versions {
+		if v.OrgId == orgID && v.Pattern == pattern && v.Version > last {
+			last = v.Version
+		}
+	}
+	return last
+}
+
+// appendChannelRuleVersion adds the version to the history, removing the oldest
+// versions of the rule above maxChannelRuleVersions.
+func appendChannelRuleVersion(versions []ChannelRuleVersion, version ChannelRuleVersion) []ChannelRuleVersion {
+	versions = append(versions, version)
+	count := 0
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].OrgId != version.OrgId || versions[i].Pattern != version.Pattern {
+			continue
+		}
+		count++
+		if count > maxChannelRuleVersions {
+			versions = append(versions[:i], versions[i+1:]...)
+		}
+	}
+	return versions
+}
+
+func saveJSONFile(filePath string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0750); err != nil {
+		return fmt.Errorf("can't create %s directory: %w", filepath.Dir(filePath), err)
+	}
+	// Safe to ignore gosec warning G304.
+	// nolint:gosec
+	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("can't open %s file: %w", filePath, err)
+	}
+	defer func() { _ = file.Close() }()
+	enc := json.NewEncoder(file)
+	enc.SetIndent("", "  ")
+	err = enc.Encode(v)
+	if err != nil {
+		return fmt.Errorf("can't save %s file: %w", filePath, err)
+	}
+	return nil
 }
 
 func removeWriteConfigByIndex(s []WriteConfig, index int) []WriteConfig {
@@ -309,6 +467,9 @@ func (f *FileStorage) readWriteConfigs() (WriteConfigs, error) {
 	// Safe to ignore gosec warning G304.
 	// nolint:gosec
 	bytes, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return WriteConfigs{}, nil
+	}
 	if err != nil {
 		return WriteConfigs{}, fmt.Errorf("can't read %s file: %w", filePath, err)
 	}
@@ -321,19 +482,5 @@ func (f *FileStorage) readWriteConfigs() (WriteConfigs, error) {
 }
 
 func (f *FileStorage) saveWriteConfigs(_ int64, writeConfigs WriteConfigs) error {
-	filePath := f.writeConfigsFilePath()
-	// Safe to ignore gosec warning G304.
-	// nolint:gosec
-	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
-	if err != nil {
-		return fmt.Errorf("can't open channel write configs file: %w", err)
-	}
-	defer func() { _ = file.Close() }()
-	enc := json.NewEncoder(file)
-	enc.SetIndent("", "  ")
-	err = enc.Encode(writeConfigs)
-	if err != nil {
-		return fmt.Errorf("can't save write configs to file: %w", err)
-	}
-	return nil
+	return saveJSONFile(f.writeConfigsFilePath(), writeConfigs)
 }
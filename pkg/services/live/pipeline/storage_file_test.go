@@ -0,0 +1,93 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testRuleSettings(channel string) ChannelRuleSettings {
+	return ChannelRuleSettings{
+		Converter: &ConverterConfig{Type: ConverterTypeJsonFrame},
+		FrameOutputters: []*FrameOutputterConfig{{
+			Type:                 FrameOutputTypeRedirect,
+			RedirectOutputConfig: &RedirectOutputConfig{Channel: channel},
+		}},
+	}
+}
+
+func TestFileStorage_ChannelRuleVersions(t *testing.T) {
+	ctx := context.Background()
+	s := &FileStorage{DataPath: t.TempDir()}
+
+	// Missing files have no rules.
+	rules, err := s.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, rules)
+
+	rule, err := s.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/test/cpu", Settings: testRuleSettings("stream/test/a")})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rule.Version)
+
+	rule, err = s.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/test/cpu", Settings: testRuleSettings("stream/test/b"), Version: 1})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), rule.Version)
+
+	// The update must be based on the current version.
+	_, err = s.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/test/cpu", Settings: testRuleSettings("stream/test/c"), Version: 1})
+	require.ErrorIs(t, err, ErrChannelRuleVersionConflict)
+
+	versions, err := s.ListChannelRuleVersions(ctx, 1, ChannelRuleVersionsCmd{Pattern: "stream/test/cpu"})
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, int64(2), versions[0].Version)
+	require.Equal(t, "stream/test/b", versions[0].Settings.FrameOutputters[0].RedirectOutputConfig.Channel)
+
+	rule, err = s.RollbackChannelRule(ctx, 1, ChannelRuleRollbackCmd{Pattern: "stream/test/cpu", Version: 1})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), rule.Version)
+	rules, err = s.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.Equal(t, "stream/test/a", rules[0].Settings.FrameOutputters[0].RedirectOutputConfig.Channel)
+	require.Equal(t, int64(3), rules[0].Version)
+
+	_, err = s.RollbackChannelRule(ctx, 1, ChannelRuleRollbackCmd{Pattern: "stream/test/cpu", Version: 10})
+	require.ErrorIs(t, err, ErrChannelRuleVersionNotFound)
+
+	// Deleted rules can be restored.
+	err = s.DeleteChannelRule(ctx, 1, ChannelRuleDeleteCmd{Pattern: "stream/test/cpu"})
+	require.NoError(t, err)
+	rules, err = s.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, rules)
+
+	versions, err = s.ListChannelRuleVersions(ctx, 1, ChannelRuleVersionsCmd{Pattern: "stream/test/cpu"})
+	require.NoError(t, err)
+	require.True(t, versions[0].Deleted)
+	_, err = s.RollbackChannelRule(ctx, 1, ChannelRuleRollbackCmd{Pattern: "stream/test/cpu", Version: versions[0].Version})
+	require.Error(t, err)
+
+	rule, err = s.RollbackChannelRule(ctx, 1, ChannelRuleRollbackCmd{Pattern: "stream/test/cpu", Version: 2})
+	require.NoError(t, err)
+	require.Equal(t, int64(5), rule.Version)
+
+	// Other orgs have their own versions.
+	versions, err = s.ListChannelRuleVersions(ctx, 2, ChannelRuleVersionsCmd{Pattern: "stream/test/cpu"})
+	require.NoError(t, err)
+	require.Empty(t, versions)
+}
+
+func TestFileStorage_ChannelRuleVersionsLimit(t *testing.T) {
+	ctx := context.Background()
+	s := &FileStorage{DataPath: t.TempDir()}
+	for i := 0; i < maxChannelRuleVersions+5; i++ {
+		_, err := s.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/test/cpu", Settings: testRuleSettings("stream/test/a")})
+		require.NoError(t, err)
+	}
+	versions, err := s.ListChannelRuleVersions(ctx, 1, ChannelRuleVersionsCmd{Pattern: "stream/test/cpu"})
+	require.NoError(t, err)
+	require.Len(t, versions, maxChannelRuleVersions)
+	require.Equal(t, int64(maxChannelRuleVersions+5), versions[0].Version)
+}
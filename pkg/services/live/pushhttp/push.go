@@ -1,6 +1,7 @@
 package pushhttp
 
 import (
+	"compress/gzip"
 	"context"
 	"errors"
 	"io"
@@ -98,13 +99,24 @@ func (g *Gateway) Handle(ctx *contextmodel.ReqContext) {
 	ctx.Resp.WriteHeader(http.StatusOK)
 }
 
+// The maximum size of a pipeline push body, once decompressed
+const pipelinePushMaxBodySize = 10 * 1024 * 1024
+
 func (g *Gateway) HandlePipelinePush(ctx *contextmodel.ReqContext) {
 	channelID := web.Params(ctx.Req)["*"]
 
-	body, err := io.ReadAll(ctx.Req.Body)
+	body, err := readPipelinePushBody(ctx.Resp, ctx.Req, pipelinePushMaxBodySize)
 	if err != nil {
 		logger.Error("Error reading body", "error", err)
-		ctx.Resp.WriteHeader(http.StatusInternalServerError)
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr), errors.Is(err, errPushBodyTooLarge):
+			ctx.Resp.WriteHeader(http.StatusRequestEntityTooLarge)
+		case errors.Is(err, gzip.ErrHeader), errors.Is(err, gzip.ErrChecksum), errors.Is(err, io.ErrUnexpectedEOF):
+			ctx.Resp.WriteHeader(http.StatusBadRequest)
+		default:
+			ctx.Resp.WriteHeader(http.StatusInternalServerError)
+		}
 		return
 	}
 	logger.Debug("Live channel push request",
@@ -131,3 +143,29 @@ func (g *Gateway) HandlePipelinePush(ctx *contextmodel.ReqContext) {
 
 	ctx.Resp.WriteHeader(http.StatusOK)
 }
+
+var errPushBodyTooLarge = errors.New("push body too large")
+
+// readPipelinePushBody reads the body, decompressed when gzipped.
+// Both the received and the decompressed body are limited, a small gzip body can expand to any size.
+func readPipelinePushBody(w http.ResponseWriter, r *http.Request, limit int64) ([]byte, error) {
+	var reader io.Reader = http.MaxBytesReader(w, r.Body, limit)
+	// OTLP exporters compress the metrics by default.
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = gz.Close() }()
+		reader = gz
+	}
+
+	body, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, errPushBodyTooLarge
+	}
+	return body, nil
+}
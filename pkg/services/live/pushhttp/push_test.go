@@ -0,0 +1,60 @@
+package pushhttp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadPipelinePushBody(t *testing.T) {
+	gzipped := func(t *testing.T, value []byte) []byte {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write(value)
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+		return buf.Bytes()
+	}
+	read := func(body []byte, encoding string) ([]byte, error) {
+		r := httptest.NewRequest(http.MethodPost, "/api/live/push/test", bytes.NewReader(body))
+		if encoding != "" {
+			r.Header.Set("Content-Encoding", encoding)
+		}
+		return readPipelinePushBody(httptest.NewRecorder(), r, 1024)
+	}
+
+	t.Run("plain", func(t *testing.T) {
+		body, err := read([]byte("metric 1"), "")
+		require.NoError(t, err)
+		require.Equal(t, "metric 1", string(body))
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		body, err := read(gzipped(t, []byte("metric 1")), "gzip")
+		require.NoError(t, err)
+		require.Equal(t, "metric 1", string(body))
+	})
+
+	t.Run("plain body too large", func(t *testing.T) {
+		_, err := read([]byte(strings.Repeat("x", 1025)), "")
+		var maxBytesErr *http.MaxBytesError
+		require.ErrorAs(t, err, &maxBytesErr)
+	})
+
+	t.Run("gzip body expanding past the limit", func(t *testing.T) {
+		compressed := gzipped(t, []byte(strings.Repeat("x", 100*1024)))
+		require.Less(t, len(compressed), 1024)
+		_, err := read(compressed, "gzip")
+		require.ErrorIs(t, err, errPushBodyTooLarge)
+	})
+
+	t.Run("invalid gzip", func(t *testing.T) {
+		_, err := read([]byte("this is not a gzip body"), "gzip")
+		require.ErrorIs(t, err, gzip.ErrHeader)
+	})
+}
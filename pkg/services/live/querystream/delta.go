@@ -0,0 +1,224 @@
+package querystream
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	// FrameActionReplace replaces the frame, the frame contains the schema and
+	// all the rows.
+	FrameActionReplace = "replace"
+	// FrameActionAppend appends the rows of the frame, the frame contains the
+	// new rows only. The first Dropped rows of the previous frame are removed
+	// before, they are out of the time range.
+	FrameActionAppend = "append"
+	// FrameActionRemove removes the frame, the query does not return it anymore.
+	FrameActionRemove = "remove"
+)
+
+// Message is published to the query channel when the results change.
+type Message struct {
+	Frames []FrameDelta `json:"frames,omitempty"`
+	// Errors of the queries by refId.
+	Errors map[string]string `json:"errors,omitempty"`
+	// Error of the whole request, the last results are kept.
+	Error string `json:"error,omitempty"`
+}
+
+// FrameDelta is the change of a frame returned by a query.
+type FrameDelta struct {
+	RefID string `json:"refId"`
+	// Index of the frame in the frames returned for the refId.
+	Index  int             `json:"index"`
+	Action string          `json:"action"`
+	Frame  json.RawMessage `json:"frame,omitempty"`
+	// Dropped is the number of leading rows of the previous frame removed by
+	// an append.
+	Dropped int `json:"dropped,omitempty"`
+}
+
+// frameState is a frame last sent to the subscribers.
+type frameState struct {
+	frame     *data.Frame
+	frameJSON data.FrameJSONCache
+}
+
+// results are the frames last sent to the subscribers by refId.
+type results map[string][]frameState
+
+func newResults(resp *backend.QueryDataResponse) (results, map[string]string, error) {
+	r := results{}
+	var errs map[string]string
+	for refID, dr := range resp.Responses {
+		if dr.Error != nil {
+			if errs == nil {
+				errs = map[string]string{}
+			}
+			errs[refID] = dr.Error.Error()
+		}
+		states := make([]frameState, 0, len(dr.Frames))
+		for _, frame := range dr.Frames {
+			state, err := newFrameState(frame)
+			if err != nil {
+				return nil, nil, err
+			}
+			states = append(states, state)
+		}
+		r[refID] = states
+	}
+	return r, errs, nil
+}
+
+func newFrameState(frame *data.Frame) (frameState, error) {
+	frameJSON, err := data.FrameToJSONCache(frame)
+	if err != nil {
+		return frameState{}, err
+	}
+	return frameState{frame: frame, frameJSON: frameJSON}, nil
+}
+
+// full returns the deltas replacing all the frames, it is sent to new
+// subscribers.
+func (r results) full() []FrameDelta {
+	var deltas []FrameDelta
+	for _, refID := range r.refIDs() {
+		for i, state := range r[refID] {
+			deltas = append(deltas, state.replace(refID, i))
+		}
+	}
+	return deltas
+}
+
+// diff returns the deltas to apply to the previous results to get r.
+func (r results) diff(prev results) ([]FrameDelta, error) {
+	refIDs := r.refIDs()
+	for _, refID := range prev.refIDs() {
+		if _, ok := r[refID]; !ok {
+			refIDs = append(refIDs, refID)
+		}
+	}
+	var deltas []FrameDelta
+	for _, refID := range refIDs {
+		current, previous := r[refID], prev[refID]
+		for i, state := range current {
+			if i >= len(previous) {
+				deltas = append(deltas, state.replace(refID, i))
+				continue
+			}
+			delta, ok, err := state.diff(previous[i])
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				delta.RefID = refID
+				delta.Index = i
+				deltas = append(deltas, delta)
+			}
+		}
+		for i := len(current); i < len(previous); i++ {
+			deltas = append(deltas, FrameDelta{RefID: refID, Index: i, Action: FrameActionRemove})
+		}
+	}
+	return deltas, nil
+}
+
+func (r results) refIDs() []string {
+	refIDs := make([]string, 0, len(r))
+	for refID := range r {
+		refIDs = append(refIDs, refID)
+	}
+	sort.Strings(refIDs)
+	return refIDs
+}
+
+func (s frameState) replace(refID string, index int) FrameDelta {
+	return FrameDelta{
+		RefID:  refID,
+		Index:  index,
+		Action: FrameActionReplace,
+		Frame:  s.frameJSON.Bytes(data.IncludeAll),
+	}
+}
+
+// diff returns the change from the previous frame, false when the frame did
+// not change. Frames with the same schema whose rows are the rows of the
+// previous frame followed by new rows, as time series queried over a relative
+// time range, only send the new rows.
+func (s frameState) diff(prev frameState) (FrameDelta, bool, error) {
+	replace := FrameDelta{Action: FrameActionReplace, Frame: s.frameJSON.Bytes(data.IncludeAll)}
+	if !s.frameJSON.SameSchema(&prev.frameJSON) {
+		return replace, true, nil
+	}
+	if bytes.Equal(s.frameJSON.Bytes(data.IncludeDataOnly), prev.frameJSON.Bytes(data.IncludeDataOnly)) {
+		return FrameDelta{}, false, nil
+	}
+	start, dropped, ok := appendedRows(prev.frame, s.frame)
+	if !ok {
+		return replace, true, nil
+	}
+	appended := s.frame.EmptyCopy()
+	for i := start; i < s.frame.Rows(); i++ {
+		appended.AppendRow(s.frame.RowCopy(i)...)
+	}
+	frameJSON, err := data.FrameToJSON(appended, data.IncludeDataOnly)
+	if err != nil {
+		return FrameDelta{}, false, err
+	}
+	return FrameDelta{Action: FrameActionAppend, Frame: frameJSON, Dropped: dropped}, true, nil
+}
+
+// appendedRows returns the index of the first new row of the frame when the
+// rows before it end the previous frame, and the number of leading rows of the
+// previous frame that are gone, the time range moved forward.
+func appendedRows(prev, frame *data.Frame) (int, int, bool) {
+	if len(frame.Fields) == 0 || len(frame.Fields) != len(prev.Fields) || prev.Rows() == 0 {
+		return 0, 0, false
+	}
+	timeIndex := -1
+	for i, field := range frame.Fields {
+		if field.Type().Time() {
+			timeIndex = i
+			break
+		}
+	}
+	if timeIndex < 0 {
+		return 0, 0, false
+	}
+	lastTime, ok := prev.Fields[timeIndex].ConcreteAt(prev.Rows() - 1)
+	if !ok {
+		return 0, 0, false
+	}
+	last := lastTime.(time.Time)
+
+	// Rows up to the last previous time must be the tail of the previous frame.
+	start := 0
+	for start < frame.Rows() {
+		t, ok := frame.Fields[timeIndex].ConcreteAt(start)
+		if !ok {
+			return 0, 0, false
+		}
+		if t.(time.Time).After(last) {
+			break
+		}
+		start++
+	}
+	if start == frame.Rows() || start > prev.Rows() {
+		return 0, 0, false
+	}
+	offset := prev.Rows() - start
+	for i := 0; i < start; i++ {
+		for j := range frame.Fields {
+			if !reflect.DeepEqual(frame.Fields[j].At(i), prev.Fields[j].At(offset+i)) {
+				return 0, 0, false
+			}
+		}
+	}
+	return start, offset, true
+}
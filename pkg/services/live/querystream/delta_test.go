@@ -0,0 +1,96 @@
+package querystream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func testSeries(from int, values ...float64) *data.Frame {
+	times := make([]time.Time, 0, len(values))
+	for i := range values {
+		times = append(times, time.Unix(int64(from+i), 0))
+	}
+	return data.NewFrame("cpu",
+		data.NewField("time", nil, times),
+		data.NewField("value", nil, values),
+	)
+}
+
+func testResults(t *testing.T, frames map[string]data.Frames) results {
+	t.Helper()
+	resp := backend.NewQueryDataResponse()
+	for refID, f := range frames {
+		resp.Responses[refID] = backend.DataResponse{Frames: f}
+	}
+	r, _, err := newResults(resp)
+	require.NoError(t, err)
+	return r
+}
+
+func TestResultsDiff(t *testing.T) {
+	prev := testResults(t, map[string]data.Frames{
+		"A": {testSeries(1, 1, 2, 3)},
+		"B": {testSeries(1, 1), testSeries(1, 2)},
+		"C": {data.NewFrame("table", data.NewField("name", nil, []string{"a"}))},
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		deltas, err := prev.diff(prev)
+		require.NoError(t, err)
+		require.Empty(t, deltas)
+	})
+
+	t.Run("changes", func(t *testing.T) {
+		current := testResults(t, map[string]data.Frames{
+			// The time range moved, one row is gone and two are new.
+			"A": {testSeries(2, 2, 3, 4, 5)},
+			"B": {testSeries(1, 1)},
+			"C": {data.NewFrame("table", data.NewField("name", nil, []string{"b"}))},
+			"D": {testSeries(1, 1)},
+		})
+		deltas, err := current.diff(prev)
+		require.NoError(t, err)
+		require.Len(t, deltas, 4)
+
+		require.Equal(t, "A", deltas[0].RefID)
+		require.Equal(t, FrameActionAppend, deltas[0].Action)
+		require.JSONEq(t, `{"data":{"values":[[4000,5000],[4,5]]}}`, string(deltas[0].Frame))
+		// The first row of the previous frame is out of the time range.
+		require.Equal(t, 1, deltas[0].Dropped)
+
+		require.Equal(t, FrameDelta{RefID: "B", Index: 1, Action: FrameActionRemove}, deltas[1])
+
+		require.Equal(t, "C", deltas[2].RefID)
+		require.Equal(t, FrameActionReplace, deltas[2].Action)
+		require.Contains(t, string(deltas[2].Frame), `"schema"`)
+
+		require.Equal(t, "D", deltas[3].RefID)
+		require.Equal(t, FrameActionReplace, deltas[3].Action)
+	})
+
+	t.Run("changed rows are replaced", func(t *testing.T) {
+		current := testResults(t, map[string]data.Frames{
+			"A": {testSeries(1, 1, 20, 3, 4)},
+			"B": {testSeries(1, 1), testSeries(1, 2)},
+			"C": {data.NewFrame("table", data.NewField("name", nil, []string{"a"}))},
+		})
+		deltas, err := current.diff(prev)
+		require.NoError(t, err)
+		require.Len(t, deltas, 1)
+		require.Equal(t, FrameActionReplace, deltas[0].Action)
+	})
+
+	t.Run("removed refId", func(t *testing.T) {
+		current := testResults(t, map[string]data.Frames{
+			"A": {testSeries(1, 1, 2, 3)},
+			"B": {testSeries(1, 1), testSeries(1, 2)},
+		})
+		deltas, err := current.diff(prev)
+		require.NoError(t, err)
+		require.Equal(t, []FrameDelta{{RefID: "C", Index: 0, Action: FrameActionRemove}}, deltas)
+	})
+}
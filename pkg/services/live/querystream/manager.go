@@ -0,0 +1,420 @@
+package querystream
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/live"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/model"
+	"github.com/grafana/grafana/pkg/services/live/orgchannel"
+)
+
+var (
+	logger = log.New("live.querystream")
+)
+
+// Namespace of the query channels in the grafana scope.
+const Namespace = "query"
+
+const (
+	// MinRefreshInterval is the shortest interval queries are run at.
+	MinRefreshInterval     = time.Second
+	defaultRefreshInterval = 5 * time.Second
+	// Running queries stop after maxChecks runs without subscribers.
+	defaultMaxChecks = 3
+	// Queries are forgotten when they have not been registered or subscribed
+	// to for registrationTTL.
+	registrationTTL = 10 * time.Minute
+)
+
+var (
+	ErrNoQueries       = errors.New("request has no queries")
+	ErrRefreshInterval = fmt.Errorf("refresh interval must be at least %s", MinRefreshInterval)
+)
+
+type ChannelLocalPublisher interface {
+	PublishLocal(channel string, data []byte) error
+}
+
+type NumLocalSubscribersGetter interface {
+	GetNumLocalSubscribers(channel string) (int, error)
+}
+
+type QueryDataService interface {
+	QueryData(ctx context.Context, user identity.Requester, skipDSCache bool, reqDTO dtos.MetricRequest) (*backend.QueryDataResponse, error)
+}
+
+// Request is a query to run for the subscribers of a channel.
+type Request struct {
+	dtos.MetricRequest
+	// RefreshIntervalMs is the interval the query is run at, 5s by default.
+	RefreshIntervalMs int64 `json:"refreshIntervalMs"`
+}
+
+// Reply is the channel to subscribe to for the results of the query.
+type Reply struct {
+	Channel string `json:"channel"`
+}
+
+// Manager runs queries on an interval for the subscribers of query channels,
+// and publishes the changes of the results. Channels are derived from the
+// query, so viewers of the same query share a channel and the query only runs
+// once for all of them. Each Grafana instance runs the queries of its own
+// subscribers.
+type Manager struct {
+	mu           sync.Mutex
+	baseCtx      context.Context
+	cancel       context.CancelFunc
+	queries      map[string]*query
+	publisher    ChannelLocalPublisher
+	subscribers  NumLocalSubscribersGetter
+	queryService QueryDataService
+	maxChecks    int
+}
+
+// NewManager creates new Manager.
+func NewManager(publisher ChannelLocalPublisher, subscribers NumLocalSubscribersGetter, queryService QueryDataService) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		baseCtx:      ctx,
+		cancel:       cancel,
+		queries:      make(map[string]*query),
+		publisher:    publisher,
+		subscribers:  subscribers,
+		queryService: queryService,
+		maxChecks:    defaultMaxChecks,
+	}
+}
+
+type query struct {
+	orgID    int64
+	channel  string
+	request  dtos.MetricRequest
+	interval time.Duration
+	// users registered the query by uid, with the time of their last
+	// registration. Registrations expire after registrationTTL.
+	users map[string]time.Time
+	// subscribers of the channel by uid. Each of them ran the query when
+	// subscribing, so the query runs as one of them.
+	subscribers map[string]*subscriber
+	lastUsed    time.Time
+	results     results
+	errors      map[string]string
+	// err of the last run of the request.
+	err string
+	// ctx of the running query, nil when it is not running.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+type subscriber struct {
+	user          identity.Requester
+	subscriptions int
+	subscribedAt  time.Time
+}
+
+// runAs returns the user the query runs as, the last subscriber. Must be called
+// with the lock held.
+func (q *query) runAs() identity.Requester {
+	var last *subscriber
+	for _, s := range q.subscribers {
+		if last == nil || s.subscribedAt.After(last.subscribedAt) {
+			last = s
+		}
+	}
+	if last == nil {
+		return nil
+	}
+	return last.user
+}
+
+// Run Manager till context canceled.
+func (m *Manager) Run(ctx context.Context) error {
+	<-ctx.Done()
+	m.cancel()
+	return ctx.Err()
+}
+
+// Register returns the channel of the query. The query runs with the user
+// first, so that it is only subscribed to by users allowed to run it.
+func (m *Manager) Register(ctx context.Context, user identity.Requester, req Request) (Reply, error) {
+	if len(req.Queries) == 0 {
+		return Reply{}, ErrNoQueries
+	}
+	interval := time.Duration(req.RefreshIntervalMs) * time.Millisecond
+	if interval == 0 {
+		interval = defaultRefreshInterval
+	}
+	if interval < MinRefreshInterval {
+		return Reply{}, ErrRefreshInterval
+	}
+	channel, err := queryChannel(user.GetOrgID(), req.MetricRequest, interval)
+	if err != nil {
+		return Reply{}, err
+	}
+	key := orgchannel.PrependOrgID(user.GetOrgID(), channel)
+
+	m.mu.Lock()
+	m.removeExpired()
+	q, ok := m.queries[key]
+	if ok {
+		if _, registered := q.users[user.GetUID()]; registered {
+			q.users[user.GetUID()] = time.Now()
+			q.lastUsed = time.Now()
+			m.mu.Unlock()
+			return Reply{Channel: channel}, nil
+		}
+	}
+	m.mu.Unlock()
+
+	resp, err := m.queryService.QueryData(ctx, user, false, req.MetricRequest)
+	if err != nil {
+		return Reply{}, err
+	}
+	res, errs, err := newResults(resp)
+	if err != nil {
+		return Reply{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q, ok = m.queries[key]
+	if !ok {
+		q = &query{
+			orgID:       user.GetOrgID(),
+			channel:     channel,
+			request:     req.MetricRequest,
+			interval:    interval,
+			users:       map[string]time.Time{},
+			subscribers: map[string]*subscriber{},
+			results:     res,
+			errors:      errs,
+		}
+		m.queries[key] = q
+	}
+	q.users[user.GetUID()] = time.Now()
+	q.lastUsed = time.Now()
+	return Reply{Channel: channel}, nil
+}
+
+// queryChannel returns the channel of the query, the same for the same query
+// and interval in the organization.
+func queryChannel(orgID int64, req dtos.MetricRequest, interval time.Duration) (string, error) {
+	// Map keys are sorted, equal queries are encoded the same way.
+	key, err := json.Marshal(struct {
+		OrgID    int64              `json:"orgId"`
+		Request  dtos.MetricRequest `json:"request"`
+		Interval time.Duration      `json:"interval"`
+	}{orgID, req, interval})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(key)
+	return live.Channel{
+		Scope:     live.ScopeGrafana,
+		Namespace: Namespace,
+		Path:      hex.EncodeToString(sum[:16]),
+	}.String(), nil
+}
+
+// removeExpired removes the registrations and the queries not used for
+// registrationTTL. Must be called with the lock held.
+func (m *Manager) removeExpired() {
+	for key, q := range m.queries {
+		for uid, registeredAt := range q.users {
+			if time.Since(registeredAt) > registrationTTL {
+				delete(q.users, uid)
+			}
+		}
+		if q.ctx == nil && time.Since(q.lastUsed) > registrationTTL {
+			delete(m.queries, key)
+		}
+	}
+}
+
+// GetHandlerForPath called on init
+func (m *Manager) GetHandlerForPath(_ string) (model.ChannelHandler, error) {
+	return m, nil // all queries share the same handler
+}
+
+// OnSubscribe starts the query if it is not running and replies with its last
+// results. Users must register the query first, and are allowed to subscribe
+// when they can still run it.
+func (m *Manager) OnSubscribe(ctx context.Context, u identity.Requester, e model.SubscribeEvent) (model.SubscribeReply, backend.SubscribeStreamStatus, error) {
+	key := orgchannel.PrependOrgID(u.GetOrgID(), e.Channel)
+	m.mu.Lock()
+	q, ok := m.queries[key]
+	if !ok {
+		m.mu.Unlock()
+		return model.SubscribeReply{}, backend.SubscribeStreamStatusNotFound, nil
+	}
+	if _, registered := q.users[u.GetUID()]; !registered {
+		m.mu.Unlock()
+		return model.SubscribeReply{}, backend.SubscribeStreamStatusPermissionDenied, nil
+	}
+	request := q.request
+	m.mu.Unlock()
+
+	// The permissions of the user may have changed since the registration.
+	if _, err := m.queryService.QueryData(ctx, u, false, request); err != nil {
+		logger.Debug("User can not run the query anymore", "channel", e.Channel, "user", u.GetUID(), "error", err)
+		return model.SubscribeReply{}, backend.SubscribeStreamStatusPermissionDenied, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q, ok = m.queries[key]
+	if !ok {
+		return model.SubscribeReply{}, backend.SubscribeStreamStatusNotFound, nil
+	}
+	sub, ok := q.subscribers[u.GetUID()]
+	if !ok {
+		sub = &subscriber{}
+		q.subscribers[u.GetUID()] = sub
+	}
+	sub.user = u
+	sub.subscriptions++
+	sub.subscribedAt = time.Now()
+	q.lastUsed = time.Now()
+	if q.ctx == nil {
+		q.ctx, q.cancel = context.WithCancel(m.baseCtx)
+		go m.runQuery(q.ctx, q)
+	}
+	data, err := json.Marshal(Message{Frames: q.results.full(), Errors: q.errors})
+	if err != nil {
+		return model.SubscribeReply{}, 0, err
+	}
+	return model.SubscribeReply{Data: data}, backend.SubscribeStreamStatusOK, nil
+}
+
+// OnUnsubscribe forgets the subscriber once all its subscriptions are gone, the
+// query does not run as it anymore.
+func (m *Manager) OnUnsubscribe(_ context.Context, u identity.Requester, e model.UnsubscribeEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q, ok := m.queries[orgchannel.PrependOrgID(u.GetOrgID(), e.Channel)]
+	if !ok {
+		return
+	}
+	sub, ok := q.subscribers[u.GetUID()]
+	if !ok {
+		return
+	}
+	sub.subscriptions--
+	if sub.subscriptions <= 0 {
+		delete(q.subscribers, u.GetUID())
+	}
+}
+
+// OnPublish is called when a client wants to broadcast on the websocket
+func (m *Manager) OnPublish(_ context.Context, _ identity.Requester, _ model.PublishEvent) (model.PublishReply, backend.PublishStreamStatus, error) {
+	return model.PublishReply{}, backend.PublishStreamStatusPermissionDenied, nil
+}
+
+// runQuery runs the query on its interval while the channel has subscribers.
+func (m *Manager) runQuery(ctx context.Context, q *query) {
+	channel := orgchannel.PrependOrgID(q.orgID, q.channel)
+	ticker := time.NewTicker(q.interval)
+	defer ticker.Stop()
+	numNoSubscribersChecks := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		numSubscribers, err := m.subscribers.GetNumLocalSubscribers(channel)
+		if err != nil {
+			logger.Error("Error checking num subscribers", "channel", channel, "error", err)
+			continue
+		}
+		if numSubscribers == 0 {
+			numNoSubscribersChecks++
+			if numNoSubscribersChecks >= m.maxChecks {
+				logger.Debug("Stop query since no active subscribers", "channel", channel)
+				m.stopQuery(ctx, q)
+				return
+			}
+			continue
+		}
+		numNoSubscribersChecks = 0
+		if err := m.refresh(ctx, q, channel); err != nil {
+			logger.Error("Error publishing query results", "channel", channel, "error", err)
+		}
+	}
+}
+
+func (m *Manager) stopQuery(ctx context.Context, q *query) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if ctx.Err() != nil {
+		return
+	}
+	q.cancel()
+	q.ctx, q.cancel = nil, nil
+	q.lastUsed = time.Now()
+	// Subscriptions whose unsubscribe was missed are gone too.
+	q.subscribers = map[string]*subscriber{}
+}
+
+// refresh runs the query as a subscriber and publishes the changes from the
+// last results.
+func (m *Manager) refresh(ctx context.Context, q *query, channel string) error {
+	m.mu.Lock()
+	user := q.runAs()
+	m.mu.Unlock()
+	if user == nil {
+		return nil
+	}
+	var msg Message
+	resp, err := m.queryService.QueryData(identity.WithRequester(ctx, user), user, false, q.request)
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		logger.Warn("Error running query", "channel", channel, "error", err)
+		msg.Error = err.Error()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		if msg.Error == q.err {
+			return nil
+		}
+		q.err = msg.Error
+	} else {
+		res, errs, err := newResults(resp)
+		if err != nil {
+			return err
+		}
+		deltas, err := res.diff(q.results)
+		if err != nil {
+			return err
+		}
+		if len(deltas) == 0 && reflect.DeepEqual(errs, q.errors) && q.err == "" {
+			return nil
+		}
+		q.results, q.errors, q.err = res, errs, ""
+		msg.Frames, msg.Errors = deltas, errs
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	// Publishing with the lock held, subscribers get the results in the
+	// subscribe reply and then only the changes.
+	return m.publisher.PublishLocal(channel, data)
+}
@@ -0,0 +1,289 @@
+package querystream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	claims "github.com/grafana/authlib/types"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/live/model"
+	"github.com/grafana/grafana/pkg/services/live/orgchannel"
+)
+
+type testQueryService struct {
+	mu      sync.Mutex
+	calls   int
+	denied  map[string]bool
+	results []data.Frames
+	// users the queries ran as.
+	users []string
+}
+
+func (s *testQueryService) QueryData(_ context.Context, user identity.Requester, _ bool, _ dtos.MetricRequest) (*backend.QueryDataResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.denied[user.GetUID()] {
+		return nil, errors.New("access denied")
+	}
+	frames := s.results[min(s.calls, len(s.results)-1)]
+	s.calls++
+	s.users = append(s.users, user.GetUID())
+	resp := backend.NewQueryDataResponse()
+	resp.Responses["A"] = backend.DataResponse{Frames: frames}
+	return resp, nil
+}
+
+func (s *testQueryService) numCalls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func (s *testQueryService) lastUser() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.users[len(s.users)-1]
+}
+
+func (s *testQueryService) deny(uid string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.denied[uid] = true
+}
+
+type testPublisher struct {
+	mu          sync.Mutex
+	subscribers int
+	messages    []Message
+}
+
+func (p *testPublisher) PublishLocal(_ string, data []byte) error {
+	var msg Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+func (p *testPublisher) GetNumLocalSubscribers(_ string) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.subscribers, nil
+}
+
+func (p *testPublisher) setSubscribers(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.subscribers = n
+}
+
+func (p *testPublisher) published() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Message(nil), p.messages...)
+}
+
+func testUser(uid string) *identity.StaticRequester {
+	return &identity.StaticRequester{Type: claims.TypeUser, UserUID: uid, OrgID: 1}
+}
+
+func testRequest() Request {
+	return Request{
+		MetricRequest: dtos.MetricRequest{
+			From: "now-1h",
+			To:   "now",
+			Queries: []*simplejson.Json{simplejson.NewFromAny(map[string]any{
+				"refId":      "A",
+				"datasource": map[string]any{"uid": "sql"},
+				"rawSql":     "SELECT 1",
+			})},
+		},
+		RefreshIntervalMs: 1000,
+	}
+}
+
+func TestManager_Register(t *testing.T) {
+	queryService := &testQueryService{
+		denied:  map[string]bool{testUser("denied").GetUID(): true},
+		results: []data.Frames{{testSeries(1, 1)}},
+	}
+	publisher := &testPublisher{}
+	m := NewManager(publisher, publisher, queryService)
+	ctx := context.Background()
+
+	reply, err := m.Register(ctx, testUser("a"), testRequest())
+	require.NoError(t, err)
+	require.Contains(t, reply.Channel, "grafana/query/")
+	require.Equal(t, 1, queryService.numCalls())
+
+	// Users registering the same query again get the same channel without
+	// running the query.
+	again, err := m.Register(ctx, testUser("a"), testRequest())
+	require.NoError(t, err)
+	require.Equal(t, reply.Channel, again.Channel)
+	require.Equal(t, 1, queryService.numCalls())
+
+	other, err := m.Register(ctx, testUser("b"), testRequest())
+	require.NoError(t, err)
+	require.Equal(t, reply.Channel, other.Channel)
+	require.Equal(t, 2, queryService.numCalls())
+
+	_, err = m.Register(ctx, testUser("denied"), testRequest())
+	require.Error(t, err)
+
+	// Other intervals and orgs have other channels.
+	req := testRequest()
+	req.RefreshIntervalMs = 2000
+	reply2, err := m.Register(ctx, testUser("a"), req)
+	require.NoError(t, err)
+	require.NotEqual(t, reply.Channel, reply2.Channel)
+	otherOrg := testUser("a")
+	otherOrg.OrgID = 2
+	reply3, err := m.Register(ctx, otherOrg, testRequest())
+	require.NoError(t, err)
+	require.NotEqual(t, reply.Channel, reply3.Channel)
+
+	req.RefreshIntervalMs = 10
+	_, err = m.Register(ctx, testUser("a"), req)
+	require.ErrorIs(t, err, ErrRefreshInterval)
+	_, err = m.Register(ctx, testUser("a"), Request{})
+	require.ErrorIs(t, err, ErrNoQueries)
+}
+
+func TestManager_Subscribe(t *testing.T) {
+	queryService := &testQueryService{
+		results: []data.Frames{
+			{testSeries(1, 1, 2)},
+			{testSeries(1, 1, 2, 3)},
+		},
+	}
+	publisher := &testPublisher{}
+	m := NewManager(publisher, publisher, queryService)
+	m.maxChecks = 1
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = m.Run(ctx) }()
+
+	reply, err := m.Register(ctx, testUser("a"), testRequest())
+	require.NoError(t, err)
+
+	// Users must register the query to subscribe.
+	_, status, err := m.OnSubscribe(ctx, testUser("b"), model.SubscribeEvent{Channel: reply.Channel})
+	require.NoError(t, err)
+	require.Equal(t, backend.SubscribeStreamStatusPermissionDenied, status)
+	_, status, err = m.OnSubscribe(ctx, testUser("a"), model.SubscribeEvent{Channel: "grafana/query/unknown"})
+	require.NoError(t, err)
+	require.Equal(t, backend.SubscribeStreamStatusNotFound, status)
+
+	publisher.setSubscribers(1)
+	subReply, status, err := m.OnSubscribe(ctx, testUser("a"), model.SubscribeEvent{Channel: reply.Channel})
+	require.NoError(t, err)
+	require.Equal(t, backend.SubscribeStreamStatusOK, status)
+	var initial Message
+	require.NoError(t, json.Unmarshal(subReply.Data, &initial))
+	require.Len(t, initial.Frames, 1)
+	require.Equal(t, FrameActionReplace, initial.Frames[0].Action)
+
+	// Only the new row is published, unchanged results are not. The subscribe
+	// ran the query to check the user can still run it.
+	require.Eventually(t, func() bool {
+		return queryService.numCalls() >= 4
+	}, 5*time.Second, 10*time.Millisecond)
+	messages := publisher.published()
+	require.Len(t, messages, 1)
+	require.Equal(t, FrameActionAppend, messages[0].Frames[0].Action)
+	require.JSONEq(t, `{"data":{"values":[[3000],[3]]}}`, string(messages[0].Frames[0].Frame))
+
+	// The query stops without subscribers.
+	publisher.setSubscribers(0)
+	require.Eventually(t, func() bool {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for _, q := range m.queries {
+			if q.ctx != nil {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestManager_Subscribers(t *testing.T) {
+	queryService := &testQueryService{
+		denied:  map[string]bool{},
+		results: []data.Frames{{testSeries(1, 1)}},
+	}
+	publisher := &testPublisher{}
+	m := NewManager(publisher, publisher, queryService)
+	ctx := context.Background()
+
+	reply, err := m.Register(ctx, testUser("a"), testRequest())
+	require.NoError(t, err)
+	_, err = m.Register(ctx, testUser("b"), testRequest())
+	require.NoError(t, err)
+	_, err = m.Register(ctx, testUser("c"), testRequest())
+	require.NoError(t, err)
+	event := model.SubscribeEvent{Channel: reply.Channel}
+	q := m.queries[orgchannel.PrependOrgID(1, reply.Channel)]
+	defer func() {
+		if q.cancel != nil {
+			q.cancel()
+		}
+	}()
+
+	runAs := func() string {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if user := q.runAs(); user != nil {
+			return user.GetUID()
+		}
+		return ""
+	}
+
+	// Users who can not run the query anymore are not allowed to subscribe.
+	queryService.deny(testUser("c").GetUID())
+	_, status, err := m.OnSubscribe(ctx, testUser("c"), event)
+	require.NoError(t, err)
+	require.Equal(t, backend.SubscribeStreamStatusPermissionDenied, status)
+
+	// The query runs as the last subscriber still subscribed.
+	_, status, err = m.OnSubscribe(ctx, testUser("a"), event)
+	require.NoError(t, err)
+	require.Equal(t, backend.SubscribeStreamStatusOK, status)
+	time.Sleep(time.Millisecond)
+	_, status, err = m.OnSubscribe(ctx, testUser("b"), event)
+	require.NoError(t, err)
+	require.Equal(t, backend.SubscribeStreamStatusOK, status)
+	require.Equal(t, testUser("b").GetUID(), runAs())
+
+	m.OnUnsubscribe(ctx, testUser("b"), model.UnsubscribeEvent{Channel: reply.Channel})
+	require.Equal(t, testUser("a").GetUID(), runAs())
+	publisher.setSubscribers(1)
+	require.NoError(t, m.refresh(ctx, q, orgchannel.PrependOrgID(1, reply.Channel)))
+	require.Equal(t, testUser("a").GetUID(), queryService.lastUser())
+
+	m.OnUnsubscribe(ctx, testUser("a"), model.UnsubscribeEvent{Channel: reply.Channel})
+	require.Empty(t, runAs())
+
+	// Expired registrations are removed.
+	m.mu.Lock()
+	q.users[testUser("a").GetUID()] = time.Now().Add(-2 * registrationTTL)
+	m.removeExpired()
+	m.mu.Unlock()
+	_, status, err = m.OnSubscribe(ctx, testUser("a"), event)
+	require.NoError(t, err)
+	require.Equal(t, backend.SubscribeStreamStatusPermissionDenied, status)
+}
@@ -394,11 +394,12 @@ func readpanelInfo(iter *jsoniter.Iterator, lookup DatasourceLookup) PanelSummar
 	panel := PanelSummaryInfo{}
 
 	targets := newTargetInfo(lookup)
+	content := ""
 
 	for l1Field := iter.ReadObject(); l1Field != ""; l1Field = iter.ReadObject() {
 		if iter.WhatIsNext() == jsoniter.NilValue {
 			if l1Field == "datasource" {
-				targets.addDatasource(iter)
+				targets.addPanelDatasource(iter)
 				continue
 			}
 
@@ -433,7 +434,7 @@ func readpanelInfo(iter *jsoniter.Iterator, lookup DatasourceLookup) PanelSummar
 			}
 
 		case "datasource":
-			targets.addDatasource(iter)
+			targets.addPanelDatasource(iter)
 
 		case "targets":
 			switch iter.WhatIsNext() {
@@ -467,7 +468,25 @@ func readpanelInfo(iter *jsoniter.Iterator, lookup DatasourceLookup) PanelSummar
 			}
 
 		case "options":
-			fallthrough
+			if iter.WhatIsNext() != jsoniter.ObjectValue {
+				iter.Skip()
+				continue
+			}
+			for sub := iter.ReadObject(); sub != ""; sub = iter.ReadObject() {
+				if sub == "content" && iter.WhatIsNext() == jsoniter.StringValue {
+					content = iter.ReadString()
+				} else {
+					iter.Skip()
+				}
+			}
+
+		// Text panels before 7.0 saved the content on the panel
+		case "content":
+			if iter.WhatIsNext() == jsoniter.StringValue {
+				content = iter.ReadString()
+			} else {
+				iter.Skip()
+			}
 
 		case "gridPos":
 			fallthrough
@@ -482,6 +501,10 @@ func readpanelInfo(iter *jsoniter.Iterator, lookup DatasourceLookup) PanelSummar
 	}
 
 	panel.Datasource = targets.GetDatasourceInfo()
+	panel.Queries, panel.MetricNames = targets.GetQueryInfo()
+	if panel.Type == "text" {
+		panel.Text = content
+	}
 
 	return panel
 }
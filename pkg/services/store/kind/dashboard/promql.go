@@ -0,0 +1,210 @@
+package dashboard
+
+import (
+	"sort"
+	"strings"
+)
+
+// Aggregation operators may have the grouping clause before the arguments: `sum by (job) (x)`
+var promqlAggregations = map[string]bool{
+	"sum": true, "min": true, "max": true, "avg": true, "group": true,
+	"stddev": true, "stdvar": true, "count": true, "count_values": true,
+	"bottomk": true, "topk": true, "quantile": true, "limitk": true, "limit_ratio": true,
+}
+
+// Keywords followed by a list of label names
+var promqlGrouping = map[string]bool{
+	"by": true, "without": true, "on": true, "ignoring": true, "group_left": true, "group_right": true,
+}
+
+var promqlKeywords = map[string]bool{
+	"and": true, "or": true, "unless": true, "bool": true, "offset": true,
+	"start": true, "end": true, "inf": true, "nan": true,
+}
+
+// promqlMetricNames returns the metric names used in a PromQL expression.
+// This is a lightweight scanner rather than a full parser, so it also works
+// with dashboard template variables in the query.
+func promqlMetricNames(expr string) []string {
+	found := make(map[string]bool)
+	s := &promqlScanner{input: expr}
+	for s.pos < len(s.input) {
+		c := s.input[s.pos]
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			s.readString()
+
+		case c == '[':
+			// range, subquery or a [[variable]]
+			s.skipUntil(']')
+
+		case c == '{':
+			s.pos++
+			for _, name := range s.readMatchers() {
+				found[name] = true
+			}
+
+		case c == '#':
+			s.skipUntil('\n')
+
+		case isPromqlDigit(c):
+			// numbers and durations
+			for s.pos < len(s.input) && (isPromqlIdent(s.input[s.pos]) || s.input[s.pos] == '.') {
+				s.pos++
+			}
+
+		case isPromqlIdentStart(c) || c == '$':
+			word, templated := s.readWord()
+			next := s.peek()
+			switch {
+			case templated:
+			case promqlGrouping[word]:
+				if next == '(' {
+					s.skipUntil(')')
+				}
+			case next == '(':
+				// function call, the arguments are scanned next
+			case promqlAggregations[word] && (s.peekWord() == "by" || s.peekWord() == "without"):
+			case promqlKeywords[strings.ToLower(word)]:
+			default:
+				found[word] = true
+			}
+
+		default:
+			s.pos++
+		}
+	}
+
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type promqlScanner struct {
+	input string
+	pos   int
+}
+
+// readWord reads an identifier, that may include template variables (`$var`, `${var}`)
+func (s *promqlScanner) readWord() (word string, templated bool) {
+	start := s.pos
+	for s.pos < len(s.input) {
+		c := s.input[s.pos]
+		switch {
+		case isPromqlIdent(c):
+			s.pos++
+		case c == '$':
+			templated = true
+			s.pos++
+			if s.pos < len(s.input) && s.input[s.pos] == '{' {
+				s.skipUntil('}')
+			}
+		default:
+			return s.input[start:s.pos], templated
+		}
+	}
+	return s.input[start:s.pos], templated
+}
+
+// readString reads a quoted string, and returns the unquoted value
+func (s *promqlScanner) readString() string {
+	quote := s.input[s.pos]
+	s.pos++
+	var sb strings.Builder
+	for s.pos < len(s.input) {
+		c := s.input[s.pos]
+		s.pos++
+		switch {
+		case c == quote:
+			return sb.String()
+		case c == '\\' && quote != '`' && s.pos < len(s.input):
+			sb.WriteByte(s.input[s.pos])
+			s.pos++
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// readMatchers reads the label matchers up to the closing brace, and returns the metric names
+// selected with `__name__="x"` or a quoted name (`{"x", job="y"}`)
+func (s *promqlScanner) readMatchers() []string {
+	var names []string
+	label, op := "", ""
+	for s.pos < len(s.input) {
+		c := s.input[s.pos]
+		switch {
+		case c == '}':
+			s.pos++
+			return names
+		case c == '"' || c == '\'' || c == '`':
+			value := s.readString()
+			if value != "" && ((label == "__name__" && op == "=") || (label == "" && op == "")) {
+				names = append(names, value)
+			}
+			label, op = "", ""
+		case c == '=' || c == '!' || c == '~':
+			op += string(c)
+			s.pos++
+		case isPromqlIdentStart(c):
+			label, _ = s.readWord()
+			op = ""
+		default:
+			s.pos++
+		}
+	}
+	return names
+}
+
+func (s *promqlScanner) skipUntil(end byte) {
+	for s.pos < len(s.input) {
+		c := s.input[s.pos]
+		s.pos++
+		if c == end {
+			return
+		}
+	}
+}
+
+// peek returns the next non-space character
+func (s *promqlScanner) peek() byte {
+	for i := s.pos; i < len(s.input); i++ {
+		if !isPromqlSpace(s.input[i]) {
+			return s.input[i]
+		}
+	}
+	return 0
+}
+
+// peekWord returns the next identifier without consuming it
+func (s *promqlScanner) peekWord() string {
+	i := s.pos
+	for i < len(s.input) && isPromqlSpace(s.input[i]) {
+		i++
+	}
+	start := i
+	for i < len(s.input) && isPromqlIdent(s.input[i]) {
+		i++
+	}
+	return s.input[start:i]
+}
+
+func isPromqlSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isPromqlDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isPromqlIdentStart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_' || c == ':'
+}
+
+func isPromqlIdent(c byte) bool {
+	return isPromqlIdentStart(c) || isPromqlDigit(c)
+}
@@ -0,0 +1,64 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromqlMetricNames(t *testing.T) {
+	tests := []struct {
+		expr    string
+		metrics []string
+	}{
+		{expr: `up`, metrics: []string{"up"}},
+		{expr: `rate(http_requests_total{job="api", code=~"5.."}[5m])`, metrics: []string{"http_requests_total"}},
+		{expr: `sum by (job, instance) (rate(node_cpu_seconds_total{mode!="idle"}[$__rate_interval]))`, metrics: []string{"node_cpu_seconds_total"}},
+		{expr: `sum(rate(a_total[1m])) without (pod) / ignoring(le) group_left(team) b_info`, metrics: []string{"a_total", "b_info"}},
+		{expr: `histogram_quantile(0.95, sum(rate(request_duration_seconds_bucket[5m])) by (le))`, metrics: []string{"request_duration_seconds_bucket"}},
+		{expr: `{__name__="process_open_fds", job="x"} > bool 10 and on(instance) up offset 1h`, metrics: []string{"process_open_fds", "up"}},
+		{expr: `{"my.dotted.metric", env="prod"}`, metrics: []string{"my.dotted.metric"}},
+		{expr: `label_replace(job:requests:rate5m, "dst", "$1", "src", "(.*)")`, metrics: []string{"job:requests:rate5m"}},
+		{expr: `${prefix}_requests_total + $metric + go_goroutines{instance="$instance"}`, metrics: []string{"go_goroutines"}},
+		{expr: `max_over_time(deriv(temperature[1h:5m])[1d:]) @ start()`, metrics: []string{"temperature"}},
+		{expr: `vector(1) * Inf`, metrics: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			require.Equal(t, tt.metrics, promqlMetricNames(tt.expr))
+		})
+	}
+}
+
+func TestReadPanelQueries(t *testing.T) {
+	lookup := CreateDatasourceLookup([]*DatasourceQueryResult{
+		{UID: "prom", Type: "prometheus", Name: "Prometheus", IsDefault: true},
+		{UID: "loki", Type: "loki", Name: "Loki"},
+	})
+
+	dash, err := ReadDashboard(strings.NewReader(`{
+		"panels": [{
+			"type": "timeseries",
+			"targets": [
+				{"refId": "A", "expr": "rate(http_requests_total[5m])"},
+				{"refId": "B", "expr": "sum(count_over_time({app=\"api\"}[1m]))", "datasource": {"uid": "loki"}},
+				{"refId": "C", "rawSql": "SELECT * FROM logs"},
+				{"refId": "D", "query": {"structured": true}}
+			]
+		}, {
+			"type": "text",
+			"options": {"mode": "markdown", "content": "# Runbook"}
+		}]
+	}`), lookup)
+	require.NoError(t, err)
+	require.Len(t, dash.Panels, 2)
+	require.Equal(t, []string{
+		"rate(http_requests_total[5m])",
+		"sum(count_over_time({app=\"api\"}[1m]))",
+		"SELECT * FROM logs",
+	}, dash.Panels[0].Queries)
+	require.Equal(t, []string{"http_requests_total"}, dash.Panels[0].MetricNames)
+	require.Equal(t, "# Runbook", dash.Panels[1].Text)
+}
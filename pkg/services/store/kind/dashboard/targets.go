@@ -1,12 +1,34 @@
 package dashboard
 
 import (
+	"sort"
+
 	jsoniter "github.com/json-iterator/go"
 )
 
+// The target properties that hold the query text for the common datasources
+// (PromQL/LogQL in expr, SQL in rawSql, Graphite in target, ...)
+var targetQueryFields = map[string]bool{
+	"expr":       true,
+	"query":      true,
+	"rawSql":     true,
+	"target":     true,
+	"expression": true,
+}
+
+type targetQuery struct {
+	datasource *DataSourceRef
+	field      string
+	text       string
+}
+
 type targetInfo struct {
 	lookup DatasourceLookup
 	uids   map[string]*DataSourceRef
+
+	// the panel level datasource, used when the target does not define one
+	panel   *DataSourceRef
+	queries []targetQuery
 }
 
 func newTargetInfo(lookup DatasourceLookup) targetInfo {
@@ -26,8 +48,42 @@ func (s *targetInfo) GetDatasourceInfo() []DataSourceRef {
 	return keys
 }
 
+// GetQueryInfo returns the query text of every target, and the metric names referenced by the prometheus queries
+func (s *targetInfo) GetQueryInfo() (queries []string, metrics []string) {
+	seenQuery := make(map[string]bool)
+	seenMetric := make(map[string]bool)
+	for _, q := range s.queries {
+		if !seenQuery[q.text] {
+			seenQuery[q.text] = true
+			queries = append(queries, q.text)
+		}
+
+		if q.field != "expr" || !s.isPrometheus(q.datasource) {
+			continue
+		}
+		for _, m := range promqlMetricNames(q.text) {
+			if !seenMetric[m] {
+				seenMetric[m] = true
+				metrics = append(metrics, m)
+			}
+		}
+	}
+	sort.Strings(metrics)
+	return queries, metrics
+}
+
+func (s *targetInfo) isPrometheus(ref *DataSourceRef) bool {
+	if ref == nil || (ref.UID == "" && ref.Type == "") {
+		ref = s.panel
+	}
+	if ref == nil || (ref.UID == "" && ref.Type == "") {
+		ref = s.lookup.ByRef(nil)
+	}
+	return ref != nil && ref.Type == "prometheus"
+}
+
 // the node will either be string (name|uid) OR ref
-func (s *targetInfo) addDatasource(iter *jsoniter.Iterator) {
+func (s *targetInfo) addDatasource(iter *jsoniter.Iterator) *DataSourceRef {
 	switch iter.WhatIsNext() {
 	case jsoniter.StringValue:
 		key := iter.ReadString()
@@ -36,28 +92,38 @@ func (s *targetInfo) addDatasource(iter *jsoniter.Iterator) {
 		if !isVariableRef(dsRef.UID) && !isSpecialDatasource(dsRef.UID) {
 			ds := s.lookup.ByRef(dsRef)
 			s.addRef(ds)
-		} else {
-			s.addRef(dsRef)
+			return ds
 		}
+		s.addRef(dsRef)
+		return dsRef
 
 	case jsoniter.NilValue:
-		s.addRef(s.lookup.ByRef(nil))
+		ds := s.lookup.ByRef(nil)
+		s.addRef(ds)
 		iter.Skip()
+		return ds
 
 	case jsoniter.ObjectValue:
 		ref := &DataSourceRef{}
 		iter.ReadVal(ref)
 
 		if !isVariableRef(ref.UID) && !isSpecialDatasource(ref.UID) {
-			s.addRef(s.lookup.ByRef(ref))
-		} else {
-			s.addRef(ref)
+			ds := s.lookup.ByRef(ref)
+			s.addRef(ds)
+			return ds
 		}
+		s.addRef(ref)
+		return ref
 
 	default:
 		v := iter.Read()
 		logf("[Panel.datasource.unknown] %v\n", v)
 	}
+	return nil
+}
+
+func (s *targetInfo) addPanelDatasource(iter *jsoniter.Iterator) {
+	s.panel = s.addDatasource(iter)
 }
 
 func (s *targetInfo) addRef(ref *DataSourceRef) {
@@ -67,19 +133,33 @@ func (s *targetInfo) addRef(ref *DataSourceRef) {
 }
 
 func (s *targetInfo) addTarget(iter *jsoniter.Iterator) {
+	var ds *DataSourceRef
+	var queries []targetQuery
 	for l1Field := iter.ReadObject(); l1Field != ""; l1Field = iter.ReadObject() {
-		switch l1Field {
-		case "datasource":
-			s.addDatasource(iter)
+		switch {
+		case l1Field == "datasource":
+			ds = s.addDatasource(iter)
 
-		case "refId":
+		case l1Field == "refId":
 			iter.Skip()
 
+		// Some datasources use the same property names for structured queries
+		case targetQueryFields[l1Field] && iter.WhatIsNext() == jsoniter.StringValue:
+			if text := iter.ReadString(); text != "" {
+				queries = append(queries, targetQuery{field: l1Field, text: text})
+			}
+
 		default:
 			v := iter.Read()
 			logf("[Panel.TARGET] %s=%v\n", l1Field, v)
 		}
 	}
+
+	// The datasource may be defined after the query
+	for i := range queries {
+		queries[i].datasource = ds
+	}
+	s.queries = append(s.queries, queries...)
 }
 
 func (s *targetInfo) addPanel(panel PanelSummaryInfo) {
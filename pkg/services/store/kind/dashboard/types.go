@@ -9,6 +9,9 @@ type PanelSummaryInfo struct {
 	LibraryPanel  string          `json:"libraryPanel,omitempty"` // UID of referenced library panel
 	Datasource    []DataSourceRef `json:"datasource,omitempty"`   // UIDs
 	Transformer   []string        `json:"transformer,omitempty"`  // ids of the transformation steps
+	Queries       []string        `json:"queries,omitempty"`      // query text of the targets (PromQL, LogQL, SQL...)
+	MetricNames   []string        `json:"metricNames,omitempty"`  // metrics referenced by prometheus queries
+	Text          string          `json:"text,omitempty"`         // markdown/html content of text panels
 	// Rows define panels as sub objects
 	Collapsed []PanelSummaryInfo `json:"collapsed,omitempty"`
 }
@@ -455,6 +455,16 @@ type Cfg struct {
 	// LiveMessageSizeLimit is the maximum size in bytes of Websocket messages
 	// from clients. Defaults to 64KB.
 	LiveMessageSizeLimit int
+	// LiveHistoryMaxFrames is the number of frames kept for each managed stream
+	// channel and replayed to new subscribers. 0 means no limit on the count.
+	LiveHistoryMaxFrames int
+	// LiveHistoryMaxAge is the age of the frames kept for each managed stream
+	// channel. 0 means no limit on the age. The history is disabled when both
+	// limits are 0.
+	LiveHistoryMaxAge time.Duration
+	// LivePipelineEnabled processes the data published to channels with the
+	// Live pipeline rules, and enables the API managing them.
+	LivePipelineEnabled bool
 
 	// Grafana.com URL, used for OAuth redirect.
 	GrafanaComURL string
@@ -561,6 +571,12 @@ type Cfg struct {
 	IndexFileThreshold                         int
 	IndexMinCount                              int
 	IndexRebuildInterval                       time.Duration
+	IndexSnapshots                             bool
+	IndexSnapshotInterval                      time.Duration
+	IndexSnapshotMinCount                      int
+	SearchFuzzyMaxEdits                        int
+	SearchSynonymsFile                         string
+	SearchUsageBoost                           float64
 	EnableSharding                             bool
 	MemberlistBindAddr                         string
 	MemberlistAdvertiseAddr                    string
@@ -585,6 +601,12 @@ type UnifiedStorageConfig struct {
 	DataSyncerInterval time.Duration
 	// DataSyncerRecordsLimit defines how many records will be processed at max during a sync invocation.
 	DataSyncerRecordsLimit int
+	// HistoryKeepVersions keeps the latest versions of each resource in the history.
+	HistoryKeepVersions int
+	// HistoryKeepAll keeps every version written in this period.
+	HistoryKeepAll time.Duration
+	// HistoryKeepDaily keeps the last version of each day in this period.
+	HistoryKeepDaily time.Duration
 }
 
 type InstallPlugin struct {
@@ -2029,6 +2051,16 @@ func (cfg *Cfg) readLiveSettings(iniFile *ini.File) error {
 	cfg.LiveHAEngineAddress = section.Key("ha_engine_address").MustString("127.0.0.1:6379")
 	cfg.LiveHAEnginePassword = section.Key("ha_engine_password").MustString("")
 
+	cfg.LiveHistoryMaxFrames = section.Key("history_max_frames").MustInt(0)
+	if cfg.LiveHistoryMaxFrames < 0 {
+		return fmt.Errorf("unexpected value %d for [live] history_max_frames", cfg.LiveHistoryMaxFrames)
+	}
+	cfg.LiveHistoryMaxAge = section.Key("history_max_age").MustDuration(0)
+	if cfg.LiveHistoryMaxAge < 0 {
+		return fmt.Errorf("unexpected value %s for [live] history_max_age", cfg.LiveHistoryMaxAge)
+	}
+	cfg.LivePipelineEnabled = section.Key("pipeline_enabled").MustBool(false)
+
 	allowedOrigins := section.Key("allowed_origins").MustString("")
 	origins := strings.Split(allowedOrigins, ",")
 
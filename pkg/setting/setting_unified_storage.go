@@ -39,12 +39,20 @@ func (cfg *Cfg) setUnifiedStorageConfig() {
 		// parse dataSyncerInterval from resource section
 		dataSyncerInterval := section.Key("dataSyncerInterval").MustDuration(time.Hour)
 
+		// parse the history retention, every version is kept when not set
+		historyKeepVersions := section.Key("historyKeepVersions").MustInt(0)
+		historyKeepAll := section.Key("historyKeepAll").MustDuration(0)
+		historyKeepDaily := section.Key("historyKeepDaily").MustDuration(0)
+
 		storageConfig[resourceName] = UnifiedStorageConfig{
 			DualWriterMode:                       rest.DualWriterMode(dualWriterMode),
 			DualWriterPeriodicDataSyncJobEnabled: dualWriterPeriodicDataSyncJobEnabled,
 			DualWriterMigrationDataSyncDisabled:  dualWriterMigrationDataSyncDisabled,
 			DataSyncerRecordsLimit:               dataSyncerRecordsLimit,
 			DataSyncerInterval:                   dataSyncerInterval,
+			HistoryKeepVersions:                  historyKeepVersions,
+			HistoryKeepAll:                       historyKeepAll,
+			HistoryKeepDaily:                     historyKeepDaily,
 		}
 	}
 	cfg.UnifiedStorage = storageConfig
@@ -66,6 +74,14 @@ func (cfg *Cfg) setUnifiedStorageConfig() {
 	cfg.IndexMinCount = section.Key("index_min_count").MustInt(1)
 	// default to 24 hours because usage insights summarizes the data every 24 hours
 	cfg.IndexRebuildInterval = section.Key("index_rebuild_interval").MustDuration(24 * time.Hour)
+	// share the file based indexes with the other replicas through the apiserver blob store
+	cfg.IndexSnapshots = section.Key("index_snapshots").MustBool(false)
+	cfg.IndexSnapshotInterval = section.Key("index_snapshot_interval").MustDuration(time.Hour)
+	cfg.IndexSnapshotMinCount = section.Key("index_snapshot_min_count").MustInt(1000)
+	// ranking of the text queries, the typo tolerance and the usage ranking are opt-in
+	cfg.SearchFuzzyMaxEdits = section.Key("search_fuzzy_max_edits").MustInt(0)
+	cfg.SearchSynonymsFile = section.Key("search_synonyms_file").String()
+	cfg.SearchUsageBoost = section.Key("search_usage_boost").MustFloat64(0)
 	cfg.SprinklesApiServer = section.Key("sprinkles_api_server").String()
 	cfg.SprinklesApiServerPageLimit = section.Key("sprinkles_api_server_page_limit").MustInt(100)
 	cfg.CACertPath = section.Key("ca_cert_path").String()
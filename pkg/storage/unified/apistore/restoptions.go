@@ -52,7 +52,8 @@ func NewRESTOptionsGetterMemory(originalStorageConfig storagebackend.Config) (*R
 		return nil, err
 	}
 	server, err := resource.NewResourceServer(resource.ResourceServerOptions{
-		Backend: backend,
+		Backend:   backend,
+		Lifecycle: backend,
 	})
 	if err != nil {
 		return nil, err
@@ -88,7 +89,8 @@ func NewRESTOptionsGetterForFile(path string,
 		return nil, err
 	}
 	server, err := resource.NewResourceServer(resource.ResourceServerOptions{
-		Backend: backend,
+		Backend:   backend,
+		Lifecycle: backend,
 	})
 	if err != nil {
 		return nil, err
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
@@ -25,6 +26,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/apiserver/options"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/storage/legacysql"
 	"github.com/grafana/grafana/pkg/storage/unified/federated"
@@ -41,6 +43,9 @@ type Options struct {
 	Reg      prometheus.Registerer
 	Authzc   types.AccessClient
 	Docs     resource.DocumentBuilderSupplier
+
+	// Sends the saved search alerts (optional)
+	Notifications notifications.Service
 }
 
 type clientMetrics struct {
@@ -52,13 +57,21 @@ type clientMetrics struct {
 func ProvideUnifiedStorageClient(opts *Options, storageMetrics *resource.StorageMetrics, indexMetrics *resource.BleveIndexMetrics) (resource.ResourceClient, error) {
 	// See: apiserver.applyAPIServerConfig(cfg, features, o)
 	apiserverCfg := opts.Cfg.SectionWithEnvOverrides("grafana-apiserver")
+	var notifier resource.SearchAlertNotifier
+	if opts.Notifications != nil {
+		notifier = search.NewSearchAlertNotifier(opts.Notifications)
+	}
 	client, err := newClient(options.StorageOptions{
 		StorageType:        options.StorageType(apiserverCfg.Key("storage_type").MustString(string(options.StorageTypeUnified))),
 		DataPath:           apiserverCfg.Key("storage_path").MustString(filepath.Join(opts.Cfg.DataPath, "grafana-apiserver")),
 		Address:            apiserverCfg.Key("address").MustString(""), // client address
 		BlobStoreURL:       apiserverCfg.Key("blob_url").MustString(""),
 		BlobThresholdBytes: apiserverCfg.Key("blob_threshold_bytes").MustInt(options.BlobThresholdDefault),
-	}, opts.Cfg, opts.Features, opts.DB, opts.Tracer, opts.Reg, opts.Authzc, opts.Docs, storageMetrics, indexMetrics)
+		// shards = name=address, name=address
+		ShardAddresses: parseShardMap(apiserverCfg.Key("shards").MustString("")),
+		// shard_namespaces = namespace=name, namespace=name
+		ShardNamespaces: parseShardMap(apiserverCfg.Key("shard_namespaces").MustString("")),
+	}, opts.Cfg, opts.Features, opts.DB, opts.Tracer, opts.Reg, opts.Authzc, opts.Docs, storageMetrics, indexMetrics, notifier)
 	if err == nil {
 		// Used to get the folder stats
 		client = federated.NewFederatedClient(
@@ -80,6 +93,7 @@ func newClient(opts options.StorageOptions,
 	docs resource.DocumentBuilderSupplier,
 	storageMetrics *resource.StorageMetrics,
 	indexMetrics *resource.BleveIndexMetrics,
+	notifier resource.SearchAlertNotifier,
 ) (resource.ResourceClient, error) {
 	ctx := context.Background()
 	switch opts.StorageType {
@@ -101,7 +115,8 @@ func newClient(opts options.StorageOptions,
 			return nil, err
 		}
 		server, err := resource.NewResourceServer(resource.ResourceServerOptions{
-			Backend: backend,
+			Backend:   backend,
+			Lifecycle: backend,
 			Blob: resource.BlobConfig{
 				URL: opts.BlobStoreURL,
 			},
@@ -112,43 +127,33 @@ func newClient(opts options.StorageOptions,
 		return resource.NewLocalResourceClient(server), nil
 
 	case options.StorageTypeUnifiedGrpc:
-		if opts.Address == "" {
-			return nil, fmt.Errorf("expecting address for storage_type: %s", opts.StorageType)
-		}
-
-		var (
-			conn    grpc.ClientConnInterface
-			err     error
-			metrics = newClientMetrics(reg)
-		)
-		// Create either a connection pool or a single connection.
-		// The connection pool __can__ be useful when connection to
-		// server side load balancers like kube-proxy.
-		if features.IsEnabledGlobally(featuremgmt.FlagUnifiedStorageGrpcConnectionPool) {
-			conn, err = newPooledConn(&poolOpts{
-				initialCapacity: 3,
-				maxCapacity:     6,
-				idleTimeout:     time.Minute,
-				factory: func() (*grpc.ClientConn, error) {
-					return grpcConn(opts.Address, metrics)
-				},
-			})
-			if err != nil {
-				return nil, err
+		metrics := newClientMetrics(reg)
+		if len(opts.ShardAddresses) > 0 {
+			shards := make(map[string]resource.ResourceClient, len(opts.ShardAddresses))
+			for name, address := range opts.ShardAddresses {
+				client, err := newGrpcClient(address, cfg, features, tracer, metrics)
+				if err != nil {
+					return nil, fmt.Errorf("shard %s: %w", name, err)
+				}
+				shards[name] = client
 			}
-		} else {
-			conn, err = grpcConn(opts.Address, metrics)
+			client, err := federated.NewShardedClient(federated.ShardedClientOptions{
+				Shards:     shards,
+				Namespaces: opts.ShardNamespaces,
+				// the namespaces moved between the shards
+				Store: newShardMapStore(db, tracer),
+			})
 			if err != nil {
 				return nil, err
 			}
+			go client.Run(ctx)
+			return client, nil
 		}
 
-		// Create a client instance
-		client, err := resource.NewResourceClient(conn, cfg, features, tracer)
-		if err != nil {
-			return nil, err
+		if opts.Address == "" {
+			return nil, fmt.Errorf("expecting address for storage_type: %s", opts.StorageType)
 		}
-		return client, nil
+		return newGrpcClient(opts.Address, cfg, features, tracer, metrics)
 
 	// Use the local SQL
 	default:
@@ -156,7 +161,7 @@ func newClient(opts options.StorageOptions,
 		if err != nil {
 			return nil, err
 		}
-		server, err := sql.NewResourceServer(db, cfg, tracer, reg, authzc, searchOptions, storageMetrics, indexMetrics, features)
+		server, err := sql.NewResourceServer(db, cfg, tracer, reg, authzc, searchOptions, storageMetrics, indexMetrics, features, notifier)
 		if err != nil {
 			return nil, err
 		}
@@ -164,6 +169,53 @@ func newClient(opts options.StorageOptions,
 	}
 }
 
+// newGrpcClient creates a client of the storage server at the address
+func newGrpcClient(address string, cfg *setting.Cfg, features featuremgmt.FeatureToggles, tracer tracing.Tracer, metrics *clientMetrics) (resource.ResourceClient, error) {
+	var (
+		conn grpc.ClientConnInterface
+		err  error
+	)
+	// Create either a connection pool or a single connection.
+	// The connection pool __can__ be useful when connection to
+	// server side load balancers like kube-proxy.
+	if features.IsEnabledGlobally(featuremgmt.FlagUnifiedStorageGrpcConnectionPool) {
+		conn, err = newPooledConn(&poolOpts{
+			initialCapacity: 3,
+			maxCapacity:     6,
+			idleTimeout:     time.Minute,
+			factory: func() (*grpc.ClientConn, error) {
+				return grpcConn(address, metrics)
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		conn, err = grpcConn(address, metrics)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Create a client instance
+	return resource.NewResourceClient(conn, cfg, features, tracer)
+}
+
+// parseShardMap reads a list of key=value pairs separated by commas
+func parseShardMap(v string) map[string]string {
+	if strings.TrimSpace(v) == "" {
+		return nil
+	}
+	m := make(map[string]string)
+	for _, pair := range strings.Split(v, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if ok {
+			m[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return m
+}
+
 // grpcConn creates a new gRPC connection to the provided address.
 func grpcConn(address string, metrics *clientMetrics) (*grpc.ClientConn, error) {
 	// Report gRPC status code errors as labels.
@@ -212,12 +264,12 @@ func GrpcConn(address string, reg prometheus.Registerer) (*grpc.ClientConn, erro
 // and middleware.StreamClientUserHeaderInterceptor as we don't need them.
 func instrument(requestDuration *prometheus.HistogramVec, instrumentationLabelOptions ...middleware.InstrumentationOption) ([]grpc.UnaryClientInterceptor, []grpc.StreamClientInterceptor) {
 	return []grpc.UnaryClientInterceptor{
-			otgrpc.OpenTracingClientInterceptor(opentracing.GlobalTracer()),
-			middleware.UnaryClientInstrumentInterceptor(requestDuration, instrumentationLabelOptions...),
-		}, []grpc.StreamClientInterceptor{
-			otgrpc.OpenTracingStreamClientInterceptor(opentracing.GlobalTracer()),
-			middleware.StreamClientInstrumentInterceptor(requestDuration, instrumentationLabelOptions...),
-		}
+		otgrpc.OpenTracingClientInterceptor(opentracing.GlobalTracer()),
+		middleware.UnaryClientInstrumentInterceptor(requestDuration, instrumentationLabelOptions...),
+	}, []grpc.StreamClientInterceptor{
+		otgrpc.OpenTracingStreamClientInterceptor(opentracing.GlobalTracer()),
+		middleware.StreamClientInstrumentInterceptor(requestDuration, instrumentationLabelOptions...),
+	}
 }
 
 func newClientMetrics(reg prometheus.Registerer) *clientMetrics {
@@ -44,3 +44,18 @@ func (s *federatedClient) GetStats(ctx context.Context, in *resourcepb.ResourceS
 
 	return rsp, err
 }
+
+// NamespaceMover is implemented by the clients moving the namespaces between storage servers
+type NamespaceMover interface {
+	StartNamespaceMove(ctx context.Context, namespace string, target string) (*NamespaceMove, error)
+	ListNamespaceMoves(ctx context.Context) ([]*NamespaceMove, error)
+}
+
+// AsNamespaceMover returns the client moving the namespaces, only set when the storage is sharded
+func AsNamespaceMover(client resource.ResourceClient) (NamespaceMover, bool) {
+	if f, ok := client.(*federatedClient); ok {
+		client = f.ResourceClient
+	}
+	mover, ok := client.(NamespaceMover)
+	return mover, ok
+}
@@ -0,0 +1,577 @@
+package federated
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// The number of objects read per list request while copying a namespace
+const moveListLimit = 500
+
+type NamespaceMoveState string
+
+const (
+	NamespaceMovePending NamespaceMoveState = "pending"
+	// Everything is copied while the namespace is still written
+	NamespaceMoveCopying NamespaceMoveState = "copying"
+	// The writes are rejected while the collections changed since the first copy are copied again
+	NamespaceMoveFrozen    NamespaceMoveState = "frozen"
+	NamespaceMoveCompleted NamespaceMoveState = "completed"
+	NamespaceMoveFailed    NamespaceMoveState = "failed"
+)
+
+// Running returns true until the move is completed or failed
+func (s NamespaceMoveState) Running() bool {
+	return s == NamespaceMovePending || s == NamespaceMoveCopying || s == NamespaceMoveFrozen
+}
+
+// NamespaceMove is the job moving a namespace to another shard, it is saved in the shard map at each step
+type NamespaceMove struct {
+	Namespace   string                    `json:"namespace"`
+	From        string                    `json:"from"`
+	To          string                    `json:"to"`
+	State       NamespaceMoveState        `json:"state"`
+	Error       string                    `json:"error,omitempty"`
+	Collections []*resourcepb.ResourceKey `json:"collections,omitempty"`
+
+	// The objects copied while the namespace was still written
+	Copied int64 `json:"copied"`
+	// The objects copied again while the writes were blocked
+	Recopied int64 `json:"recopied"`
+
+	// The clients rejecting the writes of the namespace while it is frozen
+	Acked []string `json:"acked,omitempty"`
+
+	// The client running the move, it saves the move at least every refresh interval
+	Owner   string    `json:"owner,omitempty"`
+	Started time.Time `json:"started"`
+	Updated time.Time `json:"updated"`
+}
+
+// StartNamespaceMove saves a new move of the namespace to the target shard, and runs it in the background.
+// Everything is first copied while the namespace is still written. The writes are then rejected by every client
+// while the collections changed in the meantime are copied again, and the namespace is switched to the target.
+// Only the latest version of each object is copied with its blob, and the deleted objects with the version
+// restored from the trash. The rest of the history stays on the previous shard.
+// The data on the previous shard is not removed, so the move can be reverted by moving the namespace back.
+// When the client stops, another client resumes the move from its last step.
+func (c *ShardedClient) StartNamespaceMove(ctx context.Context, namespace string, target string) (*NamespaceMove, error) {
+	if c.store == nil {
+		return nil, fmt.Errorf("moving a namespace requires a shard map store")
+	}
+	if namespace == "" {
+		return nil, fmt.Errorf("missing namespace")
+	}
+	if _, ok := c.shards[target]; !ok {
+		return nil, fmt.Errorf("unknown shard %s", target)
+	}
+
+	var move *NamespaceMove
+	m, err := c.store.Update(ctx, func(m *ShardMap) error {
+		source := c.shardIn(m.Moved, namespace)
+		if source == target {
+			return fmt.Errorf("namespace %s is already on shard %s", namespace, target)
+		}
+		if current := m.Moves[namespace]; current != nil && current.State.Running() {
+			return fmt.Errorf("namespace %s is already being moved", namespace)
+		}
+		now := c.now()
+		move = &NamespaceMove{
+			Namespace: namespace,
+			From:      source,
+			To:        target,
+			State:     NamespaceMovePending,
+			Owner:     c.id,
+			Started:   now,
+			Updated:   now,
+		}
+		m.Moves[namespace] = move
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.apply(m)
+
+	go c.runMove(context.WithoutCancel(ctx), namespace, false)
+	return move, nil
+}
+
+// ListNamespaceMoves returns the last move of each namespace, the most recent first
+func (c *ShardedClient) ListNamespaceMoves(ctx context.Context) ([]*NamespaceMove, error) {
+	if c.store == nil {
+		return nil, nil
+	}
+	m, err := c.store.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	moves := slices.Collect(maps.Values(m.Moves))
+	slices.SortFunc(moves, func(a, b *NamespaceMove) int {
+		return b.Started.Compare(a.Started)
+	})
+	return moves, nil
+}
+
+// runMove claims the move and runs it. A move is resumed when its owner did not save it for a while.
+func (c *ShardedClient) runMove(ctx context.Context, namespace string, resume bool) {
+	move, err := c.claimMove(ctx, namespace, resume)
+	if err != nil {
+		c.log.Warn("failed to claim the namespace move", "namespace", namespace, "err", err)
+		return
+	}
+	if move == nil {
+		return // run by another client
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go c.keepMove(ctx, cancel, namespace)
+
+	c.log.Info("moving namespace", "namespace", namespace, "from", move.From, "to", move.To, "state", move.State)
+	if err = c.moveNamespace(ctx, move); err != nil {
+		c.log.Error("failed to move namespace", "namespace", namespace, "err", err)
+		err = c.updateMove(context.WithoutCancel(ctx), namespace, func(move *NamespaceMove) error {
+			move.State = NamespaceMoveFailed
+			move.Error = err.Error()
+			return nil
+		})
+		if err != nil {
+			c.log.Warn("failed to save the failed namespace move", "namespace", namespace, "err", err)
+		}
+	}
+}
+
+var errMoveNotOwned = errors.New("the namespace move is run by another client")
+
+// claimMove makes this client the owner of the move, it returns nil when the move is run by another client
+func (c *ShardedClient) claimMove(ctx context.Context, namespace string, resume bool) (*NamespaceMove, error) {
+	var claimed *NamespaceMove
+	err := c.updateMove(ctx, namespace, func(move *NamespaceMove) error {
+		if !move.State.Running() {
+			return errMoveNotOwned
+		}
+		if resume && c.now().Sub(move.Updated) < c.moveStaleAfter() {
+			return errMoveNotOwned
+		}
+		if !resume && (move.Owner != c.id || move.State != NamespaceMovePending) {
+			return errMoveNotOwned
+		}
+		move.Owner = c.id
+		move.Updated = c.now()
+		claimed = move
+		return nil
+	})
+	if errors.Is(err, errMoveNotOwned) {
+		return nil, nil
+	}
+	return claimed, err
+}
+
+// keepMove saves the move every refresh interval, so no other client resumes it.
+// The move is canceled when another client took it over.
+func (c *ShardedClient) keepMove(ctx context.Context, cancel context.CancelFunc, namespace string) {
+	ticker := time.NewTicker(c.refresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		err := c.updateMove(ctx, namespace, func(move *NamespaceMove) error {
+			if move.Owner != c.id {
+				return errMoveNotOwned
+			}
+			move.Updated = c.now()
+			return nil
+		})
+		if errors.Is(err, errMoveNotOwned) {
+			c.log.Warn("namespace move taken over by another client", "namespace", namespace)
+			cancel()
+			return
+		}
+	}
+}
+
+// moveNamespace runs the steps of the move that are not done yet
+func (c *ShardedClient) moveNamespace(ctx context.Context, move *NamespaceMove) error {
+	namespace := move.Namespace
+	src, dst := c.shards[move.From], c.shards[move.To]
+	if src == nil || dst == nil {
+		return fmt.Errorf("unknown shard %s or %s", move.From, move.To)
+	}
+
+	// Copy everything while the namespace is still written
+	collections := move.Collections
+	copiedRV := make(map[string]int64, len(collections))
+	if move.State != NamespaceMoveFrozen {
+		err := c.updateMove(ctx, namespace, func(move *NamespaceMove) error {
+			move.State = NamespaceMoveCopying
+			move.Copied = 0
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		collections, err = namespaceCollections(ctx, src, namespace)
+		if err != nil {
+			return err
+		}
+		for _, key := range collections {
+			n, rv, err := copyCollection(ctx, src, dst, key)
+			if err != nil {
+				return err
+			}
+			copiedRV[key.Group+"/"+key.Resource] = rv
+			err = c.updateMove(ctx, namespace, func(move *NamespaceMove) error {
+				move.Copied += n
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		// Block the writes
+		err = c.updateMove(ctx, namespace, func(move *NamespaceMove) error {
+			move.State = NamespaceMoveFrozen
+			move.Collections = collections
+			move.Acked = nil
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := c.waitFrozen(ctx, namespace); err != nil {
+		return err
+	}
+
+	// Copy again the collections changed in the meantime
+	current, err := namespaceCollections(ctx, src, namespace)
+	if err != nil {
+		return err
+	}
+	// the collections emptied since the first copy are rebuilt empty
+	for _, key := range collections {
+		if !slices.ContainsFunc(current, func(k *resourcepb.ResourceKey) bool { return k.Group == key.Group && k.Resource == key.Resource }) {
+			current = append(current, key)
+		}
+	}
+	collections = current
+	var recopied int64
+	for _, key := range collections {
+		rv, err := collectionVersion(ctx, src, key)
+		if err != nil {
+			return err
+		}
+		if copied, ok := copiedRV[key.Group+"/"+key.Resource]; ok && copied == rv {
+			continue
+		}
+		n, _, err := copyCollection(ctx, src, dst, key)
+		if err != nil {
+			return err
+		}
+		recopied += n
+	}
+
+	// Cut over, the next writes are sent to the target by every client
+	m, err := c.store.Update(ctx, func(m *ShardMap) error {
+		move := m.Moves[namespace]
+		if move == nil || move.Owner != c.id {
+			return errMoveNotOwned
+		}
+		move.State = NamespaceMoveCompleted
+		move.Collections = collections
+		move.Recopied = recopied
+		move.Updated = c.now()
+		m.Moved[namespace] = move.To
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	c.apply(m)
+	return nil
+}
+
+// waitFrozen waits until every client of the shards acknowledged that it rejects the writes of the namespace
+func (c *ShardedClient) waitFrozen(ctx context.Context, namespace string) error {
+	for {
+		m, err := c.heartbeat(ctx)
+		if err != nil {
+			return err
+		}
+		c.apply(m)
+		move := m.Moves[namespace]
+		if move == nil || move.Owner != c.id {
+			return errMoveNotOwned
+		}
+		waiting := 0
+		for id := range m.Clients {
+			if !slices.Contains(move.Acked, id) {
+				waiting++
+			}
+		}
+		if waiting == 0 {
+			return nil
+		}
+		c.log.Debug("waiting for the clients to block the writes", "namespace", namespace, "clients", waiting)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.refresh):
+		}
+	}
+}
+
+// updateMove saves a change of the move of the namespace
+func (c *ShardedClient) updateMove(ctx context.Context, namespace string, fn func(move *NamespaceMove) error) error {
+	m, err := c.store.Update(ctx, func(m *ShardMap) error {
+		move := m.Moves[namespace]
+		if move == nil {
+			return fmt.Errorf("namespace %s is not being moved", namespace)
+		}
+		return fn(move)
+	})
+	if err != nil {
+		return err
+	}
+	c.apply(m)
+	return nil
+}
+
+// namespaceCollections returns the group/resource of every collection with objects in the namespace
+func namespaceCollections(ctx context.Context, client resource.ResourceClient, namespace string) ([]*resourcepb.ResourceKey, error) {
+	stats, err := client.GetStats(ctx, &resourcepb.ResourceStatsRequest{Namespace: namespace})
+	if err != nil {
+		return nil, fmt.Errorf("list collections: %w", err)
+	}
+	if stats.Error != nil {
+		return nil, fmt.Errorf("list collections: %w", resource.GetError(stats.Error))
+	}
+	keys := make([]*resourcepb.ResourceKey, 0, len(stats.Stats))
+	for _, s := range stats.Stats {
+		if s.Count > 0 {
+			keys = append(keys, &resourcepb.ResourceKey{Namespace: namespace, Group: s.Group, Resource: s.Resource})
+		}
+	}
+	return keys, nil
+}
+
+// collectionVersion returns the current resource version of the collection
+func collectionVersion(ctx context.Context, client resource.ResourceClient, key *resourcepb.ResourceKey) (int64, error) {
+	rsp, err := client.List(ctx, &resourcepb.ListRequest{
+		Limit:   1,
+		Options: &resourcepb.ListOptions{Key: key},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("list %s/%s: %w", key.Group, key.Resource, err)
+	}
+	if rsp.Error != nil {
+		return 0, fmt.Errorf("list %s/%s: %w", key.Group, key.Resource, resource.GetError(rsp.Error))
+	}
+	return rsp.ResourceVersion, nil
+}
+
+// copyCollection replaces the collection on the target with the objects of the source, and the objects in its trash.
+// Returns the number of objects copied, and the resource version they were read at.
+func copyCollection(ctx context.Context, src, dst resource.ResourceClient, key *resourcepb.ResourceKey) (int64, int64, error) {
+	settings := resource.BulkSettings{
+		RebuildCollection: true, // remove the objects deleted since the last copy
+		SkipValidation:    true, // already validated when written in the source
+		Collection:        []*resourcepb.ResourceKey{key},
+	}
+	stream, err := dst.BulkProcess(metadata.NewOutgoingContext(ctx, settings.ToMD()))
+	if err != nil {
+		return 0, 0, fmt.Errorf("copy %s/%s: %w", key.Group, key.Resource, err)
+	}
+
+	blobs := &blobCopier{src: src, dst: dst, copied: make(map[string]string)}
+	send := func(action resourcepb.BulkRequest_Action, value []byte) error {
+		value, err := blobs.copy(ctx, key, value)
+		if err != nil {
+			return err
+		}
+		meta := readObjectMeta(value)
+		return stream.Send(&resourcepb.BulkRequest{
+			Key: &resourcepb.ResourceKey{
+				Namespace: key.Namespace,
+				Group:     key.Group,
+				Resource:  key.Resource,
+				Name:      meta.Name,
+			},
+			Action: action,
+			Value:  value,
+			Folder: meta.Annotations[utils.AnnoKeyFolder],
+		})
+	}
+
+	rv, err := listCollection(ctx, src, key, resourcepb.ListRequest_STORE, func(item *resourcepb.ResourceWrapper) error {
+		return send(resourcepb.BulkRequest_ADDED, item.Value)
+	})
+	if err == nil {
+		// The deleted objects are restored from the version written before their deletion marker
+		_, err = listCollection(ctx, src, key, resourcepb.ListRequest_TRASH, func(item *resourcepb.ResourceWrapper) error {
+			previous, err := src.Read(ctx, &resourcepb.ReadRequest{
+				Key: &resourcepb.ResourceKey{
+					Namespace: key.Namespace,
+					Group:     key.Group,
+					Resource:  key.Resource,
+					Name:      readObjectMeta(item.Value).Name,
+				},
+				ResourceVersion: item.ResourceVersion - 1,
+			})
+			if err == nil && previous.Error != nil {
+				err = resource.GetError(previous.Error)
+			}
+			if err != nil {
+				return fmt.Errorf("read deleted object: %w", err)
+			}
+			if err = send(resourcepb.BulkRequest_ADDED, previous.Value); err != nil {
+				return err
+			}
+			return send(resourcepb.BulkRequest_DELETED, item.Value)
+		})
+	}
+	if err != nil {
+		_, _ = stream.CloseAndRecv()
+		return 0, 0, fmt.Errorf("copy %s/%s: %w", key.Group, key.Resource, err)
+	}
+
+	rsp, err := stream.CloseAndRecv()
+	if err == nil && rsp.Error != nil {
+		err = resource.GetError(rsp.Error)
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("copy %s/%s: %w", key.Group, key.Resource, err)
+	}
+	if len(rsp.Rejected) > 0 {
+		return 0, 0, fmt.Errorf("copy %s/%s: %d objects rejected", key.Group, key.Resource, len(rsp.Rejected))
+	}
+	return rsp.Processed, rv, nil
+}
+
+// listCollection calls fn with every object of the collection in the source, and returns the resource version of the list
+func listCollection(ctx context.Context, client resource.ResourceClient, key *resourcepb.ResourceKey, source resourcepb.ListRequest_Source, fn func(item *resourcepb.ResourceWrapper) error) (int64, error) {
+	var rv int64
+	req := &resourcepb.ListRequest{
+		Source:  source,
+		Limit:   moveListLimit,
+		Options: &resourcepb.ListOptions{Key: key},
+	}
+	for {
+		list, err := client.List(ctx, req)
+		if err == nil && list.Error != nil {
+			err = resource.GetError(list.Error)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("list: %w", err)
+		}
+		if rv == 0 {
+			rv = list.ResourceVersion
+		}
+		for _, item := range list.Items {
+			if err = fn(item); err != nil {
+				return 0, err
+			}
+		}
+		if list.NextPageToken == "" {
+			return rv, nil
+		}
+		req.NextPageToken = list.NextPageToken
+	}
+}
+
+// blobCopier copies the blobs of the objects to the target, the blobs are only stored in the shard of their namespace
+type blobCopier struct {
+	src, dst resource.ResourceClient
+	// the annotation of the copy of each blob copied, by blob uid in the source
+	copied map[string]string
+}
+
+// copy copies the blob of the object, and returns the object with its annotation rewritten to the copy
+func (b *blobCopier) copy(ctx context.Context, key *resourcepb.ResourceKey, value []byte) ([]byte, error) {
+	meta := readObjectMeta(value)
+	info := utils.ParseBlobInfo(meta.Annotations[utils.AnnoKeyBlob])
+	if info == nil || info.UID == "" {
+		return value, nil
+	}
+
+	anno, ok := b.copied[info.UID]
+	if !ok {
+		blobKey := &resourcepb.ResourceKey{
+			Namespace: key.Namespace,
+			Group:     key.Group,
+			Resource:  key.Resource,
+			Name:      meta.Name,
+		}
+		found, err := b.src.GetBlob(ctx, &resourcepb.GetBlobRequest{
+			Resource:       blobKey,
+			Uid:            info.UID,
+			MustProxyBytes: true,
+		})
+		if err == nil && found.Error != nil {
+			err = resource.GetError(found.Error)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read blob %s of %s: %w", info.UID, meta.Name, err)
+		}
+		saved, err := b.dst.PutBlob(ctx, &resourcepb.PutBlobRequest{
+			Resource:    blobKey,
+			Method:      resourcepb.PutBlobRequest_GRPC,
+			ContentType: found.ContentType,
+			Value:       found.Value,
+		})
+		if err == nil && saved.Error != nil {
+			err = resource.GetError(saved.Error)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("write blob %s of %s: %w", info.UID, meta.Name, err)
+		}
+		copied := &utils.BlobInfo{
+			UID:      saved.Uid,
+			Size:     saved.Size,
+			Hash:     saved.Hash,
+			MimeType: saved.MimeType,
+			Charset:  saved.Charset,
+		}
+		anno = copied.String()
+		b.copied[info.UID] = anno
+	}
+
+	obj := map[string]any{}
+	if err := json.Unmarshal(value, &obj); err != nil {
+		return nil, fmt.Errorf("read %s: %w", meta.Name, err)
+	}
+	metadata, _ := obj["metadata"].(map[string]any)
+	annotations, _ := metadata["annotations"].(map[string]any)
+	annotations[utils.AnnoKeyBlob] = anno
+	return json.Marshal(obj)
+}
+
+type objectMeta struct {
+	Namespace   string            `json:"namespace"`
+	Name        string            `json:"name"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+func readObjectMeta(value []byte) objectMeta {
+	obj := struct {
+		Metadata objectMeta `json:"metadata"`
+	}{}
+	_ = json.Unmarshal(value, &obj)
+	return obj.Metadata
+}
@@ -0,0 +1,228 @@
+package federated
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// mergeSearchResults joins the results of several shards into the page requested by the original request.
+// The rows are sorted like the search index does: by the requested fields, by score when there is a query,
+// otherwise by title.
+func mergeSearchResults(in *resourcepb.ResourceSearchRequest, rsps []*resourcepb.ResourceSearchResponse) (*resourcepb.ResourceSearchResponse, error) {
+	merged := &resourcepb.ResourceSearchResponse{
+		Key:     in.Options.GetKey(),
+		Results: &resourcepb.ResourceTable{},
+	}
+	facets := make(map[string]map[string]int64)
+	for _, rsp := range rsps {
+		if rsp.Error != nil {
+			return rsp, nil
+		}
+		merged.TotalHits += rsp.TotalHits
+		merged.QueryCost += rsp.QueryCost
+		merged.MaxScore = max(merged.MaxScore, rsp.MaxScore)
+		mergeFacets(merged, facets, rsp.Facet)
+
+		if rsp.Results == nil {
+			continue
+		}
+		if len(merged.Results.Columns) == 0 {
+			merged.Results.Columns = rsp.Results.Columns
+		}
+		merged.Results.Rows = append(merged.Results.Rows, alignRows(merged.Results.Columns, rsp.Results)...)
+	}
+
+	if err := sortRows(in, merged.Results.Columns, merged.Results.Rows); err != nil {
+		return nil, err
+	}
+
+	// Keep the requested page
+	rows := merged.Results.Rows
+	offset := min(int(in.Offset), len(rows))
+	rows = rows[offset:]
+	if in.Limit > 0 && int(in.Limit) < len(rows) {
+		rows = rows[:in.Limit]
+	}
+	merged.Results.Rows = rows
+	return merged, nil
+}
+
+// alignRows returns the rows with their cells in the order of the columns
+func alignRows(columns []*resourcepb.ResourceTableColumnDefinition, table *resourcepb.ResourceTable) []*resourcepb.ResourceTableRow {
+	if slices.EqualFunc(columns, table.Columns, func(a, b *resourcepb.ResourceTableColumnDefinition) bool { return a.Name == b.Name }) {
+		return table.Rows
+	}
+	index := make(map[string]int, len(table.Columns))
+	for i, col := range table.Columns {
+		index[col.Name] = i
+	}
+	for _, row := range table.Rows {
+		cells := make([][]byte, len(columns))
+		for i, col := range columns {
+			if j, ok := index[col.Name]; ok && j < len(row.Cells) {
+				cells[i] = row.Cells[j]
+			}
+		}
+		row.Cells = cells
+	}
+	return table.Rows
+}
+
+func mergeFacets(merged *resourcepb.ResourceSearchResponse, counts map[string]map[string]int64, facets map[string]*resourcepb.ResourceSearchResponse_Facet) {
+	for name, facet := range facets {
+		if merged.Facet == nil {
+			merged.Facet = make(map[string]*resourcepb.ResourceSearchResponse_Facet)
+		}
+		current, ok := merged.Facet[name]
+		if !ok {
+			current = &resourcepb.ResourceSearchResponse_Facet{Field: facet.Field}
+			merged.Facet[name] = current
+			counts[name] = make(map[string]int64)
+		}
+		current.Total += facet.Total
+		current.Missing += facet.Missing
+		for _, term := range facet.Terms {
+			if _, ok := counts[name][term.Term]; !ok {
+				current.Terms = append(current.Terms, &resourcepb.ResourceSearchResponse_TermFacet{Term: term.Term})
+			}
+			counts[name][term.Term] += term.Count
+		}
+	}
+	for name, facet := range merged.Facet {
+		for _, term := range facet.Terms {
+			term.Count = counts[name][term.Term]
+		}
+		slices.SortStableFunc(facet.Terms, func(a, b *resourcepb.ResourceSearchResponse_TermFacet) int {
+			return cmp.Compare(b.Count, a.Count)
+		})
+	}
+}
+
+type sortColumn struct {
+	index int
+	def   *resourcepb.ResourceTableColumnDefinition
+	desc  bool
+}
+
+// sortRows orders the rows by the decoded values of the sort columns
+func sortRows(in *resourcepb.ResourceSearchRequest, columns []*resourcepb.ResourceTableColumnDefinition, rows []*resourcepb.ResourceTableRow) error {
+	sorting := in.SortBy
+	if len(sorting) == 0 {
+		if in.Query != "" && in.Query != "*" {
+			sorting = []*resourcepb.ResourceSearchRequest_Sort{{Field: resource.SEARCH_FIELD_SCORE, Desc: true}}
+		} else {
+			sorting = []*resourcepb.ResourceSearchRequest_Sort{{Field: resource.SEARCH_FIELD_TITLE}}
+		}
+	}
+
+	var sortColumns []sortColumn
+	for _, s := range sorting {
+		field := strings.TrimPrefix(s.Field, resource.SEARCH_FIELD_PREFIX)
+		for i, col := range columns {
+			if col.Name == s.Field || col.Name == field {
+				sortColumns = append(sortColumns, sortColumn{index: i, def: col, desc: s.Desc})
+				break
+			}
+		}
+	}
+	if len(sortColumns) == 0 {
+		return nil
+	}
+
+	type sortedRow struct {
+		row  *resourcepb.ResourceTableRow
+		keys []any
+	}
+	sorted := make([]sortedRow, len(rows))
+	for i, row := range rows {
+		sorted[i] = sortedRow{row: row, keys: make([]any, len(sortColumns))}
+		for j, col := range sortColumns {
+			v, err := decodeSortCell(col, row)
+			if err != nil {
+				return err
+			}
+			sorted[i].keys[j] = v
+		}
+	}
+	slices.SortStableFunc(sorted, func(a, b sortedRow) int {
+		for i, col := range sortColumns {
+			if c := compareCells(a.keys[i], b.keys[i]); c != 0 {
+				if col.desc {
+					return -c
+				}
+				return c
+			}
+		}
+		return 0
+	})
+	for i := range sorted {
+		rows[i] = sorted[i].row
+	}
+	return nil
+}
+
+func decodeSortCell(col sortColumn, row *resourcepb.ResourceTableRow) (any, error) {
+	if col.index >= len(row.Cells) || row.Cells[col.index] == nil {
+		return nil, nil
+	}
+	v, err := resource.DecodeCell(col.def, col.index, row.Cells[col.index])
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", col.def.Name, err)
+	}
+	return v, nil
+}
+
+// compareCells orders the decoded cells, the missing values first
+func compareCells(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	switch va := a.(type) {
+	case string:
+		if vb, ok := b.(string); ok {
+			return strings.Compare(strings.ToLower(va), strings.ToLower(vb))
+		}
+	case int64:
+		if vb, ok := b.(int64); ok {
+			return cmp.Compare(va, vb)
+		}
+	case int32:
+		if vb, ok := b.(int32); ok {
+			return cmp.Compare(va, vb)
+		}
+	case float64:
+		if vb, ok := b.(float64); ok {
+			return cmp.Compare(va, vb)
+		}
+	case float32:
+		if vb, ok := b.(float32); ok {
+			return cmp.Compare(va, vb)
+		}
+	case bool:
+		if vb, ok := b.(bool); ok {
+			switch {
+			case va == vb:
+				return 0
+			case !va:
+				return -1
+			}
+			return 1
+		}
+	case time.Time:
+		if vb, ok := b.(time.Time); ok {
+			return va.Compare(vb)
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
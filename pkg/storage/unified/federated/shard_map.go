@@ -0,0 +1,55 @@
+package federated
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// ShardMap holds the namespaces moved away from their static or hashed shard, and the namespace moves
+type ShardMap struct {
+	// The shard of each moved namespace
+	Moved map[string]string `json:"moved,omitempty"`
+
+	// The last move of each namespace, running or not
+	Moves map[string]*NamespaceMove `json:"moves,omitempty"`
+
+	// The clients of the shards, with the last time they read the shard map.
+	// The moves wait for every client to reject the writes of the namespace before the last copy.
+	Clients map[string]time.Time `json:"clients,omitempty"`
+}
+
+// ShardMapStore persists the shard map, it is shared by every client of the shards
+type ShardMapStore interface {
+	// Get reads the current shard map
+	Get(ctx context.Context) (*ShardMap, error)
+
+	// Update reads the shard map, applies the change and saves it.
+	// The updates of all the clients are serialized, the change is not saved when it returns an error.
+	Update(ctx context.Context, fn func(m *ShardMap) error) (*ShardMap, error)
+}
+
+// DecodeShardMap reads a shard map saved with EncodeShardMap, an empty value is an empty map
+func DecodeShardMap(value string) (*ShardMap, error) {
+	m := &ShardMap{}
+	if value != "" {
+		if err := json.Unmarshal([]byte(value), m); err != nil {
+			return nil, err
+		}
+	}
+	if m.Moved == nil {
+		m.Moved = make(map[string]string)
+	}
+	if m.Moves == nil {
+		m.Moves = make(map[string]*NamespaceMove)
+	}
+	if m.Clients == nil {
+		m.Clients = make(map[string]time.Time)
+	}
+	return m, nil
+}
+
+func EncodeShardMap(m *ShardMap) (string, error) {
+	value, err := json.Marshal(m)
+	return string(value), err
+}
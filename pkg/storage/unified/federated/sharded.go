@@ -0,0 +1,633 @@
+package federated
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// ShardedClientOptions configures a client routing the requests to several storage servers
+type ShardedClientOptions struct {
+	// The client of each shard, by shard name
+	Shards map[string]resource.ResourceClient
+
+	// The shard of each namespace. The namespaces that are not listed
+	// are assigned to a shard with a hash of the namespace
+	Namespaces map[string]string
+
+	// Persists the namespaces moved between shards, it must be shared by every client of the shards.
+	// The namespaces can not be moved without it.
+	Store ShardMapStore
+
+	// How often the shard map is read again from the store, defaults to 10s
+	RefreshInterval time.Duration
+}
+
+// ShardedClient routes the requests by namespace to the storage server holding the namespace.
+// The requests without a namespace (cluster wide list, stats, health) are sent to every shard and merged.
+type ShardedClient struct {
+	shards  map[string]resource.ResourceClient
+	names   []string // sorted, the order used to merge the results
+	static  map[string]string
+	store   ShardMapStore
+	refresh time.Duration
+	log     *slog.Logger
+
+	// identifies the client running a namespace move
+	id string
+
+	mu sync.RWMutex
+	// the namespaces moved, they win over the static map
+	moved map[string]string
+	// the last move of each namespace, as read from the store
+	moves map[string]*NamespaceMove
+	// the writes in flight in each namespace, a frozen namespace is acknowledged once they completed
+	writes map[string]*sync.WaitGroup
+
+	// for testing
+	now func() time.Time
+}
+
+var (
+	_ resource.ResourceClient = (*ShardedClient)(nil)
+	_ NamespaceMover          = (*ShardedClient)(nil)
+)
+
+func NewShardedClient(opts ShardedClientOptions) (*ShardedClient, error) {
+	if len(opts.Shards) == 0 {
+		return nil, fmt.Errorf("missing shards")
+	}
+	for ns, shard := range opts.Namespaces {
+		if _, ok := opts.Shards[shard]; !ok {
+			return nil, fmt.Errorf("namespace %s is assigned to unknown shard %s", ns, shard)
+		}
+	}
+	names := make([]string, 0, len(opts.Shards))
+	for name := range opts.Shards {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = 10 * time.Second
+	}
+
+	c := &ShardedClient{
+		shards:  opts.Shards,
+		names:   names,
+		static:  opts.Namespaces,
+		store:   opts.Store,
+		refresh: opts.RefreshInterval,
+		log:     slog.Default().With("logger", "sharded-client"),
+		id:      uuid.NewString(),
+		moved:   make(map[string]string),
+		moves:   make(map[string]*NamespaceMove),
+		writes:  make(map[string]*sync.WaitGroup),
+		now:     time.Now,
+	}
+	if c.store != nil {
+		m, err := c.heartbeat(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("read shard map: %w", err)
+		}
+		c.apply(m)
+	}
+	return c, nil
+}
+
+// Run reads the shard map again every refresh interval, and resumes the namespace moves of the clients that stopped
+func (c *ShardedClient) Run(ctx context.Context) {
+	if c.store == nil {
+		return
+	}
+	ticker := time.NewTicker(c.refresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		m, err := c.heartbeat(ctx)
+		if err != nil {
+			c.log.Warn("failed to read the shard map", "err", err)
+			continue
+		}
+		c.apply(m)
+		for namespace, move := range m.Moves {
+			if move.State.Running() && c.now().Sub(move.Updated) >= c.moveStaleAfter() {
+				go c.runMove(ctx, namespace, true)
+			}
+		}
+	}
+}
+
+// heartbeat saves the client in the shard map, and acknowledges the frozen namespaces it rejects the writes of.
+// A namespace is acknowledged once the writes started before it was frozen completed.
+// The clients that did not save the shard map for a while are removed, they are considered stopped.
+func (c *ShardedClient) heartbeat(ctx context.Context) (*ShardMap, error) {
+	frozen := c.frozenNamespaces()
+	for _, namespace := range frozen {
+		c.waitWrites(namespace)
+	}
+	return c.store.Update(ctx, func(m *ShardMap) error {
+		now := c.now()
+		for id, seen := range m.Clients {
+			if now.Sub(seen) >= c.moveStaleAfter() {
+				delete(m.Clients, id)
+			}
+		}
+		m.Clients[c.id] = now
+		for _, namespace := range frozen {
+			move := m.Moves[namespace]
+			if move != nil && move.State == NamespaceMoveFrozen && !slices.Contains(move.Acked, c.id) {
+				move.Acked = append(move.Acked, c.id)
+			}
+		}
+		return nil
+	})
+}
+
+// A move that was not saved for this long is resumed by another client
+func (c *ShardedClient) moveStaleAfter() time.Duration {
+	return 3 * c.refresh
+}
+
+// apply replaces the moves with the ones read from the store
+func (c *ShardedClient) apply(m *ShardMap) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.moved = m.Moved
+	c.moves = m.Moves
+}
+
+// ShardFor returns the name of the shard holding the namespace
+func (c *ShardedClient) ShardFor(namespace string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.shardForLocked(namespace)
+}
+
+func (c *ShardedClient) shardForLocked(namespace string) string {
+	return c.shardIn(c.moved, namespace)
+}
+
+func (c *ShardedClient) shardIn(moved map[string]string, namespace string) string {
+	if shard, ok := moved[namespace]; ok {
+		if _, known := c.shards[shard]; known {
+			return shard
+		}
+	}
+	if shard, ok := c.static[namespace]; ok {
+		return shard
+	}
+	return hashShard(c.names, namespace)
+}
+
+// hashShard picks the shard with the highest hash of shard and namespace (rendezvous hashing),
+// so adding a shard only moves the namespaces assigned to the new shard
+func hashShard(names []string, namespace string) string {
+	var best string
+	var bestScore uint64
+	for _, name := range names {
+		sum := sha256.Sum256([]byte(name + "\x00" + namespace))
+		if score := binary.BigEndian.Uint64(sum[:8]); best == "" || score > bestScore {
+			best, bestScore = name, score
+		}
+	}
+	return best
+}
+
+// frozenNamespaces returns the namespaces the client rejects the writes of
+func (c *ShardedClient) frozenNamespaces() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var namespaces []string
+	for namespace, move := range c.moves {
+		if move.State == NamespaceMoveFrozen {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+// waitWrites waits for the writes in flight in the namespace
+func (c *ShardedClient) waitWrites(namespace string) {
+	c.mu.RLock()
+	wg := c.writes[namespace]
+	c.mu.RUnlock()
+	if wg != nil {
+		wg.Wait()
+	}
+}
+
+func (c *ShardedClient) moveState(namespace string) NamespaceMoveState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if move := c.moves[namespace]; move != nil {
+		return move.State
+	}
+	return ""
+}
+
+func (c *ShardedClient) client(namespace string) resource.ResourceClient {
+	return c.shards[c.ShardFor(namespace)]
+}
+
+// writer returns the client to write in the namespace, and the function to call once the write completed.
+// The writes are rejected while a move copies the last changes of the namespace.
+func (c *ShardedClient) writer(namespace string) (resource.ResourceClient, func(), *resourcepb.ErrorResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if move := c.moves[namespace]; move != nil && move.State == NamespaceMoveFrozen {
+		return nil, nil, &resourcepb.ErrorResult{
+			Code:    http.StatusServiceUnavailable,
+			Reason:  "NamespaceMoving",
+			Message: fmt.Sprintf("namespace %s is being moved to another storage server, retry later", namespace),
+		}
+	}
+	wg := c.writes[namespace]
+	if wg == nil {
+		wg = &sync.WaitGroup{}
+		c.writes[namespace] = wg
+	}
+	wg.Add(1)
+	return c.shards[c.shardForLocked(namespace)], wg.Done, nil
+}
+
+func (c *ShardedClient) Read(ctx context.Context, in *resourcepb.ReadRequest, opts ...grpc.CallOption) (*resourcepb.ReadResponse, error) {
+	return c.client(in.Key.GetNamespace()).Read(ctx, in, opts...)
+}
+
+func (c *ShardedClient) Create(ctx context.Context, in *resourcepb.CreateRequest, opts ...grpc.CallOption) (*resourcepb.CreateResponse, error) {
+	client, done, errResult := c.writer(in.Key.GetNamespace())
+	if errResult != nil {
+		return &resourcepb.CreateResponse{Error: errResult}, nil
+	}
+	defer done()
+	return client.Create(ctx, in, opts...)
+}
+
+func (c *ShardedClient) Update(ctx context.Context, in *resourcepb.UpdateRequest, opts ...grpc.CallOption) (*resourcepb.UpdateResponse, error) {
+	client, done, errResult := c.writer(in.Key.GetNamespace())
+	if errResult != nil {
+		return &resourcepb.UpdateResponse{Error: errResult}, nil
+	}
+	defer done()
+	return client.Update(ctx, in, opts...)
+}
+
+func (c *ShardedClient) Delete(ctx context.Context, in *resourcepb.DeleteRequest, opts ...grpc.CallOption) (*resourcepb.DeleteResponse, error) {
+	client, done, errResult := c.writer(in.Key.GetNamespace())
+	if errResult != nil {
+		return &resourcepb.DeleteResponse{Error: errResult}, nil
+	}
+	defer done()
+	return client.Delete(ctx, in, opts...)
+}
+
+// Watch is routed to the shard of the namespace.
+// A watch without namespace watches every shard, and receives the events as they come.
+// The resource versions are the ones of each shard, so such a watch can not be resumed from a resource version.
+// NOTE: the watchers started before a namespace is moved keep watching the previous shard
+func (c *ShardedClient) Watch(ctx context.Context, in *resourcepb.WatchRequest, opts ...grpc.CallOption) (resourcepb.ResourceStore_WatchClient, error) {
+	if ns := in.Options.GetKey().GetNamespace(); ns != "" {
+		return c.client(ns).Watch(ctx, in, opts...)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	w := &mergedWatch{
+		ctx:    ctx,
+		cancel: cancel,
+		events: make(chan *resourcepb.WatchEvent),
+		errs:   make(chan error, len(c.names)),
+	}
+	streams := make(map[string]resourcepb.ResourceStore_WatchClient, len(c.names))
+	for _, shard := range c.names {
+		stream, err := c.shards[shard].Watch(ctx, in, opts...)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("watch shard %s: %w", shard, err)
+		}
+		streams[shard] = stream
+	}
+	w.ClientStream = streams[c.names[0]]
+	for shard, stream := range streams {
+		go w.receive(stream, func(namespace string) bool {
+			return c.ShardFor(namespace) == shard
+		})
+	}
+	return w, nil
+}
+
+// The continue token of a list across shards
+type shardedListToken struct {
+	Shard string `json:"shard"`
+	Token string `json:"token,omitempty"`
+}
+
+// List is routed to the shard of the namespace.
+// A list without namespace reads each shard in turn, the continue token holds the current shard.
+func (c *ShardedClient) List(ctx context.Context, in *resourcepb.ListRequest, opts ...grpc.CallOption) (*resourcepb.ListResponse, error) {
+	if ns := in.Options.GetKey().GetNamespace(); ns != "" {
+		return c.client(ns).List(ctx, in, opts...)
+	}
+
+	token := shardedListToken{Shard: c.names[0]}
+	if in.NextPageToken != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(in.NextPageToken)
+		if err == nil {
+			err = json.Unmarshal(raw, &token)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid continue token: %w", err)
+		}
+	}
+	idx := slices.Index(c.names, token.Shard)
+	if idx < 0 {
+		return nil, fmt.Errorf("invalid continue token: unknown shard %s", token.Shard)
+	}
+
+	req := proto.Clone(in).(*resourcepb.ListRequest)
+	req.NextPageToken = token.Token
+	rsp, err := c.shards[token.Shard].List(ctx, req, opts...)
+	if err != nil || rsp.Error != nil {
+		return rsp, err
+	}
+
+	// The namespaces copied by a move stay on the previous shard, only keep the ones this shard holds
+	rsp.Items = slices.DeleteFunc(rsp.Items, func(item *resourcepb.ResourceWrapper) bool {
+		return c.ShardFor(readObjectMeta(item.Value).Namespace) != token.Shard
+	})
+
+	// Continue on the same shard, or start the next one
+	next := shardedListToken{Shard: token.Shard, Token: rsp.NextPageToken}
+	if rsp.NextPageToken == "" {
+		if idx+1 >= len(c.names) {
+			return rsp, nil
+		}
+		next = shardedListToken{Shard: c.names[idx+1]}
+	}
+	raw, err := json.Marshal(next)
+	if err != nil {
+		return nil, err
+	}
+	rsp.NextPageToken = base64.RawURLEncoding.EncodeToString(raw)
+	rsp.RemainingItemCount = 0 // unknown across shards
+	return rsp, nil
+}
+
+// Search is routed to the shards of the searched namespaces, and the results are merged
+func (c *ShardedClient) Search(ctx context.Context, in *resourcepb.ResourceSearchRequest, opts ...grpc.CallOption) (*resourcepb.ResourceSearchResponse, error) {
+	requests := c.splitSearch(in)
+	if len(requests) == 1 {
+		for shard, req := range requests {
+			return c.shards[shard].Search(ctx, req, opts...)
+		}
+	}
+
+	rsps := make([]*resourcepb.ResourceSearchResponse, len(c.names))
+	err := c.forEachShard(func(i int, shard string) error {
+		req, ok := requests[shard]
+		if !ok {
+			return nil
+		}
+		rsp, err := c.shards[shard].Search(ctx, req, opts...)
+		if err != nil {
+			return fmt.Errorf("search shard %s: %w", shard, err)
+		}
+		if rsp.Results != nil {
+			count := len(rsp.Results.Rows)
+			rsp.Results.Rows = slices.DeleteFunc(rsp.Results.Rows, func(row *resourcepb.ResourceTableRow) bool {
+				return c.ShardFor(row.Key.GetNamespace()) != shard
+			})
+			rsp.TotalHits -= int64(count - len(rsp.Results.Rows))
+		}
+		rsps[i] = rsp
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mergeSearchResults(in, slices.DeleteFunc(rsps, func(r *resourcepb.ResourceSearchResponse) bool { return r == nil }))
+}
+
+// splitSearch returns the request to send to each shard.
+// When several shards are queried, each returns the first offset+limit results so the merged page is complete.
+func (c *ShardedClient) splitSearch(in *resourcepb.ResourceSearchRequest) map[string]*resourcepb.ResourceSearchRequest {
+	keys := append([]*resourcepb.ResourceKey{in.Options.GetKey()}, in.Federated...)
+	byShard := make(map[string][]*resourcepb.ResourceKey)
+	for _, key := range keys {
+		if key.GetNamespace() == "" {
+			for _, shard := range c.names {
+				byShard[shard] = append(byShard[shard], key)
+			}
+			continue
+		}
+		shard := c.ShardFor(key.Namespace)
+		byShard[shard] = append(byShard[shard], key)
+	}
+
+	requests := make(map[string]*resourcepb.ResourceSearchRequest, len(byShard))
+	for shard, keys := range byShard {
+		if len(byShard) == 1 {
+			requests[shard] = in
+			break
+		}
+		req := proto.Clone(in).(*resourcepb.ResourceSearchRequest)
+		if req.Options == nil {
+			req.Options = &resourcepb.ListOptions{}
+		}
+		req.Options.Key = keys[0]
+		req.Federated = keys[1:]
+		req.Limit = in.Offset + in.Limit
+		req.Offset = 0
+		requests[shard] = req
+	}
+	return requests
+}
+
+// GetStats is routed to the shard of the namespace, the stats without namespace are summed across shards
+func (c *ShardedClient) GetStats(ctx context.Context, in *resourcepb.ResourceStatsRequest, opts ...grpc.CallOption) (*resourcepb.ResourceStatsResponse, error) {
+	if in.Namespace != "" {
+		return c.client(in.Namespace).GetStats(ctx, in, opts...)
+	}
+
+	rsps := make([]*resourcepb.ResourceStatsResponse, len(c.names))
+	err := c.forEachShard(func(i int, shard string) error {
+		rsp, err := c.shards[shard].GetStats(ctx, in, opts...)
+		if err != nil {
+			return fmt.Errorf("stats of shard %s: %w", shard, err)
+		}
+		rsps[i] = rsp
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &resourcepb.ResourceStatsResponse{}
+	counts := make(map[string]*resourcepb.ResourceStatsResponse_Stats)
+	for _, rsp := range rsps {
+		if rsp.Error != nil {
+			return rsp, nil
+		}
+		for _, s := range rsp.Stats {
+			id := s.Group + "/" + s.Resource
+			if current, ok := counts[id]; ok {
+				current.Count += s.Count
+				continue
+			}
+			stat := &resourcepb.ResourceStatsResponse_Stats{Group: s.Group, Resource: s.Resource, Count: s.Count, Retention: s.Retention}
+			counts[id] = stat
+			merged.Stats = append(merged.Stats, stat)
+		}
+	}
+	return merged, nil
+}
+
+func (c *ShardedClient) CountManagedObjects(ctx context.Context, in *resourcepb.CountManagedObjectsRequest, opts ...grpc.CallOption) (*resourcepb.CountManagedObjectsResponse, error) {
+	return c.client(in.Namespace).CountManagedObjects(ctx, in, opts...)
+}
+
+func (c *ShardedClient) ListManagedObjects(ctx context.Context, in *resourcepb.ListManagedObjectsRequest, opts ...grpc.CallOption) (*resourcepb.ListManagedObjectsResponse, error) {
+	return c.client(in.Namespace).ListManagedObjects(ctx, in, opts...)
+}
+
+// BulkProcess is routed with the namespace of the collections in the request metadata, they must all be on the same shard
+func (c *ShardedClient) BulkProcess(ctx context.Context, opts ...grpc.CallOption) (resourcepb.BulkStore_BulkProcessClient, error) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	settings, err := resource.NewBulkSettings(md)
+	if err != nil {
+		return nil, err
+	}
+	if len(settings.Collection) == 0 {
+		return nil, fmt.Errorf("missing collection in bulk request")
+	}
+
+	// The stream outlives this call, so it is rejected during the whole move instead of only the final copy
+	namespace := settings.Collection[0].Namespace
+	for _, key := range settings.Collection {
+		if c.ShardFor(key.Namespace) != c.ShardFor(namespace) {
+			return nil, fmt.Errorf("the bulk collections are stored on different shards")
+		}
+		if c.moveState(key.Namespace).Running() {
+			return nil, fmt.Errorf("namespace %s is being moved to another storage server, retry later", key.Namespace)
+		}
+	}
+	return c.client(namespace).BulkProcess(ctx, opts...)
+}
+
+func (c *ShardedClient) Restore(ctx context.Context, in *resourcepb.RestoreRequest, opts ...grpc.CallOption) (*resourcepb.RestoreResponse, error) {
+	client, done, errResult := c.writer(in.Namespace)
+	if errResult != nil {
+		return &resourcepb.RestoreResponse{Error: errResult}, nil
+	}
+	defer done()
+	return client.Restore(ctx, in, opts...)
+}
+
+func (c *ShardedClient) PutBlob(ctx context.Context, in *resourcepb.PutBlobRequest, opts ...grpc.CallOption) (*resourcepb.PutBlobResponse, error) {
+	client, done, errResult := c.writer(in.Resource.GetNamespace())
+	if errResult != nil {
+		return &resourcepb.PutBlobResponse{Error: errResult}, nil
+	}
+	defer done()
+	return client.PutBlob(ctx, in, opts...)
+}
+
+func (c *ShardedClient) GetBlob(ctx context.Context, in *resourcepb.GetBlobRequest, opts ...grpc.CallOption) (*resourcepb.GetBlobResponse, error) {
+	return c.client(in.Resource.GetNamespace()).GetBlob(ctx, in, opts...)
+}
+
+func (c *ShardedClient) ListTrash(ctx context.Context, in *resourcepb.ListTrashRequest, opts ...grpc.CallOption) (*resourcepb.ListTrashResponse, error) {
+	return c.client(in.Namespace).ListTrash(ctx, in, opts...)
+}
+
+func (c *ShardedClient) RestoreFromTrash(ctx context.Context, in *resourcepb.RestoreFromTrashRequest, opts ...grpc.CallOption) (*resourcepb.RestoreFromTrashResponse, error) {
+	client, done, errResult := c.writer(in.Key.GetNamespace())
+	if errResult != nil {
+		return &resourcepb.RestoreFromTrashResponse{Error: errResult}, nil
+	}
+	defer done()
+	return client.RestoreFromTrash(ctx, in, opts...)
+}
+
+func (c *ShardedClient) PurgeTrash(ctx context.Context, in *resourcepb.PurgeTrashRequest, opts ...grpc.CallOption) (*resourcepb.PurgeTrashResponse, error) {
+	client, done, errResult := c.writer(in.Namespace)
+	if errResult != nil {
+		return &resourcepb.PurgeTrashResponse{Error: errResult}, nil
+	}
+	defer done()
+	return client.PurgeTrash(ctx, in, opts...)
+}
+
+// IsHealthy is serving only when every shard is serving
+func (c *ShardedClient) IsHealthy(ctx context.Context, in *resourcepb.HealthCheckRequest, opts ...grpc.CallOption) (*resourcepb.HealthCheckResponse, error) {
+	rsp := &resourcepb.HealthCheckResponse{Status: resourcepb.HealthCheckResponse_SERVING}
+	for _, shard := range c.names {
+		s, err := c.shards[shard].IsHealthy(ctx, in, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("health of shard %s: %w", shard, err)
+		}
+		if s.Status != resourcepb.HealthCheckResponse_SERVING {
+			return s, nil
+		}
+	}
+	return rsp, nil
+}
+
+// ListOnlineMigrations lists the migrations of every shard
+func (c *ShardedClient) ListOnlineMigrations(ctx context.Context, in *resourcepb.ListOnlineMigrationsRequest, opts ...grpc.CallOption) (*resourcepb.ListOnlineMigrationsResponse, error) {
+	rsp := &resourcepb.ListOnlineMigrationsResponse{}
+	for _, shard := range c.names {
+		s, err := c.shards[shard].ListOnlineMigrations(ctx, in, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("online migrations of shard %s: %w", shard, err)
+		}
+		if s.Error != nil {
+			return s, nil
+		}
+		for _, m := range s.Migrations {
+			m.Shard = shard
+			rsp.Migrations = append(rsp.Migrations, m)
+		}
+	}
+	return rsp, nil
+}
+
+// forEachShard calls fn for every shard in parallel, and returns the first error
+func (c *ShardedClient) forEachShard(fn func(i int, shard string) error) error {
+	errs := make([]error, len(c.names))
+	wg := sync.WaitGroup{}
+	for i, shard := range c.names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = fn(i, shard)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
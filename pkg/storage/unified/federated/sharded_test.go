@@ -0,0 +1,693 @@
+package federated
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// fakeShard keeps the objects of every namespace in memory
+type fakeShard struct {
+	resource.ResourceClient
+
+	mu      sync.Mutex
+	rv      int64
+	objects map[string][]byte // {namespace}/{group}/{resource}/{name}
+	trash   map[string]*fakeDeleted
+	blobs   map[string]*resourcepb.GetBlobResponse
+	search  *resourcepb.ResourceSearchResponse
+	watch   chan *resourcepb.WatchEvent
+
+	// called after each list, for testing
+	onList func()
+}
+
+// fakeDeleted is a deleted object, with the value read before its deletion
+type fakeDeleted struct {
+	rv       int64
+	previous []byte
+	marker   []byte
+}
+
+func newFakeShard() *fakeShard {
+	return &fakeShard{
+		objects: make(map[string][]byte),
+		trash:   make(map[string]*fakeDeleted),
+		blobs:   make(map[string]*resourcepb.GetBlobResponse),
+		watch:   make(chan *resourcepb.WatchEvent),
+	}
+}
+
+func fakeObject(namespace, name string) []byte {
+	return []byte(fmt.Sprintf(`{"metadata":{"namespace":%q,"name":%q}}`, namespace, name))
+}
+
+func objectID(key *resourcepb.ResourceKey) string {
+	return key.Namespace + "/" + key.Group + "/" + key.Resource + "/" + key.Name
+}
+
+func (s *fakeShard) Create(_ context.Context, in *resourcepb.CreateRequest, _ ...grpc.CallOption) (*resourcepb.CreateResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rv++
+	s.objects[objectID(in.Key)] = in.Value
+	return &resourcepb.CreateResponse{ResourceVersion: s.rv}, nil
+}
+
+func (s *fakeShard) Read(_ context.Context, in *resourcepb.ReadRequest, _ ...grpc.CallOption) (*resourcepb.ReadResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if deleted, ok := s.trash[objectID(in.Key)]; ok && in.ResourceVersion == deleted.rv-1 {
+		return &resourcepb.ReadResponse{Value: deleted.previous, ResourceVersion: in.ResourceVersion}, nil
+	}
+	value, ok := s.objects[objectID(in.Key)]
+	if !ok {
+		return &resourcepb.ReadResponse{Error: &resourcepb.ErrorResult{Code: http.StatusNotFound}}, nil
+	}
+	return &resourcepb.ReadResponse{Value: value}, nil
+}
+
+// List pages with the index of the next object as token
+func (s *fakeShard) List(_ context.Context, in *resourcepb.ListRequest, _ ...grpc.CallOption) (*resourcepb.ListResponse, error) {
+	if s.onList != nil {
+		defer s.onList()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := in.Options.Key
+	objects := s.objects
+	if in.Source == resourcepb.ListRequest_TRASH {
+		objects = make(map[string][]byte, len(s.trash))
+		for id, deleted := range s.trash {
+			objects[id] = deleted.marker
+		}
+	}
+	var ids []string
+	for id := range objects {
+		prefix := key.Namespace + "/" + key.Group + "/" + key.Resource + "/"
+		if key.Namespace == "" || strings.HasPrefix(id, prefix) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	start := 0
+	if in.NextPageToken != "" {
+		start, _ = strconv.Atoi(in.NextPageToken)
+	}
+	end := len(ids)
+	if in.Limit > 0 {
+		end = min(end, start+int(in.Limit))
+	}
+	rsp := &resourcepb.ListResponse{ResourceVersion: s.rv}
+	for _, id := range ids[start:end] {
+		item := &resourcepb.ResourceWrapper{Value: objects[id]}
+		if deleted, ok := s.trash[id]; ok && in.Source == resourcepb.ListRequest_TRASH {
+			item.ResourceVersion = deleted.rv
+		}
+		rsp.Items = append(rsp.Items, item)
+	}
+	if end < len(ids) {
+		rsp.NextPageToken = strconv.Itoa(end)
+	}
+	return rsp, nil
+}
+
+func (s *fakeShard) GetStats(_ context.Context, in *resourcepb.ResourceStatsRequest, _ ...grpc.CallOption) (*resourcepb.ResourceStatsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts := make(map[string]int64)
+	for id := range s.objects {
+		parts := strings.Split(id, "/")
+		if in.Namespace == "" || in.Namespace == parts[0] {
+			counts[parts[1]+"/"+parts[2]]++
+		}
+	}
+	rsp := &resourcepb.ResourceStatsResponse{}
+	for _, gr := range slices.Sorted(maps.Keys(counts)) {
+		group, res, _ := strings.Cut(gr, "/")
+		rsp.Stats = append(rsp.Stats, &resourcepb.ResourceStatsResponse_Stats{Group: group, Resource: res, Count: counts[gr]})
+	}
+	return rsp, nil
+}
+
+func (s *fakeShard) Search(_ context.Context, _ *resourcepb.ResourceSearchRequest, _ ...grpc.CallOption) (*resourcepb.ResourceSearchResponse, error) {
+	return s.search, nil
+}
+
+func (s *fakeShard) PutBlob(_ context.Context, in *resourcepb.PutBlobRequest, _ ...grpc.CallOption) (*resourcepb.PutBlobResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	uid := fmt.Sprintf("blob-%d", len(s.blobs)+1)
+	s.blobs[uid] = &resourcepb.GetBlobResponse{ContentType: in.ContentType, Value: in.Value}
+	return &resourcepb.PutBlobResponse{Uid: uid, Size: int64(len(in.Value)), MimeType: in.ContentType}, nil
+}
+
+func (s *fakeShard) GetBlob(_ context.Context, in *resourcepb.GetBlobRequest, _ ...grpc.CallOption) (*resourcepb.GetBlobResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	blob, ok := s.blobs[in.Uid]
+	if !ok {
+		return &resourcepb.GetBlobResponse{Error: &resourcepb.ErrorResult{Code: http.StatusNotFound}}, nil
+	}
+	return blob, nil
+}
+
+func (s *fakeShard) BulkProcess(ctx context.Context, _ ...grpc.CallOption) (resourcepb.BulkStore_BulkProcessClient, error) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	settings, err := resource.NewBulkSettings(md)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeBulkStream{shard: s, settings: settings}, nil
+}
+
+type fakeBulkStream struct {
+	grpc.ClientStream
+
+	shard    *fakeShard
+	settings resource.BulkSettings
+	requests []*resourcepb.BulkRequest
+}
+
+func (b *fakeBulkStream) Send(req *resourcepb.BulkRequest) error {
+	b.requests = append(b.requests, req)
+	return nil
+}
+
+func (b *fakeBulkStream) CloseAndRecv() (*resourcepb.BulkResponse, error) {
+	b.shard.mu.Lock()
+	defer b.shard.mu.Unlock()
+	if b.settings.RebuildCollection {
+		for _, key := range b.settings.Collection {
+			prefix := key.Namespace + "/" + key.Group + "/" + key.Resource + "/"
+			for id := range b.shard.objects {
+				if strings.HasPrefix(id, prefix) {
+					delete(b.shard.objects, id)
+				}
+			}
+			for id := range b.shard.trash {
+				if strings.HasPrefix(id, prefix) {
+					delete(b.shard.trash, id)
+				}
+			}
+		}
+	}
+	for _, req := range b.requests {
+		id := objectID(req.Key)
+		b.shard.rv++
+		if req.Action == resourcepb.BulkRequest_DELETED {
+			b.shard.trash[id] = &fakeDeleted{rv: b.shard.rv, previous: b.shard.objects[id], marker: req.Value}
+			delete(b.shard.objects, id)
+			continue
+		}
+		b.shard.objects[id] = req.Value
+	}
+	return &resourcepb.BulkResponse{Processed: int64(len(b.requests))}, nil
+}
+
+// Watch sends the events written in the watch channel
+func (s *fakeShard) Watch(ctx context.Context, _ *resourcepb.WatchRequest, _ ...grpc.CallOption) (resourcepb.ResourceStore_WatchClient, error) {
+	return &fakeWatchStream{ctx: ctx, events: s.watch}, nil
+}
+
+type fakeWatchStream struct {
+	grpc.ClientStream
+
+	ctx    context.Context
+	events chan *resourcepb.WatchEvent
+}
+
+func (w *fakeWatchStream) Recv() (*resourcepb.WatchEvent, error) {
+	select {
+	case event, ok := <-w.events:
+		if !ok {
+			return nil, io.EOF
+		}
+		return event, nil
+	case <-w.ctx.Done():
+		return nil, w.ctx.Err()
+	}
+}
+
+// memShardMapStore keeps the encoded shard map in memory, like a shared database
+type memShardMapStore struct {
+	mu    sync.Mutex
+	value string
+}
+
+func (s *memShardMapStore) Get(_ context.Context) (*ShardMap, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return DecodeShardMap(s.value)
+}
+
+func (s *memShardMapStore) Update(_ context.Context, fn func(m *ShardMap) error) (*ShardMap, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, err := DecodeShardMap(s.value)
+	if err != nil {
+		return nil, err
+	}
+	if err = fn(m); err != nil {
+		return nil, err
+	}
+	if s.value, err = EncodeShardMap(m); err != nil {
+		return nil, err
+	}
+	return DecodeShardMap(s.value)
+}
+
+func setupShardedClient(t *testing.T, namespaces map[string]string) (*ShardedClient, map[string]*fakeShard) {
+	t.Helper()
+	fakes := map[string]*fakeShard{"a": newFakeShard(), "b": newFakeShard()}
+	c, err := NewShardedClient(ShardedClientOptions{
+		Shards:          map[string]resource.ResourceClient{"a": fakes["a"], "b": fakes["b"]},
+		Namespaces:      namespaces,
+		Store:           &memShardMapStore{},
+		RefreshInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	return c, fakes
+}
+
+// waitMove waits until the move of the namespace is no longer running
+func waitMove(t *testing.T, c *ShardedClient, namespace string) *NamespaceMove {
+	t.Helper()
+	var move *NamespaceMove
+	require.Eventually(t, func() bool {
+		moves, err := c.ListNamespaceMoves(context.Background())
+		require.NoError(t, err)
+		for _, m := range moves {
+			if m.Namespace == namespace && !m.State.Running() {
+				move = m
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+	return move
+}
+
+func createObject(t *testing.T, c resource.ResourceClient, namespace, name string) {
+	t.Helper()
+	rsp, err := c.Create(context.Background(), &resourcepb.CreateRequest{
+		Key:   &resourcepb.ResourceKey{Namespace: namespace, Group: "g", Resource: "r", Name: name},
+		Value: fakeObject(namespace, name),
+	})
+	require.NoError(t, err)
+	require.Nil(t, rsp.Error)
+}
+
+func TestShardedClient_routing(t *testing.T) {
+	c, fakes := setupShardedClient(t, map[string]string{"stacks-1": "a", "stacks-2": "b"})
+
+	require.Equal(t, "a", c.ShardFor("stacks-1"))
+	require.Equal(t, "b", c.ShardFor("stacks-2"))
+
+	// The other namespaces are spread by hash, always on the same shard
+	counts := map[string]int{}
+	for i := range 100 {
+		ns := fmt.Sprintf("org-%d", i)
+		shard := c.ShardFor(ns)
+		require.Equal(t, shard, c.ShardFor(ns))
+		counts[shard]++
+	}
+	require.Greater(t, counts["a"], 20)
+	require.Greater(t, counts["b"], 20)
+
+	createObject(t, c, "stacks-1", "x")
+	createObject(t, c, "stacks-2", "y")
+	require.Len(t, fakes["a"].objects, 1)
+	require.Len(t, fakes["b"].objects, 1)
+
+	_, err := NewShardedClient(ShardedClientOptions{
+		Shards:     map[string]resource.ResourceClient{"a": fakes["a"]},
+		Namespaces: map[string]string{"stacks-1": "missing"},
+	})
+	require.Error(t, err)
+}
+
+func TestShardedClient_List(t *testing.T) {
+	ctx := context.Background()
+	c, _ := setupShardedClient(t, map[string]string{"stacks-1": "a", "stacks-2": "b"})
+	for i := range 3 {
+		createObject(t, c, "stacks-1", fmt.Sprintf("a%d", i))
+		createObject(t, c, "stacks-2", fmt.Sprintf("b%d", i))
+	}
+
+	// A list in a namespace only reads its shard
+	rsp, err := c.List(ctx, &resourcepb.ListRequest{
+		Options: &resourcepb.ListOptions{Key: &resourcepb.ResourceKey{Namespace: "stacks-2", Group: "g", Resource: "r"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, rsp.Items, 3)
+	require.Empty(t, rsp.NextPageToken)
+
+	// A list without namespace pages through every shard
+	var names []string
+	req := &resourcepb.ListRequest{
+		Limit:   2,
+		Options: &resourcepb.ListOptions{Key: &resourcepb.ResourceKey{Group: "g", Resource: "r"}},
+	}
+	for {
+		rsp, err := c.List(ctx, req)
+		require.NoError(t, err)
+		for _, item := range rsp.Items {
+			names = append(names, readObjectMeta(item.Value).Name)
+		}
+		if rsp.NextPageToken == "" {
+			break
+		}
+		req.NextPageToken = rsp.NextPageToken
+	}
+	require.Equal(t, []string{"a0", "a1", "a2", "b0", "b1", "b2"}, names)
+
+	req.NextPageToken = "invalid"
+	_, err = c.List(ctx, req)
+	require.Error(t, err)
+}
+
+func TestShardedClient_Search(t *testing.T) {
+	columns := []*resourcepb.ResourceTableColumnDefinition{
+		{Name: resource.SEARCH_FIELD_TITLE, Type: resourcepb.ResourceTableColumnDefinition_STRING},
+		{Name: "views", Type: resourcepb.ResourceTableColumnDefinition_INT64},
+	}
+	result := func(namespace string, rows map[string]int64) *resourcepb.ResourceSearchResponse {
+		tb, err := resource.NewTableBuilder(columns)
+		require.NoError(t, err)
+		for title, views := range rows {
+			err = tb.AddRow(&resourcepb.ResourceKey{Namespace: namespace, Group: "g", Resource: "r", Name: title}, 1, map[string]any{
+				resource.SEARCH_FIELD_TITLE: title,
+				"views":                     views,
+			})
+			require.NoError(t, err)
+		}
+		return &resourcepb.ResourceSearchResponse{
+			TotalHits: int64(len(rows)),
+			Results:   &tb.ResourceTable,
+			Facet: map[string]*resourcepb.ResourceSearchResponse_Facet{
+				"tags": {Field: "tags", Total: 1, Terms: []*resourcepb.ResourceSearchResponse_TermFacet{{Term: "prod", Count: 1}}},
+			},
+		}
+	}
+
+	c, fakes := setupShardedClient(t, map[string]string{"stacks-1": "a", "stacks-2": "b"})
+	fakes["a"].search = result("stacks-1", map[string]int64{"alpha": 10, "delta": 3})
+	fakes["b"].search = result("stacks-2", map[string]int64{"bravo": 7, "charlie": 1})
+
+	rsp, err := c.Search(context.Background(), &resourcepb.ResourceSearchRequest{
+		Options:   &resourcepb.ListOptions{Key: &resourcepb.ResourceKey{Namespace: "stacks-1", Group: "g", Resource: "r"}},
+		Federated: []*resourcepb.ResourceKey{{Namespace: "stacks-2", Group: "g", Resource: "r"}},
+		SortBy:    []*resourcepb.ResourceSearchRequest_Sort{{Field: "views", Desc: true}},
+		Limit:     3,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(4), rsp.TotalHits)
+	require.Equal(t, int64(2), rsp.Facet["tags"].Terms[0].Count)
+
+	var titles []string
+	for _, row := range rsp.Results.Rows {
+		titles = append(titles, row.Key.Name)
+	}
+	require.Equal(t, []string{"alpha", "bravo", "delta"}, titles)
+
+	// Without sort fields, the rows are sorted by title
+	rsp, err = c.Search(context.Background(), &resourcepb.ResourceSearchRequest{
+		Options:   &resourcepb.ListOptions{Key: &resourcepb.ResourceKey{Namespace: "stacks-1", Group: "g", Resource: "r"}},
+		Federated: []*resourcepb.ResourceKey{{Namespace: "stacks-2", Group: "g", Resource: "r"}},
+		Offset:    1,
+		Limit:     2,
+	})
+	require.NoError(t, err)
+	titles = nil
+	for _, row := range rsp.Results.Rows {
+		titles = append(titles, row.Key.Name)
+	}
+	require.Equal(t, []string{"bravo", "charlie"}, titles)
+}
+
+func TestShardedClient_GetStats(t *testing.T) {
+	c, _ := setupShardedClient(t, map[string]string{"stacks-1": "a", "stacks-2": "b"})
+	createObject(t, c, "stacks-1", "x")
+	createObject(t, c, "stacks-2", "y")
+	createObject(t, c, "stacks-2", "z")
+
+	rsp, err := c.GetStats(context.Background(), &resourcepb.ResourceStatsRequest{Namespace: "stacks-2"})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), rsp.Stats[0].Count)
+
+	rsp, err = c.GetStats(context.Background(), &resourcepb.ResourceStatsRequest{})
+	require.NoError(t, err)
+	require.Len(t, rsp.Stats, 1)
+	require.Equal(t, int64(3), rsp.Stats[0].Count)
+}
+
+func TestShardedClient_MoveNamespace(t *testing.T) {
+	ctx := context.Background()
+	c, fakes := setupShardedClient(t, map[string]string{"stacks-1": "a"})
+	createObject(t, c, "stacks-1", "x")
+	createObject(t, c, "stacks-1", "y")
+
+	// A write during the first copy is copied again once the writes are blocked
+	once := sync.Once{}
+	fakes["a"].onList = func() {
+		once.Do(func() { createObject(t, c, "stacks-1", "z") })
+	}
+
+	started, err := c.StartNamespaceMove(ctx, "stacks-1", "b")
+	require.NoError(t, err)
+	require.Equal(t, NamespaceMovePending, started.State)
+
+	move := waitMove(t, c, "stacks-1")
+	require.Equal(t, NamespaceMoveCompleted, move.State, move.Error)
+	require.Equal(t, "a", move.From)
+	require.Equal(t, "b", move.To)
+	require.Equal(t, int64(2), move.Copied)
+	require.Equal(t, int64(3), move.Recopied)
+	require.Equal(t, "b", c.ShardFor("stacks-1"))
+	fakes["a"].onList = nil
+
+	// The reads and writes go to the target, the source keeps its copy
+	read, err := c.Read(ctx, &resourcepb.ReadRequest{Key: &resourcepb.ResourceKey{Namespace: "stacks-1", Group: "g", Resource: "r", Name: "z"}})
+	require.NoError(t, err)
+	require.Nil(t, read.Error)
+	createObject(t, c, "stacks-1", "w")
+	require.Len(t, fakes["b"].objects, 4)
+	require.Len(t, fakes["a"].objects, 3)
+
+	// The copy left on the previous shard is not listed
+	var names []string
+	req := &resourcepb.ListRequest{
+		Options: &resourcepb.ListOptions{Key: &resourcepb.ResourceKey{Group: "g", Resource: "r"}},
+	}
+	for {
+		rsp, err := c.List(ctx, req)
+		require.NoError(t, err)
+		for _, item := range rsp.Items {
+			names = append(names, readObjectMeta(item.Value).Name)
+		}
+		if rsp.NextPageToken == "" {
+			break
+		}
+		req.NextPageToken = rsp.NextPageToken
+	}
+	require.Equal(t, []string{"w", "x", "y", "z"}, names)
+
+	_, err = c.StartNamespaceMove(ctx, "stacks-1", "b")
+	require.Error(t, err)
+
+	// Another client of the shards reads the moved namespace from the shared store
+	other, err := NewShardedClient(ShardedClientOptions{
+		Shards:     map[string]resource.ResourceClient{"a": fakes["a"], "b": fakes["b"]},
+		Namespaces: map[string]string{"stacks-1": "a"},
+		Store:      c.store,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "b", other.ShardFor("stacks-1"))
+}
+
+func TestShardedClient_MoveNamespaceBlobsAndTrash(t *testing.T) {
+	c, fakes := setupShardedClient(t, map[string]string{"stacks-1": "a"})
+	src := fakes["a"]
+	src.blobs["uid-1"] = &resourcepb.GetBlobResponse{ContentType: "text/plain", Value: []byte("hello")}
+	src.objects["stacks-1/g/r/x"] = []byte(`{"metadata":{"namespace":"stacks-1","name":"x","annotations":{"grafana.app/blob":"uid-1; size=5"}}}`)
+	src.trash["stacks-1/g/r/y"] = &fakeDeleted{
+		rv:       10,
+		previous: []byte(`{"metadata":{"namespace":"stacks-1","name":"y","annotations":{"grafana.app/blob":"uid-1; size=5"}}}`),
+		marker:   []byte(`{"metadata":{"namespace":"stacks-1","name":"y","annotations":{"grafana.app/blob":"uid-1; size=5"}}}`),
+	}
+
+	_, err := c.StartNamespaceMove(context.Background(), "stacks-1", "b")
+	require.NoError(t, err)
+	move := waitMove(t, c, "stacks-1")
+	require.Equal(t, NamespaceMoveCompleted, move.State, move.Error)
+
+	// The blob is copied once, and the objects reference the copy
+	dst := fakes["b"]
+	require.Len(t, dst.blobs, 1)
+	require.Equal(t, []byte("hello"), dst.blobs["blob-1"].Value)
+	require.Equal(t, "blob-1; size=5; mime=text/plain", readObjectMeta(dst.objects["stacks-1/g/r/x"]).Annotations["grafana.app/blob"])
+
+	// The deleted object is in the trash of the target, and can be restored from its previous version
+	deleted := dst.trash["stacks-1/g/r/y"]
+	require.NotNil(t, deleted)
+	require.Equal(t, "blob-1; size=5; mime=text/plain", readObjectMeta(deleted.previous).Annotations["grafana.app/blob"])
+	require.Equal(t, "blob-1; size=5; mime=text/plain", readObjectMeta(deleted.marker).Annotations["grafana.app/blob"])
+	require.NotContains(t, dst.objects, "stacks-1/g/r/y")
+}
+
+func TestShardedClient_MoveNamespaceWaitsForClients(t *testing.T) {
+	// The clients are only considered stopped after a few refresh intervals
+	fakes := map[string]*fakeShard{"a": newFakeShard(), "b": newFakeShard()}
+	opts := ShardedClientOptions{
+		Shards:          map[string]resource.ResourceClient{"a": fakes["a"], "b": fakes["b"]},
+		Namespaces:      map[string]string{"stacks-1": "a"},
+		Store:           &memShardMapStore{},
+		RefreshInterval: 200 * time.Millisecond,
+	}
+	c, err := NewShardedClient(opts)
+	require.NoError(t, err)
+	createObject(t, c, "stacks-1", "x")
+
+	// Another client of the shards, it does not read the shard map until it runs
+	other, err := NewShardedClient(opts)
+	require.NoError(t, err)
+
+	_, err = c.StartNamespaceMove(context.Background(), "stacks-1", "b")
+	require.NoError(t, err)
+
+	// The writes of the other client still go to the source, so the last copy waits
+	require.Eventually(t, func() bool {
+		return c.moveState("stacks-1") == NamespaceMoveFrozen
+	}, 5*time.Second, 10*time.Millisecond)
+	createObject(t, other, "stacks-1", "y")
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, NamespaceMoveFrozen, c.moveState("stacks-1"))
+
+	// The other client blocks the writes once it reads the shard map, and the move completes
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go other.Run(ctx)
+
+	move := waitMove(t, c, "stacks-1")
+	require.Equal(t, NamespaceMoveCompleted, move.State, move.Error)
+	require.ElementsMatch(t, []string{c.id, other.id}, move.Acked)
+	require.Len(t, fakes["b"].objects, 2)
+}
+
+func TestShardedClient_resumeMove(t *testing.T) {
+	c, fakes := setupShardedClient(t, map[string]string{"stacks-1": "a"})
+	createObject(t, c, "stacks-1", "x")
+
+	// A client stopped while the writes were blocked
+	m, err := c.store.Update(context.Background(), func(m *ShardMap) error {
+		m.Moves["stacks-1"] = &NamespaceMove{
+			Namespace:   "stacks-1",
+			From:        "a",
+			To:          "b",
+			State:       NamespaceMoveFrozen,
+			Collections: []*resourcepb.ResourceKey{{Namespace: "stacks-1", Group: "g", Resource: "r"}},
+			Owner:       "stopped",
+			Started:     time.Now().Add(-time.Minute),
+			Updated:     time.Now().Add(-time.Minute),
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	c.apply(m)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Run(ctx)
+
+	move := waitMove(t, c, "stacks-1")
+	require.Equal(t, NamespaceMoveCompleted, move.State, move.Error)
+	require.Equal(t, c.id, move.Owner)
+	require.Equal(t, int64(1), move.Recopied)
+	require.Equal(t, "b", c.ShardFor("stacks-1"))
+	require.Len(t, fakes["b"].objects, 1)
+}
+
+func TestShardedClient_writesDuringMove(t *testing.T) {
+	c, _ := setupShardedClient(t, map[string]string{"stacks-1": "a"})
+
+	setState := func(state NamespaceMoveState) {
+		m, err := c.store.Update(context.Background(), func(m *ShardMap) error {
+			m.Moves["stacks-1"] = &NamespaceMove{Namespace: "stacks-1", From: "a", To: "b", State: state}
+			return nil
+		})
+		require.NoError(t, err)
+		c.apply(m)
+	}
+
+	// Allowed during the first copy
+	setState(NamespaceMoveCopying)
+	createObject(t, c, "stacks-1", "x")
+
+	// Rejected during the final copy
+	setState(NamespaceMoveFrozen)
+	rsp, err := c.Create(context.Background(), &resourcepb.CreateRequest{
+		Key:   &resourcepb.ResourceKey{Namespace: "stacks-1", Group: "g", Resource: "r", Name: "y"},
+		Value: fakeObject("stacks-1", "y"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(http.StatusServiceUnavailable), rsp.Error.Code)
+
+	// The other namespaces are not blocked
+	createObject(t, c, "stacks-2", "x")
+}
+
+func TestShardedClient_Watch(t *testing.T) {
+	c, fakes := setupShardedClient(t, map[string]string{"stacks-1": "a", "stacks-2": "b"})
+	event := func(namespace, name string) *resourcepb.WatchEvent {
+		return &resourcepb.WatchEvent{
+			Type:     resourcepb.WatchEvent_ADDED,
+			Resource: &resourcepb.WatchEvent_Resource{Value: fakeObject(namespace, name)},
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w, err := c.Watch(ctx, &resourcepb.WatchRequest{
+		Options: &resourcepb.ListOptions{Key: &resourcepb.ResourceKey{Group: "g", Resource: "r"}},
+	})
+	require.NoError(t, err)
+
+	// The events of every shard are received, except the ones of the copies left by a move
+	go func() {
+		fakes["b"].watch <- event("stacks-2", "x")
+		fakes["b"].watch <- event("stacks-1", "copy")
+		fakes["a"].watch <- event("stacks-1", "y")
+	}()
+	var names []string
+	for range 2 {
+		e, err := w.Recv()
+		require.NoError(t, err)
+		names = append(names, readObjectMeta(e.Resource.Value).Name)
+	}
+	sort.Strings(names)
+	require.Equal(t, []string{"x", "y"}, names)
+
+	// The watch ends with the first shard stream that ends
+	close(fakes["a"].watch)
+	_, err = w.Recv()
+	require.ErrorIs(t, err, io.EOF)
+	require.NoError(t, w.CloseSend())
+}
@@ -0,0 +1,81 @@
+package federated
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// mergedWatch receives the events of the watches on every shard
+type mergedWatch struct {
+	// the stream of the first shard, for the headers and trailers
+	grpc.ClientStream
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	events chan *resourcepb.WatchEvent
+	errs   chan error
+}
+
+var _ resourcepb.ResourceStore_WatchClient = (*mergedWatch)(nil)
+
+// receive forwards the events of the shard stream.
+// The events of the namespaces the shard does not hold are the copies left by a move, they are dropped.
+func (w *mergedWatch) receive(stream resourcepb.ResourceStore_WatchClient, holds func(namespace string) bool) {
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			w.errs <- err
+			return
+		}
+		if event.Type != resourcepb.WatchEvent_BOOKMARK && event.Resource != nil &&
+			!holds(readObjectMeta(event.Resource.Value).Namespace) {
+			continue
+		}
+		select {
+		case w.events <- event:
+		case <-w.ctx.Done():
+			return
+		}
+	}
+}
+
+// Recv returns the next event of any shard, the watch ends with the first shard stream that ends
+func (w *mergedWatch) Recv() (*resourcepb.WatchEvent, error) {
+	select {
+	case event := <-w.events:
+		return event, nil
+	case err := <-w.errs:
+		w.cancel()
+		return nil, err
+	case <-w.ctx.Done():
+		return nil, w.ctx.Err()
+	}
+}
+
+func (w *mergedWatch) RecvMsg(m any) error {
+	event, err := w.Recv()
+	if err != nil {
+		return err
+	}
+	dst, ok := m.(*resourcepb.WatchEvent)
+	if !ok {
+		return fmt.Errorf("unexpected message %T", m)
+	}
+	proto.Merge(dst, event)
+	return nil
+}
+
+func (w *mergedWatch) Context() context.Context {
+	return w.ctx
+}
+
+// CloseSend stops the watch of every shard
+func (w *mergedWatch) CloseSend() error {
+	w.cancel()
+	return nil
+}
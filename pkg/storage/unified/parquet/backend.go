@@ -0,0 +1,538 @@
+package parquet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/grafana/grafana-app-sdk/logging"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+const (
+	tracePrefix = "parquet.backend."
+
+	// All history is written below this folder, blobs are written next to it
+	historyFolder = "__history__/"
+	formatFile    = "format.json"
+	formatVersion = `{"version":1}`
+
+	watchBufferSize = 100
+)
+
+// Backend is a resource.StorageBackend that keeps the full history in parquet files
+type Backend interface {
+	resource.StorageBackend
+	resource.BlobSupport
+
+	// Compact merges the small trailing segments into a single segment
+	Compact(ctx context.Context) error
+}
+
+type BackendOptions struct {
+	Tracer trace.Tracer
+
+	// The bucket holding all data. When nil, a bucket is opened in the local Path
+	Bucket resource.CDKBucket
+	Path   string
+
+	// Optional prefix in the bucket
+	RootFolder string
+
+	// Compaction runs in the background once this many small segments were written (default 32)
+	CompactionThreshold int
+
+	// Segments with fewer rows are merged by compaction (default 10000)
+	SegmentTargetRows int
+
+	// Number of decoded segments kept in memory (default 64)
+	SegmentCacheSize int
+}
+
+var (
+	_ Backend = (*backend)(nil)
+)
+
+// NewBackend opens (or creates) a parquet backed storage.
+// All segments in the bucket are read on startup to build the in-memory index, values are loaded when needed.
+//
+// NOTE: the backend expects to be the only writer to the bucket
+func NewBackend(ctx context.Context, opts BackendOptions) (Backend, error) {
+	if opts.Tracer == nil {
+		opts.Tracer = noop.NewTracerProvider().Tracer("parquet-storage-backend")
+	}
+	if opts.CompactionThreshold < 1 {
+		opts.CompactionThreshold = 32
+	}
+	if opts.SegmentTargetRows < 1 {
+		opts.SegmentTargetRows = 10000
+	}
+	if opts.SegmentCacheSize < 1 {
+		opts.SegmentCacheSize = 64
+	}
+	if opts.RootFolder != "" && !strings.HasSuffix(opts.RootFolder, "/") {
+		opts.RootFolder += "/"
+	}
+
+	if opts.Bucket == nil {
+		if opts.Path == "" {
+			return nil, fmt.Errorf("missing bucket or path")
+		}
+		dir, err := filepath.Abs(opts.Path)
+		if err != nil {
+			return nil, err
+		}
+		bucket, err := resource.OpenBlobBucket(ctx, "file://"+filepath.ToSlash(dir)+"?create_dir=1")
+		if err != nil {
+			return nil, err
+		}
+		opts.Bucket = bucket
+	}
+
+	b := &backend{
+		tracer:    opts.Tracer,
+		log:       logging.DefaultLogger.With("logger", "parquet.backend"),
+		bucket:    opts.Bucket,
+		prefix:    opts.RootFolder + historyFolder,
+		threshold: opts.CompactionThreshold,
+		target:    opts.SegmentTargetRows,
+		history:   make(map[resource.NamespacedResource]map[string][]*event),
+		watchers:  make(map[chan *resource.WrittenEvent]bool),
+	}
+
+	var err error
+	b.values, err = lru.New[string, [][]byte](opts.SegmentCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// The marker makes sure the root folder exists, this is required by the blob support
+	marker := b.prefix + formatFile
+	if _, err = b.bucket.Attributes(ctx, marker); err != nil {
+		if gcerrors.Code(err) != gcerrors.NotFound {
+			return nil, err
+		}
+		if err = b.bucket.WriteAll(ctx, marker, []byte(formatVersion), &blob.WriterOptions{
+			ContentType: "application/json",
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = b.load(ctx); err != nil {
+		return nil, err
+	}
+	if b.rv < 1 {
+		b.rv = time.Now().UnixMicro()
+	}
+
+	b.BlobSupport, err = resource.NewCDKBlobSupport(ctx, resource.CDKBlobSupportOptions{
+		Tracer:     opts.Tracer,
+		Bucket:     opts.Bucket,
+		RootFolder: opts.RootFolder,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+type backend struct {
+	resource.BlobSupport
+
+	tracer trace.Tracer
+	log    logging.Logger
+	bucket resource.CDKBucket
+	prefix string
+
+	threshold int
+	target    int
+
+	// Decoded value columns by segment path
+	values *lru.Cache[string, [][]byte]
+
+	// Serializes the writes, it is held while writing to the bucket so reads are not blocked
+	writeMu sync.Mutex
+
+	// Guards everything below
+	mu       sync.RWMutex
+	rv       int64
+	segments []*segment
+	history  map[resource.NamespacedResource]map[string][]*event
+
+	// Only one compaction at a time
+	compactMu  sync.Mutex
+	compacting atomic.Bool
+
+	// Watch subscribers, events are only delivered within this process
+	watchMu  sync.RWMutex
+	watchers map[chan *resource.WrittenEvent]bool
+}
+
+// An event in the index, the value is read from the segment when needed
+type event struct {
+	key    resource.NamespacedResource
+	name   string
+	rv     int64
+	action resourcepb.WatchEvent_Type
+	folder string
+
+	// The location of the value, guarded by the backend lock
+	segment *segment
+	row     int
+}
+
+// load reads the index from all segments in the bucket
+func (b *backend) load(ctx context.Context) error {
+	var segments []*segment
+	iter := b.bucket.List(&blob.ListOptions{Prefix: b.prefix, Delimiter: "/"})
+	for {
+		obj, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		minRV, maxRV, ok := parseSegmentName(b.prefix, obj.Key)
+		if !ok {
+			continue
+		}
+		segments = append(segments, &segment{path: obj.Key, minRV: minRV, maxRV: maxRV})
+	}
+
+	// When compaction is interrupted, both the merged segment and its inputs exist.
+	// Wider ranges are sorted first, so the inputs are contained in an earlier segment.
+	sort.Slice(segments, func(i, j int) bool {
+		if segments[i].minRV == segments[j].minRV {
+			return segments[i].maxRV > segments[j].maxRV
+		}
+		return segments[i].minRV < segments[j].minRV
+	})
+	var maxRV int64
+	for _, seg := range segments {
+		if seg.maxRV <= maxRV {
+			b.log.Info("removing compacted segment", "path", seg.path)
+			if err := b.bucket.Delete(ctx, seg.path); err != nil {
+				b.log.Warn("failed to remove compacted segment", "path", seg.path, "err", err)
+			}
+			continue
+		}
+		maxRV = seg.maxRV
+
+		data, err := b.bucket.ReadAll(ctx, seg.path)
+		if err != nil {
+			return fmt.Errorf("read segment %s: %w", seg.path, err)
+		}
+		rows, err := decodeSegment(ctx, data, false)
+		if err != nil {
+			return fmt.Errorf("decode segment %s: %w", seg.path, err)
+		}
+		b.addSegment(seg, rows)
+	}
+	return nil
+}
+
+// addSegment adds the rows to the index, the caller must hold the write lock
+func (b *backend) addSegment(seg *segment, rows []segmentRow) {
+	seg.events = make([]*event, len(rows))
+	for i, row := range rows {
+		ev := &event{
+			key: resource.NamespacedResource{
+				Namespace: row.namespace,
+				Group:     row.group,
+				Resource:  row.resource,
+			},
+			name:    row.name,
+			rv:      row.rv,
+			action:  row.action,
+			folder:  row.folder,
+			segment: seg,
+			row:     i,
+		}
+		seg.events[i] = ev
+
+		names, ok := b.history[ev.key]
+		if !ok {
+			names = make(map[string][]*event)
+			b.history[ev.key] = names
+		}
+		names[ev.name] = append(names[ev.name], ev)
+		if ev.rv > b.rv {
+			b.rv = ev.rv
+		}
+	}
+	b.segments = append(b.segments, seg)
+}
+
+// latest returns the most recent event at or before rv (0 for latest), the caller must hold the lock
+func latest(events []*event, rv int64) *event {
+	for i := len(events) - 1; i >= 0; i-- {
+		if rv < 1 || events[i].rv <= rv {
+			return events[i]
+		}
+	}
+	return nil
+}
+
+func (b *backend) WriteEvent(ctx context.Context, event resource.WriteEvent) (int64, error) {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"WriteEvent")
+	defer span.End()
+
+	if event.Key == nil {
+		return 0, fmt.Errorf("missing key")
+	}
+
+	row := segmentRow{
+		namespace: event.Key.Namespace,
+		group:     event.Key.Group,
+		resource:  event.Key.Resource,
+		name:      event.Key.Name,
+		action:    event.Type,
+		value:     event.Value,
+	}
+	if event.Object != nil {
+		row.folder = event.Object.GetFolder()
+	}
+
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+
+	// Only writers change the index, so it stays the same until the event is added below
+	key := resource.NamespacedResource{Namespace: row.namespace, Group: row.group, Resource: row.resource}
+	b.mu.RLock()
+	last := latest(b.history[key][row.name], 0)
+	row.rv = max(b.rv+1, time.Now().UnixMicro()) // The resource version is a (monotonic) timestamp in microseconds
+	b.mu.RUnlock()
+
+	// As the SQL backend, only a missing or deleted resource can be added, and only an existing one changed
+	exists := last != nil && last.action != resourcepb.WatchEvent_DELETED
+	switch {
+	case event.Type == resourcepb.WatchEvent_ADDED:
+		if exists {
+			return 0, resource.ErrResourceAlreadyExists
+		}
+	case !exists:
+		return 0, resource.NewResourceNotFoundError(event.Key)
+	case event.PreviousRV > 0 && event.PreviousRV != last.rv:
+		return 0, resource.ErrOptimisticLockingFailed
+	}
+
+	seg := &segment{
+		path:  segmentName(b.prefix, row.rv, row.rv),
+		minRV: row.rv,
+		maxRV: row.rv,
+	}
+	data, err := encodeSegment([]segmentRow{row})
+	if err != nil {
+		return 0, err
+	}
+	if err = b.bucket.WriteAll(ctx, seg.path, data, &blob.WriterOptions{
+		ContentType: "application/vnd.apache.parquet",
+	}); err != nil {
+		return 0, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.addSegment(seg, []segmentRow{row})
+	b.values.Add(seg.path, [][]byte{row.value})
+
+	// Sent while holding the lock so watchers see the events in order
+	b.notify(&resource.WrittenEvent{
+		Type:            event.Type,
+		Key:             event.Key,
+		PreviousRV:      event.PreviousRV,
+		Value:           event.Value,
+		Folder:          row.folder,
+		Timestamp:       time.Now().UnixMilli(),
+		ResourceVersion: row.rv,
+	})
+
+	if b.pendingCompaction() >= b.threshold && b.compacting.CompareAndSwap(false, true) {
+		go func() {
+			defer b.compacting.Store(false)
+			if err := b.Compact(context.Background()); err != nil {
+				b.log.Error("compaction failed", "err", err)
+			}
+		}()
+	}
+	return row.rv, nil
+}
+
+func (b *backend) ReadResource(ctx context.Context, req *resourcepb.ReadRequest) *resource.BackendReadResponse {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"ReadResource")
+	defer span.End()
+
+	if req.Key == nil {
+		return &resource.BackendReadResponse{Error: resource.AsErrorResult(fmt.Errorf("missing key"))}
+	}
+
+	b.mu.RLock()
+	current := b.rv
+	ev := latest(b.history[resource.NamespacedResource{
+		Namespace: req.Key.Namespace,
+		Group:     req.Key.Group,
+		Resource:  req.Key.Resource,
+	}][req.Key.Name], req.ResourceVersion)
+	b.mu.RUnlock()
+
+	if req.ResourceVersion > current {
+		return &resource.BackendReadResponse{Error: errResourceVersionTooLarge(req.ResourceVersion, current)}
+	}
+	if ev == nil || ev.action == resourcepb.WatchEvent_DELETED {
+		return &resource.BackendReadResponse{Error: resource.NewNotFoundError(req.Key)}
+	}
+
+	value, err := b.readValue(ctx, ev)
+	if err != nil {
+		return &resource.BackendReadResponse{Error: resource.AsErrorResult(err)}
+	}
+	return &resource.BackendReadResponse{
+		Key:             req.Key,
+		Folder:          ev.folder,
+		ResourceVersion: ev.rv,
+		Value:           value,
+	}
+}
+
+func errResourceVersionTooLarge(requested, current int64) *resourcepb.ErrorResult {
+	return &resourcepb.ErrorResult{
+		Code:    http.StatusGatewayTimeout,
+		Reason:  string(metav1.StatusReasonTimeout), // match etcd behavior
+		Message: "ResourceVersion is larger than max",
+		Details: &resourcepb.ErrorDetails{
+			Causes: []*resourcepb.ErrorCause{
+				{
+					Reason:  string(metav1.CauseTypeResourceVersionTooLarge),
+					Message: fmt.Sprintf("requested: %d, current %d", requested, current),
+				},
+			},
+		},
+	}
+}
+
+// readValue returns the value of an event, decoding (and caching) the segment when required
+func (b *backend) readValue(ctx context.Context, ev *event) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		b.mu.RLock()
+		seg, row := ev.segment, ev.row
+		b.mu.RUnlock()
+
+		if values, ok := b.values.Get(seg.path); ok {
+			return values[row], nil
+		}
+
+		data, err := b.bucket.ReadAll(ctx, seg.path)
+		if err != nil {
+			// The segment was replaced by compaction while reading, the event now points to the new segment
+			if gcerrors.Code(err) == gcerrors.NotFound && attempt == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("read segment %s: %w", seg.path, err)
+		}
+		rows, err := decodeSegment(ctx, data, true)
+		if err != nil {
+			return nil, fmt.Errorf("decode segment %s: %w", seg.path, err)
+		}
+		if len(rows) <= row {
+			return nil, fmt.Errorf("segment %s has %d rows, expected at least %d", seg.path, len(rows), row+1)
+		}
+
+		values := make([][]byte, len(rows))
+		for i := range rows {
+			values[i] = rows[i].value
+		}
+		b.values.Add(seg.path, values)
+		return values[row], nil
+	}
+}
+
+func (b *backend) WatchWriteEvents(ctx context.Context) (<-chan *resource.WrittenEvent, error) {
+	events := make(chan *resource.WrittenEvent, watchBufferSize)
+
+	b.watchMu.Lock()
+	b.watchers[events] = true
+	b.watchMu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.watchMu.Lock()
+		if b.watchers[events] {
+			delete(b.watchers, events)
+			close(events)
+		}
+		b.watchMu.Unlock()
+	}()
+
+	return events, nil
+}
+
+func (b *backend) notify(event *resource.WrittenEvent) {
+	b.watchMu.RLock()
+	defer b.watchMu.RUnlock()
+
+	for ch := range b.watchers {
+		select {
+		case ch <- event:
+		default:
+			b.log.Warn("Dropped event notification for subscriber - channel full")
+		}
+	}
+}
+
+func (b *backend) GetResourceStats(ctx context.Context, namespace string, minCount int) ([]resource.ResourceStats, error) {
+	_, span := b.tracer.Start(ctx, tracePrefix+"GetResourceStats")
+	defer span.End()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	stats := make([]resource.ResourceStats, 0, len(b.history))
+	for key, names := range b.history {
+		if namespace != "" && key.Namespace != namespace {
+			continue
+		}
+		s := resource.ResourceStats{NamespacedResource: key}
+		for _, events := range names {
+			last := events[len(events)-1]
+			if last.rv > s.ResourceVersion {
+				s.ResourceVersion = last.rv
+			}
+			if last.action != resourcepb.WatchEvent_DELETED {
+				s.Count++
+			}
+		}
+		if s.Count > int64(minCount) {
+			stats = append(stats, s)
+		}
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Namespace != stats[j].Namespace {
+			return stats[i].Namespace < stats[j].Namespace
+		}
+		if stats[i].Group != stats[j].Group {
+			return stats[i].Group < stats[j].Group
+		}
+		return stats[i].Resource < stats[j].Resource
+	})
+	return stats, nil
+}
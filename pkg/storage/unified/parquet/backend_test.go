@@ -0,0 +1,254 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/memblob"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+	unitest "github.com/grafana/grafana/pkg/storage/unified/testing"
+)
+
+func TestIntegrationParquetStorageBackend(t *testing.T) {
+	t.Run("local directory", func(t *testing.T) {
+		unitest.RunStorageBackendTest(t, func(ctx context.Context) resource.StorageBackend {
+			backend, err := NewBackend(ctx, BackendOptions{
+				Path: t.TempDir(),
+			})
+			require.NoError(t, err)
+			return backend
+		}, nil)
+	})
+
+	t.Run("bucket with small compaction threshold", func(t *testing.T) {
+		unitest.RunStorageBackendTest(t, func(ctx context.Context) resource.StorageBackend {
+			backend, err := NewBackend(ctx, BackendOptions{
+				Bucket:              memblob.OpenBucket(nil),
+				RootFolder:          "unified",
+				CompactionThreshold: 3,
+				SegmentTargetRows:   5,
+			})
+			require.NoError(t, err)
+			return backend
+		}, nil)
+	})
+}
+
+func TestBackendCompaction(t *testing.T) {
+	ctx := context.Background()
+	bucket := memblob.OpenBucket(nil)
+	open := func() Backend {
+		backend, err := NewBackend(ctx, BackendOptions{
+			Bucket:              bucket,
+			CompactionThreshold: 1000, // only compact explicitly
+			SegmentCacheSize:    1,
+		})
+		require.NoError(t, err)
+		return backend
+	}
+	write := func(backend Backend, action resourcepb.WatchEvent_Type, name string, value string) int64 {
+		rv, err := backend.WriteEvent(ctx, resource.WriteEvent{
+			Type: action,
+			Key: &resourcepb.ResourceKey{
+				Namespace: "default",
+				Group:     "dashboard.grafana.app",
+				Resource:  "dashboards",
+				Name:      name,
+			},
+			Value: []byte(value),
+		})
+		require.NoError(t, err)
+		return rv
+	}
+	read := func(backend Backend, name string, rv int64) *resource.BackendReadResponse {
+		return backend.ReadResource(ctx, &resourcepb.ReadRequest{
+			Key: &resourcepb.ResourceKey{
+				Namespace: "default",
+				Group:     "dashboard.grafana.app",
+				Resource:  "dashboards",
+				Name:      name,
+			},
+			ResourceVersion: rv,
+		})
+	}
+
+	backend := open()
+	first := write(backend, resourcepb.WatchEvent_ADDED, "a", `{"v":1}`)
+	write(backend, resourcepb.WatchEvent_MODIFIED, "a", `{"v":2}`)
+	write(backend, resourcepb.WatchEvent_ADDED, "b", `{"v":1}`)
+	deleted := write(backend, resourcepb.WatchEvent_DELETED, "b", `{"v":1}`)
+	require.Equal(t, 4, countSegments(t, bucket))
+
+	require.NoError(t, backend.Compact(ctx))
+	require.Equal(t, 1, countSegments(t, bucket))
+
+	// Compacting again is a no-op
+	require.NoError(t, backend.Compact(ctx))
+	require.Equal(t, 1, countSegments(t, bucket))
+
+	last := write(backend, resourcepb.WatchEvent_MODIFIED, "a", `{"v":3}`)
+	require.Equal(t, 2, countSegments(t, bucket))
+
+	for _, b := range []Backend{backend, open()} {
+		rsp := read(b, "a", 0)
+		require.Nil(t, rsp.Error)
+		require.Equal(t, last, rsp.ResourceVersion)
+		require.JSONEq(t, `{"v":3}`, string(rsp.Value))
+
+		rsp = read(b, "a", first)
+		require.Nil(t, rsp.Error)
+		require.JSONEq(t, `{"v":1}`, string(rsp.Value))
+
+		rsp = read(b, "b", 0)
+		require.NotNil(t, rsp.Error)
+		require.Equal(t, int32(404), rsp.Error.Code)
+
+		rsp = read(b, "b", deleted-1)
+		require.Nil(t, rsp.Error)
+		require.JSONEq(t, `{"v":1}`, string(rsp.Value))
+	}
+
+	t.Run("interrupted compaction is cleaned up on load", func(t *testing.T) {
+		// Write the merged segment, but leave the inputs in place
+		segments := listSegments(t, bucket)
+		require.Len(t, segments, 2)
+		overlap := segmentName(historyFolder, first, last)
+		require.NoError(t, bucket.WriteAll(ctx, overlap, mustMerge(t, bucket, segments), nil))
+
+		reopened := open()
+		require.Equal(t, []string{overlap}, listSegments(t, bucket))
+
+		rsp := read(reopened, "a", 0)
+		require.Nil(t, rsp.Error)
+		require.JSONEq(t, `{"v":3}`, string(rsp.Value))
+	})
+}
+
+func TestBackendWrites(t *testing.T) {
+	ctx := context.Background()
+	backend, err := NewBackend(ctx, BackendOptions{
+		Bucket: memblob.OpenBucket(nil),
+	})
+	require.NoError(t, err)
+
+	key := func(name string) *resourcepb.ResourceKey {
+		return &resourcepb.ResourceKey{
+			Namespace: "default",
+			Group:     "dashboard.grafana.app",
+			Resource:  "dashboards",
+			Name:      name,
+		}
+	}
+	write := func(action resourcepb.WatchEvent_Type, name string, previousRV int64) (int64, error) {
+		return backend.WriteEvent(ctx, resource.WriteEvent{
+			Type:       action,
+			Key:        key(name),
+			Value:      []byte(`{}`),
+			PreviousRV: previousRV,
+		})
+	}
+
+	t.Run("previous resource version must match", func(t *testing.T) {
+		created, err := write(resourcepb.WatchEvent_ADDED, "a", 0)
+		require.NoError(t, err)
+		updated, err := write(resourcepb.WatchEvent_MODIFIED, "a", created)
+		require.NoError(t, err)
+
+		_, err = write(resourcepb.WatchEvent_MODIFIED, "a", created)
+		require.ErrorIs(t, err, resource.ErrOptimisticLockingFailed)
+		_, err = write(resourcepb.WatchEvent_DELETED, "a", created)
+		require.ErrorIs(t, err, resource.ErrOptimisticLockingFailed)
+		_, err = write(resourcepb.WatchEvent_MODIFIED, "missing", created)
+		require.True(t, apierrors.IsNotFound(err))
+		_, err = write(resourcepb.WatchEvent_MODIFIED, "missing", 0)
+		require.True(t, apierrors.IsNotFound(err))
+
+		_, err = write(resourcepb.WatchEvent_DELETED, "a", updated)
+		require.NoError(t, err)
+	})
+
+	t.Run("history of every resource starts after its own deletion", func(t *testing.T) {
+		_, err := write(resourcepb.WatchEvent_ADDED, "b", 0)
+		require.NoError(t, err)
+		deleted, err := write(resourcepb.WatchEvent_DELETED, "b", 0)
+		require.NoError(t, err)
+		recreated, err := write(resourcepb.WatchEvent_ADDED, "b", 0)
+		require.NoError(t, err)
+		c, err := write(resourcepb.WatchEvent_ADDED, "c", 0)
+		require.NoError(t, err)
+		require.Greater(t, c, deleted)
+
+		var rvs []int64
+		_, err = backend.ListHistory(ctx, &resourcepb.ListRequest{
+			Source: resourcepb.ListRequest_HISTORY,
+			Options: &resourcepb.ListOptions{Key: &resourcepb.ResourceKey{
+				Namespace: "default",
+				Group:     "dashboard.grafana.app",
+				Resource:  "dashboards",
+			}},
+		}, func(iter resource.ListIterator) error {
+			for iter.Next() {
+				rvs = append(rvs, iter.ResourceVersion())
+			}
+			return iter.Error()
+		})
+		require.NoError(t, err)
+		require.Equal(t, []int64{c, recreated}, rvs)
+	})
+}
+
+func mustMerge(t *testing.T, bucket *blob.Bucket, segments []string) []byte {
+	t.Helper()
+	var rows []segmentRow
+	for _, path := range segments {
+		data, err := bucket.ReadAll(context.Background(), path)
+		require.NoError(t, err)
+		decoded, err := decodeSegment(context.Background(), data, true)
+		require.NoError(t, err)
+		rows = append(rows, decoded...)
+	}
+	data, err := encodeSegment(rows)
+	require.NoError(t, err)
+	return data
+}
+
+func listSegments(t *testing.T, bucket *blob.Bucket) []string {
+	t.Helper()
+	var segments []string
+	objects, _, err := bucket.ListPage(context.Background(), blob.FirstPageToken, 1000, &blob.ListOptions{
+		Prefix: historyFolder,
+	})
+	require.NoError(t, err)
+	for _, obj := range objects {
+		if _, _, ok := parseSegmentName(historyFolder, obj.Key); ok {
+			segments = append(segments, obj.Key)
+		}
+	}
+	return segments
+}
+
+func countSegments(t *testing.T, bucket *blob.Bucket) int {
+	t.Helper()
+	return len(listSegments(t, bucket))
+}
+
+func TestSegmentName(t *testing.T) {
+	name := segmentName("x/"+historyFolder, 12, 345)
+	require.Equal(t, fmt.Sprintf("x/%s%019d-%019d.parquet", historyFolder, 12, 345), name)
+
+	minRV, maxRV, ok := parseSegmentName("x/"+historyFolder, name)
+	require.True(t, ok)
+	require.Equal(t, int64(12), minRV)
+	require.Equal(t, int64(345), maxRV)
+
+	_, _, ok = parseSegmentName("x/"+historyFolder, "x/"+historyFolder+formatFile)
+	require.False(t, ok)
+	_, _, ok = parseSegmentName("x/"+historyFolder, "x/"+historyFolder+"5-1.parquet")
+	require.False(t, ok)
+}
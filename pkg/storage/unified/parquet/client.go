@@ -43,6 +43,11 @@ func (w *writerClient) BulkProcess(ctx context.Context, _ ...grpc.CallOption) (r
 	return w, nil
 }
 
+// Restore implements resource.ResourceStoreClient.
+func (w *writerClient) Restore(ctx context.Context, in *resourcepb.RestoreRequest, opts ...grpc.CallOption) (*resourcepb.RestoreResponse, error) {
+	return nil, errUnimplemented
+}
+
 // CloseAndRecv implements resource.ResourceStore_BulkProcessClient.
 func (w *writerClient) CloseAndRecv() (*resourcepb.BulkResponse, error) {
 	return w.writer.CloseWithResults()
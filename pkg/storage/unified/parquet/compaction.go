@@ -0,0 +1,91 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+
+	"gocloud.dev/blob"
+)
+
+// pendingCompaction counts the trailing segments that are smaller than the target size.
+// The caller must hold the lock.
+func (b *backend) pendingCompaction() int {
+	count := 0
+	for i := len(b.segments) - 1; i >= 0 && len(b.segments[i].events) < b.target; i-- {
+		count++
+	}
+	return count
+}
+
+// Compact merges the trailing run of small segments into a single segment.
+// The merged file is written before the index is switched to it, and the old files are removed last,
+// so an interrupted compaction leaves overlapping segments that are cleaned up on the next load.
+func (b *backend) Compact(ctx context.Context) error {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"Compact")
+	defer span.End()
+
+	b.compactMu.Lock()
+	defer b.compactMu.Unlock()
+
+	// Only compaction removes segments, so the run stays in place while the lock is released
+	b.mu.RLock()
+	start := len(b.segments) - b.pendingCompaction()
+	run := append([]*segment(nil), b.segments[start:]...)
+	b.mu.RUnlock()
+
+	if len(run) < 2 {
+		return nil
+	}
+
+	var rows []segmentRow
+	for _, seg := range run {
+		data, err := b.bucket.ReadAll(ctx, seg.path)
+		if err != nil {
+			return fmt.Errorf("read segment %s: %w", seg.path, err)
+		}
+		decoded, err := decodeSegment(ctx, data, true)
+		if err != nil {
+			return fmt.Errorf("decode segment %s: %w", seg.path, err)
+		}
+		if len(decoded) != len(seg.events) {
+			return fmt.Errorf("segment %s has %d rows, expected %d", seg.path, len(decoded), len(seg.events))
+		}
+		rows = append(rows, decoded...)
+	}
+
+	merged := &segment{
+		path:  segmentName(b.prefix, run[0].minRV, run[len(run)-1].maxRV),
+		minRV: run[0].minRV,
+		maxRV: run[len(run)-1].maxRV,
+	}
+	data, err := encodeSegment(rows)
+	if err != nil {
+		return err
+	}
+	if err = b.bucket.WriteAll(ctx, merged.path, data, &blob.WriterOptions{
+		ContentType: "application/vnd.apache.parquet",
+	}); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	for _, seg := range run {
+		for _, ev := range seg.events {
+			ev.segment = merged
+			ev.row = len(merged.events)
+			merged.events = append(merged.events, ev)
+		}
+	}
+	segments := append(b.segments[:start:start], merged)
+	b.segments = append(segments, b.segments[start+len(run):]...)
+	b.mu.Unlock()
+
+	for _, seg := range run {
+		b.values.Remove(seg.path)
+		if err := b.bucket.Delete(ctx, seg.path); err != nil {
+			b.log.Warn("failed to remove compacted segment", "path", seg.path, "err", err)
+		}
+	}
+	b.log.Debug("compacted segments", "segments", len(run), "rows", len(rows), "path", merged.path)
+	return nil
+}
@@ -0,0 +1,291 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+func (b *backend) ListIterator(ctx context.Context, req *resourcepb.ListRequest, cb func(resource.ListIterator) error) (int64, error) {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"ListIterator")
+	defer span.End()
+
+	if err := resource.MigrateListRequestVersionMatch(req, b.log); err != nil {
+		return 0, err
+	}
+	if req.Options == nil || req.Options.Key.Group == "" || req.Options.Key.Resource == "" {
+		return 0, fmt.Errorf("missing group or resource")
+	}
+	key := req.Options.Key
+
+	listRV := req.ResourceVersion
+	var offset int64
+	if req.NextPageToken != "" {
+		token, err := resource.GetContinueToken(req.NextPageToken)
+		if err != nil {
+			return 0, fmt.Errorf("get continue token: %w", err)
+		}
+		if listRV > 0 && listRV != token.ResourceVersion {
+			return 0, fmt.Errorf("resource version mismatch: %d != %d", listRV, token.ResourceVersion)
+		}
+		listRV = token.ResourceVersion
+		offset = token.StartOffset
+	}
+
+	b.mu.RLock()
+	current := b.rv
+	if listRV < 1 {
+		listRV = current
+	}
+	var events []*event
+	if listRV <= current {
+		for nr, names := range b.history {
+			if (key.Namespace != "" && nr.Namespace != key.Namespace) || nr.Group != key.Group || nr.Resource != key.Resource {
+				continue
+			}
+			for name, versions := range names {
+				if key.Name != "" && name != key.Name {
+					continue
+				}
+				if ev := latest(versions, listRV); ev != nil && ev.action != resourcepb.WatchEvent_DELETED {
+					events = append(events, ev)
+				}
+			}
+		}
+	}
+	b.mu.RUnlock()
+
+	if listRV > current {
+		return 0, resource.GetError(errResourceVersionTooLarge(listRV, current))
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].key.Namespace != events[j].key.Namespace {
+			return events[i].key.Namespace < events[j].key.Namespace
+		}
+		return events[i].name < events[j].name
+	})
+	events = events[min(offset, int64(len(events))):]
+
+	iter := &listIterator{
+		ctx:     ctx,
+		backend: b,
+		events:  events,
+		index:   -1,
+		offset:  offset,
+		listRV:  listRV,
+	}
+	return listRV, cb(iter)
+}
+
+// ListHistory returns the versions of a single resource, or the deleted resources when listing the trash
+func (b *backend) ListHistory(ctx context.Context, req *resourcepb.ListRequest, cb func(resource.ListIterator) error) (int64, error) {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"ListHistory")
+	defer span.End()
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if err := resource.MigrateListRequestVersionMatch(req, b.log); err != nil {
+		return 0, err
+	}
+	if req.Options == nil || req.Options.Key.Group == "" || req.Options.Key.Resource == "" {
+		return 0, fmt.Errorf("missing group or resource")
+	}
+	key := req.Options.Key
+	trash := req.Source == resourcepb.ListRequest_TRASH
+
+	// Ascending when fetching everything newer than a version, newest first otherwise
+	sortAsc := req.GetVersionMatchV2() == resourcepb.ResourceVersionMatchV2_NotOlderThan
+	var startRV, minRV, exactRV int64
+	if req.NextPageToken != "" {
+		token, err := resource.GetContinueToken(req.NextPageToken)
+		if err != nil {
+			return 0, fmt.Errorf("get continue token: %w", err)
+		}
+		startRV = token.ResourceVersion
+		sortAsc = token.SortAscending
+	}
+	switch req.GetVersionMatchV2() {
+	case resourcepb.ResourceVersionMatchV2_Exact:
+		if req.ResourceVersion <= 0 {
+			return 0, fmt.Errorf("expecting an explicit resource version query when using Exact matching")
+		}
+		exactRV = req.ResourceVersion
+	case resourcepb.ResourceVersionMatchV2_NotOlderThan:
+		if req.ResourceVersion > 0 {
+			minRV = req.ResourceVersion
+		}
+	}
+
+	include := func(ev *event) bool {
+		if startRV > 0 && ((sortAsc && ev.rv <= startRV) || (!sortAsc && ev.rv >= startRV)) {
+			return false
+		}
+		if minRV > 0 && ev.rv < minRV {
+			return false
+		}
+		return exactRV == 0 || ev.rv == exactRV
+	}
+
+	b.mu.RLock()
+	listRV := b.rv
+	names := b.history[resource.NamespacedResource{
+		Namespace: key.Namespace,
+		Group:     key.Group,
+		Resource:  key.Resource,
+	}]
+
+	var events []*event
+	for name, versions := range names {
+		if key.Name != "" && name != key.Name {
+			continue
+		}
+		if !trash {
+			// Without an explicit range, only the versions after the most recent deletion of the resource are returned
+			var afterRV int64
+			if minRV == 0 && exactRV == 0 {
+				for _, ev := range versions {
+					if ev.action == resourcepb.WatchEvent_DELETED {
+						afterRV = ev.rv + 1
+					}
+				}
+			}
+			for _, ev := range versions {
+				if ev.rv >= afterRV && include(ev) {
+					events = append(events, ev)
+				}
+			}
+			continue
+		}
+
+		// The trash holds the last deletion of resources that were not recreated
+		if versions[len(versions)-1].action != resourcepb.WatchEvent_DELETED {
+			continue
+		}
+		for i := len(versions) - 1; i >= 0; i-- {
+			if versions[i].action == resourcepb.WatchEvent_DELETED && include(versions[i]) {
+				events = append(events, versions[i])
+				break
+			}
+		}
+	}
+	b.mu.RUnlock()
+
+	sort.Slice(events, func(i, j int) bool {
+		if sortAsc {
+			return events[i].rv < events[j].rv
+		}
+		return events[i].rv > events[j].rv
+	})
+
+	iter := &listIterator{
+		ctx:          ctx,
+		backend:      b,
+		events:       events,
+		index:        -1,
+		listRV:       listRV,
+		sortAsc:      sortAsc,
+		useCurrentRV: true,
+	}
+	return listRV, cb(iter)
+}
+
+type listIterator struct {
+	ctx     context.Context
+	backend *backend
+	events  []*event
+	index   int
+	err     error
+
+	// pagination
+	offset  int64
+	listRV  int64
+	sortAsc bool
+
+	// When true, the continue token starts after the current item rather than the offset
+	useCurrentRV bool
+
+	current *event
+	value   []byte
+}
+
+// Next implements resource.ListIterator.
+func (l *listIterator) Next() bool {
+	if l.err != nil {
+		return false
+	}
+	if l.err = l.ctx.Err(); l.err != nil {
+		return false
+	}
+
+	l.index++
+	if l.index >= len(l.events) {
+		l.current = nil
+		l.value = nil
+		return false
+	}
+	l.offset++
+	l.current = l.events[l.index]
+	l.value, l.err = l.backend.readValue(l.ctx, l.current)
+	return l.err == nil
+}
+
+// Error implements resource.ListIterator.
+func (l *listIterator) Error() error {
+	return l.err
+}
+
+// ContinueToken implements resource.ListIterator.
+func (l *listIterator) ContinueToken() string {
+	if l.useCurrentRV {
+		return resource.ContinueToken{
+			ResourceVersion: l.ResourceVersion(),
+			SortAscending:   l.sortAsc,
+		}.String()
+	}
+	return resource.ContinueToken{
+		ResourceVersion: l.listRV,
+		StartOffset:     l.offset,
+	}.String()
+}
+
+// ResourceVersion implements resource.ListIterator.
+func (l *listIterator) ResourceVersion() int64 {
+	if l.current == nil {
+		return 0
+	}
+	return l.current.rv
+}
+
+// Namespace implements resource.ListIterator.
+func (l *listIterator) Namespace() string {
+	if l.current == nil {
+		return ""
+	}
+	return l.current.key.Namespace
+}
+
+// Name implements resource.ListIterator.
+func (l *listIterator) Name() string {
+	if l.current == nil {
+		return ""
+	}
+	return l.current.name
+}
+
+// Folder implements resource.ListIterator.
+func (l *listIterator) Folder() string {
+	if l.current == nil {
+		return ""
+	}
+	return l.current.folder
+}
+
+// Value implements resource.ListIterator.
+func (l *listIterator) Value() []byte {
+	return l.value
+}
@@ -0,0 +1,234 @@
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+const (
+	segmentExt = ".parquet"
+
+	// rows are written to the parquet file in batches of this size, each batch is a row group
+	segmentRowGroupSize = 10000
+)
+
+// A segment is an immutable parquet file holding a contiguous range of resource versions.
+// Each write creates a new segment, and compaction merges small segments into larger ones.
+type segment struct {
+	path  string
+	minRV int64
+	maxRV int64
+
+	// events stored in this segment, in file order
+	events []*event
+}
+
+// segmentRow is a single event, as stored in a segment file
+type segmentRow struct {
+	rv        int64
+	namespace string
+	group     string
+	resource  string
+	name      string
+	folder    string
+	action    resourcepb.WatchEvent_Type
+	value     []byte
+}
+
+// Segment names sort by resource version, so the files are listed in write order
+func segmentName(prefix string, minRV, maxRV int64) string {
+	return fmt.Sprintf("%s%019d-%019d%s", prefix, minRV, maxRV, segmentExt)
+}
+
+func parseSegmentName(prefix, key string) (minRV int64, maxRV int64, ok bool) {
+	name, found := strings.CutPrefix(key, prefix)
+	if !found {
+		return 0, 0, false
+	}
+	name, found = strings.CutSuffix(name, segmentExt)
+	if !found {
+		return 0, 0, false
+	}
+	lo, hi, found := strings.Cut(name, "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	var err error
+	if minRV, err = strconv.ParseInt(lo, 10, 64); err != nil {
+		return 0, 0, false
+	}
+	if maxRV, err = strconv.ParseInt(hi, 10, 64); err != nil {
+		return 0, 0, false
+	}
+	return minRV, maxRV, minRV <= maxRV
+}
+
+func encodeSegment(rows []segmentRow) ([]byte, error) {
+	var buf bytes.Buffer
+	schema := newSchema(nil)
+	props := parquet.NewWriterProperties(
+		parquet.WithCompression(compress.Codecs.Brotli),
+	)
+	writer, err := pqarrow.NewFileWriter(schema, &buf, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return nil, err
+	}
+
+	pool := memory.DefaultAllocator
+	for len(rows) > 0 {
+		batch := rows[:min(len(rows), segmentRowGroupSize)]
+		rows = rows[len(batch):]
+
+		rv := array.NewInt64Builder(pool)
+		group := array.NewStringBuilder(pool)
+		resource := array.NewStringBuilder(pool)
+		namespace := array.NewStringBuilder(pool)
+		name := array.NewStringBuilder(pool)
+		folder := array.NewStringBuilder(pool)
+		action := array.NewInt8Builder(pool)
+		value := array.NewStringBuilder(pool)
+
+		for _, row := range batch {
+			rv.Append(row.rv)
+			group.Append(row.group)
+			resource.Append(row.resource)
+			namespace.Append(row.namespace)
+			name.Append(row.name)
+			folder.Append(row.folder)
+			action.Append(int8(row.action))
+			value.Append(string(row.value))
+		}
+
+		// Columns must match the order in newSchema
+		rec := array.NewRecord(schema, []arrow.Array{
+			rv.NewArray(),
+			group.NewArray(),
+			resource.NewArray(),
+			namespace.NewArray(),
+			name.NewArray(),
+			folder.NewArray(),
+			action.NewArray(),
+			value.NewArray(),
+		}, int64(len(batch)))
+		err = writer.Write(rec)
+		rec.Release()
+		if err != nil {
+			_ = writer.Close()
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeSegment reads all rows from a segment file.
+// When withValues is false, the value column is not read, which keeps loading the index cheap.
+func decodeSegment(ctx context.Context, data []byte, withValues bool) ([]segmentRow, error) {
+	rdr, err := file.NewParquetReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rdr.Close() }()
+
+	fr, err := pqarrow.NewFileReader(rdr, pqarrow.ArrowReadProperties{BatchSize: segmentRowGroupSize}, memory.DefaultAllocator)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := rdr.MetaData().Schema
+	names := []string{"resource_version", "group", "resource", "namespace", "name", "folder", "action"}
+	if withValues {
+		names = append(names, "value")
+	}
+	indices := make([]int, len(names))
+	for i, name := range names {
+		indices[i] = schema.ColumnIndexByName(name)
+		if indices[i] < 0 {
+			return nil, fmt.Errorf("missing column: %s", name)
+		}
+	}
+
+	rr, err := fr.GetRecordReader(ctx, indices, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rr.Release()
+
+	rows := make([]segmentRow, 0, rdr.NumRows())
+	for rr.Next() {
+		rec := rr.Record()
+		rv, ok := rec.Column(0).(*array.Int64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected resource_version column type: %s", rec.Column(0).DataType())
+		}
+		strs := make([]*array.String, 5)
+		for i := range strs {
+			strs[i], ok = rec.Column(i + 1).(*array.String)
+			if !ok {
+				return nil, fmt.Errorf("unexpected %s column type: %s", names[i+1], rec.Column(i+1).DataType())
+			}
+		}
+		var values *array.String
+		if withValues {
+			values, ok = rec.Column(7).(*array.String)
+			if !ok {
+				return nil, fmt.Errorf("unexpected value column type: %s", rec.Column(7).DataType())
+			}
+		}
+
+		for i := 0; i < int(rec.NumRows()); i++ {
+			action, err := actionAt(rec.Column(6), i)
+			if err != nil {
+				return nil, err
+			}
+			row := segmentRow{
+				rv:        rv.Value(i),
+				group:     strs[0].Value(i),
+				resource:  strs[1].Value(i),
+				namespace: strs[2].Value(i),
+				name:      strs[3].Value(i),
+				folder:    strs[4].Value(i),
+				action:    action,
+			}
+			if values != nil {
+				row.value = []byte(values.Value(i))
+			}
+			rows = append(rows, row)
+		}
+	}
+	if err := rr.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// The action is written as int8, but files written without the arrow schema read back as int32
+func actionAt(col arrow.Array, i int) (resourcepb.WatchEvent_Type, error) {
+	switch c := col.(type) {
+	case *array.Int8:
+		return resourcepb.WatchEvent_Type(c.Value(i)), nil
+	case *array.Int32:
+		return resourcepb.WatchEvent_Type(c.Value(i)), nil
+	default:
+		return 0, fmt.Errorf("unexpected action column type: %s", col.DataType())
+	}
+}
@@ -0,0 +1,142 @@
+package resource
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math/rand/v2"
+	"time"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// The blob values are split in content defined chunks, addressed by their sha256.
+// The boundaries depend on the content, not on the offsets, so the versions of a dashboard
+// that only change a few panels share most of their chunks, and the identical chunks
+// are only stored once across all the blobs.
+
+const (
+	blobChunkMin = 2 * 1024
+	blobChunkMax = 64 * 1024
+
+	// 13 bits gives ~8KiB chunks on average
+	blobChunkMask = uint64(1<<13-1) << (64 - 13)
+)
+
+// BlobGarbageCollector is implemented by the blob stores that share chunks between the blobs
+type BlobGarbageCollector interface {
+	// DeleteResourceBlob removes the blob, and releases its references to the chunks
+	DeleteResourceBlob(ctx context.Context, key *resourcepb.ResourceKey, uid string) error
+
+	// CollectBlobGarbage removes the chunks that are no longer referenced by any blob.
+	// Returns the number of chunks removed.
+	CollectBlobGarbage(ctx context.Context) (int64, error)
+}
+
+// The gear table of the rolling hash, it must never change or the existing chunks would not match anymore
+var blobChunkGear = func() [256]uint64 {
+	var gear [256]uint64
+	rng := rand.New(rand.NewPCG(0x6772616661, 0x6e61626c6f62)) // nolint:gosec
+	for i := range gear {
+		gear[i] = rng.Uint64()
+	}
+	return gear
+}()
+
+// SplitBlobChunks splits the value in content defined chunks
+func SplitBlobChunks(value []byte) [][]byte {
+	var chunks [][]byte
+	for len(value) > 0 {
+		n := blobChunkBoundary(value)
+		chunks = append(chunks, value[:n])
+		value = value[n:]
+	}
+	return chunks
+}
+
+func blobChunkBoundary(value []byte) int {
+	if len(value) <= blobChunkMin {
+		return len(value)
+	}
+	end := min(len(value), blobChunkMax)
+	var h uint64
+	for i := blobChunkMin; i < end; i++ {
+		h = (h << 1) + blobChunkGear[value[i]]
+		if h&blobChunkMask == 0 {
+			return i + 1
+		}
+	}
+	return end
+}
+
+// BlobChunkHash returns the address of the chunk
+func BlobChunkHash(chunk []byte) string {
+	sum := sha256.Sum256(chunk)
+	return hex.EncodeToString(sum[:])
+}
+
+// The blobs split in chunks are stored as a manifest, the raw blobs written before never start with this
+var blobManifestMagic = []byte("grafana-blob-chunks/v1\n")
+
+type blobManifest struct {
+	Size int64 `json:"size"`
+
+	// The hash of each chunk, in order
+	Chunks []string `json:"chunks"`
+}
+
+func encodeBlobManifest(m blobManifest) ([]byte, error) {
+	body, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return append(bytes.Clone(blobManifestMagic), body...), nil
+}
+
+// decodeBlobManifest returns nil when the value is not a manifest
+func decodeBlobManifest(value []byte) (*blobManifest, error) {
+	if !bytes.HasPrefix(value, blobManifestMagic) {
+		return nil, nil
+	}
+	m := &blobManifest{}
+	if err := json.Unmarshal(value[len(blobManifestMagic):], m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// uniqueChunks returns the chunks by hash, and the hashes in order
+func uniqueChunks(value []byte) (map[string][]byte, []string) {
+	chunks := SplitBlobChunks(value)
+	byHash := make(map[string][]byte, len(chunks))
+	hashes := make([]string, 0, len(chunks))
+	for _, chunk := range chunks {
+		hash := BlobChunkHash(chunk)
+		byHash[hash] = chunk
+		hashes = append(hashes, hash)
+	}
+	return byHash, hashes
+}
+
+func (s *server) runBlobGarbageCollection(gc BlobGarbageCollector) {
+	ticker := time.NewTicker(blobGarbageGracePeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := gc.CollectBlobGarbage(s.ctx)
+			if err != nil {
+				s.log.Warn("failed to collect blob garbage", "err", err)
+				continue
+			}
+			if removed > 0 {
+				s.log.Info("collected blob garbage", "chunks", removed)
+			}
+		}
+	}
+}
@@ -210,29 +210,35 @@ func (s *server) BulkProcess(stream resourcepb.BulkStore_BulkProcessServer) erro
 		rsp.Error = AsErrorResult(runner.err)
 	}
 
-	if rsp.Error == nil && s.search != nil {
-		// Rebuild any changed indexes
-		for _, summary := range rsp.Summary {
-			_, _, err := s.search.build(ctx, NamespacedResource{
-				Namespace: summary.Namespace,
-				Group:     summary.Group,
-				Resource:  summary.Resource,
-			}, summary.Count, summary.ResourceVersion)
-			if err != nil {
-				s.log.Warn("error building search index after batch load", "err", err)
-				rsp.Error = &resourcepb.ErrorResult{
-					Code:    http.StatusInternalServerError,
-					Message: "err building search index: " + summary.Resource,
-					Reason:  err.Error(),
-				}
+	s.rebuildIndexes(ctx, rsp)
+	return stream.SendAndClose(rsp)
+}
+
+// rebuildIndexes rebuilds the search index for every collection changed by a bulk request
+func (s *server) rebuildIndexes(ctx context.Context, rsp *resourcepb.BulkResponse) {
+	if rsp.Error != nil || s.search == nil {
+		return
+	}
+	for _, summary := range rsp.Summary {
+		_, _, err := s.search.build(ctx, NamespacedResource{
+			Namespace: summary.Namespace,
+			Group:     summary.Group,
+			Resource:  summary.Resource,
+		}, summary.Count, summary.ResourceVersion)
+		if err != nil {
+			s.log.Warn("error building search index after batch load", "err", err)
+			rsp.Error = &resourcepb.ErrorResult{
+				Code:    http.StatusInternalServerError,
+				Message: "err building search index: " + summary.Resource,
+				Reason:  err.Error(),
 			}
 		}
 	}
-	return stream.SendAndClose(rsp)
 }
 
 var (
 	_ BulkRequestIterator = (*batchRunner)(nil)
+	_ BulkRequestIterator = (*bulkRequestList)(nil)
 )
 
 type batchRunner struct {
@@ -294,3 +300,32 @@ func (b *batchRunner) RollbackRequested() bool {
 	}
 	return false
 }
+
+// NewBulkRequestIterator returns an iterator for requests that are already in memory
+func NewBulkRequestIterator(requests []*resourcepb.BulkRequest) BulkRequestIterator {
+	return &bulkRequestList{requests: requests, index: -1}
+}
+
+type bulkRequestList struct {
+	requests []*resourcepb.BulkRequest
+	index    int
+}
+
+// Next implements BulkRequestIterator.
+func (b *bulkRequestList) Next() bool {
+	b.index++
+	return b.index < len(b.requests)
+}
+
+// Request implements BulkRequestIterator.
+func (b *bulkRequestList) Request() *resourcepb.BulkRequest {
+	if b.index < 0 || b.index >= len(b.requests) {
+		return nil
+	}
+	return b.requests[b.index]
+}
+
+// RollbackRequested implements BulkRequestIterator.
+func (b *bulkRequestList) RollbackRequested() bool {
+	return false
+}
@@ -3,43 +3,105 @@ package resource
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
-	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/trace"
 	"go.opentelemetry.io/otel/trace/noop"
 	"gocloud.dev/blob"
 	_ "gocloud.dev/blob/fileblob"
 	_ "gocloud.dev/blob/memblob"
+	"gocloud.dev/gcerrors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/grafana/grafana-app-sdk/logging"
+
 	"github.com/grafana/grafana/pkg/apimachinery/utils"
 	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
 )
 
+// Every version of a resource is written as an object in the bucket:
+//
+//	{root}{group}/{resource}/{namespace}/{name}/{rv}~{action}~{folder}.json
+//
+// The action and folder are part of the object name, so the history can be listed without reading values.
+// Objects written before this layout ({rv}.json) are still supported, deletions are then found in the value.
+// Folders starting with "__" hold the change log, the writer leases and the blobs.
+// The claims of the next version ({rv}~next~{attempt}) are written next to the versions, see cdk_changelog.go.
+const (
+	cdkClusterNamespace = "__cluster__"
+	cdkChangeLogFolder  = "__changelog__/"
+	cdkWritersFolder    = "__writers__/"
+	cdkBlobsFolder      = "__blobs__/"
+	cdkVersionSuffix    = ".json"
+	cdkVersionSeparator = "~"
+	cdkClaimSeparator   = "~next~"
+)
+
 type CDKBackendOptions struct {
 	Tracer     trace.Tracer
 	Bucket     CDKBucket
 	RootFolder string
+
+	// How often the change log is polled for events, including the ones written by other processes (default 100ms)
+	WatchPollInterval time.Duration
+
+	// Versions older than this are removed by compaction, the latest version is always kept (0 keeps the full history)
+	HistoryRetention time.Duration
+
+	// Change log entries older than this are removed by compaction (default 1h)
+	ChangeLogRetention time.Duration
+
+	// How often compaction runs in the background (default 10m, negative disables it)
+	CompactionInterval time.Duration
+}
+
+// CDKBackend keeps every version of a resource as an object in a bucket (s3, gcs, azure, file, ...).
+// Multiple processes can share the same bucket, see cdk_changelog.go for how they are coordinated.
+type CDKBackend interface {
+	StorageBackend
+	BlobSupport
+
+	// Stop ends the background work and releases the writer slot
+	LifecycleHooks
+
+	// Compact removes the versions and change log entries older than the configured retention
+	Compact(ctx context.Context) error
 }
 
-func NewCDKBackend(ctx context.Context, opts CDKBackendOptions) (StorageBackend, error) {
+var _ CDKBackend = (*cdkBackend)(nil)
+
+func NewCDKBackend(ctx context.Context, opts CDKBackendOptions) (CDKBackend, error) {
 	if opts.Tracer == nil {
 		opts.Tracer = noop.NewTracerProvider().Tracer("cdk-appending-store")
 	}
+	if opts.WatchPollInterval <= 0 {
+		opts.WatchPollInterval = 100 * time.Millisecond
+	}
+	if opts.ChangeLogRetention <= 0 {
+		opts.ChangeLogRetention = time.Hour
+	}
+	if opts.CompactionInterval == 0 {
+		opts.CompactionInterval = 10 * time.Minute
+	}
 
 	if opts.Bucket == nil {
 		return nil, fmt.Errorf("missing bucket")
 	}
+	if opts.RootFolder != "" && !strings.HasSuffix(opts.RootFolder, "/") {
+		opts.RootFolder += "/"
+	}
 
 	found, _, err := opts.Bucket.ListPage(ctx, blob.FirstPageToken, 1, &blob.ListOptions{
 		Prefix:    opts.RootFolder,
@@ -53,25 +115,83 @@ func NewCDKBackend(ctx context.Context, opts CDKBackendOptions) (StorageBackend,
 	}
 
 	backend := &cdkBackend{
-		tracer: opts.Tracer,
-		bucket: opts.Bucket,
-		root:   opts.RootFolder,
+		tracer:             opts.Tracer,
+		log:                logging.DefaultLogger.With("logger", "cdk-backend"),
+		bucket:             opts.Bucket,
+		root:               opts.RootFolder,
+		writerID:           uuid.New().String(),
+		pollInterval:       opts.WatchPollInterval,
+		historyRetention:   opts.HistoryRetention,
+		changeLogRetention: opts.ChangeLogRetention,
+	}
+	backend.blobs = &cdkBlobSupport{
+		tracer:     opts.Tracer,
+		bucket:     opts.Bucket,
+		root:       opts.RootFolder + cdkBlobsFolder,
+		expiration: time.Minute * 10,
+	}
+
+	if err = backend.claimWriterSlot(ctx); err != nil {
+		return nil, err
+	}
+
+	// The background work runs until the backend is stopped
+	bgCtx, cancel := context.WithCancel(context.Background())
+	backend.cancel = cancel
+	backend.wg.Add(1)
+	go func() {
+		defer backend.wg.Done()
+		backend.renewWriterSlot(bgCtx)
+	}()
+	if opts.CompactionInterval > 0 {
+		backend.wg.Add(1)
+		go func() {
+			defer backend.wg.Done()
+			backend.runCompaction(bgCtx, opts.CompactionInterval)
+		}()
 	}
-	backend.rv.Swap(time.Now().UnixMilli())
 	return backend, nil
 }
 
 type cdkBackend struct {
 	tracer trace.Tracer
+	log    logging.Logger
 	bucket CDKBucket
 	root   string
+	blobs  *cdkBlobSupport
 
+	// Writes are serialized within the process, so the change log is written in order
 	mutex sync.Mutex
-	rv    atomic.Int64
 
-	// Simple watch stream -- NOTE, this only works for single tenant!
-	broadcaster Broadcaster[*WrittenEvent]
-	stream      chan<- *WrittenEvent
+	// Resource versions (see cdk_changelog.go)
+	rvMutex      sync.Mutex
+	writerID     string
+	slot         int64
+	term         int64
+	leaseExpires time.Time
+	lastRV       int64
+
+	// Stops the background work
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	// How often watchers poll the change log
+	pollInterval time.Duration
+
+	historyRetention   time.Duration
+	changeLogRetention time.Duration
+}
+
+// A single version of a resource
+type cdkVersion struct {
+	path      string
+	namespace string
+	name      string
+	rv        int64
+	folder    string
+
+	// Unknown for objects written with the previous layout
+	action resourcepb.WatchEvent_Type
 }
 
 func (s *cdkBackend) getPath(key *resourcepb.ResourceKey, rv int64) string {
@@ -93,7 +213,7 @@ func (s *cdkBackend) getPath(key *resourcepb.ResourceKey, rv int64) string {
 		if key.Name == "" {
 			return buffer.String()
 		}
-		buffer.WriteString("/__cluster__")
+		buffer.WriteString("/" + cdkClusterNamespace)
 	} else {
 		buffer.WriteString("/")
 		buffer.WriteString(key.Namespace)
@@ -111,116 +231,414 @@ func (s *cdkBackend) getPath(key *resourcepb.ResourceKey, rv int64) string {
 	return buffer.String()
 }
 
-// GetResourceStats implements Backend.
-func (s *cdkBackend) GetResourceStats(ctx context.Context, namespace string, minCount int) ([]ResourceStats, error) {
-	return nil, fmt.Errorf("not implemented")
+// getVersionPath returns the object name for a new version
+func (s *cdkBackend) getVersionPath(key *resourcepb.ResourceKey, rv int64, action resourcepb.WatchEvent_Type, folder string) string {
+	return fmt.Sprintf("%s/%d%s%d%s%s%s", s.getPath(key, 0), rv,
+		cdkVersionSeparator, action, cdkVersionSeparator, url.PathEscape(folder), cdkVersionSuffix)
 }
 
-func (s *cdkBackend) WriteEvent(ctx context.Context, event WriteEvent) (rv int64, err error) {
-	if event.Type == resourcepb.WatchEvent_ADDED {
-		// ReadResource deals with deleted values (i.e. a file exists but has generation -999).
-		resp := s.ReadResource(ctx, &resourcepb.ReadRequest{Key: event.Key})
-		if resp.Error != nil && resp.Error.Code != http.StatusNotFound {
-			return 0, GetError(resp.Error)
+// parseVersionPath returns the version stored in the object, or false when the object is not a resource version
+func (s *cdkBackend) parseVersionPath(path string) (cdkVersion, bool) {
+	v := cdkVersion{path: path}
+	parts := strings.Split(strings.TrimPrefix(path, s.root), "/")
+	if len(parts) != 5 || strings.HasPrefix(parts[0], "__") || !strings.HasSuffix(parts[4], cdkVersionSuffix) {
+		return v, false
+	}
+	v.namespace = parts[2]
+	if v.namespace == cdkClusterNamespace {
+		v.namespace = ""
+	}
+	v.name = parts[3]
+
+	fields := strings.SplitN(strings.TrimSuffix(parts[4], cdkVersionSuffix), cdkVersionSeparator, 3)
+	rv, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return v, false
+	}
+	v.rv = rv
+	if len(fields) == 3 {
+		action, err := strconv.ParseInt(fields[1], 10, 32)
+		if err != nil {
+			return v, false
 		}
-		if resp.Value != nil {
-			return 0, ErrResourceAlreadyExists
+		v.action = resourcepb.WatchEvent_Type(action)
+		v.folder, err = url.PathUnescape(fields[2])
+		if err != nil {
+			return v, false
 		}
 	}
+	return v, true
+}
 
-	// Scope the lock
-	{
-		s.mutex.Lock()
-		defer s.mutex.Unlock()
-
-		rv = s.rv.Add(1)
-		err = s.bucket.WriteAll(ctx, s.getPath(event.Key, rv), event.Value, &blob.WriterOptions{
-			ContentType: "application/json",
+// listVersions returns all versions below the prefix grouped by resource, newest first
+func (s *cdkBackend) listVersions(ctx context.Context, prefix string) (map[string][]cdkVersion, error) {
+	byResource := make(map[string][]cdkVersion)
+	iter := s.bucket.List(&blob.ListOptions{Prefix: prefix}) // recursive
+	for {
+		obj, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		v, ok := s.parseVersionPath(obj.Key)
+		if !ok {
+			continue
+		}
+		dir := obj.Key[:strings.LastIndex(obj.Key, "/")]
+		byResource[dir] = append(byResource[dir], v)
+	}
+	for _, versions := range byResource {
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i].rv > versions[j].rv
 		})
 	}
+	return byResource, nil
+}
 
-	// Async notify all subscribers
-	if s.stream != nil {
-		go func() {
-			write := &WrittenEvent{
-				Type:            event.Type,
-				Key:             event.Key,
-				PreviousRV:      event.PreviousRV,
-				Value:           event.Value,
-				Timestamp:       time.Now().UnixMilli(),
-				ResourceVersion: rv,
-			}
-			s.stream <- write
-		}()
+// listFolders returns the folders directly below the prefix
+func (s *cdkBackend) listFolders(ctx context.Context, prefix string) ([]string, error) {
+	var folders []string
+	iter := s.bucket.List(&blob.ListOptions{Prefix: prefix, Delimiter: "/"})
+	for {
+		obj, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			return folders, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if obj.IsDir {
+			folders = append(folders, obj.Key)
+		}
 	}
-	return rv, err
 }
 
-func (s *cdkBackend) ReadResource(ctx context.Context, req *resourcepb.ReadRequest) *BackendReadResponse {
-	rv := req.ResourceVersion
+// getCollectionPrefix returns the prefix for all resources matching the key
+func (s *cdkBackend) getCollectionPrefix(key *resourcepb.ResourceKey) string {
+	if key.Namespace == "" && key.Name != "" {
+		return s.getPath(key, 0) + "/"
+	}
+	prefix := s.getPath(&resourcepb.ResourceKey{
+		Group:     key.Group,
+		Resource:  key.Resource,
+		Namespace: key.Namespace,
+	}, 0) + "/"
+	if key.Name != "" {
+		prefix += key.Name + "/"
+	}
+	return prefix
+}
 
-	path := s.getPath(req.Key, rv)
-	if rv < 1 {
-		iter := s.bucket.List(&blob.ListOptions{Prefix: path + "/", Delimiter: "/"})
-		for {
-			obj, err := iter.Next(ctx)
-			if errors.Is(err, io.EOF) {
-				break
+// isDeleted checks if the version is a deletion, the value is only read for the previous layout
+func (s *cdkBackend) isDeleted(ctx context.Context, v cdkVersion) (bool, error) {
+	if v.action != resourcepb.WatchEvent_UNKNOWN {
+		return v.action == resourcepb.WatchEvent_DELETED, nil
+	}
+	raw, err := s.bucket.ReadAll(ctx, v.path)
+	if err != nil {
+		return false, err
+	}
+	return isDeletedValue(raw), nil
+}
+
+// currentRV is the max resource version that can be requested.
+// Versions are timestamps, and may have been written by other processes.
+func (s *cdkBackend) currentRV() int64 {
+	s.rvMutex.Lock()
+	defer s.rvMutex.Unlock()
+	return max(s.lastRV, time.Now().UnixMicro())
+}
+
+// Init implements LifecycleHooks.
+func (s *cdkBackend) Init(_ context.Context) error {
+	return nil
+}
+
+// Stop implements LifecycleHooks.
+func (s *cdkBackend) Stop(ctx context.Context) error {
+	s.cancel()
+	s.wg.Wait()
+	return s.releaseWriterSlot(ctx)
+}
+
+// GetResourceStats implements Backend.
+// Only the collections in the namespace are listed, the change log, leases and blobs are skipped.
+func (s *cdkBackend) GetResourceStats(ctx context.Context, namespace string, minCount int) ([]ResourceStats, error) {
+	ctx, span := s.tracer.Start(ctx, "cdk.GetResourceStats")
+	defer span.End()
+
+	groups, err := s.listFolders(ctx, s.root)
+	if err != nil {
+		return nil, err
+	}
+	resources := make(map[string][]cdkVersion)
+	for _, group := range groups {
+		if strings.HasPrefix(strings.TrimPrefix(group, s.root), "__") {
+			continue
+		}
+		collections, err := s.listFolders(ctx, group)
+		if err != nil {
+			return nil, err
+		}
+		for _, collection := range collections {
+			prefix := collection
+			if namespace != "" {
+				prefix += namespace + "/"
 			}
-			if strings.HasSuffix(obj.Key, ".json") {
-				idx := strings.LastIndex(obj.Key, "/") + 1
-				edx := strings.LastIndex(obj.Key, ".")
-				if idx > 0 {
-					v, err := strconv.ParseInt(obj.Key[idx:edx], 10, 64)
-					if err == nil && v > rv {
-						rv = v
-						path = obj.Key // find the path with biggest resource version
-					}
-				}
+			versions, err := s.listVersions(ctx, prefix)
+			if err != nil {
+				return nil, err
+			}
+			for dir, v := range versions {
+				resources[dir] = v
 			}
 		}
 	}
 
-	raw, err := s.bucket.ReadAll(ctx, path)
-	if raw == nil && req.ResourceVersion > 0 {
-		if req.ResourceVersion > s.rv.Load() {
-			return &BackendReadResponse{
-				Error: &resourcepb.ErrorResult{
-					Code:    http.StatusGatewayTimeout,
-					Reason:  string(metav1.StatusReasonTimeout), // match etcd behavior
-					Message: "ResourceVersion is larger than max",
-					Details: &resourcepb.ErrorDetails{
-						Causes: []*resourcepb.ErrorCause{
-							{
-								Reason:  string(metav1.CauseTypeResourceVersionTooLarge),
-								Message: fmt.Sprintf("requested: %d, current %d", req.ResourceVersion, s.rv.Load()),
-							},
-						},
-					},
-				},
-			}
+	byKey := make(map[NamespacedResource]*ResourceStats)
+	for dir, versions := range resources {
+		parts := strings.Split(strings.TrimPrefix(dir, s.root), "/")
+		latest := versions[0]
+		if namespace != "" && latest.namespace != namespace {
+			continue
+		}
+		key := NamespacedResource{Namespace: latest.namespace, Group: parts[0], Resource: parts[1]}
+		stats, ok := byKey[key]
+		if !ok {
+			stats = &ResourceStats{NamespacedResource: key}
+			byKey[key] = stats
+		}
+		stats.ResourceVersion = max(stats.ResourceVersion, latest.rv)
+
+		deleted, err := s.isDeleted(ctx, latest)
+		if err != nil {
+			return nil, err
+		}
+		if !deleted {
+			stats.Count++
 		}
+	}
+
+	stats := make([]ResourceStats, 0, len(byKey))
+	for _, v := range byKey {
+		if v.Count > int64(minCount) {
+			stats = append(stats, *v)
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Namespace != stats[j].Namespace {
+			return stats[i].Namespace < stats[j].Namespace
+		}
+		if stats[i].Group != stats[j].Group {
+			return stats[i].Group < stats[j].Group
+		}
+		return stats[i].Resource < stats[j].Resource
+	})
+	return stats, nil
+}
+
+func (s *cdkBackend) WriteEvent(ctx context.Context, event WriteEvent) (rv int64, err error) {
+	ctx, span := s.tracer.Start(ctx, "cdk.WriteEvent")
+	defer span.End()
+
+	if event.Key == nil || event.Key.Group == "" || event.Key.Resource == "" || event.Key.Name == "" {
+		return 0, fmt.Errorf("missing key")
+	}
+	folder := ""
+	if event.Object != nil {
+		folder = event.Object.GetFolder()
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 
-		// If the there was an explicit request, get the latest
-		rsp := s.ReadResource(ctx, &resourcepb.ReadRequest{Key: req.Key})
-		if rsp != nil && len(rsp.Value) > 0 {
-			raw = rsp.Value
-			rv = rsp.ResourceVersion
-			err = nil
+	// Another process may write the same resource in between, unconditional writes are then retried
+	var claim string
+	for attempt := 0; ; attempt++ {
+		rv, claim, err = s.claimNextVersion(ctx, event)
+		if !errors.Is(err, ErrOptimisticLockingFailed) || event.Type == resourcepb.WatchEvent_ADDED || event.PreviousRV > 0 || attempt >= 2 {
+			break
 		}
 	}
-	if err == nil && isDeletedValue(raw) {
-		raw = nil
+	if err != nil {
+		return 0, err
 	}
-	if raw == nil {
-		return &BackendReadResponse{Error: NewNotFoundError(req.Key)}
+	if err = s.bucket.WriteAll(ctx, s.getVersionPath(event.Key, rv, event.Type, folder), event.Value, &blob.WriterOptions{
+		ContentType: "application/json",
+	}); err != nil {
+		// nothing was written, so the next write does not need to wait for the claim to time out
+		if derr := s.bucket.Delete(ctx, claim); derr != nil && gcerrors.Code(derr) != gcerrors.NotFound {
+			s.log.Warn("failed to remove write claim", "path", claim, "err", derr)
+		}
+		return 0, err
 	}
-	return &BackendReadResponse{
-		Key:             req.Key,
-		Folder:          "", // TODO: implement this
+
+	// Watchers (in every process) receive the event from the change log
+	err = s.appendChangeLog(ctx, &cdkChange{
+		Type:            event.Type,
+		Namespace:       event.Key.Namespace,
+		Group:           event.Key.Group,
+		Resource:        event.Key.Resource,
+		Name:            event.Key.Name,
+		Folder:          folder,
+		PreviousRV:      event.PreviousRV,
 		ResourceVersion: rv,
-		Value:           raw,
+		Timestamp:       time.Now().UnixMilli(),
+		Value:           event.Value,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("append change log: %w", err)
+	}
+	return rv, nil
+}
+
+// claimNextVersion checks the write against the latest version, and claims the version written after it.
+// Only one process can claim the next version, so concurrent writes of the same resource are rejected.
+func (s *cdkBackend) claimNextVersion(ctx context.Context, event WriteEvent) (int64, string, error) {
+	resources, err := s.listVersions(ctx, s.getCollectionPrefix(event.Key))
+	if err != nil {
+		return 0, "", err
+	}
+	var latest int64
+	exists := false
+	if versions := resources[s.getPath(event.Key, 0)]; len(versions) > 0 {
+		latest = versions[0].rv
+		s.observeRV(latest) // keeps the versions of a resource ordered, even with clock skew between processes
+
+		deleted, err := s.isDeleted(ctx, versions[0])
+		if err != nil {
+			return 0, "", err
+		}
+		exists = !deleted
+	}
+	switch {
+	case event.Type == resourcepb.WatchEvent_ADDED:
+		if exists {
+			return 0, "", ErrResourceAlreadyExists
+		}
+	case !exists:
+		return 0, "", NewResourceNotFoundError(event.Key)
+	case event.PreviousRV > 0 && event.PreviousRV != latest:
+		return 0, "", ErrOptimisticLockingFailed
+	}
+
+	rv, err := s.nextRV()
+	if err != nil {
+		return 0, "", err
+	}
+	raw, err := json.Marshal(&cdkWriteClaim{ResourceVersion: rv, Timestamp: time.Now().UnixMilli()})
+	if err != nil {
+		return 0, "", err
+	}
+
+	// Claims of writes that never completed are skipped once they time out
+	for attempt := 0; ; attempt++ {
+		path := s.getClaimPath(event.Key, latest, attempt)
+		err = cdkCreateObject(ctx, s.bucket, path, raw)
+		if err == nil {
+			return rv, path, nil
+		}
+		if !errors.Is(err, errCDKObjectExists) {
+			return 0, "", err
+		}
+
+		abandoned, err := s.isAbandonedClaim(ctx, event.Key, path)
+		if err != nil {
+			return 0, "", err
+		}
+		if !abandoned {
+			if event.Type == resourcepb.WatchEvent_ADDED && latest == 0 {
+				return 0, "", ErrResourceAlreadyExists
+			}
+			return 0, "", ErrOptimisticLockingFailed
+		}
+	}
+}
+
+// getClaimPath returns the object claiming the version written after the previous one (0 for the first version)
+func (s *cdkBackend) getClaimPath(key *resourcepb.ResourceKey, previous int64, attempt int) string {
+	return fmt.Sprintf("%s/%d%s%d", s.getPath(key, 0), previous, cdkClaimSeparator, attempt)
+}
+
+// isAbandonedClaim checks if the claimed version was never written
+func (s *cdkBackend) isAbandonedClaim(ctx context.Context, key *resourcepb.ResourceKey, path string) (bool, error) {
+	raw, err := s.bucket.ReadAll(ctx, path)
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return false, nil // compacted, the version was written
+		}
+		return false, err
+	}
+	claim := &cdkWriteClaim{}
+	if err = json.Unmarshal(raw, claim); err != nil {
+		return false, err
+	}
+	if time.Since(time.UnixMilli(claim.Timestamp)) < cdkClaimTimeout {
+		return false, nil
+	}
+	resources, err := s.listVersions(ctx, s.getCollectionPrefix(key))
+	if err != nil {
+		return false, err
 	}
+	for _, v := range resources[s.getPath(key, 0)] {
+		if v.rv == claim.ResourceVersion {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (s *cdkBackend) ReadResource(ctx context.Context, req *resourcepb.ReadRequest) *BackendReadResponse {
+	ctx, span := s.tracer.Start(ctx, "cdk.ReadResource")
+	defer span.End()
+
+	if req.Key == nil {
+		return &BackendReadResponse{Error: AsErrorResult(fmt.Errorf("missing key"))}
+	}
+	if current := s.currentRV(); req.ResourceVersion > current {
+		return &BackendReadResponse{Error: &resourcepb.ErrorResult{
+			Code:    http.StatusGatewayTimeout,
+			Reason:  string(metav1.StatusReasonTimeout), // match etcd behavior
+			Message: "ResourceVersion is larger than max",
+			Details: &resourcepb.ErrorDetails{
+				Causes: []*resourcepb.ErrorCause{
+					{
+						Reason:  string(metav1.CauseTypeResourceVersionTooLarge),
+						Message: fmt.Sprintf("requested: %d, current %d", req.ResourceVersion, current),
+					},
+				},
+			},
+		}}
+	}
+
+	resources, err := s.listVersions(ctx, s.getCollectionPrefix(req.Key))
+	if err != nil {
+		return &BackendReadResponse{Error: AsErrorResult(err)}
+	}
+	for _, v := range resources[s.getPath(req.Key, 0)] {
+		if req.ResourceVersion > 0 && v.rv > req.ResourceVersion {
+			continue
+		}
+		if v.action == resourcepb.WatchEvent_DELETED {
+			break
+		}
+		raw, err := s.bucket.ReadAll(ctx, v.path)
+		if err != nil {
+			return &BackendReadResponse{Error: AsErrorResult(err)}
+		}
+		if isDeletedValue(raw) {
+			break
+		}
+		return &BackendReadResponse{
+			Key:             req.Key,
+			Folder:          v.folder,
+			ResourceVersion: v.rv,
+			Value:           raw,
+		}
+	}
+	return &BackendReadResponse{Error: NewNotFoundError(req.Key)}
 }
 
 func isDeletedValue(raw []byte) bool {
@@ -235,43 +653,266 @@ func isDeletedValue(raw []byte) bool {
 }
 
 func (s *cdkBackend) ListIterator(ctx context.Context, req *resourcepb.ListRequest, cb func(ListIterator) error) (int64, error) {
-	resources, err := buildTree(ctx, s, req.Options.Key)
+	ctx, span := s.tracer.Start(ctx, "cdk.ListIterator")
+	defer span.End()
+
+	if err := MigrateListRequestVersionMatch(req, s.log); err != nil {
+		return 0, err
+	}
+	if req.Options == nil || req.Options.Key.Group == "" || req.Options.Key.Resource == "" {
+		return 0, fmt.Errorf("missing group or resource")
+	}
+
+	listRV := req.ResourceVersion
+	var offset int64
+	if req.NextPageToken != "" {
+		token, err := GetContinueToken(req.NextPageToken)
+		if err != nil {
+			return 0, fmt.Errorf("get continue token: %w", err)
+		}
+		if listRV > 0 && listRV != token.ResourceVersion {
+			return 0, fmt.Errorf("resource version mismatch: %d != %d", listRV, token.ResourceVersion)
+		}
+		listRV = token.ResourceVersion
+		offset = token.StartOffset
+	}
+	if current := s.currentRV(); listRV < 1 {
+		listRV = current
+	} else if listRV > current {
+		return 0, fmt.Errorf("resource version %d is larger than max %d", listRV, current)
+	}
+
+	resources, err := s.listVersions(ctx, s.getCollectionPrefix(req.Options.Key))
 	if err != nil {
 		return 0, err
 	}
-	err = cb(resources)
-	return resources.listRV, err
+	var items []cdkVersion
+	for _, versions := range resources {
+		for _, v := range versions {
+			if v.rv <= listRV {
+				if v.action != resourcepb.WatchEvent_DELETED {
+					items = append(items, v)
+				}
+				break
+			}
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].namespace != items[j].namespace {
+			return items[i].namespace < items[j].namespace
+		}
+		return items[i].name < items[j].name
+	})
+
+	iter := &cdkListIterator{
+		ctx:         ctx,
+		bucket:      s.bucket,
+		items:       items[min(offset, int64(len(items))):],
+		index:       -1, // must call next first
+		offset:      offset,
+		listRV:      listRV,
+		skipDeleted: true,
+	}
+	return listRV, cb(iter)
 }
 
+// ListHistory returns the versions of a resource, or the deleted resources when listing the trash
 func (s *cdkBackend) ListHistory(ctx context.Context, req *resourcepb.ListRequest, cb func(ListIterator) error) (int64, error) {
-	return 0, fmt.Errorf("listing from history not supported in CDK backend")
+	ctx, span := s.tracer.Start(ctx, "cdk.ListHistory")
+	defer span.End()
+
+	if err := MigrateListRequestVersionMatch(req, s.log); err != nil {
+		return 0, err
+	}
+	if req.Options == nil || req.Options.Key.Group == "" || req.Options.Key.Resource == "" {
+		return 0, fmt.Errorf("missing group or resource")
+	}
+	key := req.Options.Key
+	trash := req.Source == resourcepb.ListRequest_TRASH
+
+	// Ascending when fetching everything newer than a version, newest first otherwise
+	sortAsc := req.GetVersionMatchV2() == resourcepb.ResourceVersionMatchV2_NotOlderThan
+	var startRV, minRV, exactRV int64
+	if req.NextPageToken != "" {
+		token, err := GetContinueToken(req.NextPageToken)
+		if err != nil {
+			return 0, fmt.Errorf("get continue token: %w", err)
+		}
+		startRV = token.ResourceVersion
+		sortAsc = token.SortAscending
+	}
+	switch req.GetVersionMatchV2() {
+	case resourcepb.ResourceVersionMatchV2_Exact:
+		if req.ResourceVersion <= 0 {
+			return 0, fmt.Errorf("expecting an explicit resource version query when using Exact matching")
+		}
+		exactRV = req.ResourceVersion
+	case resourcepb.ResourceVersionMatchV2_NotOlderThan:
+		if req.ResourceVersion > 0 {
+			minRV = req.ResourceVersion
+		}
+	}
+	include := func(v cdkVersion) bool {
+		if startRV > 0 && ((sortAsc && v.rv <= startRV) || (!sortAsc && v.rv >= startRV)) {
+			return false
+		}
+		if minRV > 0 && v.rv < minRV {
+			return false
+		}
+		return exactRV == 0 || v.rv == exactRV
+	}
+
+	listRV := s.currentRV()
+	resources, err := s.listVersions(ctx, s.getCollectionPrefix(key))
+	if err != nil {
+		return 0, err
+	}
+
+	// Without an explicit range, only the versions after the most recent deletion are returned
+	if minRV == 0 && exactRV == 0 && !trash {
+		for _, versions := range resources {
+			for _, v := range versions {
+				deleted, err := s.isDeleted(ctx, v)
+				if err != nil {
+					return 0, err
+				}
+				if deleted {
+					minRV = max(minRV, v.rv+1)
+					break
+				}
+			}
+		}
+	}
+
+	var items []cdkVersion
+	for _, versions := range resources {
+		if !trash {
+			for _, v := range versions {
+				if include(v) {
+					items = append(items, v)
+				}
+			}
+			continue
+		}
+
+		// The trash holds the last deletion of resources that were not recreated
+		deleted, err := s.isDeleted(ctx, versions[0])
+		if err != nil {
+			return 0, err
+		}
+		if deleted && include(versions[0]) {
+			items = append(items, versions[0])
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if sortAsc {
+			return items[i].rv < items[j].rv
+		}
+		return items[i].rv > items[j].rv
+	})
+
+	iter := &cdkListIterator{
+		ctx:          ctx,
+		bucket:       s.bucket,
+		items:        items,
+		index:        -1,
+		listRV:       listRV,
+		sortAsc:      sortAsc,
+		useCurrentRV: true,
+	}
+	return listRV, cb(iter)
 }
 
-func (s *cdkBackend) WatchWriteEvents(ctx context.Context) (<-chan *WrittenEvent, error) {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+// BlobSupport is written below the root folder
+func (s *cdkBackend) SupportsSignedURLs() bool {
+	return s.blobs.SupportsSignedURLs()
+}
 
-	if s.broadcaster == nil {
-		var err error
-		s.broadcaster, err = NewBroadcaster(context.Background(), func(c chan<- *WrittenEvent) error {
-			s.stream = c
-			return nil
-		})
+func (s *cdkBackend) PutResourceBlob(ctx context.Context, req *resourcepb.PutBlobRequest) (*resourcepb.PutBlobResponse, error) {
+	return s.blobs.PutResourceBlob(ctx, req)
+}
+
+func (s *cdkBackend) GetResourceBlob(ctx context.Context, key *resourcepb.ResourceKey, info *utils.BlobInfo, mustProxy bool) (*resourcepb.GetBlobResponse, error) {
+	return s.blobs.GetResourceBlob(ctx, key, info, mustProxy)
+}
+
+// Compact implements CDKBackend.
+func (s *cdkBackend) Compact(ctx context.Context) error {
+	ctx, span := s.tracer.Start(ctx, "cdk.Compact")
+	defer span.End()
+
+	if s.historyRetention > 0 {
+		cutoff := time.Now().Add(-s.historyRetention).UnixMicro()
+		resources, err := s.listVersions(ctx, s.root)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		removed := 0
+		for _, versions := range resources {
+			for _, v := range versions[1:] {
+				if v.rv >= cutoff {
+					continue
+				}
+				if err := s.bucket.Delete(ctx, v.path); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+					return err
+				}
+				removed++
+			}
+		}
+		if removed > 0 {
+			s.log.Info("removed old versions", "count", removed)
 		}
 	}
-	return s.broadcaster.Subscribe(ctx)
+	if err := s.compactClaims(ctx, time.Now().Add(-s.changeLogRetention)); err != nil {
+		return err
+	}
+	return s.compactChangeLog(ctx, time.Now().Add(-s.changeLogRetention))
 }
 
-// group > resource > namespace > name > versions
-type cdkResource struct {
-	prefix   string
-	versions []cdkVersion
+// compactClaims removes the claims of the next version written before the cutoff.
+// By then the version was written, or the claim was abandoned and timed out.
+func (s *cdkBackend) compactClaims(ctx context.Context, cutoff time.Time) error {
+	groups, err := s.listFolders(ctx, s.root)
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		if strings.HasPrefix(strings.TrimPrefix(group, s.root), "__") {
+			continue
+		}
+		iter := s.bucket.List(&blob.ListOptions{Prefix: group}) // recursive
+		for {
+			obj, err := iter.Next(ctx)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if !strings.Contains(obj.Key[strings.LastIndex(obj.Key, "/")+1:], cdkClaimSeparator) || !obj.ModTime.Before(cutoff) {
+				continue
+			}
+			if err = s.bucket.Delete(ctx, obj.Key); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+				return err
+			}
+		}
+	}
+	return nil
 }
-type cdkVersion struct {
-	rv  int64
-	key string
+
+func (s *cdkBackend) runCompaction(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Compact(ctx); err != nil {
+				s.log.Error("compaction failed", "err", err)
+			}
+		}
+	}
 }
 
 type cdkListIterator struct {
@@ -279,38 +920,47 @@ type cdkListIterator struct {
 	ctx    context.Context
 	err    error
 
-	listRV    int64
-	resources []cdkResource
-	index     int
+	items []cdkVersion
+	index int
+
+	// Values of deletions written with the previous layout are skipped
+	skipDeleted bool
 
-	currentRV  int64
-	currentKey string
-	currentVal []byte
+	// pagination
+	offset  int64
+	listRV  int64
+	sortAsc bool
+
+	// When true, the continue token starts after the current item rather than the offset
+	useCurrentRV bool
+
+	current *cdkVersion
+	value   []byte
 }
 
 // Next implements ListIterator.
 func (c *cdkListIterator) Next() bool {
-	if c.err != nil {
-		return false
-	}
 	for {
-		c.currentVal = nil
-		c.index += 1
-		if c.index >= len(c.resources) {
+		if c.err != nil {
+			return false
+		}
+		if c.err = c.ctx.Err(); c.err != nil {
 			return false
 		}
 
-		item := c.resources[c.index]
-		latest := item.versions[0]
-		raw, err := c.bucket.ReadAll(c.ctx, latest.key)
-		if err != nil {
-			c.err = err
+		c.index++
+		if c.index >= len(c.items) {
+			c.current = nil
+			c.value = nil
+			return false
+		}
+		c.offset++
+		c.current = &c.items[c.index]
+		c.value, c.err = c.bucket.ReadAll(c.ctx, c.current.path)
+		if c.err != nil {
 			return false
 		}
-		if !isDeletedValue(raw) {
-			c.currentRV = latest.rv
-			c.currentKey = latest.key
-			c.currentVal = raw
+		if !c.skipDeleted || !isDeletedValue(c.value) {
 			return true
 		}
 	}
@@ -323,85 +973,53 @@ func (c *cdkListIterator) Error() error {
 
 // ResourceVersion implements ListIterator.
 func (c *cdkListIterator) ResourceVersion() int64 {
-	return c.currentRV
+	if c.current == nil {
+		return 0
+	}
+	return c.current.rv
 }
 
 // Value implements ListIterator.
 func (c *cdkListIterator) Value() []byte {
-	return c.currentVal
+	return c.value
 }
 
 // ContinueToken implements ListIterator.
 func (c *cdkListIterator) ContinueToken() string {
-	return fmt.Sprintf("index:%d/key:%s", c.index, c.currentKey)
+	if c.useCurrentRV {
+		return ContinueToken{
+			ResourceVersion: c.ResourceVersion(),
+			SortAscending:   c.sortAsc,
+		}.String()
+	}
+	return ContinueToken{
+		ResourceVersion: c.listRV,
+		StartOffset:     c.offset,
+	}.String()
 }
 
 // Name implements ListIterator.
 func (c *cdkListIterator) Name() string {
-	return c.currentKey // TODO (parse name from key)
+	if c.current == nil {
+		return ""
+	}
+	return c.current.name
 }
 
 // Namespace implements ListIterator.
 func (c *cdkListIterator) Namespace() string {
-	return c.currentKey // TODO (parse namespace from key)
+	if c.current == nil {
+		return ""
+	}
+	return c.current.namespace
 }
 
+// Folder implements ListIterator.
 func (c *cdkListIterator) Folder() string {
-	return "" // TODO: implement this
+	if c.current == nil {
+		return ""
+	}
+	return c.current.folder
 }
 
 var _ ListIterator = (*cdkListIterator)(nil)
-
-func buildTree(ctx context.Context, s *cdkBackend, key *resourcepb.ResourceKey) (*cdkListIterator, error) {
-	byPrefix := make(map[string]*cdkResource)
-	path := s.getPath(key, 0)
-	iter := s.bucket.List(&blob.ListOptions{Prefix: path, Delimiter: ""}) // "" is recursive
-	for {
-		obj, err := iter.Next(ctx)
-		if errors.Is(err, io.EOF) {
-			break
-		}
-		if strings.HasSuffix(obj.Key, ".json") {
-			idx := strings.LastIndex(obj.Key, "/") + 1
-			edx := strings.LastIndex(obj.Key, ".")
-			if idx > 0 {
-				rv, err := strconv.ParseInt(obj.Key[idx:edx], 10, 64)
-				if err == nil {
-					prefix := obj.Key[:idx]
-					res, ok := byPrefix[prefix]
-					if !ok {
-						res = &cdkResource{prefix: prefix}
-						byPrefix[prefix] = res
-					}
-
-					res.versions = append(res.versions, cdkVersion{
-						rv:  rv,
-						key: obj.Key,
-					})
-				}
-			}
-		}
-	}
-
-	// Now sort all versions
-	resources := make([]cdkResource, 0, len(byPrefix))
-	for _, res := range byPrefix {
-		sort.Slice(res.versions, func(i, j int) bool {
-			return res.versions[i].rv > res.versions[j].rv
-		})
-		resources = append(resources, *res)
-	}
-	sort.Slice(resources, func(i, j int) bool {
-		a := resources[i].prefix
-		b := resources[j].prefix
-		return a < b
-	})
-
-	return &cdkListIterator{
-		ctx:       ctx,
-		bucket:    s.bucket,
-		resources: resources,
-		listRV:    s.rv.Load(),
-		index:     -1, // must call next first
-	}, nil
-}
@@ -0,0 +1,212 @@
+package resource_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob/memblob"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+	unitest "github.com/grafana/grafana/pkg/storage/unified/testing"
+)
+
+func TestIntegrationCDKStorageBackend(t *testing.T) {
+	t.Run("memory bucket", func(t *testing.T) {
+		unitest.RunStorageBackendTest(t, func(ctx context.Context) resource.StorageBackend {
+			backend, err := resource.NewCDKBackend(ctx, resource.CDKBackendOptions{
+				Bucket: memblob.OpenBucket(nil),
+			})
+			require.NoError(t, err)
+			return backend
+		}, nil)
+	})
+
+	t.Run("file bucket", func(t *testing.T) {
+		unitest.RunStorageBackendTest(t, func(ctx context.Context) resource.StorageBackend {
+			bucket, err := resource.OpenBlobBucket(ctx, "file://"+filepath.ToSlash(t.TempDir())+"?create_dir=1")
+			require.NoError(t, err)
+			backend, err := resource.NewCDKBackend(ctx, resource.CDKBackendOptions{
+				Bucket: bucket,
+			})
+			require.NoError(t, err)
+			return backend
+		}, nil)
+	})
+}
+
+func TestCDKBackendMultipleProcesses(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bucket := memblob.OpenBucket(nil)
+	open := func() resource.CDKBackend {
+		backend, err := resource.NewCDKBackend(ctx, resource.CDKBackendOptions{
+			Bucket:            bucket,
+			WatchPollInterval: 10 * time.Millisecond,
+		})
+		require.NoError(t, err)
+		return backend
+	}
+	a := open()
+	b := open()
+
+	events, err := a.WatchWriteEvents(ctx)
+	require.NoError(t, err)
+
+	rv1 := writeCDKEvent(t, b, resourcepb.WatchEvent_ADDED, "item", 0)
+	rv2 := writeCDKEvent(t, a, resourcepb.WatchEvent_MODIFIED, "item", rv1)
+	require.Greater(t, rv2, rv1)
+
+	// Both writes are visible in the other process
+	for _, expected := range []int64{rv1, rv2} {
+		select {
+		case ev := <-events:
+			require.Equal(t, expected, ev.ResourceVersion)
+			require.Equal(t, "item", ev.Key.Name)
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for event")
+		}
+	}
+	rsp := b.ReadResource(ctx, &resourcepb.ReadRequest{Key: cdkTestKey("item")})
+	require.Nil(t, rsp.Error)
+	require.Equal(t, rv2, rsp.ResourceVersion)
+
+	// Writing with an outdated version fails
+	_, err = b.WriteEvent(ctx, resource.WriteEvent{
+		Type:       resourcepb.WatchEvent_MODIFIED,
+		Key:        cdkTestKey("item"),
+		Value:      []byte(`{}`),
+		PreviousRV: rv1,
+	})
+	require.ErrorIs(t, err, resource.ErrOptimisticLockingFailed)
+}
+
+func TestCDKBackendConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	bucket := memblob.OpenBucket(nil)
+	a, err := resource.NewCDKBackend(ctx, resource.CDKBackendOptions{Bucket: bucket})
+	require.NoError(t, err)
+	b, err := resource.NewCDKBackend(ctx, resource.CDKBackendOptions{Bucket: bucket})
+	require.NoError(t, err)
+
+	rv := writeCDKEvent(t, a, resourcepb.WatchEvent_ADDED, "item", 0)
+
+	// Only one of the writes based on the same version is accepted
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, backend := range []resource.CDKBackend{a, b} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = backend.WriteEvent(ctx, resource.WriteEvent{
+				Type:       resourcepb.WatchEvent_MODIFIED,
+				Key:        cdkTestKey("item"),
+				Value:      []byte(`{}`),
+				PreviousRV: rv,
+			})
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			require.ErrorIs(t, err, resource.ErrOptimisticLockingFailed)
+			failed++
+		}
+	}
+	require.Equal(t, 1, failed)
+}
+
+func TestCDKBackendWriterSlots(t *testing.T) {
+	ctx := context.Background()
+	bucket := memblob.OpenBucket(nil)
+	open := func() (resource.CDKBackend, error) {
+		return resource.NewCDKBackend(ctx, resource.CDKBackendOptions{Bucket: bucket})
+	}
+
+	backends := make([]resource.CDKBackend, 0, 16)
+	for range 16 {
+		backend, err := open()
+		require.NoError(t, err)
+		backends = append(backends, backend)
+	}
+	_, err := open()
+	require.ErrorContains(t, err, "writer slots are in use")
+
+	// Stopping a backend releases its slot, and it can no longer write
+	require.NoError(t, backends[0].Stop(ctx))
+	_, err = backends[0].WriteEvent(ctx, resource.WriteEvent{
+		Type:  resourcepb.WatchEvent_ADDED,
+		Key:   cdkTestKey("item"),
+		Value: []byte(`{}`),
+	})
+	require.Error(t, err)
+
+	backend, err := open()
+	require.NoError(t, err)
+	writeCDKEvent(t, backend, resourcepb.WatchEvent_ADDED, "item", 0)
+
+	for _, backend := range append(backends[1:], backend) {
+		require.NoError(t, backend.Stop(ctx))
+	}
+}
+
+func TestCDKBackendCompaction(t *testing.T) {
+	ctx := context.Background()
+	backend, err := resource.NewCDKBackend(ctx, resource.CDKBackendOptions{
+		Bucket:             memblob.OpenBucket(nil),
+		HistoryRetention:   time.Microsecond,
+		CompactionInterval: -1, // only compact explicitly
+	})
+	require.NoError(t, err)
+
+	rv := writeCDKEvent(t, backend, resourcepb.WatchEvent_ADDED, "item", 0)
+	rv = writeCDKEvent(t, backend, resourcepb.WatchEvent_MODIFIED, "item", rv)
+	rv = writeCDKEvent(t, backend, resourcepb.WatchEvent_MODIFIED, "item", rv)
+	time.Sleep(time.Millisecond)
+	require.NoError(t, backend.Compact(ctx))
+
+	var history []int64
+	_, err = backend.ListHistory(ctx, &resourcepb.ListRequest{
+		Options: &resourcepb.ListOptions{Key: cdkTestKey("item")},
+	}, func(iter resource.ListIterator) error {
+		for iter.Next() {
+			history = append(history, iter.ResourceVersion())
+		}
+		return iter.Error()
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int64{rv}, history)
+
+	rsp := backend.ReadResource(ctx, &resourcepb.ReadRequest{Key: cdkTestKey("item")})
+	require.Nil(t, rsp.Error)
+	require.Equal(t, rv, rsp.ResourceVersion)
+}
+
+func cdkTestKey(name string) *resourcepb.ResourceKey {
+	return &resourcepb.ResourceKey{
+		Namespace: "default",
+		Group:     "dashboard.grafana.app",
+		Resource:  "dashboards",
+		Name:      name,
+	}
+}
+
+func writeCDKEvent(t *testing.T, backend resource.StorageBackend, action resourcepb.WatchEvent_Type, name string, previous int64) int64 {
+	t.Helper()
+	rv, err := backend.WriteEvent(context.Background(), resource.WriteEvent{
+		Type:       action,
+		Key:        cdkTestKey(name),
+		Value:      []byte(fmt.Sprintf(`{"metadata":{"name":%q,"generation":%d}}`, name, previous)),
+		PreviousRV: previous,
+	})
+	require.NoError(t, err)
+	return rv
+}
@@ -5,7 +5,9 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"mime"
 	"strings"
 	"time"
@@ -14,6 +16,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"go.opentelemetry.io/otel/trace/noop"
 	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
 
 	"github.com/grafana/grafana/pkg/apimachinery/utils"
 	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
@@ -75,15 +78,29 @@ func NewCDKBlobSupport(ctx context.Context, opts CDKBlobSupportOptions) (BlobSup
 		root:        opts.RootFolder,
 		cansignurls: false, // TODO depends on the implementation
 		expiration:  opts.URLExpiration,
+		now:         time.Now,
 	}, nil
 }
 
+const (
+	// The chunks without references are kept this long, a blob being written may still reference them
+	blobGarbageGracePeriod = time.Hour
+
+	// How often a blob waiting for the collection of one of its chunks checks again
+	blobGarbagePollInterval = 100 * time.Millisecond
+)
+
+var _ BlobGarbageCollector = (*cdkBlobSupport)(nil)
+
 type cdkBlobSupport struct {
 	tracer      trace.Tracer
 	bucket      CDKBucket
 	root        string
 	cansignurls bool
 	expiration  time.Duration
+
+	// for testing
+	now func() time.Time
 }
 
 func (s *cdkBlobSupport) getBlobPath(key *resourcepb.ResourceKey, info *utils.BlobInfo) (string, error) {
@@ -116,6 +133,11 @@ func (s *cdkBlobSupport) getBlobPath(key *resourcepb.ResourceKey, info *utils.Bl
 	buffer.WriteString("/")
 	buffer.WriteString(info.UID)
 
+	// Without a mime type, the path is a prefix for the blob (see findBlobPath)
+	if info.MimeType == "" {
+		return buffer.String(), nil
+	}
+
 	ext, err := mime.ExtensionsByType(info.MimeType)
 	if err != nil {
 		return "", err
@@ -126,6 +148,29 @@ func (s *cdkBlobSupport) getBlobPath(key *resourcepb.ResourceKey, info *utils.Bl
 	return buffer.String(), nil
 }
 
+// findBlobPath looks up a blob when only the UID is known.
+// The extension in the path is used to detect the content type.
+func (s *cdkBlobSupport) findBlobPath(ctx context.Context, key *resourcepb.ResourceKey, info *utils.BlobInfo) (string, string, error) {
+	prefix, err := s.getBlobPath(key, &utils.BlobInfo{UID: info.UID})
+	if err != nil {
+		return "", "", err
+	}
+	found, _, err := s.bucket.ListPage(ctx, blob.FirstPageToken, 10, &blob.ListOptions{
+		Prefix:    prefix,
+		Delimiter: "/",
+	})
+	if err != nil {
+		return "", "", err
+	}
+	for _, obj := range found {
+		ext := strings.TrimPrefix(obj.Key, prefix)
+		if ext == "" || (strings.HasPrefix(ext, ".") && !strings.Contains(ext, "/")) {
+			return obj.Key, mime.TypeByExtension(ext), nil
+		}
+	}
+	return "", "", fmt.Errorf("blob not found: %s", info.UID)
+}
+
 func (s *cdkBlobSupport) SupportsSignedURLs() bool {
 	return s.cansignurls
 }
@@ -153,39 +198,143 @@ func (s *cdkBlobSupport) PutResourceBlob(ctx context.Context, req *resourcepb.Pu
 		return nil, fmt.Errorf("missing content value")
 	}
 
-	// Write the value
-	err = s.bucket.WriteAll(ctx, path, req.Value, &blob.WriterOptions{
+	// Write the chunks, then the manifest that references them
+	chunks, hashes := uniqueChunks(req.Value)
+	for hash, chunk := range chunks {
+		if err = s.putChunk(ctx, req.Resource.Namespace, hash, info.UID, chunk); err != nil {
+			return nil, err
+		}
+	}
+	manifest, err := encodeBlobManifest(blobManifest{
+		Size:   int64(len(req.Value)),
+		Chunks: hashes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = s.bucket.WriteAll(ctx, path, manifest, &blob.WriterOptions{
 		ContentType: req.ContentType,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	attrs, err := s.bucket.Attributes(ctx, path)
+	h := md5.New()
+	_, _ = h.Write(req.Value)
+	rsp.Size = int64(len(req.Value))
+	rsp.Hash = hex.EncodeToString(h.Sum(nil))
+	return rsp, err
+}
+
+// The chunks are only shared within a namespace
+func chunkNamespace(namespace string) string {
+	if namespace == "" {
+		return "__cluster__"
+	}
+	return namespace
+}
+
+func (s *cdkBlobSupport) chunkPath(namespace string, hash string) string {
+	return s.root + "chunks/" + chunkNamespace(namespace) + "/" + hash[:2] + "/" + hash
+}
+
+// Each blob referencing a chunk writes its own reference, so the references never need a read-modify-write
+func (s *cdkBlobSupport) chunkRefPath(namespace string, hash string, uid string) string {
+	return s.root + "chunk-refs/" + chunkNamespace(namespace) + "/" + hash + "/" + uid
+}
+
+// Written by the garbage collection before it checks the references of a chunk, and removed once the chunk is deleted
+func (s *cdkBlobSupport) chunkCollectPath(namespace string, hash string) string {
+	return s.root + "chunk-collect/" + chunkNamespace(namespace) + "/" + hash
+}
+
+// putChunk adds the reference of the blob to the chunk, and writes the chunk when missing.
+// The reference is written before checking if the chunk is being collected, and the collection
+// writes its marker before checking the references, so at least one of them sees the other.
+// When the chunk is being collected, the blob waits for the collection to finish and writes the chunk again.
+func (s *cdkBlobSupport) putChunk(ctx context.Context, namespace string, hash string, uid string, chunk []byte) error {
+	if err := s.bucket.WriteAll(ctx, s.chunkRefPath(namespace, hash, uid), []byte{}, nil); err != nil {
+		return err
+	}
+	collected, err := s.waitChunkCollection(ctx, namespace, hash)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if !collected {
+		_, err = s.bucket.Attributes(ctx, s.chunkPath(namespace, hash))
+		if err == nil {
+			return nil
+		}
+		if gcerrors.Code(err) != gcerrors.NotFound {
+			return err
+		}
 	}
-	rsp.Size = attrs.Size
+	return s.bucket.WriteAll(ctx, s.chunkPath(namespace, hash), chunk, &blob.WriterOptions{
+		ContentType: "application/octet-stream",
+	})
+}
 
-	// Set the MD5 hash if missing
-	if len(attrs.MD5) == 0 {
-		h := md5.New()
-		_, _ = h.Write(req.Value)
-		attrs.MD5 = h.Sum(nil)
+// waitChunkCollection waits until the chunk is no longer being collected.
+// Returns true when a collection was running, the chunk may then be gone.
+// The markers older than the grace period are left by a collection that did not finish, and are ignored.
+func (s *cdkBlobSupport) waitChunkCollection(ctx context.Context, namespace string, hash string) (bool, error) {
+	collected := false
+	for {
+		attrs, err := s.bucket.Attributes(ctx, s.chunkCollectPath(namespace, hash))
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return collected, nil
+		}
+		if err != nil {
+			return collected, err
+		}
+		if s.now().Sub(attrs.ModTime) > blobGarbageGracePeriod {
+			return true, nil
+		}
+		collected = true
+		select {
+		case <-ctx.Done():
+			return collected, ctx.Err()
+		case <-time.After(blobGarbagePollInterval):
+		}
 	}
-	rsp.Hash = hex.EncodeToString(attrs.MD5[:])
-	return rsp, err
+}
+
+// readChunks joins the chunks of the manifest
+func (s *cdkBlobSupport) readChunks(ctx context.Context, namespace string, m *blobManifest) ([]byte, error) {
+	value := make([]byte, 0, m.Size)
+	for _, hash := range m.Chunks {
+		chunk, err := s.bucket.ReadAll(ctx, s.chunkPath(namespace, hash))
+		if err != nil {
+			return nil, fmt.Errorf("read chunk %s: %w", hash, err)
+		}
+		if BlobChunkHash(chunk) != hash {
+			return nil, fmt.Errorf("corrupted chunk %s", hash)
+		}
+		value = append(value, chunk...)
+	}
+	return value, nil
 }
 
 func (s *cdkBlobSupport) GetResourceBlob(ctx context.Context, resource *resourcepb.ResourceKey, info *utils.BlobInfo,
 	mustProxy bool) (*resourcepb.GetBlobResponse, error) {
+	rsp := &resourcepb.GetBlobResponse{ContentType: info.ContentType()}
 	path, err := s.getBlobPath(resource, info)
+	if err == nil && info.MimeType == "" {
+		path, rsp.ContentType, err = s.findBlobPath(ctx, resource, info)
+	}
 	if err != nil {
 		return nil, err
 	}
-	rsp := &resourcepb.GetBlobResponse{ContentType: info.ContentType()}
 	if mustProxy || !s.cansignurls {
 		rsp.Value, err = s.bucket.ReadAll(ctx, path)
+		if err != nil {
+			return rsp, err
+		}
+		m, err := decodeBlobManifest(rsp.Value)
+		if err != nil || m == nil {
+			return rsp, err
+		}
+		rsp.Value, err = s.readChunks(ctx, resource.Namespace, m)
 		return rsp, err
 	}
 	rsp.Url, err = s.bucket.SignedURL(ctx, path, &blob.SignedURLOptions{
@@ -195,3 +344,92 @@ func (s *cdkBlobSupport) GetResourceBlob(ctx context.Context, resource *resource
 	})
 	return rsp, err
 }
+
+// DeleteResourceBlob implements BlobGarbageCollector.
+// The chunks are removed by the next garbage collection when no other blob references them.
+func (s *cdkBlobSupport) DeleteResourceBlob(ctx context.Context, key *resourcepb.ResourceKey, uid string) error {
+	ctx, span := s.tracer.Start(ctx, "cdk.DeleteResourceBlob")
+	defer span.End()
+
+	path, _, err := s.findBlobPath(ctx, key, &utils.BlobInfo{UID: uid})
+	if err != nil {
+		return err
+	}
+	value, err := s.bucket.ReadAll(ctx, path)
+	if err != nil {
+		return err
+	}
+	m, err := decodeBlobManifest(value)
+	if err != nil {
+		return err
+	}
+	if m != nil {
+		for _, hash := range m.Chunks {
+			err = s.bucket.Delete(ctx, s.chunkRefPath(key.Namespace, hash, uid))
+			if err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+				return err
+			}
+		}
+	}
+	return s.bucket.Delete(ctx, path)
+}
+
+// CollectBlobGarbage implements BlobGarbageCollector.
+// Only the chunks written before the grace period are removed, the recent ones may be referenced by a blob being written.
+func (s *cdkBlobSupport) CollectBlobGarbage(ctx context.Context) (int64, error) {
+	ctx, span := s.tracer.Start(ctx, "cdk.CollectBlobGarbage")
+	defer span.End()
+
+	var removed int64
+	before := s.now().Add(-blobGarbageGracePeriod)
+	prefix := s.root + "chunks/"
+	iter := s.bucket.List(&blob.ListOptions{Prefix: prefix})
+	for {
+		obj, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			return removed, nil
+		}
+		if err != nil {
+			return removed, err
+		}
+		if obj.IsDir || !obj.ModTime.Before(before) {
+			continue
+		}
+		// chunks/{namespace}/{prefix}/{hash}
+		parts := strings.Split(strings.TrimPrefix(obj.Key, prefix), "/")
+		if len(parts) != 3 {
+			continue
+		}
+		ok, err := s.collectChunk(ctx, parts[0], parts[2])
+		if err != nil {
+			return removed, err
+		}
+		if ok {
+			removed++
+		}
+	}
+}
+
+// collectChunk removes the chunk when no blob references it.
+// The marker is written before checking the references, a blob adding a reference at the same time waits for it to be removed.
+func (s *cdkBlobSupport) collectChunk(ctx context.Context, namespace string, hash string) (bool, error) {
+	marker := s.chunkCollectPath(namespace, hash)
+	if err := s.bucket.WriteAll(ctx, marker, []byte{}, nil); err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = s.bucket.Delete(context.WithoutCancel(ctx), marker)
+	}()
+
+	refs, _, err := s.bucket.ListPage(ctx, blob.FirstPageToken, 1, &blob.ListOptions{
+		Prefix: s.root + "chunk-refs/" + namespace + "/" + hash + "/",
+	})
+	if err != nil || len(refs) > 0 {
+		return false, err
+	}
+	err = s.bucket.Delete(ctx, s.chunkPath(namespace, hash))
+	if err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+		return false, err
+	}
+	return err == nil, nil
+}
@@ -1,15 +1,21 @@
 package resource
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand/v2"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/apimachinery/utils"
 	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
 
 	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob"
 	"gocloud.dev/blob/fileblob"
 	"gocloud.dev/blob/memblob"
 )
@@ -64,5 +70,181 @@ func TestCDKBlobStore(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, raw, found.Value)
 		require.Equal(t, "application/json", found.ContentType)
+
+		// The blob can be found without the mime type
+		found, err = store.GetResourceBlob(ctx, key, &utils.BlobInfo{UID: rsp.Uid}, true)
+		require.NoError(t, err)
+		require.Equal(t, raw, found.Value)
+		require.Equal(t, "application/json", found.ContentType)
+
+		_, err = store.GetResourceBlob(ctx, key, &utils.BlobInfo{UID: "missing"}, true)
+		require.Error(t, err)
+	})
+	t.Run("shares the chunks between blobs", func(t *testing.T) {
+		cdk := store.(*cdkBlobSupport)
+		key := &resourcepb.ResourceKey{
+			Group:     "dashboard.grafana.app",
+			Resource:  "dashboards",
+			Namespace: "default",
+			Name:      "shared",
+		}
+		v1 := randomBlob(256 * 1024)
+		v2 := append(bytes.Clone(v1[:100*1024]), append([]byte("changed panel"), v1[100*1024:]...)...)
+
+		put := func(value []byte) string {
+			rsp, err := store.PutResourceBlob(ctx, &resourcepb.PutBlobRequest{
+				Resource:    key,
+				Method:      resourcepb.PutBlobRequest_GRPC,
+				ContentType: "application/json",
+				Value:       value,
+			})
+			require.NoError(t, err)
+			require.Equal(t, int64(len(value)), rsp.Size)
+			return rsp.Uid
+		}
+		uid1 := put(v1)
+		chunks1 := countObjects(t, bucket, "chunks/")
+		uid2 := put(v2)
+		chunks2 := countObjects(t, bucket, "chunks/")
+		require.Equal(t, len(onlyIn(v2, v1)), chunks2-chunks1, "only the changed chunks are written")
+
+		for uid, value := range map[string][]byte{uid1: v1, uid2: v2} {
+			found, err := store.GetResourceBlob(ctx, key, &utils.BlobInfo{UID: uid}, true)
+			require.NoError(t, err)
+			require.Equal(t, value, found.Value)
+		}
+
+		// Nothing is collected while the chunks are referenced, or recent
+		cdk.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+		defer func() { cdk.now = time.Now }()
+		removed, err := cdk.CollectBlobGarbage(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), removed)
+
+		// The chunks shared with the second blob are kept
+		require.NoError(t, cdk.DeleteResourceBlob(ctx, key, uid1))
+		removed, err = cdk.CollectBlobGarbage(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(onlyIn(v1, v2))), removed)
+		require.Positive(t, removed)
+
+		found, err := store.GetResourceBlob(ctx, key, &utils.BlobInfo{UID: uid2}, true)
+		require.NoError(t, err)
+		require.Equal(t, v2, found.Value)
+	})
+	t.Run("does not share the chunks between namespaces", func(t *testing.T) {
+		value := randomBlob(64 * 1024)
+		for _, ns := range []string{"ns-a", "ns-b"} {
+			_, err := store.PutResourceBlob(ctx, &resourcepb.PutBlobRequest{
+				Resource:    &resourcepb.ResourceKey{Group: "g", Resource: "r", Namespace: ns, Name: "n"},
+				Method:      resourcepb.PutBlobRequest_GRPC,
+				ContentType: "application/json",
+				Value:       value,
+			})
+			require.NoError(t, err)
+		}
+		require.Equal(t, countObjects(t, bucket, "chunks/ns-a/"), countObjects(t, bucket, "chunks/ns-b/"))
+		require.Positive(t, countObjects(t, bucket, "chunks/ns-a/"))
+	})
+	t.Run("writes the chunk again when it is collected at the same time", func(t *testing.T) {
+		cdk := store.(*cdkBlobSupport)
+		key := &resourcepb.ResourceKey{Group: "g", Resource: "r", Namespace: "collected", Name: "n"}
+		value := []byte(`{"collected": true}`)
+		hash := BlobChunkHash(value)
+
+		// A collection found no reference and is removing the chunk
+		require.NoError(t, bucket.WriteAll(ctx, cdk.chunkPath(key.Namespace, hash), value, nil))
+		require.NoError(t, bucket.WriteAll(ctx, cdk.chunkCollectPath(key.Namespace, hash), []byte{}, nil))
+
+		var rsp *resourcepb.PutBlobResponse
+		done := make(chan error)
+		go func() {
+			var err error
+			rsp, err = store.PutResourceBlob(ctx, &resourcepb.PutBlobRequest{
+				Resource:    key,
+				Method:      resourcepb.PutBlobRequest_GRPC,
+				ContentType: "application/json",
+				Value:       value,
+			})
+			done <- err
+		}()
+		select {
+		case <-done:
+			t.Fatal("the blob must wait for the collection")
+		case <-time.After(3 * blobGarbagePollInterval):
+		}
+
+		require.NoError(t, bucket.Delete(ctx, cdk.chunkPath(key.Namespace, hash)))
+		require.NoError(t, bucket.Delete(ctx, cdk.chunkCollectPath(key.Namespace, hash)))
+		require.NoError(t, <-done)
+
+		found, err := store.GetResourceBlob(ctx, key, &utils.BlobInfo{UID: rsp.Uid}, true)
+		require.NoError(t, err)
+		require.Equal(t, value, found.Value)
 	})
 }
+
+func randomBlob(size int) []byte {
+	value := make([]byte, size)
+	rng := rand.New(rand.NewPCG(1, 2)) // nolint:gosec
+	for i := range value {
+		value[i] = byte(rng.UintN(256))
+	}
+	return value
+}
+
+// onlyIn returns the hashes of the chunks of a not in b
+func onlyIn(a, b []byte) []string {
+	inB, _ := uniqueChunks(b)
+	inA, _ := uniqueChunks(a)
+	var hashes []string
+	for hash := range inA {
+		if _, ok := inB[hash]; !ok {
+			hashes = append(hashes, hash)
+		}
+	}
+	return hashes
+}
+
+func countObjects(t *testing.T, bucket *blob.Bucket, prefix string) int {
+	t.Helper()
+	count := 0
+	iter := bucket.List(&blob.ListOptions{Prefix: prefix})
+	for {
+		_, err := iter.Next(context.Background())
+		if errors.Is(err, io.EOF) {
+			return count
+		}
+		require.NoError(t, err)
+		count++
+	}
+}
+
+func TestSplitBlobChunks(t *testing.T) {
+	value := randomBlob(1024 * 1024)
+	chunks := SplitBlobChunks(value)
+	require.Greater(t, len(chunks), 10)
+	require.Equal(t, value, bytes.Join(chunks, nil))
+	for _, chunk := range chunks[:len(chunks)-1] {
+		require.GreaterOrEqual(t, len(chunk), blobChunkMin)
+		require.LessOrEqual(t, len(chunk), blobChunkMax)
+	}
+
+	// Inserting bytes only changes the chunks around the insertion,
+	// the boundaries are found again after the minimum size of the next chunk
+	shifted := append([]byte("inserted"), value...)
+	seen := make(map[string]bool, len(chunks))
+	for _, chunk := range chunks {
+		seen[BlobChunkHash(chunk)] = true
+	}
+	changed := 0
+	for _, chunk := range SplitBlobChunks(shifted) {
+		if !seen[BlobChunkHash(chunk)] {
+			changed++
+		}
+	}
+	require.LessOrEqual(t, changed, 2)
+
+	require.Nil(t, SplitBlobChunks(nil))
+	require.Equal(t, [][]byte{[]byte("small")}, SplitBlobChunks([]byte("small")))
+}
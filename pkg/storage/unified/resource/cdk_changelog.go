@@ -0,0 +1,431 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// Processes sharing a bucket are coordinated with objects that are only written when they do not exist yet (see cdkCreateObject):
+//
+//   - Each process leases one of the writer slots. Every lease term is a new object ({root}__writers__/{slot}/{term}.json),
+//     so only one process can take over an expired lease. A process only writes while its lease is valid.
+//     Resource versions are timestamps in microseconds, with the slot in the lowest digits,
+//     so versions written by different processes never collide.
+//   - Before writing a version, the writer claims the successor of the latest version ({rv}~next~{attempt} next to the versions).
+//     Concurrent writes to the same resource claim the same successor, and all but one fail.
+//   - Every write is also appended to the change log ({root}__changelog__/{second}/{rv}.json).
+//     Watchers poll the recent partitions of the change log, so they see the writes of all processes.
+const (
+	cdkWriterSlots = 16
+	cdkWriterLease = 30 * time.Second
+
+	// Claims older than this without a written version were abandoned, and the next attempt can be claimed
+	cdkClaimTimeout = cdkWriterLease
+
+	// Writes become visible in the change log after the resource version was allocated,
+	// the recent partitions are scanned again to find them
+	cdkWatchLookback = 2 * time.Second
+)
+
+var errCDKWriterLeaseExpired = errors.New("the writer lease expired")
+
+// An entry in the change log
+type cdkChange struct {
+	Type            resourcepb.WatchEvent_Type `json:"type"`
+	Namespace       string                     `json:"namespace,omitempty"`
+	Group           string                     `json:"group"`
+	Resource        string                     `json:"resource"`
+	Name            string                     `json:"name"`
+	Folder          string                     `json:"folder,omitempty"`
+	PreviousRV      int64                      `json:"previousRV,omitempty"`
+	ResourceVersion int64                      `json:"rv"`
+	Timestamp       int64                      `json:"timestamp"`
+	Value           []byte                     `json:"value"`
+}
+
+type cdkWriterLeaseInfo struct {
+	ID      string `json:"id"`
+	Expires int64  `json:"expires"` // unix milliseconds, 0 once released
+}
+
+// The claim of the version written after another one
+type cdkWriteClaim struct {
+	ResourceVersion int64 `json:"rv"`
+	Timestamp       int64 `json:"timestamp"` // unix milliseconds
+}
+
+func (s *cdkBackend) getWriterPath(slot int64, term int64) string {
+	return fmt.Sprintf("%s%s%02d/%020d.json", s.root, cdkWritersFolder, slot, term)
+}
+
+// latestWriterLease returns the latest lease term of the slot, all terms are returned so the older ones can be removed
+func (s *cdkBackend) latestWriterLease(ctx context.Context, slot int64) (int64, *cdkWriterLeaseInfo, []string, error) {
+	prefix := fmt.Sprintf("%s%s%02d/", s.root, cdkWritersFolder, slot)
+	var terms []string
+	var term int64
+	iter := s.bucket.List(&blob.ListOptions{Prefix: prefix})
+	for {
+		obj, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		t, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(obj.Key, prefix), ".json"), 10, 64)
+		if err != nil {
+			continue
+		}
+		terms = append(terms, obj.Key)
+		term = max(term, t)
+	}
+	if term == 0 {
+		return 0, nil, terms, nil
+	}
+
+	raw, err := s.bucket.ReadAll(ctx, s.getWriterPath(slot, term))
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return term, nil, terms, nil // removed in between, the next term is claimed
+		}
+		return 0, nil, nil, err
+	}
+	lease := &cdkWriterLeaseInfo{}
+	if err = json.Unmarshal(raw, lease); err != nil {
+		return term, nil, terms, nil
+	}
+	return term, lease, terms, nil
+}
+
+// writeWriterLease writes the next lease term, and fails with errCDKObjectExists when another process wrote it first
+func (s *cdkBackend) writeWriterLease(ctx context.Context, slot int64, term int64, expires time.Time) error {
+	info := &cdkWriterLeaseInfo{ID: s.writerID}
+	if !expires.IsZero() {
+		info.Expires = expires.UnixMilli()
+	}
+	raw, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return cdkCreateObject(ctx, s.bucket, s.getWriterPath(slot, term), raw)
+}
+
+// claimWriterSlot leases a free writer slot.
+// When two processes claim the same slot, only one of them writes the next term and the other one tries the next slot.
+func (s *cdkBackend) claimWriterSlot(ctx context.Context) error {
+	for slot := int64(0); slot < cdkWriterSlots; slot++ {
+		term, lease, terms, err := s.latestWriterLease(ctx, slot)
+		if err != nil {
+			return err
+		}
+		if lease != nil && lease.ID != s.writerID && lease.Expires > time.Now().UnixMilli() {
+			continue
+		}
+
+		expires := time.Now().Add(cdkWriterLease)
+		err = s.writeWriterLease(ctx, slot, term+1, expires)
+		if errors.Is(err, errCDKObjectExists) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		s.rvMutex.Lock()
+		s.slot = slot
+		s.term = term + 1
+		s.leaseExpires = expires
+		s.rvMutex.Unlock()
+
+		s.removeWriterLeases(ctx, terms)
+		return nil
+	}
+	return fmt.Errorf("all %d writer slots are in use", cdkWriterSlots)
+}
+
+// removeWriterLeases removes the previous terms, they are only kept until the next term is written
+func (s *cdkBackend) removeWriterLeases(ctx context.Context, terms []string) {
+	for _, path := range terms {
+		if err := s.bucket.Delete(ctx, path); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+			s.log.Warn("failed to remove writer lease", "path", path, "err", err)
+		}
+	}
+}
+
+// renewWriterSlot extends the lease, and claims a new slot if it was taken over
+func (s *cdkBackend) renewWriterSlot(ctx context.Context) {
+	ticker := time.NewTicker(cdkWriterLease / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.rvMutex.Lock()
+		slot, term := s.slot, s.term
+		s.rvMutex.Unlock()
+
+		expires := time.Now().Add(cdkWriterLease)
+		err := s.writeWriterLease(ctx, slot, term+1, expires)
+		switch {
+		case err == nil:
+			s.rvMutex.Lock()
+			s.term = term + 1
+			s.leaseExpires = expires
+			s.rvMutex.Unlock()
+			s.removeWriterLeases(ctx, []string{s.getWriterPath(slot, term)})
+		case errors.Is(err, errCDKObjectExists):
+			s.log.Warn("writer slot was taken over, claiming a new one", "slot", slot)
+			err = s.claimWriterSlot(ctx)
+		}
+		if err != nil && ctx.Err() == nil {
+			s.log.Error("failed to renew writer slot", "slot", slot, "err", err)
+		}
+	}
+}
+
+// releaseWriterSlot ends the lease, so the slot can be claimed right away
+func (s *cdkBackend) releaseWriterSlot(ctx context.Context) error {
+	s.rvMutex.Lock()
+	slot, term := s.slot, s.term
+	s.leaseExpires = time.Time{}
+	s.rvMutex.Unlock()
+
+	err := s.writeWriterLease(ctx, slot, term+1, time.Time{})
+	if errors.Is(err, errCDKObjectExists) {
+		return nil // already taken over
+	}
+	if err != nil {
+		return err
+	}
+	s.rvMutex.Lock()
+	s.term = term + 1
+	s.rvMutex.Unlock()
+	s.removeWriterLeases(ctx, []string{s.getWriterPath(slot, term)})
+	return nil
+}
+
+// nextRV returns a new resource version, greater than any version seen by this process.
+// Versions are only allocated while the writer lease is valid, otherwise another process may use the same slot.
+func (s *cdkBackend) nextRV() (int64, error) {
+	s.rvMutex.Lock()
+	defer s.rvMutex.Unlock()
+
+	if !time.Now().Before(s.leaseExpires) {
+		return 0, errCDKWriterLeaseExpired
+	}
+	rv := max(time.Now().UnixMicro(), s.lastRV+1)
+	rv = rv - rv%cdkWriterSlots + s.slot
+	if rv <= s.lastRV {
+		rv += cdkWriterSlots
+	}
+	s.lastRV = rv
+	return rv, nil
+}
+
+// observeRV makes sure the next resource version is greater than a version written by another process
+func (s *cdkBackend) observeRV(rv int64) {
+	s.rvMutex.Lock()
+	defer s.rvMutex.Unlock()
+	s.lastRV = max(s.lastRV, rv)
+}
+
+// The change log is partitioned by second, so watchers only list the recent entries
+func (s *cdkBackend) getChangeLogPartition(rv int64) string {
+	return fmt.Sprintf("%s%s%012d/", s.root, cdkChangeLogFolder, rv/int64(time.Second/time.Microsecond))
+}
+
+func (s *cdkBackend) appendChangeLog(ctx context.Context, change *cdkChange) error {
+	raw, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("%s%020d.json", s.getChangeLogPartition(change.ResourceVersion), change.ResourceVersion)
+	return s.bucket.WriteAll(ctx, path, raw, &blob.WriterOptions{
+		ContentType: "application/json",
+	})
+}
+
+// compactChangeLog removes the partitions older than the cutoff
+func (s *cdkBackend) compactChangeLog(ctx context.Context, cutoff time.Time) error {
+	prefix := s.root + cdkChangeLogFolder
+	var partitions []string
+	iter := s.bucket.List(&blob.ListOptions{Prefix: prefix, Delimiter: "/"})
+	for {
+		obj, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		second, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(obj.Key, prefix), "/"), 10, 64)
+		if obj.IsDir && err == nil && second < cutoff.Unix() {
+			partitions = append(partitions, obj.Key)
+		}
+	}
+
+	for _, partition := range partitions {
+		iter := s.bucket.List(&blob.ListOptions{Prefix: partition})
+		for {
+			obj, err := iter.Next(ctx)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if err = s.bucket.Delete(ctx, obj.Key); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WatchWriteEvents polls the change log for every subscriber, the stream is closed when the context is done
+func (s *cdkBackend) WatchWriteEvents(ctx context.Context) (<-chan *WrittenEvent, error) {
+	// Other processes may still write versions slightly lower than the start of the watch,
+	// so the recent changes are scanned too, and the ones already in the change log are skipped
+	now := s.currentRV()
+	s.observeRV(now)
+	since := now - int64(cdkWatchLookback/time.Microsecond)
+	existing, err := s.listChangeLog(ctx, since, now)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[int64]bool, len(existing))
+	for rv := range existing {
+		seen[rv] = true
+	}
+
+	stream := make(chan *WrittenEvent, 100)
+	go s.pollChangeLog(ctx, stream, since, now, seen)
+	return stream, nil
+}
+
+// pollChangeLog sends the unseen events written after the since resource version.
+// Events that show up late in the change log (see cdkWatchLookback) are sent out of order rather than dropped.
+func (s *cdkBackend) pollChangeLog(ctx context.Context, stream chan<- *WrittenEvent, since, scanned int64, seen map[int64]bool) {
+	defer close(stream)
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		lookback := int64(cdkWatchLookback / time.Microsecond)
+		to := s.currentRV()
+		changes, err := s.readChangeLog(ctx, max(since, scanned-lookback), to, seen)
+		if err != nil {
+			s.log.Warn("failed to read change log", "err", err)
+			continue
+		}
+		for _, change := range changes {
+			seen[change.ResourceVersion] = true
+			s.observeRV(change.ResourceVersion)
+			event := &WrittenEvent{
+				Type: change.Type,
+				Key: &resourcepb.ResourceKey{
+					Namespace: change.Namespace,
+					Group:     change.Group,
+					Resource:  change.Resource,
+					Name:      change.Name,
+				},
+				PreviousRV:      change.PreviousRV,
+				Value:           change.Value,
+				Folder:          change.Folder,
+				Timestamp:       change.Timestamp,
+				ResourceVersion: change.ResourceVersion,
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case stream <- event:
+			}
+		}
+
+		// Only the versions within the lookback need to be remembered
+		scanned = to
+		for rv := range seen {
+			if rv < scanned-lookback {
+				delete(seen, rv)
+			}
+		}
+	}
+}
+
+// readChangeLog returns the unseen changes after the from resource version, ordered by resource version
+func (s *cdkBackend) readChangeLog(ctx context.Context, from, to int64, seen map[int64]bool) ([]*cdkChange, error) {
+	entries, err := s.listChangeLog(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	var changes []*cdkChange
+	for rv, path := range entries {
+		if seen[rv] {
+			continue
+		}
+		raw, err := s.bucket.ReadAll(ctx, path)
+		if err != nil {
+			if gcerrors.Code(err) == gcerrors.NotFound {
+				continue // compacted
+			}
+			return nil, err
+		}
+		change := &cdkChange{}
+		if err = json.Unmarshal(raw, change); err != nil {
+			return nil, fmt.Errorf("invalid change log entry %s: %w", path, err)
+		}
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].ResourceVersion < changes[j].ResourceVersion
+	})
+	return changes, nil
+}
+
+// listChangeLog returns the paths of the change log entries after the from resource version, by resource version.
+// The partition after the to version is listed too, since other processes may be slightly ahead.
+func (s *cdkBackend) listChangeLog(ctx context.Context, from, to int64) (map[int64]string, error) {
+	entries := make(map[int64]string)
+	second := int64(time.Second / time.Microsecond)
+	for partition := from / second; partition <= to/second+1; partition++ {
+		iter := s.bucket.List(&blob.ListOptions{Prefix: s.getChangeLogPartition(partition * second)})
+		for {
+			obj, err := iter.Next(ctx)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			idx := strings.LastIndex(obj.Key, "/") + 1
+			rv, err := strconv.ParseInt(strings.TrimSuffix(obj.Key[idx:], ".json"), 10, 64)
+			if err != nil || rv <= from {
+				continue
+			}
+			entries[rv] = obj.Key
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,93 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	azblobblob "github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	s3manager "github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+)
+
+// errCDKObjectExists is returned when the object created with cdkCreateObject already exists
+var errCDKObjectExists = errors.New("object already exists")
+
+// Buckets without conditional writes (file, memory) are checked and written while holding the lock,
+// so they are only safe to share between backends within the same process.
+var cdkCreateMutex sync.Mutex
+
+// cdkCreateObject writes the object only when it does not exist yet.
+// GCS, S3 and Azure reject the write when the object was created in between (by any process).
+func cdkCreateObject(ctx context.Context, bucket CDKBucket, key string, value []byte) error {
+	cdkCreateMutex.Lock()
+	defer cdkCreateMutex.Unlock()
+
+	_, err := bucket.Attributes(ctx, key)
+	if err == nil {
+		return errCDKObjectExists
+	}
+	if gcerrors.Code(err) != gcerrors.NotFound {
+		return err
+	}
+
+	err = bucket.WriteAll(ctx, key, value, &blob.WriterOptions{
+		ContentType: "application/json",
+		BeforeWrite: cdkIfNotExists,
+	})
+	if isCDKPreconditionFailed(err) {
+		return errCDKObjectExists
+	}
+	return err
+}
+
+// cdkIfNotExists adds the precondition to the write request of the bucket driver
+func cdkIfNotExists(as func(any) bool) error {
+	var gcs **storage.ObjectHandle
+	if as(&gcs) {
+		*gcs = (*gcs).If(storage.Conditions{DoesNotExist: true})
+		return nil
+	}
+	var uploader *s3manager.Uploader
+	if as(&uploader) {
+		uploader.ClientOptions = append(uploader.ClientOptions, func(o *s3.Options) {
+			o.APIOptions = append(o.APIOptions, smithyhttp.AddHeaderValue("If-None-Match", "*"))
+		})
+		return nil
+	}
+	var azure *blockblob.UploadStreamOptions
+	if as(&azure) {
+		etag := azcore.ETagAny
+		azure.AccessConditions = &azblobblob.AccessConditions{
+			ModifiedAccessConditions: &azblobblob.ModifiedAccessConditions{IfNoneMatch: &etag},
+		}
+	}
+	return nil
+}
+
+func isCDKPreconditionFailed(err error) bool {
+	if err == nil {
+		return false
+	}
+	if gcerrors.Code(err) == gcerrors.FailedPrecondition {
+		return true
+	}
+	var s3err smithy.APIError
+	if errors.As(err, &s3err) {
+		return s3err.ErrorCode() == "PreconditionFailed" || s3err.ErrorCode() == "ConditionalRequestConflict"
+	}
+	if bloberror.HasCode(err, bloberror.BlobAlreadyExists, bloberror.ConditionNotMet) {
+		return true
+	}
+	var rerr *azcore.ResponseError
+	return errors.As(err, &rerr) && rerr.StatusCode == http.StatusPreconditionFailed
+}
@@ -36,6 +36,7 @@ type ResourceClient interface {
 	resourcepb.BulkStoreClient
 	resourcepb.BlobStoreClient
 	resourcepb.DiagnosticsClient
+	resourcepb.TrashClient
 }
 
 // Internal implementation
@@ -46,6 +47,7 @@ type resourceClient struct {
 	resourcepb.BulkStoreClient
 	resourcepb.BlobStoreClient
 	resourcepb.DiagnosticsClient
+	resourcepb.TrashClient
 }
 
 func NewResourceClient(conn grpc.ClientConnInterface, cfg *setting.Cfg, features featuremgmt.FeatureToggles, tracer trace.Tracer) (ResourceClient, error) {
@@ -73,6 +75,7 @@ func NewLegacyResourceClient(channel grpc.ClientConnInterface) ResourceClient {
 		BulkStoreClient:          resourcepb.NewBulkStoreClient(cc),
 		BlobStoreClient:          resourcepb.NewBlobStoreClient(cc),
 		DiagnosticsClient:        resourcepb.NewDiagnosticsClient(cc),
+		TrashClient:              resourcepb.NewTrashClient(cc),
 	}
 }
 
@@ -89,6 +92,7 @@ func NewLocalResourceClient(server ResourceServer) ResourceClient {
 		&resourcepb.BlobStore_ServiceDesc,
 		&resourcepb.BulkStore_ServiceDesc,
 		&resourcepb.Diagnostics_ServiceDesc,
+		&resourcepb.Trash_ServiceDesc,
 	} {
 		channel.RegisterService(
 			grpchan.InterceptServer(
@@ -113,6 +117,7 @@ func NewLocalResourceClient(server ResourceServer) ResourceClient {
 		BulkStoreClient:          resourcepb.NewBulkStoreClient(cc),
 		BlobStoreClient:          resourcepb.NewBlobStoreClient(cc),
 		DiagnosticsClient:        resourcepb.NewDiagnosticsClient(cc),
+		TrashClient:              resourcepb.NewTrashClient(cc),
 	}
 }
 
@@ -155,6 +160,7 @@ func NewRemoteResourceClient(tracer trace.Tracer, conn grpc.ClientConnInterface,
 		BulkStoreClient:          resourcepb.NewBulkStoreClient(cc),
 		ManagedObjectIndexClient: resourcepb.NewManagedObjectIndexClient(cc),
 		DiagnosticsClient:        resourcepb.NewDiagnosticsClient(cc),
+		TrashClient:              resourcepb.NewTrashClient(cc),
 	}, nil
 }
 
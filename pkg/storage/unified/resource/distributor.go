@@ -251,3 +251,8 @@ func (ds *distributorServer) IsHealthy(ctx context.Context, r *resourcepb.Health
 
 	return &resourcepb.HealthCheckResponse{Status: resourcepb.HealthCheckResponse_NOT_SERVING}, nil
 }
+
+// ListOnlineMigrations is not distributed, the storage servers report their own migrations
+func (ds *distributorServer) ListOnlineMigrations(ctx context.Context, r *resourcepb.ListOnlineMigrationsRequest) (*resourcepb.ListOnlineMigrationsResponse, error) {
+	return &resourcepb.ListOnlineMigrationsResponse{}, nil
+}
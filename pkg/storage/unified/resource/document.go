@@ -3,6 +3,8 @@ package resource
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 
@@ -105,6 +107,9 @@ type IndexableDocument struct {
 	// Someday this will likely be part of https://github.com/grafana/gamma
 	References ResourceReferences `json:"reference,omitempty"`
 
+	// internal field for finding the resources that depend on a reference ( don't set this directly )
+	ReferenceIDs []string `json:"references,omitempty"`
+
 	// When the resource is managed by an upstream repository
 	Manager *utils.ManagerProperties `json:"manager,omitempty"`
 
@@ -121,6 +126,13 @@ func (m *IndexableDocument) UpdateCopyFields() *IndexableDocument {
 	if m.Manager != nil {
 		m.ManagedBy = fmt.Sprintf("%s:%s", m.Manager.Kind, m.Manager.Identity)
 	}
+	m.ReferenceIDs = nil
+	for _, ref := range m.References {
+		if id := ref.Target(); !slices.Contains(m.ReferenceIDs, id) {
+			m.ReferenceIDs = append(m.ReferenceIDs, id)
+		}
+	}
+	sort.Strings(m.ReferenceIDs)
 	return m
 }
 
@@ -154,6 +166,15 @@ func (m ResourceReference) String() string {
 	return sb.String()
 }
 
+// Target identifies the referenced resource: {kind}/{name}
+func (m ResourceReference) Target() string {
+	kind := m.Kind
+	if kind == "" {
+		kind = m.Group
+	}
+	return kind + "/" + m.Name
+}
+
 // Sortable list of references
 type ResourceReferences []ResourceReference
 
@@ -285,7 +306,8 @@ const SEARCH_FIELD_TITLE_NGRAM = "title_ngram"
 const SEARCH_FIELD_TITLE_PHRASE = "title_phrase" // filtering/sorting on title by full phrase
 const SEARCH_FIELD_DESCRIPTION = "description"
 const SEARCH_FIELD_TAGS = "tags"
-const SEARCH_FIELD_LABELS = "labels" // All labels, not a specific one
+const SEARCH_FIELD_LABELS = "labels"         // All labels, not a specific one
+const SEARCH_FIELD_REFERENCES = "references" // {kind}/{name} of the referenced resources
 
 const SEARCH_FIELD_FOLDER = "folder"
 const SEARCH_FIELD_CREATED = "created"
@@ -369,6 +391,15 @@ func StandardSearchFields() SearchableDocumentFields {
 				Type:        resourcepb.ResourceTableColumnDefinition_STRING,
 				Description: "Kubernetes name for the folder",
 			},
+			{
+				Name:        SEARCH_FIELD_REFERENCES,
+				Type:        resourcepb.ResourceTableColumnDefinition_STRING,
+				IsArray:     true,
+				Description: "The resources this depends on: {kind}/{name}",
+				Properties: &resourcepb.ResourceTableColumnDefinition_Properties{
+					Filterable: true,
+				},
+			},
 			{
 				Name:        SEARCH_FIELD_RV,
 				Type:        resourcepb.ResourceTableColumnDefinition_INT64,
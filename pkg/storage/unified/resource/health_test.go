@@ -89,6 +89,10 @@ func (s *diag) IsHealthy(ctx context.Context, req *resourcepb.HealthCheckRequest
 	return &resourcepb.HealthCheckResponse{Status: s.healthResponse}, nil
 }
 
+func (s *diag) ListOnlineMigrations(ctx context.Context, req *resourcepb.ListOnlineMigrationsRequest) (*resourcepb.ListOnlineMigrationsResponse, error) {
+	return &resourcepb.ListOnlineMigrationsResponse{}, nil
+}
+
 type fakeHealthWatchServer struct {
 	mu sync.Mutex
 	grpc.ServerStream
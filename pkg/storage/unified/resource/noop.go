@@ -32,6 +32,11 @@ func (n *noopService) IsHealthy(context.Context, *resourcepb.HealthCheckRequest)
 	}, nil
 }
 
+// ListOnlineMigrations implements DiagnosticsServer
+func (n *noopService) ListOnlineMigrations(context.Context, *resourcepb.ListOnlineMigrationsRequest) (*resourcepb.ListOnlineMigrationsResponse, error) {
+	return &resourcepb.ListOnlineMigrationsResponse{}, nil
+}
+
 func (n *noopService) Read(context.Context, *resourcepb.ReadRequest) (*resourcepb.ReadResponse, error) {
 	return nil, ErrNotImplementedYet
 }
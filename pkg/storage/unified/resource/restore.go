@@ -0,0 +1,338 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	claims "github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+const (
+	folderGroup    = "folder.grafana.app"
+	folderResource = "folders"
+)
+
+// restoreChange is a change needed to restore the state, with the values it was calculated from
+type restoreChange struct {
+	*resourcepb.RestoreResponse_Change
+
+	current *restoreItem
+	target  *restoreItem
+}
+
+type restoreItem struct {
+	rv     int64
+	folder string
+	value  []byte
+}
+
+// Restore implements BulkStoreServer.
+// The changes are calculated from the history, and applied as a single bulk request on top of it.
+func (s *server) Restore(ctx context.Context, req *resourcepb.RestoreRequest) (*resourcepb.RestoreResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "storage_server.Restore")
+	defer span.End()
+
+	rsp := &resourcepb.RestoreResponse{}
+	user, ok := claims.AuthInfoFrom(ctx)
+	if !ok || user == nil {
+		rsp.Error = &resourcepb.ErrorResult{
+			Message: "no user found in context",
+			Code:    http.StatusUnauthorized,
+		}
+		return rsp, nil
+	}
+	if req.Namespace == "" {
+		rsp.Error = NewBadRequestError("missing namespace")
+		return rsp, nil
+	}
+	if len(req.Resources) < 1 {
+		rsp.Error = NewBadRequestError("missing resources")
+		return rsp, nil
+	}
+
+	rv := req.ResourceVersion
+	if rv < 1 && req.Timestamp > 0 {
+		rv = time.UnixMilli(req.Timestamp).UnixMicro()
+	}
+	if rv < 1 {
+		rsp.Error = NewBadRequestError("missing resource version or timestamp")
+		return rsp, nil
+	}
+	rsp.ResourceVersion = rv
+
+	// Restoring can replace everything in the collection, even for a dry run the full history is visible
+	collections := make([]*resourcepb.ResourceKey, 0, len(req.Resources))
+	for _, r := range req.Resources {
+		if r.Group == "" || r.Resource == "" {
+			rsp.Error = NewBadRequestError("missing group or resource")
+			return rsp, nil
+		}
+		check, err := s.access.Check(ctx, user, claims.CheckRequest{
+			Namespace: req.Namespace,
+			Group:     r.Group,
+			Resource:  r.Resource,
+			Verb:      utils.VerbDeleteCollection,
+		})
+		if err != nil {
+			rsp.Error = AsErrorResult(err)
+			return rsp, nil
+		}
+		if !check.Allowed {
+			rsp.Error = AsErrorResult(apierrors.NewForbidden(schema.GroupResource{Group: r.Group, Resource: r.Resource}, "",
+				fmt.Errorf("requester must be able to: %s", utils.VerbDeleteCollection)))
+			return rsp, nil
+		}
+		collections = append(collections, &resourcepb.ResourceKey{
+			Namespace: req.Namespace,
+			Group:     r.Group,
+			Resource:  r.Resource,
+		})
+	}
+
+	// Folders are restored first, so the other resources have a parent
+	sort.SliceStable(collections, func(i, j int) bool {
+		return isFolderCollection(collections[i]) && !isFolderCollection(collections[j])
+	})
+
+	var subtree map[string]bool
+	if req.Folder != "" {
+		var err error
+		subtree, err = s.restoreFolderSubtree(ctx, req.Namespace, req.Folder, rv)
+		if err != nil {
+			rsp.Error = AsErrorResult(err)
+			return rsp, nil
+		}
+	}
+
+	var changes []*restoreChange
+	var changed []*resourcepb.ResourceKey
+	for _, key := range collections {
+		current, err := s.listRestoreState(ctx, key, 0)
+		if err != nil {
+			rsp.Error = AsErrorResult(err)
+			return rsp, nil
+		}
+		target, err := s.listRestoreState(ctx, key, rv)
+		if err != nil {
+			rsp.Error = AsErrorResult(err)
+			return rsp, nil
+		}
+		found := diffRestoreState(key, current, target, subtree)
+		if len(found) > 0 {
+			changed = append(changed, key)
+			changes = append(changes, found...)
+		}
+	}
+	for _, change := range changes {
+		rsp.Changes = append(rsp.Changes, change.RestoreResponse_Change)
+	}
+	if req.DryRun || len(changes) < 1 {
+		return rsp, nil
+	}
+
+	backend, ok := s.backend.(BulkProcessingBackend)
+	if !ok {
+		rsp.Error = &resourcepb.ErrorResult{
+			Message: "the server backend does not support batch processing",
+			Code:    http.StatusNotImplemented,
+			Reason:  string(metav1.StatusReasonMethodNotAllowed),
+		}
+		return rsp, nil
+	}
+
+	now := metav1.NewTime(time.UnixMilli(s.now()))
+	requests := make([]*resourcepb.BulkRequest, 0, len(changes))
+	for _, change := range changes {
+		value, err := change.restoredValue(now, user.GetUID())
+		if err != nil {
+			rsp.Error = AsErrorResult(fmt.Errorf("restore %s/%s: %w", change.Key.Resource, change.Key.Name, err))
+			return rsp, nil
+		}
+		requests = append(requests, &resourcepb.BulkRequest{
+			Key:    change.Key,
+			Action: change.Action,
+			Value:  value,
+			Folder: change.Folder,
+		})
+	}
+
+	s.log.Info("restoring resources", "namespace", req.Namespace, "rv", rv, "changes", len(requests))
+	rsp.Bulk = backend.ProcessBulk(ctx, BulkSettings{Collection: changed}, NewBulkRequestIterator(requests))
+	if rsp.Bulk == nil {
+		rsp.Bulk = &resourcepb.BulkResponse{
+			Error: &resourcepb.ErrorResult{
+				Code:    http.StatusInternalServerError,
+				Message: "Nothing returned from process batch",
+			},
+		}
+	}
+	s.rebuildIndexes(ctx, rsp.Bulk)
+	return rsp, nil
+}
+
+func isFolderCollection(key *resourcepb.ResourceKey) bool {
+	return key.Group == folderGroup && key.Resource == folderResource
+}
+
+// listRestoreState returns every resource in the collection at a resource version (0 for the latest)
+func (s *server) listRestoreState(ctx context.Context, key *resourcepb.ResourceKey, rv int64) (map[string]*restoreItem, error) {
+	items := make(map[string]*restoreItem)
+	_, err := s.backend.ListIterator(ctx, &resourcepb.ListRequest{
+		ResourceVersion: rv,
+		Options:         &resourcepb.ListOptions{Key: key},
+	}, func(iter ListIterator) error {
+		for iter.Next() {
+			if err := iter.Error(); err != nil {
+				return err
+			}
+			items[iter.Name()] = &restoreItem{
+				rv:     iter.ResourceVersion(),
+				folder: iter.Folder(),
+				value:  append([]byte(nil), iter.Value()...),
+			}
+		}
+		return iter.Error()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list %s/%s at %d: %w", key.Group, key.Resource, rv, err)
+	}
+	return items, nil
+}
+
+// restoreFolderSubtree returns the folder and all its descendants, in either the current or the restored state
+func (s *server) restoreFolderSubtree(ctx context.Context, namespace string, root string, rv int64) (map[string]bool, error) {
+	key := &resourcepb.ResourceKey{Namespace: namespace, Group: folderGroup, Resource: folderResource}
+	children := make(map[string][]string)
+	for _, at := range []int64{0, rv} {
+		folders, err := s.listRestoreState(ctx, key, at)
+		if err != nil {
+			return nil, err
+		}
+		for name, item := range folders {
+			children[item.folder] = append(children[item.folder], name)
+		}
+	}
+
+	subtree := map[string]bool{root: true}
+	queue := []string{root}
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		for _, child := range children[parent] {
+			if !subtree[child] {
+				subtree[child] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+	return subtree, nil
+}
+
+// diffRestoreState returns the changes that turn the current state into the target state
+func diffRestoreState(key *resourcepb.ResourceKey, current, target map[string]*restoreItem, subtree map[string]bool) []*restoreChange {
+	included := func(name string, items ...*restoreItem) bool {
+		if subtree == nil || (isFolderCollection(key) && subtree[name]) {
+			return true
+		}
+		for _, item := range items {
+			if item != nil && subtree[item.folder] {
+				return true
+			}
+		}
+		return false
+	}
+
+	var changes []*restoreChange
+	for name, tgt := range target {
+		cur := current[name]
+		if cur != nil && cur.rv == tgt.rv {
+			continue
+		}
+		if !included(name, cur, tgt) {
+			continue
+		}
+		change := &restoreChange{
+			RestoreResponse_Change: &resourcepb.RestoreResponse_Change{
+				Key: &resourcepb.ResourceKey{
+					Namespace: key.Namespace,
+					Group:     key.Group,
+					Resource:  key.Resource,
+					Name:      name,
+				},
+				Action:                  resourcepb.BulkRequest_ADDED,
+				Folder:                  tgt.folder,
+				RestoredResourceVersion: tgt.rv,
+			},
+			current: cur,
+			target:  tgt,
+		}
+		if cur != nil {
+			change.Action = resourcepb.BulkRequest_MODIFIED
+			change.CurrentResourceVersion = cur.rv
+		}
+		changes = append(changes, change)
+	}
+	for name, cur := range current {
+		if _, ok := target[name]; ok || !included(name, cur) {
+			continue
+		}
+		changes = append(changes, &restoreChange{
+			RestoreResponse_Change: &resourcepb.RestoreResponse_Change{
+				Key: &resourcepb.ResourceKey{
+					Namespace: key.Namespace,
+					Group:     key.Group,
+					Resource:  key.Resource,
+					Name:      name,
+				},
+				Action:                 resourcepb.BulkRequest_DELETED,
+				Folder:                 cur.folder,
+				CurrentResourceVersion: cur.rv,
+			},
+			current: cur,
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key.Name < changes[j].Key.Name
+	})
+	return changes
+}
+
+// restoredValue returns the value written for the change
+func (c *restoreChange) restoredValue(now metav1.Time, updatedBy string) ([]byte, error) {
+	if c.Action == resourcepb.BulkRequest_DELETED {
+		return newDeletionMarker(c.current.value, now, updatedBy)
+	}
+
+	tmp := &unstructured.Unstructured{}
+	if err := json.Unmarshal(c.target.value, tmp); err != nil {
+		return nil, err
+	}
+	obj, err := utils.MetaAccessor(tmp)
+	if err != nil {
+		return nil, err
+	}
+	obj.SetUpdatedTimestamp(&now.Time)
+	obj.SetUpdatedBy(updatedBy)
+
+	// The generation keeps increasing from the current value
+	if c.current != nil {
+		prev := &unstructured.Unstructured{}
+		if err := json.Unmarshal(c.current.value, prev); err == nil && prev.GetGeneration() >= obj.GetGeneration() {
+			obj.SetGeneration(prev.GetGeneration() + 1)
+		}
+	}
+	return tmp.MarshalJSON()
+}
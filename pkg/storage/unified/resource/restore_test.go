@@ -0,0 +1,228 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	claims "github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+func TestRestore(t *testing.T) {
+	ctx := claims.WithAuthInfo(context.Background(), &identity.StaticRequester{
+		Type:    claims.TypeUser,
+		UserUID: "u123",
+		OrgRole: identity.RoleAdmin,
+	})
+
+	// rv 1-3: the initial state, rv 4-7: changes made afterwards
+	history := &restoreTestBackend{}
+	history.write(1, folderGroup, folderResource, "parent", "", 1)
+	history.write(2, folderGroup, folderResource, "child", "parent", 1)
+	history.write(3, "dashboard.grafana.app", "dashboards", "in-child", "child", 1)
+	history.write(3, "dashboard.grafana.app", "dashboards", "in-root", "", 1)
+	history.write(4, "dashboard.grafana.app", "dashboards", "in-child", "child", 2)
+	history.write(5, "dashboard.grafana.app", "dashboards", "in-root", "", 2)
+	history.write(6, "dashboard.grafana.app", "dashboards", "new", "parent", 1)
+	history.deleted(7, folderGroup, folderResource, "child")
+
+	newServer := func(t *testing.T, access claims.AccessClient) *server {
+		s, err := NewResourceServer(ResourceServerOptions{
+			Backend:      history,
+			AccessClient: access,
+		})
+		require.NoError(t, err)
+		return s.(*server)
+	}
+	request := func(folder string, dryRun bool) *resourcepb.RestoreRequest {
+		return &resourcepb.RestoreRequest{
+			Namespace: "default",
+			Resources: []*resourcepb.ResourceKey{
+				{Group: "dashboard.grafana.app", Resource: "dashboards"},
+				{Group: folderGroup, Resource: folderResource},
+			},
+			Folder:          folder,
+			ResourceVersion: 3,
+			DryRun:          dryRun,
+		}
+	}
+	summary := func(rsp *resourcepb.RestoreResponse) []string {
+		var changes []string
+		for _, c := range rsp.Changes {
+			changes = append(changes, fmt.Sprintf("%s %s %s (%d > %d)", c.Action, c.Key.Resource, c.Key.Name, c.CurrentResourceVersion, c.RestoredResourceVersion))
+		}
+		return changes
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		history.bulk = nil
+		rsp, err := newServer(t, nil).Restore(ctx, request("", true))
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+		require.Nil(t, rsp.Bulk)
+		require.Nil(t, history.bulk)
+		require.Equal(t, []string{
+			"ADDED folders child (0 > 2)",
+			"MODIFIED dashboards in-child (4 > 3)",
+			"MODIFIED dashboards in-root (5 > 3)",
+			"DELETED dashboards new (6 > 0)",
+		}, summary(rsp))
+	})
+
+	t.Run("folder subtree", func(t *testing.T) {
+		history.bulk = nil
+		rsp, err := newServer(t, nil).Restore(ctx, request("parent", false))
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+		require.Equal(t, []string{
+			"ADDED folders child (0 > 2)",
+			"MODIFIED dashboards in-child (4 > 3)",
+			"DELETED dashboards new (6 > 0)",
+		}, summary(rsp))
+
+		// Folders are written first, in append mode
+		require.NotNil(t, rsp.Bulk)
+		require.Len(t, history.bulk, 3)
+		require.Equal(t, "child", history.bulk[0].Key.Name)
+		require.Equal(t, "parent", history.bulk[0].Folder)
+		require.Len(t, history.settings.Collection, 2)
+		require.False(t, history.settings.RebuildCollection)
+
+		restored := &unstructured.Unstructured{}
+		require.NoError(t, json.Unmarshal(history.bulk[1].Value, restored))
+		require.Equal(t, int64(3), restored.GetGeneration())
+		obj, err := utils.MetaAccessor(restored)
+		require.NoError(t, err)
+		require.Equal(t, "user:u123", obj.GetUpdatedBy())
+
+		deleted := &unstructured.Unstructured{}
+		require.NoError(t, json.Unmarshal(history.bulk[2].Value, deleted))
+		require.Equal(t, int64(utils.DeletedGeneration), deleted.GetGeneration())
+	})
+
+	t.Run("requires delete collection access", func(t *testing.T) {
+		history.bulk = nil
+		rsp, err := newServer(t, claims.FixedAccessClient(false)).Restore(ctx, request("", true))
+		require.NoError(t, err)
+		require.NotNil(t, rsp.Error)
+		require.Equal(t, int32(http.StatusForbidden), rsp.Error.Code)
+		require.Nil(t, history.bulk)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		req := request("", true)
+		req.ResourceVersion = 0
+		rsp, err := newServer(t, nil).Restore(ctx, req)
+		require.NoError(t, err)
+		require.NotNil(t, rsp.Error)
+		require.Equal(t, int32(http.StatusBadRequest), rsp.Error.Code)
+	})
+}
+
+// restoreTestBackend keeps the full history in memory, and records bulk requests
+type restoreTestBackend struct {
+	StorageBackend
+
+	events   []restoreTestEvent
+	settings BulkSettings
+	bulk     []*resourcepb.BulkRequest
+
+	// Used by the saved search tests
+	failNextUpdate bool
+}
+
+type restoreTestEvent struct {
+	key     *resourcepb.ResourceKey
+	rv      int64
+	folder  string
+	value   []byte
+	deleted bool
+}
+
+func (b *restoreTestBackend) write(rv int64, group, resource, name, folder string, generation int64) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(group + "/v1")
+	obj.SetKind("Test")
+	obj.SetName(name)
+	obj.SetNamespace("default")
+	obj.SetGeneration(generation)
+	value, _ := obj.MarshalJSON()
+	b.events = append(b.events, restoreTestEvent{
+		key:    &resourcepb.ResourceKey{Namespace: "default", Group: group, Resource: resource, Name: name},
+		rv:     rv,
+		folder: folder,
+		value:  value,
+	})
+}
+
+func (b *restoreTestBackend) deleted(rv int64, group, resource, name string) {
+	b.events = append(b.events, restoreTestEvent{
+		key:     &resourcepb.ResourceKey{Namespace: "default", Group: group, Resource: resource, Name: name},
+		rv:      rv,
+		deleted: true,
+	})
+}
+
+func (b *restoreTestBackend) ListIterator(_ context.Context, req *resourcepb.ListRequest, cb func(ListIterator) error) (int64, error) {
+	key := req.Options.Key
+	latest := make(map[string]restoreTestEvent)
+	var names []string
+	for _, ev := range b.events {
+		if ev.key.Group != key.Group || ev.key.Resource != key.Resource {
+			continue
+		}
+		if req.ResourceVersion > 0 && ev.rv > req.ResourceVersion {
+			continue
+		}
+		if _, ok := latest[ev.key.Name]; !ok {
+			names = append(names, ev.key.Name)
+		}
+		latest[ev.key.Name] = ev
+	}
+	iter := &restoreTestIterator{index: -1}
+	for _, name := range names {
+		if ev := latest[name]; !ev.deleted {
+			iter.events = append(iter.events, ev)
+		}
+	}
+	return 7, cb(iter)
+}
+
+func (b *restoreTestBackend) WatchWriteEvents(ctx context.Context) (<-chan *WrittenEvent, error) {
+	return make(chan *WrittenEvent), nil
+}
+
+func (b *restoreTestBackend) ProcessBulk(_ context.Context, setting BulkSettings, iter BulkRequestIterator) *resourcepb.BulkResponse {
+	b.settings = setting
+	for iter.Next() {
+		b.bulk = append(b.bulk, iter.Request())
+	}
+	return &resourcepb.BulkResponse{Processed: int64(len(b.bulk))}
+}
+
+type restoreTestIterator struct {
+	events []restoreTestEvent
+	index  int
+}
+
+func (i *restoreTestIterator) Next() bool {
+	i.index++
+	return i.index < len(i.events)
+}
+
+func (i *restoreTestIterator) Error() error           { return nil }
+func (i *restoreTestIterator) ContinueToken() string  { return "" }
+func (i *restoreTestIterator) ResourceVersion() int64 { return i.events[i.index].rv }
+func (i *restoreTestIterator) Namespace() string      { return i.events[i.index].key.Namespace }
+func (i *restoreTestIterator) Name() string           { return i.events[i.index].key.Name }
+func (i *restoreTestIterator) Folder() string         { return i.events[i.index].folder }
+func (i *restoreTestIterator) Value() []byte          { return i.events[i.index].value }
@@ -0,0 +1,436 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	claims "github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// Saved searches are stored as regular resources, so they have the same access control, history and trash.
+// The API is registered in pkg/registry/apis/savedsearch
+const (
+	SavedSearchGroup    = "search.grafana.app"
+	SavedSearchResource = "savedsearches"
+)
+
+// SavedSearchSpec is the spec of a saved search resource
+type SavedSearchSpec struct {
+	Title string `json:"title"`
+
+	// The search request in the protobuf JSON format.
+	// The query always runs in the namespace of the saved search.
+	Query json.RawMessage `json:"query"`
+
+	// Send a notification when the set of matching resources changes
+	Alert *SavedSearchAlert `json:"alert,omitempty"`
+}
+
+type SavedSearchAlert struct {
+	Emails   []string `json:"emails,omitempty"`
+	Webhooks []string `json:"webhooks,omitempty"`
+}
+
+// SavedSearchStatus keeps the results of the last evaluation, so they are shared by all the storage servers
+type SavedSearchStatus struct {
+	// The generation of the saved search the matches were recorded for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// The resources matching the query ({group}/{resource}/{name})
+	Matches []string `json:"matches,omitempty"`
+}
+
+// SearchAlertNotifier delivers the saved search alerts
+type SearchAlertNotifier interface {
+	NotifySearchAlert(ctx context.Context, alert *SearchAlert) error
+}
+
+// SearchAlert is sent when the resources matching a saved search change
+type SearchAlert struct {
+	// The saved search
+	Key   *resourcepb.ResourceKey
+	Title string
+
+	Emails   []string
+	Webhooks []string
+
+	// The resources that started or stopped matching the query ({group}/{resource}/{name})
+	Added   []string
+	Removed []string
+
+	// The number of resources matching the query
+	TotalHits int64
+}
+
+type SavedSearchOptions struct {
+	// Evaluate the saved searches with alerts, and send the notifications.
+	// When nil, saved searches are only stored.
+	Notifier SearchAlertNotifier
+
+	// The hosts the alert webhooks can be sent to, saved searches with other webhooks are rejected.
+	// When empty, webhooks can not be used.
+	AllowedWebhookHosts []string
+
+	// How often the saved searches are evaluated (defaults to one minute)
+	Interval time.Duration
+
+	// The maximum number of matching resources compared for every saved search (defaults to 1000)
+	MaxResults int64
+}
+
+type savedSearch struct {
+	spec    SavedSearchSpec
+	status  SavedSearchStatus
+	request *resourcepb.ResourceSearchRequest
+
+	generation int64
+
+	// The user who last changed the spec, the query runs with their permissions
+	owner string
+}
+
+// parseSavedSearch reads the saved search spec, and the search request
+func parseSavedSearch(key *resourcepb.ResourceKey, value []byte, allowedWebhookHosts []string) (*savedSearch, error) {
+	obj := &struct {
+		Metadata struct {
+			Generation  int64             `json:"generation"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+		Spec   SavedSearchSpec   `json:"spec"`
+		Status SavedSearchStatus `json:"status"`
+	}{}
+	if err := json.Unmarshal(value, obj); err != nil {
+		return nil, err
+	}
+
+	s := &savedSearch{
+		spec:       obj.Spec,
+		status:     obj.Status,
+		request:    &resourcepb.ResourceSearchRequest{},
+		generation: obj.Metadata.Generation,
+		owner:      obj.Metadata.Annotations[utils.AnnoKeyUpdatedBy],
+	}
+	if s.owner == "" {
+		s.owner = obj.Metadata.Annotations[utils.AnnoKeyCreatedBy]
+	}
+	if len(s.spec.Query) == 0 {
+		return nil, fmt.Errorf("missing query")
+	}
+	if err := protojson.Unmarshal(s.spec.Query, s.request); err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+	if s.request.Options == nil || s.request.Options.Key == nil ||
+		s.request.Options.Key.Group == "" || s.request.Options.Key.Resource == "" {
+		return nil, fmt.Errorf("the query must include a group and resource")
+	}
+	if s.request.Options.Key.Namespace != "" && s.request.Options.Key.Namespace != key.Namespace {
+		return nil, fmt.Errorf("the query must use the namespace of the saved search")
+	}
+	s.request.Options.Key.Namespace = key.Namespace
+
+	if s.spec.Alert != nil {
+		for _, email := range s.spec.Alert.Emails {
+			if !strings.Contains(email, "@") {
+				return nil, fmt.Errorf("invalid alert email: %q", email)
+			}
+		}
+		for _, webhook := range s.spec.Alert.Webhooks {
+			u, err := url.Parse(webhook)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, fmt.Errorf("invalid alert webhook: %q", webhook)
+			}
+			if !slices.ContainsFunc(allowedWebhookHosts, func(host string) bool {
+				return strings.EqualFold(host, u.Hostname())
+			}) {
+				return nil, fmt.Errorf("alert webhook host is not allowed: %q", u.Hostname())
+			}
+		}
+	}
+	return s, nil
+}
+
+func (s *savedSearch) hasAlert() bool {
+	return s.spec.Alert != nil && (len(s.spec.Alert.Emails) > 0 || len(s.spec.Alert.Webhooks) > 0)
+}
+
+// ownerIdentity returns the identity the query runs as, only users and service accounts can own a saved search
+func (s *savedSearch) ownerIdentity(namespace string, orgID int64) (identity.Requester, error) {
+	typ, uid, err := claims.ParseTypeID(s.owner)
+	if err != nil {
+		return nil, fmt.Errorf("unknown owner: %w", err)
+	}
+	if !claims.IsIdentityType(typ, claims.TypeUser, claims.TypeServiceAccount) {
+		return nil, fmt.Errorf("saved searches can not be owned by %s", typ)
+	}
+	return &identity.StaticRequester{
+		Type:      typ,
+		UserUID:   uid,
+		OrgID:     orgID,
+		Namespace: namespace,
+	}, nil
+}
+
+func isSavedSearch(key *resourcepb.ResourceKey) bool {
+	return key.Group == SavedSearchGroup && key.Resource == SavedSearchResource
+}
+
+// savedSearchAlerts periodically runs the saved searches with alerts,
+// and sends a notification when the matching resources are different from the previous run.
+// The results are kept in the saved search status, and every change is only notified by the server that wrote it.
+type savedSearchAlerts struct {
+	log        log.Logger
+	backend    StorageBackend
+	search     resourcepb.ResourceIndexServer
+	notifier   SearchAlertNotifier
+	webhooks   []string
+	interval   time.Duration
+	maxResults int64
+
+	// Writes the saved search, and fails when it changed after the previous version
+	update func(ctx context.Context, key *resourcepb.ResourceKey, value []byte, previousRV int64) (int64, error)
+}
+
+func newSavedSearchAlerts(opts SavedSearchOptions, backend StorageBackend, search resourcepb.ResourceIndexServer,
+	update func(ctx context.Context, key *resourcepb.ResourceKey, value []byte, previousRV int64) (int64, error)) *savedSearchAlerts {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	if opts.MaxResults <= 0 {
+		opts.MaxResults = 1000
+	}
+	return &savedSearchAlerts{
+		log:        log.New("saved-search-alerts"),
+		backend:    backend,
+		search:     search,
+		notifier:   opts.Notifier,
+		webhooks:   opts.AllowedWebhookHosts,
+		interval:   opts.Interval,
+		maxResults: opts.MaxResults,
+		update:     update,
+	}
+}
+
+func (a *savedSearchAlerts) run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.evaluate(ctx); err != nil {
+			a.log.Warn("failed to evaluate saved searches", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// evaluate runs every saved search with an alert.
+// The first run of a saved search (or after it changed) only records the results.
+func (a *savedSearchAlerts) evaluate(ctx context.Context) error {
+	type found struct {
+		key   *resourcepb.ResourceKey
+		rv    int64
+		value []byte
+	}
+	var items []found
+	_, err := a.backend.ListIterator(ctx, &resourcepb.ListRequest{
+		Options: &resourcepb.ListOptions{
+			Key: &resourcepb.ResourceKey{
+				Group:    SavedSearchGroup,
+				Resource: SavedSearchResource,
+			},
+		},
+	}, func(iter ListIterator) error {
+		for iter.Next() {
+			if err := iter.Error(); err != nil {
+				return err
+			}
+			items = append(items, found{
+				key: &resourcepb.ResourceKey{
+					Namespace: iter.Namespace(),
+					Group:     SavedSearchGroup,
+					Resource:  SavedSearchResource,
+					Name:      iter.Name(),
+				},
+				rv:    iter.ResourceVersion(),
+				value: iter.Value(),
+			})
+		}
+		return iter.Error()
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		saved, err := parseSavedSearch(item.key, item.value, a.webhooks)
+		if err != nil {
+			a.log.Warn("skipping invalid saved search", "namespace", item.key.Namespace, "name", item.key.Name, "err", err)
+			continue
+		}
+		if !saved.hasAlert() {
+			continue
+		}
+		if err := a.evaluateSavedSearch(ctx, item.key, item.rv, item.value, saved); err != nil {
+			a.log.Warn("failed to evaluate saved search", "namespace", item.key.Namespace, "name", item.key.Name, "err", err)
+		}
+	}
+	return nil
+}
+
+func (a *savedSearchAlerts) evaluateSavedSearch(ctx context.Context, key *resourcepb.ResourceKey, rv int64, value []byte, saved *savedSearch) error {
+	ns, err := claims.ParseNamespace(key.Namespace)
+	if err != nil {
+		return err
+	}
+	owner, err := saved.ownerIdentity(key.Namespace, ns.OrgID)
+	if err != nil {
+		return err
+	}
+
+	// Only the keys are compared
+	req := saved.request
+	req.Limit = a.maxResults
+	req.Offset = 0
+	req.Page = 0
+	req.Fields = []string{SEARCH_FIELD_TITLE}
+	req.Facet = nil
+	req.Explain = false
+
+	// The alert only reports the resources the owner of the saved search can see
+	rsp, err := a.search.Search(identity.WithRequester(ctx, owner), req)
+	if err != nil {
+		return err
+	}
+	if rsp.Error != nil {
+		return GetError(rsp.Error)
+	}
+
+	var matches []string
+	if rsp.Results != nil {
+		for _, row := range rsp.Results.Rows {
+			matches = append(matches, fmt.Sprintf("%s/%s/%s", row.Key.Group, row.Key.Resource, row.Key.Name))
+		}
+	}
+	sort.Strings(matches)
+	if rsp.TotalHits > a.maxResults {
+		a.log.Info("saved search has more results than compared", "namespace", key.Namespace, "name", key.Name, "hits", rsp.TotalHits)
+	}
+
+	current := SavedSearchStatus{ObservedGeneration: saved.generation, Matches: matches}
+	if saved.status.ObservedGeneration != saved.generation {
+		_, err = a.writeStatus(ctx, key, rv, value, current)
+		if errors.Is(err, ErrOptimisticLockingFailed) {
+			return nil // recorded by another server, or the saved search changed
+		}
+		return err
+	}
+
+	alert := &SearchAlert{
+		Key:       key,
+		Title:     saved.spec.Title,
+		Emails:    saved.spec.Alert.Emails,
+		Webhooks:  saved.spec.Alert.Webhooks,
+		TotalHits: rsp.TotalHits,
+	}
+	for _, k := range matches {
+		if !slices.Contains(saved.status.Matches, k) {
+			alert.Added = append(alert.Added, k)
+		}
+	}
+	for _, k := range saved.status.Matches {
+		if !slices.Contains(matches, k) {
+			alert.Removed = append(alert.Removed, k)
+		}
+	}
+	if len(alert.Added) == 0 && len(alert.Removed) == 0 {
+		return nil
+	}
+	if alert.Title == "" {
+		alert.Title = key.Name
+	}
+
+	// Only the server that records the new results sends the notification
+	written, err := a.writeStatus(ctx, key, rv, value, current)
+	if errors.Is(err, ErrOptimisticLockingFailed) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Restore the previous results when the notification fails, so it is sent again on the next run
+	if err := a.notifier.NotifySearchAlert(ctx, alert); err != nil {
+		if _, e := a.writeStatus(ctx, key, written, value, saved.status); e != nil {
+			a.log.Warn("failed to restore the saved search status", "namespace", key.Namespace, "name", key.Name, "err", e)
+		}
+		return fmt.Errorf("notify: %w", err)
+	}
+	a.log.Info("sent saved search alert", "namespace", key.Namespace, "name", key.Name, "added", len(alert.Added), "removed", len(alert.Removed))
+	return nil
+}
+
+// writeStatus writes the saved search with the new status, when it was not changed since the previous version
+func (a *savedSearchAlerts) writeStatus(ctx context.Context, key *resourcepb.ResourceKey, previousRV int64, value []byte, status SavedSearchStatus) (int64, error) {
+	tmp := &unstructured.Unstructured{}
+	if err := tmp.UnmarshalJSON(value); err != nil {
+		return 0, err
+	}
+	raw, err := json.Marshal(status)
+	if err != nil {
+		return 0, err
+	}
+	field := map[string]any{}
+	if err = json.Unmarshal(raw, &field); err != nil {
+		return 0, err
+	}
+	tmp.Object["status"] = field
+	tmp.SetResourceVersion("")
+	value, err = tmp.MarshalJSON()
+	if err != nil {
+		return 0, err
+	}
+	return a.update(ctx, key, value, previousRV)
+}
+
+// updateSavedSearch writes the saved search status as the service
+func (s *server) updateSavedSearch(ctx context.Context, key *resourcepb.ResourceKey, value []byte, previousRV int64) (int64, error) {
+	ns, err := claims.ParseNamespace(key.Namespace)
+	if err != nil {
+		return 0, err
+	}
+	ctx, user := identity.WithServiceIdentity(ctx, ns.OrgID)
+
+	latest := s.backend.ReadResource(ctx, &resourcepb.ReadRequest{Key: key})
+	if latest.Error != nil {
+		return 0, GetError(latest.Error)
+	}
+	if latest.ResourceVersion != previousRV {
+		return 0, ErrOptimisticLockingFailed
+	}
+
+	event, e := s.newEvent(ctx, user, key, value, latest.Value)
+	if e != nil {
+		return 0, GetError(e)
+	}
+	event.Type = resourcepb.WatchEvent_MODIFIED
+	event.PreviousRV = previousRV
+	return s.backend.WriteEvent(ctx, *event)
+}
@@ -0,0 +1,231 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	claims "github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+func TestParseSavedSearch(t *testing.T) {
+	key := &resourcepb.ResourceKey{Namespace: "default", Group: SavedSearchGroup, Resource: SavedSearchResource, Name: "s1"}
+
+	t.Run("valid", func(t *testing.T) {
+		saved, err := parseSavedSearch(key, savedSearchValue("s1", `{
+			"options": {
+				"key": {"group": "dashboard.grafana.app", "resource": "dashboards"},
+				"fields": [{"key": "errors_last_1_days", "operator": ">", "values": ["0"]}]
+			}
+		}`, `{"webhooks": ["https://Example.com/hook"]}`), []string{"example.com"})
+		require.NoError(t, err)
+		require.True(t, saved.hasAlert())
+		require.Equal(t, "default", saved.request.Options.Key.Namespace)
+		require.Equal(t, "errors_last_1_days", saved.request.Options.Fields[0].Key)
+
+		owner, err := saved.ownerIdentity("default", 1)
+		require.NoError(t, err)
+		require.Equal(t, "user:owner", owner.GetUID())
+	})
+
+	for name, tc := range map[string]struct {
+		query string
+		alert string
+		err   string
+	}{
+		"missing query":     {query: ``, err: "missing query"},
+		"invalid query":     {query: `{"limit": "x"}`, err: "invalid query"},
+		"missing resource":  {query: `{"options": {"key": {"group": "dashboard.grafana.app"}}}`, err: "group and resource"},
+		"another namespace": {query: `{"options": {"key": {"namespace": "other", "group": "a", "resource": "b"}}}`, err: "namespace"},
+		"invalid webhook":   {query: `{"options": {"key": {"group": "a", "resource": "b"}}}`, alert: `{"webhooks": ["ftp://x"]}`, err: "webhook"},
+		"webhook host":      {query: `{"options": {"key": {"group": "a", "resource": "b"}}}`, alert: `{"webhooks": ["http://169.254.169.254/latest"]}`, err: "not allowed"},
+		"invalid email":     {query: `{"options": {"key": {"group": "a", "resource": "b"}}}`, alert: `{"emails": ["x"]}`, err: "email"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseSavedSearch(key, savedSearchValue("s1", tc.query, tc.alert), []string{"example.com"})
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestSavedSearchAlerts(t *testing.T) {
+	ctx := context.Background()
+	query := `{"options": {"key": {"group": "dashboard.grafana.app", "resource": "dashboards"}}}`
+
+	backend := &restoreTestBackend{}
+	backend.events = append(backend.events,
+		savedSearchEvent(1, "alerting", 1, query, `{"emails": ["team@example.com"]}`),
+		savedSearchEvent(1, "quiet", 1, query, ``),
+	)
+	index := &savedSearchTestIndex{results: []string{"a", "b"}}
+	notifier := &savedSearchTestNotifier{}
+	newAlerts := func() *savedSearchAlerts {
+		return newSavedSearchAlerts(SavedSearchOptions{Notifier: notifier}, backend, index, backend.updateSavedSearch)
+	}
+	alerts := newAlerts()
+
+	// The first run only records the results
+	require.NoError(t, alerts.evaluate(ctx))
+	require.Empty(t, notifier.alerts)
+	require.Len(t, index.requests, 1)
+	require.Equal(t, "default", index.requests[0].Options.Key.Namespace)
+	require.Equal(t, int64(1000), index.requests[0].Limit)
+	require.Equal(t, []string{"user:owner"}, index.users)
+	require.Equal(t, SavedSearchStatus{
+		ObservedGeneration: 1,
+		Matches:            []string{"dashboard.grafana.app/dashboards/a", "dashboard.grafana.app/dashboards/b"},
+	}, backend.savedSearchStatus(t, "alerting"))
+
+	// Unchanged results
+	require.NoError(t, alerts.evaluate(ctx))
+	require.Empty(t, notifier.alerts)
+
+	// The results are kept in the saved search, so another server sends the alert
+	alerts = newAlerts()
+	index.results = []string{"b", "c", "d"}
+	require.NoError(t, alerts.evaluate(ctx))
+	require.Len(t, notifier.alerts, 1)
+	alert := notifier.alerts[0]
+	require.Equal(t, "alerting", alert.Key.Name)
+	require.Equal(t, "alerting", alert.Title)
+	require.Equal(t, []string{"team@example.com"}, alert.Emails)
+	require.Equal(t, []string{"dashboard.grafana.app/dashboards/c", "dashboard.grafana.app/dashboards/d"}, alert.Added)
+	require.Equal(t, []string{"dashboard.grafana.app/dashboards/a"}, alert.Removed)
+	require.Equal(t, int64(3), alert.TotalHits)
+
+	// A failed notification is sent again
+	notifier.alerts = nil
+	notifier.err = fmt.Errorf("smtp not configured")
+	index.results = []string{"b"}
+	require.NoError(t, alerts.evaluate(ctx))
+	require.Empty(t, notifier.alerts)
+	notifier.err = nil
+	require.NoError(t, alerts.evaluate(ctx))
+	require.Len(t, notifier.alerts, 1)
+	require.Equal(t, []string{"dashboard.grafana.app/dashboards/c", "dashboard.grafana.app/dashboards/d"}, notifier.alerts[0].Removed)
+
+	// Only one of the servers evaluating the same version sends the alert
+	notifier.alerts = nil
+	index.results = []string{"e"}
+	backend.failNextUpdate = true
+	require.NoError(t, alerts.evaluate(ctx))
+	require.Empty(t, notifier.alerts)
+
+	// Changing the saved search records the results again
+	backend.events = append(backend.events, savedSearchEvent(100, "alerting", 2, query, `{"emails": ["team@example.com"]}`))
+	index.results = []string{"x"}
+	require.NoError(t, alerts.evaluate(ctx))
+	require.Empty(t, notifier.alerts)
+	require.Equal(t, int64(2), backend.savedSearchStatus(t, "alerting").ObservedGeneration)
+}
+
+// updateSavedSearch appends the new version, unless the saved search changed
+func (b *restoreTestBackend) updateSavedSearch(_ context.Context, key *resourcepb.ResourceKey, value []byte, previousRV int64) (int64, error) {
+	latest := int64(0)
+	for _, ev := range b.events {
+		if ev.key.Name == key.Name && ev.rv > latest {
+			latest = ev.rv
+		}
+	}
+	if latest != previousRV || b.failNextUpdate {
+		b.failNextUpdate = false
+		return 0, ErrOptimisticLockingFailed
+	}
+	b.events = append(b.events, restoreTestEvent{key: key, rv: latest + 1, value: value})
+	return latest + 1, nil
+}
+
+func (b *restoreTestBackend) savedSearchStatus(t *testing.T, name string) SavedSearchStatus {
+	t.Helper()
+	var value []byte
+	for _, ev := range b.events {
+		if ev.key.Name == name {
+			value = ev.value
+		}
+	}
+	obj := &struct {
+		Status SavedSearchStatus `json:"status"`
+	}{}
+	require.NoError(t, json.Unmarshal(value, obj))
+	return obj.Status
+}
+
+func savedSearchValue(name string, query string, alert string) []byte {
+	return savedSearchValueWithGeneration(name, 1, query, alert)
+}
+
+func savedSearchValueWithGeneration(name string, generation int64, query string, alert string) []byte {
+	spec := map[string]any{"title": ""}
+	if query != "" {
+		spec["query"] = json.RawMessage(query)
+	}
+	if alert != "" {
+		spec["alert"] = json.RawMessage(alert)
+	}
+	value, _ := json.Marshal(map[string]any{
+		"apiVersion": SavedSearchGroup + "/v0alpha1",
+		"kind":       "SavedSearch",
+		"metadata": map[string]any{
+			"name":        name,
+			"namespace":   "default",
+			"generation":  generation,
+			"annotations": map[string]any{utils.AnnoKeyCreatedBy: "user:owner"},
+		},
+		"spec": spec,
+	})
+	return value
+}
+
+func savedSearchEvent(rv int64, name string, generation int64, query string, alert string) restoreTestEvent {
+	return restoreTestEvent{
+		key:   &resourcepb.ResourceKey{Namespace: "default", Group: SavedSearchGroup, Resource: SavedSearchResource, Name: name},
+		rv:    rv,
+		value: savedSearchValueWithGeneration(name, generation, query, alert),
+	}
+}
+
+type savedSearchTestIndex struct {
+	resourcepb.UnimplementedResourceIndexServer
+
+	results  []string
+	requests []*resourcepb.ResourceSearchRequest
+	users    []string
+}
+
+func (i *savedSearchTestIndex) Search(ctx context.Context, req *resourcepb.ResourceSearchRequest) (*resourcepb.ResourceSearchResponse, error) {
+	user, ok := claims.AuthInfoFrom(ctx)
+	if !ok {
+		return nil, fmt.Errorf("missing auth info")
+	}
+	i.requests = append(i.requests, req)
+	i.users = append(i.users, user.GetUID())
+	rsp := &resourcepb.ResourceSearchResponse{
+		Results:   &resourcepb.ResourceTable{},
+		TotalHits: int64(len(i.results)),
+	}
+	for _, name := range i.results {
+		rsp.Results.Rows = append(rsp.Results.Rows, &resourcepb.ResourceTableRow{
+			Key: &resourcepb.ResourceKey{Namespace: "default", Group: "dashboard.grafana.app", Resource: "dashboards", Name: name},
+		})
+	}
+	return rsp, nil
+}
+
+type savedSearchTestNotifier struct {
+	alerts []*SearchAlert
+	err    error
+}
+
+func (n *savedSearchTestNotifier) NotifySearchAlert(_ context.Context, alert *SearchAlert) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
@@ -73,6 +73,16 @@ type ResourceIndex interface {
 	DocCount(ctx context.Context, folder string) (int64, error)
 }
 
+// RestoredIndex is implemented by the indexes that can be restored from a snapshot.
+// A restored index only needs the documents that changed since the snapshot.
+type RestoredIndex interface {
+	// The resource version of the snapshot, or 0 when the index was not restored
+	RestoredRV() int64
+
+	// The resource version of every document in the index, by name
+	DocumentVersions(ctx context.Context) (map[string]int64, error)
+}
+
 // SearchBackend contains the technology specific logic to support search
 type SearchBackend interface {
 	// This will return nil if the key does not exist
@@ -100,6 +110,12 @@ type SearchBackend interface {
 	TotalDocs() int64
 }
 
+// SearchBackendStopper is implemented by the search backends that run work in the background
+type SearchBackendStopper interface {
+	// Stop the background work, and wait for it to return
+	Stop()
+}
+
 const tracingPrexfixSearch = "unified_search."
 
 // This supports indexing+search regardless of implementation
@@ -349,9 +365,10 @@ func (s *searchSupport) GetStats(ctx context.Context, req *resourcepb.ResourceSt
 	if req.Folder == "" {
 		for i, stat := range stats {
 			rsp.Stats[i] = &resourcepb.ResourceStatsResponse_Stats{
-				Group:    stat.Group,
-				Resource: stat.Resource,
-				Count:    stat.Count,
+				Group:     stat.Group,
+				Resource:  stat.Resource,
+				Count:     stat.Count,
+				Retention: stat.Retention.AsProto(),
 			}
 		}
 		return rsp, nil
@@ -373,9 +390,10 @@ func (s *searchSupport) GetStats(ctx context.Context, req *resourcepb.ResourceSt
 			return rsp, nil
 		}
 		rsp.Stats[i] = &resourcepb.ResourceStatsResponse_Stats{
-			Group:    stat.Group,
-			Resource: stat.Resource,
-			Count:    count,
+			Group:     stat.Group,
+			Resource:  stat.Resource,
+			Count:     count,
+			Retention: stat.Retention.AsProto(),
 		}
 	}
 	return rsp, nil
@@ -417,6 +435,13 @@ func (s *searchSupport) buildIndexes(ctx context.Context, rebuild bool) (int, er
 	return totalBatchesIndexed, nil
 }
 
+// stop the background work of the search backend
+func (s *searchSupport) stop() {
+	if stopper, ok := s.search.(SearchBackendStopper); ok {
+		stopper.Stop()
+	}
+}
+
 func (s *searchSupport) init(ctx context.Context) error {
 	ctx, span := s.tracer.Start(ctx, tracingPrexfixSearch+"Init")
 	defer span.End()
@@ -627,6 +652,16 @@ func (s *searchSupport) build(ctx context.Context, nsr NamespacedResource, size
 	logger.Debug("Building index", "resource", nsr.Resource, "size", size, "rv", rv)
 
 	index, err := s.search.BuildIndex(ctx, nsr, size, rv, fields, func(index ResourceIndex) (int64, error) {
+		// The documents in an index restored from a snapshot, they are only indexed again when they changed
+		var indexed map[string]int64
+		if restored, ok := index.(RestoredIndex); ok && restored.RestoredRV() > 0 {
+			indexed, err = restored.DocumentVersions(ctx)
+			if err != nil {
+				return 0, err
+			}
+			logger.Info("Updating index restored from snapshot", "snapshot_rv", restored.RestoredRV(), "docs", len(indexed))
+		}
+
 		rv, err = s.storage.ListIterator(ctx, &resourcepb.ListRequest{
 			Limit: 1000000000000, // big number
 			Options: &resourcepb.ListOptions{
@@ -647,6 +682,14 @@ func (s *searchSupport) build(ctx context.Context, nsr NamespacedResource, size
 					return err
 				}
 
+				if indexed != nil {
+					indexedRV, found := indexed[iter.Name()]
+					delete(indexed, iter.Name())
+					if found && indexedRV == iter.ResourceVersion() {
+						continue
+					}
+				}
+
 				// Update the key name
 				key := &resourcepb.ResourceKey{
 					Group:     nsr.Group,
@@ -681,6 +724,23 @@ func (s *searchSupport) build(ctx context.Context, nsr NamespacedResource, size
 				}
 			}
 
+			if err = iter.Error(); err != nil {
+				return err
+			}
+
+			// Remove the documents that were deleted since the snapshot
+			for name := range indexed {
+				items = append(items, &BulkIndexItem{
+					Action: ActionDelete,
+					Key: &resourcepb.ResourceKey{
+						Group:     nsr.Group,
+						Resource:  nsr.Resource,
+						Namespace: nsr.Namespace,
+						Name:      name,
+					},
+				})
+			}
+
 			// Index any remaining items in the final batch.
 			if len(items) > 0 {
 				if err = index.BulkIndex(&BulkIndexRequest{
@@ -689,7 +749,7 @@ func (s *searchSupport) build(ctx context.Context, nsr NamespacedResource, size
 					return err
 				}
 			}
-			return iter.Error()
+			return nil
 		})
 		return rv, err
 	})
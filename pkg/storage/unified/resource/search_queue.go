@@ -126,6 +126,9 @@ func (b *indexQueueProcessor) process(batch []*WrittenEvent) {
 			}
 		}
 		req.Items = append(req.Items, item)
+		if evt.ResourceVersion > req.ResourceVersion {
+			req.ResourceVersion = evt.ResourceVersion
+		}
 	}
 
 	err := b.index.BulkIndex(req)
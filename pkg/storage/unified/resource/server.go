@@ -32,6 +32,7 @@ type ResourceServer interface {
 	resourcepb.ManagedObjectIndexServer
 	resourcepb.BlobStoreServer
 	resourcepb.DiagnosticsServer
+	resourcepb.TrashServer
 }
 
 type ListIterator interface {
@@ -115,6 +116,42 @@ type ResourceStats struct {
 
 	Count           int64
 	ResourceVersion int64
+
+	// The history retention of the resource, nil when every version is kept
+	Retention *HistoryRetention
+}
+
+// HistoryRetention defines which versions of a resource are kept in the history.
+// A version is kept when any of the rules keeps it, and the latest version is always kept.
+type HistoryRetention struct {
+	Group    string `json:"group"`
+	Resource string `json:"resource"`
+
+	// Keep the latest versions
+	Versions int `json:"versions,omitempty"`
+
+	// Keep every version written in this period
+	All time.Duration `json:"all,omitempty"`
+
+	// Keep the last version of each day in this period
+	Daily time.Duration `json:"daily,omitempty"`
+}
+
+// IsZero is true when the retention does not remove any version
+func (r HistoryRetention) IsZero() bool {
+	return r.Versions <= 0 && r.All <= 0 && r.Daily <= 0
+}
+
+// AsProto converts the retention for the stats response, nil when every version is kept
+func (r *HistoryRetention) AsProto() *resourcepb.ResourceStatsResponse_HistoryRetention {
+	if r == nil || r.IsZero() {
+		return nil
+	}
+	return &resourcepb.ResourceStatsResponse_HistoryRetention{
+		Versions: int64(r.Versions),
+		All:      r.All.Milliseconds(),
+		Daily:    r.Daily.Milliseconds(),
+	}
 }
 
 // This interface is not exposed to end users directly
@@ -200,6 +237,13 @@ type ResourceServerOptions struct {
 	IndexMetrics *BleveIndexMetrics
 
 	MaxPageSizeBytes int
+
+	// Deleted resources are purged from the trash after this period, when the backend supports it.
+	// Zero keeps deleted resources forever
+	TrashRetention time.Duration
+
+	// Alerts for the saved searches, this requires search support
+	SavedSearch SavedSearchOptions
 }
 
 func NewResourceServer(opts ResourceServerOptions) (ResourceServer, error) {
@@ -271,6 +315,7 @@ func NewResourceServer(opts ResourceServerOptions) (ResourceServer, error) {
 		storageMetrics:   opts.storageMetrics,
 		indexMetrics:     opts.IndexMetrics,
 		maxPageSizeBytes: opts.MaxPageSizeBytes,
+		trashRetention:   opts.TrashRetention,
 	}
 
 	if opts.Search.Resources != nil {
@@ -281,6 +326,14 @@ func NewResourceServer(opts ResourceServerOptions) (ResourceServer, error) {
 		}
 	}
 
+	s.savedSearchWebhooks = opts.SavedSearch.AllowedWebhookHosts
+	if opts.SavedSearch.Notifier != nil {
+		if s.search == nil {
+			return nil, fmt.Errorf("saved search alerts require search support")
+		}
+		s.savedSearchAlerts = newSavedSearchAlerts(opts.SavedSearch, s.backend, s.search, s.updateSavedSearch)
+	}
+
 	err := s.Init(ctx)
 	if err != nil {
 		s.log.Error("resource server init failed", "error", err)
@@ -316,7 +369,12 @@ type server struct {
 	once    sync.Once
 	initErr error
 
-	maxPageSizeBytes int
+	maxPageSizeBytes  int
+	trashRetention    time.Duration
+	savedSearchAlerts *savedSearchAlerts
+
+	// The hosts allowed in saved search webhooks
+	savedSearchWebhooks []string
 }
 
 // Init implements ResourceServer.
@@ -340,6 +398,27 @@ func (s *server) Init(ctx context.Context) error {
 			s.initErr = s.initWatcher()
 		}
 
+		// Purge the trash in the background
+		if s.initErr == nil && s.trashRetention > 0 {
+			if purger, ok := s.backend.(TrashPurger); ok {
+				go s.runTrashRetention(purger, s.trashRetention)
+			} else {
+				s.log.Warn("trash retention is not supported by the storage backend")
+			}
+		}
+
+		// Remove the blob chunks no longer referenced in the background
+		if s.initErr == nil {
+			if gc, ok := s.blob.(BlobGarbageCollector); ok {
+				go s.runBlobGarbageCollection(gc)
+			}
+		}
+
+		// Evaluate the saved search alerts in the background
+		if s.initErr == nil && s.savedSearchAlerts != nil {
+			go s.savedSearchAlerts.run(s.ctx)
+		}
+
 		if s.initErr != nil {
 			s.log.Error("error running resource server init", "error", s.initErr)
 		}
@@ -362,6 +441,11 @@ func (s *server) Stop(ctx context.Context) error {
 	// Stops the streaming
 	s.cancel()
 
+	// Stops the background work of the search index
+	if s.search != nil {
+		s.search.stop()
+	}
+
 	// mark the value as done
 	if stopFailed {
 		return s.initErr
@@ -457,6 +541,11 @@ func (s *server) newEvent(ctx context.Context, user claims.AuthInfo, key *resour
 	if err := validateName(obj.GetName()); err != nil {
 		return nil, err
 	}
+	if isSavedSearch(key) {
+		if _, err := parseSavedSearch(key, value, s.savedSearchWebhooks); err != nil {
+			return nil, NewBadRequestError(fmt.Sprintf("invalid saved search: %s", err))
+		}
+	}
 
 	// For folder moves, we need to check permissions on both folders
 	if s.isFolderMove(event) {
@@ -692,8 +781,22 @@ func (s *server) Delete(ctx context.Context, req *resourcepb.DeleteRequest) (*re
 	if !ok {
 		return nil, apierrors.NewBadRequest("unable to get user")
 	}
+	event.Value, err = newDeletionMarker(latest.Value, now, requester.GetUID())
+	if err != nil {
+		return nil, err
+	}
+
+	rsp.ResourceVersion, err = s.backend.WriteEvent(ctx, event)
+	if err != nil {
+		rsp.Error = AsErrorResult(err)
+	}
+	return rsp, nil
+}
+
+// newDeletionMarker returns the value written to history when a resource is deleted
+func newDeletionMarker(previous []byte, now metav1.Time, updatedBy string) ([]byte, error) {
 	marker := &unstructured.Unstructured{}
-	err = json.Unmarshal(latest.Value, marker)
+	err := json.Unmarshal(previous, marker)
 	if err != nil {
 		return nil, apierrors.NewBadRequest(
 			fmt.Sprintf("unable to read previous object, %v", err))
@@ -706,20 +809,15 @@ func (s *server) Delete(ctx context.Context, req *resourcepb.DeleteRequest) (*re
 	obj.SetUpdatedTimestamp(&now.Time)
 	obj.SetManagedFields(nil)
 	obj.SetFinalizers(nil)
-	obj.SetUpdatedBy(requester.GetUID())
+	obj.SetUpdatedBy(updatedBy)
 	obj.SetGeneration(utils.DeletedGeneration)
 	obj.SetAnnotation(utils.AnnoKeyKubectlLastAppliedConfig, "") // clears it
-	event.Value, err = marker.MarshalJSON()
+	value, err := marker.MarshalJSON()
 	if err != nil {
 		return nil, apierrors.NewBadRequest(
 			fmt.Sprintf("unable creating deletion marker, %v", err))
 	}
-
-	rsp.ResourceVersion, err = s.backend.WriteEvent(ctx, event)
-	if err != nil {
-		rsp.Error = AsErrorResult(err)
-	}
-	return rsp, nil
+	return value, nil
 }
 
 func (s *server) Read(ctx context.Context, req *resourcepb.ReadRequest) (*resourcepb.ReadResponse, error) {
@@ -1120,6 +1218,11 @@ func (s *server) IsHealthy(ctx context.Context, req *resourcepb.HealthCheckReque
 	return s.diagnostics.IsHealthy(ctx, req)
 }
 
+// ListOnlineMigrations implements ResourceServer.
+func (s *server) ListOnlineMigrations(ctx context.Context, req *resourcepb.ListOnlineMigrationsRequest) (*resourcepb.ListOnlineMigrationsResponse, error) {
+	return s.diagnostics.ListOnlineMigrations(ctx, req)
+}
+
 // GetBlob implements BlobStore.
 func (s *server) PutBlob(ctx context.Context, req *resourcepb.PutBlobRequest) (*resourcepb.PutBlobResponse, error) {
 	if s.blob == nil {
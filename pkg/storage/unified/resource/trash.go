@@ -0,0 +1,409 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	claims "github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// TrashPurger is implemented by backends that can permanently remove deleted resources
+type TrashPurger interface {
+	// Remove the full history of resources where the latest event is a deletion before the given time.
+	// Empty key fields match everything. Returns the number of history entries removed.
+	PurgeTrash(ctx context.Context, key *resourcepb.ResourceKey, deletedBefore time.Time) (int64, error)
+
+	// List the namespace+group+resource of every collection with resources deleted before the given time
+	ListTrashCollections(ctx context.Context, deletedBefore time.Time) ([]*resourcepb.ResourceKey, error)
+}
+
+// ListTrash implements TrashServer.
+func (s *server) ListTrash(ctx context.Context, req *resourcepb.ListTrashRequest) (*resourcepb.ListTrashResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "storage_server.ListTrash")
+	defer span.End()
+
+	rsp := &resourcepb.ListTrashResponse{}
+	user, ok := claims.AuthInfoFrom(ctx)
+	if !ok || user == nil {
+		rsp.Error = &resourcepb.ErrorResult{
+			Message: "no user found in context",
+			Code:    http.StatusUnauthorized,
+		}
+		return rsp, nil
+	}
+	if err := s.listTrash(ctx, user, req, rsp); err != nil {
+		rsp.Error = AsErrorResult(err)
+		rsp.Items = nil
+	}
+	return rsp, nil
+}
+
+func (s *server) listTrash(ctx context.Context, user claims.AuthInfo, req *resourcepb.ListTrashRequest, rsp *resourcepb.ListTrashResponse) error {
+	collections, err := trashCollections(req.Namespace, req.Resources)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range collections {
+		checker, err := s.access.Compile(ctx, user, claims.ListRequest{
+			Group:     key.Group,
+			Resource:  key.Resource,
+			Namespace: key.Namespace,
+			Verb:      utils.VerbGet,
+		})
+		if err != nil {
+			return err
+		}
+		if checker == nil {
+			return apierrors.NewForbidden(schema.GroupResource{Group: key.Group, Resource: key.Resource}, "",
+				fmt.Errorf("requester must be able to: %s", utils.VerbGet))
+		}
+
+		_, err = s.backend.ListHistory(ctx, &resourcepb.ListRequest{
+			Source:  resourcepb.ListRequest_TRASH,
+			Options: &resourcepb.ListOptions{Key: key},
+		}, func(iter ListIterator) error {
+			for iter.Next() {
+				if err := iter.Error(); err != nil {
+					return err
+				}
+				if !checker(iter.Name(), iter.Folder()) {
+					continue
+				}
+				item, err := newTrashItem(key, iter)
+				if err != nil {
+					s.log.Warn("skipping invalid deletion marker", "key", NSGR(key), "name", iter.Name(), "err", err)
+					continue
+				}
+				rsp.Items = append(rsp.Items, item)
+			}
+			return iter.Error()
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	sort.SliceStable(rsp.Items, func(i, j int) bool {
+		return rsp.Items[i].ResourceVersion > rsp.Items[j].ResourceVersion
+	})
+	return nil
+}
+
+func newTrashItem(key *resourcepb.ResourceKey, iter ListIterator) (*resourcepb.ListTrashResponse_Item, error) {
+	tmp := &unstructured.Unstructured{}
+	if err := json.Unmarshal(iter.Value(), tmp); err != nil {
+		return nil, err
+	}
+	obj, err := utils.MetaAccessor(tmp)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &resourcepb.ListTrashResponse_Item{
+		Key: &resourcepb.ResourceKey{
+			Namespace: key.Namespace,
+			Group:     key.Group,
+			Resource:  key.Resource,
+			Name:      iter.Name(),
+		},
+		Folder:          iter.Folder(),
+		ResourceVersion: iter.ResourceVersion(),
+		DeletedBy:       obj.GetUpdatedBy(),
+		Value:           iter.Value(),
+	}
+	if ts := obj.GetDeletionTimestamp(); ts != nil {
+		item.DeletedAt = ts.UnixMilli()
+	}
+	return item, nil
+}
+
+// RestoreFromTrash implements TrashServer.
+func (s *server) RestoreFromTrash(ctx context.Context, req *resourcepb.RestoreFromTrashRequest) (*resourcepb.RestoreFromTrashResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "storage_server.RestoreFromTrash")
+	defer span.End()
+
+	rsp := &resourcepb.RestoreFromTrashResponse{}
+	user, ok := claims.AuthInfoFrom(ctx)
+	if !ok || user == nil {
+		rsp.Error = &resourcepb.ErrorResult{
+			Message: "no user found in context",
+			Code:    http.StatusUnauthorized,
+		}
+		return rsp, nil
+	}
+	if err := validateRestoreKey(req.Key); err != nil {
+		rsp.Error = AsErrorResult(err)
+		return rsp, nil
+	}
+
+	// The folders restored before a failure stay restored, so they are still reported
+	err := s.restoreFromTrash(ctx, user, req.Key, req.ResourceVersion, map[string]bool{}, rsp)
+	if err != nil {
+		rsp.Error = AsErrorResult(err)
+	}
+	return rsp, nil
+}
+
+func validateRestoreKey(key *resourcepb.ResourceKey) error {
+	if key == nil {
+		return apierrors.NewBadRequest("missing key")
+	}
+	if key.Namespace == "" || key.Group == "" || key.Resource == "" || key.Name == "" {
+		return apierrors.NewBadRequest("the key must include namespace, group, resource and name")
+	}
+	return nil
+}
+
+// restoreFromTrash recreates a resource from its deletion, after restoring any missing parent folders
+func (s *server) restoreFromTrash(ctx context.Context, user claims.AuthInfo, key *resourcepb.ResourceKey, rv int64, visited map[string]bool, rsp *resourcepb.RestoreFromTrashResponse) error {
+	id := SearchID(key)
+	if visited[id] {
+		return apierrors.NewBadRequest(fmt.Sprintf("circular folder reference: %s", key.Name))
+	}
+	visited[id] = true
+
+	deleted, err := s.findDeletion(ctx, key, rv)
+	if err != nil {
+		return err
+	}
+
+	// The deletion marker does not keep the generation, so restore the value written before it
+	previous := s.backend.ReadResource(ctx, &resourcepb.ReadRequest{
+		Key:             key,
+		ResourceVersion: deleted.ResourceVersion - 1,
+	})
+	if previous.Error != nil {
+		return GetError(previous.Error)
+	}
+
+	tmp := &unstructured.Unstructured{}
+	if err := json.Unmarshal(previous.Value, tmp); err != nil {
+		return fmt.Errorf("read previous value: %w", err)
+	}
+	obj, err := utils.MetaAccessor(tmp)
+	if err != nil {
+		return err
+	}
+
+	if folder := obj.GetFolder(); folder != "" {
+		if err := s.ensureParentFolder(ctx, user, key.Namespace, folder, visited, rsp); err != nil {
+			return err
+		}
+	}
+
+	now := time.UnixMilli(s.now())
+	obj.SetResourceVersion("")
+	obj.SetDeletionTimestamp(nil)
+	obj.SetUpdatedTimestamp(&now)
+	obj.SetUpdatedBy(user.GetUID())
+	obj.SetGeneration(obj.GetGeneration() + 1)
+	value, err := tmp.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	created, err := s.Create(ctx, &resourcepb.CreateRequest{Key: key, Value: value})
+	if err != nil {
+		return err
+	}
+	if created.Error != nil {
+		return GetError(created.Error)
+	}
+
+	s.log.Info("restored resource from trash", "key", NSGR(key), "name", key.Name, "rv", created.ResourceVersion)
+	rsp.Restored = append(rsp.Restored, &resourcepb.RestoreFromTrashResponse_Restored{
+		Key:             key,
+		ResourceVersion: created.ResourceVersion,
+	})
+	return nil
+}
+
+// ensureParentFolder restores the folder from the trash when it no longer exists
+func (s *server) ensureParentFolder(ctx context.Context, user claims.AuthInfo, namespace string, folder string, visited map[string]bool, rsp *resourcepb.RestoreFromTrashResponse) error {
+	key := &resourcepb.ResourceKey{
+		Namespace: namespace,
+		Group:     folderGroup,
+		Resource:  folderResource,
+		Name:      folder,
+	}
+	found := s.backend.ReadResource(ctx, &resourcepb.ReadRequest{Key: key})
+	if found.Error == nil {
+		return nil
+	}
+	if found.Error.Code != http.StatusNotFound {
+		return GetError(found.Error)
+	}
+
+	err := s.restoreFromTrash(ctx, user, key, 0, visited, rsp)
+	if apierrors.IsNotFound(err) {
+		return apierrors.NewBadRequest(fmt.Sprintf("parent folder %q does not exist and can not be restored", folder))
+	}
+	return err
+}
+
+// findDeletion returns the deletion to restore, the latest one when rv is not set
+func (s *server) findDeletion(ctx context.Context, key *resourcepb.ResourceKey, rv int64) (*resourcepb.ListTrashResponse_Item, error) {
+	req := &resourcepb.ListRequest{
+		Source:  resourcepb.ListRequest_TRASH,
+		Options: &resourcepb.ListOptions{Key: key},
+	}
+	if rv > 0 {
+		req.ResourceVersion = rv
+		req.VersionMatchV2 = resourcepb.ResourceVersionMatchV2_Exact
+	}
+
+	var item *resourcepb.ListTrashResponse_Item
+	_, err := s.backend.ListHistory(ctx, req, func(iter ListIterator) error {
+		if iter.Next() {
+			if err := iter.Error(); err != nil {
+				return err
+			}
+			var err error
+			item, err = newTrashItem(key, iter)
+			return err
+		}
+		return iter.Error()
+	})
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: key.Group, Resource: key.Resource}, key.Name)
+	}
+	return item, nil
+}
+
+// PurgeTrash implements TrashServer.
+func (s *server) PurgeTrash(ctx context.Context, req *resourcepb.PurgeTrashRequest) (*resourcepb.PurgeTrashResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "storage_server.PurgeTrash")
+	defer span.End()
+
+	rsp := &resourcepb.PurgeTrashResponse{}
+	user, ok := claims.AuthInfoFrom(ctx)
+	if !ok || user == nil {
+		rsp.Error = &resourcepb.ErrorResult{
+			Message: "no user found in context",
+			Code:    http.StatusUnauthorized,
+		}
+		return rsp, nil
+	}
+	collections, err := trashCollections(req.Namespace, req.Resources)
+	if err != nil {
+		rsp.Error = AsErrorResult(err)
+		return rsp, nil
+	}
+	purger, ok := s.backend.(TrashPurger)
+	if !ok {
+		rsp.Error = &resourcepb.ErrorResult{
+			Message: "the server backend does not support purging deleted resources",
+			Code:    http.StatusNotImplemented,
+			Reason:  string(metav1.StatusReasonMethodNotAllowed),
+		}
+		return rsp, nil
+	}
+
+	// Check everything before removing anything
+	for _, key := range collections {
+		a, err := s.access.Check(ctx, user, claims.CheckRequest{
+			Namespace: key.Namespace,
+			Group:     key.Group,
+			Resource:  key.Resource,
+			Verb:      utils.VerbDeleteCollection,
+		})
+		if err != nil {
+			rsp.Error = AsErrorResult(err)
+			return rsp, nil
+		}
+		if !a.Allowed {
+			rsp.Error = AsErrorResult(apierrors.NewForbidden(schema.GroupResource{Group: key.Group, Resource: key.Resource}, "",
+				fmt.Errorf("requester must be able to: %s", utils.VerbDeleteCollection)))
+			return rsp, nil
+		}
+	}
+
+	before := time.UnixMilli(req.DeletedBefore)
+	if req.DeletedBefore <= 0 {
+		before = time.UnixMilli(s.now())
+	}
+	for _, key := range collections {
+		purged, err := purger.PurgeTrash(ctx, key, before)
+		if err != nil {
+			rsp.Error = AsErrorResult(err)
+			return rsp, nil
+		}
+		rsp.Purged += purged
+	}
+	return rsp, nil
+}
+
+func trashCollections(namespace string, resources []*resourcepb.ResourceKey) ([]*resourcepb.ResourceKey, error) {
+	if namespace == "" {
+		return nil, apierrors.NewBadRequest("missing namespace")
+	}
+	if len(resources) < 1 {
+		return nil, apierrors.NewBadRequest("missing resources")
+	}
+	collections := make([]*resourcepb.ResourceKey, 0, len(resources))
+	for _, r := range resources {
+		if r.Group == "" || r.Resource == "" {
+			return nil, apierrors.NewBadRequest("missing group or resource")
+		}
+		collections = append(collections, &resourcepb.ResourceKey{
+			Namespace: namespace,
+			Group:     r.Group,
+			Resource:  r.Resource,
+		})
+	}
+	return collections, nil
+}
+
+// runTrashRetention periodically purges everything deleted longer than the retention period ago.
+// Each collection in each namespace is purged in its own transaction, so no single purge locks the whole history.
+func (s *server) runTrashRetention(purger TrashPurger, retention time.Duration) {
+	interval := min(retention, time.Hour)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.purgeExpiredTrash(purger, time.UnixMilli(s.now()).Add(-retention))
+		}
+	}
+}
+
+func (s *server) purgeExpiredTrash(purger TrashPurger, before time.Time) {
+	collections, err := purger.ListTrashCollections(s.ctx, before)
+	if err != nil {
+		s.log.Warn("failed to list the collections to purge", "err", err)
+		return
+	}
+	for _, key := range collections {
+		if s.ctx.Err() != nil {
+			return
+		}
+		purged, err := purger.PurgeTrash(s.ctx, key, before)
+		if err != nil {
+			s.log.Warn("failed to purge trash", "key", NSGR(key), "err", err)
+			continue
+		}
+		if purged > 0 {
+			s.log.Info("purged trash", "key", NSGR(key), "before", before, "history", purged)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package resource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+func TestPurgeExpiredTrash(t *testing.T) {
+	s, err := NewResourceServer(ResourceServerOptions{
+		Backend: &restoreTestBackend{},
+	})
+	require.NoError(t, err)
+
+	before := time.UnixMilli(1000)
+	purger := &trashTestPurger{
+		collections: []*resourcepb.ResourceKey{
+			{Namespace: "a", Group: "dashboard.grafana.app", Resource: "dashboards"},
+			{Namespace: "a", Group: folderGroup, Resource: folderResource},
+			{Namespace: "b", Group: "dashboard.grafana.app", Resource: "dashboards"},
+		},
+	}
+	s.(*server).purgeExpiredTrash(purger, before)
+
+	// Every collection in every namespace is purged on its own
+	require.Equal(t, []string{
+		"a/dashboard.grafana.app/dashboards",
+		"a/folder.grafana.app/folders",
+		"b/dashboard.grafana.app/dashboards",
+	}, purger.purged)
+	require.Equal(t, before, purger.before)
+}
+
+type trashTestPurger struct {
+	collections []*resourcepb.ResourceKey
+	purged      []string
+	before      time.Time
+}
+
+func (p *trashTestPurger) PurgeTrash(_ context.Context, key *resourcepb.ResourceKey, deletedBefore time.Time) (int64, error) {
+	p.purged = append(p.purged, NSGR(key))
+	p.before = deletedBefore
+	return 1, nil
+}
+
+func (p *trashTestPurger) ListTrashCollections(_ context.Context, deletedBefore time.Time) ([]*resourcepb.ResourceKey, error) {
+	return p.collections, nil
+}
@@ -349,6 +349,7 @@ var ResourceStore_ServiceDesc = grpc.ServiceDesc{
 
 const (
 	BulkStore_BulkProcess_FullMethodName = "/resource.BulkStore/BulkProcess"
+	BulkStore_Restore_FullMethodName     = "/resource.BulkStore/Restore"
 )
 
 // BulkStoreClient is the client API for BulkStore service.
@@ -359,6 +360,10 @@ type BulkStoreClient interface {
 	// Events will not be sent until the stream is complete
 	// Only the *create* permissions is checked
 	BulkProcess(ctx context.Context, opts ...grpc.CallOption) (BulkStore_BulkProcessClient, error)
+	// Restore the collections to a previous point in time
+	// The changes are written on top of the existing history
+	// The delete collection permission is checked for every collection
+	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreResponse, error)
 }
 
 type bulkStoreClient struct {
@@ -404,6 +409,16 @@ func (x *bulkStoreBulkProcessClient) CloseAndRecv() (*BulkResponse, error) {
 	return m, nil
 }
 
+func (c *bulkStoreClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreResponse)
+	err := c.cc.Invoke(ctx, BulkStore_Restore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BulkStoreServer is the server API for BulkStore service.
 // All implementations should embed UnimplementedBulkStoreServer
 // for forward compatibility
@@ -412,6 +427,10 @@ type BulkStoreServer interface {
 	// Events will not be sent until the stream is complete
 	// Only the *create* permissions is checked
 	BulkProcess(BulkStore_BulkProcessServer) error
+	// Restore the collections to a previous point in time
+	// The changes are written on top of the existing history
+	// The delete collection permission is checked for every collection
+	Restore(context.Context, *RestoreRequest) (*RestoreResponse, error)
 }
 
 // UnimplementedBulkStoreServer should be embedded to have forward compatible implementations.
@@ -421,6 +440,9 @@ type UnimplementedBulkStoreServer struct {
 func (UnimplementedBulkStoreServer) BulkProcess(BulkStore_BulkProcessServer) error {
 	return status.Errorf(codes.Unimplemented, "method BulkProcess not implemented")
 }
+func (UnimplementedBulkStoreServer) Restore(context.Context, *RestoreRequest) (*RestoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
 
 // UnsafeBulkStoreServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to BulkStoreServer will
@@ -459,13 +481,36 @@ func (x *bulkStoreBulkProcessServer) Recv() (*BulkRequest, error) {
 	return m, nil
 }
 
+func _BulkStore_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulkStoreServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BulkStore_Restore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulkStoreServer).Restore(ctx, req.(*RestoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // BulkStore_ServiceDesc is the grpc.ServiceDesc for BulkStore service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var BulkStore_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "resource.BulkStore",
 	HandlerType: (*BulkStoreServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Restore",
+			Handler:    _BulkStore_Restore_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "BulkProcess",
@@ -476,6 +521,187 @@ var BulkStore_ServiceDesc = grpc.ServiceDesc{
 	Metadata: "resource.proto",
 }
 
+const (
+	Trash_ListTrash_FullMethodName        = "/resource.Trash/ListTrash"
+	Trash_RestoreFromTrash_FullMethodName = "/resource.Trash/RestoreFromTrash"
+	Trash_PurgeTrash_FullMethodName       = "/resource.Trash/PurgeTrash"
+)
+
+// TrashClient is the client API for Trash service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Manage the resources that have been deleted
+type TrashClient interface {
+	// List the deleted resources in a namespace
+	// The get permission is checked for every item
+	ListTrash(ctx context.Context, in *ListTrashRequest, opts ...grpc.CallOption) (*ListTrashResponse, error)
+	// Recreate a deleted resource, and any missing parent folders
+	// The create permission is checked for every restored resource
+	RestoreFromTrash(ctx context.Context, in *RestoreFromTrashRequest, opts ...grpc.CallOption) (*RestoreFromTrashResponse, error)
+	// Permanently remove deleted resources
+	// The delete collection permission is checked for every collection
+	PurgeTrash(ctx context.Context, in *PurgeTrashRequest, opts ...grpc.CallOption) (*PurgeTrashResponse, error)
+}
+
+type trashClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTrashClient(cc grpc.ClientConnInterface) TrashClient {
+	return &trashClient{cc}
+}
+
+func (c *trashClient) ListTrash(ctx context.Context, in *ListTrashRequest, opts ...grpc.CallOption) (*ListTrashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTrashResponse)
+	err := c.cc.Invoke(ctx, Trash_ListTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trashClient) RestoreFromTrash(ctx context.Context, in *RestoreFromTrashRequest, opts ...grpc.CallOption) (*RestoreFromTrashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreFromTrashResponse)
+	err := c.cc.Invoke(ctx, Trash_RestoreFromTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trashClient) PurgeTrash(ctx context.Context, in *PurgeTrashRequest, opts ...grpc.CallOption) (*PurgeTrashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeTrashResponse)
+	err := c.cc.Invoke(ctx, Trash_PurgeTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TrashServer is the server API for Trash service.
+// All implementations should embed UnimplementedTrashServer
+// for forward compatibility
+//
+// Manage the resources that have been deleted
+type TrashServer interface {
+	// List the deleted resources in a namespace
+	// The get permission is checked for every item
+	ListTrash(context.Context, *ListTrashRequest) (*ListTrashResponse, error)
+	// Recreate a deleted resource, and any missing parent folders
+	// The create permission is checked for every restored resource
+	RestoreFromTrash(context.Context, *RestoreFromTrashRequest) (*RestoreFromTrashResponse, error)
+	// Permanently remove deleted resources
+	// The delete collection permission is checked for every collection
+	PurgeTrash(context.Context, *PurgeTrashRequest) (*PurgeTrashResponse, error)
+}
+
+// UnimplementedTrashServer should be embedded to have forward compatible implementations.
+type UnimplementedTrashServer struct {
+}
+
+func (UnimplementedTrashServer) ListTrash(context.Context, *ListTrashRequest) (*ListTrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTrash not implemented")
+}
+func (UnimplementedTrashServer) RestoreFromTrash(context.Context, *RestoreFromTrashRequest) (*RestoreFromTrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreFromTrash not implemented")
+}
+func (UnimplementedTrashServer) PurgeTrash(context.Context, *PurgeTrashRequest) (*PurgeTrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeTrash not implemented")
+}
+
+// UnsafeTrashServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TrashServer will
+// result in compilation errors.
+type UnsafeTrashServer interface {
+	mustEmbedUnimplementedTrashServer()
+}
+
+func RegisterTrashServer(s grpc.ServiceRegistrar, srv TrashServer) {
+	s.RegisterService(&Trash_ServiceDesc, srv)
+}
+
+func _Trash_ListTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrashServer).ListTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Trash_ListTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrashServer).ListTrash(ctx, req.(*ListTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Trash_RestoreFromTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreFromTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrashServer).RestoreFromTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Trash_RestoreFromTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrashServer).RestoreFromTrash(ctx, req.(*RestoreFromTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Trash_PurgeTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrashServer).PurgeTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Trash_PurgeTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrashServer).PurgeTrash(ctx, req.(*PurgeTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Trash_ServiceDesc is the grpc.ServiceDesc for Trash service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Trash_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "resource.Trash",
+	HandlerType: (*TrashServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListTrash",
+			Handler:    _Trash_ListTrash_Handler,
+		},
+		{
+			MethodName: "RestoreFromTrash",
+			Handler:    _Trash_RestoreFromTrash_Handler,
+		},
+		{
+			MethodName: "PurgeTrash",
+			Handler:    _Trash_PurgeTrash_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "resource.proto",
+}
+
 const (
 	ManagedObjectIndex_CountManagedObjects_FullMethodName = "/resource.ManagedObjectIndex/CountManagedObjects"
 	ManagedObjectIndex_ListManagedObjects_FullMethodName  = "/resource.ManagedObjectIndex/ListManagedObjects"
@@ -614,7 +840,8 @@ var ManagedObjectIndex_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	Diagnostics_IsHealthy_FullMethodName = "/resource.Diagnostics/IsHealthy"
+	Diagnostics_IsHealthy_FullMethodName            = "/resource.Diagnostics/IsHealthy"
+	Diagnostics_ListOnlineMigrations_FullMethodName = "/resource.Diagnostics/ListOnlineMigrations"
 )
 
 // DiagnosticsClient is the client API for Diagnostics service.
@@ -626,6 +853,8 @@ const (
 type DiagnosticsClient interface {
 	// Check if the service is healthy
 	IsHealthy(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error)
+	// The progress of the online migrations of the storage backend
+	ListOnlineMigrations(ctx context.Context, in *ListOnlineMigrationsRequest, opts ...grpc.CallOption) (*ListOnlineMigrationsResponse, error)
 }
 
 type diagnosticsClient struct {
@@ -646,6 +875,16 @@ func (c *diagnosticsClient) IsHealthy(ctx context.Context, in *HealthCheckReques
 	return out, nil
 }
 
+func (c *diagnosticsClient) ListOnlineMigrations(ctx context.Context, in *ListOnlineMigrationsRequest, opts ...grpc.CallOption) (*ListOnlineMigrationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOnlineMigrationsResponse)
+	err := c.cc.Invoke(ctx, Diagnostics_ListOnlineMigrations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DiagnosticsServer is the server API for Diagnostics service.
 // All implementations should embed UnimplementedDiagnosticsServer
 // for forward compatibility
@@ -655,6 +894,8 @@ func (c *diagnosticsClient) IsHealthy(ctx context.Context, in *HealthCheckReques
 type DiagnosticsServer interface {
 	// Check if the service is healthy
 	IsHealthy(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error)
+	// The progress of the online migrations of the storage backend
+	ListOnlineMigrations(context.Context, *ListOnlineMigrationsRequest) (*ListOnlineMigrationsResponse, error)
 }
 
 // UnimplementedDiagnosticsServer should be embedded to have forward compatible implementations.
@@ -664,6 +905,9 @@ type UnimplementedDiagnosticsServer struct {
 func (UnimplementedDiagnosticsServer) IsHealthy(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method IsHealthy not implemented")
 }
+func (UnimplementedDiagnosticsServer) ListOnlineMigrations(context.Context, *ListOnlineMigrationsRequest) (*ListOnlineMigrationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOnlineMigrations not implemented")
+}
 
 // UnsafeDiagnosticsServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to DiagnosticsServer will
@@ -694,6 +938,24 @@ func _Diagnostics_IsHealthy_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Diagnostics_ListOnlineMigrations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOnlineMigrationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiagnosticsServer).ListOnlineMigrations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Diagnostics_ListOnlineMigrations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiagnosticsServer).ListOnlineMigrations(ctx, req.(*ListOnlineMigrationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Diagnostics_ServiceDesc is the grpc.ServiceDesc for Diagnostics service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -705,6 +967,10 @@ var Diagnostics_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "IsHealthy",
 			Handler:    _Diagnostics_IsHealthy_Handler,
 		},
+		{
+			MethodName: "ListOnlineMigrations",
+			Handler:    _Diagnostics_ListOnlineMigrations_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "resource.proto",
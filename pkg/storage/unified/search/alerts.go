@@ -0,0 +1,83 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+)
+
+const searchAlertTemplate = "search_alert"
+
+// NewSearchAlertNotifier sends the saved search alerts with the notifications service
+func NewSearchAlertNotifier(sender notifications.Service) resource.SearchAlertNotifier {
+	return &searchAlertNotifier{sender: sender}
+}
+
+type searchAlertNotifier struct {
+	sender notifications.Service
+}
+
+// The body of the webhook request
+type searchAlertPayload struct {
+	Namespace string   `json:"namespace"`
+	Name      string   `json:"name"`
+	Title     string   `json:"title"`
+	Added     []string `json:"added,omitempty"`
+	Removed   []string `json:"removed,omitempty"`
+	TotalHits int64    `json:"totalHits"`
+}
+
+func (n *searchAlertNotifier) NotifySearchAlert(ctx context.Context, alert *resource.SearchAlert) error {
+	payload := searchAlertPayload{
+		Namespace: alert.Key.Namespace,
+		Name:      alert.Key.Name,
+		Title:     alert.Title,
+		Added:     alert.Added,
+		Removed:   alert.Removed,
+		TotalHits: alert.TotalHits,
+	}
+
+	var errs []error
+	if len(alert.Webhooks) > 0 {
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		for _, url := range alert.Webhooks {
+			err = n.sender.SendWebhookSync(ctx, &notifications.SendWebhookSync{
+				Url:         url,
+				Body:        string(body),
+				HttpMethod:  "POST",
+				ContentType: "application/json",
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("webhook %s: %w", url, err))
+			}
+		}
+	}
+
+	if len(alert.Emails) > 0 {
+		err := n.sender.SendEmailCommandHandlerSync(ctx, &notifications.SendEmailCommandSync{
+			SendEmailCommand: notifications.SendEmailCommand{
+				To:       alert.Emails,
+				Template: searchAlertTemplate,
+				Data: map[string]any{
+					"Title":     payload.Title,
+					"Namespace": payload.Namespace,
+					"Name":      payload.Name,
+					"Added":     payload.Added,
+					"Removed":   payload.Removed,
+					"TotalHits": payload.TotalHits,
+				},
+			},
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("email: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
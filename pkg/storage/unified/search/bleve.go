@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/blevesearch/bleve/v2"
@@ -23,6 +24,7 @@ import (
 	bleveSearch "github.com/blevesearch/bleve/v2/search/searcher"
 	index "github.com/blevesearch/bleve_index_api"
 	"go.opentelemetry.io/otel/trace"
+	"gocloud.dev/blob"
 	"k8s.io/apimachinery/pkg/selection"
 
 	"github.com/grafana/grafana/pkg/services/dashboards/dashboardaccess"
@@ -39,6 +41,7 @@ import (
 const tracingPrexfixBleve = "unified_search.bleve."
 
 var _ resource.SearchBackend = &bleveBackend{}
+var _ resource.SearchBackendStopper = &bleveBackend{}
 var _ resource.ResourceIndex = &bleveIndex{}
 
 type BleveOptions struct {
@@ -51,6 +54,25 @@ type BleveOptions struct {
 	// How big should a batch get before flushing
 	// ?? not totally sure the units
 	BatchSize int
+
+	// The bucket where the file based indexes are shared with the other replicas, disabled when nil
+	Snapshots *blob.Bucket
+
+	// How often the indexes that changed are uploaded again
+	SnapshotInterval time.Duration
+
+	// The document count where the indexes are uploaded as snapshots
+	SnapshotMinCount int64
+
+	// The most edits allowed for a query term to still match a title, 0 disables the typo tolerance
+	FuzzyMaxEdits int
+
+	// The synonym groups by namespace, eg: [["k8s", "kubernetes"]]
+	// The groups in the SynonymsAllNamespaces key apply to every namespace
+	Synonyms map[string][][]string
+
+	// How much the views and queries counters weigh in the ranking of text queries, 0 disables the usage ranking
+	UsageBoost float64
 }
 
 type bleveBackend struct {
@@ -65,6 +87,12 @@ type bleveBackend struct {
 
 	features     featuremgmt.FeatureToggles
 	indexMetrics *resource.BleveIndexMetrics
+
+	// The background snapshot uploads run until the backend is stopped
+	bgCtx    context.Context
+	bgCancel context.CancelFunc
+	bgMu     sync.Mutex
+	bgWg     sync.WaitGroup
 }
 
 func NewBleveBackend(opts BleveOptions, tracer trace.Tracer, features featuremgmt.FeatureToggles, indexMetrics *resource.BleveIndexMetrics) (*bleveBackend, error) {
@@ -88,12 +116,39 @@ func NewBleveBackend(opts BleveOptions, tracer trace.Tracer, features featuremgm
 		features:     features,
 		indexMetrics: indexMetrics,
 	}
+	bleveBackend.bgCtx, bleveBackend.bgCancel = context.WithCancel(context.Background())
 
 	go bleveBackend.updateIndexSizeMetric(opts.Root)
 
+	if opts.Snapshots != nil && opts.SnapshotInterval > 0 {
+		bleveBackend.goBackground(bleveBackend.runSnapshots)
+	}
+
 	return bleveBackend, nil
 }
 
+// Stop implements resource.SearchBackendStopper, it cancels the snapshot uploads and waits for them to return
+func (b *bleveBackend) Stop() {
+	b.bgMu.Lock()
+	b.bgCancel()
+	b.bgMu.Unlock()
+	b.bgWg.Wait()
+}
+
+// goBackground runs fn until the backend is stopped, nothing is started once it is stopped
+func (b *bleveBackend) goBackground(fn func(ctx context.Context)) {
+	b.bgMu.Lock()
+	defer b.bgMu.Unlock()
+	if b.bgCtx.Err() != nil {
+		return
+	}
+	b.bgWg.Add(1)
+	go func() {
+		defer b.bgWg.Done()
+		fn(b.bgCtx)
+	}()
+}
+
 // This will return nil if the key does not exist
 func (b *bleveBackend) GetIndex(ctx context.Context, key resource.NamespacedResource) (resource.ResourceIndex, error) {
 	b.cacheMu.RLock()
@@ -161,14 +216,20 @@ func (b *bleveBackend) BuildIndex(ctx context.Context,
 
 	var err error
 	var index bleve.Index
+	var restored *snapshotInfo
 
 	build := true
+	fileBased := size > b.opts.FileThreshold
 	mapper, err := GetBleveMappings(fields)
 	if err != nil {
 		return nil, err
 	}
+	hash, err := mappingHash(mapper)
+	if err != nil {
+		return nil, err
+	}
 
-	if size > b.opts.FileThreshold {
+	if fileBased {
 		resourceDir := filepath.Join(b.opts.Root, key.Namespace,
 			fmt.Sprintf("%s.%s", key.Resource, key.Group),
 		)
@@ -201,6 +262,25 @@ func (b *bleveBackend) BuildIndex(ctx context.Context,
 			}
 		}
 
+		// Start from the snapshot of another replica, the builder only indexes the changes since
+		if index == nil && b.opts.Snapshots != nil {
+			if info, _ := os.Stat(dir); info == nil {
+				restored, err = b.restoreSnapshot(ctx, key, dir, hash)
+				if err != nil {
+					b.log.Warn("failed to restore search index snapshot", "namespace", key.Namespace, "group", key.Group, "resource", key.Resource, "error", err)
+					restored = nil
+				}
+				if restored != nil {
+					index, err = bleve.Open(dir)
+					if err != nil {
+						b.log.Warn("failed to open search index snapshot", "directory", dir, "error", err)
+						_ = os.RemoveAll(dir)
+						index, restored = nil, nil
+					}
+				}
+			}
+		}
+
 		if index == nil {
 			index, err = bleve.New(dir, mapper)
 			if err != nil {
@@ -227,12 +307,22 @@ func (b *bleveBackend) BuildIndex(ctx context.Context,
 
 	// Batch all the changes
 	idx := &bleveIndex{
-		key:      key,
-		index:    index,
-		fields:   fields,
-		standard: resource.StandardSearchFields(),
-		features: b.features,
-		tracing:  b.tracer,
+		key:         key,
+		index:       index,
+		fields:      fields,
+		standard:    resource.StandardSearchFields(),
+		features:    b.features,
+		tracing:     b.tracer,
+		fileBased:   fileBased,
+		mappingHash: hash,
+		fuzzyEdits:  b.opts.FuzzyMaxEdits,
+		synonyms:    newSynonyms(b.opts.Synonyms[SynonymsAllNamespaces], b.opts.Synonyms[key.Namespace]),
+		usageBoost:  b.opts.UsageBoost,
+	}
+	if restored != nil {
+		idx.restoredRV = restored.RV
+		idx.maxRV.Store(restored.RV)
+		b.log.Info("restored search index snapshot", "namespace", key.Namespace, "group", key.Group, "resource", key.Resource, "rv", restored.RV, "docs", restored.Docs)
 	}
 
 	idx.allFields, err = getAllFields(idx.standard, fields)
@@ -241,10 +331,19 @@ func (b *bleveBackend) BuildIndex(ctx context.Context,
 	}
 
 	if build {
-		_, err = builder(idx)
+		listRV, err := builder(idx)
 		if err != nil {
 			return nil, err
 		}
+		idx.updateRV(listRV)
+
+		if b.shouldSnapshot(idx) {
+			b.goBackground(func(ctx context.Context) {
+				if err := b.uploadSnapshot(ctx, idx); err != nil {
+					b.log.Warn("failed to upload search index snapshot", "namespace", key.Namespace, "group", key.Group, "resource", key.Resource, "error", err)
+				}
+			})
+		}
 	}
 
 	b.cacheMu.Lock()
@@ -314,6 +413,29 @@ type bleveIndex struct {
 	allFields []*resourcepb.ResourceTableColumnDefinition
 	features  featuremgmt.FeatureToggles
 	tracing   trace.Tracer
+
+	// Ranking of the text queries
+	fuzzyEdits int
+	synonyms   synonyms
+	usageBoost float64
+
+	// Snapshot state for the file based indexes
+	fileBased   bool
+	mappingHash string
+	restoredRV  int64
+	maxRV       atomic.Int64
+	changes     atomic.Int64
+	snapshotMu  sync.Mutex
+}
+
+// updateRV keeps the highest resource version written to the index
+func (b *bleveIndex) updateRV(rv int64) {
+	for {
+		current := b.maxRV.Load()
+		if rv <= current || b.maxRV.CompareAndSwap(current, rv) {
+			return
+		}
+	}
 }
 
 // BulkIndex implements resource.ResourceIndex.
@@ -329,8 +451,9 @@ func (b *bleveIndex) BulkIndex(req *resource.BulkIndexRequest) error {
 			if item.Doc == nil {
 				return fmt.Errorf("missing document")
 			}
+			b.updateRV(item.Doc.RV)
 			doc := item.Doc.UpdateCopyFields()
-			doc.References = nil // remove references (for now!)
+			doc.References = nil // indexed as ReferenceIDs
 
 			err := batch.Index(resource.SearchID(doc.Key), doc)
 			if err != nil {
@@ -341,7 +464,12 @@ func (b *bleveIndex) BulkIndex(req *resource.BulkIndexRequest) error {
 		}
 	}
 
-	return b.index.Batch(batch)
+	if err := b.index.Batch(batch); err != nil {
+		return err
+	}
+	b.updateRV(req.ResourceVersion)
+	b.changes.Add(int64(len(req.Items)))
+	return nil
 }
 
 func (b *bleveIndex) ListManagedObjects(ctx context.Context, req *resourcepb.ListManagedObjectsRequest) (*resourcepb.ListManagedObjectsResponse, error) {
@@ -529,16 +657,29 @@ func (b *bleveIndex) Search(
 		}
 	}
 
+	// The text matches are ranked again with the usage counters
+	selectFields := searchrequest.Fields
+	from, size := searchrequest.From, searchrequest.Size
+	rerank := b.usageBoost > 0 && size > 0 && from < usageRankWindow && isScoreSorted(searchrequest)
+	if rerank {
+		searchrequest.From = 0
+		searchrequest.Size = max(from+size, usageRankWindow)
+		searchrequest.Fields = append(slices.Clone(selectFields), viewsField, queriesField)
+	}
+
 	res, err := index.SearchInContext(ctx, searchrequest)
 	if err != nil {
 		return nil, err
 	}
+	if rerank {
+		res.Hits, res.MaxScore = rankByUsage(res.Hits, b.usageBoost, from, size)
+	}
 
 	response.TotalHits = int64(res.Total)
 	response.QueryCost = float64(res.Cost)
 	response.MaxScore = res.MaxScore
 
-	response.Results, err = b.hitsToTable(ctx, searchrequest.Fields, res.Hits, req.Explain)
+	response.Results, err = b.hitsToTable(ctx, selectFields, res.Hits, req.Explain)
 	if err != nil {
 		return nil, err
 	}
@@ -706,8 +847,16 @@ func (b *bleveIndex) toBleveSearchRequest(ctx context.Context, req *resourcepb.R
 		queryAnalyzed := bleve.NewMatchQuery(req.Query)
 		queryAnalyzed.Analyzer = standard.Name
 
+		// Query 4: The same query with the synonyms
+		// Query 5: Fuzzy match of the title terms, to tolerate typos
+		disjuncts := []query.Query{queryExact, queryAnalyzed, queryPhrase}
+		disjuncts = append(disjuncts, b.synonyms.synonymQueries(req.Query)...)
+		if fuzzy := fuzzyTitleQuery(req.Query, b.fuzzyEdits); fuzzy != nil {
+			disjuncts = append(disjuncts, fuzzy)
+		}
+
 		// At least one of the queries must match
-		searchQuery := bleve.NewDisjunctionQuery(queryExact, queryAnalyzed, queryPhrase)
+		searchQuery := bleve.NewDisjunctionQuery(disjuncts...)
 		queries = append(queries, searchQuery)
 	}
 
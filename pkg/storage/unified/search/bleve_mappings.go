@@ -9,6 +9,9 @@ import (
 	"github.com/grafana/grafana/pkg/storage/unified/resource"
 )
 
+// The stored resource version of the documents
+const indexRVField = "index_rv"
+
 func GetBleveMappings(fields resource.SearchableDocumentFields) (mapping.IndexMapping, error) {
 	mapper := bleve.NewIndexMapping()
 
@@ -83,6 +86,38 @@ func getBleveDocMappings(_ resource.SearchableDocumentFields) *mapping.DocumentM
 	}
 	mapper.AddFieldMappingsAt(resource.SEARCH_FIELD_FOLDER, folderMapping)
 
+	referencesMapping := &mapping.FieldMapping{
+		Name:               resource.SEARCH_FIELD_REFERENCES,
+		Type:               "text",
+		Analyzer:           keyword.Name,
+		Store:              true,
+		Index:              true,
+		IncludeTermVectors: false,
+		IncludeInAll:       false,
+		DocValues:          false,
+	}
+	mapper.AddFieldMappingsAt(resource.SEARCH_FIELD_REFERENCES, referencesMapping)
+
+	// The resource version stays indexed for sorting and filtering, and is also stored in a separate
+	// field so an index restored from a snapshot knows what changed
+	rvMapping := &mapping.FieldMapping{
+		Name:         resource.SEARCH_FIELD_RV,
+		Type:         "number",
+		Store:        true,
+		Index:        true,
+		IncludeInAll: false,
+		DocValues:    true,
+	}
+	snapshotRVMapping := &mapping.FieldMapping{
+		Name:         indexRVField,
+		Type:         "number",
+		Store:        true,
+		Index:        false,
+		IncludeInAll: false,
+		DocValues:    false,
+	}
+	mapper.AddFieldMappingsAt(resource.SEARCH_FIELD_RV, rvMapping, snapshotRVMapping)
+
 	// Repositories
 	manager := bleve.NewDocumentStaticMapping()
 	manager.AddFieldMappingsAt("kind", &mapping.FieldMapping{
@@ -143,5 +178,35 @@ func getBleveDocMappings(_ resource.SearchableDocumentFields) *mapping.DocumentM
 	fieldMapper := bleve.NewDocumentMapping()
 	mapper.AddSubDocumentMapping("fields", fieldMapper)
 
+	// Panel content is included in the full text search
+	for _, field := range []string{
+		DASHBOARD_PANEL_TITLES,
+		DASHBOARD_PANEL_DESCRIPTIONS,
+		DASHBOARD_PANEL_QUERIES,
+		DASHBOARD_PANEL_TEXT,
+	} {
+		fieldMapper.AddFieldMappingsAt(field, &mapping.FieldMapping{
+			Name:               field,
+			Type:               "text",
+			Analyzer:           standard.Name,
+			Store:              true,
+			Index:              true,
+			IncludeTermVectors: false,
+			IncludeInAll:       true,
+		})
+	}
+
+	// Metric names are matched exactly, eg: fields.metric_names=http_requests_total
+	fieldMapper.AddFieldMappingsAt(DASHBOARD_METRIC_NAMES, &mapping.FieldMapping{
+		Name:               DASHBOARD_METRIC_NAMES,
+		Type:               "text",
+		Analyzer:           keyword.Name,
+		Store:              true,
+		Index:              true,
+		IncludeTermVectors: false,
+		IncludeInAll:       true,
+		DocValues:          true,
+	})
+
 	return mapper
 }
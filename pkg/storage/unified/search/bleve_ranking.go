@@ -0,0 +1,193 @@
+package search
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/standard"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/query"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+)
+
+// The synonyms in this namespace apply to all the namespaces
+const SynonymsAllNamespaces = "*"
+
+// The most query variants added for the synonyms
+const maxSynonymExpansions = 10
+
+// The ranking with the usage counters only reorders this many top text matches.
+// The window does not depend on the page, so paging neither repeats nor skips results,
+// the results after the window keep the text order.
+const usageRankWindow = 100
+
+var (
+	viewsField   = resource.SEARCH_FIELD_PREFIX + DASHBOARD_VIEWS_LAST_30_DAYS
+	queriesField = resource.SEARCH_FIELD_PREFIX + DASHBOARD_QUERIES_LAST_30_DAYS
+)
+
+// synonyms maps a normalized term (or phrase) to the other terms of its groups
+type synonyms map[string][]string
+
+// newSynonyms indexes the synonym groups, eg: [["k8s", "kubernetes"]]
+func newSynonyms(groups ...[][]string) synonyms {
+	s := make(synonyms)
+	for _, group := range slices.Concat(groups...) {
+		terms := make([]string, 0, len(group))
+		for _, term := range group {
+			if term = normalizeTerm(term); term != "" && !slices.Contains(terms, term) {
+				terms = append(terms, term)
+			}
+		}
+		for _, term := range terms {
+			for _, other := range terms {
+				if other != term && !slices.Contains(s[term], other) {
+					s[term] = append(s[term], other)
+				}
+			}
+		}
+	}
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
+
+func normalizeTerm(v string) string {
+	return strings.Join(strings.Fields(strings.ToLower(v)), " ")
+}
+
+// expand returns the query with each known term replaced by its synonyms
+func (s synonyms) expand(q string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	// pad with spaces to only match whole words
+	padded := " " + normalizeTerm(q) + " "
+
+	// the terms are checked in a stable order
+	terms := make([]string, 0, len(s))
+	for term := range s {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+
+	var expanded []string
+	for _, term := range terms {
+		if !strings.Contains(padded, " "+term+" ") {
+			continue
+		}
+		for _, other := range s[term] {
+			v := strings.TrimSpace(strings.ReplaceAll(padded, " "+term+" ", " "+other+" "))
+			if !slices.Contains(expanded, v) {
+				expanded = append(expanded, v)
+			}
+			if len(expanded) >= maxSynonymExpansions {
+				return expanded
+			}
+		}
+	}
+	return expanded
+}
+
+// synonymQueries matches the query variants with the synonyms
+func (s synonyms) synonymQueries(q string) []query.Query {
+	var queries []query.Query
+	for _, v := range s.expand(q) {
+		m := bleve.NewMatchQuery(v)
+		m.Analyzer = standard.Name
+		m.SetBoost(0.8)
+		queries = append(queries, m)
+	}
+	return queries
+}
+
+// fuzziness returns the edits allowed for the term, the short terms must match exactly
+func fuzziness(term string, maxEdits int) int {
+	edits := 0
+	switch n := len([]rune(term)); {
+	case n >= 6:
+		edits = 2
+	case n >= 3:
+		edits = 1
+	}
+	return min(edits, maxEdits)
+}
+
+// fuzzyTitleQuery matches titles where every term is within the edit budget.
+// It returns nil when no term allows any edit.
+func fuzzyTitleQuery(q string, maxEdits int) query.Query {
+	terms := strings.Fields(strings.ToLower(q))
+	if len(terms) == 0 || maxEdits < 1 {
+		return nil
+	}
+
+	fuzzy := false
+	queries := make([]query.Query, 0, len(terms))
+	for _, term := range terms {
+		edits := fuzziness(term, maxEdits)
+		if edits == 0 {
+			m := bleve.NewMatchQuery(term)
+			m.Analyzer = standard.Name
+			m.SetField(resource.SEARCH_FIELD_TITLE)
+			queries = append(queries, m)
+			continue
+		}
+		fuzzy = true
+		f := bleve.NewFuzzyQuery(term)
+		f.SetFuzziness(edits)
+		f.SetField(resource.SEARCH_FIELD_TITLE)
+		queries = append(queries, f)
+	}
+	if !fuzzy {
+		return nil
+	}
+
+	// typos score below the exact matches
+	c := bleve.NewConjunctionQuery(queries...)
+	c.SetBoost(0.5)
+	return c
+}
+
+// usageScore combines the text score with the views and queries in the last 30 days.
+// With a boost of 1, every tenfold increase in views adds 10% to the score,
+// so the usage decides between similar matches without hiding the better text matches.
+func usageScore(hit *search.DocumentMatch, boost float64) float64 {
+	views, _ := hit.Fields[viewsField].(float64)
+	queries, _ := hit.Fields[queriesField].(float64)
+	usage := math.Log10(1+max(views, 0)) + 0.5*math.Log10(1+max(queries, 0))
+	return hit.Score * (1 + 0.1*boost*usage)
+}
+
+// rankByUsage sorts the hits in the ranking window by the combined score, and returns the requested page
+func rankByUsage(hits search.DocumentMatchCollection, boost float64, from int, size int) (search.DocumentMatchCollection, float64) {
+	window := hits[:min(len(hits), usageRankWindow)]
+	for _, hit := range window {
+		hit.Score = usageScore(hit, boost)
+	}
+	sort.SliceStable(window, func(i, j int) bool {
+		return window[i].Score > window[j].Score
+	})
+
+	maxScore := 0.0
+	if len(hits) > 0 {
+		maxScore = hits[0].Score
+	}
+	if from >= len(hits) {
+		return search.DocumentMatchCollection{}, maxScore
+	}
+	return hits[from:min(from+size, len(hits))], maxScore
+}
+
+// isScoreSorted checks if the request is only sorted by the text score
+func isScoreSorted(req *bleve.SearchRequest) bool {
+	if len(req.Sort) != 1 {
+		return false
+	}
+	s, ok := req.Sort[0].(*search.SortScore)
+	return ok && s.Desc
+}
@@ -0,0 +1,31 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSynonymsExpand(t *testing.T) {
+	s := newSynonyms(
+		[][]string{{"k8s", "Kubernetes", "kube"}},
+		[][]string{{"prod", "production environment"}, {"k8s", "kubernetes"}},
+	)
+
+	require.Equal(t, []string{"kubernetes cluster", "kube cluster"}, s.expand("K8s  cluster"))
+	require.Equal(t, []string{"k8s prod", "kube prod", "kubernetes production environment"}, s.expand("kubernetes prod"))
+	require.Equal(t, []string{"prod"}, s.expand("production environment"))
+
+	// only whole words are replaced
+	require.Empty(t, s.expand("k8scluster"))
+	require.Empty(t, newSynonyms().expand("k8s"))
+}
+
+func TestFuzziness(t *testing.T) {
+	require.Equal(t, 0, fuzziness("cp", 2))
+	require.Equal(t, 1, fuzziness("cpu", 2))
+	require.Equal(t, 2, fuzziness("latency", 2))
+	require.Equal(t, 1, fuzziness("latency", 1))
+	require.Nil(t, fuzzyTitleQuery("cp io", 2))
+	require.NotNil(t, fuzzyTitleQuery("cp latency", 2))
+}
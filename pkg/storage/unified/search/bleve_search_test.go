@@ -492,6 +492,222 @@ func TestCanSearchByTitle(t *testing.T) {
 	})
 }
 
+func TestTextQueryRanking(t *testing.T) {
+	ctx := context.Background()
+	key := &resourcepb.ResourceKey{
+		Namespace: "default",
+		Group:     "dashboard.grafana.app",
+		Resource:  "dashboards",
+	}
+	doc := func(name string, title string, views int64) *resource.BulkIndexItem {
+		return &resource.BulkIndexItem{
+			Action: resource.ActionIndex,
+			Doc: &resource.IndexableDocument{
+				RV:    1,
+				Name:  name,
+				Title: title,
+				Key: &resourcepb.ResourceKey{
+					Name:      name,
+					Namespace: key.Namespace,
+					Group:     key.Group,
+					Resource:  key.Resource,
+				},
+				Fields: map[string]any{
+					search.DASHBOARD_VIEWS_LAST_30_DAYS: views,
+				},
+			},
+		}
+	}
+	names := func(res *resourcepb.ResourceSearchResponse) []string {
+		var names []string
+		for _, row := range res.Results.Rows {
+			names = append(names, row.Key.Name)
+		}
+		return names
+	}
+
+	t.Run("tolerates typos within the edit budget", func(t *testing.T) {
+		index := newTestRankingIndex(t, search.BleveOptions{FuzzyMaxEdits: 2}, doc("k8s", "Kubernetes cluster", 0), doc("node", "Node exporter", 0))
+
+		res, err := index.Search(ctx, nil, newTestQuery("kubernets clster"), nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"k8s"}, names(res))
+
+		// short terms must match exactly
+		res, err = index.Search(ctx, nil, newTestQuery("nod"), nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"node"}, names(res))
+		res, err = index.Search(ctx, nil, newTestQuery("noda"), nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"node"}, names(res))
+
+		// disabled
+		index = newTestRankingIndex(t, search.BleveOptions{}, doc("k8s", "Kubernetes cluster", 0))
+		res, err = index.Search(ctx, nil, newTestQuery("kubernets clster"), nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), res.TotalHits)
+	})
+
+	t.Run("matches the namespace synonyms", func(t *testing.T) {
+		opts := search.BleveOptions{Synonyms: map[string][][]string{
+			search.SynonymsAllNamespaces: {{"k8s", "kubernetes"}},
+			"default":                    {{"prod", "production"}},
+			"other":                      {{"node", "host"}},
+		}}
+		index := newTestRankingIndex(t, opts, doc("cluster", "Kubernetes cluster", 0), doc("overview", "Production overview", 0), doc("exporter", "Node exporter", 0))
+
+		res, err := index.Search(ctx, nil, newTestQuery("k8s"), nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"cluster"}, names(res))
+
+		res, err = index.Search(ctx, nil, newTestQuery("prod"), nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"overview"}, names(res))
+
+		res, err = index.Search(ctx, nil, newTestQuery("host"), nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), res.TotalHits)
+	})
+
+	t.Run("ranks the most viewed dashboards first", func(t *testing.T) {
+		items := []*resource.BulkIndexItem{
+			doc("copy1", "Service overview", 2),
+			doc("canonical", "Service overview", 5000),
+			doc("copy2", "Service overview", 0),
+		}
+
+		index := newTestRankingIndex(t, search.BleveOptions{UsageBoost: 1}, items...)
+		res, err := index.Search(ctx, nil, newTestQuery("service overview"), nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"canonical", "copy1", "copy2"}, names(res))
+
+		// paging uses the same ranking
+		query := newTestQuery("service overview")
+		query.Limit = 1
+		query.Offset = 1
+		res, err = index.Search(ctx, nil, query, nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), res.TotalHits)
+		require.Equal(t, []string{"copy1"}, names(res))
+
+		// the usage is not part of the requested fields
+		for _, col := range res.Results.Columns {
+			require.NotEqual(t, search.DASHBOARD_VIEWS_LAST_30_DAYS, col.Name)
+		}
+
+		// an exact title match stays above a popular partial match
+		index = newTestRankingIndex(t, search.BleveOptions{UsageBoost: 1}, doc("exact", "Latency", 0), doc("popular", "Latency by service and region", 100))
+		res, err = index.Search(ctx, nil, newTestQuery("latency"), nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"exact", "popular"}, names(res))
+	})
+
+	t.Run("pages past the ranking window without repeating results", func(t *testing.T) {
+		items := make([]*resource.BulkIndexItem, 0, 150)
+		for i := range 150 {
+			items = append(items, doc(fmt.Sprintf("dash%03d", i), "Service overview", int64(i%7)*100))
+		}
+		index := newTestRankingIndex(t, search.BleveOptions{UsageBoost: 1}, items...)
+
+		seen := make(map[string]bool)
+		for offset := int64(0); offset < 150; offset += 40 {
+			query := newTestQuery("service overview")
+			query.Limit = 40
+			query.Offset = offset
+			res, err := index.Search(ctx, nil, query, nil)
+			require.NoError(t, err)
+			for _, name := range names(res) {
+				require.False(t, seen[name], "repeated %s", name)
+				seen[name] = true
+			}
+		}
+		require.Len(t, seen, 150)
+	})
+}
+
+func newTestRankingIndex(t *testing.T, opts search.BleveOptions, items ...*resource.BulkIndexItem) resource.ResourceIndex {
+	opts.Root = t.TempDir()
+	opts.FileThreshold = threshold
+	backend, err := search.NewBleveBackend(opts, tracing.NewNoopTracerService(), featuremgmt.WithFeatures(), nil)
+	require.NoError(t, err)
+
+	index, err := backend.BuildIndex(context.Background(), resource.NamespacedResource{
+		Namespace: "default",
+		Group:     "dashboard.grafana.app",
+		Resource:  "dashboards",
+	}, int64(len(items)), 10, nil, func(index resource.ResourceIndex) (int64, error) {
+		return 10, index.BulkIndex(&resource.BulkIndexRequest{Items: items})
+	})
+	require.NoError(t, err)
+	return index
+}
+
+func TestCanSearchByPanelContent(t *testing.T) {
+	key := &resourcepb.ResourceKey{
+		Namespace: "default",
+		Group:     "dashboard.grafana.app",
+		Resource:  "dashboards",
+	}
+	doc := func(name string, queries []string, metrics []string) *resource.BulkIndexItem {
+		return &resource.BulkIndexItem{
+			Action: resource.ActionIndex,
+			Doc: &resource.IndexableDocument{
+				RV:    1,
+				Name:  name,
+				Title: name,
+				Key: &resourcepb.ResourceKey{
+					Name:      name,
+					Namespace: key.Namespace,
+					Group:     key.Group,
+					Resource:  key.Resource,
+				},
+				Fields: map[string]any{
+					search.DASHBOARD_PANEL_QUERIES: queries,
+					search.DASHBOARD_METRIC_NAMES:  metrics,
+				},
+			},
+		}
+	}
+
+	index, _ := newTestDashboardsIndex(t, threshold, 2, 2, noop)
+	err := index.BulkIndex(&resource.BulkIndexRequest{
+		Items: []*resource.BulkIndexItem{
+			doc("http", []string{"sum(rate(http_requests_total[5m])) by (code)"}, []string{"http_requests_total"}),
+			doc("http-duration", []string{"rate(http_requests_total_seconds[5m])"}, []string{"http_requests_total_seconds"}),
+			doc("cpu", []string{"node_cpu_seconds_total"}, []string{"node_cpu_seconds_total"}),
+		},
+	})
+	require.NoError(t, err)
+
+	names := func(res *resourcepb.ResourceSearchResponse) []string {
+		var names []string
+		for _, row := range res.Results.Rows {
+			names = append(names, row.Key.Name)
+		}
+		return names
+	}
+
+	t.Run("metric names match exactly", func(t *testing.T) {
+		query := newTestQuery("")
+		query.Options.Fields = []*resourcepb.Requirement{{
+			Key:      resource.SEARCH_FIELD_PREFIX + search.DASHBOARD_METRIC_NAMES,
+			Operator: "=",
+			Values:   []string{"http_requests_total"},
+		}}
+		res, err := index.Search(context.Background(), nil, query, nil)
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+		require.Equal(t, []string{"http"}, names(res))
+	})
+
+	t.Run("panel queries are part of the full text search", func(t *testing.T) {
+		res, err := index.Search(context.Background(), nil, newTestQuery("node_cpu_seconds_total"), nil)
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+		require.Equal(t, []string{"cpu"}, names(res))
+	})
+}
+
 func newTestQuery(query string) *resourcepb.ResourceSearchRequest {
 	return &resourcepb.ResourceSearchRequest{
 		Options: &resourcepb.ListOptions{
@@ -0,0 +1,343 @@
+package search
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+// The file based indexes are shared between the replicas as snapshots in a blob store.
+// A replica without a local index downloads the snapshot, and only indexes the changes since.
+
+const (
+	snapshotPrefix   = "search-snapshots"
+	snapshotInfoFile = "snapshot.json"
+	snapshotIndexDir = "index/"
+)
+
+// The first entry in the snapshot archive
+type snapshotInfo struct {
+	// The highest resource version in the index
+	RV int64 `json:"rv"`
+
+	// The number of documents
+	Docs int64 `json:"docs"`
+
+	// The hash of the index mappings, the snapshot is ignored when the mappings changed
+	Mapping string `json:"mapping"`
+
+	Created time.Time `json:"created"`
+}
+
+func snapshotKey(key resource.NamespacedResource) string {
+	return path.Join(snapshotPrefix, key.Namespace, fmt.Sprintf("%s.%s", key.Resource, key.Group), "index.tar.gz")
+}
+
+func mappingHash(m mapping.IndexMapping) (string, error) {
+	body, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// uploadSnapshot writes a copy of the index to the snapshot bucket.
+// Concurrent snapshots of the same index are skipped.
+func (b *bleveBackend) uploadSnapshot(ctx context.Context, idx *bleveIndex) error {
+	if !idx.snapshotMu.TryLock() {
+		return nil
+	}
+	defer idx.snapshotMu.Unlock()
+
+	ctx, span := b.tracer.Start(ctx, tracingPrexfixBleve+"UploadSnapshot")
+	defer span.End()
+
+	copyable, ok := idx.index.(bleve.IndexCopyable)
+	if !ok {
+		return fmt.Errorf("index does not support copies")
+	}
+
+	// The changes made while copying are included in the next snapshot
+	changes := idx.changes.Swap(0)
+	uploaded := false
+	defer func() {
+		if !uploaded {
+			idx.changes.Add(changes)
+		}
+	}()
+
+	tmp, err := os.MkdirTemp(b.opts.Root, ".snapshot-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.RemoveAll(tmp)
+	}()
+
+	info := snapshotInfo{
+		RV:      idx.maxRV.Load(),
+		Mapping: idx.mappingHash,
+		Created: time.Now().UTC(),
+	}
+	if err = copyable.CopyTo(bleve.FileSystemDirectory(tmp)); err != nil {
+		return fmt.Errorf("copy index: %w", err)
+	}
+	copied, err := bleve.Open(tmp)
+	if err != nil {
+		return fmt.Errorf("open index copy: %w", err)
+	}
+	docs, err := copied.DocCount()
+	_ = copied.Close()
+	if err != nil {
+		return err
+	}
+	info.Docs = int64(docs)
+
+	// Cancelling the context aborts the upload
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	w, err := b.opts.Snapshots.NewWriter(ctx, snapshotKey(idx.key), &blob.WriterOptions{
+		ContentType: "application/gzip",
+	})
+	if err != nil {
+		return err
+	}
+	if err = writeSnapshot(w, tmp, info); err != nil {
+		cancel()
+		_ = w.Close()
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	uploaded = true
+
+	b.log.Info("uploaded search index snapshot", "namespace", idx.key.Namespace, "group", idx.key.Group, "resource", idx.key.Resource, "rv", info.RV, "docs", info.Docs)
+	return nil
+}
+
+// writeSnapshot writes the info and the index files as a gzipped tar
+func writeSnapshot(w io.Writer, dir string, info snapshotInfo) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	body, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	if err = tw.WriteHeader(&tar.Header{
+		Name:    snapshotInfoFile,
+		Mode:    0600,
+		Size:    int64(len(body)),
+		ModTime: info.Created,
+	}); err != nil {
+		return err
+	}
+	if _, err = tw.Write(body); err != nil {
+		return err
+	}
+
+	err = filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if err = tw.WriteHeader(&tar.Header{
+			Name:    snapshotIndexDir + filepath.ToSlash(rel),
+			Mode:    0600,
+			Size:    fi.Size(),
+			ModTime: fi.ModTime(),
+		}); err != nil {
+			return err
+		}
+		// nolint:gosec
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// restoreSnapshot extracts the snapshot of the index into the directory.
+// It returns nil when there is no snapshot, or when it was created with different mappings.
+func (b *bleveBackend) restoreSnapshot(ctx context.Context, key resource.NamespacedResource, dir string, hash string) (*snapshotInfo, error) {
+	ctx, span := b.tracer.Start(ctx, tracingPrexfixBleve+"RestoreSnapshot")
+	defer span.End()
+
+	r, err := b.opts.Snapshots.NewReader(ctx, snapshotKey(key), nil)
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+
+	info, err := readSnapshot(r, dir, hash)
+	if err != nil || info == nil {
+		_ = os.RemoveAll(dir)
+	}
+	return info, err
+}
+
+func readSnapshot(r io.Reader, dir string, hash string) (*snapshotInfo, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, err
+	}
+	if hdr.Name != snapshotInfoFile {
+		return nil, fmt.Errorf("missing snapshot info")
+	}
+	info := &snapshotInfo{}
+	if err = json.NewDecoder(tr).Decode(info); err != nil {
+		return nil, err
+	}
+	if info.Mapping != hash {
+		return nil, nil
+	}
+
+	for {
+		hdr, err = tr.Next()
+		if err == io.EOF {
+			return info, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg || !strings.HasPrefix(hdr.Name, snapshotIndexDir) {
+			continue
+		}
+		target := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(hdr.Name, snapshotIndexDir)))
+		if !isValidPath(target, dir) {
+			return nil, fmt.Errorf("invalid path in snapshot: %s", hdr.Name)
+		}
+		if err = os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+			return nil, err
+		}
+		// nolint:gosec
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return nil, err
+		}
+		// nolint:gosec
+		_, err = io.Copy(f, tr)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// runSnapshots periodically uploads the file based indexes that changed since their last snapshot
+func (b *bleveBackend) runSnapshots(ctx context.Context) {
+	ticker := time.NewTicker(b.opts.SnapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		b.cacheMu.RLock()
+		indexes := make([]*bleveIndex, 0, len(b.cache))
+		for _, idx := range b.cache {
+			if idx.fileBased && idx.changes.Load() > 0 {
+				indexes = append(indexes, idx)
+			}
+		}
+		b.cacheMu.RUnlock()
+
+		for _, idx := range indexes {
+			if !b.shouldSnapshot(idx) {
+				continue
+			}
+			if err := b.uploadSnapshot(ctx, idx); err != nil {
+				b.log.Warn("failed to upload search index snapshot", "namespace", idx.key.Namespace, "group", idx.key.Group, "resource", idx.key.Resource, "error", err)
+			}
+		}
+	}
+}
+
+func (b *bleveBackend) shouldSnapshot(idx *bleveIndex) bool {
+	if b.opts.Snapshots == nil || !idx.fileBased {
+		return false
+	}
+	docs, err := idx.index.DocCount()
+	return err == nil && int64(docs) >= b.opts.SnapshotMinCount
+}
+
+// RestoredRV implements resource.RestoredIndex
+func (b *bleveIndex) RestoredRV() int64 {
+	return b.restoredRV
+}
+
+// DocumentVersions implements resource.RestoredIndex
+func (b *bleveIndex) DocumentVersions(ctx context.Context) (map[string]int64, error) {
+	count, err := b.index.DocCount()
+	if err != nil {
+		return nil, err
+	}
+	req := bleve.NewSearchRequestOptions(bleve.NewMatchAllQuery(), int(count), 0, false)
+	req.Fields = []string{indexRVField}
+	res, err := b.index.SearchInContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]int64, len(res.Hits))
+	key := &resourcepb.ResourceKey{}
+	for _, hit := range res.Hits {
+		if err := resource.ReadSearchID(key, hit.ID); err != nil {
+			continue
+		}
+		rv, _ := hit.Fields[indexRVField].(float64)
+		versions[key.Name] = int64(rv)
+	}
+	return versions, nil
+}
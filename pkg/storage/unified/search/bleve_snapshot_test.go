@@ -0,0 +1,127 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob/memblob"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+)
+
+func TestBleveSnapshots(t *testing.T) {
+	ctx := context.Background()
+	bucket := memblob.OpenBucket(nil)
+	key := resource.NamespacedResource{
+		Namespace: "default",
+		Group:     "dashboard.grafana.app",
+		Resource:  "dashboards",
+	}
+	doc := func(name string, rv int64) *resource.BulkIndexItem {
+		return &resource.BulkIndexItem{
+			Action: resource.ActionIndex,
+			Doc: &resource.IndexableDocument{
+				RV:    rv,
+				Name:  name,
+				Title: name,
+				Key: &resourcepb.ResourceKey{
+					Namespace: key.Namespace,
+					Group:     key.Group,
+					Resource:  key.Resource,
+					Name:      name,
+				},
+			},
+		}
+	}
+	newBackend := func() *bleveBackend {
+		backend, err := NewBleveBackend(BleveOptions{
+			Root:          t.TempDir(),
+			FileThreshold: 1,
+			Snapshots:     bucket,
+			// The snapshots are uploaded by the tests
+			SnapshotMinCount: 1000,
+		}, tracing.NewNoopTracerService(), featuremgmt.WithFeatures(), nil)
+		require.NoError(t, err)
+		t.Cleanup(backend.Stop)
+		return backend
+	}
+
+	t.Run("no snapshot", func(t *testing.T) {
+		index, err := newBackend().BuildIndex(ctx, key, 3, 10, nil, func(index resource.ResourceIndex) (int64, error) {
+			require.Equal(t, int64(0), index.(resource.RestoredIndex).RestoredRV())
+			return 10, index.BulkIndex(&resource.BulkIndexRequest{
+				Items: []*resource.BulkIndexItem{doc("aaa", 1), doc("bbb", 2), doc("ccc", 3)},
+			})
+		})
+		require.NoError(t, err)
+
+		backend := newBackend()
+		require.NoError(t, backend.uploadSnapshot(ctx, index.(*bleveIndex)))
+		require.Equal(t, int64(0), index.(*bleveIndex).changes.Load())
+	})
+
+	t.Run("restore the snapshot", func(t *testing.T) {
+		index, err := newBackend().BuildIndex(ctx, key, 3, 20, nil, func(index resource.ResourceIndex) (int64, error) {
+			restored := index.(resource.RestoredIndex)
+			require.Equal(t, int64(10), restored.RestoredRV())
+
+			versions, err := restored.DocumentVersions(ctx)
+			require.NoError(t, err)
+			require.Equal(t, map[string]int64{"aaa": 1, "bbb": 2, "ccc": 3}, versions)
+
+			// Only the changes are indexed
+			return 20, index.BulkIndex(&resource.BulkIndexRequest{
+				Items: []*resource.BulkIndexItem{
+					doc("bbb", 15),
+					{Action: resource.ActionDelete, Key: doc("ccc", 0).Doc.Key},
+				},
+			})
+		})
+		require.NoError(t, err)
+
+		count, err := index.DocCount(ctx, "")
+		require.NoError(t, err)
+		require.Equal(t, int64(2), count)
+		require.Equal(t, int64(20), index.(*bleveIndex).maxRV.Load())
+
+		// The resource version is still indexed
+		minRV := float64(10)
+		query := bleve.NewNumericRangeQuery(&minRV, nil)
+		query.SetField(resource.SEARCH_FIELD_RV)
+		res, err := index.(*bleveIndex).index.SearchInContext(ctx, bleve.NewSearchRequest(query))
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), res.Total)
+		require.Equal(t, "bbb", res.Hits[0].ID[strings.LastIndex(res.Hits[0].ID, "/")+1:])
+	})
+
+	t.Run("ignore the snapshot when the mappings changed", func(t *testing.T) {
+		backend := newBackend()
+		index, err := backend.BuildIndex(ctx, key, 3, 30, nil, func(index resource.ResourceIndex) (int64, error) {
+			return 30, nil
+		})
+		require.NoError(t, err)
+		index.(*bleveIndex).mappingHash = "old"
+		require.NoError(t, backend.uploadSnapshot(ctx, index.(*bleveIndex)))
+
+		_, err = newBackend().BuildIndex(ctx, key, 3, 30, nil, func(index resource.ResourceIndex) (int64, error) {
+			require.Equal(t, int64(0), index.(resource.RestoredIndex).RestoredRV())
+			return 30, nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("nothing runs once stopped", func(t *testing.T) {
+		backend := newBackend()
+		backend.Stop()
+		backend.goBackground(func(ctx context.Context) {
+			t.Error("started after the backend stopped")
+		})
+		backend.bgWg.Wait()
+	})
+}
@@ -24,6 +24,11 @@ const DASHBOARD_LINK_COUNT = "link_count"
 const DASHBOARD_PANEL_TYPES = "panel_types"
 const DASHBOARD_DS_TYPES = "ds_types"
 const DASHBOARD_TRANSFORMATIONS = "transformation"
+const DASHBOARD_PANEL_TITLES = "panel_titles"
+const DASHBOARD_PANEL_DESCRIPTIONS = "panel_descriptions"
+const DASHBOARD_PANEL_QUERIES = "panel_queries"
+const DASHBOARD_PANEL_TEXT = "panel_text"
+const DASHBOARD_METRIC_NAMES = "metric_names"
 
 //------------------------------------------------------------
 // The following fields are added in enterprise
@@ -69,6 +74,39 @@ func DashboardBuilder(namespaced resource.NamespacedDocumentSupplier) (resource.
 				Filterable: true,
 			},
 		},
+		{
+			Name:        DASHBOARD_PANEL_TITLES,
+			Type:        resourcepb.ResourceTableColumnDefinition_STRING,
+			IsArray:     true,
+			Description: "The titles of all panels, including panels in collapsed rows",
+		},
+		{
+			Name:        DASHBOARD_PANEL_DESCRIPTIONS,
+			Type:        resourcepb.ResourceTableColumnDefinition_STRING,
+			IsArray:     true,
+			Description: "The panel descriptions",
+		},
+		{
+			Name:        DASHBOARD_PANEL_QUERIES,
+			Type:        resourcepb.ResourceTableColumnDefinition_STRING,
+			IsArray:     true,
+			Description: "The query text of the panel targets (PromQL, LogQL, SQL...)",
+		},
+		{
+			Name:        DASHBOARD_PANEL_TEXT,
+			Type:        resourcepb.ResourceTableColumnDefinition_STRING,
+			IsArray:     true,
+			Description: "The content of text panels",
+		},
+		{
+			Name:        DASHBOARD_METRIC_NAMES,
+			Type:        resourcepb.ResourceTableColumnDefinition_STRING,
+			IsArray:     true,
+			Description: "The metrics referenced by prometheus queries",
+			Properties: &resourcepb.ResourceTableColumnDefinition_Properties{
+				Filterable: true,
+			},
+		},
 		{
 			Name:        DASHBOARD_ERRORS_TODAY,
 			Type:        resourcepb.ResourceTableColumnDefinition_INT64,
@@ -327,6 +365,12 @@ func (s *DashboardDocumentBuilder) BuildDocument(ctx context.Context, key *resou
 		doc.Fields[DASHBOARD_TRANSFORMATIONS] = transformations
 	}
 
+	content := newPanelContent()
+	content.add(summary.Panels)
+	for field, values := range content.fields() {
+		doc.Fields[field] = values
+	}
+
 	// Add the stats fields
 	for k, v := range s.Stats[summary.UID] {
 		doc.Fields[k] = v
@@ -335,6 +379,52 @@ func (s *DashboardDocumentBuilder) BuildDocument(ctx context.Context, key *resou
 	return doc, nil
 }
 
+// panelContent collects the searchable text from all panels, including the panels in collapsed rows
+type panelContent struct {
+	values map[string]map[string]bool
+}
+
+func newPanelContent() *panelContent {
+	return &panelContent{values: make(map[string]map[string]bool)}
+}
+
+func (c *panelContent) add(panels []dashboard.PanelSummaryInfo) {
+	for _, p := range panels {
+		c.set(DASHBOARD_PANEL_TITLES, p.Title)
+		c.set(DASHBOARD_PANEL_DESCRIPTIONS, p.Description)
+		c.set(DASHBOARD_PANEL_TEXT, p.Text)
+		c.set(DASHBOARD_PANEL_QUERIES, p.Queries...)
+		c.set(DASHBOARD_METRIC_NAMES, p.MetricNames...)
+		c.add(p.Collapsed)
+	}
+}
+
+func (c *panelContent) set(field string, values ...string) {
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		if c.values[field] == nil {
+			c.values[field] = make(map[string]bool)
+		}
+		c.values[field][v] = true
+	}
+}
+
+// fields returns the sorted, unique values for each field
+func (c *panelContent) fields() map[string][]string {
+	fields := make(map[string][]string, len(c.values))
+	for field, set := range c.values {
+		values := make([]string, 0, len(set))
+		for v := range set {
+			values = append(values, v)
+		}
+		sort.Strings(values)
+		fields[field] = values
+	}
+	return fields
+}
+
 func DashboardFields() []string {
 	baseFields := []string{
 		DASHBOARD_SCHEMA_VERSION,
@@ -342,6 +432,11 @@ func DashboardFields() []string {
 		DASHBOARD_PANEL_TYPES,
 		DASHBOARD_DS_TYPES,
 		DASHBOARD_TRANSFORMATIONS,
+		DASHBOARD_PANEL_TITLES,
+		DASHBOARD_PANEL_DESCRIPTIONS,
+		DASHBOARD_PANEL_QUERIES,
+		DASHBOARD_PANEL_TEXT,
+		DASHBOARD_METRIC_NAMES,
 	}
 
 	return append(baseFields, UsageInsightsFields()...)
@@ -1,13 +1,18 @@
 package search
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/storage/unified/resource"
 	"go.opentelemetry.io/otel/trace"
+	"gocloud.dev/blob"
+	"gopkg.in/yaml.v3"
 )
 
 func NewSearchOptions(features featuremgmt.FeatureToggles, cfg *setting.Cfg, tracer trace.Tracer, docs resource.DocumentBuilderSupplier, indexMetrics *resource.BleveIndexMetrics) (resource.SearchOptions, error) {
@@ -22,11 +27,28 @@ func NewSearchOptions(features featuremgmt.FeatureToggles, cfg *setting.Cfg, tra
 		if err != nil {
 			return resource.SearchOptions{}, err
 		}
-		bleve, err := NewBleveBackend(BleveOptions{
+		opts := BleveOptions{
 			Root:          root,
 			FileThreshold: int64(cfg.IndexFileThreshold), // fewer than X items will use a memory index
 			BatchSize:     cfg.IndexMaxBatchSize,         // This is the batch size for how many objects to add to the index at once
-		}, tracer, features, indexMetrics)
+			FuzzyMaxEdits: cfg.SearchFuzzyMaxEdits,
+			UsageBoost:    cfg.SearchUsageBoost,
+		}
+		if cfg.SearchSynonymsFile != "" {
+			opts.Synonyms, err = readSynonyms(cfg.SearchSynonymsFile)
+			if err != nil {
+				return resource.SearchOptions{}, err
+			}
+		}
+		if cfg.IndexSnapshots {
+			opts.Snapshots, err = openSnapshotBucket(cfg)
+			if err != nil {
+				return resource.SearchOptions{}, err
+			}
+			opts.SnapshotInterval = cfg.IndexSnapshotInterval
+			opts.SnapshotMinCount = int64(cfg.IndexSnapshotMinCount)
+		}
+		bleve, err := NewBleveBackend(opts, tracer, features, indexMetrics)
 
 		if err != nil {
 			return resource.SearchOptions{}, err
@@ -42,3 +64,39 @@ func NewSearchOptions(features featuremgmt.FeatureToggles, cfg *setting.Cfg, tra
 	}
 	return resource.SearchOptions{}, nil
 }
+
+// openSnapshotBucket opens the apiserver blob store, where the index snapshots are shared
+func openSnapshotBucket(cfg *setting.Cfg) (*blob.Bucket, error) {
+	url := cfg.SectionWithEnvOverrides("grafana-apiserver").Key("blob_url").MustString("")
+	if url == "" {
+		return nil, fmt.Errorf("index snapshots require a blob_url in the grafana-apiserver section")
+	}
+	// Support local file blob
+	if strings.HasPrefix(url, "./data/") {
+		dir := strings.Replace(url, "./data", cfg.DataPath, 1)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, err
+		}
+		url = "file:///" + dir
+	}
+	return resource.OpenBlobBucket(context.Background(), url)
+}
+
+// readSynonyms reads the synonym groups by namespace, eg:
+//
+//	"*":
+//	  - [k8s, kubernetes]
+//	stacks-123:
+//	  - [prod, production]
+func readSynonyms(path string) (map[string][][]string, error) {
+	// nolint:gosec
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading search synonyms: %w", err)
+	}
+	synonyms := make(map[string][][]string)
+	if err = yaml.Unmarshal(body, &synonyms); err != nil {
+		return nil, fmt.Errorf("error parsing search synonyms %s: %w", path, err)
+	}
+	return synonyms, nil
+}
@@ -0,0 +1,72 @@
+package unified
+
+import (
+	"context"
+	"time"
+
+	infraDB "github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/storage/unified/federated"
+)
+
+const (
+	shardMapKey        = "shard-map"
+	shardMapLockAction = "unified-storage-shard-map"
+)
+
+// kvShardMapStore saves the shard map in the Grafana database, so every instance using the database shares it.
+// The updates are serialized with a server lock.
+type kvShardMapStore struct {
+	kv   *kvstore.NamespacedKVStore
+	lock *serverlock.ServerLockService
+}
+
+var _ federated.ShardMapStore = (*kvShardMapStore)(nil)
+
+func newShardMapStore(db infraDB.DB, tracer tracing.Tracer) *kvShardMapStore {
+	return &kvShardMapStore{
+		kv:   kvstore.WithNamespace(kvstore.ProvideService(db), 0, "unified-storage"),
+		lock: serverlock.ProvideService(db, tracer),
+	}
+}
+
+func (s *kvShardMapStore) Get(ctx context.Context) (*federated.ShardMap, error) {
+	value, _, err := s.kv.Get(ctx, shardMapKey)
+	if err != nil {
+		return nil, err
+	}
+	return federated.DecodeShardMap(value)
+}
+
+func (s *kvShardMapStore) Update(ctx context.Context, fn func(m *federated.ShardMap) error) (*federated.ShardMap, error) {
+	var (
+		m   *federated.ShardMap
+		err error
+	)
+	lockErr := s.lock.LockExecuteAndReleaseWithRetries(ctx, shardMapLockAction, serverlock.LockTimeConfig{
+		MaxInterval: time.Minute,
+		MinWait:     10 * time.Millisecond,
+		MaxWait:     100 * time.Millisecond,
+	}, func(ctx context.Context) {
+		m, err = s.Get(ctx)
+		if err != nil {
+			return
+		}
+		if err = fn(m); err != nil {
+			return
+		}
+		var value string
+		if value, err = federated.EncodeShardMap(m); err == nil {
+			err = s.kv.Set(ctx, shardMapKey, value)
+		}
+	})
+	if lockErr != nil {
+		return nil, lockErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
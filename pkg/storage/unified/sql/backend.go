@@ -53,6 +53,15 @@ type BackendOptions struct {
 	// Will be removed once fully rolled out.
 	withPruner bool
 
+	// If true, the online migrations run in the background after the init
+	OnlineMigrations             bool
+	OnlineMigrationBatchSize     int64
+	OnlineMigrationBatchInterval time.Duration
+
+	// The history kept for each resource, older versions are compacted in the background
+	HistoryRetention   []resource.HistoryRetention
+	CompactionInterval time.Duration
+
 	// testing
 	SimulatedNetworkLatency time.Duration // slows down the create transactions by a fixed amount
 }
@@ -86,6 +95,11 @@ func NewBackend(opts BackendOptions) (Backend, error) {
 		bulkLock:                &bulkLock{running: make(map[string]bool)},
 		simulatedNetworkLatency: opts.SimulatedNetworkLatency,
 		withPruner:              opts.withPruner,
+		onlineMigrations:        opts.OnlineMigrations,
+		onlineMigrationBatch:    opts.OnlineMigrationBatchSize,
+		onlineMigrationInterval: opts.OnlineMigrationBatchInterval,
+		historyRetention:        opts.HistoryRetention,
+		compactionInterval:      opts.CompactionInterval,
 	}, nil
 }
 
@@ -148,6 +162,17 @@ type backend struct {
 
 	historyPruner pruner
 	withPruner    bool
+
+	// online migrations
+	onlineMigrations        bool
+	onlineMigrationBatch    int64
+	onlineMigrationInterval time.Duration
+	migrator                *onlineMigrator
+
+	// history compaction
+	historyRetention   []resource.HistoryRetention
+	compactionInterval time.Duration
+	compactor          *historyCompactor
 }
 
 func (b *backend) Init(ctx context.Context) error {
@@ -197,9 +222,35 @@ func (b *backend) initLocked(ctx context.Context) error {
 		return fmt.Errorf("failed to create pruner: %w", err)
 	}
 
+	b.migrator = newOnlineMigrator(b.db, b.dialect, b.tracer, b.onlineMigrationBatch, b.onlineMigrationInterval)
+	if b.onlineMigrations {
+		b.runInBackground(b.migrator.run)
+	}
+
+	b.compactor = newHistoryCompactor(b.db, b.dialect, b.tracer, b.historyRetention, b.compactionInterval)
+	if len(b.historyRetention) > 0 {
+		b.runInBackground(b.compactor.run)
+	}
+
 	return nil
 }
 
+// runInBackground runs the function until the backend stops
+func (b *backend) runInBackground(fn func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-b.done
+		cancel()
+	}()
+	go fn(ctx)
+}
+
+// OnlineMigrationCompleted checks if the online migration has finished.
+// Until then, the readers must handle the rows that are not migrated yet.
+func (b *backend) OnlineMigrationCompleted(id string) bool {
+	return b.migrator != nil && b.migrator.Completed(id)
+}
+
 func (b *backend) initPruner(ctx context.Context) error {
 	if !b.withPruner {
 		b.log.Debug("using noop history pruner")
@@ -218,6 +269,8 @@ func (b *backend) initPruner(ctx context.Context) error {
 				res, err := dbutil.Exec(ctx, tx, sqlResourceHistoryPrune, &sqlPruneHistoryRequest{
 					SQLTemplate:  sqltemplate.New(b.dialect),
 					HistoryLimit: defaultPrunerHistoryLimit,
+					// The history is only partitioned by generation once the old rows have one
+					PartitionByGeneration: b.OnlineMigrationCompleted(historyGenerationBackfill.id),
 					Key: &resourcepb.ResourceKey{
 						Namespace: key.namespace,
 						Group:     key.group,
@@ -298,6 +351,9 @@ func (b *backend) GetResourceStats(ctx context.Context, namespace string, minCou
 			if err != nil {
 				return err
 			}
+			if policy, ok := b.compactor.policy(row.Group, row.Resource); ok {
+				row.Retention = &policy
+			}
 			if row.Count > int64(minCount) {
 				res = append(res, row)
 			}
@@ -385,6 +441,19 @@ func (b *backend) create(ctx context.Context, event resource.WriteEvent) (int64,
 	return rv, nil
 }
 
+// checkResourceWritten returns a not found error when the update or delete did not change any row,
+// so no history is written for a resource that does not exist.
+func checkResourceWritten(res sql.Result, key *resourcepb.ResourceKey) error {
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return resource.NewResourceNotFoundError(key)
+	}
+	return nil
+}
+
 // IsRowAlreadyExistsError checks if the error is the result of the row inserted already existing.
 func IsRowAlreadyExistsError(err error) bool {
 	var sqlite sqlite3.Error
@@ -425,7 +494,7 @@ func (b *backend) update(ctx context.Context, event resource.WriteEvent) (int64,
 	// Use rvManager.ExecWithRV instead of direct transaction
 	rv, err := b.rvManager.ExecWithRV(ctx, event.Key, func(tx db.Tx) (string, error) {
 		// 1. Update resource
-		_, err := dbutil.Exec(ctx, tx, sqlResourceUpdate, sqlResourceRequest{
+		res, err := dbutil.Exec(ctx, tx, sqlResourceUpdate, sqlResourceRequest{
 			SQLTemplate: sqltemplate.New(b.dialect),
 			WriteEvent:  event,
 			Folder:      folder,
@@ -434,6 +503,9 @@ func (b *backend) update(ctx context.Context, event resource.WriteEvent) (int64,
 		if err != nil {
 			return event.GUID, fmt.Errorf("resource update: %w", err)
 		}
+		if err = checkResourceWritten(res, event.Key); err != nil {
+			return event.GUID, err
+		}
 
 		// 2. Insert into resource history
 		if _, err := dbutil.Exec(ctx, tx, sqlResourceHistoryInsert, sqlResourceRequest{
@@ -480,7 +552,7 @@ func (b *backend) delete(ctx context.Context, event resource.WriteEvent) (int64,
 	}
 	rv, err := b.rvManager.ExecWithRV(ctx, event.Key, func(tx db.Tx) (string, error) {
 		// 1. delete from resource
-		_, err := dbutil.Exec(ctx, tx, sqlResourceDelete, sqlResourceRequest{
+		res, err := dbutil.Exec(ctx, tx, sqlResourceDelete, sqlResourceRequest{
 			SQLTemplate: sqltemplate.New(b.dialect),
 			WriteEvent:  event,
 			GUID:        event.GUID,
@@ -488,6 +560,9 @@ func (b *backend) delete(ctx context.Context, event resource.WriteEvent) (int64,
 		if err != nil {
 			return event.GUID, fmt.Errorf("delete resource: %w", err)
 		}
+		if err = checkResourceWritten(res, event.Key); err != nil {
+			return event.GUID, err
+		}
 
 		// 2. Add event to resource history
 		if _, err := dbutil.Exec(ctx, tx, sqlResourceHistoryInsert, sqlResourceRequest{
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/mattn/go-sqlite3"
@@ -206,6 +207,29 @@ func TestBackend_IsHealthy(t *testing.T) {
 	require.ErrorIs(t, err, errTest)
 }
 
+func TestBackend_GetResourceStats(t *testing.T) {
+	t.Parallel()
+
+	b, ctx := setupBackendTest(t)
+	b.compactor = newHistoryCompactor(b.db, b.dialect, b.tracer, []resource.HistoryRetention{
+		{Group: "gr", Resource: "rs", Versions: 2, Daily: 24 * time.Hour},
+	}, time.Hour)
+
+	b.SQLMock.ExpectBegin()
+	b.QueryWithResult("select namespace group resource from resource", 5, Rows{
+		{"ns", "gr", "rs", 3, 100},
+		{"ns", "gr", "other", 1, 101},
+	})
+	b.SQLMock.ExpectCommit()
+
+	stats, err := b.GetResourceStats(ctx, "ns", 0)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	require.Equal(t, &resourcepb.ResourceStatsResponse_HistoryRetention{Versions: 2, Daily: 86400000}, stats[0].Retention.AsProto())
+	require.Nil(t, stats[1].Retention)
+	require.Nil(t, stats[1].Retention.AsProto())
+}
+
 func TestBackend_create(t *testing.T) {
 	t.Parallel()
 	meta, err := utils.MetaAccessor(&unstructured.Unstructured{
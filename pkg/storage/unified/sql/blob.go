@@ -3,9 +3,13 @@ package sql
 import (
 	"context"
 	"crypto/md5"
+	"database/sql"
 	"encoding/hex"
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,7 +23,8 @@ import (
 )
 
 var (
-	_ resource.BlobSupport = (*backend)(nil)
+	_ resource.BlobSupport          = (*backend)(nil)
+	_ resource.BlobGarbageCollector = (*backend)(nil)
 )
 
 func (b *backend) SupportsSignedURLs() bool {
@@ -55,15 +60,21 @@ func (b *backend) PutResourceBlob(ctx context.Context, req *resourcepb.PutBlobRe
 		}, nil
 	}
 
-	// Insert the value
+	// Insert the chunks and the value that references them
+	chunks := resource.SplitBlobChunks(req.Value)
 	err = b.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
-		_, err := dbutil.Exec(ctx, tx, sqlResourceBlobInsert, sqlResourceBlobInsertRequest{
+		hashes, err := putBlobChunks(ctx, tx, b.dialect, req.Resource.Namespace, chunks)
+		if err != nil {
+			return err
+		}
+		_, err = dbutil.Exec(ctx, tx, sqlResourceBlobInsert, sqlResourceBlobInsertRequest{
 			SQLTemplate: sqltemplate.New(b.dialect),
 			Now:         time.Now(),
 			Info:        info,
 			Key:         req.Resource,
 			ContentType: req.ContentType,
-			Value:       req.Value,
+			Value:       []byte{},
+			Chunks:      strings.Join(hashes, ","),
 		})
 		return err
 	})
@@ -102,16 +113,26 @@ func (b *backend) GetResourceBlob(ctx context.Context, key *resourcepb.ResourceK
 		if err != nil {
 			return err
 		}
-		if rows.Next() {
-			uid := ""
-			err = rows.Scan(&uid, &rsp.Value, &rsp.ContentType)
-			if info.UID != "" && info.UID != uid {
-				return fmt.Errorf("unexpected uid in result")
+		if !rows.Next() {
+			rsp.Error = &resourcepb.ErrorResult{
+				Code: http.StatusNotFound,
 			}
+			return rows.Close()
+		}
+		uid := ""
+		chunks := sql.NullString{}
+		err = rows.Scan(&uid, &rsp.Value, &rsp.ContentType, &chunks)
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
 			return err
 		}
-		rsp.Error = &resourcepb.ErrorResult{
-			Code: http.StatusNotFound,
+		if info.UID != "" && info.UID != uid {
+			return fmt.Errorf("unexpected uid in result")
+		}
+		if chunks.String != "" {
+			rsp.Value, err = readBlobChunks(ctx, tx, b.dialect, key.Namespace, strings.Split(chunks.String, ","))
 		}
 		return err
 	})
@@ -120,3 +141,175 @@ func (b *backend) GetResourceBlob(ctx context.Context, key *resourcepb.ResourceK
 	}
 	return rsp, nil
 }
+
+// DeleteResourceBlob implements resource.BlobGarbageCollector
+func (b *backend) DeleteResourceBlob(ctx context.Context, key *resourcepb.ResourceKey, uid string) error {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"DeleteResourceBlob")
+	defer span.End()
+
+	return b.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
+		blobs, err := dbutil.Query(ctx, tx, sqlResourceBlobList, &sqlResourceBlobListRequest{
+			SQLTemplate: sqltemplate.New(b.dialect),
+			Key:         key,
+			UID:         uid,
+			Response:    &blobRef{},
+		})
+		if err != nil {
+			return err
+		}
+		for _, blob := range blobs {
+			if err = deleteBlob(ctx, tx, b.dialect, key, blob); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CollectBlobGarbage implements resource.BlobGarbageCollector.
+// The chunks are removed with the last blob referencing them, this only removes the chunks left by a failed removal.
+func (b *backend) CollectBlobGarbage(ctx context.Context) (int64, error) {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"CollectBlobGarbage")
+	defer span.End()
+
+	res, err := dbutil.Exec(ctx, b.db, sqlResourceBlobChunkDelete, sqlResourceBlobChunkDeleteRequest{
+		SQLTemplate: sqltemplate.New(b.dialect),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// putBlobChunks adds a reference to the existing chunks and inserts the others.
+// The existing chunks are locked until the transaction ends, so a blob removed at the same time can not delete them,
+// and a chunk inserted by another blob at the same time is referenced instead.
+// Returns the hashes of all the chunks, in order.
+func putBlobChunks(ctx context.Context, tx db.ContextExecer, dialect sqltemplate.Dialect, namespace string, chunks [][]byte) ([]string, error) {
+	hashes := make([]string, 0, len(chunks))
+	byHash := make(map[string][]byte, len(chunks))
+	for _, chunk := range chunks {
+		hash := resource.BlobChunkHash(chunk)
+		hashes = append(hashes, hash)
+		byHash[hash] = chunk
+	}
+	unique := slices.Sorted(maps.Keys(byHash))
+
+	existing, err := dbutil.Query(ctx, tx, sqlResourceBlobChunkExisting, &sqlResourceBlobChunkQueryRequest{
+		SQLTemplate: sqltemplate.New(dialect),
+		Namespace:   namespace,
+		Hashes:      unique,
+		Response:    &blobChunk{},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find chunks: %w", err)
+	}
+	if len(existing) > 0 {
+		shared := make([]string, 0, len(existing))
+		for _, c := range existing {
+			shared = append(shared, c.Hash)
+			delete(byHash, c.Hash)
+		}
+		res, err := dbutil.Exec(ctx, tx, sqlResourceBlobChunkRefs, sqlResourceBlobChunkRefsRequest{
+			SQLTemplate: sqltemplate.New(dialect),
+			Namespace:   namespace,
+			Hashes:      shared,
+			Delta:       1,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("reference chunks: %w", err)
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("reference chunks: %w", err)
+		}
+		if rows != int64(len(shared)) {
+			return nil, fmt.Errorf("reference chunks: expected %d chunks, referenced %d", len(shared), rows)
+		}
+	}
+	for _, hash := range unique {
+		chunk, ok := byHash[hash]
+		if !ok {
+			continue
+		}
+		_, err = dbutil.Exec(ctx, tx, sqlResourceBlobChunkInsert, sqlResourceBlobChunkInsertRequest{
+			SQLTemplate: sqltemplate.New(dialect),
+			Namespace:   namespace,
+			Hash:        hash,
+			Size:        int64(len(chunk)),
+			Value:       chunk,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("insert chunk: %w", err)
+		}
+	}
+	return hashes, nil
+}
+
+// readBlobChunks joins the chunks in order
+func readBlobChunks(ctx context.Context, tx db.ContextExecer, dialect sqltemplate.Dialect, namespace string, hashes []string) ([]byte, error) {
+	unique := slices.Compact(slices.Sorted(slices.Values(hashes)))
+	chunks, err := dbutil.Query(ctx, tx, sqlResourceBlobChunkRead, &sqlResourceBlobChunkQueryRequest{
+		SQLTemplate: sqltemplate.New(dialect),
+		Namespace:   namespace,
+		Hashes:      unique,
+		Response:    &blobChunk{},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read chunks: %w", err)
+	}
+	byHash := make(map[string][]byte, len(chunks))
+	size := 0
+	for _, c := range chunks {
+		if resource.BlobChunkHash(c.Value) != c.Hash {
+			return nil, fmt.Errorf("corrupted chunk %s", c.Hash)
+		}
+		byHash[c.Hash] = c.Value
+		size += len(c.Value)
+	}
+	value := make([]byte, 0, size)
+	for _, hash := range hashes {
+		chunk, ok := byHash[hash]
+		if !ok {
+			return nil, fmt.Errorf("missing chunk %s", hash)
+		}
+		value = append(value, chunk...)
+	}
+	return value, nil
+}
+
+// deleteBlob removes the blob, and the chunks no other blob references
+func deleteBlob(ctx context.Context, tx db.ContextExecer, dialect sqltemplate.Dialect, key *resourcepb.ResourceKey, blob *blobRef) error {
+	res, err := dbutil.Exec(ctx, tx, sqlResourceBlobDelete, sqlResourceBlobDeleteRequest{
+		SQLTemplate: sqltemplate.New(dialect),
+		Key:         key,
+		UID:         blob.UID,
+	})
+	if err != nil {
+		return fmt.Errorf("delete blob: %w", err)
+	}
+	// already removed by another transaction, its references are gone too
+	if rows, err := res.RowsAffected(); err != nil || rows == 0 || blob.Chunks.String == "" {
+		return err
+	}
+
+	hashes := slices.Compact(slices.Sorted(slices.Values(strings.Split(blob.Chunks.String, ","))))
+	_, err = dbutil.Exec(ctx, tx, sqlResourceBlobChunkRefs, sqlResourceBlobChunkRefsRequest{
+		SQLTemplate: sqltemplate.New(dialect),
+		Namespace:   key.Namespace,
+		Hashes:      hashes,
+		Delta:       -1,
+	})
+	if err != nil {
+		return fmt.Errorf("release chunks: %w", err)
+	}
+	_, err = dbutil.Exec(ctx, tx, sqlResourceBlobChunkDelete, sqlResourceBlobChunkDeleteRequest{
+		SQLTemplate: sqltemplate.New(dialect),
+		Namespace:   key.Namespace,
+		Hashes:      hashes,
+	})
+	if err != nil {
+		return fmt.Errorf("delete chunks: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/sqltemplate"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/test"
+	"github.com/grafana/grafana/pkg/util/testutil"
+)
+
+func TestPutBlobChunks(t *testing.T) {
+	t.Parallel()
+
+	existing := []byte("existing chunk")
+	added := []byte("added chunk")
+	existingHash := resource.BlobChunkHash(existing)
+	addedHash := resource.BlobChunkHash(added)
+
+	t.Run("references the existing chunks and inserts the others", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.NewDefaultTestContext(t)
+		dbp := test.NewDBProviderMatchWords(t)
+		dbp.SQLMock.ExpectQuery("select hash from resource_blob_chunk for update").
+			WithArgs("ns", sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(dbp.SQLMock.NewRows([]string{"hash"}).AddRow(existingHash))
+		dbp.SQLMock.ExpectExec("update resource_blob_chunk set refs").WithArgs(int64(1), "ns", existingHash).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbp.SQLMock.ExpectExec("insert into resource_blob_chunk on duplicate key update").
+			WithArgs("ns", addedHash, int64(len(added)), added).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		hashes, err := putBlobChunks(ctx, dbp.DB, sqltemplate.MySQL, "ns", [][]byte{existing, added, existing})
+		require.NoError(t, err)
+		require.Equal(t, []string{existingHash, addedHash, existingHash}, hashes)
+		require.NoError(t, dbp.SQLMock.ExpectationsWereMet())
+	})
+
+	t.Run("fails when an existing chunk was removed in between", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.NewDefaultTestContext(t)
+		dbp := test.NewDBProviderMatchWords(t)
+		dbp.SQLMock.ExpectQuery("select hash from resource_blob_chunk").
+			WillReturnRows(dbp.SQLMock.NewRows([]string{"hash"}).AddRow(existingHash))
+		dbp.SQLMock.ExpectExec("update resource_blob_chunk set refs").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err := putBlobChunks(ctx, dbp.DB, sqltemplate.MySQL, "ns", [][]byte{existing})
+		require.ErrorContains(t, err, "expected 1 chunks, referenced 0")
+		require.NoError(t, dbp.SQLMock.ExpectationsWereMet())
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -150,6 +151,7 @@ func (b *backend) ProcessBulk(ctx context.Context, setting resource.BulkSettings
 // internal bulk process
 func (b *backend) processBulk(ctx context.Context, setting resource.BulkSettings, iter resource.BulkRequestIterator) *resourcepb.BulkResponse {
 	rsp := &resourcepb.BulkResponse{}
+	var written []*resource.WrittenEvent
 	err := b.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
 		rollbackWithError := func(err error) error {
 			txerr := tx.Rollback()
@@ -170,18 +172,29 @@ func (b *backend) processBulk(ctx context.Context, setting resource.BulkSettings
 		// Calculate the RV based on incoming request timestamps
 		rv := newBulkRV()
 
+		// When appending, the events are written on top of the existing history
+		// with resource versions allocated from the collection (see rvManager.lock).
+		// This keeps the last resource version written to each group/resource
+		appendRV := make(map[string]int64, len(setting.Collection))
+
 		summaries := make(map[string]*resourcepb.BulkResponse_Summary, len(setting.Collection)*4)
 
 		// First clear everything in the transaction
-		if setting.RebuildCollection {
-			for _, key := range setting.Collection {
-				summary, err := bulk.deleteCollection(key)
+		for _, key := range setting.Collection {
+			summary := &resourcepb.BulkResponse_Summary{
+				Namespace: key.Namespace,
+				Group:     key.Group,
+				Resource:  key.Resource,
+			}
+			if setting.RebuildCollection {
+				var err error
+				summary, err = bulk.deleteCollection(key)
 				if err != nil {
 					return rollbackWithError(err)
 				}
-				summaries[resource.NSGR(key)] = summary
-				rsp.Summary = append(rsp.Summary, summary)
 			}
+			summaries[resource.NSGR(key)] = summary
+			rsp.Summary = append(rsp.Summary, summary)
 		}
 
 		obj := &unstructured.Unstructured{}
@@ -216,21 +229,59 @@ func (b *backend) processBulk(ctx context.Context, setting resource.BulkSettings
 				continue
 			}
 
+			event := resource.WriteEvent{
+				Key:        req.Key,
+				Type:       resourcepb.WatchEvent_Type(req.Action),
+				Value:      req.Value,
+				PreviousRV: -1, // Used for WATCH, but we want to skip watch events
+			}
+			var eventRV int64
+			if setting.RebuildCollection {
+				eventRV = rv.next(obj)
+			} else {
+				if _, ok := summaries[resource.NSGR(req.Key)]; !ok {
+					rsp.Rejected = append(rsp.Rejected, &resourcepb.BulkResponse_Rejected{
+						Key:    req.Key,
+						Action: req.Action,
+						Error:  "key is not in the collection",
+					})
+					continue
+				}
+				gr := req.Key.Group + "/" + req.Key.Resource
+				last, ok := appendRV[gr]
+				if ok {
+					eventRV = last + 1
+				} else {
+					eventRV, err = b.rvManager.lock(ctx, tx, req.Key.Group, req.Key.Resource)
+					if err != nil {
+						return rollbackWithError(err)
+					}
+				}
+				appendRV[gr] = eventRV
+				event.PreviousRV = 0 // unknown, but the changes are sent to watchers
+			}
+
 			// Write the event to history
 			if _, err := dbutil.Exec(ctx, tx, sqlResourceHistoryInsert, sqlResourceRequest{
-				SQLTemplate: sqltemplate.New(b.dialect),
-				WriteEvent: resource.WriteEvent{
-					Key:        req.Key,
-					Type:       resourcepb.WatchEvent_Type(req.Action),
-					Value:      req.Value,
-					PreviousRV: -1, // Used for WATCH, but we want to skip watch events
-				},
+				SQLTemplate:     sqltemplate.New(b.dialect),
+				WriteEvent:      event,
 				Folder:          req.Folder,
 				GUID:            uuid.New().String(),
-				ResourceVersion: rv.next(obj),
+				ResourceVersion: eventRV,
 			}); err != nil {
 				return rollbackWithError(fmt.Errorf("insert into resource history: %w", err))
 			}
+
+			if !setting.RebuildCollection {
+				written = append(written, &resource.WrittenEvent{
+					Type:            event.Type,
+					Key:             event.Key,
+					PreviousRV:      event.PreviousRV,
+					Value:           event.Value,
+					Folder:          req.Folder,
+					ResourceVersion: eventRV,
+				})
+			}
 		}
 
 		// Now update the resource table from history
@@ -241,11 +292,22 @@ func (b *backend) processBulk(ctx context.Context, setting resource.BulkSettings
 				return rollbackWithError(fmt.Errorf("missing summary key for: %s", k))
 			}
 
+			// The history is kept, so the current values are replaced from the (appended) history
+			if !setting.RebuildCollection {
+				if err := bulk.clearCollection(key); err != nil {
+					return rollbackWithError(err)
+				}
+			}
+
 			err := bulk.syncCollection(key, summary)
 			if err != nil {
 				return err
 			}
 
+			if !setting.RebuildCollection {
+				continue
+			}
+
 			// Make sure the collection RV is above our last written event
 			_, err = b.rvManager.ExecWithRV(ctx, key, func(tx db.Tx) (string, error) {
 				return "", nil
@@ -254,10 +316,23 @@ func (b *backend) processBulk(ctx context.Context, setting resource.BulkSettings
 				b.log.Warn("error increasing RV", "error", err)
 			}
 		}
+
+		// Record the latest RV of the appended collections
+		for gr, last := range appendRV {
+			group, res, _ := strings.Cut(gr, "/")
+			if err := b.rvManager.saveRV(ctx, tx, group, res, last); err != nil {
+				return rollbackWithError(err)
+			}
+		}
 		return nil
 	})
 	if err != nil {
 		rsp.Error = resource.AsErrorResult(err)
+		return rsp
+	}
+
+	for _, event := range written {
+		b.notifier.send(ctx, event)
 	}
 	return rsp
 }
@@ -307,6 +382,17 @@ func (w *bulkWroker) deleteCollection(key *resourcepb.ResourceKey) (*resourcepb.
 	return summary, err
 }
 
+// This will remove everything from the `resource` table for a given namespace/group/resource, the history is kept
+func (w *bulkWroker) clearCollection(key *resourcepb.ResourceKey) error {
+	_, err := dbutil.Exec(w.ctx, w.tx, sqlResourceDelete, &sqlResourceRequest{
+		SQLTemplate: sqltemplate.New(w.dialect),
+		WriteEvent: resource.WriteEvent{
+			Key: key,
+		},
+	})
+	return err
+}
+
 // Copy the latest value from history into the active resource table
 func (w *bulkWroker) syncCollection(key *resourcepb.ResourceKey, summary *resourcepb.BulkResponse_Summary) error {
 	w.logger.Info("synchronize collection", "key", resource.NSGR(key))
@@ -3,8 +3,12 @@ package sql
 import (
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
 )
 
 func TestBatch(t *testing.T) {
@@ -22,3 +26,72 @@ func TestBatch(t *testing.T) {
 		require.Equal(t, int64(1), v2-v1)
 	})
 }
+
+func TestBackend_processBulkAppend(t *testing.T) {
+	t.Parallel()
+
+	collection := &resourcepb.ResourceKey{Namespace: "ns", Group: "gr", Resource: "rs"}
+	request := func(name string, action resourcepb.BulkRequest_Action) *resourcepb.BulkRequest {
+		return &resourcepb.BulkRequest{
+			Key:    &resourcepb.ResourceKey{Namespace: "ns", Group: "gr", Resource: "rs", Name: name},
+			Action: action,
+			Value:  []byte(`{"apiVersion":"gr/v1","kind":"Rs","metadata":{"name":"` + name + `"}}`),
+		}
+	}
+
+	t.Run("history is kept and the last written resource version is saved", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		b.SQLMock.ExpectBegin()
+		expectSuccessfulResourceVersionLock(t, b.TestDBProvider, 100, 200)
+		b.ExecWithResult("insert resource_history", 0, 1)
+		b.ExecWithResult("insert resource_history", 0, 1)
+		b.ExecWithResult("delete resource", 0, 1)
+		b.ExecWithResult("insert resource select resource_history", 0, 1)
+		b.SQLMock.ExpectQuery("select count max resource").
+			WillReturnRows(sqlmock.NewRows([]string{"namespace", "group", "resource", "count", "rv"}).
+				AddRow("ns", "gr", "rs", 1, 201))
+		b.SQLMock.ExpectExec("update resource_version set resource_version").
+			WithArgs(int64(201), "gr", "rs").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		b.SQLMock.ExpectCommit()
+
+		rsp := b.processBulk(ctx, resource.BulkSettings{
+			Collection: []*resourcepb.ResourceKey{collection},
+		}, resource.NewBulkRequestIterator([]*resourcepb.BulkRequest{
+			request("a", resourcepb.BulkRequest_MODIFIED),
+			request("b", resourcepb.BulkRequest_DELETED),
+		}))
+		require.Nil(t, rsp.Error)
+		require.Empty(t, rsp.Rejected)
+		require.Equal(t, int64(2), rsp.Processed)
+		require.Len(t, rsp.Summary, 1)
+		require.Equal(t, int64(1), rsp.Summary[0].Count)
+		require.Equal(t, int64(201), rsp.Summary[0].ResourceVersion)
+		require.Zero(t, rsp.Summary[0].PreviousHistory)
+		require.NoError(t, b.SQLMock.ExpectationsWereMet())
+	})
+
+	t.Run("keys outside the collection are rejected", func(t *testing.T) {
+		t.Parallel()
+		b, ctx := setupBackendTest(t)
+
+		other := request("a", resourcepb.BulkRequest_ADDED)
+		other.Key.Resource = "other"
+
+		b.SQLMock.ExpectBegin()
+		b.ExecWithResult("delete resource", 0, 0)
+		b.ExecWithResult("insert resource select resource_history", 0, 0)
+		b.QueryWithResult("select count max resource", 5, nil)
+		b.SQLMock.ExpectCommit()
+
+		rsp := b.processBulk(ctx, resource.BulkSettings{
+			Collection: []*resourcepb.ResourceKey{collection},
+		}, resource.NewBulkRequestIterator([]*resourcepb.BulkRequest{other}))
+		require.Nil(t, rsp.Error)
+		require.Len(t, rsp.Rejected, 1)
+		require.Equal(t, "key is not in the collection", rsp.Rejected[0].Error)
+		require.NoError(t, b.SQLMock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,7 @@
+DELETE FROM {{ .Ident "resource_blob_chunk" }}
+    WHERE {{ .Ident "refs" }} <= 0
+    {{ if .Hashes }}
+        AND {{ .Ident "namespace" }} = {{ .Arg .Namespace }}
+        AND {{ .Ident "hash" }} IN ({{ .ArgList .Hashes }})
+    {{ end }}
+;
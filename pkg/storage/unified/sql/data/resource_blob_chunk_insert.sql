@@ -0,0 +1,22 @@
+INSERT INTO {{ .Ident "resource_blob_chunk" }}
+    (
+        {{ .Ident "namespace" }},
+        {{ .Ident "hash" }},
+        {{ .Ident "size" }},
+        {{ .Ident "refs" }},
+        {{ .Ident "value" }}
+    )
+    VALUES (
+        {{ .Arg .Namespace }},
+        {{ .Arg .Hash }},
+        {{ .Arg .Size }},
+        1,
+        {{ .Arg .Value }}
+    )
+{{ if eq .DialectName "mysql" }}
+    ON DUPLICATE KEY UPDATE {{ .Ident "refs" }} = {{ .Ident "refs" }} + 1
+{{ else }}
+    ON CONFLICT ({{ .Ident "namespace" }}, {{ .Ident "hash" }})
+    DO UPDATE SET {{ .Ident "refs" }} = {{ .Ident "resource_blob_chunk" }}.{{ .Ident "refs" }} + 1
+{{ end }}
+;
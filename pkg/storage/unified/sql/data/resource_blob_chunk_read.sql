@@ -0,0 +1,7 @@
+SELECT
+    {{ .Ident "hash" | .Into .Response.Hash }},
+    {{ .Ident "value" | .Into .Response.Value }}
+    FROM {{ .Ident "resource_blob_chunk" }}
+    WHERE {{ .Ident "namespace" }} = {{ .Arg .Namespace }}
+        AND {{ .Ident "hash" }} IN ({{ .ArgList .Hashes }})
+;
@@ -0,0 +1,5 @@
+UPDATE {{ .Ident "resource_blob_chunk" }}
+    SET {{ .Ident "refs" }} = {{ .Ident "refs" }} + {{ .Arg .Delta }}
+    WHERE {{ .Ident "namespace" }} = {{ .Arg .Namespace }}
+        AND {{ .Ident "hash" }} IN ({{ .ArgList .Hashes }})
+;
@@ -9,7 +9,8 @@ INSERT INTO {{ .Ident "resource_blob" }}
 
     {{ .Ident "value" }},
     {{ .Ident "hash" }},
-    {{ .Ident "content_type" }}
+    {{ .Ident "content_type" }},
+    {{ .Ident "chunks" }}
   )
   VALUES (
     {{ .Arg .Info.UID }}, 
@@ -21,6 +22,7 @@ INSERT INTO {{ .Ident "resource_blob" }}
 
     {{ .Arg .Value }},
     {{ .Arg .Info.Hash }},
-    {{ .Arg .ContentType }}
+    {{ .Arg .ContentType }},
+    {{ .Arg .Chunks }}
   )
 ;
\ No newline at end of file
@@ -0,0 +1,15 @@
+SELECT
+    {{ .Ident "uuid" | .Into .Response.UID }},
+    {{ .Ident "chunks" | .Into .Response.Chunks }}
+    FROM {{ .Ident "resource_blob" }}
+    WHERE {{ .Ident "namespace" }} = {{ .Arg .Key.Namespace }}
+        AND {{ .Ident "group" }} = {{ .Arg .Key.Group }}
+        AND {{ .Ident "resource" }} = {{ .Arg .Key.Resource }}
+        AND {{ .Ident "name" }} = {{ .Arg .Key.Name }}
+        {{ if .UID }}
+        AND {{ .Ident "uuid" }} = {{ .Arg .UID }}
+        {{ end }}
+        {{ if .CreatedBefore }}
+        AND {{ .Ident "created" }} < {{ .Arg .CreatedBefore }}
+        {{ end }}
+;
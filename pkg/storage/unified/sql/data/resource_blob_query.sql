@@ -1,7 +1,8 @@
 SELECT
   {{ .Ident "uuid" }},
   {{ .Ident "value" }},
-  {{ .Ident "content_type" }}
+  {{ .Ident "content_type" }},
+  {{ .Ident "chunks" }}
 FROM {{ .Ident "resource_blob" }}
 WHERE 1 = 1
   AND {{ .Ident "namespace" }} = {{ .Arg .Key.Namespace }}
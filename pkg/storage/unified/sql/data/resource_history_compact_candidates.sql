@@ -0,0 +1,16 @@
+SELECT
+    {{ .Ident "namespace" | .Into .Response.Namespace }},
+    {{ .Ident "name" | .Into .Response.Name }}
+    FROM {{ .Ident "resource_history" }}
+    WHERE {{ .Ident "group" }} = {{ .Arg .Group }}
+        AND {{ .Ident "resource" }} = {{ .Arg .Resource }}
+        AND (
+            {{ .Ident "namespace" }} > {{ .Arg .CursorNamespace }}
+            OR ({{ .Ident "namespace" }} = {{ .Arg .CursorNamespace }} AND {{ .Ident "name" }} > {{ .Arg .CursorName }})
+        )
+    GROUP BY {{ .Ident "namespace" }}, {{ .Ident "name" }}
+    HAVING COUNT(*) > {{ .Arg .MinVersions }}
+        AND MIN({{ .Ident "resource_version" }}) < {{ .Arg .MaxRV }}
+    ORDER BY {{ .Ident "namespace" }} ASC, {{ .Ident "name" }} ASC
+    LIMIT {{ .Arg .Limit }}
+;
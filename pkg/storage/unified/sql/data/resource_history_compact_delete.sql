@@ -0,0 +1,8 @@
+DELETE FROM {{ .Ident "resource_history" }}
+    WHERE {{ .Ident "namespace" }} = {{ .Arg .Key.Namespace }}
+        AND {{ .Ident "group" }} = {{ .Arg .Key.Group }}
+        AND {{ .Ident "resource" }} = {{ .Arg .Key.Resource }}
+        AND {{ .Ident "name" }} = {{ .Arg .Key.Name }}
+        AND {{ .Ident "resource_version" }} < {{ .Arg .MaxRV }}
+        AND {{ .Ident "guid" }} IN ({{ .ArgList .GUIDs }})
+;
@@ -0,0 +1,9 @@
+SELECT
+    {{ .Ident "value" | .Into .Response.Value }}
+    FROM {{ .Ident "resource_history" }}
+    WHERE {{ .Ident "namespace" }} = {{ .Arg .Key.Namespace }}
+        AND {{ .Ident "group" }} = {{ .Arg .Key.Group }}
+        AND {{ .Ident "resource" }} = {{ .Arg .Key.Resource }}
+        AND {{ .Ident "name" }} = {{ .Arg .Key.Name }}
+        AND {{ .Ident "guid" }} IN ({{ .ArgList .GUIDs }})
+;
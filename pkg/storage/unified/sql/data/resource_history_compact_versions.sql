@@ -0,0 +1,11 @@
+SELECT
+    {{ .Ident "guid" | .Into .Response.GUID }},
+    {{ .Ident "resource_version" | .Into .Response.ResourceVersion }},
+    {{ .Ident "action" | .Into .Response.Action }}
+    FROM {{ .Ident "resource_history" }}
+    WHERE {{ .Ident "namespace" }} = {{ .Arg .Key.Namespace }}
+        AND {{ .Ident "group" }} = {{ .Arg .Key.Group }}
+        AND {{ .Ident "resource" }} = {{ .Arg .Key.Resource }}
+        AND {{ .Ident "name" }} = {{ .Arg .Key.Name }}
+    ORDER BY {{ .Ident "resource_version" }} DESC
+;
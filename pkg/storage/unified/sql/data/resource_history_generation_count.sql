@@ -0,0 +1,6 @@
+SELECT
+    {{ "COUNT(*)" | .Into .Response.Count }}
+    FROM {{ .Ident "resource_history" }}
+    WHERE {{ .Ident "generation" }} = 0
+        AND {{ .Ident "action" }} != 3
+;
@@ -0,0 +1,10 @@
+SELECT
+    {{ .Ident "guid" | .Into .Response.GUID }},
+    {{ .Ident "value" | .Into .Response.Value }}
+    FROM {{ .Ident "resource_history" }}
+    WHERE {{ .Ident "guid" }} > {{ .Arg .CursorGUID }}
+        AND {{ .Ident "generation" }} = 0
+        AND {{ .Ident "action" }} != 3
+    ORDER BY {{ .Ident "guid" }} ASC
+    LIMIT {{ .Arg .Limit }}
+;
@@ -0,0 +1,13 @@
+UPDATE {{ .Ident "resource_history" }}
+SET {{ .Ident "generation" }} = (
+    CASE
+    {{ range $guid, $generation := .GUIDToGeneration }}
+    WHEN {{ $.Ident "guid" }} = {{ $.Arg $guid }} THEN CAST({{ $.Arg $generation }} AS {{ if eq $.DialectName "postgres" }}BIGINT{{ else }}SIGNED{{ end }})
+    {{ end }}
+    END
+)
+WHERE {{ .Ident "generation" }} = 0
+    AND {{ .Ident "guid" }} IN (
+    {{$first := true}}
+    {{ range $guid, $generation := .GUIDToGeneration }}{{if $first}}{{$first = false}}{{else}}, {{end}}{{ $.Arg $guid }}{{ end }}
+);
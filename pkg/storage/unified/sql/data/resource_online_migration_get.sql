@@ -0,0 +1,15 @@
+SELECT
+    {{ .Ident "id" | .Into .Response.ID }},
+    {{ .Ident "status" | .Into .Response.Status }},
+    {{ .Ident "cursor_guid" | .Into .Response.CursorGUID }},
+    {{ .Ident "processed" | .Into .Response.Processed }},
+    {{ .Ident "total" | .Into .Response.Total }},
+    {{ .Ident "error" | .Into .Response.Error }},
+    {{ .Ident "started" | .Into .Response.Started }},
+    {{ .Ident "updated" | .Into .Response.Updated }}
+    FROM {{ .Ident "resource_online_migration" }}
+    {{ if .ID }}
+    WHERE {{ .Ident "id" }} = {{ .Arg .ID }}
+    {{ end }}
+    ORDER BY {{ .Ident "id" }} ASC
+;
@@ -0,0 +1,22 @@
+INSERT INTO {{ .Ident "resource_online_migration" }}
+    (
+        {{ .Ident "id" }},
+        {{ .Ident "status" }},
+        {{ .Ident "cursor_guid" }},
+        {{ .Ident "processed" }},
+        {{ .Ident "total" }},
+        {{ .Ident "error" }},
+        {{ .Ident "started" }},
+        {{ .Ident "updated" }}
+    )
+    VALUES (
+        {{ .Arg .State.ID }},
+        {{ .Arg .State.Status }},
+        {{ .Arg .State.CursorGUID }},
+        {{ .Arg .State.Processed }},
+        {{ .Arg .State.Total }},
+        {{ .Arg .State.Error }},
+        {{ .Arg .State.Started }},
+        {{ .Arg .State.Updated }}
+    )
+;
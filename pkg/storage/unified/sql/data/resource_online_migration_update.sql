@@ -0,0 +1,11 @@
+UPDATE {{ .Ident "resource_online_migration" }}
+    SET
+        {{ .Ident "status" }} = {{ .Arg .State.Status }},
+        {{ .Ident "cursor_guid" }} = {{ .Arg .State.CursorGUID }},
+        {{ .Ident "processed" }} = {{ .Arg .State.Processed }},
+        {{ .Ident "total" }} = {{ .Arg .State.Total }},
+        {{ .Ident "error" }} = {{ .Arg .State.Error }},
+        {{ .Ident "updated" }} = {{ .Arg .State.Updated }}
+    WHERE {{ .Ident "id" }} = {{ .Arg .State.ID }}
+        AND {{ .Ident "cursor_guid" }} = {{ .Arg .PreviousGUID }}
+;
@@ -0,0 +1,12 @@
+SELECT DISTINCT
+  {{ .Ident "namespace" }},
+  {{ .Ident "group"     }},
+  {{ .Ident "resource"  }}
+FROM {{ .Ident "resource_history" }}
+WHERE {{ .Ident "action" }} = 3
+  AND {{ .Ident "resource_version" }} < {{ .Arg .DeletedBeforeRV }}
+ORDER BY
+  {{ .Ident "namespace" }},
+  {{ .Ident "group"     }},
+  {{ .Ident "resource"  }}
+;
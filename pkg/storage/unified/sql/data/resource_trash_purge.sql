@@ -0,0 +1,45 @@
+DELETE FROM {{ .Ident "resource_history" }}
+WHERE {{ .Ident "guid" }} IN (
+  SELECT {{ .Ident "guid" }}
+  FROM (
+  SELECT h.{{ .Ident "guid" }}
+  FROM {{ .Ident "resource_history" }} h
+  INNER JOIN (
+    SELECT {{ .Ident "namespace" }}, {{ .Ident "group" }}, {{ .Ident "resource" }}, {{ .Ident "name" }}, MAX({{ .Ident "resource_version" }}) AS {{ .Ident "max_rv" }}
+    FROM {{ .Ident "resource_history" }}
+    WHERE 1 = 1
+      {{ if .Key.Namespace }}
+      AND {{ .Ident "namespace" }} = {{ .Arg .Key.Namespace }}
+      {{ end }}
+      {{ if .Key.Group }}
+      AND {{ .Ident "group" }}     = {{ .Arg .Key.Group }}
+      {{ end }}
+      {{ if .Key.Resource }}
+      AND {{ .Ident "resource" }}  = {{ .Arg .Key.Resource }}
+      {{ end }}
+      {{ if .Key.Name }}
+      AND {{ .Ident "name" }}      = {{ .Arg .Key.Name }}
+      {{ end }}
+    GROUP BY {{ .Ident "namespace" }}, {{ .Ident "group" }}, {{ .Ident "resource" }}, {{ .Ident "name" }}
+  ) AS {{ .Ident "latest" }}
+     ON h.{{ .Ident "namespace" }} = {{ .Ident "latest" }}.{{ .Ident "namespace" }}
+    AND h.{{ .Ident "group" }}     = {{ .Ident "latest" }}.{{ .Ident "group" }}
+    AND h.{{ .Ident "resource" }}  = {{ .Ident "latest" }}.{{ .Ident "resource" }}
+    AND h.{{ .Ident "name" }}      = {{ .Ident "latest" }}.{{ .Ident "name" }}
+  INNER JOIN {{ .Ident "resource_history" }} d
+     ON d.{{ .Ident "namespace" }} = {{ .Ident "latest" }}.{{ .Ident "namespace" }}
+    AND d.{{ .Ident "group" }}     = {{ .Ident "latest" }}.{{ .Ident "group" }}
+    AND d.{{ .Ident "resource" }}  = {{ .Ident "latest" }}.{{ .Ident "resource" }}
+    AND d.{{ .Ident "name" }}      = {{ .Ident "latest" }}.{{ .Ident "name" }}
+    AND d.{{ .Ident "resource_version" }} = {{ .Ident "latest" }}.{{ .Ident "max_rv" }}
+  WHERE d.{{ .Ident "action" }} = 3
+    AND d.{{ .Ident "resource_version" }} < {{ .Arg .DeletedBeforeRV }}
+    AND NOT EXISTS (
+      SELECT 1 FROM {{ .Ident "resource" }} r
+      WHERE r.{{ .Ident "namespace" }} = h.{{ .Ident "namespace" }}
+        AND r.{{ .Ident "group" }}     = h.{{ .Ident "group" }}
+        AND r.{{ .Ident "resource" }}  = h.{{ .Ident "resource" }}
+        AND r.{{ .Ident "name" }}      = h.{{ .Ident "name" }}
+    )
+  ) AS {{ .Ident "purged" }}
+);
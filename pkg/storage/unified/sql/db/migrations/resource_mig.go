@@ -167,5 +167,46 @@ func initResourceTables(mg *migrator.Migrator) string {
 		Name: "IDX_resource_history_namespace_group_resource_name_generation",
 	}))
 
+	// Progress of the online migrations, they update the existing rows in batches after startup
+	online_migration_table := migrator.Table{
+		Name: "resource_online_migration",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_NVarchar, Length: 190, Nullable: false, IsPrimaryKey: true},
+			{Name: "status", Type: migrator.DB_NVarchar, Length: 32, Nullable: false},
+			// The last migrated row
+			{Name: "cursor_guid", Type: migrator.DB_NVarchar, Length: 36, Nullable: false},
+			{Name: "processed", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "total", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "error", Type: migrator.DB_Text, Nullable: false},
+			{Name: "started", Type: migrator.DB_BigInt, Nullable: false}, // unix milliseconds
+			{Name: "updated", Type: migrator.DB_BigInt, Nullable: false},
+		},
+	}
+	mg.AddMigration("create table resource_online_migration", migrator.NewAddTableMigration(online_migration_table))
+
+	// The blobs are split in content addressed chunks, shared by all the blobs with the same content
+	blob_chunk_table := migrator.Table{
+		Name: "resource_blob_chunk",
+		Columns: []*migrator.Column{
+			// The chunks are only shared within a namespace
+			{Name: "namespace", Type: migrator.DB_NVarchar, Length: 63, Nullable: false, IsPrimaryKey: true},
+			{Name: "hash", Type: migrator.DB_NVarchar, Length: 64, Nullable: false, IsPrimaryKey: true}, // sha256
+			{Name: "size", Type: migrator.DB_BigInt, Nullable: false},
+			// The number of blobs referencing the chunk
+			{Name: "refs", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "value", Type: migrator.DB_LongBlob, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"refs"}, Type: migrator.IndexType},
+		},
+	}
+	mg.AddMigration("create table resource_blob_chunk", migrator.NewAddTableMigration(blob_chunk_table))
+	mg.AddMigration("create table resource_blob_chunk, index: 0", migrator.NewAddIndexMigration(blob_chunk_table, blob_chunk_table.Indices[0]))
+
+	// The hashes of the chunks, when set the value column is empty
+	mg.AddMigration("Add column chunks in resource_blob", migrator.NewAddColumnMigration(migrator.Table{Name: "resource_blob"}, &migrator.Column{
+		Name: "chunks", Type: migrator.DB_Text, Nullable: true,
+	}))
+
 	return marker
 }
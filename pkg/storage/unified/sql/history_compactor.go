@@ -0,0 +1,331 @@
+package sql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/grafana-app-sdk/logging"
+
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/db"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/dbutil"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/sqltemplate"
+)
+
+const (
+	defaultCompactionInterval = time.Hour
+
+	// The versions written recently are never removed, so the watchers polling the history
+	// and the batches of the resource version manager still in flight always see them
+	compactionMinAge = time.Hour
+
+	// The number of names loaded per query, and the most versions removed per statement
+	compactionBatchSize = 100
+	compactionDeleteMax = 500
+)
+
+var (
+	historyCompactedVersions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "unified_storage_history_compacted_versions_total",
+		Help:      "Number of versions removed from the history by the retention policies",
+		Namespace: "grafana",
+	}, []string{"group", "resource"})
+
+	historyCompactionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:                        "unified_storage_history_compaction_duration_seconds",
+		Help:                        "Duration of the history compaction of a resource",
+		Namespace:                   "grafana",
+		NativeHistogramBucketFactor: 1.1,
+	}, []string{"group", "resource", "status"})
+)
+
+// historyCompactor removes the versions that are not kept by the retention policies.
+// The deletes only touch old versions, so they do not conflict with the writes.
+// A watch started from a removed resource version misses the removed events.
+type historyCompactor struct {
+	db       db.DB
+	dialect  sqltemplate.Dialect
+	log      logging.Logger
+	tracer   trace.Tracer
+	policies []resource.HistoryRetention
+	interval time.Duration
+
+	// for testing
+	now func() time.Time
+}
+
+func newHistoryCompactor(dbConn db.DB, dialect sqltemplate.Dialect, tracer trace.Tracer, policies []resource.HistoryRetention, interval time.Duration) *historyCompactor {
+	if interval <= 0 {
+		interval = defaultCompactionInterval
+	}
+	return &historyCompactor{
+		db:       dbConn,
+		dialect:  dialect,
+		log:      logging.DefaultLogger.With("logger", "sql-history-compactor"),
+		tracer:   tracer,
+		policies: policies,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// policy returns the retention of the resource, if any
+func (c *historyCompactor) policy(group, res string) (resource.HistoryRetention, bool) {
+	if c == nil {
+		return resource.HistoryRetention{}, false
+	}
+	for _, p := range c.policies {
+		if p.Group == group && p.Resource == res && !p.IsZero() {
+			return p, true
+		}
+	}
+	return resource.HistoryRetention{}, false
+}
+
+// run compacts the history of every resource with a policy on each interval, until the context is cancelled
+func (c *historyCompactor) run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		for _, p := range c.policies {
+			if p.IsZero() {
+				continue
+			}
+			removed, err := c.compact(ctx, p)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				c.log.Error("failed to compact history", "group", p.Group, "resource", p.Resource, "error", err)
+				continue
+			}
+			if removed > 0 {
+				c.log.Info("compacted history", "group", p.Group, "resource", p.Resource, "versions", removed)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// compact applies the policy to every name of the resource, and returns the number of removed versions
+func (c *historyCompactor) compact(ctx context.Context, policy resource.HistoryRetention) (removed int64, err error) {
+	ctx, span := c.tracer.Start(ctx, tracePrefix+"CompactHistory")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("group", policy.Group),
+		attribute.String("resource", policy.Resource),
+	)
+
+	timer := prometheus.NewTimer(prometheus.ObserverFunc(func(v float64) {
+		status := "success"
+		if err != nil {
+			status = "error"
+		}
+		historyCompactionDuration.WithLabelValues(policy.Group, policy.Resource, status).Observe(v)
+	}))
+	defer timer.ObserveDuration()
+
+	now := c.now()
+	maxRV := now.Add(-compactionMinAge).UnixMicro()
+	req := &sqlHistoryCompactCandidatesRequest{
+		SQLTemplate: sqltemplate.New(c.dialect),
+		Group:       policy.Group,
+		Resource:    policy.Resource,
+		MinVersions: int64(max(policy.Versions, 1)),
+		MaxRV:       maxRV,
+		Limit:       compactionBatchSize,
+		Response:    &compactCandidate{},
+	}
+	for {
+		candidates, err := dbutil.Query(ctx, c.db, sqlResourceHistoryCompactCandidates, req)
+		if err != nil {
+			return removed, fmt.Errorf("list names: %w", err)
+		}
+		for _, candidate := range candidates {
+			if ctx.Err() != nil {
+				return removed, ctx.Err()
+			}
+			n, err := c.compactName(ctx, policy, &resourcepb.ResourceKey{
+				Namespace: candidate.Namespace,
+				Group:     policy.Group,
+				Resource:  policy.Resource,
+				Name:      candidate.Name,
+			}, now)
+			if err != nil {
+				return removed, err
+			}
+			removed += n
+		}
+		if len(candidates) < compactionBatchSize {
+			return removed, nil
+		}
+		last := candidates[len(candidates)-1]
+		req.CursorNamespace = last.Namespace
+		req.CursorName = last.Name
+	}
+}
+
+// compactName removes the versions of one object that are not kept by the policy
+func (c *historyCompactor) compactName(ctx context.Context, policy resource.HistoryRetention, key *resourcepb.ResourceKey, now time.Time) (int64, error) {
+	var removed int64
+	err := c.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
+		versions, err := dbutil.Query(ctx, tx, sqlResourceHistoryCompactVersions, &sqlHistoryCompactVersionsRequest{
+			SQLTemplate: sqltemplate.New(c.dialect),
+			Key:         key,
+			Response:    &historyVersion{},
+		})
+		if err != nil {
+			return fmt.Errorf("list versions: %w", err)
+		}
+
+		guids := compactVersions(policy, versions, now)
+		if len(guids) == 0 {
+			return nil
+		}
+		if err = c.releaseBlobs(ctx, tx, key, versions, guids, now); err != nil {
+			return err
+		}
+		for len(guids) > 0 {
+			batch := guids[:min(len(guids), compactionDeleteMax)]
+			guids = guids[len(batch):]
+
+			res, err := dbutil.Exec(ctx, tx, sqlResourceHistoryCompactDelete, &sqlHistoryCompactDeleteRequest{
+				SQLTemplate: sqltemplate.New(c.dialect),
+				Key:         key,
+				MaxRV:       now.Add(-compactionMinAge).UnixMicro(),
+				GUIDs:       batch,
+			})
+			if err != nil {
+				return fmt.Errorf("remove versions: %w", err)
+			}
+			rows, err := res.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("remove versions: %w", err)
+			}
+			removed += rows
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	historyCompactedVersions.WithLabelValues(key.Group, key.Resource).Add(float64(removed))
+	return removed, nil
+}
+
+// releaseBlobs removes the blobs only referenced by the removed versions.
+// The recent blobs are kept, they may belong to a version being written.
+func (c *historyCompactor) releaseBlobs(ctx context.Context, tx db.Tx, key *resourcepb.ResourceKey, versions []*historyVersion, removed []string, now time.Time) error {
+	blobs, err := dbutil.Query(ctx, tx, sqlResourceBlobList, &sqlResourceBlobListRequest{
+		SQLTemplate:   sqltemplate.New(c.dialect),
+		Key:           key,
+		CreatedBefore: now.Add(-compactionMinAge),
+		Response:      &blobRef{},
+	})
+	if err != nil {
+		return fmt.Errorf("list blobs: %w", err)
+	}
+	if len(blobs) == 0 {
+		return nil
+	}
+
+	// Only the values of the kept versions are read, to find the blobs they reference.
+	var keptGUIDs []string
+	for _, v := range versions {
+		if !slices.Contains(removed, v.GUID) {
+			keptGUIDs = append(keptGUIDs, v.GUID)
+		}
+	}
+	kept := make(map[string]bool, len(keptGUIDs))
+	for len(keptGUIDs) > 0 {
+		batch := keptGUIDs[:min(len(keptGUIDs), compactionDeleteMax)]
+		keptGUIDs = keptGUIDs[len(batch):]
+
+		values, err := dbutil.Query(ctx, tx, sqlResourceHistoryCompactValues, &sqlHistoryCompactValuesRequest{
+			SQLTemplate: sqltemplate.New(c.dialect),
+			Key:         key,
+			GUIDs:       batch,
+			Response:    &historyValue{},
+		})
+		if err != nil {
+			return fmt.Errorf("read kept versions: %w", err)
+		}
+		for _, v := range values {
+			kept[readBlobUID(v.Value)] = true
+		}
+	}
+	for _, blob := range blobs {
+		if kept[blob.UID] {
+			continue
+		}
+		if err = deleteBlob(ctx, tx, c.dialect, key, blob); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readBlobUID returns the blob referenced by the value, if any
+func readBlobUID(value []byte) string {
+	obj := struct {
+		Metadata struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	}{}
+	if err := json.Unmarshal(value, &obj); err != nil {
+		return ""
+	}
+	info := utils.ParseBlobInfo(obj.Metadata.Annotations[utils.AnnoKeyBlob])
+	if info == nil {
+		return ""
+	}
+	return info.UID
+}
+
+// compactVersions returns the GUIDs of the versions the policy removes.
+// The versions are sorted from the newest. The latest version, the last version before a deletion
+// (needed to restore from the trash) and the versions written in the last compactionMinAge are always kept.
+// The resource versions are allocated from the database clock in microseconds, so they give the write time.
+func compactVersions(policy resource.HistoryRetention, versions []*historyVersion, now time.Time) []string {
+	var remove []string
+	days := make(map[string]bool)
+	keptLive := false
+	for i, v := range versions {
+		written := time.UnixMicro(v.ResourceVersion).UTC()
+		age := now.Sub(written)
+
+		keep := i == 0 || i < policy.Versions || age < compactionMinAge || age < policy.All
+		if !keptLive && v.Action != int(resourcepb.WatchEvent_DELETED) {
+			keptLive = true
+			keep = true
+		}
+
+		// the newest version of each day
+		day := written.Format(time.DateOnly)
+		if age < policy.Daily && !days[day] {
+			keep = true
+		}
+		days[day] = true
+
+		if !keep {
+			remove = append(remove, v.GUID)
+		}
+	}
+	return remove
+}
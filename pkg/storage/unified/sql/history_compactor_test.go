@@ -0,0 +1,145 @@
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/sqltemplate"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/test"
+	"github.com/grafana/grafana/pkg/util/testutil"
+)
+
+func TestCompactVersions(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	version := func(guid string, written time.Time, action resourcepb.WatchEvent_Type) *historyVersion {
+		return &historyVersion{GUID: guid, ResourceVersion: written.UnixMicro(), Action: int(action)}
+	}
+	updated := func(guid string, age time.Duration) *historyVersion {
+		return version(guid, now.Add(-age), resourcepb.WatchEvent_MODIFIED)
+	}
+
+	tests := []struct {
+		name     string
+		policy   resource.HistoryRetention
+		versions []*historyVersion
+		removed  []string
+	}{
+		{
+			name:   "keep the latest versions",
+			policy: resource.HistoryRetention{Versions: 2},
+			versions: []*historyVersion{
+				updated("a", 2*time.Hour),
+				updated("b", 3*time.Hour),
+				updated("c", 4*time.Hour),
+				updated("d", 5*time.Hour),
+			},
+			removed: []string{"c", "d"},
+		},
+		{
+			name:   "keep every version in the period",
+			policy: resource.HistoryRetention{All: 3 * time.Hour},
+			versions: []*historyVersion{
+				updated("a", 2*time.Hour),
+				updated("b", 150*time.Minute),
+				updated("c", 4*time.Hour),
+				updated("d", 5*time.Hour),
+			},
+			removed: []string{"c", "d"},
+		},
+		{
+			name:   "keep the last version of each day",
+			policy: resource.HistoryRetention{Daily: 72 * time.Hour},
+			versions: []*historyVersion{
+				version("a", time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC), resourcepb.WatchEvent_MODIFIED),
+				version("b", time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC), resourcepb.WatchEvent_MODIFIED),
+				version("c", time.Date(2025, 1, 9, 20, 0, 0, 0, time.UTC), resourcepb.WatchEvent_MODIFIED),
+				version("d", time.Date(2025, 1, 9, 10, 0, 0, 0, time.UTC), resourcepb.WatchEvent_MODIFIED),
+				version("e", time.Date(2025, 1, 5, 10, 0, 0, 0, time.UTC), resourcepb.WatchEvent_ADDED),
+			},
+			removed: []string{"b", "d", "e"},
+		},
+		{
+			name:   "keep the version before a deletion",
+			policy: resource.HistoryRetention{Versions: 1},
+			versions: []*historyVersion{
+				version("a", now.Add(-2*time.Hour), resourcepb.WatchEvent_DELETED),
+				updated("b", 3*time.Hour),
+				updated("c", 4*time.Hour),
+			},
+			removed: []string{"c"},
+		},
+		{
+			name:   "keep the recent versions",
+			policy: resource.HistoryRetention{Versions: 1},
+			versions: []*historyVersion{
+				updated("a", time.Minute),
+				updated("b", 2*time.Minute),
+				updated("c", 2*time.Hour),
+			},
+			removed: []string{"c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.removed, compactVersions(tt.policy, tt.versions, now))
+		})
+	}
+}
+
+func TestHistoryCompactor_compactName(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.NewDefaultTestContext(t)
+	dbp := test.NewDBProviderMatchWords(t)
+	policy := resource.HistoryRetention{Group: "gr", Resource: "rs", Versions: 1}
+	c := newHistoryCompactor(dbp.DB, sqltemplate.MySQL, noop.NewTracerProvider().Tracer("test"), []resource.HistoryRetention{policy}, time.Hour)
+	now := time.Now()
+
+	withBlob := func(uid string) []byte {
+		return []byte(`{"metadata":{"annotations":{"grafana.app/blob":"` + uid + `; size=10"}}}`)
+	}
+
+	dbp.SQLMock.ExpectBegin()
+	dbp.SQLMock.ExpectQuery("select guid resource_version action from resource_history").
+		WillReturnRows(dbp.SQLMock.NewRows([]string{"guid", "resource_version", "action"}).
+			AddRow("a", now.Add(-2*time.Hour).UnixMicro(), 2).
+			AddRow("b", now.Add(-3*time.Hour).UnixMicro(), 2).
+			AddRow("c", now.Add(-4*time.Hour).UnixMicro(), 1))
+	dbp.SQLMock.ExpectQuery("select uuid chunks from resource_blob").
+		WillReturnRows(dbp.SQLMock.NewRows([]string{"uuid", "chunks"}).
+			AddRow("blob-a", "h1,h2").
+			AddRow("blob-b", "h1,h3").
+			AddRow("blob-c", nil))
+	// Only the value of the kept version is read
+	dbp.SQLMock.ExpectQuery("select value from resource_history").WithArgs("ns", "gr", "rs", "nm", "a").
+		WillReturnRows(dbp.SQLMock.NewRows([]string{"value"}).AddRow(withBlob("blob-a")))
+	dbp.SQLMock.ExpectExec("delete from resource_blob").WithArgs("ns", "gr", "rs", "nm", "blob-b").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	dbp.SQLMock.ExpectExec("update resource_blob_chunk set refs").WithArgs(int64(-1), "ns", "h1", "h3").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	dbp.SQLMock.ExpectExec("delete from resource_blob_chunk").WillReturnResult(sqlmock.NewResult(0, 1))
+	dbp.SQLMock.ExpectExec("delete from resource_blob").WithArgs("ns", "gr", "rs", "nm", "blob-c").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	dbp.SQLMock.ExpectExec("delete from resource_history").WillReturnResult(sqlmock.NewResult(0, 2))
+	dbp.SQLMock.ExpectCommit()
+
+	removed, err := c.compactName(ctx, policy, resKey, now)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), removed)
+	require.NoError(t, dbp.SQLMock.ExpectationsWereMet())
+
+	got, ok := c.policy("gr", "rs")
+	require.True(t, ok)
+	require.Equal(t, policy, got)
+	_, ok = c.policy("gr", "other")
+	require.False(t, ok)
+}
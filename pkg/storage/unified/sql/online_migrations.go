@@ -0,0 +1,427 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/grafana-app-sdk/logging"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/db"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/dbutil"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/sqltemplate"
+)
+
+// The schema migrations in db/migrations run at startup, so they must stay cheap on large tables.
+// Changes that need to touch the existing rows are done by the online migrations instead:
+// they run in the background in small batches, and save their cursor after each batch so they
+// continue where they stopped after a restart. Until a migration is completed, the readers
+// must handle the rows that are not migrated yet.
+
+const (
+	OnlineMigrationPending   = "pending"
+	OnlineMigrationRunning   = "running"
+	OnlineMigrationCompleted = "completed"
+	OnlineMigrationFailed    = "failed"
+)
+
+const (
+	defaultOnlineMigrationBatchSize = 500
+	defaultOnlineMigrationInterval  = time.Second
+)
+
+var errOnlineMigrationConflict = errors.New("the migration was moved by another replica")
+
+var (
+	onlineMigrationProcessed = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name:      "unified_storage_online_migration_processed_rows",
+		Help:      "Number of rows processed by the online migration",
+		Namespace: "grafana",
+	}, []string{"migration"})
+
+	onlineMigrationTotal = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name:      "unified_storage_online_migration_total_rows",
+		Help:      "Number of rows to migrate when the online migration started",
+		Namespace: "grafana",
+	}, []string{"migration"})
+
+	onlineMigrationCompleted = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name:      "unified_storage_online_migration_completed",
+		Help:      "1 when the online migration is completed",
+		Namespace: "grafana",
+	}, []string{"migration"})
+
+	onlineMigrationBatchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:                        "unified_storage_online_migration_batch_duration_seconds",
+		Help:                        "Duration of the online migration batches",
+		Namespace:                   "grafana",
+		NativeHistogramBucketFactor: 1.1,
+	}, []string{"migration", "status"})
+)
+
+// OnlineMigrationState is the progress of an online migration
+type OnlineMigrationState struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+
+	// The guid of the last migrated row
+	CursorGUID string `json:"-"`
+
+	Processed int64  `json:"processed"`
+	Total     int64  `json:"total"`
+	Error     string `json:"error,omitempty"`
+
+	// unix milliseconds
+	Started int64 `json:"started,omitempty"`
+	Updated int64 `json:"updated,omitempty"`
+}
+
+type onlineMigration struct {
+	id string
+
+	// count returns the number of rows to migrate
+	count func(ctx context.Context, x db.ContextExecer, d sqltemplate.Dialect) (int64, error)
+
+	// migrate updates the rows after the cursor, and returns the cursor of the last row and the number of rows read.
+	// The rows are paged by their primary key, so every batch is a range scan.
+	// The migration is completed when it reads less rows than the limit.
+	migrate func(ctx context.Context, tx db.Tx, d sqltemplate.Dialect, cursor string, limit int64) (string, int64, error)
+}
+
+// The registered online migrations, they run in this order
+var onlineMigrations = []onlineMigration{
+	historyGenerationBackfill,
+}
+
+// The history written before the generation column was added has generation 0.
+// The pruner only partitions the history by generation once this completes.
+var historyGenerationBackfill = onlineMigration{
+	id: "backfill resource_history generation",
+	count: func(ctx context.Context, x db.ContextExecer, d sqltemplate.Dialect) (int64, error) {
+		res, err := dbutil.QueryRow(ctx, x, sqlResourceHistoryGenerationCount, &sqlResourceHistoryGenerationCountRequest{
+			SQLTemplate: sqltemplate.New(d),
+			Response:    &countResponse{},
+		})
+		if err != nil {
+			return 0, err
+		}
+		return res.Count, nil
+	},
+	migrate: func(ctx context.Context, tx db.Tx, d sqltemplate.Dialect, cursor string, limit int64) (string, int64, error) {
+		rows, err := dbutil.Query(ctx, tx, sqlResourceHistoryGenerationRead, &sqlResourceHistoryGenerationReadRequest{
+			SQLTemplate: sqltemplate.New(d),
+			CursorGUID:  cursor,
+			Limit:       limit,
+			Response:    &historyGenerationRow{},
+		})
+		if err != nil {
+			return cursor, 0, fmt.Errorf("read history: %w", err)
+		}
+		if len(rows) == 0 {
+			return cursor, 0, nil
+		}
+
+		guidToGeneration := make(map[string]int64, len(rows))
+		for _, row := range rows {
+			if generation := readGeneration(row.Value); generation > 0 {
+				guidToGeneration[row.GUID] = generation
+			}
+		}
+		if len(guidToGeneration) > 0 {
+			if _, err = dbutil.Exec(ctx, tx, sqlResourceHistoryGenerationUpdate, &sqlResourceHistoryGenerationUpdateRequest{
+				SQLTemplate:      sqltemplate.New(d),
+				GUIDToGeneration: guidToGeneration,
+			}); err != nil {
+				return cursor, 0, fmt.Errorf("update history generation: %w", err)
+			}
+		}
+
+		return rows[len(rows)-1].GUID, int64(len(rows)), nil
+	},
+}
+
+// readGeneration returns the metadata.generation of the stored object, or 0 when it is not set
+func readGeneration(value []byte) int64 {
+	obj := struct {
+		Metadata struct {
+			Generation int64 `json:"generation"`
+		} `json:"metadata"`
+	}{}
+	if err := json.Unmarshal(value, &obj); err != nil {
+		return 0
+	}
+	return obj.Metadata.Generation
+}
+
+type onlineMigrator struct {
+	db         db.DB
+	dialect    sqltemplate.Dialect
+	log        logging.Logger
+	tracer     trace.Tracer
+	batchSize  int64
+	interval   time.Duration
+	migrations []onlineMigration
+
+	mu        sync.RWMutex
+	completed map[string]bool
+}
+
+func newOnlineMigrator(dbConn db.DB, dialect sqltemplate.Dialect, tracer trace.Tracer, batchSize int64, interval time.Duration) *onlineMigrator {
+	if batchSize <= 0 {
+		batchSize = defaultOnlineMigrationBatchSize
+	}
+	if interval <= 0 {
+		interval = defaultOnlineMigrationInterval
+	}
+	return &onlineMigrator{
+		db:         dbConn,
+		dialect:    dialect,
+		log:        logging.DefaultLogger.With("logger", "sql-online-migrations"),
+		tracer:     tracer,
+		batchSize:  batchSize,
+		interval:   interval,
+		migrations: onlineMigrations,
+		completed:  make(map[string]bool),
+	}
+}
+
+// run executes the migrations one after the other, until they complete or the context is cancelled.
+// A failed migration stops the following ones, it continues from its cursor on the next start.
+func (m *onlineMigrator) run(ctx context.Context) {
+	for _, migration := range m.migrations {
+		if err := m.runMigration(ctx, migration); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			m.log.Error("online migration failed", "migration", migration.id, "error", err)
+			m.setFailed(ctx, migration.id, err)
+			return
+		}
+	}
+}
+
+func (m *onlineMigrator) runMigration(ctx context.Context, migration onlineMigration) error {
+	state, err := m.start(ctx, migration)
+	if err != nil {
+		return err
+	}
+
+	for state.Status != OnlineMigrationCompleted {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.interval):
+		}
+
+		state, err = m.step(ctx, migration, state)
+		if errors.Is(err, errOnlineMigrationConflict) {
+			// another replica runs the same migration, continue from its cursor
+			state, err = m.get(ctx, migration.id)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	m.log.Info("online migration completed", "migration", migration.id, "rows", state.Processed)
+	m.setCompleted(migration.id)
+	return nil
+}
+
+// start loads the saved state of the migration, or saves a new one
+func (m *onlineMigrator) start(ctx context.Context, migration onlineMigration) (*OnlineMigrationState, error) {
+	state, err := m.get(ctx, migration.id)
+	if err == nil {
+		if state.Status == OnlineMigrationCompleted {
+			m.setCompleted(migration.id)
+		}
+		return state, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	total, err := migration.count(ctx, m.db, m.dialect)
+	if err != nil {
+		return nil, fmt.Errorf("count rows: %w", err)
+	}
+	now := time.Now().UnixMilli()
+	state = &OnlineMigrationState{
+		ID:      migration.id,
+		Status:  OnlineMigrationRunning,
+		Total:   total,
+		Started: now,
+		Updated: now,
+	}
+	if _, err = dbutil.Exec(ctx, m.db, sqlOnlineMigrationInsert, &sqlOnlineMigrationWriteRequest{
+		SQLTemplate: sqltemplate.New(m.dialect),
+		State:       state,
+	}); err != nil {
+		if IsRowAlreadyExistsError(err) {
+			// started by another replica
+			return m.get(ctx, migration.id)
+		}
+		return nil, fmt.Errorf("save migration: %w", err)
+	}
+
+	m.log.Info("online migration started", "migration", migration.id, "rows", total)
+	onlineMigrationTotal.WithLabelValues(migration.id).Set(float64(total))
+	return state, nil
+}
+
+// step migrates a batch of rows, and moves the cursor in the same transaction
+func (m *onlineMigrator) step(ctx context.Context, migration onlineMigration, state *OnlineMigrationState) (*OnlineMigrationState, error) {
+	ctx, span := m.tracer.Start(ctx, tracePrefix+"OnlineMigrationStep")
+	defer span.End()
+	span.SetAttributes(attribute.String("migration", migration.id))
+
+	start := time.Now()
+	next := *state
+	err := m.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
+		cursor, n, err := migration.migrate(ctx, tx, m.dialect, state.CursorGUID, m.batchSize)
+		if err != nil {
+			return err
+		}
+		next.CursorGUID = cursor
+		next.Processed += n
+		next.Status = OnlineMigrationRunning
+		next.Error = ""
+		if n < m.batchSize {
+			next.Status = OnlineMigrationCompleted
+		}
+		next.Updated = time.Now().UnixMilli()
+
+		res, err := dbutil.Exec(ctx, tx, sqlOnlineMigrationUpdate, &sqlOnlineMigrationWriteRequest{
+			SQLTemplate:  sqltemplate.New(m.dialect),
+			State:        &next,
+			PreviousGUID: state.CursorGUID,
+		})
+		if err != nil {
+			return fmt.Errorf("save migration: %w", err)
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("save migration: %w", err)
+		}
+		if rows == 0 {
+			return errOnlineMigrationConflict
+		}
+		return nil
+	})
+
+	status := "success"
+	if err != nil {
+		status = "failure"
+	}
+	onlineMigrationBatchDuration.WithLabelValues(migration.id, status).Observe(time.Since(start).Seconds())
+	if err != nil {
+		return nil, err
+	}
+	onlineMigrationProcessed.WithLabelValues(migration.id).Set(float64(next.Processed))
+	return &next, nil
+}
+
+func (m *onlineMigrator) get(ctx context.Context, id string) (*OnlineMigrationState, error) {
+	state, err := dbutil.QueryRow(ctx, m.db, sqlOnlineMigrationGet, &sqlOnlineMigrationGetRequest{
+		SQLTemplate: sqltemplate.New(m.dialect),
+		ID:          id,
+		Response:    &OnlineMigrationState{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	onlineMigrationTotal.WithLabelValues(id).Set(float64(state.Total))
+	onlineMigrationProcessed.WithLabelValues(id).Set(float64(state.Processed))
+	return state, nil
+}
+
+// setFailed records the error, the cursor is kept so the migration continues on the next start
+func (m *onlineMigrator) setFailed(ctx context.Context, id string, cause error) {
+	state, err := m.get(ctx, id)
+	if err != nil {
+		return
+	}
+	next := *state
+	next.Status = OnlineMigrationFailed
+	next.Error = cause.Error()
+	next.Updated = time.Now().UnixMilli()
+	if _, err = dbutil.Exec(ctx, m.db, sqlOnlineMigrationUpdate, &sqlOnlineMigrationWriteRequest{
+		SQLTemplate:  sqltemplate.New(m.dialect),
+		State:        &next,
+		PreviousGUID: state.CursorGUID,
+	}); err != nil {
+		m.log.Warn("failed to save the online migration error", "migration", id, "error", err)
+	}
+}
+
+func (m *onlineMigrator) setCompleted(id string) {
+	m.mu.Lock()
+	m.completed[id] = true
+	m.mu.Unlock()
+	onlineMigrationCompleted.WithLabelValues(id).Set(1)
+}
+
+// Completed checks if the migration has finished, so the readers can rely on the migrated rows
+func (m *onlineMigrator) Completed(id string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.completed[id]
+}
+
+// ListOnlineMigrations implements DiagnosticsServer
+func (b *backend) ListOnlineMigrations(ctx context.Context, _ *resourcepb.ListOnlineMigrationsRequest) (*resourcepb.ListOnlineMigrationsResponse, error) {
+	states, err := listOnlineMigrations(ctx, b.db, b.dialect)
+	if err != nil {
+		return &resourcepb.ListOnlineMigrationsResponse{Error: resource.AsErrorResult(err)}, nil
+	}
+	rsp := &resourcepb.ListOnlineMigrationsResponse{}
+	for _, state := range states {
+		rsp.Migrations = append(rsp.Migrations, &resourcepb.OnlineMigration{
+			Id:        state.ID,
+			Status:    state.Status,
+			Processed: state.Processed,
+			Total:     state.Total,
+			Error:     state.Error,
+			Started:   state.Started,
+			Updated:   state.Updated,
+		})
+	}
+	return rsp, nil
+}
+
+// listOnlineMigrations returns the state of the registered migrations.
+// The migrations that did not start yet are pending.
+func listOnlineMigrations(ctx context.Context, x db.ContextExecer, dialect sqltemplate.Dialect) ([]OnlineMigrationState, error) {
+	saved, err := dbutil.Query(ctx, x, sqlOnlineMigrationGet, &sqlOnlineMigrationGetRequest{
+		SQLTemplate: sqltemplate.New(dialect),
+		Response:    &OnlineMigrationState{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*OnlineMigrationState, len(saved))
+	for _, state := range saved {
+		byID[state.ID] = state
+	}
+
+	res := make([]OnlineMigrationState, 0, len(onlineMigrations))
+	for _, migration := range onlineMigrations {
+		if state, ok := byID[migration.id]; ok {
+			res = append(res, *state)
+			continue
+		}
+		res = append(res, OnlineMigrationState{ID: migration.id, Status: OnlineMigrationPending})
+	}
+	return res, nil
+}
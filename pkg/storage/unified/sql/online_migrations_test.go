@@ -0,0 +1,157 @@
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/grafana/grafana/pkg/storage/unified/sql/sqltemplate"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/test"
+	"github.com/grafana/grafana/pkg/util/testutil"
+)
+
+func TestReadGeneration(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, int64(3), readGeneration([]byte(`{"metadata":{"name":"a","generation":3}}`)))
+	require.Equal(t, int64(0), readGeneration([]byte(`{"metadata":{"name":"a"}}`)))
+	require.Equal(t, int64(0), readGeneration([]byte(`not json`)))
+	require.Equal(t, int64(0), readGeneration(nil))
+}
+
+func setupOnlineMigratorTest(t *testing.T, batchSize int64) (*onlineMigrator, test.TestDBProvider, context.Context) {
+	t.Helper()
+
+	ctx := testutil.NewDefaultTestContext(t)
+	dbp := test.NewDBProviderMatchWords(t)
+	m := newOnlineMigrator(dbp.DB, sqltemplate.MySQL, noop.NewTracerProvider().Tracer("test"), batchSize, time.Millisecond)
+	return m, dbp, ctx
+}
+
+func TestOnlineMigrator_step(t *testing.T) {
+	t.Parallel()
+
+	state := &OnlineMigrationState{
+		ID:         historyGenerationBackfill.id,
+		Status:     OnlineMigrationRunning,
+		CursorGUID: "guid0",
+		Processed:  10,
+		Total:      20,
+	}
+
+	t.Run("moves the cursor", func(t *testing.T) {
+		t.Parallel()
+		m, dbp, ctx := setupOnlineMigratorTest(t, 2)
+
+		dbp.SQLMock.ExpectBegin()
+		dbp.SQLMock.ExpectQuery("select guid value from resource_history").
+			WillReturnRows(dbp.SQLMock.NewRows([]string{"guid", "value"}).
+				AddRow("guid1", []byte(`{"metadata":{"generation":2}}`)).
+				AddRow("guid2", []byte(`{"metadata":{}}`)))
+		dbp.SQLMock.ExpectExec("update resource_history set generation").
+			WithArgs("guid1", int64(2), "guid1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbp.SQLMock.ExpectExec("update resource_online_migration").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbp.SQLMock.ExpectCommit()
+
+		next, err := m.step(ctx, historyGenerationBackfill, state)
+		require.NoError(t, err)
+		require.Equal(t, OnlineMigrationRunning, next.Status)
+		require.Equal(t, "guid2", next.CursorGUID)
+		require.Equal(t, int64(12), next.Processed)
+		require.NoError(t, dbp.SQLMock.ExpectationsWereMet())
+	})
+
+	t.Run("completes with the last batch", func(t *testing.T) {
+		t.Parallel()
+		m, dbp, ctx := setupOnlineMigratorTest(t, 2)
+
+		dbp.SQLMock.ExpectBegin()
+		dbp.SQLMock.ExpectQuery("select guid value from resource_history").
+			WillReturnRows(dbp.SQLMock.NewRows([]string{"guid", "value"}))
+		dbp.SQLMock.ExpectExec("update resource_online_migration").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbp.SQLMock.ExpectCommit()
+
+		next, err := m.step(ctx, historyGenerationBackfill, state)
+		require.NoError(t, err)
+		require.Equal(t, OnlineMigrationCompleted, next.Status)
+		require.Equal(t, state.CursorGUID, next.CursorGUID)
+		require.Equal(t, state.Processed, next.Processed)
+		require.NoError(t, dbp.SQLMock.ExpectationsWereMet())
+	})
+
+	t.Run("cursor moved by another replica", func(t *testing.T) {
+		t.Parallel()
+		m, dbp, ctx := setupOnlineMigratorTest(t, 2)
+
+		dbp.SQLMock.ExpectBegin()
+		dbp.SQLMock.ExpectQuery("select guid value from resource_history").
+			WillReturnRows(dbp.SQLMock.NewRows([]string{"guid", "value"}).
+				AddRow("guid1", []byte(`{"metadata":{"generation":2}}`)))
+		dbp.SQLMock.ExpectExec("update resource_history set generation").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbp.SQLMock.ExpectExec("update resource_online_migration").WillReturnResult(sqlmock.NewResult(0, 0))
+		dbp.SQLMock.ExpectRollback()
+
+		_, err := m.step(ctx, historyGenerationBackfill, state)
+		require.ErrorIs(t, err, errOnlineMigrationConflict)
+		require.NoError(t, dbp.SQLMock.ExpectationsWereMet())
+	})
+}
+
+func TestOnlineMigrator_start(t *testing.T) {
+	t.Parallel()
+
+	t.Run("saves a new migration", func(t *testing.T) {
+		t.Parallel()
+		m, dbp, ctx := setupOnlineMigratorTest(t, 2)
+
+		dbp.SQLMock.ExpectQuery("select from resource_online_migration").
+			WillReturnRows(dbp.SQLMock.NewRows(onlineMigrationCols))
+		dbp.SQLMock.ExpectQuery("select count resource_history").
+			WillReturnRows(dbp.SQLMock.NewRows([]string{"count"}).AddRow(42))
+		dbp.SQLMock.ExpectExec("insert into resource_online_migration").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		state, err := m.start(ctx, historyGenerationBackfill)
+		require.NoError(t, err)
+		require.Equal(t, OnlineMigrationRunning, state.Status)
+		require.Equal(t, int64(42), state.Total)
+		require.False(t, m.Completed(historyGenerationBackfill.id))
+		require.NoError(t, dbp.SQLMock.ExpectationsWereMet())
+	})
+
+	t.Run("loads a completed migration", func(t *testing.T) {
+		t.Parallel()
+		m, dbp, ctx := setupOnlineMigratorTest(t, 2)
+
+		dbp.SQLMock.ExpectQuery("select from resource_online_migration").
+			WillReturnRows(dbp.SQLMock.NewRows(onlineMigrationCols).
+				AddRow(historyGenerationBackfill.id, OnlineMigrationCompleted, "guid9", 42, 42, "", 1, 2))
+
+		state, err := m.start(ctx, historyGenerationBackfill)
+		require.NoError(t, err)
+		require.Equal(t, OnlineMigrationCompleted, state.Status)
+		require.True(t, m.Completed(historyGenerationBackfill.id))
+		require.NoError(t, dbp.SQLMock.ExpectationsWereMet())
+	})
+}
+
+func TestListOnlineMigrations(t *testing.T) {
+	t.Parallel()
+	_, dbp, ctx := setupOnlineMigratorTest(t, 2)
+
+	dbp.SQLMock.ExpectQuery("select from resource_online_migration").
+		WillReturnRows(dbp.SQLMock.NewRows(onlineMigrationCols))
+
+	states, err := listOnlineMigrations(ctx, dbp.DB, sqltemplate.MySQL)
+	require.NoError(t, err)
+	require.Len(t, states, len(onlineMigrations))
+	for _, state := range states {
+		require.Equal(t, OnlineMigrationPending, state.Status)
+	}
+}
+
+var onlineMigrationCols = []string{"id", "status", "cursor_guid", "processed", "total", "error", "started", "updated"}
@@ -47,6 +47,8 @@ var (
 	sqlResourceHistoryDelete       = mustTemplate("resource_history_delete.sql")
 	sqlResourceHistoryPrune        = mustTemplate("resource_history_prune.sql")
 	sqlResourceTrash               = mustTemplate("resource_trash.sql")
+	sqlResourceTrashPurge          = mustTemplate("resource_trash_purge.sql")
+	sqlResourceTrashCollections    = mustTemplate("resource_trash_collections.sql")
 	sqlResourceInsertFromHistory   = mustTemplate("resource_insert_from_history.sql")
 
 	// sqlResourceLabelsInsert = mustTemplate("resource_labels_insert.sql")
@@ -57,6 +59,27 @@ var (
 
 	sqlResourceBlobInsert = mustTemplate("resource_blob_insert.sql")
 	sqlResourceBlobQuery  = mustTemplate("resource_blob_query.sql")
+	sqlResourceBlobList   = mustTemplate("resource_blob_list.sql")
+	sqlResourceBlobDelete = mustTemplate("resource_blob_delete.sql")
+
+	sqlResourceBlobChunkExisting = mustTemplate("resource_blob_chunk_existing.sql")
+	sqlResourceBlobChunkInsert   = mustTemplate("resource_blob_chunk_insert.sql")
+	sqlResourceBlobChunkRefs     = mustTemplate("resource_blob_chunk_refs.sql")
+	sqlResourceBlobChunkRead     = mustTemplate("resource_blob_chunk_read.sql")
+	sqlResourceBlobChunkDelete   = mustTemplate("resource_blob_chunk_delete.sql")
+
+	sqlOnlineMigrationGet    = mustTemplate("resource_online_migration_get.sql")
+	sqlOnlineMigrationInsert = mustTemplate("resource_online_migration_insert.sql")
+	sqlOnlineMigrationUpdate = mustTemplate("resource_online_migration_update.sql")
+
+	sqlResourceHistoryGenerationRead   = mustTemplate("resource_history_generation_read.sql")
+	sqlResourceHistoryGenerationCount  = mustTemplate("resource_history_generation_count.sql")
+	sqlResourceHistoryGenerationUpdate = mustTemplate("resource_history_generation_update.sql")
+
+	sqlResourceHistoryCompactCandidates = mustTemplate("resource_history_compact_candidates.sql")
+	sqlResourceHistoryCompactVersions   = mustTemplate("resource_history_compact_versions.sql")
+	sqlResourceHistoryCompactValues     = mustTemplate("resource_history_compact_values.sql")
+	sqlResourceHistoryCompactDelete     = mustTemplate("resource_history_compact_delete.sql")
 )
 
 // TxOptions.
@@ -306,6 +329,36 @@ func (r sqlGetHistoryRequest) Validate() error {
 	return nil // TODO
 }
 
+// permanently remove deleted resources from history
+type sqlTrashPurgeRequest struct {
+	sqltemplate.SQLTemplate
+	Key             *resourcepb.ResourceKey
+	DeletedBeforeRV int64
+}
+
+func (r *sqlTrashPurgeRequest) Validate() error {
+	if r.Key == nil {
+		return fmt.Errorf("missing key")
+	}
+	if r.DeletedBeforeRV <= 0 {
+		return fmt.Errorf("missing deleted before resource version")
+	}
+	return nil
+}
+
+// the collections with resources deleted before the resource version
+type sqlTrashCollectionsRequest struct {
+	sqltemplate.SQLTemplate
+	DeletedBeforeRV int64
+}
+
+func (r *sqlTrashCollectionsRequest) Validate() error {
+	if r.DeletedBeforeRV <= 0 {
+		return fmt.Errorf("missing deleted before resource version")
+	}
+	return nil
+}
+
 // prune resource history
 type sqlPruneHistoryRequest struct {
 	sqltemplate.SQLTemplate
@@ -340,10 +393,13 @@ type sqlResourceBlobInsertRequest struct {
 	Key         *resourcepb.ResourceKey
 	Value       []byte
 	ContentType string
+
+	// The comma separated hashes of the chunks, the value is empty when set
+	Chunks string
 }
 
 func (r sqlResourceBlobInsertRequest) Validate() error {
-	if len(r.Value) < 1 {
+	if len(r.Value) < 1 && r.Chunks == "" {
 		return fmt.Errorf("missing body")
 	}
 	return nil
@@ -359,6 +415,110 @@ func (r sqlResourceBlobQueryRequest) Validate() error {
 	return nil
 }
 
+type blobRef struct {
+	UID    string
+	Chunks sql.NullString
+}
+
+type sqlResourceBlobListRequest struct {
+	sqltemplate.SQLTemplate
+	Key           *resourcepb.ResourceKey
+	UID           string    // optional
+	CreatedBefore time.Time // optional
+	Response      *blobRef
+}
+
+func (r *sqlResourceBlobListRequest) Validate() error {
+	if r.Key == nil || r.Key.Name == "" {
+		return fmt.Errorf("missing key")
+	}
+	return nil
+}
+
+func (r *sqlResourceBlobListRequest) Results() (*blobRef, error) {
+	x := *r.Response
+	return &x, nil
+}
+
+type sqlResourceBlobDeleteRequest struct {
+	sqltemplate.SQLTemplate
+	Key *resourcepb.ResourceKey
+	UID string
+}
+
+func (r sqlResourceBlobDeleteRequest) Validate() error {
+	if r.Key == nil || r.Key.Name == "" {
+		return fmt.Errorf("missing key")
+	}
+	if r.UID == "" {
+		return fmt.Errorf("missing uid")
+	}
+	return nil
+}
+
+type blobChunk struct {
+	Hash  string
+	Value []byte
+}
+
+type sqlResourceBlobChunkQueryRequest struct {
+	sqltemplate.SQLTemplate
+	Namespace string
+	Hashes    []string
+	Response  *blobChunk
+}
+
+func (r *sqlResourceBlobChunkQueryRequest) Validate() error {
+	if len(r.Hashes) == 0 {
+		return fmt.Errorf("missing hashes")
+	}
+	return nil
+}
+
+func (r *sqlResourceBlobChunkQueryRequest) Results() (*blobChunk, error) {
+	x := *r.Response
+	return &x, nil
+}
+
+type sqlResourceBlobChunkInsertRequest struct {
+	sqltemplate.SQLTemplate
+	Namespace string
+	Hash      string
+	Size      int64
+	Value     []byte
+}
+
+func (r sqlResourceBlobChunkInsertRequest) Validate() error {
+	if r.Hash == "" || len(r.Value) == 0 {
+		return fmt.Errorf("missing chunk")
+	}
+	return nil
+}
+
+type sqlResourceBlobChunkRefsRequest struct {
+	sqltemplate.SQLTemplate
+	Namespace string
+	Hashes    []string
+	Delta     int64
+}
+
+func (r sqlResourceBlobChunkRefsRequest) Validate() error {
+	if len(r.Hashes) == 0 {
+		return fmt.Errorf("missing hashes")
+	}
+	return nil
+}
+
+type sqlResourceBlobChunkDeleteRequest struct {
+	sqltemplate.SQLTemplate
+	Namespace string
+	Hashes    []string // optional, all the namespaces are collected without them
+}
+
+func (r sqlResourceBlobChunkDeleteRequest) Validate() error {
+	return nil
+}
+
 // update RV
 
 type sqlResourceUpdateRVRequest struct {
@@ -425,3 +585,197 @@ func (r *sqlResourceVersionListRequest) Results() (*groupResourceVersion, error)
 	x := *r.groupResourceVersion
 	return &x, nil
 }
+
+// online migrations
+
+type sqlOnlineMigrationGetRequest struct {
+	sqltemplate.SQLTemplate
+	ID       string // all the migrations when empty
+	Response *OnlineMigrationState
+}
+
+func (r *sqlOnlineMigrationGetRequest) Validate() error {
+	return nil
+}
+
+func (r *sqlOnlineMigrationGetRequest) Results() (*OnlineMigrationState, error) {
+	x := *r.Response
+	return &x, nil
+}
+
+type sqlOnlineMigrationWriteRequest struct {
+	sqltemplate.SQLTemplate
+	State *OnlineMigrationState
+
+	// The cursor the update is based on, the update does nothing when another replica moved it
+	PreviousGUID string
+}
+
+func (r *sqlOnlineMigrationWriteRequest) Validate() error {
+	if r.State == nil || r.State.ID == "" {
+		return fmt.Errorf("missing migration id")
+	}
+	return nil
+}
+
+type historyGenerationRow struct {
+	GUID  string
+	Value []byte
+}
+
+type sqlResourceHistoryGenerationReadRequest struct {
+	sqltemplate.SQLTemplate
+	CursorGUID string
+	Limit      int64
+	Response   *historyGenerationRow
+}
+
+func (r *sqlResourceHistoryGenerationReadRequest) Validate() error {
+	if r.Limit <= 0 {
+		return fmt.Errorf("limit must be greater than zero")
+	}
+	return nil
+}
+
+func (r *sqlResourceHistoryGenerationReadRequest) Results() (*historyGenerationRow, error) {
+	x := *r.Response
+	x.Value = append([]byte(nil), r.Response.Value...)
+	return &x, nil
+}
+
+type countResponse struct {
+	Count int64
+}
+
+type sqlResourceHistoryGenerationCountRequest struct {
+	sqltemplate.SQLTemplate
+	Response *countResponse
+}
+
+func (r *sqlResourceHistoryGenerationCountRequest) Validate() error {
+	return nil
+}
+
+func (r *sqlResourceHistoryGenerationCountRequest) Results() (*countResponse, error) {
+	return &countResponse{Count: r.Response.Count}, nil
+}
+
+type sqlResourceHistoryGenerationUpdateRequest struct {
+	sqltemplate.SQLTemplate
+	GUIDToGeneration map[string]int64
+}
+
+func (r *sqlResourceHistoryGenerationUpdateRequest) Validate() error {
+	if len(r.GUIDToGeneration) == 0 {
+		return fmt.Errorf("missing rows")
+	}
+	return nil
+}
+
+// history compaction
+
+type compactCandidate struct {
+	Namespace string
+	Name      string
+}
+
+type sqlHistoryCompactCandidatesRequest struct {
+	sqltemplate.SQLTemplate
+	Group    string
+	Resource string
+
+	// The last candidate of the previous page
+	CursorNamespace string
+	CursorName      string
+
+	// Only the names with more versions, and with versions older than MaxRV
+	MinVersions int64
+	MaxRV       int64
+	Limit       int64
+	Response    *compactCandidate
+}
+
+func (r *sqlHistoryCompactCandidatesRequest) Validate() error {
+	if r.Group == "" || r.Resource == "" {
+		return fmt.Errorf("missing group or resource")
+	}
+	if r.Limit <= 0 {
+		return fmt.Errorf("limit must be greater than zero")
+	}
+	return nil
+}
+
+func (r *sqlHistoryCompactCandidatesRequest) Results() (*compactCandidate, error) {
+	x := *r.Response
+	return &x, nil
+}
+
+type historyVersion struct {
+	GUID            string
+	ResourceVersion int64
+	Action          int
+}
+
+type sqlHistoryCompactVersionsRequest struct {
+	sqltemplate.SQLTemplate
+	Key      *resourcepb.ResourceKey
+	Response *historyVersion
+}
+
+func (r *sqlHistoryCompactVersionsRequest) Validate() error {
+	if r.Key == nil || r.Key.Name == "" {
+		return fmt.Errorf("missing key")
+	}
+	return nil
+}
+
+func (r *sqlHistoryCompactVersionsRequest) Results() (*historyVersion, error) {
+	x := *r.Response
+	return &x, nil
+}
+
+// historyValue is the value of a kept version, read to find the blob it references
+type historyValue struct {
+	Value []byte
+}
+
+type sqlHistoryCompactValuesRequest struct {
+	sqltemplate.SQLTemplate
+	Key      *resourcepb.ResourceKey
+	GUIDs    []string
+	Response *historyValue
+}
+
+func (r *sqlHistoryCompactValuesRequest) Validate() error {
+	if r.Key == nil || r.Key.Name == "" {
+		return fmt.Errorf("missing key")
+	}
+	if len(r.GUIDs) == 0 {
+		return fmt.Errorf("missing versions")
+	}
+	return nil
+}
+
+func (r *sqlHistoryCompactValuesRequest) Results() (*historyValue, error) {
+	x := *r.Response
+	return &x, nil
+}
+
+type sqlHistoryCompactDeleteRequest struct {
+	sqltemplate.SQLTemplate
+	Key *resourcepb.ResourceKey
+
+	// The versions newer than this are never removed
+	MaxRV int64
+	GUIDs []string
+}
+
+func (r *sqlHistoryCompactDeleteRequest) Validate() error {
+	if r.Key == nil || r.Key.Name == "" {
+		return fmt.Errorf("missing key")
+	}
+	if len(r.GUIDs) == 0 {
+		return fmt.Errorf("missing versions")
+	}
+	return nil
+}
@@ -406,6 +406,27 @@ func TestUnifiedStorageQueries(t *testing.T) {
 						Value:       []byte("abcdefg"),
 					},
 				},
+				{
+					Name: "chunks",
+					Data: &sqlResourceBlobInsertRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Key: &resourcepb.ResourceKey{
+							Namespace: "x",
+							Group:     "g",
+							Resource:  "r",
+							Name:      "name",
+						},
+						Now: time.UnixMilli(1704056400000).UTC(),
+						Info: &utils.BlobInfo{
+							UID:  "abc",
+							Hash: "xxx",
+							Size: 1234,
+						},
+						ContentType: "text/plain",
+						Value:       []byte{},
+						Chunks:      "aaa,bbb,aaa",
+					},
+				},
 			},
 
 			sqlResourceBlobQuery: {
@@ -434,6 +455,119 @@ func TestUnifiedStorageQueries(t *testing.T) {
 					},
 				},
 			},
+
+			sqlResourceBlobList: {
+				{
+					Name: "all",
+					Data: &sqlResourceBlobListRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Key: &resourcepb.ResourceKey{
+							Namespace: "x",
+							Group:     "g",
+							Resource:  "r",
+							Name:      "name",
+						},
+						Response: new(blobRef),
+					},
+				},
+				{
+					Name: "uid and created",
+					Data: &sqlResourceBlobListRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Key: &resourcepb.ResourceKey{
+							Namespace: "x",
+							Group:     "g",
+							Resource:  "r",
+							Name:      "name",
+						},
+						UID:           "abc",
+						CreatedBefore: time.UnixMilli(1704056400000).UTC(),
+						Response:      new(blobRef),
+					},
+				},
+			},
+
+			sqlResourceBlobDelete: {
+				{
+					Name: "simple",
+					Data: &sqlResourceBlobDeleteRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Key: &resourcepb.ResourceKey{
+							Namespace: "x",
+							Group:     "g",
+							Resource:  "r",
+							Name:      "name",
+						},
+						UID: "abc",
+					},
+				},
+			},
+
+			sqlResourceBlobChunkExisting: {
+				{
+					Name: "simple",
+					Data: &sqlResourceBlobChunkQueryRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Namespace:   "ns",
+						Hashes:      []string{"aaa", "bbb"},
+						Response:    new(blobChunk),
+					},
+				},
+			},
+
+			sqlResourceBlobChunkRead: {
+				{
+					Name: "simple",
+					Data: &sqlResourceBlobChunkQueryRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Namespace:   "ns",
+						Hashes:      []string{"aaa", "bbb"},
+						Response:    new(blobChunk),
+					},
+				},
+			},
+
+			sqlResourceBlobChunkInsert: {
+				{
+					Name: "simple",
+					Data: &sqlResourceBlobChunkInsertRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Namespace:   "ns",
+						Hash:        "aaa",
+						Size:        7,
+						Value:       []byte("abcdefg"),
+					},
+				},
+			},
+
+			sqlResourceBlobChunkRefs: {
+				{
+					Name: "release",
+					Data: &sqlResourceBlobChunkRefsRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Namespace:   "ns",
+						Hashes:      []string{"aaa", "bbb"},
+						Delta:       -1,
+					},
+				},
+			},
+
+			sqlResourceBlobChunkDelete: {
+				{
+					Name: "all",
+					Data: &sqlResourceBlobChunkDeleteRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+					},
+				},
+				{
+					Name: "hashes",
+					Data: &sqlResourceBlobChunkDeleteRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Namespace:   "ns",
+						Hashes:      []string{"aaa", "bbb"},
+					},
+				},
+			},
 			sqlResourceHistoryDelete: {
 				{
 					Name: "guid",
@@ -453,6 +587,37 @@ func TestUnifiedStorageQueries(t *testing.T) {
 					},
 				},
 			},
+			sqlResourceTrashPurge: {
+				{
+					Name: "namespace",
+					Data: &sqlTrashPurgeRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Key: &resourcepb.ResourceKey{
+							Namespace: "default",
+							Group:     "dashboard.grafana.app",
+							Resource:  "dashboards",
+						},
+						DeletedBeforeRV: 1234,
+					},
+				},
+				{
+					Name: "everything",
+					Data: &sqlTrashPurgeRequest{
+						SQLTemplate:     mocks.NewTestingSQLTemplate(),
+						Key:             &resourcepb.ResourceKey{},
+						DeletedBeforeRV: 1234,
+					},
+				},
+			},
+			sqlResourceTrashCollections: {
+				{
+					Name: "deleted before",
+					Data: &sqlTrashCollectionsRequest{
+						SQLTemplate:     mocks.NewTestingSQLTemplate(),
+						DeletedBeforeRV: 1234,
+					},
+				},
+			},
 			sqlResourceInsertFromHistory: {
 				{
 					Name: "update",
@@ -466,5 +631,161 @@ func TestUnifiedStorageQueries(t *testing.T) {
 					},
 				},
 			},
+			sqlOnlineMigrationGet: {
+				{
+					Name: "all",
+					Data: &sqlOnlineMigrationGetRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Response:    new(OnlineMigrationState),
+					},
+				},
+				{
+					Name: "by id",
+					Data: &sqlOnlineMigrationGetRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						ID:          "backfill resource_history generation",
+						Response:    new(OnlineMigrationState),
+					},
+				},
+			},
+			sqlOnlineMigrationInsert: {
+				{
+					Name: "simple",
+					Data: &sqlOnlineMigrationWriteRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						State: &OnlineMigrationState{
+							ID:      "backfill resource_history generation",
+							Status:  OnlineMigrationRunning,
+							Total:   1000,
+							Started: 1234,
+							Updated: 1234,
+						},
+					},
+				},
+			},
+			sqlOnlineMigrationUpdate: {
+				{
+					Name: "move cursor",
+					Data: &sqlOnlineMigrationWriteRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						State: &OnlineMigrationState{
+							ID:         "backfill resource_history generation",
+							Status:     OnlineMigrationRunning,
+							CursorGUID: "guid2",
+							Processed:  500,
+							Total:      1000,
+							Updated:    5678,
+						},
+						PreviousGUID: "guid1",
+					},
+				},
+			},
+			sqlResourceHistoryGenerationRead: {
+				{
+					Name: "after cursor",
+					Data: &sqlResourceHistoryGenerationReadRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						CursorGUID:  "guid1",
+						Limit:       500,
+						Response:    new(historyGenerationRow),
+					},
+				},
+			},
+			sqlResourceHistoryGenerationCount: {
+				{
+					Name: "simple",
+					Data: &sqlResourceHistoryGenerationCountRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Response:    new(countResponse),
+					},
+				},
+			},
+			sqlResourceHistoryGenerationUpdate: {
+				{
+					Name: "two rows",
+					Data: &sqlResourceHistoryGenerationUpdateRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						GUIDToGeneration: map[string]int64{
+							"guid1": 1,
+							"guid2": 3,
+						},
+					},
+				},
+			},
+			sqlResourceHistoryCompactCandidates: {
+				{
+					Name: "first page",
+					Data: &sqlHistoryCompactCandidatesRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Group:       "dashboard.grafana.app",
+						Resource:    "dashboards",
+						MinVersions: 10,
+						MaxRV:       1234,
+						Limit:       100,
+						Response:    new(compactCandidate),
+					},
+				},
+				{
+					Name: "next page",
+					Data: &sqlHistoryCompactCandidatesRequest{
+						SQLTemplate:     mocks.NewTestingSQLTemplate(),
+						Group:           "dashboard.grafana.app",
+						Resource:        "dashboards",
+						CursorNamespace: "default",
+						CursorName:      "name",
+						MinVersions:     1,
+						MaxRV:           1234,
+						Limit:           100,
+						Response:        new(compactCandidate),
+					},
+				},
+			},
+			sqlResourceHistoryCompactVersions: {
+				{
+					Name: "simple",
+					Data: &sqlHistoryCompactVersionsRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Key: &resourcepb.ResourceKey{
+							Namespace: "default",
+							Group:     "dashboard.grafana.app",
+							Resource:  "dashboards",
+							Name:      "name",
+						},
+						Response: new(historyVersion),
+					},
+				},
+			},
+			sqlResourceHistoryCompactValues: {
+				{
+					Name: "simple",
+					Data: &sqlHistoryCompactValuesRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Key: &resourcepb.ResourceKey{
+							Namespace: "default",
+							Group:     "dashboard.grafana.app",
+							Resource:  "dashboards",
+							Name:      "name",
+						},
+						GUIDs:    []string{"guid1", "guid2"},
+						Response: new(historyValue),
+					},
+				},
+			},
+			sqlResourceHistoryCompactDelete: {
+				{
+					Name: "simple",
+					Data: &sqlHistoryCompactDeleteRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Key: &resourcepb.ResourceKey{
+							Namespace: "default",
+							Group:     "dashboard.grafana.app",
+							Resource:  "dashboards",
+							Name:      "name",
+						},
+						MaxRV: 1234,
+						GUIDs: []string{"guid1", "guid2"},
+					},
+				},
+			},
 		}})
 }
@@ -40,11 +40,15 @@ func (b *backend) GetStats(ctx context.Context, req *resourcepb.ResourceStatsReq
 				return err
 			}
 
-			rsp.Stats = append(rsp.Stats, &resourcepb.ResourceStatsResponse_Stats{
+			stat := &resourcepb.ResourceStatsResponse_Stats{
 				Group:    row.Group,
 				Resource: row.Resource,
 				Count:    row.Count,
-			})
+			}
+			if policy, ok := b.compactor.policy(row.Group, row.Resource); ok {
+				stat.Retention = policy.AsProto()
+			}
+			rsp.Stats = append(rsp.Stats, stat)
 		}
 		return err
 	})
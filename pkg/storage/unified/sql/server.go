@@ -3,6 +3,7 @@ package sql
 import (
 	"os"
 	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/otel/trace"
@@ -21,7 +22,8 @@ import (
 func NewResourceServer(db infraDB.DB, cfg *setting.Cfg,
 	tracer trace.Tracer, reg prometheus.Registerer, ac types.AccessClient,
 	searchOptions resource.SearchOptions, storageMetrics *resource.StorageMetrics,
-	indexMetrics *resource.BleveIndexMetrics, features featuremgmt.FeatureToggles) (resource.ResourceServer, error) {
+	indexMetrics *resource.BleveIndexMetrics, features featuremgmt.FeatureToggles,
+	notifier resource.SearchAlertNotifier) (resource.ResourceServer, error) {
 	apiserverCfg := cfg.SectionWithEnvOverrides("grafana-apiserver")
 	opts := resource.ResourceServerOptions{
 		Tracer: tracer,
@@ -49,6 +51,9 @@ func NewResourceServer(db infraDB.DB, cfg *setting.Cfg,
 	maxPageSizeBytes := unifiedStorageCfg.Key("max_page_size_bytes")
 	opts.MaxPageSizeBytes = maxPageSizeBytes.MustInt(0)
 
+	// Deleted resources are kept in the trash until they are purged
+	opts.TrashRetention = unifiedStorageCfg.Key("trash_retention").MustDuration(0)
+
 	eDB, err := dbimpl.ProvideResourceDB(db, cfg, tracer)
 	if err != nil {
 		return nil, err
@@ -65,6 +70,14 @@ func NewResourceServer(db infraDB.DB, cfg *setting.Cfg,
 		IsHA:           isHA,
 		withPruner:     withPruner,
 		storageMetrics: storageMetrics,
+
+		// The changes to the existing rows run in the background after startup
+		OnlineMigrations:             unifiedStorageCfg.Key("online_migrations").MustBool(true),
+		OnlineMigrationBatchSize:     unifiedStorageCfg.Key("online_migration_batch_size").MustInt64(defaultOnlineMigrationBatchSize),
+		OnlineMigrationBatchInterval: unifiedStorageCfg.Key("online_migration_batch_interval").MustDuration(defaultOnlineMigrationInterval),
+
+		HistoryRetention:   historyRetention(cfg),
+		CompactionInterval: unifiedStorageCfg.Key("history_compaction_interval").MustDuration(defaultCompactionInterval),
 	})
 	if err != nil {
 		return nil, err
@@ -75,6 +88,15 @@ func NewResourceServer(db infraDB.DB, cfg *setting.Cfg,
 	opts.Search = searchOptions
 	opts.IndexMetrics = indexMetrics
 
+	// Saved search alerts need the search index
+	opts.SavedSearch = resource.SavedSearchOptions{
+		AllowedWebhookHosts: unifiedStorageCfg.Key("saved_search_webhook_allowed_hosts").Strings(","),
+		Interval:            unifiedStorageCfg.Key("saved_search_interval").MustDuration(time.Minute),
+	}
+	if searchOptions.Backend != nil {
+		opts.SavedSearch.Notifier = notifier
+	}
+
 	rs, err := resource.NewResourceServer(opts)
 	if err != nil {
 		return nil, err
@@ -83,6 +105,28 @@ func NewResourceServer(db infraDB.DB, cfg *setting.Cfg,
 	return rs, nil
 }
 
+// historyRetention reads the retention policies from the [unified_storage.<resource>.<group>] sections
+func historyRetention(cfg *setting.Cfg) []resource.HistoryRetention {
+	var policies []resource.HistoryRetention
+	for name, storageCfg := range cfg.UnifiedStorage {
+		res, group, ok := strings.Cut(name, ".")
+		if !ok {
+			continue
+		}
+		policy := resource.HistoryRetention{
+			Group:    group,
+			Resource: res,
+			Versions: storageCfg.HistoryKeepVersions,
+			All:      storageCfg.HistoryKeepAll,
+			Daily:    storageCfg.HistoryKeepDaily,
+		}
+		if !policy.IsZero() {
+			policies = append(policies, policy)
+		}
+	}
+	return policies
+}
+
 // isHighAvailabilityEnabled determines if high availability mode should
 // be enabled based on database configuration. High availability is enabled
 // by default except for SQLite databases.
@@ -29,6 +29,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/grpcserver"
 	"github.com/grafana/grafana/pkg/services/grpcserver/interceptors"
+	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/storage/unified/resource"
 	"github.com/grafana/grafana/pkg/storage/unified/resource/grpc"
@@ -69,6 +70,9 @@ type service struct {
 
 	docBuilders resource.DocumentBuilderSupplier
 
+	// Sends the saved search alerts, optional
+	notifications notifications.Service
+
 	storageRing *ring.Ring
 	lifecycler  *ring.BasicLifecycler
 }
@@ -80,6 +84,7 @@ func ProvideUnifiedStorageGrpcService(
 	log log.Logger,
 	reg prometheus.Registerer,
 	docBuilders resource.DocumentBuilderSupplier,
+	notificationService notifications.Service,
 	storageMetrics *resource.StorageMetrics,
 	indexMetrics *resource.BleveIndexMetrics,
 	storageRing *ring.Ring,
@@ -109,6 +114,7 @@ func ProvideUnifiedStorageGrpcService(
 		log:            log,
 		reg:            reg,
 		docBuilders:    docBuilders,
+		notifications:  notificationService,
 		storageMetrics: storageMetrics,
 		indexMetrics:   indexMetrics,
 		storageRing:    storageRing,
@@ -167,7 +173,12 @@ func (s *service) start(ctx context.Context) error {
 		return err
 	}
 
-	server, err := NewResourceServer(s.db, s.cfg, s.tracing, s.reg, authzClient, searchOptions, s.storageMetrics, s.indexMetrics, s.features)
+	var notifier resource.SearchAlertNotifier
+	if s.notifications != nil {
+		notifier = search.NewSearchAlertNotifier(s.notifications)
+	}
+
+	server, err := NewResourceServer(s.db, s.cfg, s.tracing, s.reg, authzClient, searchOptions, s.storageMetrics, s.indexMetrics, s.features, notifier)
 	if err != nil {
 		return err
 	}
@@ -188,6 +199,7 @@ func (s *service) start(ctx context.Context) error {
 	resourcepb.RegisterManagedObjectIndexServer(srv, server)
 	resourcepb.RegisterBlobStoreServer(srv, server)
 	resourcepb.RegisterDiagnosticsServer(srv, server)
+	resourcepb.RegisterTrashServer(srv, server)
 	grpc_health_v1.RegisterHealthServer(srv, healthService)
 
 	// register reflection service
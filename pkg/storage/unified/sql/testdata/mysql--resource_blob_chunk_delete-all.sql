@@ -0,0 +1,3 @@
+DELETE FROM `resource_blob_chunk`
+    WHERE `refs` <= 0
+;
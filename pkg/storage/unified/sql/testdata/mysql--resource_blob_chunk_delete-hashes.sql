@@ -0,0 +1,5 @@
+DELETE FROM `resource_blob_chunk`
+    WHERE `refs` <= 0
+        AND `namespace` = 'ns'
+        AND `hash` IN ('aaa', 'bbb')
+;
@@ -0,0 +1,17 @@
+INSERT INTO `resource_blob_chunk`
+    (
+        `namespace`,
+        `hash`,
+        `size`,
+        `refs`,
+        `value`
+    )
+    VALUES (
+        'ns',
+        'aaa',
+        7,
+        1,
+        '[97 98 99 100 101 102 103]'
+    )
+    ON DUPLICATE KEY UPDATE `refs` = `refs` + 1
+;
@@ -0,0 +1,7 @@
+SELECT
+    `hash`,
+    `value`
+    FROM `resource_blob_chunk`
+    WHERE `namespace` = 'ns'
+        AND `hash` IN ('aaa', 'bbb')
+;
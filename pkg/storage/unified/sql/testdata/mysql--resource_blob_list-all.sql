@@ -0,0 +1,10 @@
+SELECT
+    `uuid`,
+    `chunks`
+    FROM `resource_blob`
+    WHERE `namespace` = 'x'
+        AND `group` = 'g'
+        AND `resource` = 'r'
+        AND `name` = 'name'
+        AND `created` < '0001-01-01 00:00:00 +0000 UTC'
+;
@@ -0,0 +1,11 @@
+SELECT
+    `uuid`,
+    `chunks`
+    FROM `resource_blob`
+    WHERE `namespace` = 'x'
+        AND `group` = 'g'
+        AND `resource` = 'r'
+        AND `name` = 'name'
+        AND `uuid` = 'abc'
+        AND `created` < '2023-12-31 21:00:00 +0000 UTC'
+;
@@ -0,0 +1,16 @@
+SELECT
+    `namespace`,
+    `name`
+    FROM `resource_history`
+    WHERE `group` = 'dashboard.grafana.app'
+        AND `resource` = 'dashboards'
+        AND (
+            `namespace` > ''
+            OR (`namespace` = '' AND `name` > '')
+        )
+    GROUP BY `namespace`, `name`
+    HAVING COUNT(*) > 10
+        AND MIN(`resource_version`) < 1234
+    ORDER BY `namespace` ASC, `name` ASC
+    LIMIT 100
+;
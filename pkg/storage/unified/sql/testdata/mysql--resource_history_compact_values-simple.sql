@@ -0,0 +1,9 @@
+SELECT
+    `value`
+    FROM `resource_history`
+    WHERE `namespace` = 'default'
+        AND `group` = 'dashboard.grafana.app'
+        AND `resource` = 'dashboards'
+        AND `name` = 'name'
+        AND `guid` IN ('guid1', 'guid2')
+;
@@ -0,0 +1,22 @@
+INSERT INTO `resource_online_migration`
+    (
+        `id`,
+        `status`,
+        `cursor_guid`,
+        `processed`,
+        `total`,
+        `error`,
+        `started`,
+        `updated`
+    )
+    VALUES (
+        'backfill resource_history generation',
+        'running',
+        '',
+        0,
+        1000,
+        '',
+        1234,
+        1234
+    )
+;
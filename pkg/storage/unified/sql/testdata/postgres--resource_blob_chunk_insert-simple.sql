@@ -0,0 +1,18 @@
+INSERT INTO "resource_blob_chunk"
+    (
+        "namespace",
+        "hash",
+        "size",
+        "refs",
+        "value"
+    )
+    VALUES (
+        'ns',
+        'aaa',
+        7,
+        1,
+        '[97 98 99 100 101 102 103]'
+    )
+    ON CONFLICT ("namespace", "hash")
+    DO UPDATE SET "refs" = "resource_blob_chunk"."refs" + 1
+;
@@ -0,0 +1,5 @@
+UPDATE "resource_blob_chunk"
+    SET "refs" = "refs" + -1
+    WHERE "namespace" = 'ns'
+        AND "hash" IN ('aaa', 'bbb')
+;
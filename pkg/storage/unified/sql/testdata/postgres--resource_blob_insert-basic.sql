@@ -8,7 +8,8 @@ INSERT INTO "resource_blob"
     "name",
     "value",
     "hash",
-    "content_type"
+    "content_type",
+    "chunks"
   )
   VALUES (
     'abc', 
@@ -19,6 +20,7 @@ INSERT INTO "resource_blob"
     'name',
     '[97 98 99 100 101 102 103]',
     'xxx',
-    'text/plain'
+    'text/plain',
+    ''
   )
 ;
@@ -1,7 +1,8 @@
 SELECT
   "uuid",
   "value",
-  "content_type"
+  "content_type",
+  "chunks"
 FROM "resource_blob"
 WHERE 1 = 1
   AND "namespace" = 'x'
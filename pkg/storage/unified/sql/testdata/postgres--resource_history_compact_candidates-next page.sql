@@ -0,0 +1,16 @@
+SELECT
+    "namespace",
+    "name"
+    FROM "resource_history"
+    WHERE "group" = 'dashboard.grafana.app'
+        AND "resource" = 'dashboards'
+        AND (
+            "namespace" > 'default'
+            OR ("namespace" = 'default' AND "name" > 'name')
+        )
+    GROUP BY "namespace", "name"
+    HAVING COUNT(*) > 1
+        AND MIN("resource_version") < 1234
+    ORDER BY "namespace" ASC, "name" ASC
+    LIMIT 100
+;
@@ -0,0 +1,8 @@
+DELETE FROM "resource_history"
+    WHERE "namespace" = 'default'
+        AND "group" = 'dashboard.grafana.app'
+        AND "resource" = 'dashboards'
+        AND "name" = 'name'
+        AND "resource_version" < 1234
+        AND "guid" IN ('guid1', 'guid2')
+;
@@ -0,0 +1,11 @@
+UPDATE "resource_history"
+SET "generation" = (
+    CASE
+    WHEN "guid" = 'guid1' THEN CAST(1 AS BIGINT)
+    WHEN "guid" = 'guid2' THEN CAST(3 AS BIGINT)
+    END
+)
+WHERE "generation" = 0
+    AND "guid" IN (
+    'guid1', 'guid2'
+);
@@ -0,0 +1,13 @@
+SELECT
+    "id",
+    "status",
+    "cursor_guid",
+    "processed",
+    "total",
+    "error",
+    "started",
+    "updated"
+    FROM "resource_online_migration"
+    WHERE "id" = 'backfill resource_history generation'
+    ORDER BY "id" ASC
+;
@@ -0,0 +1,6 @@
+SELECT
+    "hash"
+    FROM "resource_blob_chunk"
+    WHERE "namespace" = 'ns'
+        AND "hash" IN ('aaa', 'bbb')
+;
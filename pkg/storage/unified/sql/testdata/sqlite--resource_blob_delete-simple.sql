@@ -0,0 +1,7 @@
+DELETE FROM "resource_blob"
+    WHERE "namespace" = 'x'
+        AND "group" = 'g'
+        AND "resource" = 'r'
+        AND "name" = 'name'
+        AND "uuid" = 'abc'
+;
@@ -0,0 +1,26 @@
+INSERT INTO "resource_blob"
+  (
+    "uuid", 
+    "created",
+    "group",
+    "resource",
+    "namespace",
+    "name",
+    "value",
+    "hash",
+    "content_type",
+    "chunks"
+  )
+  VALUES (
+    'abc', 
+    '2023-12-31 21:00:00 +0000 UTC', 
+    'g',
+    'r',
+    'x',
+    'name',
+    '[]',
+    'xxx',
+    'text/plain',
+    'aaa,bbb,aaa'
+  )
+;
@@ -0,0 +1,11 @@
+SELECT
+    "guid",
+    "resource_version",
+    "action"
+    FROM "resource_history"
+    WHERE "namespace" = 'default'
+        AND "group" = 'dashboard.grafana.app'
+        AND "resource" = 'dashboards'
+        AND "name" = 'name'
+    ORDER BY "resource_version" DESC
+;
@@ -0,0 +1,6 @@
+SELECT
+    COUNT(*)
+    FROM "resource_history"
+    WHERE "generation" = 0
+        AND "action" != 3
+;
@@ -0,0 +1,10 @@
+SELECT
+    "guid",
+    "value"
+    FROM "resource_history"
+    WHERE "guid" > 'guid1'
+        AND "generation" = 0
+        AND "action" != 3
+    ORDER BY "guid" ASC
+    LIMIT 500
+;
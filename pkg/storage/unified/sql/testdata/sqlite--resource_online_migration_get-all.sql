@@ -0,0 +1,12 @@
+SELECT
+    "id",
+    "status",
+    "cursor_guid",
+    "processed",
+    "total",
+    "error",
+    "started",
+    "updated"
+    FROM "resource_online_migration"
+    ORDER BY "id" ASC
+;
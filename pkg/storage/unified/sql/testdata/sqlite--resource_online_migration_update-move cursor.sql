@@ -0,0 +1,11 @@
+UPDATE "resource_online_migration"
+    SET
+        "status" = 'running',
+        "cursor_guid" = 'guid2',
+        "processed" = 500,
+        "total" = 1000,
+        "error" = '',
+        "updated" = 5678
+    WHERE "id" = 'backfill resource_history generation'
+        AND "cursor_guid" = 'guid1'
+;
@@ -0,0 +1,12 @@
+SELECT DISTINCT
+  "namespace",
+  "group",
+  "resource"
+FROM "resource_history"
+WHERE "action" = 3
+  AND "resource_version" < 1234
+ORDER BY
+  "namespace",
+  "group",
+  "resource"
+;
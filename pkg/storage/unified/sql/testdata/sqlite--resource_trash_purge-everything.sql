@@ -0,0 +1,33 @@
+DELETE FROM "resource_history"
+WHERE "guid" IN (
+  SELECT "guid"
+  FROM (
+  SELECT h."guid"
+  FROM "resource_history" h
+  INNER JOIN (
+    SELECT "namespace", "group", "resource", "name", MAX("resource_version") AS "max_rv"
+    FROM "resource_history"
+    WHERE 1 = 1
+    GROUP BY "namespace", "group", "resource", "name"
+  ) AS "latest"
+     ON h."namespace" = "latest"."namespace"
+    AND h."group"     = "latest"."group"
+    AND h."resource"  = "latest"."resource"
+    AND h."name"      = "latest"."name"
+  INNER JOIN "resource_history" d
+     ON d."namespace" = "latest"."namespace"
+    AND d."group"     = "latest"."group"
+    AND d."resource"  = "latest"."resource"
+    AND d."name"      = "latest"."name"
+    AND d."resource_version" = "latest"."max_rv"
+  WHERE d."action" = 3
+    AND d."resource_version" < 1234
+    AND NOT EXISTS (
+      SELECT 1 FROM "resource" r
+      WHERE r."namespace" = h."namespace"
+        AND r."group"     = h."group"
+        AND r."resource"  = h."resource"
+        AND r."name"      = h."name"
+    )
+  ) AS "purged"
+);
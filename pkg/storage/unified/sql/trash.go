@@ -0,0 +1,77 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/resourcepb"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/db"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/dbutil"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/sqltemplate"
+)
+
+var (
+	_ resource.TrashPurger = (*backend)(nil)
+)
+
+// PurgeTrash removes the full history of resources that were deleted before the given time.
+// Resource versions are allocated from the database clock in microseconds (see rvManager.lock),
+// so the deletion time is compared against the resource version of the deletion.
+func (b *backend) PurgeTrash(ctx context.Context, key *resourcepb.ResourceKey, deletedBefore time.Time) (int64, error) {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"PurgeTrash")
+	defer span.End()
+
+	if key == nil {
+		key = &resourcepb.ResourceKey{}
+	}
+
+	var purged int64
+	err := b.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
+		res, err := dbutil.Exec(ctx, tx, sqlResourceTrashPurge, &sqlTrashPurgeRequest{
+			SQLTemplate:     sqltemplate.New(b.dialect),
+			Key:             key,
+			DeletedBeforeRV: deletedBefore.UnixMicro(),
+		})
+		if err != nil {
+			return fmt.Errorf("purge trash: %w", err)
+		}
+		purged, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// ListTrashCollections returns every collection with resources deleted before the given time.
+func (b *backend) ListTrashCollections(ctx context.Context, deletedBefore time.Time) ([]*resourcepb.ResourceKey, error) {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"ListTrashCollections")
+	defer span.End()
+
+	var collections []*resourcepb.ResourceKey
+	err := b.db.WithTx(ctx, ReadCommittedRO, func(ctx context.Context, tx db.Tx) error {
+		rows, err := dbutil.QueryRows(ctx, tx, sqlResourceTrashCollections, &sqlTrashCollectionsRequest{
+			SQLTemplate:     sqltemplate.New(b.dialect),
+			DeletedBeforeRV: deletedBefore.UnixMicro(),
+		})
+		if err != nil {
+			return fmt.Errorf("list trash collections: %w", err)
+		}
+		defer func() { _ = rows.Close() }()
+		for rows.Next() {
+			key := &resourcepb.ResourceKey{}
+			if err := rows.Scan(&key.Namespace, &key.Group, &key.Resource); err != nil {
+				return err
+			}
+			collections = append(collections, key)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return collections, nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apiserver/pkg/endpoints/request"
@@ -37,6 +38,8 @@ const (
 	TestListHistoryErrorReporting = "list history error reporting"
 	TestListTrash                 = "list trash"
 	TestCreateNewResource         = "create new resource"
+	TestRestoreFromTrash          = "restore from trash"
+	TestWriteEventPreconditions   = "write event preconditions"
 )
 
 type NewBackendFunc func(ctx context.Context) resource.StorageBackend
@@ -82,6 +85,8 @@ func RunStorageBackendTest(t *testing.T, newBackend NewBackendFunc, opts *TestOp
 		{TestListHistoryErrorReporting, runTestIntegrationBackendListHistoryErrorReporting},
 		{TestListTrash, runTestIntegrationBackendTrash},
 		{TestCreateNewResource, runTestIntegrationBackendCreateNewResource},
+		{TestRestoreFromTrash, runTestIntegrationBackendRestoreFromTrash},
+		{TestWriteEventPreconditions, runTestIntegrationBackendWriteEventPreconditions},
 	}
 
 	for _, tc := range cases {
@@ -1007,6 +1012,166 @@ func runTestIntegrationBackendCreateNewResource(t *testing.T, backend resource.S
 	})
 }
 
+func runTestIntegrationBackendRestoreFromTrash(t *testing.T, backend resource.StorageBackend, nsPrefix string) {
+	ctx := types.WithAuthInfo(t.Context(), authn.NewAccessTokenAuthInfo(authn.Claims[authn.AccessTokenClaims]{
+		Claims: jwt.Claims{
+			Subject: "testuser",
+		},
+		Rest: authn.AccessTokenClaims{},
+	}))
+
+	server := newServer(t, backend)
+	ns := nsPrefix + "-restore-trash"
+	folders := &resourcepb.ResourceKey{Namespace: ns, Group: "folder.grafana.app", Resource: "folders"}
+	dashboards := &resourcepb.ResourceKey{Namespace: ns, Group: "dashboard.grafana.app", Resource: "dashboards"}
+	keyFor := func(collection *resourcepb.ResourceKey, name string) *resourcepb.ResourceKey {
+		return &resourcepb.ResourceKey{
+			Namespace: collection.Namespace,
+			Group:     collection.Group,
+			Resource:  collection.Resource,
+			Name:      name,
+		}
+	}
+	create := func(collection *resourcepb.ResourceKey, kind, name, folder string) {
+		value := fmt.Sprintf(`{"apiVersion":"%s/v1","kind":"%s","metadata":{"name":"%s","namespace":"%s","annotations":{"grafana.app/folder":"%s"}}}`,
+			collection.Group, kind, name, ns, folder)
+		rsp, err := server.Create(ctx, &resourcepb.CreateRequest{Key: keyFor(collection, name), Value: []byte(value)})
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+	}
+	remove := func(collection *resourcepb.ResourceKey, name string) {
+		rsp, err := server.Delete(ctx, &resourcepb.DeleteRequest{Key: keyFor(collection, name)})
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+	}
+	listTrash := func() []string {
+		rsp, err := server.ListTrash(ctx, &resourcepb.ListTrashRequest{
+			Namespace: ns,
+			Resources: []*resourcepb.ResourceKey{folders, dashboards},
+		})
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+		names := []string{}
+		for _, item := range rsp.Items {
+			require.NotEmpty(t, item.DeletedBy)
+			require.Greater(t, item.DeletedAt, int64(0))
+			names = append(names, item.Key.Resource+"/"+item.Key.Name)
+		}
+		return names
+	}
+
+	create(folders, "Folder", "parent", "")
+	create(folders, "Folder", "child", "parent")
+	create(dashboards, "Dashboard", "dash", "child")
+	remove(dashboards, "dash")
+	remove(folders, "child")
+	remove(folders, "parent")
+
+	require.Equal(t, []string{"folders/parent", "folders/child", "dashboards/dash"}, listTrash())
+
+	t.Run("restores missing parent folders", func(t *testing.T) {
+		rsp, err := server.RestoreFromTrash(ctx, &resourcepb.RestoreFromTrashRequest{Key: keyFor(dashboards, "dash")})
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+		restored := []string{}
+		for _, r := range rsp.Restored {
+			require.Greater(t, r.ResourceVersion, int64(0))
+			restored = append(restored, r.Key.Resource+"/"+r.Key.Name)
+		}
+		require.Equal(t, []string{"folders/parent", "folders/child", "dashboards/dash"}, restored)
+		require.Empty(t, listTrash())
+
+		read, err := server.Read(ctx, &resourcepb.ReadRequest{Key: keyFor(dashboards, "dash")})
+		require.NoError(t, err)
+		require.Nil(t, read.Error)
+		require.Contains(t, string(read.Value), `"grafana.app/folder":"child"`)
+	})
+
+	t.Run("missing resources are not found", func(t *testing.T) {
+		rsp, err := server.RestoreFromTrash(ctx, &resourcepb.RestoreFromTrashRequest{Key: keyFor(dashboards, "dash")})
+		require.NoError(t, err)
+		require.NotNil(t, rsp.Error)
+		require.Equal(t, int32(http.StatusNotFound), rsp.Error.Code)
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		remove(dashboards, "dash")
+		require.Equal(t, []string{"dashboards/dash"}, listTrash())
+
+		before := time.Now().Add(time.Minute)
+		purger, ok := backend.(resource.TrashPurger)
+		if ok {
+			collections, err := purger.ListTrashCollections(ctx, before)
+			require.NoError(t, err)
+			found := []string{}
+			for _, c := range collections {
+				found = append(found, resource.NSGR(c))
+			}
+			require.Contains(t, found, resource.NSGR(dashboards))
+		}
+
+		rsp, err := server.PurgeTrash(ctx, &resourcepb.PurgeTrashRequest{
+			Namespace:     ns,
+			Resources:     []*resourcepb.ResourceKey{dashboards},
+			DeletedBefore: before.UnixMilli(),
+		})
+		require.NoError(t, err)
+		if !ok {
+			require.NotNil(t, rsp.Error)
+			require.Equal(t, int32(http.StatusNotImplemented), rsp.Error.Code)
+			return
+		}
+		require.Nil(t, rsp.Error)
+		require.Greater(t, rsp.Purged, int64(0))
+		require.Empty(t, listTrash())
+
+		restored, err := server.RestoreFromTrash(ctx, &resourcepb.RestoreFromTrashRequest{Key: keyFor(dashboards, "dash")})
+		require.NoError(t, err)
+		require.NotNil(t, restored.Error)
+	})
+}
+
+func runTestIntegrationBackendWriteEventPreconditions(t *testing.T, backend resource.StorageBackend, nsPrefix string) {
+	ctx := testutil.NewTestContext(t, time.Now().Add(5*time.Second))
+	ns := nsPrefix + "-write-preconditions"
+
+	t.Run("missing resources can not be modified or deleted", func(t *testing.T) {
+		_, err := writeEvent(ctx, backend, "missing", resourcepb.WatchEvent_MODIFIED, WithNamespace(ns))
+		require.True(t, apierrors.IsNotFound(err), "modify a missing resource: %v", err)
+
+		_, err = writeEvent(ctx, backend, "missing", resourcepb.WatchEvent_DELETED, WithNamespace(ns))
+		require.True(t, apierrors.IsNotFound(err), "delete a missing resource: %v", err)
+	})
+
+	t.Run("existing resources can not be added again or changed from an old version", func(t *testing.T) {
+		_, err := writeEvent(ctx, backend, "item1", resourcepb.WatchEvent_ADDED, WithNamespace(ns))
+		require.NoError(t, err)
+
+		_, err = writeEvent(ctx, backend, "item1", resourcepb.WatchEvent_ADDED, WithNamespace(ns))
+		require.ErrorIs(t, err, resource.ErrResourceAlreadyExists)
+
+		rv, err := writeEvent(ctx, backend, "item1", resourcepb.WatchEvent_MODIFIED, WithNamespace(ns))
+		require.NoError(t, err)
+		_, err = writeEvent(ctx, backend, "item1", resourcepb.WatchEvent_MODIFIED, WithNamespace(ns), WithPreviousRV(rv-1))
+		require.Error(t, err)
+	})
+
+	t.Run("deleted resources can only be added again", func(t *testing.T) {
+		_, err := writeEvent(ctx, backend, "item2", resourcepb.WatchEvent_ADDED, WithNamespace(ns))
+		require.NoError(t, err)
+		_, err = writeEvent(ctx, backend, "item2", resourcepb.WatchEvent_DELETED, WithNamespace(ns))
+		require.NoError(t, err)
+
+		_, err = writeEvent(ctx, backend, "item2", resourcepb.WatchEvent_MODIFIED, WithNamespace(ns))
+		require.True(t, apierrors.IsNotFound(err), "modify a deleted resource: %v", err)
+		_, err = writeEvent(ctx, backend, "item2", resourcepb.WatchEvent_DELETED, WithNamespace(ns))
+		require.True(t, apierrors.IsNotFound(err), "delete a deleted resource: %v", err)
+
+		_, err = writeEvent(ctx, backend, "item2", resourcepb.WatchEvent_ADDED, WithNamespace(ns))
+		require.NoError(t, err)
+	})
+}
+
 // WriteEventOption is a function that modifies WriteEventOptions
 type WriteEventOption func(*WriteEventOptions)
 
@@ -1058,12 +1223,20 @@ func WithValue(value string) WriteEventOption {
 	}
 }
 
+// WithPreviousRV sets the resource version the write event expects to replace
+func WithPreviousRV(rv int64) WriteEventOption {
+	return func(o *WriteEventOptions) {
+		o.PreviousRV = rv
+	}
+}
+
 type WriteEventOptions struct {
-	Namespace string
-	Group     string
-	Resource  string
-	Folder    string
-	Value     []byte
+	Namespace  string
+	Group      string
+	Resource   string
+	Folder     string
+	Value      []byte
+	PreviousRV int64
 }
 
 func writeEvent(ctx context.Context, store resource.StorageBackend, name string, action resourcepb.WatchEvent_Type, opts ...WriteEventOption) (int64, error) {
@@ -1106,9 +1279,10 @@ func writeEvent(ctx context.Context, store resource.StorageBackend, name string,
 	meta.SetFolder(options.Folder)
 
 	return store.WriteEvent(ctx, resource.WriteEvent{
-		Type:  action,
-		Value: options.Value,
-		GUID:  uuid.New().String(),
+		Type:       action,
+		Value:      options.Value,
+		GUID:       uuid.New().String(),
+		PreviousRV: options.PreviousRV,
 		Key: &resourcepb.ResourceKey{
 			Namespace: options.Namespace,
 			Group:     options.Group,
@@ -1136,16 +1310,19 @@ func runTestIntegrationBackendTrash(t *testing.T, backend resource.StorageBacken
 	server := newServer(t, backend)
 	ns := nsPrefix + "-ns-trash"
 
-	// item1 deleted with multiple history events
+	// item1 deleted twice, with multiple delete events in its history
 	rv1, err := writeEvent(ctx, backend, "item1", resourcepb.WatchEvent_ADDED, WithNamespace(ns))
 	require.NoError(t, err)
 	require.Greater(t, rv1, int64(0))
 	rvDelete1, err := writeEvent(ctx, backend, "item1", resourcepb.WatchEvent_DELETED, WithNamespace(ns))
 	require.NoError(t, err)
 	require.Greater(t, rvDelete1, rv1)
+	rvRecreate1, err := writeEvent(ctx, backend, "item1", resourcepb.WatchEvent_ADDED, WithNamespace(ns))
+	require.NoError(t, err)
+	require.Greater(t, rvRecreate1, rvDelete1)
 	rvDelete2, err := writeEvent(ctx, backend, "item1", resourcepb.WatchEvent_DELETED, WithNamespace(ns))
 	require.NoError(t, err)
-	require.Greater(t, rvDelete2, rvDelete1)
+	require.Greater(t, rvDelete2, rvRecreate1)
 
 	// item2 deleted and recreated, should not be returned in trash
 	rv2, err := writeEvent(ctx, backend, "item2", resourcepb.WatchEvent_ADDED, WithNamespace(ns))
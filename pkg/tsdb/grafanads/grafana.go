@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/live/managedstream"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/store"
 	testdatasource "github.com/grafana/grafana/pkg/tsdb/grafana-testdata-datasource"
@@ -52,14 +53,15 @@ var (
 	)
 )
 
-func ProvideService(search searchV2.SearchService, store store.StorageService, features featuremgmt.FeatureToggles) *Service {
-	return newService(search, store, features)
+func ProvideService(search searchV2.SearchService, store store.StorageService, features featuremgmt.FeatureToggles, history managedstream.FrameHistory) *Service {
+	return newService(search, store, features, history)
 }
 
-func newService(search searchV2.SearchService, store store.StorageService, features featuremgmt.FeatureToggles) *Service {
+func newService(search searchV2.SearchService, store store.StorageService, features featuremgmt.FeatureToggles, history managedstream.FrameHistory) *Service {
 	s := &Service{
 		search:   search,
 		store:    store,
+		history:  history,
 		log:      log.New("grafanads"),
 		features: features,
 	}
@@ -71,6 +73,7 @@ func newService(search searchV2.SearchService, store store.StorageService, featu
 type Service struct {
 	search   searchV2.SearchService
 	store    store.StorageService
+	history  managedstream.FrameHistory
 	log      log.Logger
 	features featuremgmt.FeatureToggles
 }
@@ -100,6 +103,8 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 			response.Responses[q.RefID] = s.doReadQuery(ctx, q)
 		case queryTypeSearch, queryTypeSearchNext:
 			response.Responses[q.RefID] = s.doSearchQuery(ctx, req, q)
+		case queryTypeLiveHistory:
+			response.Responses[q.RefID] = s.doLiveHistoryQuery(ctx, req, q)
 		default:
 			response.Responses[q.RefID] = backend.DataResponse{
 				Error: fmt.Errorf("unknown query type"),
@@ -166,6 +171,39 @@ func (s *Service) doReadQuery(ctx context.Context, query backend.DataQuery) back
 	return response
 }
 
+func (s *Service) doLiveHistoryQuery(ctx context.Context, req *backend.QueryDataRequest, query backend.DataQuery) backend.DataResponse {
+	q := &liveHistoryQueryModel{}
+	response := backend.DataResponse{}
+	err := json.Unmarshal(query.JSON, &q)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+	if s.history == nil {
+		response.Error = fmt.Errorf("live history is disabled")
+		return response
+	}
+	if q.Channel == "" {
+		response.Error = fmt.Errorf("missing channel")
+		return response
+	}
+
+	frames, err := s.history.Get(ctx, req.PluginContext.OrgID, q.Channel, query.TimeRange.From, query.TimeRange.To)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+	frame, err := managedstream.MergeHistoryFrames(frames, int(query.MaxDataPoints))
+	if err != nil {
+		response.Error = err
+		return response
+	}
+	if frame != nil {
+		response.Frames = data.Frames{frame}
+	}
+	return response
+}
+
 func (s *Service) doRandomWalk(query backend.DataQuery) backend.DataResponse {
 	response := backend.DataResponse{}
 
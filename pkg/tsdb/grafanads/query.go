@@ -17,6 +17,10 @@ const (
 	// currently only .csv files are supported,
 	// other file types will eventually be supported (parquet, etc)
 	queryTypeRead = "read"
+
+	// queryTypeLiveHistory returns the recent frames of a Live managed stream
+	// channel in the time range, merged in a single frame
+	queryTypeLiveHistory = "liveHistory"
 )
 
 type listQueryModel struct {
@@ -25,3 +29,7 @@ type listQueryModel struct {
 type readQueryModel struct {
 	Path string `json:"path"`
 }
+
+type liveHistoryQueryModel struct {
+	Channel string `json:"channel"`
+}
@@ -0,0 +1,72 @@
+package graphite
+
+import (
+	"sync"
+	"time"
+)
+
+// resourceCache keeps the responses of the resource calls of a data source for
+// a TTL. Data source instances are created again when their settings change,
+// with an empty cache.
+type resourceCache struct {
+	mu         sync.Mutex
+	entries    map[string]resourceCacheEntry
+	maxEntries int
+	now        func() time.Time
+}
+
+type resourceCacheEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+func newResourceCache(maxEntries int) *resourceCache {
+	return &resourceCache{
+		entries:    make(map[string]resourceCacheEntry),
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+func (c *resourceCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *resourceCache) set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+	c.entries[key] = resourceCacheEntry{value: value, expires: now.Add(ttl)}
+}
+
+// evict removes the expired entries, or the entry expiring first when none
+// expired.
+func (c *resourceCache) evict(now time.Time) {
+	var first string
+	var firstExpires time.Time
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, key)
+			continue
+		}
+		if first == "" || entry.expires.Before(firstExpires) {
+			first, firstExpires = key, entry.expires
+		}
+	}
+	if len(c.entries) >= c.maxEntries && first != "" {
+		delete(c.entries, first)
+	}
+}
@@ -51,6 +51,8 @@ type datasourceInfo struct {
 	HTTPClient *http.Client
 	URL        string
 	Id         int64
+	// resourceCache is shared by the copies of the instance.
+	resourceCache *resourceCache
 }
 
 func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.InstanceFactoryFunc {
@@ -69,6 +71,8 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			HTTPClient: client,
 			URL:        settings.URL,
 			Id:         settings.ID,
+
+			resourceCache: newResourceCache(resourceCacheMaxEntries),
 		}
 
 		return model, nil
@@ -97,13 +101,33 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		return nil, err
 	}
 
+	// The targets of the queries by refId, to expand the references to other
+	// queries.
+	targets := make(map[string]string, len(req.Queries))
+	for _, query := range req.Queries {
+		model, err := simplejson.NewJson(query.JSON)
+		if err != nil {
+			continue
+		}
+		if target := model.Get(TargetModelField).MustString(); target != "" {
+			targets[query.RefID] = target
+		}
+	}
+
+	invalidQueries := make(backend.Responses)
 	emptyQueries := []string{}
 	graphiteQueries := map[string]struct {
 		req      *http.Request
 		formData url.Values
 	}{}
 	for _, query := range req.Queries {
-		graphiteReq, formData, emptyQuery, err := s.createGraphiteRequest(ctx, query, logger, dsInfo)
+		graphiteReq, formData, emptyQuery, err := s.createGraphiteRequest(ctx, query, targets, logger, dsInfo)
+		var targetErr *TargetError
+		if errors.As(err, &targetErr) {
+			logger.Debug("Invalid Graphite target references", "refId", query.RefID, "error", err)
+			invalidQueries[query.RefID] = backend.ErrDataResponseWithSource(http.StatusBadRequest, backend.ErrorSourceDownstream, err.Error())
+			continue
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -181,7 +205,7 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	}
 
 	result = backend.QueryDataResponse{
-		Responses: make(backend.Responses),
+		Responses: invalidQueries,
 	}
 
 	for _, f := range frames {
@@ -199,8 +223,10 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 }
 
 // processQuery converts a Graphite data source query to a Graphite query target. It returns the target,
-// and the model if the target is invalid
-func (s *Service) processQuery(logger log.Logger, query backend.DataQuery) (string, *simplejson.Json, error) {
+// and the model if the target is empty. Targets without targetFull, as in alert and recording rules, have
+// their references to the other targets expanded, circular references return a *TargetError. The targets
+// are not validated, Graphite reports the errors of the invalid ones.
+func (s *Service) processQuery(logger log.Logger, query backend.DataQuery, targets map[string]string) (string, *simplejson.Json, error) {
 	model, err := simplejson.NewJson(query.JSON)
 	if err != nil {
 		return "", nil, err
@@ -211,6 +237,11 @@ func (s *Service) processQuery(logger log.Logger, query backend.DataQuery) (stri
 		currTarget = fullTarget
 	} else {
 		currTarget = model.Get(TargetModelField).MustString()
+		if currTarget != "" {
+			if currTarget, err = expandSeriesRefs(query.RefID, currTarget, targets); err != nil {
+				return "", nil, err
+			}
+		}
 	}
 	if currTarget == "" {
 		logger.Debug("Graphite", "empty query target", model)
@@ -238,7 +269,7 @@ func (s *Service) createRequest(ctx context.Context, l log.Logger, dsInfo *datas
 	return req, err
 }
 
-func (s *Service) createGraphiteRequest(ctx context.Context, query backend.DataQuery, logger log.Logger, dsInfo *datasourceInfo) (*http.Request, url.Values, *simplejson.Json, error) {
+func (s *Service) createGraphiteRequest(ctx context.Context, query backend.DataQuery, targets map[string]string, logger log.Logger, dsInfo *datasourceInfo) (*http.Request, url.Values, *simplejson.Json, error) {
 	/*
 		graphite doc about from and until, with sdk we are getting absolute instead of relative time
 		https://graphite-api.readthedocs.io/en/latest/api.html#from-until
@@ -252,7 +283,7 @@ func (s *Service) createGraphiteRequest(ctx context.Context, query backend.DataQ
 		"target":        []string{},
 	}
 
-	target, emptyQuery, err := s.processQuery(logger, query)
+	target, emptyQuery, err := s.processQuery(logger, query, targets)
 	if err != nil {
 		return nil, formData, nil, err
 	}
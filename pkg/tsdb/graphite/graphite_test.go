@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -69,7 +70,7 @@ func TestProcessQuery(t *testing.T) {
 				}`),
 			},
 		}
-		target, jsonModel, err := service.processQuery(log, queries[0])
+		target, jsonModel, err := service.processQuery(log, queries[0], nil)
 		assert.NoError(t, err)
 		assert.Nil(t, jsonModel)
 		assert.Equal(t, "app.grafana.*.dashboards.views.1M.count", target)
@@ -85,12 +86,82 @@ func TestProcessQuery(t *testing.T) {
 			},
 		}
 		jsonEqual, _ := simplejson.NewJson([]byte(`{"target": ""}`))
-		target, jsonModel, err := service.processQuery(log, queries[0])
+		target, jsonModel, err := service.processQuery(log, queries[0], nil)
 		assert.NoError(t, err)
 		assert.Equal(t, jsonEqual, jsonModel)
 		assert.Equal(t, "", target)
 	})
 
+	t.Run("Expands references to other targets without targetFull", func(t *testing.T) {
+		query := backend.DataQuery{
+			RefID: "B",
+			JSON: []byte(`{
+				"target": "asPercent(#A, sumSeries(#A))"
+			}`),
+		}
+		targets := map[string]string{"A": "app.*.count", "B": "asPercent(#A, sumSeries(#A))"}
+		target, jsonModel, err := service.processQuery(log, query, targets)
+		assert.NoError(t, err)
+		assert.Nil(t, jsonModel)
+		assert.Equal(t, "asPercent(app.*.count, sumSeries(app.*.count))", target)
+	})
+
+	t.Run("Sends the targets the parser does not support to Graphite", func(t *testing.T) {
+		query := backend.DataQuery{
+			RefID: "B",
+			JSON: []byte(`{
+				"target": "sumSeries(#A"
+			}`),
+		}
+		target, _, err := service.processQuery(log, query, map[string]string{"A": "app.*.count"})
+		require.NoError(t, err)
+		assert.Equal(t, "sumSeries(#A", target)
+	})
+
+	t.Run("Returns a target error for circular references", func(t *testing.T) {
+		query := backend.DataQuery{
+			RefID: "A",
+			JSON: []byte(`{
+				"target": "sumSeries(#B)"
+			}`),
+		}
+		_, _, err := service.processQuery(log, query, map[string]string{"A": "sumSeries(#B)", "B": "sumSeries(#A)"})
+		var targetErr *TargetError
+		require.ErrorAs(t, err, &targetErr)
+	})
+
+	t.Run("QueryData sends the targets the parser does not support to Graphite", func(t *testing.T) {
+		var mu sync.Mutex
+		var received []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.NoError(t, r.ParseForm())
+			mu.Lock()
+			received = append(received, r.Form.Get("target"))
+			mu.Unlock()
+			_, _ = w.Write([]byte(`[{"target": "target", "datapoints": [[50, 1]]}]`))
+		}))
+		t.Cleanup(server.Close)
+
+		service := ProvideService(httpclient.NewProvider(), tracing.NewNoopTracerService())
+
+		rsp, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+					ID:  0,
+					URL: server.URL,
+				},
+			},
+			Queries: []backend.DataQuery{
+				{RefID: "A", JSON: []byte(`{"target": "sumSeries(app.*.count"}`)},
+				{RefID: "B", JSON: []byte(`{"target": "app.*.count"}`)},
+			},
+		})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"sumSeries(app.*.count", "app.*.count"}, received)
+		assert.Len(t, rsp.Responses["A"].Frames, 1)
+		assert.Len(t, rsp.Responses["B"].Frames, 1)
+	})
+
 	t.Run("QueryData with no valid queries returns bad request response", func(t *testing.T) {
 		queries := []backend.DataQuery{
 			{
@@ -0,0 +1,354 @@
+package graphite
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Expression is a parsed Graphite target expression.
+type Expression struct {
+	// Kind of the expression, one of the ExpressionKind* constants.
+	Kind string
+	// Value is the metric path, function name, string, number, bool or refId.
+	Value string
+	// Args are the arguments of a function call.
+	Args []Argument
+	// Pos and End are the offsets of the expression in the target.
+	Pos int
+	End int
+}
+
+// Argument is a function call argument, Key is set for keyword arguments.
+type Argument struct {
+	Key   string
+	Value *Expression
+}
+
+const (
+	ExpressionKindPath      = "path"
+	ExpressionKindFunction  = "function"
+	ExpressionKindString    = "string"
+	ExpressionKindNumber    = "number"
+	ExpressionKindBool      = "bool"
+	ExpressionKindSeriesRef = "seriesRef"
+)
+
+// TargetError is returned for invalid targets, Pos is the offset of the error.
+type TargetError struct {
+	Target string
+	Pos    int
+	Msg    string
+}
+
+func (e *TargetError) Error() string {
+	return fmt.Sprintf("invalid graphite target: %s at column %d", e.Msg, e.Pos+1)
+}
+
+var (
+	numberRegexp     = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+	identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	seriesRefRegexp  = regexp.MustCompile(`^#[A-Z][A-Za-z0-9_]*$`)
+)
+
+// ParseTarget parses a Graphite target. It only reports errors making the
+// target invalid for Graphite, as unbalanced parentheses or unterminated
+// strings, function names are not checked. Piped calls, as a.b|alias('x'),
+// are parsed as calls with the piped expression as first argument.
+func ParseTarget(target string) (*Expression, error) {
+	p := &parser{target: target}
+	p.skipSpaces()
+	if p.eof() {
+		return nil, p.errorf(p.pos, "empty target")
+	}
+	expr, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if !p.eof() {
+		return nil, p.errorf(p.pos, "unexpected %q", p.peek())
+	}
+	return expr, nil
+}
+
+// Walk calls fn for the expression and its arguments, depth first.
+func (e *Expression) Walk(fn func(*Expression)) {
+	fn(e)
+	for _, arg := range e.Args {
+		arg.Value.Walk(fn)
+	}
+}
+
+// Functions returns the names of the functions called by the expression.
+func (e *Expression) Functions() []string {
+	var names []string
+	e.Walk(func(expr *Expression) {
+		if expr.Kind == ExpressionKindFunction {
+			names = append(names, expr.Value)
+		}
+	})
+	return names
+}
+
+type parser struct {
+	target string
+	pos    int
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.target)
+}
+
+func (p *parser) peek() byte {
+	return p.target[p.pos]
+}
+
+func (p *parser) skipSpaces() {
+	for !p.eof() && unicode.IsSpace(rune(p.peek())) {
+		p.pos++
+	}
+}
+
+func (p *parser) errorf(pos int, format string, args ...any) error {
+	return &TargetError{Target: p.target, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// isPathChar reports if c can be in a metric path, braces are handled when
+// scanning paths as the commas they contain are not separators, and backslashes
+// as they escape the next character.
+func isPathChar(c byte) bool {
+	switch c {
+	case '(', ')', ',', '=', '\'', '"', '{', '}', '|', '\\':
+		return false
+	}
+	return (c > ' ' && c < 0x7f) || c >= 0x80
+}
+
+// parseExpression parses an expression followed by the calls it is piped to.
+func (p *parser) parseExpression() (*Expression, error) {
+	expr, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		afterExpr := p.pos
+		p.skipSpaces()
+		if p.eof() || p.peek() != '|' {
+			p.pos = afterExpr
+			return expr, nil
+		}
+		pipe := p.pos
+		p.pos++
+		p.skipSpaces()
+		if p.eof() {
+			return nil, p.errorf(pipe, "expected function call after '|'")
+		}
+		call, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if call.Kind != ExpressionKindFunction {
+			return nil, p.errorf(call.Pos, "expected function call after '|'")
+		}
+		call.Args = append([]Argument{{Value: expr}}, call.Args...)
+		call.Pos = expr.Pos
+		expr = call
+	}
+}
+
+func (p *parser) parseOperand() (*Expression, error) {
+	start := p.pos
+	if p.eof() {
+		return nil, p.errorf(p.pos, "expected expression")
+	}
+	switch c := p.peek(); c {
+	case '\'', '"':
+		return p.parseString(c)
+	case '(', ')', ',', '=', '|':
+		return nil, p.errorf(p.pos, "unexpected %q", c)
+	}
+
+	token, err := p.scanPath()
+	if err != nil {
+		return nil, err
+	}
+	expr := &Expression{Kind: ExpressionKindPath, Value: token, Pos: start, End: p.pos}
+	switch {
+	case numberRegexp.MatchString(token):
+		expr.Kind = ExpressionKindNumber
+	case strings.EqualFold(token, "true") || strings.EqualFold(token, "false"):
+		expr.Kind = ExpressionKindBool
+	case seriesRefRegexp.MatchString(token):
+		expr.Kind = ExpressionKindSeriesRef
+		expr.Value = token[1:]
+	}
+
+	afterToken := p.pos
+	p.skipSpaces()
+	if p.eof() || p.peek() != '(' {
+		p.pos = afterToken
+		return expr, nil
+	}
+	if !identifierRegexp.MatchString(token) {
+		return nil, p.errorf(start, "invalid function name %q", token)
+	}
+	expr.Kind = ExpressionKindFunction
+	if err := p.parseArguments(expr); err != nil {
+		return nil, err
+	}
+	expr.End = p.pos
+	return expr, nil
+}
+
+// scanPath scans a metric path, as well as function names, numbers and bools.
+func (p *parser) scanPath() (string, error) {
+	start := p.pos
+	for !p.eof() {
+		c := p.peek()
+		if c == '{' {
+			end := strings.IndexAny(p.target[p.pos+1:], "{}()")
+			if end < 0 || p.target[p.pos+1+end] != '}' {
+				return "", p.errorf(p.pos, "unterminated '{'")
+			}
+			p.pos += end + 2
+			continue
+		}
+		if c == '}' {
+			return "", p.errorf(p.pos, "unexpected '}'")
+		}
+		if c == '\\' {
+			if p.pos+1 >= len(p.target) {
+				return "", p.errorf(p.pos, "unterminated escape")
+			}
+			p.pos += 2
+			continue
+		}
+		if !isPathChar(c) {
+			break
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorf(p.pos, "expected expression")
+	}
+	return p.target[start:p.pos], nil
+}
+
+func (p *parser) parseString(quote byte) (*Expression, error) {
+	start := p.pos
+	p.pos++
+	for !p.eof() {
+		c := p.peek()
+		if c == '\\' {
+			p.pos += 2
+			continue
+		}
+		p.pos++
+		if c == quote {
+			return &Expression{Kind: ExpressionKindString, Value: p.target[start+1 : p.pos-1], Pos: start, End: p.pos}, nil
+		}
+	}
+	return nil, p.errorf(start, "unterminated string")
+}
+
+func (p *parser) parseArguments(call *Expression) error {
+	open := p.pos
+	p.pos++ // (
+	p.skipSpaces()
+	if !p.eof() && p.peek() == ')' {
+		p.pos++
+		return nil
+	}
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			return p.errorf(open, "missing ')' for %s", call.Value)
+		}
+		if c := p.peek(); c == ',' || c == ')' {
+			return p.errorf(p.pos, "missing argument of %s", call.Value)
+		}
+		value, err := p.parseExpression()
+		if err != nil {
+			return err
+		}
+		arg := Argument{Value: value}
+		p.skipSpaces()
+		if !p.eof() && p.peek() == '=' {
+			if value.Kind != ExpressionKindPath || !identifierRegexp.MatchString(value.Value) {
+				return p.errorf(p.pos, "unexpected '='")
+			}
+			p.pos++
+			p.skipSpaces()
+			if p.eof() {
+				return p.errorf(open, "missing ')' for %s", call.Value)
+			}
+			if c := p.peek(); c == ',' || c == ')' {
+				return p.errorf(p.pos, "missing value of %s", value.Value)
+			}
+			arg.Key = value.Value
+			if arg.Value, err = p.parseExpression(); err != nil {
+				return err
+			}
+			p.skipSpaces()
+		}
+		call.Args = append(call.Args, arg)
+		if p.eof() {
+			return p.errorf(open, "missing ')' for %s", call.Value)
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return nil
+		default:
+			return p.errorf(p.pos, "expected ',' or ')' in %s", call.Value)
+		}
+	}
+}
+
+// expandSeriesRefs replaces the references to other queries, as #A, by their
+// targets. The frontend sends the expanded target as targetFull, queries saved
+// without it, as in alert and recording rules, are expanded here. Targets the
+// parser does not support are left as they are for Graphite.
+func expandSeriesRefs(refID string, target string, targets map[string]string) (string, error) {
+	return expandSeriesRefsVisited(target, targets, map[string]bool{refID: true})
+}
+
+func expandSeriesRefsVisited(target string, targets map[string]string, visited map[string]bool) (string, error) {
+	if !strings.Contains(target, "#") {
+		return target, nil
+	}
+	expr, err := ParseTarget(target)
+	if err != nil {
+		logger.Debug("Graphite target references not expanded", "target", target, "error", err)
+		return target, nil
+	}
+	var refs []*Expression
+	expr.Walk(func(e *Expression) {
+		if e.Kind == ExpressionKindSeriesRef {
+			refs = append(refs, e)
+		}
+	})
+	// Replace from the end, so the offsets of the previous refs do not change.
+	for i := len(refs) - 1; i >= 0; i-- {
+		ref := refs[i]
+		refTarget, ok := targets[ref.Value]
+		if !ok {
+			continue
+		}
+		if visited[ref.Value] {
+			return "", &TargetError{Target: target, Pos: ref.Pos, Msg: fmt.Sprintf("circular reference to #%s", ref.Value)}
+		}
+		visited[ref.Value] = true
+		expanded, err := expandSeriesRefsVisited(refTarget, targets, visited)
+		if err != nil {
+			return "", err
+		}
+		delete(visited, ref.Value)
+		target = target[:ref.Pos] + expanded + target[ref.End:]
+	}
+	return target, nil
+}
@@ -0,0 +1,141 @@
+package graphite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTarget(t *testing.T) {
+	t.Run("Parses valid targets", func(t *testing.T) {
+		targets := []string{
+			"app.grafana.*.dashboards.views.1M.count",
+			"app.{web,api}.requests.count",
+			"app.[ab]*.requests.count",
+			"seriesByTag('name=app.requests', 'env=~prod|dev')",
+			"aliasByNode(sumSeries(app.*.count), 1)",
+			"summarize(app.count, '1min', \"sum\", false)",
+			"highestAverage(app.*.count, n=5)",
+			"groupByTags(app.*.count, 'sum', 'env', 'dc')",
+			"movingAverage( app.count , -1.5e3 )",
+			"asPercent(#A, #B)",
+			"timeShift(app.count, '-1d')",
+			"constantLine(100)",
+			"randomWalk()",
+			"alias(app.count, 'it\\'s')",
+			"app.count|alias('x')",
+			"app.*.count | sumSeries() | scale(2)",
+			"alias(app.count|scale(2), 'x')",
+			"#A|alias('x')",
+			"app.requests\\(GET\\).count",
+			"app.a\\,b.count",
+			"alias(app.user\\=admin.count, 'x')",
+		}
+		for _, target := range targets {
+			_, err := ParseTarget(target)
+			assert.NoError(t, err, target)
+		}
+	})
+
+	t.Run("Parses the function calls and arguments", func(t *testing.T) {
+		expr, err := ParseTarget("aliasByNode(sumSeries(app.*.count, #A), 1, key='x')")
+		require.NoError(t, err)
+		assert.Equal(t, ExpressionKindFunction, expr.Kind)
+		assert.Equal(t, "aliasByNode", expr.Value)
+		require.Len(t, expr.Args, 3)
+		assert.Equal(t, ExpressionKindFunction, expr.Args[0].Value.Kind)
+		assert.Equal(t, ExpressionKindPath, expr.Args[0].Value.Args[0].Value.Kind)
+		assert.Equal(t, ExpressionKindSeriesRef, expr.Args[0].Value.Args[1].Value.Kind)
+		assert.Equal(t, "A", expr.Args[0].Value.Args[1].Value.Value)
+		assert.Equal(t, ExpressionKindNumber, expr.Args[1].Value.Kind)
+		assert.Equal(t, "key", expr.Args[2].Key)
+		assert.Equal(t, ExpressionKindString, expr.Args[2].Value.Kind)
+		assert.Equal(t, "x", expr.Args[2].Value.Value)
+		assert.Equal(t, []string{"aliasByNode", "sumSeries"}, expr.Functions())
+	})
+
+	t.Run("Parses piped calls as calls with the piped expression as first argument", func(t *testing.T) {
+		expr, err := ParseTarget("app.*.count|sumSeries()|alias('x')")
+		require.NoError(t, err)
+		assert.Equal(t, "alias", expr.Value)
+		assert.Equal(t, 0, expr.Pos)
+		require.Len(t, expr.Args, 2)
+		assert.Equal(t, "sumSeries", expr.Args[0].Value.Value)
+		require.Len(t, expr.Args[0].Value.Args, 1)
+		assert.Equal(t, "app.*.count", expr.Args[0].Value.Args[0].Value.Value)
+		assert.Equal(t, "x", expr.Args[1].Value.Value)
+	})
+
+	t.Run("Keeps the escaped characters of paths", func(t *testing.T) {
+		expr, err := ParseTarget("alias(app.requests\\(GET\\).count, 'x')")
+		require.NoError(t, err)
+		assert.Equal(t, ExpressionKindPath, expr.Args[0].Value.Kind)
+		assert.Equal(t, "app.requests\\(GET\\).count", expr.Args[0].Value.Value)
+	})
+
+	t.Run("Returns errors for invalid targets", func(t *testing.T) {
+		tests := []struct {
+			target string
+			pos    int
+			msg    string
+		}{
+			{target: "", pos: 0, msg: "empty target"},
+			{target: "sumSeries(app.count", pos: 9, msg: "missing ')' for sumSeries"},
+			{target: "sumSeries(app.count))", pos: 20, msg: "unexpected ')'"},
+			{target: "sumSeries(app.count,)", pos: 20, msg: "missing argument of sumSeries"},
+			{target: "alias(app.count, 'name)", pos: 17, msg: "unterminated string"},
+			{target: "app.{web,api.count", pos: 4, msg: "unterminated '{'"},
+			{target: "app.count(1)", pos: 0, msg: "invalid function name \"app.count\""},
+			{target: "alias(app.count 'name')", pos: 16, msg: "expected ',' or ')' in alias"},
+			{target: "highestAverage(app.count, n=)", pos: 28, msg: "missing value of n"},
+			{target: "app.count|", pos: 9, msg: "expected function call after '|'"},
+			{target: "app.count|other.count", pos: 10, msg: "expected function call after '|'"},
+			{target: "|alias('x')", pos: 0, msg: "unexpected '|'"},
+			{target: "app.count\\", pos: 9, msg: "unterminated escape"},
+		}
+		for _, tt := range tests {
+			_, err := ParseTarget(tt.target)
+			var targetErr *TargetError
+			require.ErrorAs(t, err, &targetErr, tt.target)
+			assert.Equal(t, tt.pos, targetErr.Pos, tt.target)
+			assert.Equal(t, tt.msg, targetErr.Msg, tt.target)
+		}
+	})
+}
+
+func TestExpandSeriesRefs(t *testing.T) {
+	targets := map[string]string{
+		"A": "app.*.count",
+		"B": "sumSeries(#A)",
+		"C": "asPercent(#A, #B)",
+		"D": "sumSeries(#E)",
+		"E": "sumSeries(#D)",
+	}
+
+	target, err := expandSeriesRefs("C", targets["C"], targets)
+	require.NoError(t, err)
+	assert.Equal(t, "asPercent(app.*.count, sumSeries(app.*.count))", target)
+
+	// Unknown references are left for Graphite.
+	target, err = expandSeriesRefs("F", "sumSeries(#Z)", targets)
+	require.NoError(t, err)
+	assert.Equal(t, "sumSeries(#Z)", target)
+
+	target, err = expandSeriesRefs("F", "#A|alias('x')", targets)
+	require.NoError(t, err)
+	assert.Equal(t, "app.*.count|alias('x')", target)
+
+	// Targets the parser does not support are left for Graphite.
+	target, err = expandSeriesRefs("F", "sumSeries(#A", targets)
+	require.NoError(t, err)
+	assert.Equal(t, "sumSeries(#A", target)
+
+	_, err = expandSeriesRefs("D", targets["D"], targets)
+	var targetErr *TargetError
+	require.ErrorAs(t, err, &targetErr)
+	assert.Equal(t, "circular reference to #D", targetErr.Msg)
+
+	_, err = expandSeriesRefs("A", "sumSeries(#A)", map[string]string{"A": "sumSeries(#A)"})
+	require.ErrorAs(t, err, &targetErr)
+}
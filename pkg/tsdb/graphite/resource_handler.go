@@ -0,0 +1,253 @@
+package graphite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+)
+
+const (
+	resourceCacheMaxEntries = 1000
+	metricFindCacheTTL      = time.Minute
+	tagsCacheTTL            = time.Minute
+	// The functions only change with the Graphite version.
+	functionsCacheTTL = time.Hour
+)
+
+// Graphite returns Infinity as the default of some function parameters, which
+// is not valid JSON. It is replaced by 1e9999 as in the frontend.
+var infinityRegexp = regexp.MustCompile(`:\s*(-?)Infinity\b`)
+
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	handler := httpadapter.New(s.registerResourceRoutes())
+	return handler.CallResource(ctx, req, sender)
+}
+
+func (s *Service) registerResourceRoutes() *http.ServeMux {
+	router := http.NewServeMux()
+	router.HandleFunc("GET /metrics/find", s.withDatasourceHandlerFunc(s.handleMetricFind))
+	router.HandleFunc("GET /tags/autoComplete/tags", s.withDatasourceHandlerFunc(s.handleTagsAutoComplete))
+	router.HandleFunc("GET /tags/autoComplete/values", s.withDatasourceHandlerFunc(s.handleTagValuesAutoComplete))
+	router.HandleFunc("GET /functions", s.withDatasourceHandlerFunc(s.handleFunctions))
+	router.HandleFunc("POST /validate", s.withDatasourceHandlerFunc(s.handleValidate))
+	return router
+}
+
+func (s *Service) withDatasourceHandlerFunc(handler func(rw http.ResponseWriter, r *http.Request, dsInfo *datasourceInfo)) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		dsInfo, err := s.getDSInfo(r.Context(), backend.PluginConfigFromContext(r.Context()))
+		if err != nil {
+			logger.Error("Failed to get data source info", "error", err)
+			http.Error(rw, "error getting data source information from context", http.StatusInternalServerError)
+			return
+		}
+		handler(rw, r, dsInfo)
+	}
+}
+
+// handleMetricFind returns the metrics matching the query, as /metrics/find.
+func (s *Service) handleMetricFind(rw http.ResponseWriter, r *http.Request, dsInfo *datasourceInfo) {
+	params := filterParams(r.URL.Query(), "query", "from", "until")
+	if params.Get("query") == "" {
+		http.Error(rw, "query is required", http.StatusBadRequest)
+		return
+	}
+	s.proxyCached(rw, r, dsInfo, "metrics/find", params, metricFindCacheTTL)
+}
+
+// handleTagsAutoComplete returns the tags matching the prefix and expressions.
+func (s *Service) handleTagsAutoComplete(rw http.ResponseWriter, r *http.Request, dsInfo *datasourceInfo) {
+	params := filterParams(r.URL.Query(), "tagPrefix", "expr", "limit")
+	s.proxyCached(rw, r, dsInfo, "tags/autoComplete/tags", params, tagsCacheTTL)
+}
+
+// handleTagValuesAutoComplete returns the values of the tag matching the
+// prefix and expressions.
+func (s *Service) handleTagValuesAutoComplete(rw http.ResponseWriter, r *http.Request, dsInfo *datasourceInfo) {
+	params := filterParams(r.URL.Query(), "tag", "valuePrefix", "expr", "limit")
+	if params.Get("tag") == "" {
+		http.Error(rw, "tag is required", http.StatusBadRequest)
+		return
+	}
+	s.proxyCached(rw, r, dsInfo, "tags/autoComplete/values", params, tagsCacheTTL)
+}
+
+// handleFunctions returns the functions supported by Graphite, as /functions
+// with the Infinity values replaced to be valid JSON.
+func (s *Service) handleFunctions(rw http.ResponseWriter, r *http.Request, dsInfo *datasourceInfo) {
+	body, status, err := s.getFunctions(r.Context(), dsInfo)
+	if err != nil {
+		logger.Warn("Failed to get Graphite functions", "error", err)
+		http.Error(rw, "error getting Graphite functions", http.StatusInternalServerError)
+		return
+	}
+	writeResourceResponse(rw, status, body)
+}
+
+func (s *Service) getFunctions(ctx context.Context, dsInfo *datasourceInfo) ([]byte, int, error) {
+	body, status, err := s.getCached(ctx, dsInfo, "functions", url.Values{}, functionsCacheTTL)
+	if err != nil || status != http.StatusOK {
+		return body, status, err
+	}
+	return infinityRegexp.ReplaceAll(body, []byte(`: ${1}1e9999`)), status, nil
+}
+
+// ValidateRequest is the body of validate resource requests.
+type ValidateRequest struct {
+	Target string `json:"target"`
+}
+
+// ValidateResponse reports if the target is valid for Graphite.
+type ValidateResponse struct {
+	Valid bool           `json:"valid"`
+	Error *ValidateError `json:"error,omitempty"`
+	// UnknownFunctions are called by the target but not listed by Graphite.
+	UnknownFunctions []string `json:"unknownFunctions,omitempty"`
+}
+
+type ValidateError struct {
+	Message string `json:"message"`
+	// Column of the error, starting at 1.
+	Column int `json:"column"`
+}
+
+// handleValidate parses the target, and checks its functions are supported
+// when Graphite lists its functions.
+func (s *Service) handleValidate(rw http.ResponseWriter, r *http.Request, dsInfo *datasourceInfo) {
+	var req ValidateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(rw, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	resp := ValidateResponse{Valid: true}
+	expr, err := ParseTarget(fixIntervalFormat(req.Target))
+	var targetErr *TargetError
+	switch {
+	case errors.As(err, &targetErr):
+		resp.Valid = false
+		resp.Error = &ValidateError{Message: targetErr.Msg, Column: targetErr.Pos + 1}
+	case err != nil:
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	default:
+		resp.UnknownFunctions = s.unknownFunctions(r.Context(), dsInfo, expr.Functions())
+		resp.Valid = len(resp.UnknownFunctions) == 0
+	}
+	body, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeResourceResponse(rw, http.StatusOK, body)
+}
+
+// unknownFunctions returns the names not listed by Graphite, none if the
+// functions can not be listed as with Graphite versions before 1.1.
+func (s *Service) unknownFunctions(ctx context.Context, dsInfo *datasourceInfo, names []string) []string {
+	if len(names) == 0 {
+		return nil
+	}
+	body, status, err := s.getFunctions(ctx, dsInfo)
+	if err != nil || status != http.StatusOK {
+		logger.Debug("Graphite functions not available, skipping function validation", "status", status, "error", err)
+		return nil
+	}
+	var functions map[string]json.RawMessage
+	if err := json.Unmarshal(body, &functions); err != nil {
+		logger.Debug("Failed to decode Graphite functions, skipping function validation", "error", err)
+		return nil
+	}
+	unknown := map[string]struct{}{}
+	for _, name := range names {
+		if _, ok := functions[name]; !ok {
+			unknown[name] = struct{}{}
+		}
+	}
+	result := make([]string, 0, len(unknown))
+	for name := range unknown {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// proxyCached writes the response of Graphite to the request with the params,
+// successful responses are cached for the TTL.
+func (s *Service) proxyCached(rw http.ResponseWriter, r *http.Request, dsInfo *datasourceInfo, graphitePath string, params url.Values, ttl time.Duration) {
+	body, status, err := s.getCached(r.Context(), dsInfo, graphitePath, params, ttl)
+	if err != nil {
+		logger.Warn("Graphite resource request failed", "path", graphitePath, "error", err)
+		http.Error(rw, "error requesting Graphite", http.StatusBadGateway)
+		return
+	}
+	writeResourceResponse(rw, status, body)
+}
+
+func (s *Service) getCached(ctx context.Context, dsInfo *datasourceInfo, graphitePath string, params url.Values, ttl time.Duration) ([]byte, int, error) {
+	key := graphitePath + "?" + params.Encode()
+	if dsInfo.resourceCache != nil {
+		if body, ok := dsInfo.resourceCache.get(key); ok {
+			return body, http.StatusOK, nil
+		}
+	}
+
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, 0, err
+	}
+	u.Path = path.Join(u.Path, graphitePath)
+	u.RawQuery = params.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	res, err := dsInfo.HTTPClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	if res.StatusCode == http.StatusOK && dsInfo.resourceCache != nil {
+		dsInfo.resourceCache.set(key, body, ttl)
+	}
+	return body, res.StatusCode, nil
+}
+
+// filterParams returns the params with the names, other params are not sent
+// to Graphite.
+func filterParams(query url.Values, names ...string) url.Values {
+	params := url.Values{}
+	for _, name := range names {
+		if values, ok := query[name]; ok {
+			params[name] = values
+		}
+	}
+	return params
+}
+
+func writeResourceResponse(rw http.ResponseWriter, status int, body []byte) {
+	if status == http.StatusOK && json.Valid(bytes.TrimSpace(body)) {
+		rw.Header().Set("Content-Type", "application/json")
+	}
+	rw.WriteHeader(status)
+	_, _ = rw.Write(body)
+}
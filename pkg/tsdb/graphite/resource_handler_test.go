@@ -0,0 +1,161 @@
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+type fakeSender struct {
+	resp *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(resp *backend.CallResourceResponse) error {
+	s.resp = resp
+	return nil
+}
+
+type fakeGraphite struct {
+	mu       sync.Mutex
+	requests map[string]int
+}
+
+func (g *fakeGraphite) handler(w http.ResponseWriter, r *http.Request) {
+	g.mu.Lock()
+	g.requests[r.URL.Path]++
+	g.mu.Unlock()
+	switch r.URL.Path {
+	case "/metrics/find":
+		_, _ = w.Write([]byte(`[{"text": "` + r.URL.Query().Get("query") + `", "expandable": 1}]`))
+	case "/tags/autoComplete/tags":
+		_, _ = w.Write([]byte(`["dc", "env"]`))
+	case "/functions":
+		_, _ = w.Write([]byte(`{"sumSeries": {"name": "sumSeries"}, "highestMax": {"params": [{"name": "n", "default": Infinity}]}}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (g *fakeGraphite) numRequests(path string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.requests[path]
+}
+
+func TestCallResource(t *testing.T) {
+	graphite := &fakeGraphite{requests: map[string]int{}}
+	server := httptest.NewServer(http.HandlerFunc(graphite.handler))
+	t.Cleanup(server.Close)
+
+	service := ProvideService(httpclient.NewProvider(), tracing.NewNoopTracerService())
+	callResource := func(t *testing.T, url string, method string, body string) *backend.CallResourceResponse {
+		t.Helper()
+		sender := &fakeSender{}
+		path, _, _ := strings.Cut(url, "?")
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+					ID:  1,
+					URL: server.URL,
+				},
+			},
+			Path:   path,
+			Method: method,
+			URL:    url,
+			Body:   []byte(body),
+		}, sender)
+		require.NoError(t, err)
+		require.NotNil(t, sender.resp)
+		return sender.resp
+	}
+
+	t.Run("metrics find is cached", func(t *testing.T) {
+		resp := callResource(t, "metrics/find?query=app.*&other=1", http.MethodGet, "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		assert.JSONEq(t, `[{"text": "app.*", "expandable": 1}]`, string(resp.Body))
+
+		resp = callResource(t, "metrics/find?query=app.*&other=2", http.MethodGet, "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		assert.Equal(t, 1, graphite.numRequests("/metrics/find"))
+
+		resp = callResource(t, "metrics/find?query=web.*", http.MethodGet, "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		assert.Equal(t, 2, graphite.numRequests("/metrics/find"))
+
+		resp = callResource(t, "metrics/find", http.MethodGet, "")
+		assert.Equal(t, http.StatusBadRequest, resp.Status)
+	})
+
+	t.Run("tags autocomplete", func(t *testing.T) {
+		resp := callResource(t, "tags/autoComplete/tags?tagPrefix=e", http.MethodGet, "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		assert.JSONEq(t, `["dc", "env"]`, string(resp.Body))
+
+		resp = callResource(t, "tags/autoComplete/values?tag=env", http.MethodGet, "")
+		assert.Equal(t, http.StatusNotFound, resp.Status)
+		// Errors are not cached.
+		callResource(t, "tags/autoComplete/values?tag=env", http.MethodGet, "")
+		assert.Equal(t, 2, graphite.numRequests("/tags/autoComplete/values"))
+	})
+
+	t.Run("functions are valid JSON", func(t *testing.T) {
+		resp := callResource(t, "functions", http.MethodGet, "")
+		require.Equal(t, http.StatusOK, resp.Status)
+		var functions map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(resp.Body, &functions))
+		assert.Contains(t, functions, "highestMax")
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		tests := []struct {
+			target   string
+			expected string
+		}{
+			{target: "sumSeries(app.*.count)", expected: `{"valid": true}`},
+			{target: "sumSeries(app.*.count", expected: `{"valid": false, "error": {"message": "missing ')' for sumSeries", "column": 10}}`},
+			{target: "unknown(sumSeries(app.*.count))", expected: `{"valid": false, "unknownFunctions": ["unknown"]}`},
+		}
+		for _, tt := range tests {
+			body, err := json.Marshal(ValidateRequest{Target: tt.target})
+			require.NoError(t, err)
+			resp := callResource(t, "validate", http.MethodPost, string(body))
+			require.Equal(t, http.StatusOK, resp.Status)
+			assert.JSONEq(t, tt.expected, string(resp.Body), tt.target)
+		}
+	})
+}
+
+func TestResourceCache(t *testing.T) {
+	now := time.Unix(0, 0)
+	cache := newResourceCache(2)
+	cache.now = func() time.Time { return now }
+
+	cache.set("a", []byte("a"), time.Minute)
+	cache.set("b", []byte("b"), 2*time.Minute)
+	value, ok := cache.get("a")
+	require.True(t, ok)
+	assert.Equal(t, []byte("a"), value)
+
+	// The entry expiring first is evicted when the cache is full.
+	cache.set("c", []byte("c"), 2*time.Minute)
+	_, ok = cache.get("a")
+	assert.False(t, ok)
+	_, ok = cache.get("b")
+	assert.True(t, ok)
+
+	now = now.Add(2 * time.Minute)
+	_, ok = cache.get("b")
+	assert.False(t, ok)
+}
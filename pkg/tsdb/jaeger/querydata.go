@@ -3,6 +3,7 @@ package jaeger
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"time"
@@ -59,6 +60,23 @@ func queryData(ctx context.Context, dsInfo *datasourceInfo, req *backend.QueryDa
 			}
 		}
 
+		// Handle "Service graph" query type, aggregating the spans of the searched traces
+		if query.QueryType == "serviceGraph" {
+			if query.Service == "" {
+				response.Responses[q.RefID] = backend.ErrorResponseWithErrorSource(backend.DownstreamError(errors.New("service is required for the service graph")))
+				continue
+			}
+			traces, err := dsInfo.JaegerClient.Search(&query, q.TimeRange.From.UnixMicro(), q.TimeRange.To.UnixMicro())
+			if err != nil {
+				response.Responses[q.RefID] = backend.ErrorResponseWithErrorSource(err)
+				continue
+			}
+			frames := transformServiceGraphResponse(traces, q.RefID)
+			response.Responses[q.RefID] = backend.DataResponse{
+				Frames: frames,
+			}
+		}
+
 		// No query type means traceID query
 		if query.QueryType == "" {
 			traces, err := dsInfo.JaegerClient.Trace(ctx, query.Query, q.TimeRange.From.UnixMilli(), q.TimeRange.To.UnixMilli())
@@ -380,3 +380,47 @@ func TestTransformDependenciesResponse(t *testing.T) {
 		experimental.CheckGoldenJSONFrame(t, "./testdata", "complex_dependencies_edges.golden", frames[1], false)
 	})
 }
+
+func TestTransformServiceGraphResponse(t *testing.T) {
+	t.Run("empty_service_graph", func(t *testing.T) {
+		frames := transformServiceGraphResponse([]TraceResponse{}, "test")
+		experimental.CheckGoldenJSONFrame(t, "./testdata", "empty_service_graph_nodes.golden", frames[0], false)
+		experimental.CheckGoldenJSONFrame(t, "./testdata", "empty_service_graph_edges.golden", frames[1], false)
+	})
+
+	t.Run("service_graph", func(t *testing.T) {
+		processes := map[string]TraceProcess{
+			"p1": {ServiceName: "frontend"},
+			"p2": {ServiceName: "api"},
+			"p3": {ServiceName: "database"},
+		}
+		childOf := func(spanID string) []TraceSpanReference {
+			return []TraceSpanReference{{RefType: "CHILD_OF", SpanID: spanID}}
+		}
+		traces := []TraceResponse{
+			{
+				TraceID:   "trace-1",
+				Processes: processes,
+				Spans: []Span{
+					{SpanID: "1", ProcessID: "p1", Duration: 30000},
+					// Spans in the same service are not calls between services
+					{SpanID: "2", ProcessID: "p1", Duration: 25000, References: childOf("1")},
+					{SpanID: "3", ProcessID: "p2", Duration: 20000, References: childOf("2")},
+					{SpanID: "4", ProcessID: "p3", Duration: 5000, References: childOf("3")},
+				},
+			},
+			{
+				TraceID:   "trace-2",
+				Processes: processes,
+				Spans: []Span{
+					{SpanID: "1", ProcessID: "p1", Duration: 60000, Tags: []TraceKeyValuePair{{Key: "error", Value: true}}},
+					{SpanID: "2", ProcessID: "p2", Duration: 40000, References: childOf("1"), Tags: []TraceKeyValuePair{{Key: "otel.status_code", Value: "ERROR"}}},
+				},
+			},
+		}
+
+		frames := transformServiceGraphResponse(traces, "test")
+		experimental.CheckGoldenJSONFrame(t, "./testdata", "service_graph_nodes.golden", frames[0], false)
+		experimental.CheckGoldenJSONFrame(t, "./testdata", "service_graph_edges.golden", frames[1], false)
+	})
+}
@@ -0,0 +1,78 @@
+package jaeger
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/tsdb/servicegraph"
+)
+
+// transformServiceGraphResponse aggregates the spans of the traces into a
+// service graph. Spans with a parent in another service are calls between the
+// services, the root spans are the calls received by their service.
+func transformServiceGraphResponse(traces []TraceResponse, refID string) []*data.Frame {
+	graph := servicegraph.New()
+	for _, trace := range traces {
+		spans := make(map[string]Span, len(trace.Spans))
+		for _, span := range trace.Spans {
+			spans[span.SpanID] = span
+		}
+		serviceName := func(span Span) string {
+			if process, ok := trace.Processes[span.ProcessID]; ok && process.ServiceName != "" {
+				return process.ServiceName
+			}
+			return "unknown"
+		}
+
+		for _, span := range trace.Spans {
+			service := serviceName(span)
+			durationMs := float64(span.Duration) / 1000
+			parentID, hasParent := parentSpanID(span)
+			if !hasParent {
+				graph.AddCall("", service, durationMs, isErrorSpan(span))
+				continue
+			}
+			parent, ok := spans[parentID]
+			if !ok {
+				// The parent is not in the trace, as when it was not ingested
+				// yet, the span is counted as a root span.
+				graph.AddCall("", service, durationMs, isErrorSpan(span))
+				continue
+			}
+			if parentService := serviceName(parent); parentService != service {
+				graph.AddCall(parentService, service, durationMs, isErrorSpan(span))
+			}
+		}
+	}
+	return graph.Frames(refID, len(traces))
+}
+
+// parentSpanID returns the span the span is a child of, as when building the
+// trace frame.
+func parentSpanID(span Span) (string, bool) {
+	for _, ref := range span.References {
+		if ref.RefType == "CHILD_OF" {
+			return ref.SpanID, true
+		}
+	}
+	return "", false
+}
+
+// isErrorSpan reports if the span has the error tag, or an OpenTelemetry error
+// status.
+func isErrorSpan(span Span) bool {
+	for _, tag := range span.Tags {
+		switch tag.Key {
+		case "error":
+			if fmt.Sprint(tag.Value) == "true" {
+				return true
+			}
+		case "otel.status_code":
+			if fmt.Sprint(tag.Value) == "ERROR" {
+				return true
+			}
+		}
+	}
+	return false
+}
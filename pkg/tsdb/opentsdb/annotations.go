@@ -0,0 +1,103 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const annotationQueryType = "annotation"
+
+// queryAnnotations returns the annotations of the metric of the query target,
+// or the global annotations when isGlobal is set, as returned by OpenTSDB
+// with the series of the query.
+func (s *Service) queryAnnotations(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, query backend.DataQuery) backend.DataResponse {
+	model, err := simplejson.NewJson(query.JSON)
+	if err != nil {
+		return backend.ErrDataResponseWithSource(http.StatusBadRequest, backend.ErrorSourceDownstream, fmt.Sprintf("invalid annotation query: %s", err))
+	}
+	target := model.Get("target").MustString()
+	if target == "" {
+		return backend.ErrDataResponseWithSource(http.StatusBadRequest, backend.ErrorSourceDownstream, "annotation query target is required")
+	}
+	isGlobal := model.Get("isGlobal").MustBool()
+
+	tsdbQuery := OpenTsdbQuery{
+		Start:             query.TimeRange.From.UnixNano() / int64(time.Millisecond),
+		End:               query.TimeRange.To.UnixNano() / int64(time.Millisecond),
+		Queries:           []map[string]any{{"aggregator": "sum", "metric": target}},
+		GlobalAnnotations: isGlobal,
+	}
+	request, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusInternal, err.Error())
+	}
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		return backend.ErrorResponseWithErrorSource(backend.DownstreamError(err))
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return backend.ErrorResponseWithErrorSource(backend.DownstreamError(err))
+	}
+	if res.StatusCode/100 != 2 {
+		logger.Info("Annotation request failed", "status", res.Status, "body", string(body))
+		return backend.ErrDataResponseWithSource(backend.Status(res.StatusCode), backend.ErrorSourceDownstream, fmt.Sprintf("request failed, status: %s", res.Status))
+	}
+
+	var responseData []OpenTsdbAnnotationResponse
+	if err := json.Unmarshal(body, &responseData); err != nil {
+		logger.Info("Failed to unmarshal opentsdb annotation response", "error", err, "status", res.Status, "body", string(body))
+		return backend.ErrDataResponse(backend.StatusInternal, err.Error())
+	}
+
+	// As in the frontend, only the annotations of the first series are used.
+	var annotations []OpenTsdbAnnotation
+	if len(responseData) > 0 {
+		annotations = responseData[0].Annotations
+		if isGlobal {
+			annotations = responseData[0].GlobalAnnotations
+		}
+	}
+	return backend.DataResponse{Frames: data.Frames{createAnnotationFrame(query.RefID, annotations)}}
+}
+
+func createAnnotationFrame(refID string, annotations []OpenTsdbAnnotation) *data.Frame {
+	times := make([]time.Time, 0, len(annotations))
+	timeEnds := make([]time.Time, 0, len(annotations))
+	texts := make([]string, 0, len(annotations))
+	for _, annotation := range annotations {
+		start := time.Unix(int64(annotation.StartTime), 0).UTC()
+		end := start
+		if annotation.EndTime > annotation.StartTime {
+			end = time.Unix(int64(annotation.EndTime), 0).UTC()
+		}
+		times = append(times, start)
+		timeEnds = append(timeEnds, end)
+		texts = append(texts, annotation.Description)
+	}
+
+	frame := data.NewFrame("annotations",
+		data.NewField("time", nil, times),
+		data.NewField("timeEnd", nil, timeEnds),
+		data.NewField("text", nil, texts),
+	)
+	frame.RefID = refID
+	frame.SetMeta(&data.FrameMeta{DataTopic: data.DataTopicAnnotations})
+	return frame
+}
@@ -3,11 +3,14 @@ package opentsdb
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"path"
+	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -89,12 +92,26 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 
 	q := req.Queries[0]
 
-	refID := q.RefID
+	refID := ""
 
 	tsdbQuery.Start = q.TimeRange.From.UnixNano() / int64(time.Millisecond)
 	tsdbQuery.End = q.TimeRange.To.UnixNano() / int64(time.Millisecond)
 
+	result := backend.NewQueryDataResponse()
+	annotationQueries := []backend.DataQuery{}
 	for _, query := range req.Queries {
+		if query.QueryType == annotationQueryType {
+			annotationQueries = append(annotationQueries, query)
+			continue
+		}
+		if err := validateQuery(query); err != nil {
+			logger.Debug("Invalid OpenTSDB query", "refId", query.RefID, "error", err)
+			result.Responses[query.RefID] = backend.ErrDataResponseWithSource(http.StatusBadRequest, backend.ErrorSourceDownstream, err.Error())
+			continue
+		}
+		if refID == "" {
+			refID = query.RefID
+		}
 		metric := s.buildMetric(query)
 		tsdbQuery.Queries = append(tsdbQuery.Queries, metric)
 	}
@@ -109,6 +126,14 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		return nil, err
 	}
 
+	for _, query := range annotationQueries {
+		result.Responses[query.RefID] = s.queryAnnotations(ctx, logger, dsInfo, query)
+	}
+
+	if len(tsdbQuery.Queries) == 0 {
+		return result, nil
+	}
+
 	request, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
 	if err != nil {
 		return &backend.QueryDataResponse{}, err
@@ -126,11 +151,12 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		}
 	}()
 
-	result, err := s.parseResponse(logger, res, refID, dsInfo.TSDBVersion)
+	resp, err := s.parseResponse(logger, res, refID, dsInfo.TSDBVersion)
 	if err != nil {
 		return &backend.QueryDataResponse{}, err
 	}
 
+	result.Responses[refID] = resp.Responses[refID]
 	return result, nil
 }
 
@@ -271,6 +297,71 @@ func (s *Service) parseResponse(logger log.Logger, res *http.Response, refID str
 	return resp, nil
 }
 
+var (
+	// downsampleIntervalRegexp matches the OpenTSDB durations, with the c suffix
+	// of the calendar based intervals as 1dc, and 0all to downsample the whole range.
+	downsampleIntervalRegexp = regexp.MustCompile(`^(\d+(ms|s|m|h|d|w|n|y)c?|0all)$`)
+	downsampleFillPolicies   = []string{"none", "nan", "null", "zero"}
+)
+
+// validateQuery returns an error when the downsample or rate options of the
+// query would be rejected by OpenTSDB.
+func validateQuery(query backend.DataQuery) error {
+	model, err := simplejson.NewJson(query.JSON)
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+
+	if !model.Get("disableDownsampling").MustBool() {
+		interval := model.Get("downsampleInterval").MustString()
+		if interval != "" && !downsampleIntervalRegexp.MatchString(interval) {
+			return fmt.Errorf("invalid downsample interval %q, expected a duration such as 1m or 1h", interval)
+		}
+		fillPolicy := model.Get("downsampleFillPolicy").MustString()
+		if fillPolicy != "" && !slices.Contains(downsampleFillPolicies, fillPolicy) {
+			return fmt.Errorf("invalid downsample fill policy %q, expected one of %s", fillPolicy, strings.Join(downsampleFillPolicies, ", "))
+		}
+	}
+
+	if model.Get("shouldComputeRate").MustBool() {
+		for _, name := range []string{"counterMax", "counterResetValue"} {
+			value, ok, err := optionalFloat(model, name)
+			if err != nil {
+				return fmt.Errorf("invalid %s: %w", name, err)
+			}
+			if ok && value < 0 {
+				return fmt.Errorf("invalid %s %v, expected a positive number", name, value)
+			}
+		}
+	}
+
+	return nil
+}
+
+// optionalFloat returns the number of the field, which the query editor sets as
+// a string. Fields not set or set to an empty string are not returned.
+func optionalFloat(model *simplejson.Json, name string) (float64, bool, error) {
+	field, ok := model.CheckGet(name)
+	if !ok || field.Interface() == nil {
+		return 0, false, nil
+	}
+	if value, err := field.Float64(); err == nil {
+		return value, true, nil
+	}
+	str, err := field.String()
+	if err != nil {
+		return 0, false, errors.New("expected a number")
+	}
+	if strings.TrimSpace(str) == "" {
+		return 0, false, nil
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("expected a number, got %q", str)
+	}
+	return value, true, nil
+}
+
 func (s *Service) buildMetric(query backend.DataQuery) map[string]any {
 	metric := make(map[string]any)
 
@@ -290,9 +381,13 @@ func (s *Service) buildMetric(query backend.DataQuery) map[string]any {
 		if downsampleInterval == "" {
 			downsampleInterval = "1m" // default value for blank
 		}
-		downsample := downsampleInterval + "-" + model.Get("downsampleAggregator").MustString()
-		if model.Get("downsampleFillPolicy").MustString() != "none" {
-			metric["downsample"] = downsample + "-" + model.Get("downsampleFillPolicy").MustString()
+		downsampleAggregator := model.Get("downsampleAggregator").MustString()
+		if downsampleAggregator == "" {
+			downsampleAggregator = "avg" // default value for blank, as in the query editor
+		}
+		downsample := downsampleInterval + "-" + downsampleAggregator
+		if fillPolicy := model.Get("downsampleFillPolicy").MustString(); fillPolicy != "" && fillPolicy != "none" {
+			metric["downsample"] = downsample + "-" + fillPolicy
 		} else {
 			metric["downsample"] = downsample
 		}
@@ -304,17 +399,17 @@ func (s *Service) buildMetric(query backend.DataQuery) map[string]any {
 		rateOptions := make(map[string]any)
 		rateOptions["counter"] = model.Get("isCounter").MustBool()
 
-		counterMax, counterMaxCheck := model.CheckGet("counterMax")
+		counterMax, counterMaxCheck, _ := optionalFloat(model, "counterMax")
 		if counterMaxCheck {
-			rateOptions["counterMax"] = counterMax.MustFloat64()
+			rateOptions["counterMax"] = counterMax
 		}
 
-		resetValue, resetValueCheck := model.CheckGet("counterResetValue")
+		resetValue, resetValueCheck, _ := optionalFloat(model, "counterResetValue")
 		if resetValueCheck {
-			rateOptions["resetValue"] = resetValue.MustFloat64()
+			rateOptions["resetValue"] = resetValue
 		}
 
-		if !counterMaxCheck && (!resetValueCheck || resetValue.MustFloat64() == 0) {
+		if !counterMaxCheck && (!resetValueCheck || resetValue == 0) {
 			rateOptions["dropResets"] = true
 		}
 
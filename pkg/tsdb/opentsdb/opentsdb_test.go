@@ -2,8 +2,10 @@ package opentsdb
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -13,6 +15,8 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
 )
 
 func TestOpenTsdbExecutor(t *testing.T) {
@@ -257,6 +261,22 @@ func TestOpenTsdbExecutor(t *testing.T) {
 		require.Equal(t, "1m-avg", metric["downsample"])
 	})
 
+	t.Run("Build metric with the default downsample aggregator", func(t *testing.T) {
+		query := backend.DataQuery{
+			JSON: []byte(`
+					{
+						"metric": "cpu.average.percent",
+						"aggregator": "avg",
+						"downsampleInterval": "1hc"
+					}`,
+			),
+		}
+
+		metric := service.buildMetric(query)
+
+		require.Equal(t, "1hc-avg", metric["downsample"])
+	})
+
 	t.Run("Build metric with downsampling disabled", func(t *testing.T) {
 		query := backend.DataQuery{
 			JSON: []byte(`
@@ -403,4 +423,141 @@ func TestOpenTsdbExecutor(t *testing.T) {
 		require.Equal(t, float64(45), metricRateOptions["counterMax"])
 		require.Equal(t, float64(60), metricRateOptions["resetValue"])
 	})
+	t.Run("Build metric with rate options set as strings", func(t *testing.T) {
+		query := backend.DataQuery{
+			JSON: []byte(`
+					{
+						"metric": "cpu.average.percent",
+						"aggregator": "avg",
+						"disableDownsampling": true,
+						"shouldComputeRate": true,
+						"isCounter": true,
+						"counterMax": "45",
+						"counterResetValue": ""
+					}`,
+			),
+		}
+
+		metric := service.buildMetric(query)
+
+		metricRateOptions := metric["rateOptions"].(map[string]any)
+		require.Len(t, metricRateOptions, 2)
+		require.Equal(t, float64(45), metricRateOptions["counterMax"])
+		require.Nil(t, metricRateOptions["resetValue"])
+	})
+
+	t.Run("Validate query", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			query string
+			err   string
+		}{
+			{
+				name:  "default downsampling",
+				query: `{"metric": "cpu", "downsampleInterval": "", "downsampleAggregator": "avg", "downsampleFillPolicy": "none"}`,
+			},
+			{
+				name:  "downsampling with params",
+				query: `{"metric": "cpu", "downsampleInterval": "500ms", "downsampleAggregator": "sum", "downsampleFillPolicy": "nan"}`,
+			},
+			{
+				name:  "downsampling disabled",
+				query: `{"metric": "cpu", "disableDownsampling": true, "downsampleInterval": "invalid"}`,
+			},
+			{
+				name:  "invalid downsample interval",
+				query: `{"metric": "cpu", "downsampleInterval": "5 minutes", "downsampleAggregator": "avg"}`,
+				err:   `invalid downsample interval "5 minutes", expected a duration such as 1m or 1h`,
+			},
+			{
+				name:  "default downsample aggregator",
+				query: `{"metric": "cpu", "downsampleInterval": "5m"}`,
+			},
+			{
+				name:  "calendar downsample interval",
+				query: `{"metric": "cpu", "downsampleInterval": "1dc", "downsampleAggregator": "sum"}`,
+			},
+			{
+				name:  "invalid fill policy",
+				query: `{"metric": "cpu", "downsampleAggregator": "avg", "downsampleFillPolicy": "previous"}`,
+				err:   `invalid downsample fill policy "previous", expected one of none, nan, null, zero`,
+			},
+			{
+				name:  "rate options",
+				query: `{"metric": "cpu", "disableDownsampling": true, "shouldComputeRate": true, "isCounter": true, "counterMax": "45", "counterResetValue": 0}`,
+			},
+			{
+				name:  "invalid counter max",
+				query: `{"metric": "cpu", "disableDownsampling": true, "shouldComputeRate": true, "isCounter": true, "counterMax": "max"}`,
+				err:   `invalid counterMax: expected a number, got "max"`,
+			},
+			{
+				name:  "negative counter reset value",
+				query: `{"metric": "cpu", "disableDownsampling": true, "shouldComputeRate": true, "counterResetValue": -1}`,
+				err:   "invalid counterResetValue -1, expected a positive number",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				err := validateQuery(backend.DataQuery{JSON: []byte(tt.query)})
+				if tt.err == "" {
+					require.NoError(t, err)
+					return
+				}
+				require.EqualError(t, err, tt.err)
+			})
+		}
+	})
+}
+
+func TestQueryData(t *testing.T) {
+	var requests []OpenTsdbQuery
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var query OpenTsdbQuery
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&query))
+		requests = append(requests, query)
+		if query.GlobalAnnotations {
+			_, _ = w.Write([]byte(`[{"metric": "events", "dps": {}, "annotations": [{"description": "local", "startTime": 1}],
+				"globalAnnotations": [{"description": "deploy", "startTime": 1405544146, "endTime": 1405544206}]}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"metric": "cpu", "dps": {"1405544146": 50}, "tags": {}}]`))
+	}))
+	t.Cleanup(server.Close)
+
+	service := ProvideService(httpclient.NewProvider())
+	timeRange := backend.TimeRange{From: time.Unix(1405544000, 0), To: time.Unix(1405545000, 0)}
+
+	resp, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+		PluginContext: backend.PluginContext{
+			DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+				URL:      server.URL,
+				JSONData: []byte(`{"tsdbVersion": 3}`),
+			},
+		},
+		Queries: []backend.DataQuery{
+			{RefID: "A", TimeRange: timeRange, JSON: []byte(`{"metric": "cpu", "downsampleInterval": "5 minutes", "downsampleAggregator": "avg"}`)},
+			{RefID: "B", TimeRange: timeRange, JSON: []byte(`{"metric": "cpu", "aggregator": "sum", "disableDownsampling": true}`)},
+			{RefID: "C", TimeRange: timeRange, QueryType: annotationQueryType, JSON: []byte(`{"target": "events", "isGlobal": true}`)},
+		},
+	})
+	require.NoError(t, err)
+
+	// The invalid query is not sent.
+	require.Len(t, requests, 2)
+	require.Equal(t, backend.StatusBadRequest, resp.Responses["A"].Status)
+	require.Equal(t, backend.ErrorSourceDownstream, resp.Responses["A"].ErrorSource)
+
+	require.NoError(t, resp.Responses["B"].Error)
+	require.Len(t, resp.Responses["B"].Frames, 1)
+	require.Equal(t, "B", resp.Responses["B"].Frames[0].RefID)
+
+	require.NoError(t, resp.Responses["C"].Error)
+	require.Len(t, resp.Responses["C"].Frames, 1)
+	frame := resp.Responses["C"].Frames[0]
+	require.Equal(t, data.DataTopicAnnotations, frame.Meta.DataTopic)
+	require.Equal(t, 1, frame.Rows())
+	require.Equal(t, time.Unix(1405544146, 0).UTC(), frame.Fields[0].At(0))
+	require.Equal(t, time.Unix(1405544206, 0).UTC(), frame.Fields[1].At(0))
+	require.Equal(t, "deploy", frame.Fields[2].At(0))
 }
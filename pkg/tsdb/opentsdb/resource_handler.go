@@ -0,0 +1,139 @@
+package opentsdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+)
+
+// defaultLookupLimit is used when the data source has no lookup limit, as in
+// the frontend.
+const defaultLookupLimit = 1000
+
+var suggestTypes = []string{"metrics", "tagk", "tagv"}
+
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	handler := httpadapter.New(s.registerResourceRoutes())
+	return handler.CallResource(ctx, req, sender)
+}
+
+func (s *Service) registerResourceRoutes() *http.ServeMux {
+	router := http.NewServeMux()
+	router.HandleFunc("GET /suggest", s.withDatasourceHandlerFunc(s.handleSuggest))
+	router.HandleFunc("GET /lookup", s.withDatasourceHandlerFunc(s.handleLookup))
+	return router
+}
+
+func (s *Service) withDatasourceHandlerFunc(handler func(rw http.ResponseWriter, r *http.Request, dsInfo *datasourceInfo)) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		dsInfo, err := s.getDSInfo(r.Context(), backend.PluginConfigFromContext(r.Context()))
+		if err != nil {
+			logger.Error("Failed to get data source info", "error", err)
+			http.Error(rw, "error getting data source information from context", http.StatusInternalServerError)
+			return
+		}
+		handler(rw, r, dsInfo)
+	}
+}
+
+// handleSuggest returns the metrics, tag keys or tag values starting with q,
+// using the suggest API of OpenTSDB.
+func (s *Service) handleSuggest(rw http.ResponseWriter, r *http.Request, dsInfo *datasourceInfo) {
+	query := r.URL.Query()
+	suggestType := query.Get("type")
+	if !slices.Contains(suggestTypes, suggestType) {
+		http.Error(rw, fmt.Sprintf("invalid suggest type %q, expected one of metrics, tagk or tagv", suggestType), http.StatusBadRequest)
+		return
+	}
+	limit, err := lookupLimit(query.Get("max"), dsInfo)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	params := url.Values{
+		"type": {suggestType},
+		"q":    {query.Get("q")},
+		"max":  {strconv.Itoa(limit)},
+	}
+	s.proxyGet(rw, r, dsInfo, "api/suggest", params)
+}
+
+// handleLookup returns the time series matching the metric and tags of m,
+// using the search lookup API of OpenTSDB.
+func (s *Service) handleLookup(rw http.ResponseWriter, r *http.Request, dsInfo *datasourceInfo) {
+	query := r.URL.Query()
+	m := query.Get("m")
+	if m == "" {
+		http.Error(rw, "m is required", http.StatusBadRequest)
+		return
+	}
+	limit, err := lookupLimit(query.Get("limit"), dsInfo)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	params := url.Values{
+		"m":     {m},
+		"limit": {strconv.Itoa(limit)},
+	}
+	s.proxyGet(rw, r, dsInfo, "api/search/lookup", params)
+}
+
+// lookupLimit returns the requested limit, or the limit of the data source.
+func lookupLimit(value string, dsInfo *datasourceInfo) (int, error) {
+	if value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return 0, fmt.Errorf("invalid limit %q", value)
+		}
+		return limit, nil
+	}
+	if dsInfo.LookupLimit > 0 {
+		return int(dsInfo.LookupLimit), nil
+	}
+	return defaultLookupLimit, nil
+}
+
+// proxyGet writes the response of OpenTSDB to the request with the params.
+func (s *Service) proxyGet(rw http.ResponseWriter, r *http.Request, dsInfo *datasourceInfo, apiPath string, params url.Values) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	u.Path = path.Join(u.Path, apiPath)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, u.String(), nil)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	res, err := dsInfo.HTTPClient.Do(req)
+	if err != nil {
+		logger.Warn("OpenTSDB resource request failed", "path", apiPath, "error", err)
+		http.Error(rw, "error requesting OpenTSDB", http.StatusBadGateway)
+		return
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if contentType := res.Header.Get("Content-Type"); contentType != "" {
+		rw.Header().Set("Content-Type", contentType)
+	}
+	rw.WriteHeader(res.StatusCode)
+	if _, err := io.Copy(rw, res.Body); err != nil {
+		logger.Warn("Failed to write OpenTSDB response", "path", apiPath, "error", err)
+	}
+}
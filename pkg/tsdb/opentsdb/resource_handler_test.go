@@ -0,0 +1,88 @@
+package opentsdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+)
+
+type fakeSender struct {
+	resp *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(resp *backend.CallResourceResponse) error {
+	s.resp = resp
+	return nil
+}
+
+func TestCallResource(t *testing.T) {
+	var lastQuery url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastQuery = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/suggest":
+			_, _ = w.Write([]byte(`["cpu.user", "cpu.system"]`))
+		case "/api/search/lookup":
+			_, _ = w.Write([]byte(`{"results": [{"metric": "cpu.user", "tags": {"host": "a"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	service := ProvideService(httpclient.NewProvider())
+	callResource := func(t *testing.T, url string) *backend.CallResourceResponse {
+		t.Helper()
+		sender := &fakeSender{}
+		path, _, _ := strings.Cut(url, "?")
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+					URL:      server.URL,
+					JSONData: []byte(`{"lookupLimit": 50}`),
+				},
+			},
+			Path:   path,
+			Method: http.MethodGet,
+			URL:    url,
+		}, sender)
+		require.NoError(t, err)
+		require.NotNil(t, sender.resp)
+		return sender.resp
+	}
+
+	t.Run("suggest", func(t *testing.T) {
+		resp := callResource(t, "suggest?type=metrics&q=cpu")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `["cpu.user", "cpu.system"]`, string(resp.Body))
+		require.Equal(t, url.Values{"type": {"metrics"}, "q": {"cpu"}, "max": {"50"}}, lastQuery)
+
+		resp = callResource(t, "suggest?type=tagv&q=a&max=10")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.Equal(t, "10", lastQuery.Get("max"))
+
+		resp = callResource(t, "suggest?type=other&q=cpu")
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+		resp = callResource(t, "suggest?type=tagk&max=none")
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+	})
+
+	t.Run("lookup", func(t *testing.T) {
+		resp := callResource(t, "lookup?m=cpu.user{host=*}")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `{"results": [{"metric": "cpu.user", "tags": {"host": "a"}}]}`, string(resp.Body))
+		require.Equal(t, url.Values{"m": {"cpu.user{host=*}"}, "limit": {"50"}}, lastQuery)
+
+		resp = callResource(t, "lookup")
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+	})
+}
@@ -1,9 +1,10 @@
 package opentsdb
 
 type OpenTsdbQuery struct {
-	Start   int64            `json:"start"`
-	End     int64            `json:"end"`
-	Queries []map[string]any `json:"queries"`
+	Start             int64            `json:"start"`
+	End               int64            `json:"end"`
+	Queries           []map[string]any `json:"queries"`
+	GlobalAnnotations bool             `json:"globalAnnotations,omitempty"`
 }
 
 type OpenTsdbCommon struct {
@@ -20,3 +21,14 @@ type OpenTsdbResponse24 struct {
 	OpenTsdbCommon
 	DataPoints [][]float64 `json:"dps"`
 }
+
+type OpenTsdbAnnotation struct {
+	Description string  `json:"description"`
+	StartTime   float64 `json:"startTime"`
+	EndTime     float64 `json:"endTime"`
+}
+
+type OpenTsdbAnnotationResponse struct {
+	Annotations       []OpenTsdbAnnotation `json:"annotations"`
+	GlobalAnnotations []OpenTsdbAnnotation `json:"globalAnnotations"`
+}
@@ -0,0 +1,154 @@
+// Package servicegraph builds node graph frames of the calls between services
+// from the spans of searched traces, with the same fields as the Tempo service
+// graph. It is shared by the tracing data sources without service graph
+// metrics, as Jaeger and Zipkin.
+package servicegraph
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// stats are the calls received by a service, or made from a service to another.
+type stats struct {
+	total  int64
+	failed int64
+	// Sum of the durations of the calls in milliseconds
+	durationSum float64
+}
+
+func (s *stats) add(durationMs float64, failed bool) {
+	s.total++
+	s.durationSum += durationMs
+	if failed {
+		s.failed++
+	}
+}
+
+type edge struct {
+	source string
+	target string
+}
+
+// Graph aggregates the calls between services, as Tempo service graphs do from
+// the spans it receives. The searched traces are a sample of the traces of the
+// time range, so the stats are counts of the calls in the sample, not rates.
+type Graph struct {
+	nodes map[string]*stats
+	edges map[edge]*stats
+}
+
+func New() *Graph {
+	return &Graph{
+		nodes: make(map[string]*stats),
+		edges: make(map[edge]*stats),
+	}
+}
+
+// AddCall adds a call from the client service to the server service. Calls
+// without client are the root spans of the traces, they only count for the
+// server node. As in Tempo, the stats of the nodes are the calls they received.
+func (g *Graph) AddCall(client, server string, durationMs float64, failed bool) {
+	if _, ok := g.nodes[server]; !ok {
+		g.nodes[server] = &stats{}
+	}
+	g.nodes[server].add(durationMs, failed)
+	if client == "" {
+		return
+	}
+	if _, ok := g.nodes[client]; !ok {
+		g.nodes[client] = &stats{}
+	}
+	e := edge{source: client, target: server}
+	if _, ok := g.edges[e]; !ok {
+		g.edges[e] = &stats{}
+	}
+	g.edges[e].add(durationMs, failed)
+}
+
+// Frames returns the nodes and edges frames of the node graph. The secondary
+// stat is the number of calls in the numTraces searched traces, a notice tells
+// the graph is built from this sample.
+func (g *Graph) Frames(refID string, numTraces int) []*data.Frame {
+	meta := &data.FrameMeta{
+		PreferredVisualization: "nodeGraph",
+		Notices: []data.Notice{{
+			Severity: data.NoticeSeverityInfo,
+			Text:     fmt.Sprintf("The service graph is built from the %d searched traces, the request counts are the calls in these traces and not all the requests of the time range.", numTraces),
+		}},
+	}
+
+	nodesFrame := data.NewFrame(refID+"_nodes",
+		data.NewField("id", nil, []string{}),
+		data.NewField("title", nil, []string{}).SetConfig(&data.FieldConfig{DisplayName: "Service name"}),
+		data.NewField("mainstat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Average response time", Unit: "ms/r"}),
+		data.NewField("secondarystat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Sampled requests", Unit: "none"}),
+		data.NewField("arc__success", nil, []float64{}).SetConfig(&data.FieldConfig{
+			DisplayName: "Success",
+			Color:       map[string]any{"mode": "fixed", "fixedColor": "green"},
+		}),
+		data.NewField("arc__failed", nil, []float64{}).SetConfig(&data.FieldConfig{
+			DisplayName: "Failed",
+			Color:       map[string]any{"mode": "fixed", "fixedColor": "red"},
+		}),
+	)
+	nodesFrame.Meta = meta
+
+	edgesFrame := data.NewFrame(refID+"_edges",
+		data.NewField("id", nil, []string{}),
+		data.NewField("source", nil, []string{}),
+		data.NewField("target", nil, []string{}),
+		data.NewField("mainstat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Average response time", Unit: "ms/r"}),
+		data.NewField("secondarystat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Sampled requests", Unit: "none"}),
+		data.NewField("detail__errorRate", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Error rate", Unit: "percentunit"}),
+	)
+	edgesFrame.Meta = &data.FrameMeta{
+		PreferredVisualization: "nodeGraph",
+	}
+
+	// Sort the nodes and edges to return them in a consistent order
+	services := make([]string, 0, len(g.nodes))
+	for service := range g.nodes {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	for _, service := range services {
+		s := g.nodes[service]
+		// Services only making calls have no stats, NaN values are not shown.
+		mainStat, secondaryStat, success, failed := math.NaN(), math.NaN(), 1.0, 0.0
+		if s.total > 0 {
+			mainStat = s.durationSum / float64(s.total)
+			secondaryStat = float64(s.total)
+			failed = float64(s.failed) / float64(s.total)
+			success = 1 - failed
+		}
+		nodesFrame.AppendRow(service, service, mainStat, secondaryStat, success, failed)
+	}
+
+	edges := make([]edge, 0, len(g.edges))
+	for e := range g.edges {
+		edges = append(edges, e)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].source != edges[j].source {
+			return edges[i].source < edges[j].source
+		}
+		return edges[i].target < edges[j].target
+	})
+	for _, e := range edges {
+		s := g.edges[e]
+		edgesFrame.AppendRow(
+			e.source+"--"+e.target,
+			e.source,
+			e.target,
+			s.durationSum/float64(s.total),
+			float64(s.total),
+			float64(s.failed)/float64(s.total),
+		)
+	}
+
+	return []*data.Frame{nodesFrame, edgesFrame}
+}
@@ -0,0 +1,44 @@
+package servicegraph
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraph_Frames(t *testing.T) {
+	g := New()
+	g.AddCall("", "frontend", 30, false)
+	g.AddCall("frontend", "api", 20, false)
+	g.AddCall("frontend", "api", 40, true)
+
+	frames := g.Frames("A", 2)
+	require.Len(t, frames, 2)
+	nodes, edges := frames[0], frames[1]
+
+	require.Equal(t, 2, nodes.Rows())
+	assert.Equal(t, "api", nodes.Fields[0].At(0))
+	assert.Equal(t, 30.0, nodes.Fields[2].At(0))
+	// The secondary stat counts the calls, the traces are a sample.
+	assert.Equal(t, 2.0, nodes.Fields[3].At(0))
+	assert.Equal(t, "Sampled requests", nodes.Fields[3].Config.DisplayName)
+	assert.Equal(t, 0.5, nodes.Fields[5].At(0))
+	require.Len(t, nodes.Meta.Notices, 1)
+	assert.Contains(t, nodes.Meta.Notices[0].Text, "2 searched traces")
+
+	require.Equal(t, 1, edges.Rows())
+	assert.Equal(t, "frontend--api", edges.Fields[0].At(0))
+	assert.Equal(t, 2.0, edges.Fields[4].At(0))
+	assert.Equal(t, 0.5, edges.Fields[5].At(0))
+
+	t.Run("services only making calls have no stats", func(t *testing.T) {
+		g := New()
+		g.AddCall("frontend", "api", 20, false)
+		nodes := g.Frames("A", 1)[0]
+		assert.Equal(t, "frontend", nodes.Fields[0].At(1))
+		assert.True(t, math.IsNaN(nodes.Fields[2].At(1).(float64)))
+		assert.Equal(t, 1.0, nodes.Fields[4].At(1))
+	})
+}
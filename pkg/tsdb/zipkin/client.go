@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
@@ -113,6 +115,56 @@ func (z *ZipkinClient) Traces(serviceName string, spanName string) ([][]model.Sp
 	return traces, err
 }
 
+// SearchTraces returns list of traces in the given time range, for the service and span when set
+// https://zipkin.io/zipkin-api/#/default/get_traces
+func (z *ZipkinClient) SearchTraces(serviceName string, spanName string, start time.Time, end time.Time, limit int) ([][]model.SpanModel, error) {
+	traces := [][]model.SpanModel{}
+	params := map[string]string{
+		"endTs":    strconv.FormatInt(end.UnixMilli(), 10),
+		"lookback": strconv.FormatInt(end.Sub(start).Milliseconds(), 10),
+	}
+	if serviceName != "" {
+		params["serviceName"] = serviceName
+	}
+	if spanName != "" {
+		params["spanName"] = spanName
+	}
+	if limit > 0 {
+		params["limit"] = strconv.Itoa(limit)
+	}
+	tracesUrl, err := createZipkinURL(z.url, "/api/v2/traces", params)
+	if err != nil {
+		return traces, backend.DownstreamError(fmt.Errorf("failed to compose url: %w", err))
+	}
+
+	res, err := z.httpClient.Get(tracesUrl)
+	if err != nil {
+		if backend.IsDownstreamHTTPError(err) {
+			return traces, backend.DownstreamError(err)
+		}
+		return traces, err
+	}
+
+	defer func() {
+		if err = res.Body.Close(); err != nil {
+			z.logger.Error("Failed to close response body", "error", err)
+		}
+	}()
+
+	if res.StatusCode/100 != 2 {
+		err := fmt.Errorf("request failed: %s", res.Status)
+		if backend.ErrorSourceFromHTTPStatus(res.StatusCode) == backend.ErrorSourceDownstream {
+			return traces, backend.DownstreamError(err)
+		}
+		return traces, err
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&traces); err != nil {
+		return traces, err
+	}
+	return traces, nil
+}
+
 // Trace returns trace for the given traceId
 // https://zipkin.io/zipkin-api/#/default/get_trace__traceId_
 func (z *ZipkinClient) Trace(traceId string) ([]model.SpanModel, error) {
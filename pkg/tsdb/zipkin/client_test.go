@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
@@ -237,6 +238,45 @@ func TestZipkinClient_Traces(t *testing.T) {
 	}
 }
 
+func TestZipkinClient_SearchTraces(t *testing.T) {
+	end := time.UnixMilli(1700000000000)
+	start := end.Add(-time.Hour)
+	trace := [][]model.SpanModel{{{SpanContext: model.SpanContext{TraceID: model.TraceID{Low: 1234}, ID: 1}, Name: "operation1"}}}
+
+	t.Run("Successful response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v2/traces", r.URL.Path)
+			assert.Equal(t, url.Values{
+				"serviceName": {"service1"},
+				"endTs":       {"1700000000000"},
+				"lookback":    {"3600000"},
+				"limit":       {"20"},
+			}, r.URL.Query())
+			response, _ := json.Marshal(trace)
+			_, _ = w.Write(response)
+		}))
+		defer server.Close()
+
+		client, _ := New(server.URL, server.Client(), log.New())
+		traces, err := client.SearchTraces("service1", "", start, end, 20)
+		assert.NoError(t, err)
+		assert.Equal(t, trace, traces)
+	})
+
+	t.Run("Non-200 response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		client, _ := New(server.URL, server.Client(), log.New())
+		traces, err := client.SearchTraces("", "", start, end, 0)
+		assert.Error(t, err)
+		assert.True(t, backend.IsDownstreamError(err))
+		assert.Empty(t, traces)
+	})
+}
+
 func TestZipkinClient_Trace(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -35,6 +35,23 @@ func queryData(ctx context.Context, dsInfo *datasourceInfo, req *backend.QueryDa
 				Error:       fmt.Errorf("unsupported query type %s. only available in frontend mode", query.QueryType),
 				ErrorSource: backend.ErrorSourcePlugin,
 			}
+		case zipkinQueryTypeServiceGraph:
+			traces, err := dsInfo.ZipkinClient.SearchTraces(query.ServiceName, query.SpanName, q.TimeRange.From, q.TimeRange.To, query.Limit)
+			if err != nil {
+				es := backend.ErrorSourcePlugin
+				if backend.IsDownstreamError(err) {
+					es = backend.ErrorSourceDownstream
+				}
+				response.Responses[q.RefID] = backend.DataResponse{
+					Error:       err,
+					ErrorSource: es,
+				}
+				continue
+			}
+
+			response.Responses[q.RefID] = backend.DataResponse{
+				Frames: transformServiceGraphResponse(traces, q.RefID),
+			}
 		default:
 			traces, err := dsInfo.ZipkinClient.Trace(query.Query)
 			if err != nil {
@@ -63,11 +80,17 @@ type zipkinQueryType string
 const (
 	zipkinQueryTypeTraceId zipkinQueryType = "traceID"
 	zipkinQueryTypeUpload  zipkinQueryType = "upload"
+	// zipkinQueryTypeServiceGraph builds the service graph of the traces of the time range
+	zipkinQueryTypeServiceGraph zipkinQueryType = "serviceGraph"
 )
 
 type zipkinQuery struct {
 	Query     string          `json:"query,omitempty"`
 	QueryType zipkinQueryType `json:"queryType,omitempty"`
+	// Service graph queries are limited to the traces of the service and span when set
+	ServiceName string `json:"serviceName,omitempty"`
+	SpanName    string `json:"spanName,omitempty"`
+	Limit       int    `json:"limit,omitempty"`
 }
 
 func loadQuery(backendQuery backend.DataQuery) (zipkinQuery, error) {
@@ -81,3 +81,28 @@ func TestTransformResponse(t *testing.T) {
 		experimental.CheckGoldenJSONFrame(t, "./testdata", "simple_trace.golden", frames, false)
 	})
 }
+
+func TestTransformServiceGraphResponse(t *testing.T) {
+	endpoint := func(serviceName string) *model.Endpoint {
+		return &model.Endpoint{ServiceName: serviceName}
+	}
+	traceID := model.TraceID{Low: 1}
+	rootID, clientID, dbID := model.ID(1), model.ID(2), model.ID(3)
+	traces := [][]model.SpanModel{
+		{
+			{SpanContext: model.SpanContext{TraceID: traceID, ID: rootID}, Kind: model.Server, Duration: 30 * time.Millisecond, LocalEndpoint: endpoint("frontend")},
+			{SpanContext: model.SpanContext{TraceID: traceID, ID: clientID, ParentID: &rootID}, Kind: model.Client, Duration: 25 * time.Millisecond, LocalEndpoint: endpoint("frontend")},
+			// The server span shares the ID of the client span
+			{SpanContext: model.SpanContext{TraceID: traceID, ID: clientID, ParentID: &rootID}, Shared: true, Kind: model.Server, Duration: 20 * time.Millisecond, LocalEndpoint: endpoint("api")},
+			{SpanContext: model.SpanContext{TraceID: traceID, ID: dbID, ParentID: &clientID}, Kind: model.Server, Duration: 5 * time.Millisecond, LocalEndpoint: endpoint("database")},
+		},
+		{
+			{SpanContext: model.SpanContext{TraceID: traceID, ID: rootID}, Kind: model.Server, Duration: 60 * time.Millisecond, LocalEndpoint: endpoint("frontend"), Tags: map[string]string{"error": "500"}},
+			{SpanContext: model.SpanContext{TraceID: traceID, ID: clientID, ParentID: &rootID}, Kind: model.Server, Duration: 40 * time.Millisecond, LocalEndpoint: endpoint("api"), Tags: map[string]string{"error": "timeout"}},
+		},
+	}
+
+	frames := transformServiceGraphResponse(traces, "test")
+	experimental.CheckGoldenJSONFrame(t, "./testdata", "service_graph_nodes.golden", frames[0], false)
+	experimental.CheckGoldenJSONFrame(t, "./testdata", "service_graph_edges.golden", frames[1], false)
+}
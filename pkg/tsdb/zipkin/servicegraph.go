@@ -0,0 +1,66 @@
+package zipkin
+
+import (
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/openzipkin/zipkin-go/model"
+
+	"github.com/grafana/grafana/pkg/tsdb/servicegraph"
+)
+
+// transformServiceGraphResponse aggregates the spans of the traces into a
+// service graph. Spans with a parent in another service are calls between the
+// services, the root spans are the calls received by their service.
+func transformServiceGraphResponse(traces [][]model.SpanModel, refID string) []*data.Frame {
+	graph := servicegraph.New()
+	for _, trace := range traces {
+		// Shared server spans have the ID of their client span, the children
+		// with this parent ID are children of the server span.
+		spans := make(map[model.ID]model.SpanModel, len(trace))
+		clientSpans := make(map[model.ID]model.SpanModel, len(trace))
+		for _, span := range trace {
+			if !span.Shared {
+				clientSpans[span.ID] = span
+			}
+			if _, ok := spans[span.ID]; !ok || span.Shared {
+				spans[span.ID] = span
+			}
+		}
+
+		for _, span := range trace {
+			service := getServiceName(span)
+			durationMs := float64(span.Duration.Microseconds()) / 1000
+			parent, ok := parentSpan(span, spans, clientSpans)
+			if !ok {
+				graph.AddCall("", service, durationMs, isErrorSpan(span))
+				continue
+			}
+			if parentService := getServiceName(parent); parentService != service {
+				graph.AddCall(parentService, service, durationMs, isErrorSpan(span))
+			}
+		}
+	}
+	return graph.Frames(refID, len(traces))
+}
+
+// parentSpan returns the client span of shared server spans, or the span with
+// the parent ID. Spans with a parent not in the trace are counted as root
+// spans.
+func parentSpan(span model.SpanModel, spans, clientSpans map[model.ID]model.SpanModel) (model.SpanModel, bool) {
+	if span.Shared {
+		if client, ok := clientSpans[span.ID]; ok {
+			return client, true
+		}
+	}
+	if span.ParentID == nil {
+		return model.SpanModel{}, false
+	}
+	parent, ok := spans[*span.ParentID]
+	return parent, ok
+}
+
+// isErrorSpan reports if the span has the error tag, set to the error message
+// by Zipkin instrumentations.
+func isErrorSpan(span model.SpanModel) bool {
+	_, ok := span.Tags["error"]
+	return ok
+}
@@ -835,6 +835,22 @@ export type JobList = {
   kind?: string;
   metadata?: ListMeta;
 };
+export type CommitSigningConfig = {
+  /** Private key used to sign commits, but encrypted. This is not possible to read back to a user decrypted. */
+  encryptedKey?: string;
+  /** The format of the signing key. Defaults to gpg.
+    
+    Possible enum values:
+     - `"gpg"` GPGSigningFormat signs commits with an OpenPGP key
+     - `"ssh"` SSHSigningFormat signs commits with an SSH key */
+  format?: 'gpg' | 'ssh';
+  /** Private key used to sign commits (armored OpenPGP key or OpenSSH private key). If set, it will be encrypted into encryptedKey, then set to an empty string again. The key must not be protected by a passphrase. */
+  key?: string;
+  /** When true, sync refuses to apply changes unless every commit since the last synced commit verifies against one of the trusted keys. Commits Grafana writes through the GitHub API, such as saving a dashboard from the UI, are not signed with the signing key: they only verify when GitHub signs them and GitHub's web-flow key is trusted, otherwise the next sync rejects them. */
+  requireVerified?: boolean;
+  /** Public keys trusted when verifying commit signatures. Each entry is either an armored OpenPGP public key (block) or an SSH public key in authorized_keys format. */
+  trustedKeys?: string[];
+};
 export type GitHubRepositoryConfig = {
   /** The branch to use in the repository. */
   branch: string;
@@ -846,6 +862,8 @@ export type GitHubRepositoryConfig = {
     
     When specifying something like `grafana-`, we will not look for `grafana-*`; we will only look for files under the directory `/grafana-/`. That means `/grafana-example.json` would not be found. */
   path?: string;
+  /** Signing configures how commits written by Grafana are signed, and whether signatures are verified before sync. */
+  signing?: CommitSigningConfig;
   /** Token for accessing the repository. If set, it will be encrypted into encryptedToken, then set to an empty string again. */
   token?: string;
   /** The repository URL (e.g. `https://github.com/example/test`). */
@@ -114,8 +114,9 @@ export async function updateLibraryPanel(panelSaveModel: PanelModelWithLibraryPa
   return result;
 }
 
+// The user confirms the deletion in the UI, so it is forced even when dashboards still use the library panel.
 export function deleteLibraryPanel(uid: string): Promise<{ message: string }> {
-  return getBackendSrv().delete(`/api/library-elements/${uid}`);
+  return getBackendSrv().delete(`/api/library-elements/${uid}?force=true`);
 }
 
 export async function getLibraryPanelConnectedDashboards(
@@ -35,6 +35,7 @@ export function QueryEditor({ datasource, query, onChange, onRunQuery }: Props)
   const renderEditorBody = () => {
     switch (query.queryType) {
       case 'search':
+      case 'serviceGraph':
         return <SearchForm datasource={datasource} query={query} onChange={onChange} />;
       case 'dependencyGraph':
         return null;
@@ -82,6 +83,7 @@ export function QueryEditor({ datasource, query, onChange, onRunQuery }: Props)
                   { value: 'search', label: 'Search' },
                   { value: undefined, label: 'TraceID' },
                   { value: 'dependencyGraph', label: 'Dependency graph' },
+                  { value: 'serviceGraph', label: 'Service graph' },
                 ]}
                 value={query.queryType}
                 onChange={(v) =>
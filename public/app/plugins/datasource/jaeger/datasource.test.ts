@@ -528,6 +528,24 @@ describe('Test behavior with unmocked time', () => {
     expect(lookbackMatch).not.toBeNull();
     expect(parseInt(lookbackMatch![1], 10)).toBeCloseTo(21600000, -1);
   });
+
+  it('sends `serviceGraph` queries to the backend without the feature toggle', async () => {
+    const backendQuery = jest
+      .spyOn(DataSourceWithBackend.prototype, 'query')
+      .mockImplementation(() => of({ data: [] }));
+    const ds = new JaegerDatasource(defaultSettings);
+
+    const targets: JaegerQuery[] = [{ queryType: 'serviceGraph', service: 'app', refId: '1' }];
+    await lastValueFrom(ds.query({ ...defaultQuery, targets }));
+    expect(backendQuery).toHaveBeenCalledTimes(1);
+
+    // The service is required
+    const response = await lastValueFrom(
+      ds.query({ ...defaultQuery, targets: [{ queryType: 'serviceGraph', refId: '1' }] })
+    );
+    expect(response.error?.message).toBe('You must select a service.');
+    expect(backendQuery).toHaveBeenCalledTimes(1);
+  });
 });
 
 function setupFetchMock(response: unknown, mock?: ReturnType<typeof backendSrv.fetch>) {
@@ -80,7 +80,15 @@ export class JaegerDatasource extends DataSourceWithBackend<JaegerQuery, JaegerJ
       return of({ data: [emptyTraceDataFrame] });
     }
 
-    if (config.featureToggles.jaegerBackendMigration && target.queryType !== 'upload') {
+    if (target.queryType === 'serviceGraph' && !this.isSearchFormValid(target)) {
+      return of({ error: { message: 'You must select a service.' }, data: [] });
+    }
+
+    // The service graph is only built by the backend, whatever the feature toggle.
+    const backendQuery =
+      target.queryType === 'serviceGraph' ||
+      (config.featureToggles.jaegerBackendMigration && target.queryType !== 'upload');
+    if (backendQuery) {
       return super.query({ ...options, targets: [target] }).pipe(
         map((response) => {
           // If the node graph is enabled and the query is a trace ID query, add the node graph frames to the response
@@ -52,7 +52,7 @@ export type JaegerQuery = {
   limit?: number;
 } & DataQuery;
 
-export type JaegerQueryType = 'search' | 'upload' | 'dependencyGraph';
+export type JaegerQueryType = 'search' | 'upload' | 'dependencyGraph' | 'serviceGraph';
 
 export type JaegerResponse = {
   data: TraceResponse[];
@@ -12,7 +12,9 @@ import {
   FileDropzone,
   InlineField,
   InlineFieldRow,
+  Input,
   RadioButtonGroup,
+  Select,
   useTheme2,
   QueryField,
   useStyles2,
@@ -92,7 +94,10 @@ export const ZipkinQueryField = ({ query, onChange, onRunQuery, datasource }: Pr
         <InlineField label="Query type" grow={true}>
           <Stack gap={1} alignItems="center" justifyContent="space-between">
             <RadioButtonGroup<ZipkinQueryType>
-              options={[{ value: 'traceID', label: 'TraceID' }]}
+              options={[
+                { value: 'traceID', label: 'TraceID' },
+                { value: 'serviceGraph', label: 'Service graph' },
+              ]}
               value={query.queryType || 'traceID'}
               onChange={(v) =>
                 onChange({
@@ -136,6 +141,62 @@ export const ZipkinQueryField = ({ query, onChange, onRunQuery, datasource }: Pr
           </div>
         </InlineFieldRow>
       )}
+      {query.queryType === 'serviceGraph' && (
+        <>
+          <InlineFieldRow>
+            <InlineField label="Service Name" labelWidth={14} grow>
+              <Select
+                inputId="service"
+                options={serviceOptions.value?.map(({ label, value }) => ({ label, value }))}
+                isLoading={serviceOptions.loading}
+                value={query.serviceName || null}
+                placeholder="All services"
+                onChange={(v) =>
+                  onChange({
+                    ...query,
+                    serviceName: v?.value || undefined,
+                  })
+                }
+                menuPlacement="bottom"
+                isClearable
+                aria-label={'select-service-name'}
+              />
+            </InlineField>
+          </InlineFieldRow>
+          <InlineFieldRow>
+            <InlineField label="Span Name" labelWidth={14} grow>
+              <Input
+                id="spanName"
+                name="spanName"
+                value={query.spanName || ''}
+                placeholder="All spans"
+                onChange={(v) =>
+                  onChange({
+                    ...query,
+                    spanName: v.currentTarget.value || undefined,
+                  })
+                }
+              />
+            </InlineField>
+          </InlineFieldRow>
+          <InlineFieldRow>
+            <InlineField label="Limit" labelWidth={14} grow tooltip="Maximum number of traces in the graph">
+              <Input
+                id="limit"
+                name="limit"
+                value={query.limit || ''}
+                type="number"
+                onChange={(v) =>
+                  onChange({
+                    ...query,
+                    limit: v.currentTarget.value ? parseInt(v.currentTarget.value, 10) : undefined,
+                  })
+                }
+              />
+            </InlineField>
+          </InlineFieldRow>
+        </>
+      )}
       {alertText && <TemporaryAlert text={alertText} severity={'error'} />}
     </>
   );
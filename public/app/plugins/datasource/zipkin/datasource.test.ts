@@ -49,6 +49,17 @@ describe('ZipkinDatasource', () => {
       });
     });
 
+    it('runs service graph queries without a trace ID', async () => {
+      const fetch = jest.fn().mockReturnValue(of({ data: [] }));
+      setBackendSrv({ ...getBackendSrv(), fetch });
+
+      await lastValueFrom(
+        ds.query({ targets: [{ queryType: 'serviceGraph', serviceName: 'app' }] } as DataQueryRequest<ZipkinQuery>)
+      );
+      expect(fetch).toHaveBeenCalledTimes(1);
+      expect(fetch.mock.calls[0][0].data.queries[0]).toMatchObject({ queryType: 'serviceGraph', serviceName: 'app' });
+    });
+
     it('should handle json file upload', async () => {
       ds.uploadedJson = JSON.stringify(mockJson);
       const response = await lastValueFrom(
@@ -49,6 +49,11 @@ export class ZipkinDatasource extends DataSourceWithBackend<ZipkinQuery, ZipkinJ
       }
     }
 
+    // The service graph is built by the backend from the searched traces.
+    if (target.queryType === 'serviceGraph') {
+      return super.query(options);
+    }
+
     if (target.query) {
       return super.query(options).pipe(
         map((response) => {
@@ -94,6 +99,8 @@ export class ZipkinDatasource extends DataSourceWithBackend<ZipkinQuery, ZipkinJ
     return {
       ...expandedQuery,
       query: this.templateSrv.replace(query.query ?? '', scopedVars),
+      serviceName: query.serviceName && this.templateSrv.replace(query.serviceName, scopedVars),
+      spanName: query.spanName && this.templateSrv.replace(query.spanName, scopedVars),
     };
   }
 }
@@ -26,9 +26,13 @@ export type ZipkinAnnotation = {
   timestamp: number;
   value: string;
 };
-export type ZipkinQueryType = 'traceID' | 'upload';
+export type ZipkinQueryType = 'traceID' | 'upload' | 'serviceGraph';
 
 export interface ZipkinQuery extends DataQuery {
   query: string;
   queryType?: ZipkinQueryType;
+  // Service graph queries are limited to the traces of the service and span when set
+  serviceName?: string;
+  spanName?: string;
+  limit?: number;
 }